@@ -0,0 +1,202 @@
+package allocator
+
+import (
+	"fmt"
+	"hash/crc64"
+	"sort"
+	"strings"
+
+	"go.gazette.dev/core/keyspace"
+)
+
+// AffinityItemValue is an optional interface of an ItemValue which declares
+// groups of related Items that benefit from co-location upon the same Members,
+// such as shards which join the same data and would otherwise exchange it
+// across the network.
+//
+// Items of a group are co-located with the group's "anchor", which is its
+// first Item in key order. The allocator first solves for a maximum
+// assignment without regard to affinities, which places each anchor. It then
+// solves again, and Items of each group (including the anchor) prefer the
+// Members at which their anchor was placed.
+//
+// Affinity is soft by default: Members of the anchor are tried before others,
+// but the Item is otherwise placed as usual, and current Assignments of the
+// Item are retained. A hard affinity instead restricts the Item to Members of
+// the anchor, moving it from other Members. Both are preferences rather than
+// constraints of the solver: a hard affinity is relaxed if the Item can't
+// otherwise be fully replicated, as when Members of the anchor lack capacity.
+// Assignments which aren't co-located with their anchor are reported as
+// violations (see State.AffinityViolations).
+//
+// An Item co-locates with at most one group. If an Item returns multiple
+// groups, only the first applies and the conflict is reported as a violation.
+// Affinity doesn't apply to Items having a spread attribute (see
+// SpreadItemValue), which are placed as though they have no affinity, though
+// such an Item may still be the anchor of its group.
+type AffinityItemValue interface {
+	// AffinityGroups returns the affinity groups of the Item, or empty if
+	// the Item has no affinity.
+	AffinityGroups() []string
+	// HardAffinity returns true if the Item's affinity is hard.
+	HardAffinity() bool
+}
+
+// AffinityViolation is an Item whose affinity isn't satisfied by its
+// Assignments, or whose affinity groups conflict.
+type AffinityViolation struct {
+	ItemID string
+	// Group of the Item's affinity which applies.
+	Group string
+	// Hard is true if the Item's affinity is hard.
+	Hard bool
+	// Reason describes the violation.
+	Reason string
+}
+
+// AffinityViolations returns each Item having an affinity which isn't
+// satisfied by the current Assignments. AffinityViolations read-locks the
+// KeySpace. It must not be called while the KeySpace is locked, such as from
+// a KeySpace Observer.
+func (s *State) AffinityViolations() []AffinityViolation {
+	s.KS.Mu.RLock()
+	defer s.KS.Mu.RUnlock()
+
+	return affinityViolations(s, currentAssignmentsOf(s))
+}
+
+// affinityViolations returns AffinityViolations of the State, where
+// |assignmentsOf| returns the Assignments of an Item to be evaluated
+// (such as its current, or desired, Assignments).
+func affinityViolations(s *State, assignmentsOf func(itemID string) []Assignment) []AffinityViolation {
+	var anchors = affinityAnchors(s, assignmentsOf)
+	var out []AffinityViolation
+
+	for i := range s.Items {
+		var item = itemAt(s.Items, i)
+		var groups, hard = affinity(item)
+
+		if len(groups) == 0 {
+			continue
+		} else if len(groups) > 1 {
+			out = append(out, AffinityViolation{
+				ItemID: item.ID,
+				Group:  groups[0],
+				Hard:   hard,
+				Reason: fmt.Sprintf("item has conflicting affinity groups (%s); only %s applies",
+					strings.Join(groups, ", "), groups[0]),
+			})
+		}
+
+		var anchor, ok = anchors[groups[0]]
+		if !ok || anchor.ID == item.ID || len(anchor.Assignments) == 0 || spreadAttribute(item) != "" {
+			continue
+		}
+		var assignments = assignmentsOf(item.ID)
+		var apart int
+
+		for _, a := range assignments {
+			if !anchor.colocated(a) {
+				apart++
+			}
+		}
+		if apart != 0 {
+			out = append(out, AffinityViolation{
+				ItemID: item.ID,
+				Group:  groups[0],
+				Hard:   hard,
+				Reason: fmt.Sprintf("%d of %d assignments are not co-located with anchor item %s",
+					apart, len(assignments), anchor.ID),
+			})
+		}
+	}
+	return out
+}
+
+// currentAssignmentsOf returns a function which returns the current
+// Assignments of an Item of the State.
+func currentAssignmentsOf(s *State) func(itemID string) []Assignment {
+	return func(itemID string) []Assignment {
+		var kvs = s.Assignments.Prefixed(ItemAssignmentsPrefix(s.KS, itemID))
+		var out = make([]Assignment, len(kvs))
+
+		for i, kv := range kvs {
+			out[i] = kv.Decoded.(Assignment)
+		}
+		return out
+	}
+}
+
+// desiredAssignmentsOf returns a function which returns the Assignments
+// of an Item within |desired|, which is ordered on Item ID.
+func desiredAssignmentsOf(desired []Assignment) func(itemID string) []Assignment {
+	return func(itemID string) []Assignment {
+		var begin = sort.Search(len(desired), func(i int) bool { return desired[i].ItemID >= itemID })
+		var end = begin
+		for end != len(desired) && desired[end].ItemID == itemID {
+			end++
+		}
+		return desired[begin:end]
+	}
+}
+
+// affinityAnchor is the anchor Item of an affinity group.
+type affinityAnchor struct {
+	ID string
+	// Assignments of the anchor Item.
+	Assignments []Assignment
+}
+
+// colocated returns true if the Assignment is to a Member of the anchor.
+func (a affinityAnchor) colocated(assignment Assignment) bool {
+	for _, o := range a.Assignments {
+		if o.MemberZone == assignment.MemberZone && o.MemberSuffix == assignment.MemberSuffix {
+			return true
+		}
+	}
+	return false
+}
+
+// affinityAnchors returns the anchor of each affinity group of the State,
+// having Assignments returned by |assignmentsOf|. It returns nil if no Items
+// have an affinity.
+func affinityAnchors(s *State, assignmentsOf func(itemID string) []Assignment) map[string]affinityAnchor {
+	var out map[string]affinityAnchor
+
+	for i := range s.Items {
+		var item = itemAt(s.Items, i)
+		var groups, _ = affinity(item)
+
+		if len(groups) == 0 {
+			continue
+		} else if out == nil {
+			out = make(map[string]affinityAnchor)
+		}
+		if _, ok := out[groups[0]]; !ok {
+			out[groups[0]] = affinityAnchor{
+				ID:          item.ID,
+				Assignments: append([]Assignment(nil), assignmentsOf(item.ID)...),
+			}
+		}
+	}
+	return out
+}
+
+// affinity returns the affinity groups of the Item, and whether it's hard.
+func affinity(item Item) ([]string, bool) {
+	if av, ok := item.ItemValue.(AffinityItemValue); ok {
+		return av.AffinityGroups(), av.HardAffinity()
+	}
+	return nil, false
+}
+
+// foldAffinityCRC folds the affinities of |items| into |crc|.
+func foldAffinityCRC(crc uint64, items keyspace.KeyValues) uint64 {
+	for i := range items {
+		if groups, hard := affinity(itemAt(items, i)); len(groups) != 0 {
+			crc = crc64.Update(crc, crcTable, items[i].Raw.Key)
+			crc = crc64.Update(crc, crcTable, []byte(fmt.Sprintf("%q %t", groups, hard)))
+		}
+	}
+	return crc
+}
@@ -5,6 +5,7 @@ import (
 	"math"
 	"strconv"
 	"strings"
+	"time"
 
 	log "github.com/sirupsen/logrus"
 	"go.gazette.dev/core/keyspace"
@@ -37,11 +38,12 @@ type State struct {
 	Members     keyspace.KeyValues
 	Items       keyspace.KeyValues
 	Assignments keyspace.KeyValues
+	Evacuations keyspace.KeyValues
 
 	LocalMemberInd int         // Index of |LocalKey| within |Members|, or -1 if not found.
 	LocalItems     []LocalItem // Assignments of this instance.
 
-	Zones       []string // Sorted and unique Zones of |Members|.
+	Zones       []string // Sorted and unique Zones of |Members| which aren't evacuated.
 	ZoneSlots   []int    // Total number of item slots summed across all |Members| of each Zone.
 	ItemSlots   int      // Total desired replication slots summed across all |Items|.
 	MemberSlots int      // Total available slots for replication summed across all |Members|.
@@ -51,8 +53,19 @@ type State struct {
 	// These share cardinality with |Members|.
 	MemberTotalCount   []int
 	MemberPrimaryCount []int
+
+	// ItemLimit of each of |Members|, or zero if the Member's zone is evacuated.
+	memberLimits []int
+	// Time at which ReplicationSchedules of |Items| were evaluated, and the
+	// next time at which an evaluated replication changes (or zero if none do).
+	scheduledAt, scheduleChange time.Time
 }
 
+// StateObserverPriority is the KeySpace Observer priority of a State returned
+// by NewObservedState. It's negative, so that the State is updated before
+// Observers of default priority which may inspect it.
+const StateObserverPriority = -1
+
 // NewObservedState returns a *State instance which extracts and updates itself
 // from the provided KeySpace, pivoted around the Member instance identified by
 // |localKey|. Item consistency is determined using the provided IsConsistentFn.
@@ -65,9 +78,7 @@ func NewObservedState(ks *keyspace.KeySpace, localKey string, fn IsConsistentFn)
 		IsConsistent:   fn,
 		LocalMemberInd: -1,
 	}
-	ks.Mu.Lock()
-	ks.Observers = append(ks.Observers, s.observe)
-	ks.Mu.Unlock()
+	ks.AddObserver("allocator.State", StateObserverPriority, s.observe)
 	return s
 }
 
@@ -80,6 +91,7 @@ func (s *State) observe() {
 	s.Members = s.KS.Prefixed(s.KS.Root + MembersPrefix)
 	s.Items = s.KS.Prefixed(s.KS.Root + ItemsPrefix)
 	s.Assignments = s.KS.Prefixed(s.KS.Root + AssignmentsPrefix)
+	s.Evacuations = s.KS.Prefixed(s.KS.Root + EvacuationsPrefix)
 	s.LocalMemberInd = -1
 	s.LocalItems = s.LocalItems[:0]
 	s.Zones = s.Zones[:0]
@@ -89,8 +101,11 @@ func (s *State) observe() {
 	s.NetworkHash = 0
 	s.MemberTotalCount = make([]int, len(s.Members))
 	s.MemberPrimaryCount = make([]int, len(s.Members))
+	s.memberLimits = s.memberLimits[:0]
+	s.scheduledAt, s.scheduleChange = timeNow(), time.Time{}
 
 	// Walk Members to:
+	//  * Initialize |memberLimits|, which are zero for Members of evacuated zones.
 	//  * Group the set of ordered |Zones| across all Members.
 	//  * Initialize |ZoneSlots|.
 	//  * Initialize |MemberSlots|.
@@ -100,6 +115,11 @@ func (s *State) observe() {
 		var slots = m.ItemLimit()
 		var zone = len(s.Zones) - 1
 
+		if s.isEvacuated(m.Zone) {
+			slots = 0
+		}
+		s.memberLimits = append(s.memberLimits, slots)
+
 		if slots == 0 {
 			// Don't collect zones of members having no slots.
 		} else if len(s.Zones) == 0 || s.Zones[zone] < m.Zone {
@@ -126,6 +146,7 @@ func (s *State) observe() {
 	// Left-join Items with their Assignments to:
 	//   * Initialize |ItemSlots|.
 	//   * Initialize |NetworkHash|.
+	//   * Initialize |scheduleChange|.
 	//   * Collect Items and Assignments which map to the |LocalKey| Member.
 	//   * Accumulate per-Member counts of primary and total Assignments.
 	var it = LeftJoin{
@@ -137,11 +158,16 @@ func (s *State) observe() {
 	}
 	for cur, ok := it.Next(); ok; cur, ok = it.Next() {
 		var item = itemAt(s.Items, cur.Left)
-		var slots = item.DesiredReplication()
+		var slots, next = scheduledReplication(item, s.scheduledAt)
+		s.scheduleChange = earliest(s.scheduleChange, next)
 
 		s.ItemSlots += slots
 		s.NetworkHash = foldCRC(s.NetworkHash, s.Items[cur.Left].Raw.Key, slots)
 
+		if w := itemWeight(item); w != 1 {
+			s.NetworkHash = foldCRC(s.NetworkHash, s.Items[cur.Left].Raw.Key, w)
+		}
+
 		for r := cur.RightBegin; r != cur.RightEnd; r++ {
 			var a = assignmentAt(s.Assignments, r)
 			var key = MemberKey(s.KS, a.MemberZone, a.MemberSuffix)
@@ -161,6 +187,18 @@ func (s *State) observe() {
 			}
 		}
 	}
+	s.NetworkHash = foldSpreadCRC(s.NetworkHash, s.Items, s.Members)
+	s.NetworkHash = foldAffinityCRC(s.NetworkHash, s.Items)
+}
+
+// isEvacuated returns true iff |zone| has a ZoneEvacuation.
+func (s *State) isEvacuated(zone string) bool {
+	for i := range s.Evacuations {
+		if evacuationAt(s.Evacuations, i).Zone == zone {
+			return true
+		}
+	}
+	return false
 }
 
 // shouldExit returns true iff the local Member is able to safely exit.
@@ -180,6 +218,49 @@ func (s *State) isLeader() bool {
 	return string(leader.Raw.Key) == s.LocalKey
 }
 
+// ZoneStats summarizes the capacity and load of Members within a zone.
+type ZoneStats struct {
+	// Members is the number of Members of the zone.
+	Members int
+	// Capacity is the total ItemLimit of the zone's Members,
+	// or zero if the zone is evacuated.
+	Capacity int
+	// Load is the total number of Item Assignments to the zone's Members.
+	Load int
+	// Headroom is Capacity less Load. It's negative if the zone's Members
+	// are assigned beyond their ItemLimits, as may happen transiently when
+	// ItemLimits are reduced.
+	Headroom int
+	// Evacuated is true if the zone has a ZoneEvacuation.
+	Evacuated bool
+}
+
+// ZoneLoad returns ZoneStats of each zone having at least one Member, as of
+// the current KeySpace. Unlike Zones and ZoneSlots, it includes zones whose
+// Members all have an ItemLimit of zero, and zones which are evacuated.
+// ZoneLoad read-locks the KeySpace, and is safe for concurrent use with
+// KeySpace updates. It must not be called while the KeySpace is locked,
+// such as from a KeySpace Observer.
+func (s *State) ZoneLoad() map[string]ZoneStats {
+	s.KS.Mu.RLock()
+	defer s.KS.Mu.RUnlock()
+
+	var out = make(map[string]ZoneStats)
+	for i := range s.Members {
+		var m = memberAt(s.Members, i)
+		var stats = out[m.Zone]
+
+		stats.Members++
+		stats.Capacity += s.memberLimits[i]
+		stats.Load += s.MemberTotalCount[i]
+		stats.Headroom = stats.Capacity - stats.Load
+		stats.Evacuated = s.isEvacuated(m.Zone)
+
+		out[m.Zone] = stats
+	}
+	return out
+}
+
 func (s *State) debugLog() {
 	var la []string
 	for _, a := range s.LocalItems {
@@ -210,11 +291,23 @@ func (s *State) memberLoadRatio(assignment keyspace.KeyValue, counts []int) floa
 	var a = assignment.Decoded.(Assignment)
 
 	if ind, found := s.Members.Search(MemberKey(s.KS, a.MemberZone, a.MemberSuffix)); found {
-		return float32(counts[ind]) / float32(memberAt(s.Members, ind).ItemLimit())
+		return float32(counts[ind]) / float32(s.memberLimits[ind])
 	}
 	return math.MaxFloat32
 }
 
+// effectiveItemLimit returns the ItemLimit of the Member at index |ind| of
+// Members, less the |headroom| fraction which is held in reserve. It's zero
+// for Members of evacuated zones.
+func (s *State) effectiveItemLimit(ind int, headroom float64) int {
+	var limit = s.memberLimits[ind]
+	if headroom == 0 {
+		return limit
+	}
+	// Round down, tolerating floating-point error (eg, 10 * 0.7 = 6.999...).
+	return int(math.Floor(float64(limit)*(1-headroom) + 1e-9))
+}
+
 func foldCRC(crc uint64, key []byte, n int) uint64 {
 	var tmp [12]byte
 	crc = crc64.Update(crc, crcTable, key)
@@ -77,6 +77,15 @@ func (s *AllocStateSuite) TestExtractOverFixture(c *gc.C) {
 	c.Check(states[3].LocalKey, gc.Equals, "/root/members/does-not#exist")
 	c.Check(states[3].LocalMemberInd, gc.Equals, -1)
 	c.Check(states[3].LocalItems, gc.IsNil)
+
+	// Expect per-zone load is invariant to the pivoted member key.
+	// Assignments of missing Items don't count towards load.
+	for _, s := range states {
+		c.Check(s.ZoneLoad(), gc.DeepEquals, map[string]ZoneStats{
+			"us-east": {Members: 2, Capacity: 3, Load: 2, Headroom: 1},
+			"us-west": {Members: 1, Capacity: 3, Load: 2, Headroom: 1},
+		})
+	}
 }
 
 func (s *AllocStateSuite) TestLeaderSelection(c *gc.C) {
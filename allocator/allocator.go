@@ -12,18 +12,168 @@ import (
 	clientv3 "go.etcd.io/etcd/client/v3"
 	"go.gazette.dev/core/allocator/sparse_push_relabel"
 	"go.gazette.dev/core/keyspace"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type AllocateArgs struct {
 	Context context.Context
 	// Etcd client Allocate will use to effect changes to the distributed allocation.
-	Etcd *clientv3.Client
+	// It's typically a *clientv3.Client, but may be any clientv3.KV.
+	Etcd clientv3.KV
 	// Allocator state, which is derived from a Watched KeySpace.
 	State *State
 	// TestHook is an optional testing hook, invoked after each convergence round.
 	TestHook func(round int, isIdle bool)
+	// Audit is an optional AuditFunc, notified of committed Assignment changes.
+	Audit AuditFunc
+	// WarmStart solves for a maximum assignment by starting from current
+	// Assignments, rather than solving from scratch. This reduces the cost
+	// of each solve where changes are incremental (see BenchmarkAll). Solutions
+	// have the same number of Assignments as a cold solve, but may differ in
+	// the specific Assignments chosen (see sparseFlowNetwork.seedAssignments).
+	WarmStart bool
+	// Cost is an optional CostFunc which orders candidate Members of new
+	// Item Assignments. If nil, candidate Members are equally preferred.
+	Cost CostFunc
+	// Headroom is the fraction of each Member's ItemLimit which is held in
+	// reserve, and must be in the range [0, 1). A Member is assigned at most
+	// floor((1 - Headroom) * ItemLimit) Items, which leaves it capacity to
+	// absorb transient load. If the remaining capacity is insufficient to fully
+	// replicate all Items, then Items are under-replicated (and a warning is
+	// logged) rather than Members being assigned beyond their reduced limit.
+	Headroom float64
+	// FairShare allocates Member slots in proportion to Item weights (see
+	// WeightedItemValue) when there are too few slots to fully replicate all
+	// Items, so that Items of one tenant can't starve those of another.
+	// Otherwise, scarce slots are allocated to Items in their key order.
+	// Under FairShare, an Item having more Assignments than its fair share
+	// releases them (in favor of starved Items) down to its fair share.
+	// FairShare has no effect where Member slots are sufficient.
+	FairShare bool
+	// MoveBudget optionally limits the number of Assignments which each
+	// convergence round may add in order to migrate Items between Members.
+	// Moves beyond the budget are deferred to subsequent rounds, which lets a
+	// rollout which would move many Items (eg, the addition of Members) be
+	// observed as it progresses. Each round converges towards the same solved
+	// assignment, and a migrated Item's current Assignments are removed only
+	// once its new ones are consistent, so every intermediate state fully
+	// replicates the Item. Assignments which increase an under-replicated Item
+	// towards its desired replication (or fair share) are never deferred.
+	// If zero, moves are unlimited.
+	MoveBudget int
+	// Pack assigns Items to as few Members as possible, leaving the remaining
+	// Members without Assignments (eg, so that they may be scaled down).
+	// Packing is subject to Member ItemLimits (less Headroom), and never
+	// reduces the number of Assignments nor the number of Items replicated
+	// across multiple zones, as compared with a solve which doesn't pack.
+	Pack bool
+	// PackHysteresis is the fraction of additional capacity, beyond that
+	// required, which packing retains of Members which currently hold
+	// Assignments. Where demand fluctuates about the capacity of a Member
+	// boundary, it keeps that Member from being repeatedly released and re-added.
+	// It has no effect unless Pack is set, and must be >= 0.
+	PackHysteresis float64
+	// Status is an optional StatusHandler, which is informed of the solve and
+	// convergence rounds of Allocate.
+	Status *StatusHandler
+	// IsLeader is an optional, externally-managed leadership signal, such as
+	// a Kubernetes lease held by the process. If set, IsLeader replaces the
+	// allocator's own election of the Member having the oldest key: Allocate
+	// solves for and writes Assignments only while IsLeader returns true.
+	//
+	// IsLeader is re-checked before each Etcd transaction of a convergence
+	// round, and a round which observes a loss of leadership is aborted.
+	// Each transaction is atomic and guarded by comparisons of the keys it
+	// depends upon, so an aborted round leaves Etcd consistent (if only
+	// partially converged), and a transaction of a stale leader which overlaps
+	// with its successor fails rather than applying conflicting changes.
+	// IsLeader may be called while the KeySpace is read-locked, and must not
+	// block.
+	IsLeader func() bool
+	// LeaderChangedCh is an optional channel which is signalled upon changes
+	// of IsLeader. Allocate otherwise checks IsLeader only as the KeySpace
+	// changes, and a Member which gains leadership of an unchanging KeySpace
+	// wouldn't begin to allocate.
+	LeaderChangedCh <-chan struct{}
+	// OnGainedLeadership is an optional callback, invoked when Allocate
+	// observes that its Member has become the leader (under either the
+	// allocator's own election, or IsLeader).
+	OnGainedLeadership func()
+	// OnLostLeadership is an optional callback, invoked as soon as Allocate
+	// observes that its Member is no longer the leader, such as because a
+	// Member having an older key was observed, IsLeader returned false, or the
+	// Member's own key was removed (eg, upon revocation of its lease). It's
+	// also invoked if Allocate returns while leader. Embedding applications
+	// may use it to promptly stop leader-only work.
+	//
+	// Leadership callbacks are strictly balanced and ordered: each
+	// OnLostLeadership follows a corresponding OnGainedLeadership, even as
+	// leadership rapidly flaps. Callbacks are invoked synchronously, may be
+	// invoked while the KeySpace is read-locked, and must not block.
+	OnLostLeadership func()
+	// AssignmentValue is an optional AssignmentValueFunc, which produces the
+	// value of each new Assignment. If nil, new Assignments have an empty value.
+	AssignmentValue AssignmentValueFunc
+	// Tracer is an optional OpenTelemetry Tracer, with which the leader traces
+	// each of its convergence rounds and their solve, load, and commit phases.
+	// If nil, rounds aren't traced.
+	Tracer trace.Tracer
+	// TraceInterval is the minimum interval between traced rounds. Rounds which
+	// begin within TraceInterval of the last traced round aren't traced. If
+	// zero, every round is traced (subject to sampling of the Tracer itself).
+	TraceInterval time.Duration
+	// Invariants is an optional InvariantChecker, with which the leader checks
+	// invariants of the State at the start of each convergence round.
+	Invariants *InvariantChecker
 }
 
+// CostFunc returns the cost of assigning the Item to the Member, given the
+// current State. The allocator prefers lower-cost Members when it must create
+// a new Assignment of an Item within a zone, and may be used to express domain
+// placement preferences (eg, of hardware class or network topology).
+//
+// Costs are preferences and not constraints: they order the Members which
+// are tried, but never exclude a Member, and all the usual goals (current
+// Assignments, zone spreading, and fair-share balancing) take precedence. This
+// guarantees that a maximum assignment remains solvable regardless of costs.
+// Use Member ItemLimits to constrain where Items may be placed.
+//
+// Costs must be in the range [0, MaxAssignmentCost], and equal costs are
+// ordered by Member key. A cost outside this range fails Allocate. Costs are
+// evaluated only when the allocator re-solves for a maximum assignment, which
+// happens when the State of Items, Members, or Assignments changes. A CostFunc
+// must therefore be a deterministic function of its arguments, and must not
+// block as it's called while the KeySpace is read-locked.
+type CostFunc func(state *State, member Member, item Item) int
+
+// MaxAssignmentCost is the largest cost which a CostFunc may return.
+const MaxAssignmentCost = 1 << 20
+
+// AssignmentValueFunc returns the Etcd value of a new Assignment of the Item
+// to the Member, given the current State. Applications may use it to stamp
+// diagnostic metadata into Assignments as they're created, such as the key of
+// the assigning leader or the time of assignment.
+//
+// The value is decoded by the KeySpace's Decoder.DecodeAssignment, and the
+// returned AssignmentValue is evaluated by the State's IsConsistentFn just as
+// any other Assignment is. An AssignmentValueFunc must therefore return a
+// value which:
+//
+//   - Decodes without error. An Assignment which fails to decode is absent
+//     from the KeySpace, and the allocator will repeatedly attempt to re-add it.
+//   - Is not "consistent" as determined by the IsConsistentFn, as is true of
+//     the empty value. A new Assignment has not yet synchronized with its Item's
+//     other replicas, and a value which claims otherwise would permit the
+//     allocator to remove a current Assignment of the Item prematurely.
+//
+// The value is written only when the Assignment is created: Assignments which
+// are moved to a new Slot retain their current value, and the assigned Member
+// is free to replace the value with its own (eg, to communicate its progress
+// towards consistency). An AssignmentValueFunc must not block, as it's called
+// while the KeySpace is read-locked.
+type AssignmentValueFunc func(state *State, member Member, item Item) string
+
 // Allocate observes the Allocator KeySpace, and if this Allocator instance is
 // the current leader, performs reactive scheduling rounds to maintain the
 // allocation of all Items to Members. Allocate exits on an unrecoverable
@@ -43,12 +193,44 @@ func Allocate(args AllocateArgs) error {
 	// This caching is both more efficient, and also mitigates the impact of
 	// small instabilities in the prioritized push/relabel solution.
 	var desired []Assignment
+	var shares []int // Fair-share replication of each Item, if FairShare.
 	var lastNetworkHash uint64
 
 	var state = args.State
 	var ks = args.State.KS
 	var ctx = args.Context
 	var round int
+	var tracer = newRoundTracer(args.Tracer, args.TraceInterval)
+
+	if len(ks.SubPrefixes) != 0 {
+		// Keys outside of SubPrefixes would appear to be deleted.
+		return fmt.Errorf("Allocate requires a complete KeySpace (SubPrefixes are set)")
+	} else if args.Headroom < 0 || args.Headroom >= 1 {
+		return fmt.Errorf("invalid Headroom (%f; expected 0 <= Headroom < 1)", args.Headroom)
+	} else if args.MoveBudget < 0 {
+		return fmt.Errorf("invalid MoveBudget (%d; expected >= 0)", args.MoveBudget)
+	} else if args.PackHysteresis < 0 {
+		return fmt.Errorf("invalid PackHysteresis (%f; expected >= 0)", args.PackHysteresis)
+	}
+	var isLeader = state.isLeader
+	if args.IsLeader != nil {
+		isLeader = args.IsLeader
+	}
+	if args.Status != nil && args.IsLeader != nil {
+		args.Status.setIsLeader(args.IsLeader)
+	}
+	// Track leadership to invoke balanced leadership callbacks.
+	var leading bool
+	var setLeading = func(next bool) {
+		if next == leading {
+			return
+		} else if leading = next; next && args.OnGainedLeadership != nil {
+			args.OnGainedLeadership()
+		} else if !next && args.OnLostLeadership != nil {
+			args.OnLostLeadership()
+		}
+	}
+	defer setLeading(false)
 
 	defer ks.Mu.RUnlock()
 	ks.Mu.RLock()
@@ -64,13 +246,31 @@ func Allocate(args AllocateArgs) error {
 		// watched through its revision before driving further action.
 		var txnResponse *clientv3.TxnResponse
 
-		if state.isLeader() {
+		if setLeading(isLeader()); leading {
+			if args.Invariants != nil {
+				if err := args.Invariants.check(state, round); err != nil {
+					return err
+				}
+			}
+			var roundCtx, phases, span = tracer.startRound(ctx, round, state)
 
 			// Do we need to re-solve for a maximum assignment?
 			if state.NetworkHash != lastNetworkHash {
+				var _, solveSpan = phases.Start(roundCtx, "allocator.solve")
 				var startTime = time.Now()
-				desired = solveDesiredAssignments(state, desired[:0])
+				var packed int
+				var err error
+				if desired, shares, packed, err = solve(state, desired[:0], args); args.Pack {
+					allocatorPackedMembers.Set(float64(packed))
+				}
+				if err != nil {
+					endSpan(solveSpan, err)
+					endSpan(span, err)
+					return err
+				}
 				var dur = time.Since(startTime)
+				solveSpan.SetAttributes(attribute.Int("allocator.desired_assignments", len(desired)))
+				solveSpan.End()
 				allocatorMaxFlowRuntimeSeconds.Observe(dur.Seconds())
 
 				log.WithFields(log.Fields{
@@ -90,8 +290,35 @@ func Allocate(args AllocateArgs) error {
 				if len(desired) < state.ItemSlots {
 					// We cannot assign each Item to the desired number of replicas. Most likely,
 					// there are too few Members or they are poorly distributed across zones.
-					log.WithField("unattainableReplicas", state.ItemSlots-len(desired)).
-						Warn("cannot reach desired replication for all items")
+					log.WithFields(log.Fields{
+						"unattainableReplicas": state.ItemSlots - len(desired),
+						"headroom":             args.Headroom,
+					}).Warn("cannot reach desired replication for all items")
+				}
+				var violations = spreadViolations(state)
+				for _, v := range violations {
+					log.WithFields(log.Fields{
+						"item":               v.ItemID,
+						"attribute":          v.Attribute,
+						"values":             v.Values,
+						"desiredReplication": v.DesiredReplication,
+					}).Warn("too few distinct member attribute values to spread item replicas")
+				}
+				allocatorSpreadViolations.Set(float64(len(violations)))
+
+				var affinities = affinityViolations(state, desiredAssignmentsOf(desired))
+				for _, v := range affinities {
+					log.WithFields(log.Fields{
+						"item":   v.ItemID,
+						"group":  v.Group,
+						"hard":   v.Hard,
+						"reason": v.Reason,
+					}).Warn("item affinity is not satisfied")
+				}
+				allocatorAffinityViolations.Set(float64(len(affinities)))
+
+				if args.Status != nil {
+					args.Status.onSolve(dur, desired)
 				}
 				lastNetworkHash = state.NetworkHash
 			}
@@ -100,23 +327,65 @@ func Allocate(args AllocateArgs) error {
 			// and re-verify our Member key with each flush to ensure we're still leader.
 			var txn = newBatchedTxn(ctx, args.Etcd,
 				modRevisionUnchanged(state.Members[state.LocalMemberInd]))
+			traceTxns(roundCtx, phases, span, txn)
+
+			if args.IsLeader != nil {
+				// Abort the round prior to its next transaction if leadership is lost.
+				var txnDo = txn.txnDo
+				txn.txnDo = func(op clientv3.Op) (*clientv3.TxnResponse, error) {
+					if !args.IsLeader() {
+						return nil, errLostLeadership
+					}
+					return txnDo(op)
+				}
+			}
+
+			if args.Audit != nil {
+				txn.onCommit = func(ops []clientv3.Op, revision int64) {
+					if records := auditRecords(ks, ops, revision); len(records) != 0 {
+						args.Audit(records)
+					}
+				}
+			}
 
 			// Converge the current state towards |desired|.
-			var err error
-			if err = converge(txn, state, desired); err == nil {
+			var _, convergeSpan = phases.Start(roundCtx, "allocator.converge")
+			var err = converge(txn, state, desired, shares, args)
+			endSpan(convergeSpan, err)
+
+			if err == nil {
 				txnResponse, err = txn.Commit()
 			}
+			if err == nil {
+				span.SetAttributes(attribute.Bool("allocator.idle", txn.noop))
+			}
+			endSpan(span, err)
+
+			if err == errLostLeadership {
+				setLeading(false)
+
+				log.WithFields(log.Fields{"round": round, "rev": ks.Header.Revision}).
+					Info("lost leadership (aborted converge iteration)")
+			} else if err != nil {
+				allocatorTxnRetriesTotal.Inc()
 
-			if err != nil {
 				log.WithFields(log.Fields{"err": err, "round": round, "rev": ks.Header.Revision}).
 					Warn("converge iteration failed (will retry)")
 			} else {
 				allocatorConvergeTotal.Inc()
+				if txn.noop {
+					allocatorIdleRoundsTotal.Inc()
+				} else {
+					allocatorLastActiveRoundTime.Set(float64(timeNow().UnixNano()) / 1e9)
+				}
 
 				allocatorNumMembers.Set(float64(len(state.Members)))
 				allocatorNumItems.Set(float64(len(state.Items)))
 				allocatorNumItemSlots.Set(float64(state.ItemSlots))
 
+				if args.Status != nil {
+					args.Status.onConverge(txn.noop)
+				}
 				if args.TestHook != nil {
 					args.TestHook(round, txn.noop)
 				}
@@ -131,19 +400,73 @@ func Allocate(args AllocateArgs) error {
 		if txnResponse != nil && txnResponse.Header.Revision > next {
 			next = txnResponse.Header.Revision
 		}
-		if err := ks.WaitForRevision(ctx, next); err != nil {
+		if err := awaitNextRound(ctx, ks, next, args.LeaderChangedCh, state.scheduleChange); err != nil {
 			return err
 		}
+		if c := state.scheduleChange; !c.IsZero() && !timeNow().Before(c) {
+			// The scheduled replication of an Item has changed.
+			// Re-observe the State, as though the Item were updated.
+			ks.Mu.RUnlock()
+			ks.Mu.Lock()
+			state.observe()
+			ks.Mu.Unlock()
+			ks.Mu.RLock()
+		}
 	}
 }
 
+// awaitNextRound waits for the KeySpace to reach |revision|, for a signal
+// of |leaderChangedCh| (if non-nil), or until |wake| (if non-zero).
+// The KeySpace must be read-locked.
+func awaitNextRound(ctx context.Context, ks *keyspace.KeySpace, revision int64, leaderChangedCh <-chan struct{}, wake time.Time) error {
+	if leaderChangedCh == nil && wake.IsZero() {
+		return ks.WaitForRevision(ctx, revision)
+	}
+	var waitCtx, cancel = context.WithCancel(ctx)
+	defer cancel()
+
+	if !wake.IsZero() {
+		var cancelTimeout context.CancelFunc
+		waitCtx, cancelTimeout = context.WithTimeout(waitCtx, wake.Sub(timeNow()))
+		defer cancelTimeout()
+	}
+	if leaderChangedCh != nil {
+		// A signal consumed after WaitForRevision returns is harmless,
+		// as the caller re-checks leadership with every round.
+		go func() {
+			select {
+			case <-leaderChangedCh:
+				cancel()
+			case <-waitCtx.Done():
+			}
+		}()
+	}
+
+	var err = ks.WaitForRevision(waitCtx, revision)
+	if err != nil && ctx.Err() == nil {
+		return nil // Woken by a change of leadership, or of scheduled replication.
+	}
+	return err
+}
+
+// errLostLeadership is returned by a transaction of a convergence round
+// which observed a loss of externally-managed leadership.
+var errLostLeadership = fmt.Errorf("lost leadership")
+
 // converge identifies and applies allowed incremental changes which bring the
 // current state closer to the |desired| state. A change is allowed iff it does
 // not cause any Item or Member replication constraints to be violated (eg, by
 // leaving an Item with too few consistent replicas, or a Member with too many
-// assigned Items).
-func converge(txn checkpointTxn, as *State, desired []Assignment) error {
-	var itemState = itemState{global: as}
+// assigned Items, after reserving the Headroom of |args|). If |shares| is
+// non-nil, an Item's replication is bounded by its fair share. New Assignments
+// are created with values of the AssignmentValue of |args|, and moves are
+// limited by its MoveBudget.
+func converge(txn checkpointTxn, as *State, desired []Assignment, shares []int, args AllocateArgs) error {
+	var itemState = itemState{global: as, shares: shares, headroom: args.Headroom, value: args.AssignmentValue}
+	if args.MoveBudget != 0 {
+		var budget = args.MoveBudget
+		itemState.moves = &budget
+	}
 	var lastCRE int // cur.RightEnd of the previous iteration.
 
 	// Walk Items, joined with their current Assignments. Simultaneously walk
@@ -206,7 +529,55 @@ func removeDeadAssignments(txn checkpointTxn, ks *keyspace.KeySpace, asn keyspac
 	return nil
 }
 
-func solveDesiredAssignments(s *State, desired []Assignment) []Assignment {
+// solve solves for a maximum assignment of the State under the Cost, Headroom,
+// FairShare, Pack, PackHysteresis, and WarmStart of |args|. It returns desired
+// Assignments appended to |desired|, the fair-share replication of each Item
+// (or nil if not FairShare), and the number of packed Members (if Pack).
+func solve(s *State, desired []Assignment, args AllocateArgs) (_ []Assignment, shares []int, packed int, err error) {
+	var sa = solveArgs{warmStart: args.WarmStart, cost: args.Cost, headroom: args.Headroom}
+
+	if args.FairShare {
+		sa.shares = fairShares(s.Items, memberSlots(s, args.Headroom), s.desiredReplication)
+	}
+	if args.Pack {
+		desired, packed, err = solvePackedAssignments(s, desired, sa, args.PackHysteresis)
+	} else {
+		desired, err = solveDesiredAssignments(s, desired, sa)
+	}
+	return desired, sa.shares, packed, err
+}
+
+// solveArgs are arguments of a solve for a maximum assignment of a State.
+type solveArgs struct {
+	warmStart bool                      // Whether to warm-start from current Assignments.
+	cost      CostFunc                  // Optional CostFunc of Assignments.
+	headroom  float64                   // Fraction of Member ItemLimits held in reserve.
+	shares    []int                     // Optional fair-share replication of each State Item.
+	packed    []bool                    // Optional Members to which Items may be assigned.
+	anchors   map[string]affinityAnchor // Optional anchors of Items having affinities.
+}
+
+// solveDesiredAssignments solves for a maximum assignment of the State.
+// If Items have affinities, the solve is made in two passes: the first places
+// the anchor of each affinity group, and the second co-locates the group
+// with the anchor's placement. The |anchors| of |args| are ignored.
+func solveDesiredAssignments(s *State, desired []Assignment, args solveArgs) ([]Assignment, error) {
+	var from = len(desired)
+	var err error
+
+	args.anchors = nil
+	if desired, err = solveNetworks(s, desired, args); err != nil {
+		return nil, err
+	}
+	if args.anchors = affinityAnchors(s, desiredAssignmentsOf(desired[from:])); args.anchors == nil {
+		return desired, nil
+	}
+	return solveNetworks(s, desired[:from], args)
+}
+
+// solveNetworks solves for a maximum assignment of the State, co-locating
+// Items having affinities with the |anchors| of |args| (if non-nil).
+func solveNetworks(s *State, desired []Assignment, args solveArgs) ([]Assignment, error) {
 	// Number of items to lump into each invocation of push/relabel.
 	// This is an arbitrary number which is empirically fast to solve,
 	// but is large enough that we're unlikely to see further improvements
@@ -225,10 +596,30 @@ func solveDesiredAssignments(s *State, desired []Assignment) []Assignment {
 
 		// Build a prioritized flow network and solve for maximum flow.
 		var network = newSparseFlowNetwork(s, items)
-		var maxFlow = sparse_push_relabel.FindMaxFlow(network)
+		network.cost = args.cost
+		network.reserveHeadroom(args.headroom)
+		if args.packed != nil {
+			network.packMembers(args.packed)
+		}
+		if args.anchors != nil {
+			network.buildAffinityArcs(args.anchors)
+		}
+		if args.shares != nil {
+			network.itemShares = args.shares[i*itemsPerNetwork : end]
+		}
+		var maxFlow *sparse_push_relabel.MaxFlow
+
+		if args.warmStart {
+			maxFlow = sparse_push_relabel.FindMaxFlow(warmStartNetwork{network})
+		} else {
+			maxFlow = sparse_push_relabel.FindMaxFlow(network)
+		}
+		if network.costErr != nil {
+			return nil, network.costErr
+		}
 		desired = network.extractAssignments(maxFlow, desired)
 	}
-	return desired
+	return desired, nil
 }
 
 // modRevisionUnchanged returns a Cmp which verifies the key has not changed
@@ -272,6 +663,8 @@ type batchedTxn struct {
 	fixedCmps []clientv3.Cmp
 	// Flags whether no operations have committed with this batchedTxn.
 	noop bool
+	// Optional callback invoked with the Ops and revision of each committed Txn.
+	onCommit func(ops []clientv3.Op, revision int64)
 }
 
 // newBatchedTxn returns a batchedTxn using the given Context and KV. It will
@@ -349,8 +742,14 @@ func (b *batchedTxn) Commit() (*clientv3.TxnResponse, error) {
 	if err != nil {
 		return nil, err
 	} else if !response.Succeeded {
+		// Compare conditions failed: our view of the KeySpace is stale, or
+		// another leader has modified it (eg, during a leader transition).
+		allocatorTxnConflictsTotal.Inc()
 		return response, fmt.Errorf("transaction checks did not succeed")
 	} else {
+		if b.onCommit != nil {
+			b.onCommit(b.ops, response.Header.Revision)
+		}
 		b.noop = false
 		b.cmps, b.ops = b.cmps[:0], b.ops[:0]
 		return response, nil
@@ -18,6 +18,8 @@ const (
 	MembersPrefix = "/members/"
 	// AssignmentsPrefix prefixes Assignment keys, eg "prefix/assign/item-id#zone#member-suffix#slot"
 	AssignmentsPrefix = "/assign/"
+	// EvacuationsPrefix prefixes ZoneEvacuation keys, eg "root/evacuate/zone"
+	EvacuationsPrefix = "/evacuate/"
 	// '#' is selected as separator, because it's the first visual ASCII character
 	// which is not interpreted by shells (preceding visual characters are " and !).
 	// The fact that it's lowest-value ensures that the natural ordering of KeySpace
@@ -77,6 +79,12 @@ type Assignment struct {
 	AssignmentValue
 }
 
+// ZoneEvacuation marks a Zone as evacuated: its Members are treated as
+// having no item slots, and their Items are migrated to other zones.
+type ZoneEvacuation struct {
+	Zone string
+}
+
 // LocalItem represents an Item which is assigned to the local Allocator.
 type LocalItem struct {
 	Item        keyspace.KeyValue  // Item which is locally Assigned.
@@ -88,11 +96,14 @@ type LocalItem struct {
 // Decoder, and suitable for use with NewKeySpace of the same |prefix|.
 // Some implementations may wish to further wrap the returned KeyValueDecoder
 // to enable recognition and decoding of additional custom prefixes and entity
-// types, beyond the Allocator's Members, Items, & Assignments.
+// types, beyond the Allocator's Members, Items, Assignments, & ZoneEvacuations.
+// Keys of other prefixes fail to decode with keyspace.ErrUnknownKey, and are
+// ignored by the KeySpace.
 func NewAllocatorKeyValueDecoder(prefix string, decode Decoder) keyspace.KeyValueDecoder {
 	var membersPrefix = prefix + MembersPrefix
 	var itemsPrefix = prefix + ItemsPrefix
 	var assignmentsPrefix = prefix + AssignmentsPrefix
+	var evacuationsPrefix = prefix + EvacuationsPrefix
 
 	return func(raw *mvccpb.KeyValue) (interface{}, error) {
 		switch {
@@ -125,8 +136,16 @@ func NewAllocatorKeyValueDecoder(prefix string, decode Decoder) keyspace.KeyValu
 				return Assignment{ItemID: p[0], MemberZone: p[1], MemberSuffix: p[2], Slot: slot, AssignmentValue: value}, nil
 			}
 
+		case bytes.HasPrefix(raw.Key, []byte(evacuationsPrefix)):
+			if p := strings.Split(string(raw.Key[len(evacuationsPrefix):]), Sep); len(p) != 1 {
+				return nil, fmt.Errorf("expected (zone) in evacuation key")
+			} else {
+				return ZoneEvacuation{Zone: p[0]}, nil
+			}
+
 		default:
-			return nil, fmt.Errorf("unexpected key prefix")
+			// Ignore keys of prefixes which may be added by future releases.
+			return nil, fmt.Errorf("unexpected key prefix: %w", keyspace.ErrUnknownKey)
 		}
 	}
 }
@@ -163,6 +182,12 @@ func AssignmentKey(ks *keyspace.KeySpace, a Assignment) string {
 	return ItemAssignmentsPrefix(ks, a.ItemID) + a.MemberZone + Sep + a.MemberSuffix + Sep + strconv.Itoa(a.Slot)
 }
 
+// ZoneEvacuationKey returns the unique key for a ZoneEvacuation of |zone| under the KeySpace.
+func ZoneEvacuationKey(ks *keyspace.KeySpace, zone string) string {
+	assertAboveSep(zone)
+	return ks.Root + EvacuationsPrefix + zone
+}
+
 // LookupMember returns the identified Member, or false if not found.
 // The KeySpace must already be locked.
 func LookupMember(ks *keyspace.KeySpace, zone, suffix string) (Member, bool) {
@@ -183,9 +208,10 @@ func LookupItem(ks *keyspace.KeySpace, id string) (Item, bool) {
 	}
 }
 
-func memberAt(kv keyspace.KeyValues, i int) Member         { return kv[i].Decoded.(Member) }
-func itemAt(kv keyspace.KeyValues, i int) Item             { return kv[i].Decoded.(Item) }
-func assignmentAt(kv keyspace.KeyValues, i int) Assignment { return kv[i].Decoded.(Assignment) }
+func memberAt(kv keyspace.KeyValues, i int) Member             { return kv[i].Decoded.(Member) }
+func itemAt(kv keyspace.KeyValues, i int) Item                 { return kv[i].Decoded.(Item) }
+func assignmentAt(kv keyspace.KeyValues, i int) Assignment     { return kv[i].Decoded.(Assignment) }
+func evacuationAt(kv keyspace.KeyValues, i int) ZoneEvacuation { return kv[i].Decoded.(ZoneEvacuation) }
 
 // compareAssignment defines an order of Assignment over ItemID, MemberZone,
 // and MemberSuffix. It matches the natural key order, with the exception of
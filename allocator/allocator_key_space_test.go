@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strings"
 
 	"go.etcd.io/etcd/api/v3/mvccpb"
 	clientv3 "go.etcd.io/etcd/client/v3"
@@ -336,29 +337,44 @@ func (d testAllocDecoder) DecodeMember(zone, suffix string, raw *mvccpb.KeyValue
 }
 
 func (d testAllocDecoder) DecodeAssignment(itemID, zone, suffix string, slot int, raw *mvccpb.KeyValue) (AssignmentValue, error) {
-	switch s := string(raw.Value); s {
-	case "":
+	switch s := string(raw.Value); {
+	case s == "", strings.HasPrefix(s, "pending:"):
 		return testAssignment{consistent: false}, nil
-	case "consistent":
+	case s == "consistent":
 		return testAssignment{consistent: true}, nil
 	default:
 		return nil, fmt.Errorf("invalid value: %s", s)
 	}
 }
 
-type testItem struct{ R int }
+type testItem struct {
+	R, W int
+	S    string
+	G    []string               `json:",omitempty"`
+	H    bool                   `json:",omitempty"`
+	T    []ScheduledReplication `json:",omitempty"`
+}
 
-func (i testItem) DesiredReplication() int { return i.R }
+func (i testItem) DesiredReplication() int                     { return i.R }
+func (i testItem) Weight() int                                 { return i.W }
+func (i testItem) SpreadAttribute() string                     { return i.S }
+func (i testItem) AffinityGroups() []string                    { return i.G }
+func (i testItem) HardAffinity() bool                          { return i.H }
+func (i testItem) ReplicationSchedule() []ScheduledReplication { return i.T }
 
 func isConsistent(_ Item, assignment keyspace.KeyValue, allAssignments keyspace.KeyValues) bool {
 	return assignment.Decoded.(Assignment).AssignmentValue.(testAssignment).consistent
 }
 
-type testMember struct{ R int }
+type testMember struct {
+	R int
+	A map[string]string `json:",omitempty"`
+}
 
-func (m testMember) ItemLimit() int  { return m.R }
-func (m testMember) Validate() error { return nil }
-func (m *testMember) ZeroLimit()     { m.R = 0 }
+func (m testMember) ItemLimit() int               { return m.R }
+func (m testMember) Attribute(name string) string { return m.A[name] }
+func (m testMember) Validate() error              { return nil }
+func (m *testMember) ZeroLimit()                  { m.R = 0 }
 
 func (m *testMember) MarshalString() string {
 	if b, err := json.Marshal(m); err != nil {
@@ -4,6 +4,7 @@ import (
 	"context"
 	"testing"
 
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	epb "go.etcd.io/etcd/api/v3/etcdserverpb"
 	clientv3 "go.etcd.io/etcd/client/v3"
 	"go.gazette.dev/core/etcdtest"
@@ -72,7 +73,7 @@ func (s *AllocatorSuite) TestConvergeFixtureCases(c *gc.C) {
 
 		{ItemID: "item-two", MemberZone: "us-east", MemberSuffix: "bar"},
 		{ItemID: "item-two", MemberZone: "us-west", MemberSuffix: "baz"},
-	})
+	}, nil, AllocateArgs{})
 
 	var expectCmps = []clientv3.Cmp{
 		clientv3.Compare(clientv3.CreateRevision("/root/items/item-missing"), "=", 0),
@@ -95,7 +96,7 @@ func (s *AllocatorSuite) TestConvergeFixtureCases(c *gc.C) {
 
 		{ItemID: "item-two", MemberZone: "us-east", MemberSuffix: "foo"},
 		{ItemID: "item-two", MemberZone: "us-west", MemberSuffix: "baz"},
-	})
+	}, nil, AllocateArgs{})
 
 	// In addition to the cleanup checks of the previous case,
 	// expect Member us-east/foo is also verified as unchanged.
@@ -169,12 +170,15 @@ func (s *AllocatorSuite) TestTxnBatching(c *gc.C) {
 		nil,
 	))
 
-	// Empty Checkpoint, then Commit. Expect it's treated as a no-op.
+	// Empty Checkpoint, then Commit. Expect it's treated as a no-op,
+	// which isn't counted as a conflict.
+	var conflicts = testutil.ToFloat64(allocatorTxnConflictsTotal)
 	c.Check(txn.Checkpoint(), gc.IsNil)
 
 	r, err = txn.Commit()
 	c.Check(r, gc.IsNil)
 	c.Check(err, gc.IsNil)
+	c.Check(testutil.ToFloat64(allocatorTxnConflictsTotal), gc.Equals, conflicts)
 
 	// Non-empty commit that fails checks. Expect it's mapped to an error.
 	c.Check(txn.Then(testOp).Checkpoint(), gc.IsNil)
@@ -183,6 +187,7 @@ func (s *AllocatorSuite) TestTxnBatching(c *gc.C) {
 	r, err = txn.Commit()
 	c.Check(r, gc.Equals, txnResp)
 	c.Check(err, gc.ErrorMatches, "transaction checks did not succeed")
+	c.Check(testutil.ToFloat64(allocatorTxnConflictsTotal), gc.Equals, conflicts+1)
 }
 
 var _ = gc.Suite(&AllocatorSuite{})
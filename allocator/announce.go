@@ -8,9 +8,9 @@ import (
 
 	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
-	"go.etcd.io/etcd/client/v3/concurrency"
 	"go.etcd.io/etcd/client/v3"
 	"go.gazette.dev/core/task"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // Announcement manages a unique key which is "announced" to peers through Etcd,
@@ -21,13 +21,13 @@ type Announcement struct {
 	Key      string
 	Revision int64
 
-	etcd *clientv3.Client
+	etcd clientv3.KV
 }
 
 // Announce a key and value to etcd under the LeaseID, asserting the key doesn't
 // already exist. If the key does exist, Announce will retry until it disappears
 // (eg, due to a former lease timeout).
-func Announce(etcd *clientv3.Client, key, value string, lease clientv3.LeaseID) *Announcement {
+func Announce(etcd clientv3.KV, key, value string, lease clientv3.LeaseID) *Announcement {
 	for {
 		var resp, err = etcd.Txn(context.Background()).
 			If(clientv3.Compare(clientv3.Version(key), "=", 0)).
@@ -69,19 +69,41 @@ func (a *Announcement) Update(value string) error {
 	return err
 }
 
+// EtcdClient is the subset of a *clientv3.Client which is used by an allocator
+// session: it reads and writes keys, and grants and keeps alive a member lease.
+// An EtcdClient may be a decorator of a *clientv3.Client, such as one which
+// adds tracing or rate limiting, or a fake for testing.
+type EtcdClient interface {
+	clientv3.KV
+	clientv3.Lease
+}
+
 // SessionArgs are arguments of StartSession.
 type SessionArgs struct {
-	Etcd  *clientv3.Client
+	Etcd  EtcdClient
 	Tasks *task.Group
 	Spec  interface {
 		Validate() error
 		ZeroLimit()
 		MarshalString() string
 	}
-	State    *State
-	LeaseTTL time.Duration
-	SignalCh <-chan os.Signal
-	TestHook func(round int, isIdle bool)
+	State     *State
+	LeaseTTL  time.Duration
+	SignalCh  <-chan os.Signal
+	TestHook  func(round int, isIdle bool)
+	Audit     AuditFunc
+	WarmStart bool
+	Status    *StatusHandler
+	// IsLeader and LeaderChangedCh are optional, externally-managed
+	// leadership signals. See AllocateArgs.
+	IsLeader        func() bool
+	LeaderChangedCh <-chan struct{}
+	// AssignmentValue is an optional AssignmentValueFunc. See AllocateArgs.
+	AssignmentValue AssignmentValueFunc
+	// Tracer and TraceInterval optionally trace convergence rounds.
+	// See AllocateArgs.
+	Tracer        trace.Tracer
+	TraceInterval time.Duration
 }
 
 // StartSession starts an allocator session. It:
@@ -97,7 +119,7 @@ func StartSession(args SessionArgs) error {
 	if err := args.Spec.Validate(); err != nil {
 		return errors.WithMessage(err, "spec.Validate")
 	}
-	var lease, err = concurrency.NewSession(args.Etcd, concurrency.WithTTL(int(args.LeaseTTL.Seconds())))
+	var lease, err = grantMemberLease(args.Etcd, args.LeaseTTL)
 	if err != nil {
 		return errors.WithMessage(err, "establishing Etcd lease")
 	}
@@ -113,7 +135,7 @@ func StartSession(args SessionArgs) error {
 		}
 	})
 
-	var ann = Announce(args.Etcd, args.State.LocalKey, args.Spec.MarshalString(), lease.Lease())
+	var ann = Announce(args.Etcd, args.State.LocalKey, args.Spec.MarshalString(), lease.id)
 
 	// Initialize the KeySpace at the announcement revision.
 	if err = args.State.KS.Load(context.Background(), args.Etcd, ann.Revision); err != nil {
@@ -141,10 +163,19 @@ func StartSession(args SessionArgs) error {
 		defer args.Tasks.Cancel()
 
 		var err = Allocate(AllocateArgs{
-			Context:  args.Tasks.Context(),
-			Etcd:     args.Etcd,
-			State:    args.State,
-			TestHook: args.TestHook,
+			Context:   args.Tasks.Context(),
+			Etcd:      args.Etcd,
+			State:     args.State,
+			TestHook:  args.TestHook,
+			Audit:     args.Audit,
+			WarmStart: args.WarmStart,
+			Status:    args.Status,
+
+			IsLeader:        args.IsLeader,
+			LeaderChangedCh: args.LeaderChangedCh,
+			AssignmentValue: args.AssignmentValue,
+			Tracer:          args.Tracer,
+			TraceInterval:   args.TraceInterval,
 		})
 		if errors.Cause(err) == context.Canceled {
 			err = nil
@@ -155,4 +186,66 @@ func StartSession(args SessionArgs) error {
 	return nil
 }
 
+// memberLease is an Etcd lease which is kept alive until it's closed.
+type memberLease struct {
+	id     clientv3.LeaseID
+	lease  clientv3.Lease
+	ttl    time.Duration
+	cancel context.CancelFunc
+	doneCh chan struct{}
+}
+
+// grantMemberLease grants a lease of the |ttl| (or a default of one minute,
+// if less than one second) and begins to keep it alive.
+func grantMemberLease(lease clientv3.Lease, ttl time.Duration) (*memberLease, error) {
+	var seconds = int64(ttl.Seconds())
+	if seconds <= 0 {
+		seconds = defaultLeaseTTLSeconds
+	}
+	var resp, err = lease.Grant(context.Background(), seconds)
+	if err != nil {
+		return nil, err
+	}
+
+	var ctx, cancel = context.WithCancel(context.Background())
+	keepAliveCh, err := lease.KeepAlive(ctx, resp.ID)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	var l = &memberLease{
+		id:     resp.ID,
+		lease:  lease,
+		ttl:    time.Duration(seconds) * time.Second,
+		cancel: cancel,
+		doneCh: make(chan struct{}),
+	}
+	go func() {
+		defer close(l.doneCh)
+		// Drain responses until keep-alives are cancelled, or the lease
+		// could not be kept alive within its deadline.
+		for range keepAliveCh {
+		}
+	}()
+	return l, nil
+}
+
+// Done is closed when the lease is no longer kept alive.
+func (l *memberLease) Done() <-chan struct{} { return l.doneCh }
+
+// Close stops keep-alives of the lease, and revokes it.
+func (l *memberLease) Close() error {
+	l.cancel()
+	<-l.doneCh
+
+	var ctx, cancel = context.WithTimeout(context.Background(), l.ttl)
+	defer cancel()
+
+	var _, err = l.lease.Revoke(ctx, l.id)
+	return err
+}
+
 var announceConflictRetryInterval = time.Second * 10
+
+// defaultLeaseTTLSeconds is the TTL of a member lease having no LeaseTTL.
+const defaultLeaseTTLSeconds = 60
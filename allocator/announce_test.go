@@ -5,6 +5,7 @@ import (
 	"os"
 	"time"
 
+	clientv3 "go.etcd.io/etcd/client/v3"
 	"go.etcd.io/etcd/client/v3/concurrency"
 	"go.gazette.dev/core/etcdtest"
 	"go.gazette.dev/core/task"
@@ -75,9 +76,11 @@ func (s *AnnounceSuite) TestBasicSessionStart(c *gc.C) {
 		sigCh = make(chan os.Signal)
 		spec  = &testMember{R: 10}
 		state = NewObservedState(ks, MemberKey(ks, "a", "member"), isConsistent)
+		// Use a decorated EtcdClient, which counts lease operations.
+		counting = &countingEtcd{Client: etcd}
 
 		args = SessionArgs{
-			Etcd:     etcd,
+			Etcd:     counting,
 			Tasks:    task.NewGroup(context.Background()),
 			Spec:     spec,
 			State:    state,
@@ -111,6 +114,33 @@ func (s *AnnounceSuite) TestBasicSessionStart(c *gc.C) {
 	leasesResp, err := etcd.Leases(context.Background())
 	c.Check(err, gc.IsNil)
 	c.Check(leasesResp.Leases, gc.HasLen, 0)
+
+	// Expect the lease was granted, kept alive, and revoked through the decorator.
+	c.Check(counting.grants, gc.Equals, 1)
+	c.Check(counting.keepAlives, gc.Equals, 1)
+	c.Check(counting.revokes, gc.Equals, 1)
+}
+
+// countingEtcd is an EtcdClient which decorates a *clientv3.Client,
+// counting its lease operations.
+type countingEtcd struct {
+	*clientv3.Client
+	grants, keepAlives, revokes int
+}
+
+func (e *countingEtcd) Grant(ctx context.Context, ttl int64) (*clientv3.LeaseGrantResponse, error) {
+	e.grants++
+	return e.Client.Grant(ctx, ttl)
+}
+
+func (e *countingEtcd) KeepAlive(ctx context.Context, id clientv3.LeaseID) (<-chan *clientv3.LeaseKeepAliveResponse, error) {
+	e.keepAlives++
+	return e.Client.KeepAlive(ctx, id)
+}
+
+func (e *countingEtcd) Revoke(ctx context.Context, id clientv3.LeaseID) (*clientv3.LeaseRevokeResponse, error) {
+	e.revokes++
+	return e.Client.Revoke(ctx, id)
 }
 
 var _ = gc.Suite(&AnnounceSuite{})
@@ -0,0 +1,67 @@
+package allocator
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"go.gazette.dev/core/keyspace"
+)
+
+// AuditRecord is a compact record of an Assignment change which was
+// committed to Etcd by the Allocator.
+type AuditRecord struct {
+	// Etcd revision at which the change was committed.
+	Revision int64 `json:"rev"`
+	// Op is "put" if the Assignment was created or updated,
+	// and "delete" if it was removed.
+	Op           string `json:"op"`
+	ItemID       string `json:"item"`
+	MemberZone   string `json:"zone"`
+	MemberSuffix string `json:"suffix"`
+	Slot         int    `json:"slot"`
+}
+
+// AuditFunc is notified of AuditRecords of each Allocator transaction which
+// committed Assignment changes. It's invoked synchronously from the
+// allocation loop while the KeySpace is read-locked, and must not block.
+type AuditFunc func([]AuditRecord)
+
+// auditRecords returns AuditRecords for Assignment puts and deletes of the
+// Ops, which were committed at |revision|.
+func auditRecords(ks *keyspace.KeySpace, ops []clientv3.Op, revision int64) []AuditRecord {
+	var prefix = []byte(ks.Root + AssignmentsPrefix)
+	var out []AuditRecord
+
+	for _, op := range ops {
+		var key = op.KeyBytes()
+		if !bytes.HasPrefix(key, prefix) {
+			continue
+		}
+		var p = strings.Split(string(key[len(prefix):]), Sep)
+		if len(p) != 4 {
+			continue
+		}
+		var slot, err = strconv.Atoi(p[3])
+		if err != nil {
+			continue
+		}
+		var r = AuditRecord{
+			Revision:     revision,
+			ItemID:       p[0],
+			MemberZone:   p[1],
+			MemberSuffix: p[2],
+			Slot:         slot,
+		}
+		if op.IsPut() {
+			r.Op = "put"
+		} else if op.IsDelete() {
+			r.Op = "delete"
+		} else {
+			continue
+		}
+		out = append(out, r)
+	}
+	return out
+}
@@ -0,0 +1,44 @@
+package allocator
+
+import (
+	epb "go.etcd.io/etcd/api/v3/etcdserverpb"
+	clientv3 "go.etcd.io/etcd/client/v3"
+	gc "gopkg.in/check.v1"
+)
+
+type AuditSuite struct{}
+
+func (s *AuditSuite) TestRecordsOfCommittedTxn(c *gc.C) {
+	var ks = NewAllocatorKeySpace("/root", testAllocDecoder{})
+	var records []AuditRecord
+
+	var txn = batchedTxn{
+		txnDo: func(op clientv3.Op) (*clientv3.TxnResponse, error) {
+			return &clientv3.TxnResponse{
+				Succeeded: true,
+				Header:    &epb.ResponseHeader{Revision: 1234},
+			}, nil
+		},
+		onCommit: func(ops []clientv3.Op, revision int64) {
+			records = append(records, auditRecords(ks, ops, revision)...)
+		},
+	}
+	c.Check(txn.Then(
+		clientv3.OpPut("/root/assign/item-1#us-east#foo#0", ""),
+		clientv3.OpDelete("/root/assign/item-2#us-west#bar#1"),
+		// Ops which don't represent Assignments are ignored.
+		clientv3.OpPut("/root/items/item-1", "{}"),
+		clientv3.OpPut("/root/assign/malformed#key", ""),
+		clientv3.OpPut("/root/assign/item-1#us-east#foo#not-a-slot", ""),
+	).Checkpoint(), gc.IsNil)
+
+	var _, err = txn.Commit()
+	c.Check(err, gc.IsNil)
+
+	c.Check(records, gc.DeepEquals, []AuditRecord{
+		{Revision: 1234, Op: "put", ItemID: "item-1", MemberZone: "us-east", MemberSuffix: "foo", Slot: 0},
+		{Revision: 1234, Op: "delete", ItemID: "item-2", MemberZone: "us-west", MemberSuffix: "bar", Slot: 1},
+	})
+}
+
+var _ = gc.Suite(&AuditSuite{})
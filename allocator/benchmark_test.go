@@ -17,7 +17,10 @@ import (
 
 func BenchmarkAll(b *testing.B) {
 	b.Run("simulated-deploy", func(b *testing.B) {
-		benchmarkSimulatedDeploy(b)
+		benchmarkSimulatedDeploy(b, false)
+	})
+	b.Run("simulated-deploy-warm-start", func(b *testing.B) {
+		benchmarkSimulatedDeploy(b, true)
 	})
 }
 
@@ -27,12 +30,13 @@ type BenchmarkHealthSuite struct{}
 func (s *BenchmarkHealthSuite) TestBenchmarkHealth(c *gc.C) {
 	var fakeB = testing.B{N: 1}
 
-	benchmarkSimulatedDeploy(&fakeB)
+	benchmarkSimulatedDeploy(&fakeB, false)
+	benchmarkSimulatedDeploy(&fakeB, true)
 }
 
 var _ = gc.Suite(&BenchmarkHealthSuite{})
 
-func benchmarkSimulatedDeploy(b *testing.B) {
+func benchmarkSimulatedDeploy(b *testing.B, warmStart bool) {
 	var client = etcdtest.TestClient()
 	defer etcdtest.Cleanup()
 
@@ -135,17 +139,25 @@ func benchmarkSimulatedDeploy(b *testing.B) {
 	require.NoError(b, ks.Load(ctx, client, 0))
 	go ks.Watch(ctx, client)
 
+	var solves, solveSeconds = histogramVal(allocatorMaxFlowRuntimeSeconds)
+
 	require.NoError(b, Allocate(AllocateArgs{
-		Context:  ctx,
-		Etcd:     client,
-		State:    state,
-		TestHook: testHook,
+		Context:   ctx,
+		Etcd:      client,
+		State:     state,
+		TestHook:  testHook,
+		WarmStart: warmStart,
 	}))
 
+	var solvesAfter, solveSecondsAfter = histogramVal(allocatorMaxFlowRuntimeSeconds)
+
 	log.WithFields(log.Fields{
-		"adds":    counterVal(allocatorAssignmentAddedTotal),
-		"removes": counterVal(allocatorAssignmentRemovedTotal),
-		"packs":   counterVal(allocatorAssignmentPackedTotal),
+		"adds":         counterVal(allocatorAssignmentAddedTotal),
+		"removes":      counterVal(allocatorAssignmentRemovedTotal),
+		"packs":        counterVal(allocatorAssignmentPackedTotal),
+		"warmStart":    warmStart,
+		"solves":       solvesAfter - solves,
+		"solveSeconds": solveSecondsAfter - solveSeconds,
 	}).Info("final metrics")
 }
 
@@ -168,3 +180,11 @@ func counterVal(c prometheus.Counter) float64 {
 	}
 	return *out.Counter.Value
 }
+
+func histogramVal(h prometheus.Histogram) (count uint64, sum float64) {
+	var out dto.Metric
+	if err := h.Write(&out); err != nil {
+		panic(err)
+	}
+	return *out.Histogram.SampleCount, *out.Histogram.SampleSum
+}
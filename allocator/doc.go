@@ -18,6 +18,10 @@ var (
 		Name: "gazette_allocator_assignment_added_total",
 		Help: "Cumulative number of item / member assignments added by the allocator.",
 	})
+	allocatorAssignmentDeferredTotal = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "gazette_allocator_assignment_deferred_total",
+		Help: "Cumulative number of item / member assignment moves deferred by the allocator's MoveBudget.",
+	})
 	allocatorAssignmentPackedTotal = promauto.NewCounter(prometheus.CounterOpts{
 		Name: "gazette_allocator_assignment_packed_total",
 		Help: "Cumulative number of item / member assignments packed by the allocator.",
@@ -26,10 +30,30 @@ var (
 		Name: "gazette_allocator_assignment_removed_total",
 		Help: "Cumulative number of item / member assignments removed by the allocator.",
 	})
+	allocatorInvariantViolationsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "gazette_allocator_invariant_violations_total",
+		Help: "Cumulative number of allocator invariant violations which persisted across a convergence round's commit.",
+	}, []string{"invariant"})
 	allocatorConvergeTotal = promauto.NewCounter(prometheus.CounterOpts{
 		Name: "gazette_allocator_converge_total",
 		Help: "Cumulative number of converge iterations.",
 	})
+	allocatorIdleRoundsTotal = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "gazette_allocator_idle_rounds_total",
+		Help: "Cumulative number of converge iterations which found no changes to make.",
+	})
+	allocatorLastActiveRoundTime = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "gazette_allocator_last_active_round_timestamp_seconds",
+		Help: "Unix time of the last converge iteration which made changes.",
+	})
+	allocatorTxnConflictsTotal = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "gazette_allocator_txn_conflicts_total",
+		Help: "Cumulative number of allocator Etcd transactions which failed their compare conditions.",
+	})
+	allocatorTxnRetriesTotal = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "gazette_allocator_txn_retries_total",
+		Help: "Cumulative number of converge iterations which failed to commit and will be retried.",
+	})
 	allocatorMaxFlowRuntimeSeconds = promauto.NewHistogram(prometheus.HistogramOpts{
 		Name: "gazette_allocator_max_flow_runtime_seconds",
 		Help: "Duration required to re-solve for maximum assignment.",
@@ -46,4 +70,16 @@ var (
 		Name: "gazette_allocator_members",
 		Help: "Number of members known to the allocator.",
 	})
+	allocatorSpreadViolations = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "gazette_allocator_spread_violations",
+		Help: "Number of items having too few distinct member attribute values to satisfy their spread constraint.",
+	})
+	allocatorAffinityViolations = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "gazette_allocator_affinity_violations",
+		Help: "Number of items whose affinity group is not satisfied by their current assignments.",
+	})
+	allocatorPackedMembers = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "gazette_allocator_packed_members",
+		Help: "Number of members onto which items are packed, where the allocator packs items.",
+	})
 )
@@ -0,0 +1,48 @@
+package allocator
+
+import (
+	"context"
+
+	"go.etcd.io/etcd/client/v3"
+	"go.gazette.dev/core/keyspace"
+)
+
+// EvacuateZone evacuates |zone| by creating its ZoneEvacuation key. Members
+// of an evacuated zone are cordoned, receiving no new Assignments, and are
+// drained: their Items are migrated to Members of other zones through the
+// usual allocation process, which adds a replacement Assignment before
+// removing the evacuated one, waits for the Item to be consistent, and
+// respects AllocateArgs.MoveBudget. Members of other zones are never assigned
+// beyond their ItemLimits: if they have too few item slots, Items which can't
+// be migrated remain with evacuated Members until slots become available, and
+// Items which can't otherwise be placed are left under-replicated (see
+// Status.UnderReplicatedItems and ExplainPlacement).
+//
+// The zone remains evacuated, and will receive no Assignments even as
+// Members join it, until RestoreZone is called. EvacuateZone is idempotent.
+//
+// Allocators of releases which predate zone evacuation don't recognize
+// ZoneEvacuation keys: they log decode errors of each key, and don't honor
+// it should they lead the allocation. Every Member of a deployment must run
+// a release which supports evacuation before a zone is evacuated.
+func EvacuateZone(ctx context.Context, etcd clientv3.KV, ks *keyspace.KeySpace, zone string) error {
+	var _, err = etcd.Put(ctx, ZoneEvacuationKey(ks, zone), "")
+	return err
+}
+
+// RestoreZone removes the ZoneEvacuation of |zone|, after which its Members
+// may again be assigned Items. RestoreZone is idempotent.
+func RestoreZone(ctx context.Context, etcd clientv3.KV, ks *keyspace.KeySpace, zone string) error {
+	var _, err = etcd.Delete(ctx, ZoneEvacuationKey(ks, zone))
+	return err
+}
+
+// EvacuatedZones returns the sorted zones having a ZoneEvacuation.
+// The KeySpace must already be locked.
+func (s *State) EvacuatedZones() []string {
+	var out []string
+	for i := range s.Evacuations {
+		out = append(out, evacuationAt(s.Evacuations, i).Zone)
+	}
+	return out
+}
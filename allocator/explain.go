@@ -0,0 +1,214 @@
+package allocator
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// PlacementExplanation explains the placement of an Item upon Members,
+// as returned by State.ExplainPlacement.
+type PlacementExplanation struct {
+	ItemID string
+	// DesiredReplication of the Item, and its current number of Assignments.
+	DesiredReplication int
+	Assigned           int
+	// Members of the State, in order of preference for placement of the Item:
+	// Members currently assigned the Item (ordered on slot), then eligible
+	// Members (ordered on ascending cost and load), then ineligible Members.
+	Members []MemberPlacement
+	// Reason summarizes the placement of the Item, and if it's under-replicated,
+	// why that is.
+	Reason string
+}
+
+// MemberPlacement explains the placement of an Item with respect to a Member.
+type MemberPlacement struct {
+	Zone, Suffix string
+	// Slot of the Member's Assignment of the Item, or -1 if it's not assigned.
+	Slot int
+	// Cost of the Item to the Member under the CostFunc (or zero if none).
+	Cost int
+	// Load is the number of Assignments of the Member, and Limit its ItemLimit.
+	Load, Limit int
+	// Eligible is true if the Member is not assigned the Item, and may take
+	// a new Assignment of it.
+	Eligible bool
+	// Reason the Member is (or isn't) a placement of the Item.
+	Reason string
+}
+
+// ExplainPlacement explains the placement of the Item identified by |itemID|:
+// which Members are assigned the Item, which are eligible to be, and which
+// are eliminated (and why), as well as why an under-replicated Item isn't
+// fully assigned. Candidate costs are recomputed from the optional CostFunc,
+// which should be the one used by Allocate.
+//
+// The explanation mirrors the preferences of the allocator's solver, which
+// retains current Assignments, then prefers Members of lower cost and load,
+// and spreads replicas across zones: where Members span multiple zones, a zone
+// holds at most R-1 replicas of an Item of desired replication R > 1. The solver may
+// relax the zone constraint when it can't otherwise replicate the Item, but
+// never relaxes the spread constraint of an Item (see SpreadItemValue), and
+// the explanation is therefore a guide rather than a proof of the solution.
+//
+// ExplainPlacement read-locks the KeySpace. It must not be called while the
+// KeySpace is locked, such as from a KeySpace Observer.
+func (s *State) ExplainPlacement(itemID string, cost CostFunc) PlacementExplanation {
+	s.KS.Mu.RLock()
+	defer s.KS.Mu.RUnlock()
+
+	var out = PlacementExplanation{ItemID: itemID}
+
+	var ind, found = s.Items.Search(ItemKey(s.KS, itemID))
+	if !found {
+		out.Reason = fmt.Sprintf("item %s does not exist", itemID)
+		return out
+	}
+	var item = itemAt(s.Items, ind)
+	out.DesiredReplication = s.desiredReplication(item)
+
+	// Index current Assignments of the Item on Member key,
+	// and count Assignments of each zone.
+	// If the Item has a spread attribute, also collect the zone-scoped
+	// attribute values of current Assignments.
+	var assignments = s.Assignments.Prefixed(ItemAssignmentsPrefix(s.KS, itemID))
+	var byMember = make(map[string]Assignment, len(assignments))
+	var zoneCount = make(map[string]int)
+	var spread = spreadAttribute(item)
+	var spreadTaken = make(map[[2]string]bool)
+
+	for _, kv := range assignments {
+		var a = kv.Decoded.(Assignment)
+		var key = MemberKey(s.KS, a.MemberZone, a.MemberSuffix)
+		byMember[key] = a
+		zoneCount[a.MemberZone]++
+
+		if ind, found := s.Members.Search(key); found && spread != "" {
+			spreadTaken[[2]string{a.MemberZone, memberAttribute(memberAt(s.Members, ind), spread)}] = true
+		}
+	}
+	out.Assigned = len(assignments)
+
+	// Replicas per zone are bounded only if multiple zones have slots.
+	var zoneBound = out.DesiredReplication
+	if len(s.Zones) > 1 && zoneBound > 1 {
+		zoneBound--
+	}
+
+	var (
+		assigned, eligible, ineligible []MemberPlacement
+		zoneLimited, spreadLimited     int
+	)
+	for i := range s.Members {
+		var member = memberAt(s.Members, i)
+		var mp = MemberPlacement{
+			Zone:   member.Zone,
+			Suffix: member.Suffix,
+			Slot:   -1,
+			Load:   s.MemberTotalCount[i],
+			Limit:  s.memberLimits[i],
+		}
+		if cost != nil {
+			mp.Cost = cost(s, member, item)
+		}
+
+		var a, ok = byMember[string(s.Members[i].Raw.Key)]
+		delete(byMember, string(s.Members[i].Raw.Key))
+
+		switch {
+		case ok && a.Slot == 0:
+			mp.Slot, mp.Reason = a.Slot, fmt.Sprintf("assigned as primary with cost %d and load %d of %d",
+				mp.Cost, mp.Load, mp.Limit)
+		case ok:
+			mp.Slot, mp.Reason = a.Slot, fmt.Sprintf("assigned as replica (slot %d) with cost %d and load %d of %d",
+				a.Slot, mp.Cost, mp.Load, mp.Limit)
+		case s.isEvacuated(member.Zone):
+			mp.Reason = fmt.Sprintf("zone %s is evacuated", member.Zone)
+		case mp.Limit == 0:
+			mp.Reason = "member has no item slots"
+		case mp.Load >= mp.Limit:
+			mp.Reason = fmt.Sprintf("member is at capacity (%d of %d slots)", mp.Load, mp.Limit)
+		case spread != "" && memberAttribute(member, spread) == "":
+			mp.Reason = fmt.Sprintf("member lacks spread attribute %s", spread)
+		case spread != "" && spreadTaken[[2]string{member.Zone, memberAttribute(member, spread)}]:
+			mp.Reason = fmt.Sprintf("%s %s of zone %s already holds a replica",
+				spread, memberAttribute(member, spread), member.Zone)
+			spreadLimited++
+		case zoneCount[member.Zone] >= zoneBound:
+			mp.Reason = fmt.Sprintf("zone %s already holds %d of %d replicas",
+				member.Zone, zoneCount[member.Zone], out.DesiredReplication)
+			zoneLimited++
+		default:
+			mp.Eligible = true
+			mp.Reason = fmt.Sprintf("eligible with cost %d and load %d of %d", mp.Cost, mp.Load, mp.Limit)
+		}
+
+		if mp.Slot != -1 {
+			assigned = append(assigned, mp)
+		} else if mp.Eligible {
+			eligible = append(eligible, mp)
+		} else {
+			ineligible = append(ineligible, mp)
+		}
+	}
+	// Remaining Assignments are of Members which don't exist.
+	for _, a := range byMember {
+		assigned = append(assigned, MemberPlacement{
+			Zone:   a.MemberZone,
+			Suffix: a.MemberSuffix,
+			Slot:   a.Slot,
+			Reason: "assigned, but the member does not exist",
+		})
+	}
+
+	sort.Slice(assigned, func(i, j int) bool { return assigned[i].Slot < assigned[j].Slot })
+	// Stable sort, so that equal costs and loads retain Member order.
+	sort.SliceStable(eligible, func(i, j int) bool {
+		if eligible[i].Cost != eligible[j].Cost {
+			return eligible[i].Cost < eligible[j].Cost
+		}
+		return eligible[i].Load*eligible[j].Limit < eligible[j].Load*eligible[i].Limit
+	})
+	out.Members = append(append(append(out.Members, assigned...), eligible...), ineligible...)
+
+	switch {
+	case out.DesiredReplication == 0:
+		out.Reason = "item has no desired replication"
+	case out.Assigned > out.DesiredReplication:
+		out.Reason = fmt.Sprintf("item is over-replicated (%d of %d desired); excess assignments are pending removal",
+			out.Assigned, out.DesiredReplication)
+	case out.Assigned == out.DesiredReplication:
+		out.Reason = fmt.Sprintf("item is fully replicated (%d of %d desired)", out.Assigned, out.DesiredReplication)
+	case s.MemberSlots == 0:
+		out.Reason = "item is unplaceable: no members have item slots"
+	case spread != "" && spreadValues(s, spread) < out.DesiredReplication:
+		out.Reason = fmt.Sprintf("item is under-replicated (%d of %d desired): members have only %d "+
+			"distinct values of spread attribute %s", out.Assigned, out.DesiredReplication,
+			spreadValues(s, spread), spread)
+	case len(eligible) == 0 && spreadLimited != 0:
+		out.Reason = fmt.Sprintf("item is under-replicated (%d of %d desired): members with capacity "+
+			"share %s values with current replicas", out.Assigned, out.DesiredReplication, spread)
+	case len(eligible) == 0 && zoneLimited != 0:
+		out.Reason = fmt.Sprintf("item is under-replicated (%d of %d desired): members with capacity "+
+			"are in zones which hold their share of replicas", out.Assigned, out.DesiredReplication)
+	case len(eligible) == 0 || s.ItemSlots > s.MemberSlots:
+		out.Reason = fmt.Sprintf("item is under-replicated (%d of %d desired): member capacity is exhausted "+
+			"(%d item slots desired, %d member slots)", out.Assigned, out.DesiredReplication, s.ItemSlots, s.MemberSlots)
+	default:
+		out.Reason = fmt.Sprintf("item is under-replicated (%d of %d desired): %d eligible members, "+
+			"pending allocation", out.Assigned, out.DesiredReplication, len(eligible))
+	}
+	return out
+}
+
+// String formats the PlacementExplanation as a human-readable, multi-line report.
+func (e PlacementExplanation) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "item %s: %s\n", e.ItemID, e.Reason)
+
+	for _, m := range e.Members {
+		fmt.Fprintf(&b, "  %s/%s: %s\n", m.Zone, m.Suffix, m.Reason)
+	}
+	return b.String()
+}
@@ -0,0 +1,93 @@
+package allocator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestExplainPlacement(t *testing.T) {
+	var ctx, client, ks = testSetup(t)
+
+	require.NoError(t, insert(ctx, client,
+		"/root/items/item-0", `{"R": 0}`,
+		"/root/items/item-1", `{"R": 2}`,
+		"/root/items/item-2", `{"R": 3}`,
+
+		"/root/members/zone-a#A", `{"R": 2}`,
+		"/root/members/zone-a#B", `{"R": 1}`,
+		"/root/members/zone-b#C", `{"R": 0}`,
+		"/root/members/zone-b#D", `{"R": 1}`,
+
+		"/root/assign/item-1#zone-a#A#0", `consistent`,
+		"/root/assign/item-1#zone-b#D#1", `consistent`,
+		"/root/assign/item-2#zone-a#B#0", `consistent`,
+		"/root/assign/item-2#zone-c#gone#1", `consistent`,
+	))
+	var state = NewObservedState(ks, "/root/members/zone-a#A", isConsistent)
+	require.NoError(t, ks.Load(ctx, client, 0))
+
+	var cost = func(_ *State, m Member, _ Item) int {
+		return map[string]int{"A": 3, "B": 2, "C": 1, "D": 0}[m.Suffix]
+	}
+
+	// Case: a fully-replicated Item.
+	var e = state.ExplainPlacement("item-1", cost)
+	require.Equal(t, "item is fully replicated (2 of 2 desired)", e.Reason)
+	require.Equal(t, []MemberPlacement{
+		{Zone: "zone-a", Suffix: "A", Slot: 0, Cost: 3, Load: 1, Limit: 2,
+			Reason: "assigned as primary with cost 3 and load 1 of 2"},
+		{Zone: "zone-b", Suffix: "D", Slot: 1, Cost: 0, Load: 1, Limit: 1,
+			Reason: "assigned as replica (slot 1) with cost 0 and load 1 of 1"},
+		{Zone: "zone-a", Suffix: "B", Slot: -1, Cost: 2, Load: 1, Limit: 1,
+			Reason: "member is at capacity (1 of 1 slots)"},
+		{Zone: "zone-b", Suffix: "C", Slot: -1, Cost: 1, Load: 0, Limit: 0,
+			Reason: "member has no item slots"},
+	}, e.Members)
+
+	// Case: an under-replicated Item, with an Assignment of a missing Member.
+	e = state.ExplainPlacement("item-2", nil)
+	require.Equal(t, "item is under-replicated (2 of 3 desired): member capacity is exhausted "+
+		"(5 item slots desired, 4 member slots)", e.Reason)
+	require.Equal(t, MemberPlacement{Zone: "zone-c", Suffix: "gone", Slot: 1,
+		Reason: "assigned, but the member does not exist"}, e.Members[1])
+	require.Equal(t, MemberPlacement{Zone: "zone-a", Suffix: "A", Slot: -1, Load: 1, Limit: 2,
+		Eligible: true, Reason: "eligible with cost 0 and load 1 of 2"}, e.Members[2])
+
+	require.Equal(t, strings.Join([]string{
+		"item item-2: " + e.Reason,
+		"  zone-a/B: assigned as primary with cost 0 and load 1 of 1",
+		"  zone-c/gone: assigned, but the member does not exist",
+		"  zone-a/A: eligible with cost 0 and load 1 of 2",
+		"  zone-b/C: member has no item slots",
+		"  zone-b/D: member is at capacity (1 of 1 slots)",
+		"",
+	}, "\n"), e.String())
+
+	// Case: Items which are missing, or have no desired replication.
+	require.Equal(t, "item item-9 does not exist", state.ExplainPlacement("item-9", nil).Reason)
+	require.Equal(t, "item has no desired replication", state.ExplainPlacement("item-0", nil).Reason)
+
+	// Case: Members with capacity are eliminated by the zone constraint.
+	require.NoError(t, update(ctx, client, "/root/members/zone-a#A", `{"R": 3}`))
+	require.NoError(t, insert(ctx, client,
+		"/root/members/zone-a#E", `{"R": 5}`,
+		"/root/items/item-3", `{"R": 2}`,
+		"/root/assign/item-3#zone-a#E#0", `consistent`,
+	))
+	require.NoError(t, ks.Load(ctx, client, 0))
+
+	e = state.ExplainPlacement("item-3", nil)
+	require.Equal(t, "item is under-replicated (1 of 2 desired): members with capacity "+
+		"are in zones which hold their share of replicas", e.Reason)
+	require.Equal(t, "zone zone-a already holds 1 of 2 replicas", e.Members[1].Reason)
+
+	// Case: an Item is unplaceable, as no Members have slots.
+	for _, key := range []string{"zone-a#A", "zone-a#B", "zone-b#D", "zone-a#E"} {
+		require.NoError(t, update(ctx, client, "/root/members/"+key, `{"R": 0}`))
+	}
+	require.NoError(t, ks.Load(ctx, client, 0))
+	require.Equal(t, "item is unplaceable: no members have item slots",
+		state.ExplainPlacement("item-3", nil).Reason)
+}
@@ -0,0 +1,93 @@
+package allocator
+
+import (
+	"sort"
+
+	"go.gazette.dev/core/keyspace"
+)
+
+// WeightedItemValue is an optional interface of an ItemValue which declares
+// the weight of its Item under AllocateArgs.FairShare. Items which don't
+// implement WeightedItemValue, or which have a Weight less than one, have
+// a weight of one.
+type WeightedItemValue interface {
+	// Weight of this Item, relative to the weights of other Items.
+	Weight() int
+}
+
+// itemWeight returns the fair-share weight of the Item.
+func itemWeight(item Item) int {
+	if w, ok := item.ItemValue.(WeightedItemValue); ok && w.Weight() > 1 {
+		return w.Weight()
+	}
+	return 1
+}
+
+// fairShares returns the replication of each of |items| under a weighted
+// fair sharing of |slots| Member slots, where the desired replication of
+// each Item is given by |replication|. If |slots| are sufficient to fully
+// replicate every Item, then each share is its Item's desired replication.
+//
+// Otherwise, slots are allocated in proportion to Item weights, where an
+// Item is never allocated more than its DesiredReplication and the excess of
+// such Items is re-allocated proportionally across the remaining Items
+// (a weighted max-min fair allocation). Proportional shares are rounded down,
+// and the slots lost to rounding are given one apiece to the Items having the
+// largest rounded remainders, with ties broken by Item order. Shares are thus
+// deterministic and sum to exactly |slots|.
+func fairShares(items keyspace.KeyValues, slots int, replication func(Item) int) []int {
+	var (
+		shares  = make([]int, len(items))
+		weights = make([]int, len(items))
+		order   = make([]int, len(items))
+		total   int
+		weight  int
+	)
+	for i := range items {
+		var item = itemAt(items, i)
+		shares[i], weights[i], order[i] = replication(item), itemWeight(item), i
+		total, weight = total+shares[i], weight+weights[i]
+	}
+	if total <= slots {
+		return shares
+	}
+
+	// Order Items on ascending replication per unit of weight, which is the
+	// order in which Items are saturated as the proportional share grows.
+	sort.SliceStable(order, func(i, j int) bool {
+		var l, r = order[i], order[j]
+		return shares[l]*weights[r] < shares[r]*weights[l]
+	})
+	// Saturate Items whose DesiredReplication is within their proportional
+	// share of the remaining slots. As |total| exceeds |slots|, at least one
+	// Item remains unsaturated.
+	var n int
+	for ; n != len(order); n++ {
+		var i = order[n]
+		if shares[i]*weight > slots*weights[i] {
+			break
+		}
+		slots, weight = slots-shares[i], weight-weights[i]
+	}
+	var rest = order[n:]
+	var remainders = make([]int, len(items))
+	var lost = slots
+
+	for _, i := range rest {
+		shares[i], remainders[i] = slots*weights[i]/weight, slots*weights[i]%weight
+		lost -= shares[i]
+	}
+	// Distribute slots lost to rounding. Each unsaturated share was strictly
+	// less than its DesiredReplication, so a share is never rounded above it.
+	sort.SliceStable(rest, func(i, j int) bool {
+		var l, r = rest[i], rest[j]
+		if remainders[l] != remainders[r] {
+			return remainders[l] > remainders[r]
+		}
+		return l < r
+	})
+	for _, i := range rest[:lost] {
+		shares[i]++
+	}
+	return shares
+}
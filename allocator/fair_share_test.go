@@ -0,0 +1,114 @@
+package allocator
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.gazette.dev/core/keyspace"
+)
+
+func TestFairShareRounding(t *testing.T) {
+	// Builds Items having the given (DesiredReplication, Weight) pairs.
+	var items = func(rw ...int) keyspace.KeyValues {
+		var out keyspace.KeyValues
+		for i := 0; i != len(rw); i += 2 {
+			out = append(out, keyspace.KeyValue{Decoded: Item{
+				ID:        fmt.Sprintf("item-%d", i/2),
+				ItemValue: testItem{R: rw[i], W: rw[i+1]},
+			}})
+		}
+		return out
+	}
+	var cases = []struct {
+		items  keyspace.KeyValues
+		slots  int
+		expect []int
+	}{
+		// Sufficient slots: each Item is fully replicated.
+		{items(3, 1, 2, 5), 5, []int{3, 2}},
+		{items(3, 1, 2, 5), 9, []int{3, 2}},
+		// Equal weights share equally, and the remainder goes to the first Item.
+		{items(3, 1, 3, 1, 3, 1), 7, []int{3, 2, 2}},
+		{items(3, 1, 3, 1, 3, 1), 4, []int{2, 1, 1}},
+		// Shares are proportional to weights.
+		{items(9, 1, 9, 2), 6, []int{2, 4}},
+		// Shares are rounded by largest remainder: 5/6, 10/6, 15/6.
+		{items(9, 1, 9, 2, 9, 3), 5, []int{1, 2, 2}},
+		// An Item is allocated no more than its DesiredReplication, and its
+		// excess is re-allocated to other Items.
+		{items(1, 4, 9, 1, 9, 1), 7, []int{1, 3, 3}},
+		{items(2, 3, 9, 1, 3, 2), 8, []int{2, 3, 3}},
+		// Weights less than one are treated as one.
+		{items(3, 0, 3, -1), 2, []int{1, 1}},
+		// Zero slots.
+		{items(3, 1, 3, 1), 0, []int{0, 0}},
+	}
+	for _, tc := range cases {
+		var shares = fairShares(tc.items, tc.slots, Item.DesiredReplication)
+		require.Equal(t, tc.expect, shares)
+
+		// Expect shares sum to |slots|, and don't exceed DesiredReplication.
+		var total, desired int
+		for i, s := range shares {
+			require.LessOrEqual(t, s, itemAt(tc.items, i).DesiredReplication())
+			total, desired = total+s, desired+itemAt(tc.items, i).DesiredReplication()
+		}
+		if desired > tc.slots {
+			require.Equal(t, tc.slots, total)
+		}
+	}
+}
+
+func TestFairShareVersusKeyOrderUnderScarcity(t *testing.T) {
+	var ctx, client, ks = testSetup(t)
+
+	// Six Member slots are too few to replicate nine Item slots. Items of
+	// tenant "a" order before (and would starve) tenant "b".
+	require.NoError(t, insert(ctx, client,
+		"/root/items/a-1", `{"R": 3, "W": 1}`,
+		"/root/items/a-2", `{"R": 3, "W": 1}`,
+		"/root/items/b-1", `{"R": 3, "W": 2}`,
+
+		"/root/members/zone-a#member-1", `{"R": 2}`,
+		"/root/members/zone-a#member-2", `{"R": 2}`,
+		"/root/members/zone-a#member-3", `{"R": 2}`,
+	))
+	// Returns the number of Assignments of each Item.
+	var itemCounts = func() map[string]int {
+		var out = make(map[string]int)
+		for _, kv := range ks.Prefixed(ks.Root + AssignmentsPrefix) {
+			out[kv.Decoded.(Assignment).ItemID]++
+		}
+		return out
+	}
+
+	// Without FairShare, slots go to Items in key order and b-1 is starved.
+	require.NotZero(t, serveUntilIdle(t, ctx, client, ks, ""))
+	require.Equal(t, map[string]int{"a-1": 3, "a-2": 3}, itemCounts())
+
+	// With FairShare, b-1 is allocated half of the slots by its weight, and
+	// a-1 and a-2 share the remaining three (with a-1 taking the odd slot).
+	var args = AllocateArgs{FairShare: true}
+	require.NotZero(t, serveUntilIdleWithArgs(t, ctx, client, ks, "", args))
+	require.Equal(t, map[string]int{"a-1": 2, "a-2": 1, "b-1": 3}, itemCounts())
+
+	// Shrink to two Member slots, such that a-2 rounds to a zero share.
+	// Expect it nonetheless retains its one consistent Assignment.
+	require.NoError(t, update(ctx, client,
+		"/root/members/zone-a#member-1", `{"R": 1}`,
+		"/root/members/zone-a#member-2", `{"R": 1}`,
+		"/root/members/zone-a#member-3", `{"R": 0}`,
+	))
+	require.NotZero(t, serveUntilIdleWithArgs(t, ctx, client, ks, "", args))
+	require.Equal(t, 1, itemCounts()["a-2"])
+
+	// Expect FairShare has no effect once slots are sufficient.
+	require.NoError(t, update(ctx, client,
+		"/root/members/zone-a#member-1", `{"R": 3}`,
+		"/root/members/zone-a#member-2", `{"R": 3}`,
+		"/root/members/zone-a#member-3", `{"R": 3}`,
+	))
+	require.NotZero(t, serveUntilIdleWithArgs(t, ctx, client, ks, "", args))
+	require.Equal(t, map[string]int{"a-1": 3, "a-2": 3, "b-1": 3}, itemCounts())
+}
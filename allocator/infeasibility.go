@@ -0,0 +1,182 @@
+package allocator
+
+import (
+	"fmt"
+)
+
+// Constraints which may bind an Infeasibility.
+const (
+	// InfeasibleMembers is an Item which can't be fully replicated because
+	// fewer Members have item slots than its desired replication.
+	InfeasibleMembers = "members"
+	// InfeasibleSpread is an Item having a spread attribute (see
+	// SpreadItemValue) for which too few Members have distinct values.
+	InfeasibleSpread = "spread"
+	// InfeasibleCapacity is an Item which can't be fully replicated because
+	// the Members eligible to hold it are at capacity.
+	InfeasibleCapacity = "capacity"
+	// InfeasibleZoneDiversity is an Item which is fully replicated, but only
+	// within a single zone though Members span multiple zones, because
+	// Members of other zones lack capacity.
+	InfeasibleZoneDiversity = "zone-diversity"
+)
+
+// Infeasibility is an Item whose desired replication or zone diversity
+// can't be satisfied by the current Members, as determined by a solve for
+// a maximum assignment.
+type Infeasibility struct {
+	ItemID string
+	// Constraint which binds the Item, such as InfeasibleCapacity.
+	Constraint string
+	// DesiredReplication of the Item, and the number of its replicas
+	// which are attainable under the constraint.
+	DesiredReplication, Attainable int
+	// Reason describes the binding constraint.
+	Reason string
+	// Suggestion is a change of Members which would relieve the constraint.
+	Suggestion string
+}
+
+// Infeasibilities solves for a maximum assignment of the current State, and
+// returns each Item which the solve couldn't fully replicate across zones,
+// with the constraint which binds it and a suggested remedy. The solve is
+// made without a CostFunc or headroom, and doesn't reflect a fair-share
+// policy. Infeasibilities read-locks the KeySpace. It must not be called
+// while the KeySpace is locked, such as from a KeySpace Observer.
+func (s *State) Infeasibilities() []Infeasibility {
+	s.KS.Mu.RLock()
+	defer s.KS.Mu.RUnlock()
+
+	// A solve errors only if its CostFunc does, and this one has none.
+	var desired, _ = solveDesiredAssignments(s, nil, solveArgs{})
+	var assignmentsOf = desiredAssignmentsOf(desired)
+	var out []Infeasibility
+
+	for i := range s.Items {
+		var item = itemAt(s.Items, i)
+		if inf, ok := infeasibilityOf(s, item, assignmentsOf(item.ID)); ok {
+			out = append(out, inf)
+		}
+	}
+	return out
+}
+
+// infeasibilityOf returns the Infeasibility of the Item given its solved
+// |assignments|, or false if the Item is feasible.
+func infeasibilityOf(s *State, item Item, assignments []Assignment) (Infeasibility, bool) {
+	var out = Infeasibility{
+		ItemID:             item.ID,
+		DesiredReplication: s.desiredReplication(item),
+		Attainable:         len(assignments),
+	}
+	var r, attr = out.DesiredReplication, spreadAttribute(item)
+
+	switch {
+	case r == 0:
+		return out, false
+
+	case slottedMembers(s) < r:
+		var n = slottedMembers(s)
+		out.Constraint = InfeasibleMembers
+		out.Reason = fmt.Sprintf("%d members have item slots, of %d desired replicas", n, r)
+
+		if r-n == 1 {
+			out.Suggestion = "add a member having item slots"
+		} else {
+			out.Suggestion = fmt.Sprintf("add %d members having item slots", r-n)
+		}
+
+	case attr != "" && spreadValues(s, attr) < r:
+		var n, zone = spreadValues(s, attr), leastSpreadZone(s, attr)
+		out.Constraint = InfeasibleSpread
+		out.Reason = fmt.Sprintf("members have %d distinct values of spread attribute %s, of %d desired replicas",
+			n, attr, r)
+
+		if r-n == 1 {
+			out.Suggestion = fmt.Sprintf("add a member in zone %s with a new value of attribute %s", zone, attr)
+		} else {
+			out.Suggestion = fmt.Sprintf("add %d members in zone %s with distinct new values of attribute %s",
+				r-n, zone, attr)
+		}
+
+	case out.Attainable < r && attr != "":
+		out.Constraint = InfeasibleCapacity
+		out.Reason = fmt.Sprintf("members having spread attribute %s are at capacity", attr)
+		out.Suggestion = fmt.Sprintf("increase item limits of members having attribute %s by %d",
+			attr, r-out.Attainable)
+
+	case out.Attainable < r:
+		out.Constraint = InfeasibleCapacity
+		out.Reason = fmt.Sprintf("member capacity is exhausted (%d item slots desired, %d member slots)",
+			s.ItemSlots, s.MemberSlots)
+		out.Suggestion = fmt.Sprintf("increase item limits of members by %d", r-out.Attainable)
+
+	case r > 1 && len(s.Zones) > 1 && singleZone(assignments):
+		var zone = assignments[0].MemberZone
+		out.Constraint = InfeasibleZoneDiversity
+		out.Reason = fmt.Sprintf("all replicas are in zone %s, as members of other zones are at capacity", zone)
+		out.Suggestion = fmt.Sprintf("add item slots in a zone other than %s, such as %s",
+			zone, otherZone(s, zone))
+
+	default:
+		return out, false
+	}
+	return out, true
+}
+
+// singleZone returns true if |assignments| are all of one zone.
+func singleZone(assignments []Assignment) bool {
+	for _, a := range assignments {
+		if a.MemberZone != assignments[0].MemberZone {
+			return false
+		}
+	}
+	return true
+}
+
+// slottedMembers returns the number of State Members having item slots.
+func slottedMembers(s *State) int {
+	var n int
+	for _, limit := range s.memberLimits {
+		if limit != 0 {
+			n++
+		}
+	}
+	return n
+}
+
+// otherZone returns the zone of State Zones other than |zone| which has
+// the most item slots.
+func otherZone(s *State, zone string) string {
+	var out, slots = "", -1
+	for i, z := range s.Zones {
+		if z != zone && s.ZoneSlots[i] > slots {
+			out, slots = z, s.ZoneSlots[i]
+		}
+	}
+	return out
+}
+
+// leastSpreadZone returns the zone of State Zones having the fewest distinct
+// values of attribute |attr|, where a new value most improves diversity.
+func leastSpreadZone(s *State, attr string) string {
+	var values = make(map[string]map[string]struct{})
+
+	for i := range s.Members {
+		var m = memberAt(s.Members, i)
+
+		if v := memberAttribute(m, attr); v != "" && s.memberLimits[i] != 0 {
+			if values[m.Zone] == nil {
+				values[m.Zone] = make(map[string]struct{})
+			}
+			values[m.Zone][v] = struct{}{}
+		}
+	}
+	var out, n = "", -1
+	for _, z := range s.Zones {
+		if n == -1 || len(values[z]) < n {
+			out, n = z, len(values[z])
+		}
+	}
+	return out
+}
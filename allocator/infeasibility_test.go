@@ -0,0 +1,133 @@
+package allocator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestInfeasibilities(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		keyValues []string
+		expect    []Infeasibility
+	}{
+		{
+			name: "feasible",
+			keyValues: []string{
+				"/root/items/item-1", `{"R": 2}`,
+				"/root/items/item-2", `{"R": 0}`,
+				"/root/members/zone-a#A", `{"R": 1}`,
+				"/root/members/zone-b#B", `{"R": 1}`,
+			},
+		},
+		{
+			name: "no members",
+			keyValues: []string{
+				"/root/items/item-1", `{"R": 1}`,
+				"/root/members/zone-a#A", `{"R": 0}`,
+			},
+			expect: []Infeasibility{{
+				ItemID:             "item-1",
+				Constraint:         InfeasibleMembers,
+				DesiredReplication: 1,
+				Reason:             "0 members have item slots, of 1 desired replicas",
+				Suggestion:         "add a member having item slots",
+			}},
+		},
+		{
+			name: "too few members",
+			keyValues: []string{
+				"/root/items/item-1", `{"R": 4}`,
+				"/root/members/zone-a#A", `{"R": 5}`,
+				"/root/members/zone-b#B", `{"R": 5}`,
+			},
+			expect: []Infeasibility{{
+				ItemID:             "item-1",
+				Constraint:         InfeasibleMembers,
+				DesiredReplication: 4,
+				Attainable:         2,
+				Reason:             "2 members have item slots, of 4 desired replicas",
+				Suggestion:         "add 2 members having item slots",
+			}},
+		},
+		{
+			name: "spread",
+			keyValues: []string{
+				"/root/items/item-1", `{"R": 2, "S": "rack"}`,
+				"/root/members/zone-a#A", `{"R": 2, "A": {"rack": "r1"}}`,
+				"/root/members/zone-a#B", `{"R": 2, "A": {"rack": "r1"}}`,
+				"/root/members/zone-b#C", `{"R": 2}`,
+			},
+			expect: []Infeasibility{{
+				ItemID:             "item-1",
+				Constraint:         InfeasibleSpread,
+				DesiredReplication: 2,
+				Attainable:         1,
+				Reason:             "members have 1 distinct values of spread attribute rack, of 2 desired replicas",
+				Suggestion:         "add a member in zone zone-b with a new value of attribute rack",
+			}},
+		},
+		{
+			name: "spread capacity",
+			keyValues: []string{
+				"/root/items/item-1", `{"R": 2, "S": "rack"}`,
+				"/root/items/item-2", `{"R": 2, "S": "rack"}`,
+				"/root/members/zone-a#A", `{"R": 1, "A": {"rack": "r1"}}`,
+				"/root/members/zone-b#B", `{"R": 2, "A": {"rack": "r2"}}`,
+				"/root/members/zone-b#C", `{"R": 5}`,
+			},
+			expect: []Infeasibility{{
+				ItemID:             "item-2",
+				Constraint:         InfeasibleCapacity,
+				DesiredReplication: 2,
+				Attainable:         1,
+				Reason:             "members having spread attribute rack are at capacity",
+				Suggestion:         "increase item limits of members having attribute rack by 1",
+			}},
+		},
+		{
+			name: "capacity",
+			keyValues: []string{
+				"/root/items/item-1", `{"R": 2}`,
+				"/root/items/item-2", `{"R": 1}`,
+				"/root/members/zone-a#A", `{"R": 1}`,
+				"/root/members/zone-b#B", `{"R": 1}`,
+			},
+			expect: []Infeasibility{{
+				ItemID:             "item-2",
+				Constraint:         InfeasibleCapacity,
+				DesiredReplication: 1,
+				Reason:             "member capacity is exhausted (3 item slots desired, 2 member slots)",
+				Suggestion:         "increase item limits of members by 1",
+			}},
+		},
+		{
+			name: "zone diversity",
+			keyValues: []string{
+				"/root/items/item-1", `{"R": 2}`,
+				"/root/items/item-2", `{"R": 2}`,
+				"/root/members/zone-a#A", `{"R": 2}`,
+				"/root/members/zone-a#B", `{"R": 2}`,
+				"/root/members/zone-b#C", `{"R": 1}`,
+			},
+			expect: []Infeasibility{{
+				ItemID:             "item-1",
+				Constraint:         InfeasibleZoneDiversity,
+				DesiredReplication: 2,
+				Attainable:         2,
+				Reason:             "all replicas are in zone zone-a, as members of other zones are at capacity",
+				Suggestion:         "add item slots in a zone other than zone-a, such as zone-b",
+			}},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var ctx, client, ks = testSetup(t)
+			require.NoError(t, insert(ctx, client, tc.keyValues...))
+
+			var state = NewObservedState(ks, "/root/members/zone-a#A", isConsistent)
+			require.NoError(t, ks.Load(ctx, client, 0))
+			require.Equal(t, tc.expect, state.Infeasibilities())
+		})
+	}
+}
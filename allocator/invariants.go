@@ -0,0 +1,241 @@
+package allocator
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	"go.gazette.dev/core/keyspace"
+)
+
+// Invariants of the allocator State, which may be violated by an
+// InvariantViolation.
+const (
+	// InvariantMemberOverLimit is a Member having more Assignments than its
+	// ItemLimit. Assignments which precede the Member's last update (or the
+	// evacuation of its zone) aren't counted, as the allocator removes them
+	// only as their Items are re-assigned to other Members.
+	InvariantMemberOverLimit = "member-over-limit"
+	// InvariantItemOverReplicated is an Item having more consistent
+	// Assignments than its desired replication.
+	InvariantItemOverReplicated = "item-over-replicated"
+	// InvariantUnknownMember is an Assignment of a Member which doesn't exist,
+	// where the Item would remain fully replicated without the Assignment.
+	InvariantUnknownMember = "unknown-member"
+	// InvariantUnknownItem is an Assignment of an Item which doesn't exist.
+	InvariantUnknownItem = "unknown-item"
+)
+
+// InvariantViolation is a violation of an invariant of the allocator State.
+type InvariantViolation struct {
+	// Invariant which is violated, such as InvariantMemberOverLimit.
+	Invariant string
+	// ItemID of the violation, or empty if it's not specific to an Item.
+	ItemID string
+	// MemberZone and MemberSuffix of the violation, or empty if it's not
+	// specific to a Member.
+	MemberZone, MemberSuffix string
+	// Reason describes the violation.
+	Reason string
+}
+
+func (v InvariantViolation) String() string {
+	return fmt.Sprintf("%s (item %q, member %s#%s): %s",
+		v.Invariant, v.ItemID, v.MemberZone, v.MemberSuffix, v.Reason)
+}
+
+// key identifies the violation across checks, independent of its Reason.
+func (v InvariantViolation) key() string {
+	return v.Invariant + "#" + v.ItemID + "#" + v.MemberZone + "#" + v.MemberSuffix
+}
+
+// InvariantChecker checks invariants of the State after each convergence
+// round of the allocator leader, catching bugs of the allocator or external
+// changes of its Etcd keys which leave the State invalid. Pass it as
+// AllocateArgs.Invariants.
+//
+// Changes of a round may be applied across multiple Etcd transactions, and
+// invariants may be transiently violated between them. A violation is
+// therefore flagged only if it persists across the commit of a round: it was
+// observed at the start of one round, and is still observed at the start of
+// the next. Each flagged violation is logged, counted by metric
+// gazette_allocator_invariant_violations_total, and passed to OnViolation.
+// A violation is flagged once, and again only if it's resolved and then recurs.
+type InvariantChecker struct {
+	// OnViolation is an optional callback, invoked with flagged violations.
+	// It's called while the KeySpace is read-locked, and must not block.
+	OnViolation func([]InvariantViolation)
+	// Halt causes Allocate to return an error upon a flagged violation,
+	// rather than proceeding to converge the violating State.
+	Halt bool
+
+	round    int                 // Round of the last check.
+	observed map[string]struct{} // Violations observed by the last check.
+	flagged  map[string]struct{} // Violations which have been flagged.
+}
+
+// check the invariants of State at the start of |round|, and flag violations
+// which persist from a check of a prior round. The KeySpace must be read-locked.
+func (c *InvariantChecker) check(s *State, round int) error {
+	var violations = checkInvariants(s)
+	var observed = make(map[string]struct{}, len(violations))
+	var flagged = make(map[string]struct{}, len(violations))
+	var out []InvariantViolation
+
+	for _, v := range violations {
+		var key = v.key()
+		observed[key] = struct{}{}
+
+		if _, ok := c.observed[key]; !ok || round == c.round {
+			continue // Not yet observed across a commit boundary.
+		}
+		flagged[key] = struct{}{}
+
+		if _, ok := c.flagged[key]; !ok {
+			out = append(out, v) // Newly flagged.
+		}
+	}
+	// Violations remain flagged for as long as they're observed.
+	for key := range c.flagged {
+		if _, ok := observed[key]; ok {
+			flagged[key] = struct{}{}
+		}
+	}
+	c.round, c.observed, c.flagged = round, observed, flagged
+
+	if len(out) == 0 {
+		return nil
+	}
+	for _, v := range out {
+		allocatorInvariantViolationsTotal.WithLabelValues(v.Invariant).Inc()
+
+		log.WithFields(log.Fields{
+			"invariant": v.Invariant,
+			"item":      v.ItemID,
+			"zone":      v.MemberZone,
+			"suffix":    v.MemberSuffix,
+			"reason":    v.Reason,
+			"rev":       s.KS.Header.Revision,
+		}).Error("allocator invariant violated")
+	}
+	if c.OnViolation != nil {
+		c.OnViolation(out)
+	}
+	if c.Halt {
+		return fmt.Errorf("allocator invariant violated: %s", out[0])
+	}
+	return nil
+}
+
+// checkInvariants returns the current InvariantViolations of the State.
+// The KeySpace must be read-locked.
+func checkInvariants(s *State) []InvariantViolation {
+	var out []InvariantViolation
+	var since = make([]int64, len(s.Members)) // Revision of each Member's ItemLimit.
+	var counts = make([]int, len(s.Members))  // Assignments of each Member after |since|.
+
+	for i := range s.Members {
+		var m = memberAt(s.Members, i)
+		since[i] = s.Members[i].Raw.ModRevision
+
+		for j := range s.Evacuations {
+			if evacuationAt(s.Evacuations, j).Zone == m.Zone && s.Evacuations[j].Raw.ModRevision > since[i] {
+				since[i] = s.Evacuations[j].Raw.ModRevision
+			}
+		}
+	}
+
+	var it = LeftJoin{
+		LenL: len(s.Items),
+		LenR: len(s.Assignments),
+		Compare: func(l, r int) int {
+			return strings.Compare(itemAt(s.Items, l).ID, assignmentAt(s.Assignments, r).ItemID)
+		},
+	}
+	var lastCRE int // cur.RightEnd of the previous iteration.
+
+	for cur, ok := it.Next(); ok; cur, ok = it.Next() {
+		// Assignments skipped between the last cursor iteration and this one,
+		// have no associated Item.
+		out = appendUnknownItems(out, s.Assignments[lastCRE:cur.RightBegin])
+		lastCRE = cur.RightEnd
+
+		var item = itemAt(s.Items, cur.Left)
+		var current = s.Assignments[cur.RightBegin:cur.RightEnd]
+		var consistent, live int
+		var unknown []Assignment
+
+		for i := range current {
+			var a = assignmentAt(current, i)
+			var isConsistent = s.IsConsistent(item, current[i], current)
+
+			if isConsistent {
+				consistent++
+			}
+			if ind, found := s.Members.Search(MemberKey(s.KS, a.MemberZone, a.MemberSuffix)); !found {
+				unknown = append(unknown, a)
+			} else {
+				if isConsistent {
+					live++
+				}
+				if current[i].Raw.CreateRevision > since[ind] {
+					counts[ind]++
+				}
+			}
+		}
+
+		var r = s.desiredReplication(item)
+		if consistent > r {
+			out = append(out, InvariantViolation{
+				Invariant: InvariantItemOverReplicated,
+				ItemID:    item.ID,
+				Reason:    fmt.Sprintf("item has %d consistent assignments (desired replication %d)", consistent, r),
+			})
+		}
+		if live >= r {
+			for _, a := range unknown {
+				out = append(out, InvariantViolation{
+					Invariant:    InvariantUnknownMember,
+					ItemID:       a.ItemID,
+					MemberZone:   a.MemberZone,
+					MemberSuffix: a.MemberSuffix,
+					Reason:       fmt.Sprintf("member doesn't exist, and item has %d consistent assignments of live members", live),
+				})
+			}
+		}
+	}
+	out = appendUnknownItems(out, s.Assignments[lastCRE:])
+
+	for i := range s.Members {
+		if counts[i] > s.memberLimits[i] {
+			var m = memberAt(s.Members, i)
+			out = append(out, InvariantViolation{
+				Invariant:    InvariantMemberOverLimit,
+				MemberZone:   m.Zone,
+				MemberSuffix: m.Suffix,
+				Reason: fmt.Sprintf("member has %d assignments since its last update (item limit %d)",
+					counts[i], s.memberLimits[i]),
+			})
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Invariant < out[j].Invariant })
+
+	return out
+}
+
+// appendUnknownItems appends an InvariantViolation of each of the
+// Assignments |asn|, which have no associated Item.
+func appendUnknownItems(out []InvariantViolation, asn keyspace.KeyValues) []InvariantViolation {
+	for i := range asn {
+		var a = assignmentAt(asn, i)
+		out = append(out, InvariantViolation{
+			Invariant:    InvariantUnknownItem,
+			ItemID:       a.ItemID,
+			MemberZone:   a.MemberZone,
+			MemberSuffix: a.MemberSuffix,
+			Reason:       "item doesn't exist",
+		})
+	}
+	return out
+}
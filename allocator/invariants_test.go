@@ -0,0 +1,134 @@
+package allocator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+func TestCheckInvariantsCases(t *testing.T) {
+	var ctx, client, ks = testSetup(t)
+	require.NoError(t, insert(ctx, client,
+		"/root/items/item-1", `{"R": 1}`,
+		"/root/items/item-2", `{"R": 2}`,
+		"/root/items/item-3", `{"R": 1}`,
+
+		"/root/members/zone-a#A", `{"R": 1}`,
+		"/root/members/zone-b#B", `{"R": 2}`,
+
+		// Precedes the last update of member A, and isn't counted against its limit.
+		"/root/assign/item-3#zone-a#A#0", `consistent`,
+	))
+	require.NoError(t, insert(ctx, client,
+		"/root/assign/item-1#zone-a#A#0", `consistent`,
+		"/root/assign/item-1#zone-b#B#1", `consistent`,
+		"/root/assign/item-2#zone-a#A#0", `consistent`,
+		"/root/assign/item-2#zone-b#B#1", `consistent`,
+		"/root/assign/item-3#zone-b#B#1", ``,
+		"/root/assign/item-2#zone-c#C#2", `consistent`,
+		"/root/assign/item-4#zone-b#B#0", `consistent`,
+	))
+	var state = NewObservedState(ks, "/root/members/zone-a#A", isConsistent)
+	require.NoError(t, ks.Load(ctx, client, 0))
+
+	require.Equal(t, []InvariantViolation{
+		{
+			Invariant: InvariantItemOverReplicated,
+			ItemID:    "item-1",
+			Reason:    "item has 2 consistent assignments (desired replication 1)",
+		},
+		{
+			Invariant: InvariantItemOverReplicated,
+			ItemID:    "item-2",
+			Reason:    "item has 3 consistent assignments (desired replication 2)",
+		},
+		{
+			Invariant:    InvariantMemberOverLimit,
+			MemberZone:   "zone-a",
+			MemberSuffix: "A",
+			Reason:       "member has 2 assignments since its last update (item limit 1)",
+		},
+		{
+			Invariant:    InvariantMemberOverLimit,
+			MemberZone:   "zone-b",
+			MemberSuffix: "B",
+			Reason:       "member has 3 assignments since its last update (item limit 2)",
+		},
+		{
+			Invariant:    InvariantUnknownItem,
+			ItemID:       "item-4",
+			MemberZone:   "zone-b",
+			MemberSuffix: "B",
+			Reason:       "item doesn't exist",
+		},
+		{
+			Invariant:    InvariantUnknownMember,
+			ItemID:       "item-2",
+			MemberZone:   "zone-c",
+			MemberSuffix: "C",
+			Reason:       "member doesn't exist, and item has 2 consistent assignments of live members",
+		},
+	}, checkInvariants(state))
+
+	// Case: a valid State has no violations.
+	_, err := client.Delete(ctx, "/root/assign/", clientv3.WithPrefix())
+	require.NoError(t, err)
+	require.NoError(t, insert(ctx, client,
+		"/root/assign/item-1#zone-a#A#0", `consistent`,
+		"/root/assign/item-2#zone-b#B#1", `consistent`,
+		"/root/assign/item-3#zone-b#B#0", `consistent`,
+	))
+	require.NoError(t, ks.Load(ctx, client, 0))
+	require.Empty(t, checkInvariants(state))
+}
+
+func TestInvariantCheckerFlagsPersistentViolations(t *testing.T) {
+	var ctx, client, ks = testSetup(t)
+	require.NoError(t, insert(ctx, client,
+		"/root/items/item-1", `{"R": 1}`,
+		"/root/members/zone-a#A", `{"R": 1}`,
+		"/root/assign/item-1#zone-a#A#0", `consistent`,
+		"/root/assign/item-2#zone-a#A#0", `consistent`,
+	))
+	var state = NewObservedState(ks, "/root/members/zone-a#A", isConsistent)
+	require.NoError(t, ks.Load(ctx, client, 0))
+
+	var flagged []InvariantViolation
+	var checker = &InvariantChecker{
+		OnViolation: func(v []InvariantViolation) { flagged = append(flagged, v...) },
+	}
+
+	// Case: a violation isn't flagged when first observed,
+	// nor when observed again within the same round.
+	require.NoError(t, checker.check(state, 0))
+	require.NoError(t, checker.check(state, 0))
+	require.Empty(t, flagged)
+
+	// Case: it's flagged once it persists into the next round.
+	require.NoError(t, checker.check(state, 1))
+	require.Len(t, flagged, 1)
+	require.Equal(t, InvariantUnknownItem, flagged[0].Invariant)
+	require.Equal(t, "item-2", flagged[0].ItemID)
+
+	// Case: it's not flagged again while it persists.
+	require.NoError(t, checker.check(state, 2))
+	require.Len(t, flagged, 1)
+
+	// Case: it's flagged again if it's resolved, and then recurs.
+	_, err := client.Delete(ctx, "/root/assign/item-2#zone-a#A#0")
+	require.NoError(t, err)
+	require.NoError(t, ks.Load(ctx, client, 0))
+	require.NoError(t, checker.check(state, 3))
+
+	require.NoError(t, insert(ctx, client, "/root/assign/item-2#zone-a#A#0", `consistent`))
+	require.NoError(t, ks.Load(ctx, client, 0))
+	require.NoError(t, checker.check(state, 4))
+	require.Len(t, flagged, 1)
+
+	// Case: under Halt, a flagged violation is returned as an error.
+	checker.Halt = true
+	require.EqualError(t, checker.check(state, 5), "allocator invariant violated: "+
+		`unknown-item (item "item-2", member zone-a#A): item doesn't exist`)
+	require.Len(t, flagged, 2)
+}
@@ -10,7 +10,11 @@ import (
 // itemState is an extracted representation of an Item and a collection of
 // desired changes to its Assignments.
 type itemState struct {
-	global *State
+	global   *State
+	shares   []int               // Optional fair-share replication of each of |global.Items|.
+	headroom float64             // Fraction of Member ItemLimits held in reserve.
+	value    AssignmentValueFunc // Optional producer of new Assignment values.
+	moves    *int                // Optional remaining budget of moves of the round.
 
 	item    int                // Index of current Item within |global.Items|.
 	current keyspace.KeyValues // Sub-slice of Item's current Assignments within |global.Assignments|.
@@ -24,7 +28,11 @@ type itemState struct {
 // given |current| and |desired|.
 func (s *itemState) init(item int, current keyspace.KeyValues, desired []Assignment) {
 	*s = itemState{
-		global: s.global,
+		global:   s.global,
+		shares:   s.shares,
+		headroom: s.headroom,
+		value:    s.value,
+		moves:    s.moves,
 
 		item:    item,
 		current: current,
@@ -93,9 +101,18 @@ func (s *itemState) constrainRemovals() {
 	}
 	// Release Assignments in decreasing order of member load ratio. Halt if
 	// releasing an Assignment would violate the Item replication guarantee.
+	// Under fair sharing, the Item is guaranteed only its fair share, but
+	// an Item having a consistent Assignment always keeps at least one, so
+	// that one rounded to a zero share isn't left without a ready replica.
 	var limit int
+	var r = s.global.desiredReplication(item)
 
-	for r := item.DesiredReplication(); n >= r && limit != len(s.remove); limit++ {
+	if s.shares != nil && s.shares[s.item] < r {
+		if r = s.shares[s.item]; r == 0 && n != 0 {
+			r = 1
+		}
+	}
+	for ; n >= r && limit != len(s.remove); limit++ {
 		if c := s.global.IsConsistent(item, s.remove[limit], s.current); c && n == r {
 			break // We cannot remove this assignment without breaking n >= r.
 		} else if c {
@@ -152,7 +169,8 @@ func (s *itemState) constrainReorders() {
 	s.reorder[0] = primary.kv
 }
 
-// constrainAdds prunes Assignments from |s.add| which would otherwise violate constraints.
+// constrainAdds prunes Assignments from |s.add| which would otherwise violate
+// constraints, or which would exceed the remaining budget of |s.moves|.
 func (s *itemState) constrainAdds() {
 	for i := 0; i != len(s.add); {
 		var a = s.add[i]
@@ -162,7 +180,7 @@ func (s *itemState) constrainAdds() {
 			panic("member not found")
 		}
 
-		if memberAt(s.global.Members, ind).ItemLimit() <= s.global.MemberTotalCount[ind] {
+		if s.global.effectiveItemLimit(ind, s.headroom) <= s.global.MemberTotalCount[ind] {
 			// Addition would violate member's ItemLimit. Remove this Assignment.
 			copy(s.add[i:], s.add[i+1:])
 			s.add = s.add[:len(s.add)-1]
@@ -170,6 +188,30 @@ func (s *itemState) constrainAdds() {
 			i++
 		}
 	}
+	if s.moves == nil {
+		return
+	}
+
+	// Additions which replicate the Item towards its desired replication
+	// (or fair share) are repairs, and are always made. Further additions
+	// move the Item between Members, and are limited by the move budget.
+	var r = s.global.desiredReplication(itemAt(s.global.Items, s.item))
+	if s.shares != nil && s.shares[s.item] < r {
+		r = s.shares[s.item]
+	}
+	var repairs = r - len(s.current)
+	if repairs < 0 {
+		repairs = 0
+	}
+	if moves := len(s.add) - repairs; moves <= 0 {
+		// No moves.
+	} else if moves <= *s.moves {
+		*s.moves -= moves
+	} else {
+		allocatorAssignmentDeferredTotal.Add(float64(moves - *s.moves))
+		s.add = s.add[:repairs+*s.moves]
+		*s.moves = 0
+	}
 }
 
 // buildRemoveOps adds operations to |txn| removing each of the Assignments in |s.remove|.
@@ -234,8 +276,13 @@ func (s *itemState) buildAddOps(txn checkpointTxn) {
 		// Verify the Member has not changed. Otherwise, its ItemLimit may have decreased
 		// (and this addition could violate it).
 		txn.If(modRevisionUnchanged(s.global.Members[ind]))
-		// Put an Assignment with an empty value under the Member's Lease.
-		txn.Then(clientv3.OpPut(AssignmentKey(s.global.KS, a), "",
+		// Put an Assignment under the Member's Lease. Its value is empty,
+		// unless produced by the AssignmentValueFunc.
+		var value string
+		if s.value != nil {
+			value = s.value(s.global, memberAt(s.global.Members, ind), itemAt(s.global.Items, s.item))
+		}
+		txn.Then(clientv3.OpPut(AssignmentKey(s.global.KS, a), value,
 			clientv3.WithLease(clientv3.LeaseID(s.global.Members[ind].Raw.Lease))))
 
 		// Update to reflect the member's total count (and potentially primary count) has increased.
@@ -0,0 +1,257 @@
+package allocator
+
+import (
+	"sort"
+)
+
+// solvePackedAssignments solves for a maximum assignment of the State which
+// uses as few Members as it can (see AllocateArgs.Pack). It first solves
+// without packing to determine the number of Assignments which are attainable,
+// and the number of Items which may be replicated across multiple zones.
+// It then selects a subset of Members (see memberPacker) and re-solves, adding
+// the fewest further Members, in order of preference, for which the packed
+// solution is as complete as the unpacked one. The number of further Members
+// is found by an exponential and then binary search, so that re-solves are
+// logarithmic (rather than linear) in the number of Members which are added.
+func solvePackedAssignments(s *State, desired []Assignment, args solveArgs, hysteresis float64) ([]Assignment, int, error) {
+	var from = len(desired)
+	var err error
+
+	args.packed = nil
+	if desired, err = solveDesiredAssignments(s, desired, args); err != nil {
+		return nil, 0, err
+	}
+	var unpacked = append([]Assignment(nil), desired[from:]...)
+	var target, targetZones = len(unpacked), multiZoneItems(unpacked)
+
+	var p = newMemberPacker(s, args.headroom)
+	p.selectFor(target, hysteresis)
+
+	var order = p.remaining()
+	var solvedWith = -1
+
+	// solveWith solves with selected Members and the first |n| of |order|,
+	// returning whether the solution is as complete as the unpacked one.
+	var solveWith = func(n int) (bool, error) {
+		args.packed = append([]bool(nil), p.packed...)
+		for _, ind := range order[:n] {
+			args.packed[ind] = true
+		}
+		if desired, err = solveDesiredAssignments(s, desired[:from], args); err != nil {
+			return false, err
+		}
+		solvedWith = n
+
+		var out = desired[from:]
+		return len(out) >= target && multiZoneItems(out) >= targetZones, nil
+	}
+
+	// Find |lo| < |hi| where adding |lo| Members is insufficient and adding |hi| is.
+	var lo, hi = -1, 0
+	for {
+		if ok, err := solveWith(hi); err != nil {
+			return nil, 0, err
+		} else if ok {
+			break
+		} else if hi == len(order) {
+			// All Members are selected. This shouldn't happen, as the
+			// solution should then match the unpacked one.
+			p.addAll(order)
+			return append(desired[:from], unpacked...), p.count, nil
+		}
+		if lo, hi = hi, 2*hi+1; hi > len(order) {
+			hi = len(order)
+		}
+	}
+	for hi-lo > 1 {
+		var mid = lo + (hi-lo)/2
+
+		if ok, err := solveWith(mid); err != nil {
+			return nil, 0, err
+		} else if ok {
+			hi = mid
+		} else {
+			lo = mid
+		}
+	}
+	if solvedWith != hi {
+		if _, err = solveWith(hi); err != nil {
+			return nil, 0, err
+		}
+	}
+	p.addAll(order[:hi])
+	return desired, p.count, nil
+}
+
+// memberPacker selects the Members of a State onto which Items are packed.
+type memberPacker struct {
+	s        *State
+	limits   []int  // Effective ItemLimit of each Member.
+	packed   []bool // Whether each Member is selected.
+	count    int    // Number of selected Members.
+	capacity int    // Total effective ItemLimit of selected Members.
+
+	// Candidate Members of each zone, ordered on preference.
+	candidates map[string][]int
+	// Total effective ItemLimit of selected Members of each zone.
+	zoneCapacity map[string]int
+}
+
+func newMemberPacker(s *State, headroom float64) *memberPacker {
+	var p = &memberPacker{
+		s:            s,
+		limits:       make([]int, len(s.Members)),
+		packed:       make([]bool, len(s.Members)),
+		candidates:   make(map[string][]int),
+		zoneCapacity: make(map[string]int),
+	}
+	for ind := range s.Members {
+		if p.limits[ind] = s.effectiveItemLimit(ind, headroom); p.limits[ind] == 0 {
+			continue // Member may not be assigned Items.
+		}
+		var zone = memberAt(s.Members, ind).Zone
+		p.candidates[zone] = append(p.candidates[zone], ind)
+	}
+	for _, c := range p.candidates {
+		sort.SliceStable(c, func(i, j int) bool { return p.prefer(c[i], c[j]) })
+	}
+	return p
+}
+
+// prefer returns true if Member |i| is preferred for selection over Member
+// |j|. Members which currently hold more Assignments are preferred, so that
+// packing moves as few Assignments as possible. Then, Members of larger limits
+// are preferred, so that fewer are required. Finally, Members are ordered on key.
+func (p *memberPacker) prefer(i, j int) bool {
+	if ci, cj := p.s.MemberTotalCount[i], p.s.MemberTotalCount[j]; ci != cj {
+		return ci > cj
+	} else if p.limits[i] != p.limits[j] {
+		return p.limits[i] > p.limits[j]
+	}
+	return i < j
+}
+
+// selectFor selects Members having a total capacity of at least |target|.
+// Members which currently hold Assignments remain selected until the other
+// selected Members have a total capacity of at least |target| * (1 + hysteresis),
+// which keeps a Member from being repeatedly released and re-added as
+// the number of desired Assignments fluctuates around a Member boundary.
+func (p *memberPacker) selectFor(target int, hysteresis float64) {
+	for p.capacity < target {
+		if _, ok := p.addNext(); !ok {
+			break
+		}
+	}
+
+	// Retain Members currently holding Assignments, in order of preference.
+	var retain []int
+	for _, c := range p.candidates {
+		for _, ind := range c {
+			if p.s.MemberTotalCount[ind] != 0 {
+				retain = append(retain, ind)
+			}
+		}
+	}
+	sort.Slice(retain, func(i, j int) bool { return p.prefer(retain[i], retain[j]) })
+
+	var bound = float64(target) * (1 + hysteresis)
+	for _, ind := range retain {
+		if float64(p.capacity) >= bound {
+			break
+		}
+		p.add(ind)
+	}
+}
+
+// addNext selects the next preferred Member of the zone having the least
+// selected capacity, which balances selected capacity across zones so that
+// Items may be replicated across them. It returns the selected Member, or
+// false if all candidate Members are selected.
+func (p *memberPacker) addNext() (int, bool) {
+	var zone string
+	var found bool
+
+	for z, c := range p.candidates {
+		if len(c) == 0 {
+			continue
+		} else if !found || p.zoneCapacity[z] < p.zoneCapacity[zone] ||
+			(p.zoneCapacity[z] == p.zoneCapacity[zone] && z < zone) {
+			zone, found = z, true
+		}
+	}
+	if !found {
+		return 0, false
+	}
+	var ind = p.candidates[zone][0]
+	p.add(ind)
+	return ind, true
+}
+
+// remaining returns the unselected candidate Members, in the order in which
+// they would be selected by successive calls to addNext. The memberPacker
+// isn't modified.
+func (p *memberPacker) remaining() []int {
+	var c = *p
+	c.packed = append([]bool(nil), p.packed...)
+	c.candidates = make(map[string][]int, len(p.candidates))
+	c.zoneCapacity = make(map[string]int, len(p.zoneCapacity))
+
+	for z, ind := range p.candidates {
+		c.candidates[z] = append([]int(nil), ind...)
+	}
+	for z, n := range p.zoneCapacity {
+		c.zoneCapacity[z] = n
+	}
+
+	var out []int
+	for ind, ok := c.addNext(); ok; ind, ok = c.addNext() {
+		out = append(out, ind)
+	}
+	return out
+}
+
+// addAll selects each of Members |inds|.
+func (p *memberPacker) addAll(inds []int) {
+	for _, ind := range inds {
+		p.add(ind)
+	}
+}
+
+// add selects Member |ind|, if it's not already selected.
+func (p *memberPacker) add(ind int) {
+	if p.packed[ind] {
+		return
+	}
+	var zone = memberAt(p.s.Members, ind).Zone
+	var c = p.candidates[zone]
+
+	for i := range c {
+		if c[i] == ind {
+			p.candidates[zone] = append(c[:i:i], c[i+1:]...)
+			break
+		}
+	}
+	p.packed[ind] = true
+	p.count++
+	p.capacity += p.limits[ind]
+	p.zoneCapacity[zone] += p.limits[ind]
+}
+
+// multiZoneItems returns the number of Items of ordered Assignments |asn|
+// which are assigned to Members of more than one zone.
+func multiZoneItems(asn []Assignment) int {
+	var out int
+	for i := 0; i != len(asn); {
+		var j = i + 1
+		var multi bool
+
+		for ; j != len(asn) && asn[j].ItemID == asn[i].ItemID; j++ {
+			multi = multi || asn[j].MemberZone != asn[i].MemberZone
+		}
+		if multi {
+			out++
+		}
+		i = j
+	}
+	return out
+}
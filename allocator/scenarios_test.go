@@ -3,6 +3,10 @@ package allocator
 import (
 	"context"
 	"io"
+	"math"
+	"sort"
+	"strings"
+	"sync/atomic"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -872,6 +876,716 @@ func TestCleanupOfAssignmentsWithoutMember(t *testing.T) {
 	})
 }
 
+func TestHeadroomReservesMemberCapacity(t *testing.T) {
+	var ctx, client, ks = testSetup(t)
+	var args = AllocateArgs{Headroom: 0.2}
+
+	require.NoError(t, insert(ctx, client,
+		"/root/items/item-1", `{"R": 2}`,
+		"/root/items/item-2", `{"R": 2}`,
+		"/root/items/item-3", `{"R": 2}`,
+		"/root/items/item-4", `{"R": 2}`,
+		"/root/items/item-5", `{"R": 2}`,
+		"/root/items/item-6", `{"R": 2}`,
+		"/root/items/item-7", `{"R": 2}`,
+
+		"/root/members/zone-a#member-A1", `{"R": 5}`,
+		"/root/members/zone-a#member-A2", `{"R": 5}`,
+		"/root/members/zone-b#member-B", `{"R": 5}`,
+	))
+	// Returns the number of Assignments of each Member.
+	var memberCounts = func() map[string]int {
+		var out = make(map[string]int)
+		for _, kv := range ks.Prefixed(ks.Root + AssignmentsPrefix) {
+			var a = kv.Decoded.(Assignment)
+			out[a.MemberZone+"#"+a.MemberSuffix]++
+		}
+		return out
+	}
+
+	// Members have an effective capacity of 4 (rather than 5), and 12 slots
+	// are too few to fully replicate all Items. Expect Items are under-replicated,
+	// and no Member exceeds its effective capacity.
+	require.Equal(t, serveUntilIdleWithArgs(t, ctx, client, ks, "", args), 2)
+	require.Equal(t, map[string]int{
+		"zone-a#member-A1": 4,
+		"zone-a#member-A2": 4,
+		"zone-b#member-B":  4,
+	}, memberCounts())
+
+	// Increase the ItemLimit of member-B, such that Items may be fully replicated.
+	require.NoError(t, update(ctx, client,
+		"/root/members/zone-b#member-B", `{"R": 10}`))
+	require.Equal(t, serveUntilIdleWithArgs(t, ctx, client, ks, "", args), 5)
+
+	var counts = memberCounts()
+	require.Equal(t, 14, counts["zone-a#member-A1"]+counts["zone-a#member-A2"]+counts["zone-b#member-B"])
+	require.LessOrEqual(t, counts["zone-a#member-A1"], 4)
+	require.LessOrEqual(t, counts["zone-a#member-A2"], 4)
+	require.LessOrEqual(t, counts["zone-b#member-B"], 8)
+
+	// Headroom must be a fraction of ItemLimit.
+	require.EqualError(t, Allocate(AllocateArgs{State: &State{KS: ks}, Headroom: 1}),
+		"invalid Headroom (1.000000; expected 0 <= Headroom < 1)")
+}
+
+func TestExternalLeadership(t *testing.T) {
+	var ctx, client, ks = testSetup(t)
+	defer func(m int) { maxTxnOps = m }(maxTxnOps) // Force multiple transactions per round.
+	maxTxnOps = 4
+
+	require.NoError(t, insert(ctx, client,
+		"/root/items/item-1", `{"R": 1}`,
+		"/root/items/item-2", `{"R": 1}`,
+		"/root/items/item-3", `{"R": 1}`,
+		"/root/items/item-4", `{"R": 1}`,
+		"/root/items/item-5", `{"R": 1}`,
+		"/root/items/item-6", `{"R": 1}`,
+
+		"/root/members/zone-a#member-A", `{"R": 10}`,
+		"/root/members/zone-a#member-B", `{"R": 10}`,
+	))
+	// Returns the number of Assignments in Etcd.
+	var countAssignments = func() int64 {
+		var resp, err = client.Get(ctx, ks.Root+AssignmentsPrefix, clientv3.WithPrefix(), clientv3.WithCountOnly())
+		require.NoError(t, err)
+		return resp.Count
+	}
+
+	// member-B is elected externally, though member-A has the oldest key.
+	var state = NewObservedState(ks, MemberKey(ks, "zone-a", "member-B"), isConsistent)
+	require.NoError(t, ks.Load(ctx, client, 0))
+
+	ctx, cancel := context.WithCancel(ctx)
+	go ks.Watch(ctx, client)
+
+	// |grants| is the number of remaining IsLeader calls which return true.
+	// A call which returns false is signalled to |deniedCh|.
+	var grants int32
+	var deniedCh = make(chan struct{}, 1)
+	var changedCh = make(chan struct{}, 1)
+	var idleCh = make(chan struct{}, 1)
+
+	var isLeader = func() bool {
+		if atomic.AddInt32(&grants, -1) >= 0 {
+			return true
+		}
+		select {
+		case deniedCh <- struct{}{}:
+		default:
+		}
+		return false
+	}
+	var setGrants = func(n int32) {
+		select {
+		case <-deniedCh: // Discard a prior denial.
+		default:
+		}
+		atomic.StoreInt32(&grants, n)
+		changedCh <- struct{}{}
+	}
+
+	// |transitions| counts leadership callbacks, which must alternate.
+	var transitions int32
+	var onTransition = func(gained bool) func() {
+		return func() {
+			var n = atomic.AddInt32(&transitions, 1)
+			require.Equal(t, gained, n%2 == 1)
+		}
+	}
+
+	var doneCh = make(chan error)
+	go func() {
+		doneCh <- Allocate(AllocateArgs{
+			Context:            ctx,
+			Etcd:               client,
+			State:              state,
+			IsLeader:           isLeader,
+			LeaderChangedCh:    changedCh,
+			OnGainedLeadership: onTransition(true),
+			OnLostLeadership:   onTransition(false),
+			TestHook: func(_ int, idle bool) {
+				if idle {
+					idleCh <- struct{}{}
+				}
+			},
+		})
+	}()
+
+	// Without leadership, nothing is allocated.
+	<-deniedCh
+	require.Equal(t, int64(0), countAssignments())
+
+	// Grant leadership of the round and its first transaction only. Expect
+	// the round is aborted prior to its second transaction.
+	setGrants(2)
+	<-deniedCh
+	var partial = countAssignments()
+	require.True(t, partial > 0 && partial < 6, partial)
+
+	// Grant leadership indefinitely. Allocation converges.
+	setGrants(math.MaxInt32)
+	<-idleCh
+	require.Equal(t, int64(6), countAssignments())
+
+	// Losing leadership steps down, without further writes.
+	setGrants(0)
+	<-deniedCh
+	require.Equal(t, int64(6), countAssignments())
+
+	cancel()
+	require.Equal(t, context.Canceled, <-doneCh)
+
+	// Leadership was gained and then lost with each of two grants.
+	require.Equal(t, int32(4), atomic.LoadInt32(&transitions))
+}
+
+func TestLeadershipCallbacksOnLeaseRevocation(t *testing.T) {
+	var ctx, client, ksA = testSetup(t)
+	var ksB = NewAllocatorKeySpace("/root", testAllocDecoder{})
+
+	// member-A has the oldest key, and is leader until its lease is revoked.
+	var lease, err = client.Grant(ctx, 60)
+	require.NoError(t, err)
+	_, err = client.Put(ctx, "/root/members/zone-a#member-A", `{"R": 10}`, clientv3.WithLease(lease.ID))
+	require.NoError(t, err)
+
+	require.NoError(t, insert(ctx, client,
+		"/root/items/item-1", `{"R": 1}`,
+		"/root/members/zone-a#member-B", `{"R": 10}`,
+	))
+
+	ctx, cancel := context.WithCancel(ctx)
+	var callbacks = make(chan string, 10)
+	var doneA, doneB = make(chan error), make(chan error)
+
+	for _, tc := range []struct {
+		ks     *keyspace.KeySpace
+		member string
+		doneCh chan error
+	}{
+		{ksA, "member-A", doneA},
+		{ksB, "member-B", doneB},
+	} {
+		var state = NewObservedState(tc.ks, MemberKey(tc.ks, "zone-a", tc.member), isConsistent)
+		require.NoError(t, tc.ks.Load(ctx, client, 0))
+		go tc.ks.Watch(ctx, client)
+
+		var member, doneCh = tc.member, tc.doneCh
+		go func() {
+			doneCh <- Allocate(AllocateArgs{
+				Context:            ctx,
+				Etcd:               client,
+				State:              state,
+				OnGainedLeadership: func() { callbacks <- member + " gained" },
+				OnLostLeadership:   func() { callbacks <- member + " lost" },
+			})
+		}()
+	}
+	require.Equal(t, "member-A gained", <-callbacks)
+
+	// Revoke member-A's lease. It loses leadership, and member-B gains it.
+	_, err = client.Revoke(ctx, lease.ID)
+	require.NoError(t, err)
+
+	require.EqualError(t, <-doneA, "member key not found in Etcd")
+	require.ElementsMatch(t, []string{"member-A lost", "member-B gained"},
+		[]string{<-callbacks, <-callbacks})
+
+	cancel()
+	require.Equal(t, context.Canceled, <-doneB)
+	require.Equal(t, "member-B lost", <-callbacks)
+}
+
+func TestCustomAssignmentValues(t *testing.T) {
+	var ctx, client, ks = testSetup(t)
+	var args = AllocateArgs{
+		AssignmentValue: func(state *State, member Member, item Item) string {
+			return "pending:" + member.Zone + "#" + member.Suffix + "/" + item.ID
+		},
+	}
+
+	require.NoError(t, insert(ctx, client,
+		"/root/items/item-1", `{"R": 1}`,
+		"/root/items/item-2", `{"R": 1}`,
+
+		"/root/members/zone-a#member-A", `{"R": 10}`,
+		"/root/members/zone-a#member-B", `{"R": 10}`,
+	))
+	// Returns Assignment values, keyed on Assignment key suffix.
+	var values = func() map[string]string {
+		var resp, err = client.Get(ctx, ks.Root+AssignmentsPrefix, clientv3.WithPrefix())
+		require.NoError(t, err)
+
+		var out = make(map[string]string)
+		for _, kv := range resp.Kvs {
+			out[string(kv.Key[len(ks.Root+AssignmentsPrefix):])] = string(kv.Value)
+		}
+		return out
+	}
+	// Marks all Assignments having a custom value as consistent.
+	var markPendingConsistent = func() {
+		for key, value := range values() {
+			if strings.HasPrefix(value, "pending:") {
+				require.NoError(t, update(ctx, client, ks.Root+AssignmentsPrefix+key, "consistent"))
+			}
+		}
+	}
+
+	// Expect new Assignments are created with custom values.
+	serveUntilIdleWithArgs(t, ctx, client, ks, "", args)
+	require.Equal(t, map[string]string{
+		"item-1#zone-a#member-A#0": "pending:zone-a#member-A/item-1",
+		"item-2#zone-a#member-B#0": "pending:zone-a#member-B/item-2",
+	}, values())
+	markPendingConsistent()
+
+	// Drain member-B. Its Item is re-assigned, but the custom value of the new
+	// Assignment isn't consistent, and the current Assignment may not yet be removed.
+	require.NoError(t, update(ctx, client, "/root/members/zone-a#member-B", `{"R": 0}`))
+	serveUntilIdleWithArgs(t, ctx, client, ks, "", args)
+	require.Equal(t, map[string]string{
+		"item-1#zone-a#member-A#0": "consistent",
+		"item-2#zone-a#member-A#1": "pending:zone-a#member-A/item-2",
+		"item-2#zone-a#member-B#0": "consistent",
+	}, values())
+
+	// Once the new Assignment is consistent, the drained one is removed.
+	// The moved Assignment retains its current value.
+	markPendingConsistent()
+	serveUntilIdleWithArgs(t, ctx, client, ks, "", args)
+	require.Equal(t, map[string]string{
+		"item-1#zone-a#member-A#0": "consistent",
+		"item-2#zone-a#member-A#0": "consistent",
+	}, values())
+}
+
+func TestEvacuateZoneMigratesItems(t *testing.T) {
+	var ctx, client, ks = testSetup(t)
+
+	require.NoError(t, insert(ctx, client,
+		"/root/items/item-1", `{"R": 3}`,
+		"/root/items/item-2", `{"R": 3}`,
+
+		"/root/members/zone-a#member-A1", `{"R": 10}`,
+		"/root/members/zone-a#member-A2", `{"R": 10}`,
+		"/root/members/zone-a#member-A3", `{"R": 10}`,
+		"/root/members/zone-b#member-B", `{"R": 10}`,
+		"/root/members/zone-c#member-C", `{"R": 10}`,
+
+		"/root/assign/item-1#zone-a#member-A1#0", `consistent`,
+		"/root/assign/item-1#zone-a#member-A2#1", `consistent`,
+		"/root/assign/item-1#zone-c#member-C#2", `consistent`,
+		"/root/assign/item-2#zone-a#member-A3#0", `consistent`,
+		"/root/assign/item-2#zone-b#member-B#1", `consistent`,
+		"/root/assign/item-2#zone-c#member-C#2", `consistent`,
+	))
+	require.Equal(t, serveUntilIdle(t, ctx, client, ks, ""), 0) // Fixture is stable.
+
+	// Evacuate zone-c. Expect Items are re-balanced across two remaining zones,
+	// exactly as if member-C's ItemLimit were zero.
+	require.NoError(t, EvacuateZone(ctx, client, ks, "zone-c"))
+	require.Equal(t, serveUntilIdle(t, ctx, client, ks, ""), 4)
+
+	require.Equal(t, keys(ks.Prefixed(ks.Root+AssignmentsPrefix)), []string{
+		"/root/assign/item-1#zone-a#member-A1#0",
+		"/root/assign/item-1#zone-a#member-A2#1",
+		"/root/assign/item-1#zone-b#member-B#2",
+		"/root/assign/item-2#zone-a#member-A1#2",
+		"/root/assign/item-2#zone-a#member-A3#0",
+		"/root/assign/item-2#zone-b#member-B#1",
+	})
+
+	// A Member joining the evacuated zone isn't assigned Items.
+	require.NoError(t, insert(ctx, client, "/root/members/zone-c#member-C2", `{"R": 10}`))
+	require.Equal(t, serveUntilIdle(t, ctx, client, ks, ""), 0)
+
+	var state = NewObservedState(ks, MemberKey(ks, "zone-c", "member-C2"), isConsistent)
+	require.NoError(t, ks.Load(ctx, client, 0))
+
+	require.Equal(t, []string{"zone-a", "zone-b"}, state.Zones)
+	require.Equal(t, []string{"zone-c"}, state.EvacuatedZones())
+	require.Equal(t, ZoneStats{Members: 2, Evacuated: true}, state.ZoneLoad()["zone-c"])
+
+	var placement = state.ExplainPlacement("item-1", nil)
+	require.Equal(t, "zone zone-c is evacuated", placement.Members[len(placement.Members)-1].Reason)
+
+	// Add an Item while remaining zones have room for only one of its replicas.
+	// Expect it's under-replicated, rather than placed in zone-c.
+	require.NoError(t, update(ctx, client,
+		"/root/members/zone-a#member-A1", `{"R": 2}`,
+		"/root/members/zone-a#member-A2", `{"R": 1}`,
+		"/root/members/zone-a#member-A3", `{"R": 2}`,
+		"/root/members/zone-b#member-B", `{"R": 2}`,
+	))
+	require.NoError(t, insert(ctx, client, "/root/items/item-3", `{"R": 3}`))
+	serveUntilIdle(t, ctx, client, ks, "")
+
+	require.Equal(t, []string{"member-A3"}, itemMembers(ks)["item-3"])
+	require.Equal(t, 1, NewStatusHandler(state).Status().UnderReplicatedItems)
+
+	// Restore zone-c. Expect its Members are again assigned Items.
+	require.NoError(t, RestoreZone(ctx, client, ks, "zone-c"))
+	serveUntilIdle(t, ctx, client, ks, "")
+
+	require.Equal(t, []string{"member-A3", "member-C", "member-C2"}, itemMembers(ks)["item-3"])
+}
+
+func TestMoveBudgetDefersMigration(t *testing.T) {
+	var ctx, client, ks = testSetup(t)
+
+	require.NoError(t, insert(ctx, client,
+		"/root/items/item-1", `{"R": 1}`,
+		"/root/items/item-2", `{"R": 1}`,
+		"/root/items/item-3", `{"R": 1}`,
+		"/root/items/item-4", `{"R": 1}`,
+		"/root/items/item-5", `{"R": 1}`,
+		"/root/items/item-6", `{"R": 1}`,
+
+		"/root/members/zone-a#member-A", `{"R": 6}`,
+	))
+	// Each transaction audits the (item, member) pairs it newly assigns.
+	var assigned = make(map[string]bool)
+	var added []int
+
+	var args = AllocateArgs{
+		MoveBudget: 1,
+		Audit: func(records []AuditRecord) {
+			var next = make(map[string]bool)
+			for key := range assigned {
+				next[key] = true
+			}
+			for _, r := range records {
+				var key = r.ItemID + "#" + r.MemberSuffix
+				next[key] = r.Op != "delete"
+			}
+			var n int
+			for key, ok := range next {
+				if ok && !assigned[key] {
+					n++
+				} else if !ok {
+					delete(next, key)
+				}
+			}
+			assigned, added = next, append(added, n)
+		},
+	}
+	// Initial Assignments are repairs, which aren't limited by the budget.
+	require.Equal(t, 2, serveUntilIdleWithArgs(t, ctx, client, ks, "", args))
+	require.Equal(t, []int{6}, added)
+
+	// Add a Member, which causes half of the Items to migrate. Expect the
+	// migration proceeds with one move per round, and converges.
+	require.NoError(t, insert(ctx, client, "/root/members/zone-a#member-B", `{"R": 6}`))
+	added = added[:0]
+
+	var rounds = serveUntilIdleWithArgs(t, ctx, client, ks, "", args)
+	require.LessOrEqual(t, rounds, 3*3+1)
+	require.Len(t, assigned, 6)
+
+	var moves int
+	for _, n := range added {
+		require.LessOrEqual(t, n, 1)
+		moves += n
+	}
+	require.Equal(t, 3, moves)
+
+	require.Equal(t, []string{
+		"/root/assign/item-1#zone-a#member-A#0",
+		"/root/assign/item-2#zone-a#member-B#0",
+		"/root/assign/item-3#zone-a#member-B#0",
+		"/root/assign/item-4#zone-a#member-A#0",
+		"/root/assign/item-5#zone-a#member-B#0",
+		"/root/assign/item-6#zone-a#member-A#0",
+	}, keys(ks.Prefixed(ks.Root+AssignmentsPrefix)))
+
+	// MoveBudget must not be negative.
+	require.EqualError(t, Allocate(AllocateArgs{State: &State{KS: ks}, MoveBudget: -1}),
+		"invalid MoveBudget (-1; expected >= 0)")
+}
+
+func TestPackMinimizesAssignedMembers(t *testing.T) {
+	var ctx, client, ks = testSetup(t)
+	var args = AllocateArgs{Pack: true, PackHysteresis: 0.5}
+
+	require.NoError(t, insert(ctx, client,
+		"/root/items/item-1", `{"R": 1}`,
+		"/root/items/item-2", `{"R": 1}`,
+		"/root/items/item-3", `{"R": 1}`,
+
+		"/root/members/zone-a#member-A1", `{"R": 4}`,
+		"/root/members/zone-a#member-A2", `{"R": 4}`,
+		"/root/members/zone-a#member-A3", `{"R": 4}`,
+		"/root/members/zone-a#member-A4", `{"R": 4}`,
+	))
+	// Returns the number of Assignments of each Member.
+	var memberCounts = func() map[string]int {
+		var out = make(map[string]int)
+		for _, kv := range ks.Prefixed(ks.Root + AssignmentsPrefix) {
+			var a = kv.Decoded.(Assignment)
+			out[a.MemberZone+"#"+a.MemberSuffix]++
+		}
+		return out
+	}
+
+	// Under low demand, all Items are packed onto a single Member.
+	serveUntilIdleWithArgs(t, ctx, client, ks, "", args)
+	require.Equal(t, map[string]int{"zone-a#member-A1": 3}, memberCounts())
+
+	// Demand grows beyond the capacity of one Member. Expect one more is used.
+	require.NoError(t, insert(ctx, client,
+		"/root/items/item-4", `{"R": 1}`,
+		"/root/items/item-5", `{"R": 1}`,
+	))
+	serveUntilIdleWithArgs(t, ctx, client, ks, "", args)
+
+	var counts = memberCounts()
+	require.Len(t, counts, 2)
+	require.Equal(t, 5, counts["zone-a#member-A1"]+counts["zone-a#member-A2"])
+
+	// Demand falls back within the capacity of one Member, but not within its
+	// capacity less hysteresis. Expect the second Member is retained.
+	var _, err = client.Delete(ctx, "/root/items/item-5")
+	require.NoError(t, err)
+	serveUntilIdleWithArgs(t, ctx, client, ks, "", args)
+	require.Len(t, memberCounts(), 2)
+
+	// Without hysteresis, Items are packed back onto a single Member.
+	args.PackHysteresis = 0
+	serveUntilIdleWithArgs(t, ctx, client, ks, "", args)
+	require.Equal(t, map[string]int{"zone-a#member-A1": 4}, memberCounts())
+
+	// Items replicated across zones remain so when packed.
+	require.NoError(t, insert(ctx, client,
+		"/root/members/zone-b#member-B1", `{"R": 4}`,
+		"/root/members/zone-b#member-B2", `{"R": 4}`,
+	))
+	require.NoError(t, update(ctx, client,
+		"/root/items/item-1", `{"R": 2}`,
+		"/root/items/item-2", `{"R": 2}`,
+	))
+	serveUntilIdleWithArgs(t, ctx, client, ks, "", args)
+	require.Equal(t, map[string]int{
+		"zone-a#member-A1": 3,
+		"zone-b#member-B1": 3,
+	}, memberCounts())
+
+	// PackHysteresis must not be negative.
+	require.EqualError(t, Allocate(AllocateArgs{State: &State{KS: ks}, PackHysteresis: -1}),
+		"invalid PackHysteresis (-1.000000; expected >= 0)")
+}
+
+func TestSpreadAcrossMemberAttribute(t *testing.T) {
+	var ctx, client, ks = testSetup(t)
+
+	require.NoError(t, insert(ctx, client,
+		"/root/items/item-1", `{"R": 3, "S": "rack"}`,
+		"/root/items/item-2", `{"R": 3, "S": "rack"}`,
+		"/root/items/item-3", `{"R": 3, "S": "rack"}`,
+		"/root/items/item-4", `{"R": 3, "S": "rack"}`,
+
+		"/root/members/zone-a#member-1", `{"R": 4, "A": {"rack": "r1"}}`,
+		"/root/members/zone-a#member-2", `{"R": 4, "A": {"rack": "r1"}}`,
+		"/root/members/zone-a#member-3", `{"R": 4, "A": {"rack": "r2"}}`,
+		"/root/members/zone-a#member-4", `{"R": 4, "A": {"rack": "r2"}}`,
+		"/root/members/zone-a#member-5", `{"R": 4, "A": {"rack": "r3"}}`,
+		"/root/members/zone-a#member-6", `{"R": 4, "A": {"rack": "r3"}}`,
+		"/root/members/zone-a#member-7", `{"R": 4}`, // Has no rack.
+	))
+	var state = NewObservedState(ks, MemberKey(ks, "zone-a", "member-1"), isConsistent)
+
+	// Returns the racks of each Item's Assignments.
+	var itemRacks = func() map[string][]string {
+		var out = make(map[string][]string)
+		for _, kv := range ks.Prefixed(ks.Root + AssignmentsPrefix) {
+			var a = kv.Decoded.(Assignment)
+			var ind, found = ks.Search(MemberKey(ks, a.MemberZone, a.MemberSuffix))
+			require.True(t, found)
+
+			var rack = ks.KeyValues[ind].Decoded.(Member).MemberValue.(testMember).A["rack"]
+			out[a.ItemID] = append(out[a.ItemID], rack)
+		}
+		for _, racks := range out {
+			sort.Strings(racks)
+		}
+		return out
+	}
+	var allRacks = []string{"r1", "r2", "r3"}
+
+	// Expect each Item is replicated once to each of the three racks, even
+	// though member-7 (having no rack) has capacity, and the lowest load.
+	require.Equal(t, 2, serveUntilIdle(t, ctx, client, ks, ""))
+	require.Equal(t, map[string][]string{
+		"item-1": allRacks,
+		"item-2": allRacks,
+		"item-3": allRacks,
+		"item-4": allRacks,
+	}, itemRacks())
+
+	require.Empty(t, state.SpreadViolations())
+
+	// A warm-started solve, seeded from current Assignments, is also spread.
+	ks.Mu.RLock()
+	var desired, err = solveDesiredAssignments(state, nil, solveArgs{warmStart: true})
+	ks.Mu.RUnlock()
+	require.NoError(t, err)
+
+	var desiredMembers = make(map[string][]string)
+	for _, a := range desired {
+		desiredMembers[a.ItemID] = append(desiredMembers[a.ItemID], a.MemberSuffix)
+	}
+	require.Equal(t, map[string][]string{
+		"item-1": {"member-2", "member-3", "member-6"},
+		"item-2": {"member-1", "member-4", "member-5"},
+		"item-3": {"member-2", "member-3", "member-6"},
+		"item-4": {"member-1", "member-4", "member-5"},
+	}, desiredMembers)
+
+	// Members of rack r3 and their Assignments disappear. Expect Items are
+	// under-replicated rather than placed on two Members of a shared rack,
+	// and the violation is reported.
+	var deleted []string
+	for _, kv := range ks.Prefixed(ks.Root + AssignmentsPrefix) {
+		if a := kv.Decoded.(Assignment); a.MemberSuffix == "member-5" || a.MemberSuffix == "member-6" {
+			deleted = append(deleted, string(kv.Raw.Key))
+		}
+	}
+	deleted = append(deleted, "/root/members/zone-a#member-5", "/root/members/zone-a#member-6")
+
+	for _, key := range deleted {
+		var _, err = client.Delete(ctx, key)
+		require.NoError(t, err)
+	}
+	require.Equal(t, 0, serveUntilIdle(t, ctx, client, ks, ""))
+	require.Equal(t, map[string][]string{
+		"item-1": {"r1", "r2"},
+		"item-2": {"r1", "r2"},
+		"item-3": {"r1", "r2"},
+		"item-4": {"r1", "r2"},
+	}, itemRacks())
+
+	require.Equal(t, []SpreadViolation{
+		{ItemID: "item-1", Attribute: "rack", Values: 2, DesiredReplication: 3},
+		{ItemID: "item-2", Attribute: "rack", Values: 2, DesiredReplication: 3},
+		{ItemID: "item-3", Attribute: "rack", Values: 2, DesiredReplication: 3},
+		{ItemID: "item-4", Attribute: "rack", Values: 2, DesiredReplication: 3},
+	}, state.SpreadViolations())
+
+	require.Contains(t, state.ExplainPlacement("item-1", nil).String(),
+		"item is under-replicated (2 of 3 desired): members have only 2 distinct values of spread attribute rack")
+}
+
+func TestHardAffinityCoLocatesItems(t *testing.T) {
+	var ctx, client, ks = testSetup(t)
+
+	require.NoError(t, insert(ctx, client,
+		"/root/items/item-1", `{"R": 2, "G": ["g"], "H": true}`,
+		"/root/items/item-2", `{"R": 2, "G": ["g"], "H": true}`,
+		"/root/items/item-3", `{"R": 2, "G": ["g"], "H": true}`,
+		"/root/items/item-4", `{"R": 2}`,
+		"/root/items/item-5", `{"R": 2}`,
+		"/root/items/item-6", `{"R": 2}`,
+
+		"/root/members/zone-a#member-1", `{"R": 4}`,
+		"/root/members/zone-a#member-2", `{"R": 4}`,
+		"/root/members/zone-b#member-3", `{"R": 4}`,
+		"/root/members/zone-b#member-4", `{"R": 4}`,
+	))
+	var state = NewObservedState(ks, MemberKey(ks, "zone-a", "member-1"), isConsistent)
+
+	// Expect Items of the group are co-located with their anchor, item-1,
+	// though Members are then loaded beyond their fair share.
+	require.Equal(t, 2, serveUntilIdle(t, ctx, client, ks, ""))
+	require.Equal(t, map[string][]string{
+		"item-1": {"member-2", "member-4"},
+		"item-2": {"member-2", "member-4"},
+		"item-3": {"member-2", "member-4"},
+		"item-4": {"member-1", "member-3"},
+		"item-5": {"member-1", "member-3"},
+		"item-6": {"member-1", "member-3"},
+	}, itemMembers(ks))
+	require.Empty(t, state.AffinityViolations())
+
+	// Reduce capacity such that no two Members can hold the group. Also give
+	// item-3 a conflicting second group. Expect the affinity is relaxed so
+	// that Items remain fully replicated, and violations are reported.
+	require.NoError(t, update(ctx, client,
+		"/root/members/zone-a#member-2", `{"R": 2}`,
+		"/root/members/zone-b#member-4", `{"R": 2}`,
+		"/root/items/item-3", `{"R": 2, "G": ["g", "h"], "H": true}`,
+	))
+	require.Equal(t, 4, serveUntilIdle(t, ctx, client, ks, ""))
+	require.Equal(t, map[string][]string{
+		"item-1": {"member-1", "member-4"},
+		"item-2": {"member-2", "member-3"},
+		"item-3": {"member-2", "member-4"},
+		"item-4": {"member-1", "member-3"},
+		"item-5": {"member-1", "member-3"},
+		"item-6": {"member-1", "member-3"},
+	}, itemMembers(ks))
+
+	require.Equal(t, []AffinityViolation{
+		{ItemID: "item-2", Group: "g", Hard: true,
+			Reason: "2 of 2 assignments are not co-located with anchor item item-1"},
+		{ItemID: "item-3", Group: "g", Hard: true,
+			Reason: "item has conflicting affinity groups (g, h); only g applies"},
+		{ItemID: "item-3", Group: "g", Hard: true,
+			Reason: "1 of 2 assignments are not co-located with anchor item item-1"},
+	}, state.AffinityViolations())
+}
+
+func TestSoftAffinityPrefersAnchorMembers(t *testing.T) {
+	var ctx, client, ks = testSetup(t)
+
+	require.NoError(t, insert(ctx, client,
+		"/root/items/item-1", `{"R": 2, "G": ["g"]}`,
+		"/root/items/item-4", `{"R": 2}`,
+
+		"/root/members/zone-a#member-1", `{"R": 4}`,
+		"/root/members/zone-a#member-2", `{"R": 4}`,
+		"/root/members/zone-b#member-3", `{"R": 4}`,
+		"/root/members/zone-b#member-4", `{"R": 4}`,
+	))
+	var state = NewObservedState(ks, MemberKey(ks, "zone-a", "member-1"), isConsistent)
+
+	require.Equal(t, 2, serveUntilIdle(t, ctx, client, ks, ""))
+	require.Equal(t, map[string][]string{
+		"item-1": {"member-2", "member-4"},
+		"item-4": {"member-1", "member-3"},
+	}, itemMembers(ks))
+
+	// New Items of the group prefer Members of the anchor, item-1.
+	require.NoError(t, insert(ctx, client,
+		"/root/items/item-2", `{"R": 2, "G": ["g"]}`,
+		"/root/items/item-3", `{"R": 2, "G": ["g"]}`,
+		"/root/items/item-5", `{"R": 2}`,
+	))
+	require.Equal(t, 2, serveUntilIdle(t, ctx, client, ks, ""))
+	require.Equal(t, map[string][]string{
+		"item-1": {"member-2", "member-4"},
+		"item-2": {"member-2", "member-4"},
+		"item-3": {"member-2", "member-4"},
+		"item-4": {"member-1", "member-3"},
+		"item-5": {"member-1", "member-3"},
+	}, itemMembers(ks))
+	require.Empty(t, state.AffinityViolations())
+
+	// Members of the anchor have capacity for only one more Item of the group.
+	// Expect the other is placed apart from the anchor, and is reported.
+	require.NoError(t, insert(ctx, client,
+		"/root/items/item-6", `{"R": 2, "G": ["g"]}`,
+		"/root/items/item-7", `{"R": 2, "G": ["g"]}`,
+	))
+	require.Equal(t, 2, serveUntilIdle(t, ctx, client, ks, ""))
+	require.Equal(t, []string{"member-2", "member-4"}, itemMembers(ks)["item-6"])
+	require.Equal(t, []string{"member-1", "member-3"}, itemMembers(ks)["item-7"])
+
+	require.Equal(t, []AffinityViolation{
+		{ItemID: "item-7", Group: "g",
+			Reason: "2 of 2 assignments are not co-located with anchor item item-1"},
+	}, state.AffinityViolations())
+}
+
 func testSetup(t *testing.T) (context.Context, *clientv3.Client, *keyspace.KeySpace) {
 	var ctx, client = context.Background(), etcdtest.TestClient()
 	t.Cleanup(etcdtest.Cleanup)
@@ -921,7 +1635,7 @@ func markAllConsistent(ctx context.Context, client *clientv3.Client, ks *keyspac
 		}
 
 		txn.If(modRevisionUnchanged(kv)).
-			Then(clientv3.OpPut(string(kv.Raw.Key), "consistent"))
+			Then(clientv3.OpPut(string(kv.Raw.Key), "consistent", clientv3.WithIgnoreLease()))
 
 		if err := txn.Checkpoint(); err != nil {
 			return err
@@ -937,6 +1651,16 @@ func markAllConsistent(ctx context.Context, client *clientv3.Client, ks *keyspac
 	}
 }
 
+// itemMembers returns the Members of each Item's Assignments.
+func itemMembers(ks *keyspace.KeySpace) map[string][]string {
+	var out = make(map[string][]string)
+	for _, kv := range ks.Prefixed(ks.Root + AssignmentsPrefix) {
+		var a = kv.Decoded.(Assignment)
+		out[a.ItemID] = append(out[a.ItemID], a.MemberSuffix)
+	}
+	return out
+}
+
 func keys(kv keyspace.KeyValues) []string {
 	var r []string
 	for i := range kv {
@@ -946,6 +1670,11 @@ func keys(kv keyspace.KeyValues) []string {
 }
 
 func serveUntilIdle(t *testing.T, ctx context.Context, client *clientv3.Client, ks *keyspace.KeySpace, when string) int {
+	return serveUntilIdleWithArgs(t, ctx, client, ks, when, AllocateArgs{})
+}
+
+// serveUntilIdleWithArgs is serveUntilIdle, using additional AllocateArgs.
+func serveUntilIdleWithArgs(t *testing.T, ctx context.Context, client *clientv3.Client, ks *keyspace.KeySpace, when string, args AllocateArgs) int {
 	// Pluck out the key of the current Member leader. We'll assume its identity.
 	var resp, err = client.Get(ctx, ks.Root+MembersPrefix,
 		clientv3.WithPrefix(),
@@ -962,24 +1691,21 @@ func serveUntilIdle(t *testing.T, ctx context.Context, client *clientv3.Client,
 	go ks.Watch(ctx, client)
 
 	// Create and serve an Allocator which will |cancel| when it becomes idle.
-	require.Equal(t, Allocate(AllocateArgs{
-		Context: ctx,
-		Etcd:    client,
-		State:   state,
-		TestHook: func(round int, idle bool) {
-			if !idle {
-				return
-			} else if err := markAllConsistent(ctx, client, ks, when); err == nil {
-				return // We were able to update some assignments from "" => "consistent".
-			} else if err != io.ErrNoProgress {
-				panic(err)
-			} else {
-				// All done. The allocator is idle, and all assignments are already consistent.
-				result = round // Preserve and return the round on which the Allocator became idle.
-				cancel()
-			}
-		},
-	}), context.Canceled)
+	args.Context, args.Etcd, args.State = ctx, client, state
+	args.TestHook = func(round int, idle bool) {
+		if !idle {
+			return
+		} else if err := markAllConsistent(ctx, client, ks, when); err == nil {
+			return // We were able to update some assignments from "" => "consistent".
+		} else if err != io.ErrNoProgress {
+			panic(err)
+		} else {
+			// All done. The allocator is idle, and all assignments are already consistent.
+			result = round // Preserve and return the round on which the Allocator became idle.
+			cancel()
+		}
+	}
+	require.Equal(t, Allocate(args), context.Canceled)
 
 	return result
 }
@@ -0,0 +1,75 @@
+package allocator
+
+import "time"
+
+// ScheduledItemValue is an optional interface of an ItemValue whose desired
+// replication varies over time, such as to ramp up replication ahead of a
+// predictable spike in demand rather than in reaction to it.
+//
+// Schedules are expressed in absolute time, and are evaluated against the
+// clock of the allocator leader, which is authoritative. The leader
+// re-evaluates schedules as each ScheduledReplication begins or ends, and
+// applies the changed replication as though the Item had been updated.
+// Other Members may momentarily disagree with the leader as to an Item's
+// replication (eg, due to clock skew), but only the leader assigns Items.
+type ScheduledItemValue interface {
+	// ReplicationSchedule of the Item.
+	ReplicationSchedule() []ScheduledReplication
+}
+
+// ScheduledReplication is a desired replication R of an Item over the time
+// interval [Begin, End). Where ScheduledReplications of an Item overlap, the
+// greatest R applies. Outside of its ScheduledReplications, the Item's
+// DesiredReplication applies.
+type ScheduledReplication struct {
+	Begin, End time.Time
+	R          int
+}
+
+// scheduledReplication returns the desired replication of the Item at |at|,
+// and the next time after |at| at which it may change (or zero if it won't).
+func scheduledReplication(item Item, at time.Time) (r int, next time.Time) {
+	var sv, ok = item.ItemValue.(ScheduledItemValue)
+	if !ok {
+		return item.DesiredReplication(), time.Time{}
+	}
+	var active bool
+
+	for _, sr := range sv.ReplicationSchedule() {
+		if !sr.Begin.Before(sr.End) {
+			continue // Empty interval.
+		} else if at.Before(sr.Begin) {
+			next = earliest(next, sr.Begin)
+			continue
+		} else if !at.Before(sr.End) {
+			continue // Elapsed.
+		}
+		next = earliest(next, sr.End)
+
+		if !active || sr.R > r {
+			r, active = sr.R, true
+		}
+	}
+	if !active {
+		r = item.DesiredReplication()
+	}
+	return r, next
+}
+
+// desiredReplication returns the DesiredReplication of the Item, under its
+// ReplicationSchedule (if any) as of the last observation of the State.
+func (s *State) desiredReplication(item Item) int {
+	var r, _ = scheduledReplication(item, s.scheduledAt)
+	return r
+}
+
+// earliest returns the earlier of |a| and |b|, where a zero Time is unset.
+func earliest(a, b time.Time) time.Time {
+	if a.IsZero() || (!b.IsZero() && b.Before(a)) {
+		return b
+	}
+	return a
+}
+
+// timeNow is the clock against which ReplicationSchedules are evaluated.
+var timeNow = time.Now
@@ -0,0 +1,106 @@
+package allocator
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestScheduledReplicationCases(t *testing.T) {
+	var t0 = time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	var at = func(h int) time.Time { return t0.Add(time.Duration(h) * time.Hour) }
+
+	var item = Item{ID: "item", ItemValue: testItem{R: 2, T: []ScheduledReplication{
+		{Begin: at(2), End: at(6), R: 3},
+		{Begin: at(4), End: at(8), R: 5}, // Overlaps the first.
+		{Begin: at(10), End: at(12), R: 1},
+		{Begin: at(14), End: at(14), R: 9}, // Empty.
+	}}}
+
+	for _, tc := range []struct {
+		at   time.Time
+		r    int
+		next time.Time
+	}{
+		{at(0), 2, at(2)},
+		{at(2), 3, at(4)},
+		{at(4), 5, at(6)}, // Greatest R of overlapping schedules applies.
+		{at(6), 5, at(8)},
+		{at(8), 2, at(10)},
+		{at(11), 1, at(12)},
+		{at(12), 2, time.Time{}},
+	} {
+		var r, next = scheduledReplication(item, tc.at)
+		require.Equal(t, tc.r, r)
+		require.Equal(t, tc.next, next)
+	}
+
+	// An Item without a schedule has its DesiredReplication.
+	var r, next = scheduledReplication(Item{ID: "other", ItemValue: testItem{R: 4}}, at(0))
+	require.Equal(t, 4, r)
+	require.True(t, next.IsZero())
+}
+
+func TestAllocateRampsAcrossScheduleBoundary(t *testing.T) {
+	var t0 = time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	defer func(f func() time.Time) { timeNow = f }(timeNow)
+	timeNow = func() time.Time { return t0 }
+
+	var ctx, client, ks = testSetup(t)
+	var value, err = json.Marshal(testItem{R: 1, T: []ScheduledReplication{
+		{Begin: t0.Add(time.Hour), End: t0.Add(2 * time.Hour), R: 2},
+	}})
+	require.NoError(t, err)
+
+	require.NoError(t, insert(ctx, client,
+		"/root/items/item-1", string(value),
+		"/root/items/item-2", string(value),
+
+		"/root/members/zone-a#A", `{"R": 2}`,
+		"/root/members/zone-b#B", `{"R": 2}`,
+	))
+	var state = NewObservedState(ks, "/root/members/zone-a#A", isConsistent)
+	require.NoError(t, ks.Load(ctx, client, 0))
+	go ks.Watch(ctx, client)
+
+	require.Equal(t, 2, state.ItemSlots)
+	require.Equal(t, t0.Add(time.Hour), state.scheduleChange)
+
+	var counts []int
+	ctx, cancel := context.WithCancel(ctx)
+
+	require.Equal(t, context.Canceled, Allocate(AllocateArgs{
+		Context: ctx,
+		Etcd:    client,
+		State:   state,
+		TestHook: func(round int, idle bool) {
+			if !idle {
+				return
+			} else if err := markAllConsistent(ctx, client, ks, ""); err == nil {
+				return
+			} else if err != io.ErrNoProgress {
+				panic(err)
+			}
+			counts = append(counts, len(state.Assignments))
+
+			if len(counts) == 1 {
+				// Advance the clock across the schedule boundary. Allocate
+				// wakes to apply the scheduled replication without a change
+				// of the KeySpace.
+				timeNow = func() time.Time { return t0.Add(time.Hour) }
+			} else {
+				cancel()
+			}
+		},
+	}))
+
+	// Expect Items were ramped from one to two Assignments.
+	require.Equal(t, []int{2, 4}, counts)
+	require.Equal(t, 4, state.ItemSlots)
+	require.Equal(t, t0.Add(2*time.Hour), state.scheduleChange)
+}
@@ -0,0 +1,175 @@
+package allocator
+
+import (
+	"fmt"
+	"sort"
+
+	"go.etcd.io/etcd/api/v3/mvccpb"
+	"go.gazette.dev/core/keyspace"
+)
+
+// MemberChange is a hypothetical change of the Member identified by Zone and
+// Suffix, as simulated by State.SimulateMemberChange.
+type MemberChange struct {
+	Zone, Suffix string
+	// MemberValue of the added or updated Member,
+	// or nil if the Member is removed.
+	MemberValue
+}
+
+// Simulation is the outcome of a simulated change of Members.
+type Simulation struct {
+	// Assignments solved for the changed Members, ordered on Item ID and then
+	// Member. Allocate would converge current Assignments towards these.
+	Assignments []Assignment
+	// Members of the current and changed State, ordered on Member key.
+	Members []SimulatedMember
+	// ItemSlots is the total desired replication of all Items. Items are
+	// under-replicated if there are fewer Assignments than ItemSlots.
+	ItemSlots int
+	// Added and Removed are the numbers of current Assignments which
+	// Assignments add and remove, respectively. Changes of an Assignment's
+	// slot aren't counted.
+	Added, Removed int
+}
+
+// SimulatedMember is the load of a Member before and after a Simulation.
+type SimulatedMember struct {
+	Zone, Suffix string
+	// Limit and SimulatedLimit are the current and changed ItemLimit of the
+	// Member, or zero if the Member doesn't exist or its zone is evacuated.
+	Limit, SimulatedLimit int
+	// Load and SimulatedLoad are the current and solved number of Assignments
+	// of the Member.
+	Load, SimulatedLoad int
+}
+
+// SimulateMemberChange solves for the Assignments which would result from
+// applying |changes| to the current Members, without changing Etcd. It's
+// intended for capacity planning, such as to ask how Items would redistribute
+// if a Member were added, and whether that would relieve loaded Members.
+//
+// The simulation uses the same solver as Allocate, under the Cost, Headroom,
+// FairShare, Pack, PackHysteresis, and WarmStart of |args|. Other fields of
+// |args| are ignored. Its Assignments are those which Allocate would converge
+// towards if it observed the changed Members.
+//
+// SimulateMemberChange read-locks the KeySpace. It must not be called while
+// the KeySpace is locked, such as from a KeySpace Observer.
+func (s *State) SimulateMemberChange(args AllocateArgs, changes ...MemberChange) (Simulation, error) {
+	if args.Headroom < 0 || args.Headroom >= 1 {
+		return Simulation{}, fmt.Errorf("invalid Headroom (%f; expected 0 <= Headroom < 1)", args.Headroom)
+	} else if args.PackHysteresis < 0 {
+		return Simulation{}, fmt.Errorf("invalid PackHysteresis (%f; expected >= 0)", args.PackHysteresis)
+	}
+	var members = make(map[string]*SimulatedMember)
+
+	s.KS.Mu.RLock()
+	var sim, err = simulatedState(s, changes)
+
+	for i := range s.Members {
+		var m = memberAt(s.Members, i)
+		members[string(s.Members[i].Raw.Key)] = &SimulatedMember{
+			Zone:   m.Zone,
+			Suffix: m.Suffix,
+			Limit:  s.memberLimits[i],
+			Load:   s.MemberTotalCount[i],
+		}
+	}
+	var current = make(map[[3]string]struct{}, len(s.Assignments))
+	for i := range s.Assignments {
+		var a = assignmentAt(s.Assignments, i)
+		current[[3]string{a.ItemID, a.MemberZone, a.MemberSuffix}] = struct{}{}
+	}
+	s.KS.Mu.RUnlock()
+
+	if err != nil {
+		return Simulation{}, err
+	}
+	// |sim| references a copy of the KeySpace, and is solved without a lock.
+	var out = Simulation{ItemSlots: sim.ItemSlots}
+	if out.Assignments, _, _, err = solve(sim, nil, args); err != nil {
+		return Simulation{}, err
+	}
+
+	for i := range sim.Members {
+		var key = string(sim.Members[i].Raw.Key)
+		var sm, ok = members[key]
+		if !ok {
+			var m = memberAt(sim.Members, i)
+			sm = &SimulatedMember{Zone: m.Zone, Suffix: m.Suffix}
+			members[key] = sm
+		}
+		sm.SimulatedLimit = sim.memberLimits[i]
+	}
+	for _, a := range out.Assignments {
+		members[MemberKey(sim.KS, a.MemberZone, a.MemberSuffix)].SimulatedLoad++
+
+		var key = [3]string{a.ItemID, a.MemberZone, a.MemberSuffix}
+		if _, ok := current[key]; ok {
+			delete(current, key)
+		} else {
+			out.Added++
+		}
+	}
+	out.Removed = len(current)
+
+	var keys = make([]string, 0, len(members))
+	for key := range members {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		out.Members = append(out.Members, *members[key])
+	}
+	return out, nil
+}
+
+// simulatedState returns a State extracted from a copy of the KeySpace of
+// |s|, to which Member |changes| are applied. The KeySpace must be read-locked.
+func simulatedState(s *State, changes []MemberChange) (*State, error) {
+	var kvs = s.KS.KeyValues.Copy()
+
+	for _, change := range changes {
+		var key = MemberKey(s.KS, change.Zone, change.Suffix)
+		var ind, found = kvs.Search(key)
+
+		if change.MemberValue == nil {
+			if !found {
+				return nil, fmt.Errorf("member %s not found", key)
+			}
+			kvs = append(kvs[:ind], kvs[ind+1:]...)
+			continue
+		}
+		var kv = keyspace.KeyValue{
+			Raw: mvccpb.KeyValue{Key: []byte(key)},
+			Decoded: Member{
+				Zone:        change.Zone,
+				Suffix:      change.Suffix,
+				MemberValue: change.MemberValue,
+			},
+		}
+		if found {
+			kv.Raw = kvs[ind].Raw
+			kvs[ind] = kv
+		} else {
+			kvs = append(kvs, keyspace.KeyValue{})
+			copy(kvs[ind+1:], kvs[ind:])
+			kvs[ind] = kv
+		}
+	}
+
+	var sim = &State{
+		KS: &keyspace.KeySpace{
+			Root:      s.KS.Root,
+			Header:    s.KS.Header,
+			KeyValues: kvs,
+		},
+		LocalKey:     s.LocalKey,
+		IsConsistent: s.IsConsistent,
+	}
+	sim.observe()
+
+	return sim, nil
+}
@@ -0,0 +1,82 @@
+package allocator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSimulateMemberChange(t *testing.T) {
+	var ctx, client, ks = testSetup(t)
+	require.NoError(t, insert(ctx, client,
+		"/root/items/item-1", `{"R": 2}`,
+		"/root/items/item-2", `{"R": 2}`,
+		"/root/items/item-3", `{"R": 1}`,
+
+		"/root/members/zone-a#A", `{"R": 3}`,
+		"/root/members/zone-b#B", `{"R": 3}`,
+
+		"/root/assign/item-1#zone-a#A#0", `consistent`,
+		"/root/assign/item-1#zone-b#B#1", `consistent`,
+		"/root/assign/item-2#zone-a#A#0", `consistent`,
+		"/root/assign/item-2#zone-b#B#1", `consistent`,
+		"/root/assign/item-3#zone-a#A#0", `consistent`,
+	))
+	var state = NewObservedState(ks, "/root/members/zone-a#A", isConsistent)
+	require.NoError(t, ks.Load(ctx, client, 0))
+
+	// Case: adding a member in zone-a relieves member A.
+	var sim, err = state.SimulateMemberChange(AllocateArgs{},
+		MemberChange{Zone: "zone-a", Suffix: "C", MemberValue: testMember{R: 3}})
+	require.NoError(t, err)
+
+	require.Equal(t, 5, sim.ItemSlots)
+	require.Len(t, sim.Assignments, 5)
+	require.Equal(t, []SimulatedMember{
+		{Zone: "zone-a", Suffix: "A", Limit: 3, SimulatedLimit: 3, Load: 3, SimulatedLoad: 2},
+		{Zone: "zone-a", Suffix: "C", Limit: 0, SimulatedLimit: 3, Load: 0, SimulatedLoad: 1},
+		{Zone: "zone-b", Suffix: "B", Limit: 3, SimulatedLimit: 3, Load: 2, SimulatedLoad: 2},
+	}, sim.Members)
+	require.Equal(t, 1, sim.Added)
+	require.Equal(t, 1, sim.Removed)
+
+	// Case: the simulation didn't change the observed State.
+	require.Len(t, state.Members, 2)
+	require.Equal(t, []int{3, 2}, state.MemberTotalCount)
+
+	// Case: removing member B leaves Items under-replicated,
+	// as each of A's Items may be assigned to A only once.
+	sim, err = state.SimulateMemberChange(AllocateArgs{},
+		MemberChange{Zone: "zone-b", Suffix: "B"})
+	require.NoError(t, err)
+
+	require.Less(t, len(sim.Assignments), sim.ItemSlots)
+	require.Equal(t, SimulatedMember{Zone: "zone-b", Suffix: "B", Limit: 3, Load: 2}, sim.Members[1])
+	require.Equal(t, 0, sim.Added)
+	require.Equal(t, 5-len(sim.Assignments), sim.Removed)
+
+	// Case: replacing B with a member of zone-a fully assigns Items within zone-a.
+	sim, err = state.SimulateMemberChange(AllocateArgs{},
+		MemberChange{Zone: "zone-a", Suffix: "A", MemberValue: testMember{R: 4}},
+		MemberChange{Zone: "zone-a", Suffix: "D", MemberValue: testMember{R: 2}},
+		MemberChange{Zone: "zone-b", Suffix: "B"})
+	require.NoError(t, err)
+
+	require.Len(t, sim.Assignments, 5)
+	require.Equal(t, SimulatedMember{Zone: "zone-a", Suffix: "A", Limit: 3, SimulatedLimit: 4, Load: 3, SimulatedLoad: 3}, sim.Members[0])
+	require.Equal(t, 2, sim.Added)
+	require.Equal(t, 2, sim.Removed)
+
+	// Case: headroom is reserved as it is by Allocate.
+	sim, err = state.SimulateMemberChange(AllocateArgs{Headroom: 0.5})
+	require.NoError(t, err)
+	require.Len(t, sim.Assignments, 2)
+
+	// Case: errors.
+	_, err = state.SimulateMemberChange(AllocateArgs{},
+		MemberChange{Zone: "zone-c", Suffix: "missing"})
+	require.EqualError(t, err, "member /root/members/zone-c#missing not found")
+
+	_, err = state.SimulateMemberChange(AllocateArgs{Headroom: 1})
+	require.EqualError(t, err, "invalid Headroom (1.000000; expected 0 <= Headroom < 1)")
+}
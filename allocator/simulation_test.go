@@ -0,0 +1,191 @@
+package allocator
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"go.gazette.dev/core/keyspace"
+)
+
+// TestSimulatedMemberFailures runs seeded simulations of random Member
+// failures, and verifies that the Allocator re-converges to a valid
+// allocation within a bounded number of rounds after each failure. A
+// failed simulation logs a trace, and may be re-run by its seed, eg:
+//
+//	go test ./allocator -run 'TestSimulatedMemberFailures/seed=3$'
+func TestSimulatedMemberFailures(t *testing.T) {
+	for seed := int64(1); seed <= 8; seed++ {
+		t.Run(fmt.Sprintf("seed=%d", seed), func(t *testing.T) {
+			simulateMemberFailures(t, seed, 6)
+		})
+	}
+}
+
+// simulateMemberFailures builds a random fixture of Items and Members from
+// |seed|, and then runs |steps| rounds in which a random Member fails and a
+// replacement Member may join. Members fail by the revocation of their Etcd
+// lease, which also removes all of their Assignments.
+func simulateMemberFailures(t *testing.T, seed int64, steps int) {
+	// The Allocator should converge within a small number of rounds. This bound
+	// is generous, and guards against failures to converge (eg, oscillation).
+	const maxRounds = 20
+
+	var ctx, client, ks = testSetup(t)
+	var rng = rand.New(rand.NewSource(seed))
+	var sim = &simulation{
+		t:      t,
+		ctx:    ctx,
+		client: client,
+		ks:     ks,
+		rng:    rng,
+		leases: make(map[string]clientv3.LeaseID),
+		nItems: 5 + rng.Intn(10),
+	}
+	defer func() {
+		if t.Failed() {
+			t.Logf("simulation trace (seed %d):\n%s", seed, strings.Join(sim.trace, "\n"))
+		}
+	}()
+
+	for i := 0; i != sim.nItems; i++ {
+		var key, value = fmt.Sprintf("/root/items/item-%02d", i), fmt.Sprintf(`{"R": %d}`, 1+rng.Intn(3))
+		require.NoError(t, insert(ctx, client, key, value))
+		sim.tracef("item %s: %s", key, value)
+	}
+	for i, n := 0, 3+rng.Intn(4); i != n; i++ {
+		sim.addMember()
+	}
+
+	for step := 0; true; step++ {
+		var rounds = serveUntilIdle(t, ctx, client, ks, "")
+		sim.tracef("converged after %d rounds:\n\t%s", rounds,
+			strings.Join(keys(ks.Prefixed(ks.Root+AssignmentsPrefix)), "\n\t"))
+
+		require.LessOrEqual(t, rounds, maxRounds, "allocator didn't converge")
+		sim.verify()
+
+		if step == steps {
+			break
+		}
+		// Fail a random Member. Usually, but not always, a replacement joins.
+		// As Members may outnumber replacements, the simulation may arrive at
+		// a state which cannot fully replicate Items.
+		var members = ks.Prefixed(ks.Root + MembersPrefix)
+		var failed = string(members[rng.Intn(len(members))].Raw.Key)
+
+		_, err := client.Revoke(ctx, sim.leases[failed])
+		require.NoError(t, err)
+		sim.tracef("member %s failed", failed)
+
+		if len(members) == 1 || rng.Intn(3) != 0 {
+			sim.addMember()
+		}
+	}
+}
+
+type simulation struct {
+	t      *testing.T
+	ctx    context.Context
+	client *clientv3.Client
+	ks     *keyspace.KeySpace
+	rng    *rand.Rand
+	leases map[string]clientv3.LeaseID // Lease of each Member key.
+	nItems int
+	next   int      // Next Member ID.
+	trace  []string // Events of the simulation.
+}
+
+func (s *simulation) tracef(format string, args ...interface{}) {
+	s.trace = append(s.trace, fmt.Sprintf(format, args...))
+}
+
+// addMember adds a Member to a random zone under a new lease. Each Member
+// has capacity for at least one replica of every Item.
+func (s *simulation) addMember() {
+	var key = fmt.Sprintf("/root/members/zone-%c#member-%02d", 'a'+s.rng.Intn(3), s.next)
+	var value = fmt.Sprintf(`{"R": %d}`, s.nItems+s.rng.Intn(s.nItems))
+	s.next++
+
+	var lease, err = s.client.Grant(s.ctx, 3600)
+	require.NoError(s.t, err)
+	_, err = s.client.Put(s.ctx, key, value, clientv3.WithLease(lease.ID))
+	require.NoError(s.t, err)
+
+	s.leases[key] = lease.ID
+	s.tracef("member %s: %s joined", key, value)
+}
+
+// verify that the converged allocation is valid. If the Members have capacity
+// to fully replicate every Item, then verify that every Item is.
+func (s *simulation) verify() {
+	var (
+		members     = s.ks.Prefixed(s.ks.Root + MembersPrefix)
+		items       = s.ks.Prefixed(s.ks.Root + ItemsPrefix)
+		assignments = s.ks.Prefixed(s.ks.Root + AssignmentsPrefix)
+		limits      = make(map[string]int)             // Member zone#suffix => ItemLimit.
+		counts      = make(map[string]int)             // Member zone#suffix => Assignments.
+		itemZones   = make(map[string][]string)        // Item ID => zones.
+		itemMembers = make(map[string]map[string]bool) // Item ID => Members.
+		zones       = make(map[string]bool)
+		maxR        int
+	)
+	for _, kv := range members {
+		var m = kv.Decoded.(Member)
+		limits[m.Zone+"#"+m.Suffix] = m.ItemLimit()
+		zones[m.Zone] = true
+	}
+	for _, kv := range assignments {
+		var a = kv.Decoded.(Assignment)
+		var member = a.MemberZone + "#" + a.MemberSuffix
+
+		_, ok := limits[member]
+		require.True(s.t, ok, "assignment %s of a missing member", kv.Raw.Key)
+		require.False(s.t, itemMembers[a.ItemID][member], "item %s is assigned twice to %s", a.ItemID, member)
+
+		if itemMembers[a.ItemID] == nil {
+			itemMembers[a.ItemID] = make(map[string]bool)
+		}
+		itemMembers[a.ItemID][member] = true
+		itemZones[a.ItemID] = append(itemZones[a.ItemID], a.MemberZone)
+		counts[member]++
+	}
+	for member, count := range counts {
+		require.LessOrEqual(s.t, count, limits[member], "member %s exceeds its ItemLimit", member)
+	}
+	for _, kv := range items {
+		var item = kv.Decoded.(Item)
+		require.LessOrEqual(s.t, len(itemMembers[item.ID]), item.DesiredReplication(),
+			"item %s is over-replicated", item.ID)
+
+		if r := item.DesiredReplication(); r > maxR {
+			maxR = r
+		}
+	}
+
+	// Every Member can hold a replica of every Item, so Items can be fully
+	// replicated across two zones if there are at least two zones and
+	// enough Members for the largest desired replication.
+	if len(zones) < 2 || len(members) < maxR {
+		s.tracef("capacity doesn't permit full replication")
+		return
+	}
+	for _, kv := range items {
+		var item = kv.Decoded.(Item)
+		var r = item.DesiredReplication()
+
+		require.Equal(s.t, r, len(itemMembers[item.ID]), "item %s is under-replicated", item.ID)
+
+		if r >= 2 {
+			var spans = make(map[string]bool)
+			for _, zone := range itemZones[item.ID] {
+				spans[zone] = true
+			}
+			require.GreaterOrEqual(s.t, len(spans), 2, "item %s doesn't span zones", item.ID)
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package allocator
 
 import (
+	"fmt"
 	"sort"
 	"strings"
 
@@ -45,6 +46,14 @@ import (
 //     assignments and balancing evenly across zones are also expressed.
 //   - A preference for current assignments is reflected in arcs from Zone Items
 //     to Members.
+//   - Spread constraints of Items (see SpreadItemValue) are captured by "Spread"
+//     nodes between the Zone Items and Members of such Items: one for each
+//     distinct attribute value of the zone's Members, having a single arc of
+//     unit capacity from its Zone Item, and arcs to each Member of the value.
+//   - Affinities of Items (see AffinityItemValue) are expressed by arcs from
+//     Zone Items to Members, which prefer Members of the anchor Item of the
+//     Item's affinity group, or are restricted to them (for hard affinities)
+//     until the Zone Item overflows.
 //   - Desired "fair share" scaled capacity and upper-bound capacity is reflected
 //     by arcs from Members to the Sink.
 //
@@ -66,6 +75,7 @@ type sparseFlowNetwork struct {
 	firstItemNodeID     pr.NodeID // First Item NodeID in the graph.
 	firstZoneItemNodeID pr.NodeID // First Zone-Item NodeID in the graph.
 	firstMemberNodeID   pr.NodeID // First Member NodeID in the graph.
+	firstSpreadNodeID   pr.NodeID // First Spread NodeID in the graph.
 
 	// For each zone-item, the offset into State.Assignments of its first assignment.
 	zoneItemAssignments []keyspace.KeyValues
@@ -74,6 +84,45 @@ type sparseFlowNetwork struct {
 	// For each zone, a slice of Arcs to all members of that zone.
 	allZoneItemArcsByZone [][]pr.Arc
 
+	// For each of |myItems|, its spread attribute. Nil if no Items have one.
+	itemSpread []string
+	// For each zone-item of an Item having a spread attribute, Arcs to each of
+	// its Spread nodes. Nil if no Items have a spread attribute.
+	zoneItemSpreadArcs [][]pr.Arc
+	// For each Spread node, its zone-item and Arcs to each Member of its value.
+	spreadZoneItem   []int
+	spreadMemberArcs [][]pr.Arc
+	// For each Spread node, Arcs to Members of its value ordered on ascending
+	// cost. Built lazily, and only if |cost| is set.
+	costSpreadArcs [][]pr.Arc
+
+	// For each zone-item of an Item having an affinity, Arcs to each Member of
+	// the zone which is assigned the anchor of the Item's affinity group.
+	// Nil if the network doesn't model affinities.
+	zoneItemAffineArcs [][]pr.Arc
+	// For each of |myItems|, whether it has a hard affinity having an anchor.
+	// Nil if the network doesn't model affinities.
+	itemHardAffinity []bool
+
+	// Optional CostFunc of Assignments.
+	cost CostFunc
+	// For each zone-item, Arcs to all members of its zone ordered on affinity
+	// and then ascending cost. Built lazily, and only if |cost| is set or the
+	// zone-item has an affinity.
+	orderedZoneItemArcs [][]pr.Arc
+	// First error encountered in evaluating |cost|, if any.
+	costErr error
+
+	// Fraction of Member ItemLimits held in reserve.
+	headroom float64
+	// Total slots summed across all Members, after reserving |headroom|.
+	memberSlots int
+	// Optional Members onto which Items are packed (see AllocateArgs.Pack).
+	// If set, other Members have no capacity.
+	packed []bool
+	// Optional fair-share replication of each of |myItems| (see fairShares).
+	itemShares []int
+
 	// scratch is a small slice of Arcs for (re)use without allocating. We'll
 	// want up-to the number of zones, or the number of Assignments of an Item
 	// within a zone -- both should be small, but if we overflow that's fine,
@@ -112,6 +161,13 @@ const (
 	// that height is len(nodes) + 1, but we use len(nodes) - 1 to give the
 	// solver time to fully explore these overflow heuristics.
 	memberOverflowThreshold = -1
+	// Zone-Items of an Item having a hard affinity present Arcs only to Members
+	// of their anchor, until they reach a RelativeHeight of -1, at which point
+	// they also present all other Members of the zone. Like Members, we would
+	// rather relax an affinity than cause an Item to overflow, and
+	// this is also the height to which the label gap heuristic relabels
+	// Zone-Items which are cut off from the sink by their affinity.
+	affinityOverflowThreshold = -1
 
 	pageItemArcsUniform    = pr.PageInitial + 1
 	pageItemArcsRMinusOne  = pageItemArcsUniform + 1
@@ -127,7 +183,8 @@ func newSparseFlowNetwork(s *State, myItems keyspace.KeyValues) *sparseFlowNetwo
 	//  - Sink node, then
 	//  - Item Nodes, then
 	//  - Zone-Item Nodes, then
-	//  - Member Nodes.
+	//  - Member Nodes, then
+	//  - Spread Nodes.
 	var firstItemNodeID = pr.SinkID + 1 // == 2.
 	var firstZoneItemNodeID = firstItemNodeID + pr.NodeID(len(myItems))
 	var firstMemberNodeID = firstZoneItemNodeID + pr.NodeID(len(myItems)*len(s.Zones))
@@ -202,24 +259,150 @@ func newSparseFlowNetwork(s *State, myItems keyspace.KeyValues) *sparseFlowNetwo
 		zoneItemAssignments:   zoneItemAssignments,
 		memberSuffixIdxByZone: memberSuffixIdxByZone,
 		allZoneItemArcsByZone: allZoneItemArcsByZone,
+		memberSlots:           s.MemberSlots,
 	}
+	fs.firstSpreadNodeID = firstMemberNodeID + pr.NodeID(len(s.Members))
+	fs.buildSpreadNodes()
+
 	return fs
 }
 
+// buildSpreadNodes builds a Spread node for each distinct attribute value of
+// the Members of each zone, for each of |myItems| having a spread attribute.
+// Members lacking the attribute have no Spread node, and aren't reachable
+// by the Item.
+func (fs *sparseFlowNetwork) buildSpreadNodes() {
+	var lz = len(fs.Zones)
+
+	for item := range fs.myItems {
+		var attr = spreadAttribute(itemAt(fs.myItems, item))
+		if attr == "" {
+			continue
+		} else if fs.itemSpread == nil {
+			fs.itemSpread = make([]string, len(fs.myItems))
+			fs.zoneItemSpreadArcs = make([][]pr.Arc, len(fs.myItems)*lz)
+		}
+		fs.itemSpread[item] = attr
+
+		for zone := 0; zone != lz; zone++ {
+			var zoneItem = item*lz + zone
+			var nodes = make(map[string]int) // Attribute value => Spread node index.
+			var arcs = []pr.Arc{}
+
+			for _, arc := range fs.allZoneItemArcsByZone[zone] {
+				var member = memberAt(fs.Members, int(arc.To-fs.firstMemberNodeID))
+				var value = memberAttribute(member, attr)
+
+				if value == "" {
+					continue
+				}
+				var ind, ok = nodes[value]
+				if !ok {
+					ind = len(fs.spreadZoneItem)
+					nodes[value] = ind
+
+					fs.spreadZoneItem = append(fs.spreadZoneItem, zoneItem)
+					fs.spreadMemberArcs = append(fs.spreadMemberArcs, nil)
+					arcs = append(arcs, pr.Arc{To: fs.firstSpreadNodeID + pr.NodeID(ind), Capacity: 1})
+				}
+				fs.spreadMemberArcs[ind] = append(fs.spreadMemberArcs[ind], arc)
+			}
+			fs.zoneItemSpreadArcs[zoneItem] = arcs
+		}
+	}
+}
+
+// buildAffinityArcs builds Arcs from each zone-item of |myItems| having an
+// affinity to the Members of its zone which are assigned the anchor Item of
+// its affinity group, under |anchors|. Items having a spread attribute have
+// no affinity Arcs.
+func (fs *sparseFlowNetwork) buildAffinityArcs(anchors map[string]affinityAnchor) {
+	var lz = len(fs.Zones)
+
+	for item := range fs.myItems {
+		var v = itemAt(fs.myItems, item)
+		var groups, hard = affinity(v)
+
+		if len(groups) == 0 || spreadAttribute(v) != "" {
+			continue
+		}
+		var anchor, ok = anchors[groups[0]]
+		if !ok || len(anchor.Assignments) == 0 {
+			continue
+		} else if fs.zoneItemAffineArcs == nil {
+			fs.zoneItemAffineArcs = make([][]pr.Arc, len(fs.myItems)*lz)
+			fs.itemHardAffinity = make([]bool, len(fs.myItems))
+		}
+		fs.itemHardAffinity[item] = hard
+
+		for _, a := range anchor.Assignments {
+			var zone = sort.SearchStrings(fs.Zones, a.MemberZone)
+
+			if zone == lz || fs.Zones[zone] != a.MemberZone {
+				continue // Zone has no Members with slots.
+			} else if id, ok := fs.memberSuffixIdxByZone[zone][a.MemberSuffix]; ok {
+				fs.zoneItemAffineArcs[item*lz+zone] = append(fs.zoneItemAffineArcs[item*lz+zone],
+					pr.Arc{To: id, Capacity: 1})
+			}
+		}
+	}
+}
+
+// affineArcs returns Arcs of zone-item |zoneItem| to Members of its anchor.
+func (fs *sparseFlowNetwork) affineArcs(zoneItem int) []pr.Arc {
+	if fs.zoneItemAffineArcs == nil {
+		return nil
+	}
+	return fs.zoneItemAffineArcs[zoneItem]
+}
+
+// isHardAffine returns true if the Item has a hard affinity having an anchor.
+func (fs *sparseFlowNetwork) isHardAffine(item int) bool {
+	return fs.itemHardAffinity != nil && fs.itemHardAffinity[item]
+}
+
+// isAffine returns true if Member node |member| is assigned the anchor of
+// zone-item |zoneItem|.
+func (fs *sparseFlowNetwork) isAffine(zoneItem int, member pr.NodeID) bool {
+	for _, arc := range fs.affineArcs(zoneItem) {
+		if arc.To == member {
+			return true
+		}
+	}
+	return false
+}
+
 func (fs *sparseFlowNetwork) Nodes() int {
-	return int(fs.firstMemberNodeID) + len(fs.Members)
+	return int(fs.firstSpreadNodeID) + len(fs.spreadZoneItem)
 }
 
 func (fs *sparseFlowNetwork) InitialHeight(id pr.NodeID) pr.Height {
+	// Items having a spread attribute are one node further from the Sink.
 	if id < fs.firstZoneItemNodeID {
-		return 3 // Item node.
+		return 3 + fs.spreadHeight(int(id-fs.firstItemNodeID)) // Item node.
 	} else if id < fs.firstMemberNodeID {
-		return 2 // Zone-Item node.
-	} else {
+		var zoneItem = int(id - fs.firstZoneItemNodeID)
+		return 2 + fs.spreadHeight(zoneItem/len(fs.Zones)) // Zone-Item node.
+	} else if id < fs.firstSpreadNodeID {
 		return 1 // Member node.
+	} else {
+		return 2 // Spread node.
 	}
 }
 
+// spreadHeight returns 1 if the Item has a spread attribute, or 0 otherwise.
+func (fs *sparseFlowNetwork) spreadHeight(item int) pr.Height {
+	if fs.isSpread(item) {
+		return 1
+	}
+	return 0
+}
+
+// isSpread returns true if the Item has a spread attribute.
+func (fs *sparseFlowNetwork) isSpread(item int) bool {
+	return fs.itemSpread != nil && fs.itemSpread[item] != ""
+}
+
 func (fs *sparseFlowNetwork) Arcs(mf *pr.MaxFlow, id pr.NodeID, page pr.PageToken) ([]pr.Arc, pr.PageToken) {
 	if id == pr.SourceID {
 		return fs.buildSourceArcs(), pr.PageEOF // Arcs from the Source to each Item.
@@ -231,7 +414,7 @@ func (fs *sparseFlowNetwork) Arcs(mf *pr.MaxFlow, id pr.NodeID, page pr.PageToke
 
 	} else if id < fs.firstZoneItemNodeID {
 		var item = int(id - fs.firstItemNodeID)
-		var r = itemAt(fs.myItems, item).DesiredReplication()
+		var r = fs.desiredReplication(itemAt(fs.myItems, item))
 
 		// Enumerate Arcs from the Item to each of its Zone-Item Nodes.
 		// If there is only one zone, or we will next push back to the Source,
@@ -265,31 +448,75 @@ func (fs *sparseFlowNetwork) Arcs(mf *pr.MaxFlow, id pr.NodeID, page pr.PageToke
 		// - Arcs which represent the total set of zone Members.
 		// Intuitively: we prefer to keep current Member Assignments, but will allow
 		// a new assignment to any of the zone's Members.
-		switch page {
-		case pr.PageInitial:
+		// Zone-Items of an Item having a spread attribute do the same, but
+		// through the Spread nodes of the zone's attribute values.
+		// Zone-Items of an Item having a hard affinity are restricted to
+		// Members of their anchor, until they overflow.
+		var spread = fs.isSpread(zoneItem / len(fs.Zones))
+		var affine = fs.isHardAffine(zoneItem/len(fs.Zones)) &&
+			mf.RelativeHeight(id) < affinityOverflowThreshold
+
+		switch {
+		case page == pr.PageInitial && spread:
+			return fs.buildCurrentSpreadArcs(zoneItem), pageZoneItemAllMembers
+		case page == pr.PageInitial && affine:
+			return fs.buildCurrentAffineArcs(zoneItem), pageZoneItemAllMembers
+		case page == pr.PageInitial:
 			return fs.buildCurrentZoneItemArcs(zoneItem), pageZoneItemAllMembers
-		case pageZoneItemAllMembers:
-			return fs.allZoneItemArcsByZone[zoneItem%len(fs.Zones)], pr.PageEOF
+		case page == pageZoneItemAllMembers && spread:
+			return fs.zoneItemSpreadArcs[zoneItem], pr.PageEOF
+		case page == pageZoneItemAllMembers && affine:
+			return fs.affineArcs(zoneItem), pr.PageEOF
+		case page == pageZoneItemAllMembers:
+			return fs.buildAllZoneItemArcs(zoneItem), pr.PageEOF
 		default:
 			panic("invalid PageToken")
 		}
-	} else {
+	} else if id < fs.firstSpreadNodeID {
 		var member = int(id - fs.firstMemberNodeID)
 		return fs.buildMemberArc(mf, id, member), pr.PageEOF
+	} else {
+		var spread = int(id - fs.firstSpreadNodeID)
+
+		// Like Zone-Items, prefer current Assignments of the Spread node's
+		// Members, but allow a new assignment to any of them.
+		switch page {
+		case pr.PageInitial:
+			return fs.buildCurrentSpreadMemberArcs(spread), pageZoneItemAllMembers
+		case pageZoneItemAllMembers:
+			return fs.buildAllSpreadMemberArcs(spread), pr.PageEOF
+		default:
+			panic("invalid PageToken")
+		}
+	}
+}
+
+// OrderedArcs returns true for the page of Arcs from a Zone-Item or Spread
+// node to all of its Members, if a CostFunc is set or the Zone-Item has an
+// affinity, so that its ordering is preserved.
+func (fs *sparseFlowNetwork) OrderedArcs(id pr.NodeID, page pr.PageToken) bool {
+	if page != pageZoneItemAllMembers || id < fs.firstZoneItemNodeID {
+		return false
+	} else if id < fs.firstMemberNodeID {
+		return fs.cost != nil || len(fs.affineArcs(int(id-fs.firstZoneItemNodeID))) != 0
 	}
+	return fs.cost != nil && id >= fs.firstSpreadNodeID
 }
 
 // buildSourceArcs enumerates an Arc for each Item node, nominally having capacity
-// of the Item's desired replication. If the total number of Item slots greatly
-// exceeds Member slots, this degrades the performance and stability of the push/
-// relabel solver; we therefore globally bound Item capacities to the number of
-// available Member slots.
+// of the Item's desired replication (or its fair share, if |itemShares| is set).
+// If the total number of Item slots greatly exceeds Member slots, this degrades
+// the performance and stability of the push/relabel solver; we therefore globally
+// bound Item capacities to the number of available Member slots.
 func (fs *sparseFlowNetwork) buildSourceArcs() []pr.Arc {
 	var arcs = make([]pr.Arc, len(fs.myItems))
-	var remaining = fs.MemberSlots
+	var remaining = fs.memberSlots
 
 	for item := range fs.myItems {
-		var c = itemAt(fs.myItems, item).DesiredReplication()
+		var c = fs.desiredReplication(itemAt(fs.myItems, item))
+		if fs.itemShares != nil {
+			c = fs.itemShares[item]
+		}
 
 		if c > remaining {
 			c = remaining
@@ -347,23 +574,56 @@ func (fs *sparseFlowNetwork) buildCurrentItemArcs(item int, bound int) []pr.Arc
 
 // buildMemberArc from member `member` to the sink.
 func (fs *sparseFlowNetwork) buildMemberArc(mf *pr.MaxFlow, id pr.NodeID, member int) []pr.Arc {
-	var c = memberAt(fs.Members, member).ItemLimit()
+	fs.scratch[0] = pr.Arc{
+		To:       pr.SinkID,
+		Capacity: pr.Rate(fs.memberCapacity(member, mf.RelativeHeight(id) >= memberOverflowThreshold)),
+	}
+	return fs.scratch[:1]
+}
+
+// memberCapacity returns the capacity of the Arc from |member| to the sink.
+func (fs *sparseFlowNetwork) memberCapacity(member int, overflow bool) int {
+	if fs.packed != nil && !fs.packed[member] {
+		return 0
+	}
+	var c = fs.effectiveItemLimit(member, fs.headroom)
 	// Constrain to the scaled ItemLimit for our portion of the global assignment problem.
 	c = scaleAndRound(c, len(fs.myItems), len(fs.Items))
 
-	if mf.RelativeHeight(id) < memberOverflowThreshold {
+	if !overflow {
 		// Further scale to our relative "fair share" items.
 		// Intuitively, the Member node will resist having more than its fair share of
 		// assignments until sufficient pressure builds within the network to indicate
 		// that not all assignments can otherwise be made, at which point we'll
 		// allow assignments up to our (scaled) full capacity.
-		c = scaleAndRound(c, fs.ItemSlots, fs.MemberSlots)
+		c = scaleAndRound(c, fs.ItemSlots, fs.memberSlots)
 	}
-	fs.scratch[0] = pr.Arc{
-		To:       pr.SinkID,
-		Capacity: pr.Rate(c),
+	return c
+}
+
+// reserveHeadroom reduces the capacity of each Member to its ItemLimit
+// less the |headroom| fraction, which is held in reserve.
+func (fs *sparseFlowNetwork) reserveHeadroom(headroom float64) {
+	fs.headroom, fs.memberSlots = headroom, memberSlots(fs.State, headroom)
+}
+
+// packMembers limits Items to being assigned to |packed| Members.
+func (fs *sparseFlowNetwork) packMembers(packed []bool) {
+	fs.packed, fs.memberSlots = packed, 0
+	for m := range fs.Members {
+		if packed[m] {
+			fs.memberSlots += fs.effectiveItemLimit(m, fs.headroom)
+		}
 	}
-	return fs.scratch[:1]
+}
+
+// memberSlots returns the total slots of State Members, after reserving |headroom|.
+func memberSlots(s *State, headroom float64) int {
+	var slots int
+	for m := range s.Members {
+		slots += s.effectiveItemLimit(m, headroom)
+	}
+	return slots
 }
 
 // buildCurrentZoneItemArcs from zone-item |zoneItem| to each Member node of the
@@ -385,6 +645,243 @@ func (fs *sparseFlowNetwork) buildCurrentZoneItemArcs(zoneItem int) []pr.Arc {
 	return arcs
 }
 
+// buildCurrentAffineArcs from zone-item |zoneItem| to each Member node of the
+// zone having a current assignment, and which is assigned the zone-item's anchor.
+func (fs *sparseFlowNetwork) buildCurrentAffineArcs(zoneItem int) []pr.Arc {
+	var arcs = fs.buildCurrentZoneItemArcs(zoneItem)
+	var out = arcs[:0]
+
+	for _, arc := range arcs {
+		if fs.isAffine(zoneItem, arc.To) {
+			out = append(out, arc)
+		}
+	}
+	return out
+}
+
+// buildAllZoneItemArcs from zone-item |zoneItem| to each Member node of the
+// zone. Arcs are in Member order, unless a CostFunc is set or the zone-item
+// has an affinity, in which case Members of the zone-item's anchor are
+// ordered first, and then on ascending cost so that the solver tries
+// lower-cost Members first.
+func (fs *sparseFlowNetwork) buildAllZoneItemArcs(zoneItem int) []pr.Arc {
+	var zone = zoneItem % len(fs.Zones)
+
+	if fs.cost == nil && len(fs.affineArcs(zoneItem)) == 0 {
+		return fs.allZoneItemArcsByZone[zone]
+	} else if fs.orderedZoneItemArcs == nil {
+		fs.orderedZoneItemArcs = make([][]pr.Arc, len(fs.myItems)*len(fs.Zones))
+	}
+	if arcs := fs.orderedZoneItemArcs[zoneItem]; arcs != nil {
+		return arcs
+	}
+
+	var (
+		item  = itemAt(fs.myItems, zoneItem/len(fs.Zones))
+		arcs  = append([]pr.Arc(nil), fs.allZoneItemArcsByZone[zone]...)
+		costs = make(map[pr.NodeID]int, len(arcs))
+	)
+	for _, arc := range arcs {
+		if fs.cost == nil {
+			break
+		}
+		var member = memberAt(fs.Members, int(arc.To-fs.firstMemberNodeID))
+		var c = fs.cost(fs.State, member, item)
+
+		if c < 0 || c > MaxAssignmentCost {
+			if fs.costErr == nil {
+				fs.costErr = fmt.Errorf("invalid cost %d of item %s to member %s/%s (must be in [0, %d])",
+					c, item.ID, member.Zone, member.Suffix, MaxAssignmentCost)
+			}
+			c = MaxAssignmentCost
+		}
+		costs[arc.To] = c
+	}
+	// Stable sort, so that equal affinities and costs retain Member order.
+	sort.SliceStable(arcs, func(i, j int) bool {
+		if ai, aj := fs.isAffine(zoneItem, arcs[i].To), fs.isAffine(zoneItem, arcs[j].To); ai != aj {
+			return ai
+		}
+		return costs[arcs[i].To] < costs[arcs[j].To]
+	})
+
+	fs.orderedZoneItemArcs[zoneItem] = arcs
+	return arcs
+}
+
+// buildCurrentSpreadArcs from zone-item |zoneItem| to each Spread node having
+// a Member with a current assignment of the zone-item.
+func (fs *sparseFlowNetwork) buildCurrentSpreadArcs(zoneItem int) []pr.Arc {
+	var (
+		arcs = fs.scratch[:0]
+		zone = zoneItem % len(fs.Zones)
+	)
+	for _, a := range fs.zoneItemAssignments[zoneItem] {
+		var memberID, ok = fs.memberSuffixIdxByZone[zone][a.Decoded.(Assignment).MemberSuffix]
+		if !ok {
+			continue
+		}
+		var id, found = fs.spreadNodeOf(zoneItem, memberID)
+		if !found {
+			continue
+		}
+		// Current Assignments may violate the spread constraint (eg, if it was
+		// just added). Don't present an Arc to the same Spread node twice.
+		var dup bool
+		for _, arc := range arcs {
+			dup = dup || arc.To == id
+		}
+		if !dup {
+			arcs = append(arcs, pr.Arc{
+				To:        id,
+				Capacity:  1,
+				PushFront: true,
+			})
+		}
+	}
+	return arcs
+}
+
+// buildCurrentSpreadMemberArcs from Spread node |spread| to each of its
+// Members having a current assignment of its zone-item.
+func (fs *sparseFlowNetwork) buildCurrentSpreadMemberArcs(spread int) []pr.Arc {
+	var (
+		arcs     = fs.scratch[:0]
+		zoneItem = fs.spreadZoneItem[spread]
+		zone     = zoneItem % len(fs.Zones)
+	)
+	for _, a := range fs.zoneItemAssignments[zoneItem] {
+		var id, ok = fs.memberSuffixIdxByZone[zone][a.Decoded.(Assignment).MemberSuffix]
+		if !ok {
+			continue
+		}
+		for _, arc := range fs.spreadMemberArcs[spread] {
+			if arc.To == id {
+				arcs = append(arcs, pr.Arc{
+					To:        id,
+					Capacity:  1,
+					PushFront: true,
+				})
+			}
+		}
+	}
+	return arcs
+}
+
+// buildAllSpreadMemberArcs from Spread node |spread| to each of its Members.
+// Like buildAllZoneItemArcs, Arcs are in Member order unless a CostFunc is
+// set, in which case they're ordered on ascending cost.
+func (fs *sparseFlowNetwork) buildAllSpreadMemberArcs(spread int) []pr.Arc {
+	if fs.cost == nil {
+		return fs.spreadMemberArcs[spread]
+	} else if fs.costSpreadArcs == nil {
+		fs.costSpreadArcs = make([][]pr.Arc, len(fs.spreadZoneItem))
+	}
+	if arcs := fs.costSpreadArcs[spread]; arcs != nil {
+		return arcs
+	}
+
+	// Filter the cost-ordered Arcs of the zone-item to Members of this node.
+	var arcs = make([]pr.Arc, 0, len(fs.spreadMemberArcs[spread]))
+	for _, arc := range fs.buildAllZoneItemArcs(fs.spreadZoneItem[spread]) {
+		for _, m := range fs.spreadMemberArcs[spread] {
+			if m.To == arc.To {
+				arcs = append(arcs, arc)
+			}
+		}
+	}
+	fs.costSpreadArcs[spread] = arcs
+	return arcs
+}
+
+// spreadNodeOf returns the Spread node of zone-item |zoneItem| which has an
+// Arc to Member node |member|, if there is one.
+func (fs *sparseFlowNetwork) spreadNodeOf(zoneItem int, member pr.NodeID) (pr.NodeID, bool) {
+	for _, arc := range fs.zoneItemSpreadArcs[zoneItem] {
+		for _, m := range fs.spreadMemberArcs[arc.To-fs.firstSpreadNodeID] {
+			if m.To == member {
+				return arc.To, true
+			}
+		}
+	}
+	return 0, false
+}
+
+// warmStartNetwork is a sparseFlowNetwork which is a pr.WarmStarter.
+type warmStartNetwork struct{ *sparseFlowNetwork }
+
+func (ws warmStartNetwork) WarmStart(mf *pr.MaxFlow) { ws.seedAssignments(mf) }
+
+// seedAssignments seeds the MaxFlow with a flow path for each current Assignment,
+// so that the solver need only find augmenting paths for the delta between
+// current Assignments and a maximum flow. Seeded paths are selected in the
+// same order that the solver would explore them from initial node heights,
+// and only while they remain within Arc capacities at those heights.
+//
+// A warm-started solve arrives at a maximum flow, having the same total flow
+// (and thus, the same number of desired Assignments) as a cold solve. It's
+// not guaranteed to arrive at the very same Assignments: push/relabel has no
+// formal notion of cost, and a cold solve may resolve ties in different ways.
+// However both prefer to retain current Assignments, subject to the same
+// zone and "fair share" Member constraints, and the warm-start departs from
+// the seeded Assignments only where required to achieve a maximum flow.
+func (fs *sparseFlowNetwork) seedAssignments(mf *pr.MaxFlow) {
+	var (
+		lz          = len(fs.Zones)
+		sourceArcs  = fs.buildSourceArcs()
+		memberFlows = make([]int, len(fs.Members))
+		spreadFlows = make(map[pr.NodeID]bool)
+	)
+	for item := range fs.myItems {
+		var (
+			itemID   = fs.firstItemNodeID + pr.NodeID(item)
+			itemFlow = 0
+			r        = fs.desiredReplication(itemAt(fs.myItems, item))
+			zoneCap  = r
+		)
+		if lz != 1 {
+			// The largest Item => Zone-Item capacity presented at initial height.
+			zoneCap = max(max(r-1, 1), scaleAndRound(r, 1, lz))
+		}
+
+		for zone := 0; zone != lz; zone++ {
+			var zoneItemID = fs.firstZoneItemNodeID + pr.NodeID(item*lz+zone)
+			var zoneFlow = 0
+
+			for _, a := range fs.zoneItemAssignments[item*lz+zone] {
+				if itemFlow == int(sourceArcs[item].Capacity) || zoneFlow == zoneCap {
+					break
+				}
+				var memberID, ok = fs.memberSuffixIdxByZone[zone][a.Decoded.(Assignment).MemberSuffix]
+				if !ok {
+					continue // Member no longer exists.
+				}
+				var member = int(memberID - fs.firstMemberNodeID)
+
+				if memberFlows[member] == fs.memberCapacity(member, false) {
+					continue // Member is at its fair share.
+				} else if fs.isHardAffine(item) && !fs.isAffine(item*lz+zone, memberID) {
+					continue // Member isn't assigned the Item's anchor.
+				}
+				if !fs.isSpread(item) {
+					mf.AddPath(pr.SourceID, itemID, zoneItemID, memberID, pr.SinkID)
+				} else if spreadID, ok := fs.spreadNodeOf(item*lz+zone, memberID); !ok {
+					continue // Member lacks the spread attribute.
+				} else if spreadFlows[spreadID] {
+					continue // Spread node already has its unit of flow.
+				} else {
+					spreadFlows[spreadID] = true
+					mf.AddPath(pr.SourceID, itemID, zoneItemID, spreadID, memberID, pr.SinkID)
+				}
+
+				itemFlow++
+				zoneFlow++
+				memberFlows[member]++
+			}
+		}
+	}
+}
+
 // extractAssignments appends and returns the set of ordered []Assignment
 // implied by the MaxFlow solution.
 func (fs *sparseFlowNetwork) extractAssignments(g *pr.MaxFlow, out []Assignment) []Assignment {
@@ -397,7 +894,7 @@ func (fs *sparseFlowNetwork) extractAssignments(g *pr.MaxFlow, out []Assignment)
 		for zone := 0; zone != lz; zone++ {
 			var nodeID = fs.firstZoneItemNodeID + pr.NodeID(item*lz+zone)
 
-			g.Flows(nodeID, func(flow pr.Flow) {
+			var extract = func(flow pr.Flow) {
 				var member = memberAt(fs.Members, int(flow.To-fs.firstMemberNodeID))
 
 				out = append(out, Assignment{
@@ -405,6 +902,13 @@ func (fs *sparseFlowNetwork) extractAssignments(g *pr.MaxFlow, out []Assignment)
 					MemberZone:   member.Zone,
 					MemberSuffix: member.Suffix,
 				})
+			}
+			g.Flows(nodeID, func(flow pr.Flow) {
+				if flow.To >= fs.firstSpreadNodeID {
+					g.Flows(flow.To, extract) // Flow of a Spread node to its Member.
+				} else {
+					extract(flow)
+				}
 			})
 		}
 		// Sort the portion just added to |out| under natural Assignment order.
@@ -335,6 +335,143 @@ func (s *SparseSuite) TestZoneBalancing(c *gc.C) {
 	})
 }
 
+func (s *SparseSuite) TestWarmStartFromAssignments(c *gc.C) {
+	var client, ctx = etcdtest.TestClient(), context.Background()
+	defer etcdtest.Cleanup()
+
+	for k, v := range map[string]string{
+		"/root/items/item-1": `{"R": 2}`,
+		"/root/items/item-2": `{"R": 2}`,
+		"/root/items/item-3": `{"R": 2}`,
+
+		"/root/members/A#one":   `{"R": 4}`,
+		"/root/members/A#two":   `{"R": 4}`,
+		"/root/members/B#three": `{"R": 4}`,
+
+		"/root/assign/item-1#A#one#0":   ``,
+		"/root/assign/item-1#B#three#1": ``,
+		"/root/assign/item-2#A#one#0":   ``,
+		"/root/assign/item-2#B#three#1": ``,
+		"/root/assign/item-3#A#one#0":   ``,
+		"/root/assign/item-3#A#two#1":   ``,
+		"/root/assign/item-3#B#gone#2":  ``,
+	} {
+		var _, err = client.Put(ctx, k, v)
+		c.Assert(err, gc.IsNil)
+	}
+	var ks = NewAllocatorKeySpace("/root", testAllocDecoder{})
+	var state = NewObservedState(ks, MemberKey(ks, "A", "one"), isConsistent)
+	c.Check(ks.Load(ctx, client, 0), gc.IsNil)
+
+	var fn = newSparseFlowNetwork(state, state.Items)
+
+	// Expect current Assignments are seeded, except those of a missing Member,
+	// or which exceed a Member's fair share (here, two Items per Member).
+	var mf = pr.FindMaxFlow(seedOnlyNetwork{noopNetwork{fn}})
+	c.Check(fn.extractAssignments(mf, nil), gc.DeepEquals, []Assignment{
+		{ItemID: "item-1", MemberZone: "A", MemberSuffix: "one"},
+		{ItemID: "item-1", MemberZone: "B", MemberSuffix: "three"},
+		{ItemID: "item-2", MemberZone: "A", MemberSuffix: "one"},
+		{ItemID: "item-2", MemberZone: "B", MemberSuffix: "three"},
+		{ItemID: "item-3", MemberZone: "A", MemberSuffix: "two"},
+	})
+
+	// Expect cold and warm solves arrive at a maximum assignment
+	// having the same number of Assignments.
+	var cold = fn.extractAssignments(pr.FindMaxFlow(fn), nil)
+	var warm = fn.extractAssignments(pr.FindMaxFlow(warmStartNetwork{fn}), nil)
+
+	c.Check(cold, gc.HasLen, state.ItemSlots)
+	c.Check(warm, gc.HasLen, state.ItemSlots)
+	c.Check(warm, gc.DeepEquals, []Assignment{
+		{ItemID: "item-1", MemberZone: "A", MemberSuffix: "one"},
+		{ItemID: "item-1", MemberZone: "B", MemberSuffix: "three"},
+		{ItemID: "item-2", MemberZone: "A", MemberSuffix: "one"},
+		{ItemID: "item-2", MemberZone: "B", MemberSuffix: "three"},
+		{ItemID: "item-3", MemberZone: "A", MemberSuffix: "two"},
+		{ItemID: "item-3", MemberZone: "B", MemberSuffix: "three"},
+	})
+}
+
+func (s *SparseSuite) TestCostOrdersZoneItemArcs(c *gc.C) {
+	var client, ctx = etcdtest.TestClient(), context.Background()
+	defer etcdtest.Cleanup()
+
+	for k, v := range map[string]string{
+		"/root/items/item-1": `{"R": 1}`,
+		"/root/items/item-2": `{"R": 1}`,
+
+		"/root/members/A#one":   `{"R": 1}`,
+		"/root/members/A#three": `{"R": 1}`,
+		"/root/members/A#two":   `{"R": 1}`,
+
+		"/root/assign/item-2#A#one#0": ``,
+	} {
+		var _, err = client.Put(ctx, k, v)
+		c.Assert(err, gc.IsNil)
+	}
+	var ks = NewAllocatorKeySpace("/root", testAllocDecoder{})
+	var state = NewObservedState(ks, MemberKey(ks, "A", "one"), isConsistent)
+	c.Check(ks.Load(ctx, client, 0), gc.IsNil)
+
+	const (
+		I1 = pr.SinkID + 1 + iota
+		I2
+		I1A
+		I2A
+		MOne
+		MThree
+		MTwo
+	)
+	// Members "two" and "three" are preferred over "one", and "two" and
+	// "three" are equally preferred for item-2.
+	var costs = map[string]int{
+		"item-1/one": 10, "item-1/three": 5, "item-1/two": 0,
+		"item-2/one": 10, "item-2/three": 0, "item-2/two": 0,
+	}
+	var fn = newSparseFlowNetwork(state, state.Items)
+	fn.cost = func(s *State, member Member, item Item) int {
+		c.Check(s, gc.Equals, state)
+		return costs[item.ID+"/"+member.Suffix]
+	}
+	var mf = pr.FindMaxFlow(fn)
+
+	verifyArcs(c, fn, mf, I1A, [][]pr.Arc{
+		{},
+		{
+			{To: MTwo, Capacity: 1},
+			{To: MThree, Capacity: 1},
+			{To: MOne, Capacity: 1},
+		},
+	})
+	verifyArcs(c, fn, mf, I2A, [][]pr.Arc{
+		{{To: MOne, Capacity: 1, PushFront: true}},
+		{
+			{To: MThree, Capacity: 1},
+			{To: MTwo, Capacity: 1},
+			{To: MOne, Capacity: 1},
+		},
+	})
+	c.Check(fn.costErr, gc.IsNil)
+
+	// Costs are preferences: the current Assignment of item-2 is retained,
+	// and item-1 is assigned to its lowest-cost Member.
+	c.Check(fn.extractAssignments(mf, nil), gc.DeepEquals, []Assignment{
+		{ItemID: "item-1", MemberZone: "A", MemberSuffix: "two"},
+		{ItemID: "item-2", MemberZone: "A", MemberSuffix: "one"},
+	})
+
+	// Costs outside of [0, MaxAssignmentCost] fail the solve.
+	costs["item-1/three"] = -1
+	var _, err = solveDesiredAssignments(state, nil, solveArgs{cost: fn.cost})
+	c.Check(err, gc.ErrorMatches, `invalid cost -1 of item item-1 to member A/three \(must be in \[0, 1048576\]\)`)
+
+	costs["item-1/three"] = MaxAssignmentCost
+	out, err := solveDesiredAssignments(state, nil, solveArgs{cost: fn.cost})
+	c.Check(err, gc.IsNil)
+	c.Check(out, gc.HasLen, 2)
+}
+
 func verifyArcs(c *gc.C, fs *sparseFlowNetwork, mf *pr.MaxFlow, from pr.NodeID, expect [][]pr.Arc) {
 	var page = pr.PageInitial
 
@@ -358,4 +495,9 @@ func (s noopNetwork) Arcs(*pr.MaxFlow, pr.NodeID, pr.PageToken) ([]pr.Arc, pr.Pa
 	return nil, pr.PageEOF
 }
 
+// seedOnlyNetwork is a noopNetwork which seeds current Assignments.
+type seedOnlyNetwork struct{ noopNetwork }
+
+func (s seedOnlyNetwork) WarmStart(mf *pr.MaxFlow) { s.seedAssignments(mf) }
+
 var _ = gc.Suite(&SparseSuite{})
@@ -85,6 +85,29 @@ type Network interface {
 	Arcs(*MaxFlow, NodeID, PageToken) ([]Arc, PageToken)
 }
 
+// WarmStarter is an optional interface of a Network which seeds the MaxFlow
+// with an initial flow, from which the solver then begins (a "warm start").
+// When the Network is closely related to one having a known prior solution,
+// seeding the flow of that solution leaves the solver to find augmenting
+// paths only for the delta, which is cheaper than a solve from scratch.
+//
+// Seeded flows must be feasible with respect to Arc capacities presented by
+// the Network at initial node heights, and are added via MaxFlow.AddPath.
+type WarmStarter interface {
+	WarmStart(*MaxFlow)
+}
+
+// ArcOrderer is an optional interface of a Network which indicates whether
+// a page of node []Arcs is presented in a strict order of preference. By
+// default the solver shifts the enumeration order of each page by NodeID,
+// which speeds solving where many nodes present common []Arcs, but departs
+// from the presented order.
+type ArcOrderer interface {
+	// OrderedArcs returns true if the page of node []Arcs must be
+	// enumerated in its presented order.
+	OrderedArcs(NodeID, PageToken) bool
+}
+
 // Adjacency represents a directed edge between two nodes.
 type Adjacency struct {
 	From, To NodeID
@@ -155,9 +178,13 @@ func newMaxFlow(network Network) *MaxFlow {
 }
 
 // FindMaxFlow solves for the maximum flow of the given Network using a sparse
-// variant of the push/relabel algorithm.
+// variant of the push/relabel algorithm. If the Network is a WarmStarter, the
+// solver begins from its seeded flow.
 func FindMaxFlow(network Network) *MaxFlow {
 	var mf = newMaxFlow(network)
+	if ws, ok := network.(WarmStarter); ok {
+		ws.WarmStart(mf)
+	}
 	for {
 		if id, ok := mf.popActiveNode(); !ok {
 			return mf // All done.
@@ -167,6 +194,49 @@ func FindMaxFlow(network Network) *MaxFlow {
 	}
 }
 
+// AddPath adds a unit of flow along |path|, which must begin at the SourceID
+// and end at the SinkID. It may be used only to seed the MaxFlow by a
+// WarmStarter. Flows of the path are tracked as if they were pushed along Arcs
+// having PushFront, meaning the solver prefers to retain them.
+//
+// A seeded flow is a valid starting point for push/relabel: each interior
+// node of the path has balanced in- and out-flow, and where InitialHeight is
+// a node's distance from the Sink, residuals of seeded flows respect the
+// height labeling required by the algorithm.
+func (mf *MaxFlow) AddPath(path ...NodeID) {
+	if l := len(path); l < 2 || path[0] != SourceID || path[l-1] != SinkID {
+		panic("path must begin at SourceID and end at SinkID")
+	}
+	for i := 1; i != len(path); i++ {
+		var adj = Adjacency{From: path[i-1], To: path[i]}
+		var fid = mf.findFlow(adj)
+
+		if fid == 0 {
+			fid = mf.addFlow(adj, true)
+		}
+		mf.flows[fid].Rate++
+	}
+	mf.nodes[SourceID].excess--
+	mf.nodes[SinkID].excess++
+}
+
+// findFlow returns the flowID of the Adjacency, or zero if it's not tracked.
+// It walks forward flows of the From node and reverse flows of the To node in
+// lockstep, which bounds its cost by the shorter of the two lists.
+func (mf *MaxFlow) findFlow(adj Adjacency) flowID {
+	var fwd, rev = mf.nodes[adj.From].fwdHead, mf.nodes[adj.To].revHead
+
+	for fwd != 0 && rev != 0 {
+		if mf.flows[fwd].To == adj.To {
+			return fwd
+		} else if mf.flows[rev].From == adj.From {
+			return rev
+		}
+		fwd, rev = mf.flows[fwd].fwdNext, mf.flows[rev].revNext
+	}
+	return 0
+}
+
 // RelativeHeight returns the node Height delta, relative to the source node.
 // Depending on Network semantics, implementations may wish to use RelativeHeight
 // to condition capacities of returned []Arcs, for example by increasing capacity
@@ -213,7 +283,7 @@ func (mf *MaxFlow) discharge(nid NodeID, structure Network) {
 	// instead walking residuals, restart |fid| from the list tail.
 	if node.dischargePage != PageEOF {
 		if arcs, nextPage = structure.Arcs(mf, nid, node.dischargePage); len(arcs) != 0 {
-			arcShift = int(nid) % len(arcs)
+			arcShift = shiftArcs(structure, nid, node.dischargePage, len(arcs))
 		}
 	} else {
 		fid = node.revTail
@@ -239,7 +309,7 @@ func (mf *MaxFlow) discharge(nid NodeID, structure Network) {
 
 		if node.dischargePage != PageEOF {
 			if arcs, nextPage = structure.Arcs(mf, nid, node.dischargePage); len(arcs) != 0 {
-				arcShift = int(nid) % len(arcs)
+				arcShift = shiftArcs(structure, nid, node.dischargePage, len(arcs))
 			}
 		} else {
 			fid = node.revTail // Walk backwards from the tail (LIFO order).
@@ -395,6 +465,15 @@ func (mf *MaxFlow) discharge(nid NodeID, structure Network) {
 	}
 }
 
+// shiftArcs returns the shift of the enumeration order of a page of |n| Arcs
+// of node |nid|, which is zero if the Network orders the page.
+func shiftArcs(structure Network, nid NodeID, page PageToken, n int) int {
+	if o, ok := structure.(ArcOrderer); ok && o.OrderedArcs(nid, page) {
+		return 0
+	}
+	return int(nid) % n
+}
+
 // constrainHeight returns true if |node|'s height is greater than |to|'s, a
 // constraint required by push/relabel in order for flow to be pushed from
 // |node| to |to|.
@@ -48,6 +48,57 @@ func TestSimpleFixtureOne(t *testing.T) {
 	require.Equal(t, mf.heightCounts, []int32{1, 2, 0, 0, 0, 0})
 }
 
+func TestWarmStartFromSeededFlow(t *testing.T) {
+	// Use the fixture of TestSimpleFixtureOne, but seed an initial flow
+	// which is feasible yet departs from the unique maximum flow.
+	const (
+		A = SinkID + 1
+		B = A + 1
+		C = B + 1
+		D = C + 1
+	)
+	var arcs = fixedArcs{
+		SourceID: {{
+			{To: A, Capacity: 15},
+			{To: C, Capacity: 4},
+		}},
+		A: {{{To: B, Capacity: 12}}},
+		B: {{{To: C, Capacity: 3}, {To: SinkID, Capacity: 7}}},
+		C: {{{To: D, Capacity: 10}}},
+		D: {{
+			{To: A, Capacity: 5},
+			{To: SinkID, Capacity: 10},
+		}},
+	}
+	var network = warmStartNetwork{
+		testNetwork: testNetwork{nodes: 6, arcsFn: arcs.fn},
+		seed: func(mf *MaxFlow) {
+			for i := 0; i != 4; i++ {
+				mf.AddPath(SourceID, C, D, A, B, SinkID)
+			}
+			mf.AddPath(SourceID, A, B, SinkID)
+		},
+	}
+	var mf = FindMaxFlow(network)
+
+	// Expect the solver departs from the seeded flow as required
+	// to arrive at the same (unique) maximum flow.
+	require.Equal(t, toMap(mf), map[Adjacency]Rate{
+		{From: A, To: B}:        10,
+		{From: B, To: C}:        3,
+		{From: B, To: SinkID}:   7,
+		{From: C, To: D}:        7,
+		{From: D, To: SinkID}:   7,
+		{From: SourceID, To: A}: 10,
+		{From: SourceID, To: C}: 4,
+	})
+	require.Equal(t, Rate(14), mf.nodes[SinkID].excess)
+
+	// Paths must begin at the source and end at the sink.
+	require.Panics(t, func() { mf.AddPath(A, B, SinkID) })
+	require.Panics(t, func() { mf.AddPath(SourceID, A, B) })
+}
+
 func TestSimpleFixtureTwo(t *testing.T) {
 	// Build the graph as described by the Maximum Flow Wikipedia page.
 	// There is only one valid maximum flow.
@@ -165,6 +216,30 @@ func TestCommonSubstructureFixture(t *testing.T) {
 		{From: 8, To: SinkID}: 2,
 		{From: 9, To: SinkID}: 2,
 	})
+
+	// Again. This time, the Network is an ArcOrderer which requires that
+	// Arcs of Nodes [2, 5] are walked in their presented order.
+	mf = FindMaxFlow(orderedNetwork{
+		testNetwork: testNetwork{nodes: 11, arcsFn: arcsFn},
+		ordered:     func(id NodeID, _ PageToken) bool { return id >= 2 && id < 6 },
+	})
+
+	require.Equal(t, toMap(mf), map[Adjacency]Rate{
+		{From: SourceID, To: 2}: 2,
+		{From: SourceID, To: 3}: 2,
+		{From: SourceID, To: 4}: 2,
+		{From: SourceID, To: 5}: 2,
+
+		// Nodes push along the first Arc which is admissible
+		// (having a lower height), in presented order.
+		{From: 2, To: 6}: 2,
+		{From: 3, To: 7}: 2,
+		{From: 4, To: 7}: 2,
+		{From: 5, To: 6}: 2,
+
+		{From: 6, To: SinkID}: 4,
+		{From: 7, To: SinkID}: 4,
+	})
 }
 
 func TestOverflowFixture(t *testing.T) {
@@ -532,6 +607,22 @@ func (s testNetwork) Arcs(g *MaxFlow, id NodeID, token PageToken) ([]Arc, PageTo
 	return s.arcsFn(g, id, token)
 }
 
+// warmStartNetwork is a testNetwork which is a WarmStarter.
+type warmStartNetwork struct {
+	testNetwork
+	seed func(*MaxFlow)
+}
+
+func (s warmStartNetwork) WarmStart(mf *MaxFlow) { s.seed(mf) }
+
+// orderedNetwork is a testNetwork which is an ArcOrderer.
+type orderedNetwork struct {
+	testNetwork
+	ordered func(NodeID, PageToken) bool
+}
+
+func (s orderedNetwork) OrderedArcs(id NodeID, token PageToken) bool { return s.ordered(id, token) }
+
 type fixedArcs map[NodeID][][]Arc
 
 func (f fixedArcs) fn(g *MaxFlow, id NodeID, token PageToken) ([]Arc, PageToken) {
@@ -0,0 +1,139 @@
+package allocator
+
+import (
+	"hash/crc64"
+
+	"go.gazette.dev/core/keyspace"
+)
+
+// AttributedMemberValue is an optional interface of a MemberValue which
+// declares attributes of its Member, such as its rack, instance type, or
+// kernel version. Attributes are decoded with the MemberValue by the Decoder.
+type AttributedMemberValue interface {
+	// Attribute returns the value of the named attribute,
+	// or empty if the Member doesn't have the attribute.
+	Attribute(name string) string
+}
+
+// SpreadItemValue is an optional interface of an ItemValue which constrains
+// the replicas of its Item to be spread across distinct values of a Member
+// attribute (see AttributedMemberValue), limiting the blast radius of
+// correlated failures of Members which share an attribute value.
+//
+// Spread is a hard constraint of the solver: no two Assignments of an Item
+// within a zone are made to Members having the same attribute value, and the
+// Item is never assigned to a Member which lacks the attribute. As zones are
+// themselves distinct failure domains, attribute values are scoped to their
+// zone: a rack "r1" of zone A is distinct from a rack "r1" of zone B. The
+// preferences of the solver for spreading replicas across zones still apply.
+//
+// If there are fewer distinct attribute values than the DesiredReplication of
+// the Item, it's under-replicated rather than placing replicas on Members of
+// a shared value, and the violation is reported (see State.SpreadViolations).
+type SpreadItemValue interface {
+	// SpreadAttribute returns the name of the Member attribute across which
+	// replicas of the Item are spread, or empty if the Item has no constraint.
+	SpreadAttribute() string
+}
+
+// SpreadViolation is an Item whose spread constraint can't be satisfied,
+// as its Members have too few distinct values of the spread attribute.
+type SpreadViolation struct {
+	ItemID string
+	// Attribute across which replicas of the Item are spread.
+	Attribute string
+	// Number of distinct (zone-scoped) values of the Attribute across Members
+	// having item slots, and the DesiredReplication of the Item.
+	Values, DesiredReplication int
+}
+
+// SpreadViolations returns each Item having a spread constraint which can't
+// be satisfied by the current Members. SpreadViolations read-locks the
+// KeySpace. It must not be called while the KeySpace is locked, such as from
+// a KeySpace Observer.
+func (s *State) SpreadViolations() []SpreadViolation {
+	s.KS.Mu.RLock()
+	defer s.KS.Mu.RUnlock()
+
+	return spreadViolations(s)
+}
+
+// spreadViolations is SpreadViolations, without locking the KeySpace.
+func spreadViolations(s *State) []SpreadViolation {
+	var out []SpreadViolation
+
+	for i := range s.Items {
+		var item = itemAt(s.Items, i)
+		var attr = spreadAttribute(item)
+
+		if attr == "" {
+			continue
+		}
+		if n, r := spreadValues(s, attr), s.desiredReplication(item); n < r {
+			out = append(out, SpreadViolation{
+				ItemID:             item.ID,
+				Attribute:          attr,
+				Values:             n,
+				DesiredReplication: r,
+			})
+		}
+	}
+	return out
+}
+
+// spreadValues returns the number of distinct zone-scoped values of attribute
+// |attr| across State Members having item slots.
+func spreadValues(s *State, attr string) int {
+	var seen = make(map[[2]string]struct{})
+
+	for i := range s.Members {
+		var m = memberAt(s.Members, i)
+
+		if v := memberAttribute(m, attr); v != "" && s.memberLimits[i] != 0 {
+			seen[[2]string{m.Zone, v}] = struct{}{}
+		}
+	}
+	return len(seen)
+}
+
+// spreadAttribute returns the spread attribute of the Item, or empty if none.
+func spreadAttribute(item Item) string {
+	if sv, ok := item.ItemValue.(SpreadItemValue); ok {
+		return sv.SpreadAttribute()
+	}
+	return ""
+}
+
+// memberAttribute returns the named attribute of the Member, or empty if none.
+func memberAttribute(m Member, name string) string {
+	if av, ok := m.MemberValue.(AttributedMemberValue); ok {
+		return av.Attribute(name)
+	}
+	return ""
+}
+
+// foldSpreadCRC folds spread attributes of |items|, and the values of those
+// attributes of |members|, into |crc|.
+func foldSpreadCRC(crc uint64, items, members keyspace.KeyValues) uint64 {
+	var attrs []string
+	var seen = make(map[string]struct{})
+
+	for i := range items {
+		if attr := spreadAttribute(itemAt(items, i)); attr != "" {
+			crc = crc64.Update(crc, crcTable, items[i].Raw.Key)
+			crc = crc64.Update(crc, crcTable, []byte(attr))
+
+			if _, ok := seen[attr]; !ok {
+				seen[attr] = struct{}{}
+				attrs = append(attrs, attr)
+			}
+		}
+	}
+	for _, attr := range attrs {
+		for i := range members {
+			crc = crc64.Update(crc, crcTable, members[i].Raw.Key)
+			crc = crc64.Update(crc, crcTable, []byte(memberAttribute(memberAt(members, i), attr)))
+		}
+	}
+	return crc
+}
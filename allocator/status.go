@@ -0,0 +1,198 @@
+package allocator
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Status is a point-in-time summary of the allocator, as observed by a Member.
+type Status struct {
+	// MemberKey of this allocator instance.
+	MemberKey string `json:"memberKey"`
+	// Leader is true if this Member is the current allocator leader.
+	Leader bool `json:"leader"`
+	// Revision of the KeySpace from which the Status was extracted.
+	Revision int64 `json:"revision"`
+	// Number of Members, Items, and Assignments of the KeySpace.
+	Members     int `json:"members"`
+	Items       int `json:"items"`
+	Assignments int `json:"assignments"`
+	// Total desired replication slots of Items, and available slots of Members.
+	ItemSlots   int `json:"itemSlots"`
+	MemberSlots int `json:"memberSlots"`
+	// UnderReplicatedItems is the number of Items having fewer current
+	// Assignments than their desired replication.
+	UnderReplicatedItems int `json:"underReplicatedItems"`
+	// EvacuatedZones are zones having a ZoneEvacuation.
+	EvacuatedZones []string `json:"evacuatedZones,omitempty"`
+
+	// Fields which follow are known only to the leader, and reflect its most
+	// recent solve and convergence round. They're zero-valued for non-leaders.
+
+	// Converged is true if the leader's last convergence round found no
+	// further changes to make, as of the current Revision.
+	Converged bool `json:"converged"`
+	// LastSolveDuration is the duration of the leader's last solve for a
+	// maximum assignment.
+	LastSolveDuration time.Duration `json:"lastSolveDuration"`
+	// UnplaceableItems is the number of Items which could not be assigned
+	// their desired replication by the leader's last solve, such as because
+	// there are too few Members or they're poorly distributed across zones.
+	UnplaceableItems int `json:"unplaceableItems"`
+	// UnattainableReplicas is the total number of Item replicas which could
+	// not be assigned by the leader's last solve.
+	UnattainableReplicas int `json:"unattainableReplicas"`
+	// Rounds is the number of convergence rounds completed by this Member
+	// while leader, and IdleRounds is the number of those which found no
+	// changes to make. A leader which is rebalancing completes non-idle
+	// rounds, while a stable one completes rounds only as the KeySpace
+	// changes, nearly all of which are idle. A high idle ratio is normal.
+	Rounds     int64 `json:"rounds"`
+	IdleRounds int64 `json:"idleRounds"`
+	// LastActiveRound is the time of the last convergence round which
+	// made changes, or nil if there hasn't been one.
+	LastActiveRound *time.Time `json:"lastActiveRound,omitempty"`
+}
+
+// StatusHandler is an http.Handler which serves the current Status of an
+// allocator State as JSON. Its Status reflects the latest Revision observed
+// by the State's KeySpace, and if passed as AllocateArgs.Status, it also
+// reflects the solve and convergence rounds of Allocate when leader. Register
+// it with a mux under a path of the application's choosing:
+//
+//	mux.Handle("/debug/allocator", allocator.NewStatusHandler(state))
+type StatusHandler struct {
+	state *State
+
+	mu                sync.Mutex
+	isLeader          func() bool
+	solveDuration     time.Duration
+	unplaceable       int
+	unattainable      int
+	convergedRevision int64
+	rounds            int64
+	idleRounds        int64
+	lastActiveRound   time.Time
+}
+
+// NewStatusHandler returns a StatusHandler of the State.
+func NewStatusHandler(state *State) *StatusHandler {
+	return &StatusHandler{state: state}
+}
+
+// Status returns the current Status. It read-locks the State's KeySpace,
+// and is safe for concurrent use with KeySpace updates and with Allocate.
+func (h *StatusHandler) Status() Status {
+	var s = h.state
+	s.KS.Mu.RLock()
+	defer s.KS.Mu.RUnlock()
+
+	var out = Status{
+		MemberKey:   s.LocalKey,
+		Revision:    s.KS.Header.Revision,
+		Members:     len(s.Members),
+		Items:       len(s.Items),
+		Assignments: len(s.Assignments),
+		ItemSlots:   s.ItemSlots,
+		MemberSlots: s.MemberSlots,
+
+		EvacuatedZones: s.EvacuatedZones(),
+	}
+	h.mu.Lock()
+	var isLeader = h.isLeader
+	h.mu.Unlock()
+
+	if isLeader != nil {
+		out.Leader = isLeader()
+	} else if s.LocalMemberInd != -1 {
+		out.Leader = s.isLeader()
+	}
+
+	var it = LeftJoin{
+		LenL: len(s.Items),
+		LenR: len(s.Assignments),
+		Compare: func(l, r int) int {
+			return strings.Compare(itemAt(s.Items, l).ID, assignmentAt(s.Assignments, r).ItemID)
+		},
+	}
+	for cur, ok := it.Next(); ok; cur, ok = it.Next() {
+		if cur.RightEnd-cur.RightBegin < s.desiredReplication(itemAt(s.Items, cur.Left)) {
+			out.UnderReplicatedItems++
+		}
+	}
+
+	if out.Leader {
+		h.mu.Lock()
+		out.Converged = h.convergedRevision == out.Revision
+		out.LastSolveDuration = h.solveDuration
+		out.UnplaceableItems = h.unplaceable
+		out.UnattainableReplicas = h.unattainable
+		out.Rounds = h.rounds
+		out.IdleRounds = h.idleRounds
+		if t := h.lastActiveRound; !t.IsZero() {
+			out.LastActiveRound = &t
+		}
+		h.mu.Unlock()
+	}
+	return out
+}
+
+// ServeHTTP serves the current Status as JSON.
+func (h *StatusHandler) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	var status = h.Status()
+
+	w.Header().Set("Content-Type", "application/json")
+	var enc = json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	if err := enc.Encode(&status); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// setIsLeader sets an externally-managed leadership signal of Allocate.
+func (h *StatusHandler) setIsLeader(fn func() bool) {
+	h.mu.Lock()
+	h.isLeader = fn
+	h.mu.Unlock()
+}
+
+// onSolve records a solve for |desired| Assignments of the State.
+// The State's KeySpace must be read-locked.
+func (h *StatusHandler) onSolve(dur time.Duration, desired []Assignment) {
+	var counts = make(map[string]int, len(h.state.Items))
+	for _, a := range desired {
+		counts[a.ItemID]++
+	}
+	var unplaceable int
+	for i := range h.state.Items {
+		var item = itemAt(h.state.Items, i)
+		if counts[item.ID] < h.state.desiredReplication(item) {
+			unplaceable++
+		}
+	}
+
+	h.mu.Lock()
+	h.solveDuration = dur
+	h.unplaceable = unplaceable
+	h.unattainable = h.state.ItemSlots - len(desired)
+	h.mu.Unlock()
+}
+
+// onConverge records a convergence round of Allocate, which is idle if it
+// found no further changes to make. The State's KeySpace must be read-locked.
+func (h *StatusHandler) onConverge(isIdle bool) {
+	h.mu.Lock()
+	h.rounds++
+	if isIdle {
+		h.convergedRevision = h.state.KS.Header.Revision
+		h.idleRounds++
+	} else {
+		h.convergedRevision = 0
+		h.lastActiveRound = timeNow()
+	}
+	h.mu.Unlock()
+}
@@ -0,0 +1,116 @@
+package allocator
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatusHandler(t *testing.T) {
+	var t0 = time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	defer func(f func() time.Time) { timeNow = f }(timeNow)
+	timeNow = func() time.Time { return t0 }
+
+	var ctx, client, ks = testSetup(t)
+
+	require.NoError(t, insert(ctx, client,
+		"/root/items/item-1", `{"R": 2}`,
+		"/root/items/item-2", `{"R": 2}`,
+		"/root/items/item-3", `{"R": 2}`,
+		"/root/items/item-4", `{"R": 2}`,
+
+		"/root/members/zone-a#member-A", `{"R": 3}`,
+		"/root/members/zone-b#member-B", `{"R": 3}`,
+	))
+
+	var leader = NewObservedState(ks, MemberKey(ks, "zone-a", "member-A"), isConsistent)
+	var follower = NewObservedState(ks, MemberKey(ks, "zone-b", "member-B"), isConsistent)
+	var leaderStatus, followerStatus = NewStatusHandler(leader), NewStatusHandler(follower)
+
+	ctx, cancel := context.WithCancel(ctx)
+	require.NoError(t, ks.Load(ctx, client, 0))
+	go ks.Watch(ctx, client)
+
+	// Before Allocate runs, nothing is assigned and the leader hasn't converged.
+	var status = leaderStatus.Status()
+	require.True(t, status.Leader)
+	require.False(t, status.Converged)
+	require.Equal(t, 4, status.UnderReplicatedItems)
+	require.Equal(t, 0, status.Assignments)
+
+	// Serve Allocate until it's idle.
+	var rounds int64
+	require.Equal(t, context.Canceled, Allocate(AllocateArgs{
+		Context: ctx,
+		Etcd:    client,
+		State:   leader,
+		Status:  leaderStatus,
+		TestHook: func(round int, idle bool) {
+			if rounds++; idle {
+				cancel()
+			}
+		},
+	}))
+	status = leaderStatus.Status()
+
+	// Six slots are available for eight desired replicas. The solve fully
+	// replicates three Items, leaving one Item without any Assignment.
+	require.Equal(t, Status{
+		MemberKey:            "/root/members/zone-a#member-A",
+		Leader:               true,
+		Revision:             status.Revision,
+		Members:              2,
+		Items:                4,
+		Assignments:          6,
+		ItemSlots:            8,
+		MemberSlots:          6,
+		UnderReplicatedItems: 1,
+		Converged:            true,
+		LastSolveDuration:    status.LastSolveDuration,
+		UnplaceableItems:     1,
+		UnattainableReplicas: 2,
+		Rounds:               rounds,
+		IdleRounds:           1,
+		LastActiveRound:      &t0,
+	}, status)
+	require.Greater(t, rounds, int64(1))
+	require.NotZero(t, status.Revision)
+	require.NotZero(t, status.LastSolveDuration)
+
+	// A follower reports the current allocation, but not solve details.
+	require.Equal(t, Status{
+		MemberKey:            "/root/members/zone-b#member-B",
+		Revision:             status.Revision,
+		Members:              2,
+		Items:                4,
+		Assignments:          6,
+		ItemSlots:            8,
+		MemberSlots:          6,
+		UnderReplicatedItems: 1,
+	}, followerStatus.Status())
+
+	// Status is served as JSON.
+	var w = httptest.NewRecorder()
+	followerStatus.ServeHTTP(w, httptest.NewRequest("GET", "/debug/allocator", nil))
+	require.Equal(t, "application/json", w.Header().Get("Content-Type"))
+
+	var served map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &served))
+	require.Equal(t, "/root/members/zone-b#member-B", served["memberKey"])
+	require.Equal(t, false, served["leader"])
+	require.Equal(t, float64(6), served["assignments"])
+	require.Equal(t, float64(1), served["underReplicatedItems"])
+	require.NotContains(t, served, "lastActiveRound")
+
+	w = httptest.NewRecorder()
+	leaderStatus.ServeHTTP(w, httptest.NewRequest("GET", "/debug/allocator", nil))
+
+	served = nil
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &served))
+	require.Equal(t, "2026-01-01T00:00:00Z", served["lastActiveRound"])
+}
@@ -0,0 +1,112 @@
+package allocator
+
+import (
+	"context"
+	"time"
+
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// roundTracer emits an OpenTelemetry span for each sampled convergence
+// round of Allocate. A round span has child spans of its phases:
+//
+//   - "allocator.solve" solves for a maximum assignment. It's present only
+//     in rounds which re-solve, which happens as the State's network changes.
+//   - "allocator.converge" walks Items and current Assignments of the KeySpace,
+//     joined with desired Assignments, to derive Etcd operations of the round.
+//   - "allocator.commit" applies an Etcd transaction. A round may have many
+//     transactions, which are batched and flushed as the round is loaded.
+//
+// Rounds of a leader are frequent when its KeySpace is changing rapidly, such
+// as during a rolling deployment. Rounds are sampled by limiting traced
+// rounds to one per |interval|, and untraced rounds use a no-op Tracer.
+// This is in addition to any sampling of the TracerProvider itself.
+type roundTracer struct {
+	tracer   trace.Tracer
+	interval time.Duration
+	last     time.Time
+}
+
+// newRoundTracer returns a roundTracer of the Tracer, which may be nil.
+func newRoundTracer(tracer trace.Tracer, interval time.Duration) *roundTracer {
+	if tracer == nil {
+		tracer = noopTracer
+	}
+	return &roundTracer{tracer: tracer, interval: interval}
+}
+
+// startRound begins a span of a convergence round. It returns a Tracer of
+// the round's phases, which is a no-op Tracer if the round isn't sampled.
+// The KeySpace must be read-locked.
+func (rt *roundTracer) startRound(ctx context.Context, round int, state *State) (context.Context, trace.Tracer, trace.Span) {
+	var tracer = rt.tracer
+	if now := time.Now(); now.Sub(rt.last) < rt.interval {
+		tracer = noopTracer
+	} else if tracer != noopTracer {
+		rt.last = now
+	}
+
+	ctx, span := tracer.Start(ctx, "allocator.round", trace.WithAttributes(
+		attribute.Int("allocator.round", round),
+		attribute.Int64("allocator.revision", state.KS.Header.Revision),
+		attribute.String("allocator.member", state.LocalKey),
+		attribute.Int("allocator.members", len(state.Members)),
+		attribute.Int("allocator.items", len(state.Items)),
+		attribute.Int("allocator.assignments", len(state.Assignments)),
+		attribute.Int("allocator.item_slots", state.ItemSlots),
+		attribute.Int("allocator.member_slots", state.MemberSlots),
+	))
+	return ctx, tracer, span
+}
+
+// traceTxns wraps the transactions of the batchedTxn with "allocator.commit"
+// spans, and records the churn of its committed operations on |round|.
+func traceTxns(ctx context.Context, tracer trace.Tracer, round trace.Span, txn *batchedTxn) {
+	var txnDo = txn.txnDo
+	var puts, deletes int
+
+	txn.txnDo = func(op clientv3.Op) (*clientv3.TxnResponse, error) {
+		var cmps, ops, _ = op.Txn()
+		var _, span = tracer.Start(ctx, "allocator.commit", trace.WithAttributes(
+			attribute.Int("allocator.cmps", len(cmps)),
+			attribute.Int("allocator.ops", len(ops)),
+		))
+
+		var resp, err = txnDo(op)
+		if err == nil {
+			span.SetAttributes(
+				attribute.Bool("allocator.succeeded", resp.Succeeded),
+				attribute.Int64("allocator.revision", resp.Header.Revision),
+			)
+		}
+		if err == nil && resp.Succeeded {
+			for _, op := range ops {
+				if op.IsPut() {
+					puts++
+				} else if op.IsDelete() {
+					deletes++
+				}
+			}
+			round.SetAttributes(
+				attribute.Int("allocator.churn.puts", puts),
+				attribute.Int("allocator.churn.deletes", deletes),
+			)
+		}
+		endSpan(span, err)
+		return resp, err
+	}
+}
+
+// endSpan records a non-nil |err| as the status of the Span, and ends it.
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+var noopTracer = trace.NewNoopTracerProvider().Tracer("")
@@ -0,0 +1,82 @@
+package allocator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestTracedRounds(t *testing.T) {
+	var ctx, client, ks = testSetup(t)
+
+	var recorder = tracetest.NewSpanRecorder()
+	var provider = sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	var args = AllocateArgs{Tracer: provider.Tracer("allocator")}
+
+	require.NoError(t, insert(ctx, client,
+		"/root/items/item-1", `{"R": 1}`,
+		"/root/items/item-2", `{"R": 1}`,
+
+		"/root/members/zone-a#member-A", `{"R": 10}`,
+	))
+	var rounds = serveUntilIdleWithArgs(t, ctx, client, ks, "", args)
+
+	// Returns the names of ended spans, and attributes of round spans.
+	var collect = func() (names []string, attrs []map[attribute.Key]attribute.Value) {
+		for _, span := range recorder.Ended() {
+			names = append(names, span.Name())
+
+			if span.Name() != "allocator.round" {
+				continue
+			}
+			var m = make(map[attribute.Key]attribute.Value)
+			for _, kv := range span.Attributes() {
+				m[kv.Key] = kv.Value
+			}
+			attrs = append(attrs, m)
+		}
+		return
+	}
+	var names, attrs = collect()
+
+	// The first round solves, and commits new Assignments. Later rounds
+	// mark Assignments as consistent, and the final round is idle.
+	require.Equal(t, []string{
+		"allocator.solve",
+		"allocator.converge",
+		"allocator.commit",
+		"allocator.round",
+	}, names[:4])
+	require.Len(t, attrs, rounds+1)
+
+	require.Equal(t, int64(0), attrs[0]["allocator.round"].AsInt64())
+	require.Equal(t, int64(2), attrs[0]["allocator.items"].AsInt64())
+	require.Equal(t, int64(1), attrs[0]["allocator.members"].AsInt64())
+	require.Equal(t, int64(0), attrs[0]["allocator.assignments"].AsInt64())
+	require.Equal(t, int64(2), attrs[0]["allocator.churn.puts"].AsInt64())
+	require.False(t, attrs[0]["allocator.idle"].AsBool())
+	require.True(t, attrs[rounds]["allocator.idle"].AsBool())
+
+	// Child spans are parented by their round.
+	var ended = recorder.Ended()
+	require.Equal(t, ended[3].SpanContext().SpanID(), ended[0].Parent().SpanID())
+	require.Equal(t, ended[3].SpanContext().SpanID(), ended[2].Parent().SpanID())
+
+	// Case: with a TraceInterval, only the first of frequent rounds is traced.
+	recorder = tracetest.NewSpanRecorder()
+	provider = sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	args = AllocateArgs{Tracer: provider.Tracer("allocator"), TraceInterval: time.Hour}
+
+	require.NoError(t, update(ctx, client, "/root/items/item-1", `{"R": 0}`))
+	require.NoError(t, insert(ctx, client, "/root/items/item-3", `{"R": 1}`))
+	require.NotZero(t, serveUntilIdleWithArgs(t, ctx, client, ks, "", args))
+
+	names, attrs = collect()
+	require.Len(t, attrs, 1)
+	require.Equal(t, "allocator.round", names[len(names)-1])
+	require.Equal(t, int64(1), attrs[0]["allocator.churn.deletes"].AsInt64())
+}
@@ -66,7 +66,8 @@ func (svc *Service) Append(stream pb.Journal_AppendServer) (err error) {
 				Status: fsm.resolved.status,
 				Header: fsm.resolved.Header,
 			}
-			if fsm.resolved.status == pb.Status_REGISTER_MISMATCH {
+			if fsm.resolved.status == pb.Status_REGISTER_MISMATCH ||
+				fsm.resolved.status == pb.Status_WRONG_APPEND_OFFSET {
 				resp.Registers = &fsm.registers
 			}
 			return stream.SendAndClose(resp)
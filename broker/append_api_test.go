@@ -3,13 +3,18 @@ package broker
 import (
 	"context"
 	"errors"
+	"fmt"
 	"io"
+	"sort"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/require"
+	"go.gazette.dev/core/broker/client"
 	"go.gazette.dev/core/broker/fragment"
 	pb "go.gazette.dev/core/broker/protocol"
 	"go.gazette.dev/core/etcdtest"
+	"go.gazette.dev/core/labels"
 )
 
 func TestAppendSingle(t *testing.T) {
@@ -49,6 +54,60 @@ func TestAppendSingle(t *testing.T) {
 	broker.cleanup()
 }
 
+func TestAppendFlush(t *testing.T) {
+	var ctx, etcd = pb.WithDispatchDefault(context.Background()), etcdtest.TestClient()
+	defer etcdtest.Cleanup()
+
+	var broker = newTestBroker(t, etcd, pb.ProcessSpec_ID{Zone: "local", Suffix: "broker"})
+	setTestJournal(broker, pb.JournalSpec{Name: "a/journal", Replication: 1}, broker.id)
+	broker.initialFragmentLoad()
+
+	var doAppend = func(req pb.AppendRequest, content string) *pb.Fragment {
+		var stream, _ = broker.client().Append(ctx)
+		require.NoError(t, stream.Send(&req))
+		if content != "" {
+			require.NoError(t, stream.Send(&pb.AppendRequest{Content: []byte(content)}))
+		}
+		require.NoError(t, stream.Send(&pb.AppendRequest{})) // Intend to commit.
+		resp, err := stream.CloseAndRecv()
+		require.NoError(t, err)
+		require.Equal(t, pb.Status_OK, resp.Status)
+		return resp.Commit
+	}
+	var fragments = func() (out [][2]int64) {
+		var resp, err = broker.client().ListFragments(ctx, &pb.FragmentsRequest{Journal: "a/journal"})
+		require.NoError(t, err)
+		for _, f := range resp.Fragments {
+			out = append(out, [2]int64{f.Spec.Begin, f.Spec.End})
+		}
+		return
+	}
+
+	// The journal's first write is rolled by its next append.
+	doAppend(pb.AppendRequest{Journal: "a/journal"}, "foo")
+	doAppend(pb.AppendRequest{Journal: "a/journal"}, "bar")
+	require.Equal(t, [][2]int64{{0, 3}, {3, 6}}, fragments())
+
+	// A further append extends the current Fragment.
+	doAppend(pb.AppendRequest{Journal: "a/journal"}, "baz")
+	require.Equal(t, [][2]int64{{0, 3}, {3, 9}}, fragments())
+
+	// An empty append which flushes rolls the current Fragment.
+	var commit = doAppend(pb.AppendRequest{Journal: "a/journal", Flush: true}, "")
+	require.Equal(t, int64(9), commit.Begin)
+	require.Equal(t, int64(9), commit.End)
+
+	// Expect a following append begins a new Fragment.
+	doAppend(pb.AppendRequest{Journal: "a/journal"}, "bing")
+	require.Equal(t, [][2]int64{{0, 3}, {3, 9}, {9, 13}}, fragments())
+
+	// A flush with content rolls ahead of its appended content.
+	doAppend(pb.AppendRequest{Journal: "a/journal", Flush: true}, "quux")
+	require.Equal(t, [][2]int64{{0, 3}, {3, 9}, {9, 13}, {13, 17}}, fragments())
+
+	broker.cleanup()
+}
+
 func TestAppendRegisterCheckAndUpdateSequence(t *testing.T) {
 	var ctx, etcd = pb.WithDispatchDefault(context.Background()), etcdtest.TestClient()
 	defer etcdtest.Cleanup()
@@ -122,6 +181,92 @@ func TestAppendRegisterCheckAndUpdateSequence(t *testing.T) {
 	broker.cleanup()
 }
 
+func TestAppendIfHeadWithConcurrentWriters(t *testing.T) {
+	var ctx, etcd = pb.WithDispatchDefault(context.Background()), etcdtest.TestClient()
+	defer etcdtest.Cleanup()
+
+	var broker = newTestBroker(t, etcd, pb.ProcessSpec_ID{Zone: "local", Suffix: "broker"})
+	setTestJournal(broker, pb.JournalSpec{Name: "a/journal", Replication: 1}, broker.id)
+	broker.initialFragmentLoad()
+
+	var rjc = pb.NewRoutedJournalClient(broker.client(), pb.NoopDispatchRouter{})
+	var req = pb.AppendRequest{Journal: "a/journal"}
+	var content = strings.NewReader("content")
+
+	// Case: a conditional append of an empty journal, at offset zero.
+	var resp, err = client.AppendIfHead(ctx, rjc, req, 0, "token-1", content)
+	require.NoError(t, err)
+	require.Equal(t, int64(0), resp.Commit.Begin)
+	require.Equal(t, "token-1", resp.Registers.ValueOf(labels.AppendToken))
+
+	// Case: a retry of the committed append detects its prior success.
+	resp, err = client.AppendIfHead(ctx, rjc, req, 0, "token-1", content)
+	require.NoError(t, err)
+	require.Equal(t, pb.Status_WRONG_APPEND_OFFSET, resp.Status)
+	require.Nil(t, resp.Commit)
+
+	// Case: another append at the same offset fails its offset check.
+	_, err = client.AppendIfHead(ctx, rjc, req, 0, "token-2", content)
+	require.Equal(t, client.ErrWrongAppendOffset, err)
+
+	// Case: concurrent writers race to append at the current head. Each
+	// append commits exactly once, and only at the head it expected.
+	const writers, appendsPerWriter = 4, 10
+	var commitsCh = make(chan pb.Fragment, writers*appendsPerWriter)
+	var errCh = make(chan error, writers)
+
+	for w := 0; w != writers; w++ {
+		go func(w int) {
+			for n := 0; n != appendsPerWriter; {
+				var head, err = client.GetHead(ctx, rjc, req.Journal)
+				if err != nil {
+					errCh <- err
+					return
+				}
+				var token = fmt.Sprintf("writer-%d-%d", w, n)
+				resp, err := client.AppendIfHead(ctx, rjc, req, head, token, content)
+
+				if err == client.ErrWrongAppendOffset {
+					continue // Lost the race. Try again at the new head.
+				} else if err != nil {
+					errCh <- err
+					return
+				} else if resp.Commit.Begin != head {
+					errCh <- fmt.Errorf("append of %s committed at %d (not %d)", token, resp.Commit.Begin, head)
+					return
+				}
+				commitsCh <- *resp.Commit
+				n++
+			}
+			errCh <- nil
+		}(w)
+	}
+	for w := 0; w != writers; w++ {
+		require.NoError(t, <-errCh)
+	}
+	close(commitsCh)
+
+	// Expect commits are contiguous and non-overlapping.
+	var commits []pb.Fragment
+	for c := range commitsCh {
+		commits = append(commits, c)
+	}
+	sort.Slice(commits, func(i, j int) bool { return commits[i].Begin < commits[j].Begin })
+
+	var offset = int64(len("content"))
+	for _, c := range commits {
+		require.Equal(t, offset, c.Begin)
+		offset = c.End
+	}
+	require.Len(t, commits, writers*appendsPerWriter)
+
+	head, err := client.GetHead(ctx, rjc, req.Journal)
+	require.NoError(t, err)
+	require.Equal(t, offset, head)
+
+	broker.cleanup()
+}
+
 func TestAppendBadlyBehavedClientCases(t *testing.T) {
 	var ctx, etcd = pb.WithDispatchDefault(context.Background()), etcdtest.TestClient()
 	defer etcdtest.Cleanup()
@@ -264,8 +409,9 @@ func TestAppendRequestErrorCases(t *testing.T) {
 	resp, err = stream.CloseAndRecv()
 	require.NoError(t, err)
 	require.Equal(t, &pb.AppendResponse{
-		Status: pb.Status_WRONG_APPEND_OFFSET,
-		Header: *broker.header("read/only"),
+		Status:    pb.Status_WRONG_APPEND_OFFSET,
+		Header:    *broker.header("read/only"),
+		Registers: new(pb.LabelSet),
 	}, resp)
 
 	broker.cleanup()
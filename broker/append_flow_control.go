@@ -20,6 +20,17 @@ var (
 	// limit the impact of slow or faulted clients over the pipeline, which is
 	// an exclusively owned and highly contended resource.
 	MinAppendRate int64 = 1 << 16 // 64K per second.
+	// MaxSpoolBytes is a budget of content bytes which may be held by the open
+	// Spools of all journals of the broker, before they're rolled and persisted
+	// (see fragment.SpoolUsage). When the budget is exceeded, journals holding
+	// at least a fair share of the budget (the budget divided by the number of
+	// open Spools) roll their Spools ahead of their next append, even if they're
+	// under their target Fragment length. Journals holding less aren't rolled,
+	// so that a few large journals don't force the flushing of many small ones.
+	// The budget is evaluated by the primary broker of each journal, against
+	// its own usage. If zero, there is no budget. The Spool of any one journal
+	// is further bounded by its JournalSpec_Fragment.MaxSpoolBytes.
+	MaxSpoolBytes int64 = 0 // No budget.
 	// ErrFlowControlUnderflow is returned if an Append RPC was terminated due to
 	// flow control policing. Specifically, the client failed to sustain the
 	// MinAppendRate when sending content chunks of the stream.
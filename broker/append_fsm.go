@@ -249,7 +249,7 @@ func (b *appendFSM) onStartPipeline() {
 func (b *appendFSM) onSendPipelineSync() {
 	b.mustState(stateSendPipelineSync)
 
-	var proposal = maybeRollFragment(b.pln.spool, b.rollToOffset, b.resolved.journalSpec.Fragment)
+	var proposal = maybeRollFragment(b.pln.spool, b.rollToOffset, b.fragmentSpec())
 	var req = &pb.ReplicateRequest{
 		Proposal:    &proposal,
 		Registers:   &b.registers,
@@ -387,7 +387,8 @@ func (b *appendFSM) onAwaitDesiredReplicas() {
 // being written to by a separate & disconnected gazette cluster.
 //
 // Note that an AppendRequest offset may also be used outside of recovery,
-// for example to implement at-most-once writes.
+// for example to implement at-most-once writes, or with CheckOffset to
+// implement conditional (compare-and-append) writes.
 func (b *appendFSM) onValidatePreconditions() {
 	b.mustState(stateValidatePreconditions)
 
@@ -423,14 +424,16 @@ func (b *appendFSM) onValidatePreconditions() {
 
 		b.resolved.status = pb.Status_REGISTER_MISMATCH
 		b.state = stateError
-	} else if b.pln.spool.End != maxOffset && b.req.Offset == 0 && b.resolved.journalSpec.Flags.MayWrite() {
+	} else if b.pln.spool.End != maxOffset && b.req.Offset == 0 && !b.req.CheckOffset &&
+		b.resolved.journalSpec.Flags.MayWrite() {
+
 		b.resolved.status = pb.Status_INDEX_HAS_GREATER_OFFSET
 		b.state = stateError
-	} else if b.req.Offset != 0 && b.req.Offset != maxOffset {
+	} else if (b.req.Offset != 0 || b.req.CheckOffset) && b.req.Offset != maxOffset {
 		// If a request offset is present, it must match |maxOffset|.
 		b.resolved.status = pb.Status_WRONG_APPEND_OFFSET
 		b.state = stateError
-	} else if b.req.Offset != 0 && b.pln.spool.End != maxOffset {
+	} else if (b.req.Offset != 0 || b.req.CheckOffset) && b.pln.spool.End != maxOffset {
 		// Re-sync the pipeline at the explicitly requested |maxOffset|.
 		b.rollToOffset = maxOffset
 		b.state = stateSendPipelineSync
@@ -456,8 +459,13 @@ func (b *appendFSM) onStreamContent(req *pb.AppendRequest, err error) {
 
 		// Potentially roll the Fragment forward ahead of this append. Our
 		// pipeline is synchronized, so we expect this will always succeed
-		// and don't ask for an acknowledgement.
-		var proposal = maybeRollFragment(b.pln.spool, 0, b.resolved.journalSpec.Fragment)
+		// and don't ask for an acknowledgement. If the client requested a
+		// flush, we always roll to the current journal write head.
+		var rollToOffset int64
+		if b.req.Flush {
+			rollToOffset = b.pln.spool.End
+		}
+		var proposal = maybeRollFragment(b.pln.spool, rollToOffset, b.fragmentSpec())
 
 		if b.pln.spool.Fragment.Fragment != proposal {
 			b.pln.scatter(&pb.ReplicateRequest{
@@ -466,6 +474,9 @@ func (b *appendFSM) onStreamContent(req *pb.AppendRequest, err error) {
 				Acknowledge: false,
 			})
 		}
+		// Incremental compression of the primary Spool uses the current
+		// CompressionLevel, which takes effect on the next compressor built.
+		b.pln.spool.CompressionLevel = b.resolved.journalSpec.Fragment.CompressionLevel
 
 		b.clientFragment = &pb.Fragment{
 			Journal:          b.pln.spool.Journal,
@@ -611,6 +622,19 @@ func (b *appendFSM) onReadAcknowledgements() {
 	}
 }
 
+// fragmentSpec returns the JournalSpec_Fragment of the journal, having a
+// Length which is adapted to the journal's write rate (if applicable).
+func (b *appendFSM) fragmentSpec() pb.JournalSpec_Fragment {
+	var spec = b.resolved.journalSpec.Fragment
+	if spec.AdaptiveInterval == 0 {
+		return spec
+	}
+	spec.Length = b.resolved.replica.fragmentLength.observe(spec, b.pln.spool.End, timeNow())
+	fragmentTargetLengthGauge.WithLabelValues(b.resolved.journalSpec.Name.String()).Set(float64(spec.Length))
+
+	return spec
+}
+
 func (b *appendFSM) mustState(s appendState) {
 	if b.state != s {
 		var sHeap = s
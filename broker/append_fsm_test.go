@@ -430,6 +430,19 @@ func TestFSMValidatePreconditions(t *testing.T) {
 	require.Equal(t, pb.Status_WRONG_APPEND_OFFSET, fsm.resolved.status)
 	fsm.returnPipeline()
 
+	// Case: request checks for a zero offset, but the writable journal isn't
+	// empty. The remote index also has a greater offset than the pipeline,
+	// but the request expects a specific offset, and it's not matched.
+	fsm = appendFSM{svc: broker.svc, ctx: ctx, req: pb.AppendRequest{Journal: "a/journal", CheckOffset: true}}
+	fsm.onResolve()
+	fsm.resolved.journalSpec.Flags = pb.JournalSpec_O_RDWR
+	require.True(t, fsm.runTo(stateValidatePreconditions))
+	require.NotEqual(t, fsm.pln.spool.End, fsm.resolved.replica.index.EndOffset())
+	fsm.onValidatePreconditions()
+	require.Equal(t, stateError, fsm.state)
+	require.Equal(t, pb.Status_WRONG_APPEND_OFFSET, fsm.resolved.status)
+	fsm.returnPipeline()
+
 	// Case: request offset does match the max journal offset.
 	fsm = appendFSM{svc: broker.svc, ctx: ctx, req: pb.AppendRequest{Journal: "a/journal", Offset: 456}}
 	require.True(t, fsm.runTo(stateValidatePreconditions))
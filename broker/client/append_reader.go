@@ -0,0 +1,106 @@
+package client
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+
+	pb "go.gazette.dev/core/broker/protocol"
+)
+
+// AppendReader appends the content of io.Reader |r| to |journal|, without
+// first reading it fully into memory. The SplitFunc |split| defines the
+// framing of records within |r| (eg, bufio.ScanLines for newline-delimited
+// records). Each record is appended verbatim, including any delimiters which
+// |split| may strip from its returned tokens, and records are never split
+// across Append RPCs.
+//
+// Records are streamed into a sequence of AsyncAppends of the AsyncJournalClient,
+// which dispatches, orders, and retries them as it would any other append.
+// Records of other writers may be interleaved between (but not within) these
+// AsyncAppends. A record larger than the maximum size of a single append fails
+// with ErrRecordTooLarge. If an error is returned, records preceding the one
+// being read may have already been appended.
+//
+// AppendReader blocks until all records have committed, and returns the
+// journal offset through which its final append committed.
+func AppendReader(ctx context.Context, ajc AsyncJournalClient, journal pb.Journal,
+	split bufio.SplitFunc, r io.Reader) (pb.Offset, error) {
+
+	var scanner = bufio.NewScanner(r)
+	scanner.Buffer(nil, int(appendBufferCutoff))
+	scanner.Split(rawRecords(split))
+
+	var aa, last *AsyncAppend
+	var size int
+
+	for scanner.Scan() {
+		if aa == nil {
+			aa = ajc.StartAppend(pb.AppendRequest{Journal: journal}, nil)
+		}
+		var record = scanner.Bytes()
+		_, _ = aa.Writer().Write(record) // Release checks for errors.
+		size += len(record)
+
+		// Release at a record boundary once the chunk is large enough,
+		// allowing the AppendService to begin its dispatch.
+		if size >= appendReaderChunkSize {
+			if err := aa.Release(); err != nil {
+				return 0, err
+			} else if err = ctx.Err(); err != nil {
+				return 0, err
+			}
+			aa, last, size = nil, aa, 0
+		}
+	}
+
+	var err = scanner.Err()
+	if err == bufio.ErrTooLong {
+		err = ErrRecordTooLarge
+	}
+	if aa != nil {
+		// If |err| is set, the current chunk is rolled back.
+		if err = aa.Require(err).Release(); err != nil {
+			return 0, err
+		}
+		last = aa
+	} else if err != nil {
+		return 0, err
+	}
+
+	if last == nil {
+		return 0, nil // |r| was empty.
+	}
+	// Appends of the journal are ordered and later appends fail if
+	// prior ones did, so it's sufficient to wait for just the last.
+	select {
+	case <-last.Done():
+	case <-ctx.Done():
+		return 0, ctx.Err()
+	}
+	if err = last.Err(); err != nil {
+		return 0, err
+	}
+	return last.Response().Commit.End, nil
+}
+
+// rawRecords adapts a SplitFunc to instead return tokens which are the raw
+// spans of input consumed by each invocation of |split|. For example,
+// bufio.ScanLines strips trailing newlines from its returned tokens, while
+// rawRecords(bufio.ScanLines) retains them.
+func rawRecords(split bufio.SplitFunc) bufio.SplitFunc {
+	return func(data []byte, atEOF bool) (advance int, token []byte, err error) {
+		advance, token, err = split(data, atEOF)
+		if token != nil || advance != 0 {
+			token = data[:advance]
+		}
+		return
+	}
+}
+
+// ErrRecordTooLarge is returned by AppendReader and AppendService.AppendBatch
+// if a record is larger than may be written by a single append.
+var ErrRecordTooLarge = errors.New("record exceeds the maximum size of an append")
+
+var appendReaderChunkSize = 1 << 20 // 1MB.
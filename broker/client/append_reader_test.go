@@ -0,0 +1,102 @@
+package client
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing/iotest"
+
+	pb "go.gazette.dev/core/broker/protocol"
+	"go.gazette.dev/core/broker/teststub"
+	gc "gopkg.in/check.v1"
+)
+
+type AppendReaderSuite struct{}
+
+func (s *AppendReaderSuite) TestAppendAtRecordBoundaries(c *gc.C) {
+	defer func(s int, l int64) { appendReaderChunkSize, appendBufferCutoff = s, l }(
+		appendReaderChunkSize, appendBufferCutoff)
+	appendReaderChunkSize, appendBufferCutoff = 8, 16
+
+	var broker = teststub.NewBroker(c)
+	defer broker.Cleanup()
+
+	var rjc = pb.NewRoutedJournalClient(broker.Client(), pb.NoopDispatchRouter{})
+	var as = NewAppendService(context.Background(), rjc)
+
+	const fixture = "one\ntwo\nthree\nfour\nfive\nsix\nseven"
+
+	var resultCh = make(chan pb.Offset)
+	go func() {
+		var offset, err = AppendReader(context.Background(), as, "a/journal",
+			bufio.ScanLines, strings.NewReader(fixture))
+		c.Check(err, gc.IsNil)
+		resultCh <- offset
+	}()
+
+	// Serve Append RPCs until all content is appended. The AppendService may
+	// batch records differently depending on timing, but each RPC must end
+	// at a record boundary.
+	var content []byte
+	for len(content) != len(fixture) {
+		c.Check(<-broker.AppendReqCh, gc.DeepEquals, pb.AppendRequest{Journal: "a/journal"})
+
+		for req := <-broker.AppendReqCh; len(req.Content) != 0; req = <-broker.AppendReqCh {
+			content = append(content, req.Content...)
+		}
+		c.Check(<-broker.ReadLoopErrCh, gc.Equals, io.EOF)
+		c.Check(content[len(content)-1] == '\n' || len(content) == len(fixture), gc.Equals, true)
+
+		var resp = buildAppendResponseFixture(broker)
+		resp.Commit.Begin, resp.Commit.End = 0, int64(len(content))
+		broker.AppendRespCh <- resp
+	}
+	c.Check(string(content), gc.Equals, fixture)
+	c.Check(<-resultCh, gc.Equals, pb.Offset(len(fixture)))
+}
+
+func (s *AppendReaderSuite) TestErrorCases(c *gc.C) {
+	defer func(l int64) { appendBufferCutoff = l }(appendBufferCutoff)
+	appendBufferCutoff = 16
+
+	var broker = teststub.NewBroker(c)
+	defer broker.Cleanup()
+
+	var rjc = pb.NewRoutedJournalClient(broker.Client(), pb.NoopDispatchRouter{})
+	var as = NewAppendService(context.Background(), rjc)
+
+	// Case: a record is larger than the maximum append size.
+	var _, err = AppendReader(context.Background(), as, "a/journal", bufio.ScanLines,
+		strings.NewReader("a record which is too large\nok\n"))
+	c.Check(err, gc.Equals, ErrRecordTooLarge)
+
+	// Case: the reader fails.
+	_, err = AppendReader(context.Background(), as, "a/journal", bufio.ScanLines,
+		iotest.ErrReader(errors.New("whoops")))
+	c.Check(err, gc.ErrorMatches, "whoops")
+
+	// Case: the reader is empty.
+	offset, err := AppendReader(context.Background(), as, "a/journal", bufio.ScanLines,
+		strings.NewReader(""))
+	c.Check(err, gc.IsNil)
+	c.Check(offset, gc.Equals, pb.Offset(0))
+
+	// No Append RPCs were issued.
+	c.Check(as.PendingExcept(""), gc.HasLen, 0)
+}
+
+func (s *AppendReaderSuite) TestRawRecords(c *gc.C) {
+	var scanner = bufio.NewScanner(strings.NewReader("one\r\ntwo\n\nthree"))
+	scanner.Split(rawRecords(bufio.ScanLines))
+
+	var records []string
+	for scanner.Scan() {
+		records = append(records, scanner.Text())
+	}
+	c.Check(scanner.Err(), gc.IsNil)
+	c.Check(records, gc.DeepEquals, []string{"one\r\n", "two\n", "\n", "three"})
+}
+
+var _ = gc.Suite(&AppendReaderSuite{})
@@ -9,7 +9,6 @@ import (
 	"time"
 
 	"github.com/pkg/errors"
-	log "github.com/sirupsen/logrus"
 	pb "go.gazette.dev/core/broker/protocol"
 )
 
@@ -25,6 +24,23 @@ import (
 // each having buffered content to be appended. The list is dispatched in
 // FIFO order by a journal-specific goroutine.
 //
+// Appends of a journal commit in the order in which they acquire the journal
+// through StartAppend, AppendBatch, or TryAppend. Appends made by a single
+// goroutine therefore commit in the order they were made, as do appends which
+// are otherwise ordered by their callers (eg, through a mutex or channel).
+// This holds regardless of how appends are batched into Append RPCs, and
+// across appends having differing AppendRequests or dependencies. Appends of
+// different journals are ordered only through dependencies.
+//
+// By default, concurrent appends of a journal are ordered like acquisitions
+// of a sync.Mutex: a goroutine which arrives as the journal is released may
+// acquire it ahead of goroutines already waiting, which improves batching
+// but means commit order needn't match the order in which goroutines began
+// to append. Journals for which StrictOrder is true are instead acquired in
+// strict FIFO order of arrival, at the cost of throughput. In either case,
+// records of a split AppendBatch may be interleaved with appends of other
+// goroutines.
+//
 // AsyncAppends are backed by temporary files on the local disk rather
 // than memory buffers. This minimizes the impact of buffering on the heap
 // and garbage collector, and also makes AppendService tolerant to sustained
@@ -33,10 +49,52 @@ import (
 // AppendService implements the AsyncJournalClient interface.
 type AppendService struct {
 	pb.RoutedJournalClient
+	// MaxAppendSize is the maximum size of the content of an Append RPC, and
+	// should not exceed the limit of brokers or of intermediaries (such as
+	// gRPC proxies). AppendBatch and TryAppend place content of known size
+	// into Append RPCs which are within MaxAppendSize. Content of StartAppend
+	// is of unknown size, and the AppendService begins a new Append RPC only
+	// once a prior one exceeds MaxAppendSize. If zero, a default of 64MB is used.
+	MaxAppendSize int64
+	// HedgeDelay, if non-zero, is the delay after which an Append RPC which
+	// the journal primary has yet to acknowledge is hedged: the same append
+	// is also sent through an alternate member of the journal's Route, and
+	// whichever attempt commits first is used while the other is cancelled.
+	// The alternate broker proxies its attempt to the primary over its own
+	// peer connection, so a hedge routes around a slow or stalled client
+	// connection to the primary.
+	//
+	// Only the hedge attempt is fenced by an explicit journal Offset, which is
+	// that at which the prior append of this AppendService committed (or that
+	// of the AppendRequest, if set). Should another writer have since appended
+	// to the journal, the hedge fails with WRONG_APPEND_OFFSET while the first
+	// attempt proceeds as usual. An append of a journal having no known offset
+	// isn't hedged. Nor is an append of a journal whose Route is unknown or has
+	// no alternate member, so hedging requires a RoutedJournalClient having a
+	// DispatchRouter such as RouteCache.
+	//
+	// The first attempt is cancelled once a hedge commits. Much like a retried
+	// Append RPC, should the first attempt nonetheless also commit, its
+	// content is appended twice.
+	HedgeDelay time.Duration
+	// StrictOrder, if non-nil, returns whether appends of the journal are
+	// acquired in strict FIFO order of the StartAppend, AppendBatch, or
+	// TryAppend calls which queue them, rather than allowing later calls to
+	// barge ahead of earlier ones which are blocked. A TryAppend of a strictly
+	// ordered journal fails with ErrAppendWouldBlock if any call is queued.
+	//
+	// Strict ordering hands the journal directly from one caller to the next,
+	// which costs throughput as fewer appends are batched into each Append RPC.
+	// StrictOrder is evaluated as appends of an idle journal begin, and should
+	// be set before the AppendService is used.
+	StrictOrder func(pb.Journal) bool
+
 	ctx     context.Context             // Context for all appends of this service.
 	appends map[pb.Journal]*AsyncAppend // Index of the most-recent AsyncAppend.
 	errs    map[pb.Journal]error        // Index of terminal errors.
-	mu      sync.Mutex                  // Guards |appends| and |errs|.
+	failing map[pb.Journal]error        // Index of errors of Append RPCs being retried.
+	offsets pb.Offsets                  // Index of offsets at which appends last committed.
+	mu      sync.Mutex                  // Guards |appends|, |errs|, |failing|, and |offsets|.
 	pool    *sync.Pool                  // Pool of appendBuffers.
 }
 
@@ -47,6 +105,8 @@ func NewAppendService(ctx context.Context, client pb.RoutedJournalClient) *Appen
 		RoutedJournalClient: client,
 		appends:             make(map[pb.Journal]*AsyncAppend),
 		errs:                make(map[pb.Journal]error),
+		failing:             make(map[pb.Journal]error),
+		offsets:             make(pb.Offsets),
 		pool:                newAppendBufferPool(),
 	}
 }
@@ -120,7 +180,8 @@ type AsyncJournalClient interface {
 	// For performance reasons, an Append will often be batched with other Appends
 	// having identical AppendRequests which are dispatched to this AppendService,
 	// and note the Response.Fragment will reflect the entire batch written to the
-	// broker. In all cases, relative order of Appends is preserved. One or more
+	// broker. Use ReleaseCall to obtain the offsets of the caller's own content
+	// within the batch. In all cases, relative order of Appends is preserved. One or more
 	// dependencies may optionally be supplied. The Append RPC will not begin
 	// until all dependencies have completed without error. A failure of a
 	// dependency will also permanently fail the returned AsyncAppend and prevent
@@ -152,11 +213,121 @@ type AsyncJournalClient interface {
 
 // StartAppend implements the AsyncJournalClient interface.
 func (s *AppendService) StartAppend(req pb.AppendRequest, dependencies OpFutures) *AsyncAppend {
+	var aa, _ = s.startAppend(req, dependencies, 0, false)
+	return aa
+}
+
+// AppendBatch queues |records| to be appended to the journal, where each
+// record is a framed message (or other unit of content) which must not be
+// split across Append RPCs. If the batch fits within MaxAppendSize, it's
+// queued as a single AsyncAppend and is appended atomically.
+//
+// If the batch is larger than MaxAppendSize and |split| is false, AppendBatch
+// fails with ErrBatchTooLarge and queues nothing. Otherwise, records are
+// queued in order as a sequence of AsyncAppends, each within MaxAppendSize.
+// A split batch is not atomic: should an Append RPC of the journal fail,
+// records of prior AsyncAppends may have committed while later ones did not.
+// A record which alone exceeds MaxAppendSize can't be split, and fails with
+// ErrRecordTooLarge regardless of |split|.
+//
+// AppendBatch returns the released AsyncAppends of the batch, and the caller
+// may select on each Done to await its commit, after which its Response().Commit
+// is the journal offset range of its records. If an error is returned while
+// queueing a split batch, then returned AsyncAppends of prior records have
+// been released and may commit.
+func (s *AppendService) AppendBatch(req pb.AppendRequest, dependencies OpFutures,
+	records [][]byte, split bool) ([]*AsyncAppend, error) {
+
+	var limit = s.maxAppendSize()
+	var total int64
+
+	for i, r := range records {
+		if int64(len(r)) > limit {
+			return nil, fmt.Errorf("%w: record %d of %s is %d bytes, and MaxAppendSize is %d",
+				ErrRecordTooLarge, i, req.Journal, len(r), limit)
+		}
+		total += int64(len(r))
+	}
+	if !split && total > limit {
+		return nil, fmt.Errorf("%w: batch of %d records to %s is %d bytes, and MaxAppendSize is %d "+
+			"(split the batch, or allow AppendBatch to split it non-atomically)",
+			ErrBatchTooLarge, len(records), req.Journal, total, limit)
+	}
+
+	var out []*AsyncAppend
+	for len(records) != 0 {
+		// Take the next chunk of records which fits within |limit|.
+		var n, size = 0, int64(0)
+		for n != len(records) && size+int64(len(records[n])) <= limit {
+			size += int64(len(records[n]))
+			n++
+		}
+		var aa, _ = s.startAppend(req, dependencies, size, false)
+		for _, r := range records[:n] {
+			_, _ = aa.Writer().Write(r) // Release checks for errors.
+		}
+		if err := aa.Release(); err != nil {
+			return out, err // Writes of this chunk were rolled back.
+		}
+		out, records = append(out, aa), records[n:]
+	}
+	return out, nil
+}
+
+// TryAppend queues |content| to be appended to the journal, without blocking.
+// Unlike StartAppend, it never waits for exclusive access to the journal, and
+// it doesn't queue content behind Append RPCs which are failing. Instead, it
+// returns immediately with:
+//
+//   - ErrAppendWouldBlock, if another client currently holds the journal's
+//     AsyncAppend (between its StartAppend and Release), or
+//   - ErrJournalUnavailable, if an Append RPC of the journal is failing and
+//     being retried, or a prior append of the journal failed terminally.
+//
+// Callers may then apply their own overflow handling, such as spilling to
+// local storage or dropping with a counter. A failed TryAppend has queued
+// nothing: |content| is either wholly queued, or not at all.
+//
+// Otherwise, the returned AsyncAppend has already been Released, and the
+// caller may select on its Done to await its commit. Content of TryAppend is
+// ordered with respect to all other appends of the journal, including those
+// already in-flight: it's batched into the current (not yet dispatched)
+// AsyncAppend of the journal, and commits only after prior appends commit.
+// If a prior in-flight append has dependencies, TryAppend content also waits
+// upon them. And as a journal is only known to be unavailable once an Append
+// RPC has failed, a successful TryAppend may yet be queued behind an Append
+// RPC which later fails and is retried.
+func (s *AppendService) TryAppend(req pb.AppendRequest, content []byte) (*AsyncAppend, error) {
+	var aa, err = s.startAppend(req, nil, int64(len(content)), true)
+	if err != nil {
+		return nil, err
+	}
+	_, _ = aa.Writer().Write(content)
+
+	if err = aa.Release(); err != nil {
+		return nil, err // Writes were rolled back.
+	}
+	return aa, nil
+}
+
+// startAppend begins a new AsyncAppend, into which the caller will write
+// |size| bytes (or zero, if unknown). If |try|, startAppend returns an error
+// rather than blocking for exclusive access to the journal, or rather than
+// queueing behind a failing Append RPC.
+func (s *AppendService) startAppend(req pb.AppendRequest, dependencies OpFutures, size int64, try bool) (*AsyncAppend, error) {
 	// Fetch the current AsyncAppend for |name|, or start one if none exists.
 	s.mu.Lock()
 	var aa, ok = s.appends[req.Journal]
 	var err = s.errs[req.Journal]
 
+	if try && err == nil {
+		err = s.failing[req.Journal]
+	}
+	if try && err != nil {
+		s.mu.Unlock()
+		return nil, fmt.Errorf("%w: %s", ErrJournalUnavailable, err)
+	}
+
 	if !ok {
 		aa = &AsyncAppend{
 			op:           *NewAsyncOperation(),
@@ -164,14 +335,34 @@ func (s *AppendService) StartAppend(req pb.AppendRequest, dependencies OpFutures
 			dependencies: dependencies,
 			mu:           new(sync.Mutex),
 		}
+		if s.StrictOrder != nil && s.StrictOrder(req.Journal) {
+			aa.mu = new(fifoMutex)
+		}
 		s.appends[req.Journal] = aa
 	}
+	// If the journal is strictly ordered, take our place in its queue while
+	// still holding |s.mu|, which serveAppends also holds as it checks for
+	// queued callers before completing a chain.
+	var ready <-chan struct{}
+	if fm, strict := aa.mu.(*fifoMutex); strict && !try {
+		ready = fm.enqueue()
+	}
 	s.mu.Unlock()
 
 	// Acquire exclusive write access for journal |name|. This may race with
 	// other writes in progress, and on mutex acquisition a different AsyncAppend
 	// may now be current for the journal.
-	aa.mu.Lock()
+	if ready != nil {
+		<-ready
+	} else if !try {
+		aa.mu.Lock()
+	} else if !aa.mu.TryLock() {
+		if !ok {
+			// |aa| is indexed, and must be served though we didn't lock it.
+			go serveAppends(s, aa, err)
+		}
+		return nil, ErrAppendWouldBlock
+	}
 
 	// Start the service loop (if needed) *after* we acquire |aa.mu|, and
 	// *before* we skip forward to the current AsyncAppend. This ensures
@@ -190,12 +381,14 @@ func (s *AppendService) StartAppend(req pb.AppendRequest, dependencies OpFutures
 		// |aa.next| to itself. Recurse to try again.
 		if aa == aa.next {
 			aa.mu.Unlock()
-			return s.StartAppend(req, dependencies)
+			return s.startAppend(req, dependencies, size, try)
 		}
 	}
 
-	// Chain a new Append RPC if this one is over a threshold size.
+	// Chain a new Append RPC if this one is over a threshold size,
+	// or would be with the addition of |size| bytes.
 	if aa.checkpoint > appendBufferCutoff ||
+		(aa.checkpoint != 0 && aa.checkpoint+size > s.maxAppendSize()) ||
 		// Or has dependencies which are not a subset of |aa|'s.
 		!dependencies.IsSubsetOf(aa.dependencies) ||
 		// Or if the requests themselves differ.
@@ -211,7 +404,15 @@ func (s *AppendService) StartAppend(req pb.AppendRequest, dependencies OpFutures
 		// waiting on its RPC response.
 		aa.fb = s.pool.Get().(*appendBuffer)
 	}
-	return aa
+	return aa, nil
+}
+
+// maxAppendSize returns MaxAppendSize, or appendBufferCutoff if it's zero.
+func (s *AppendService) maxAppendSize() int64 {
+	if s.MaxAppendSize != 0 {
+		return s.MaxAppendSize
+	}
+	return appendBufferCutoff
 }
 
 // PendingExcept implements the AsyncJournalClient interface.
@@ -263,7 +464,7 @@ type AsyncAppend struct {
 	fb           *appendBuffer // Buffer into which writes are queued.
 	checkpoint   int64         // Buffer |fb| offset to append through.
 
-	mu   *sync.Mutex  // Shared mutex over all AsyncAppends of the journal.
+	mu   journalMutex // Shared mutex over all AsyncAppends of the journal.
 	next *AsyncAppend // Next ordered AsyncAppend of the journal.
 }
 
@@ -294,8 +495,28 @@ func (p *AsyncAppend) Require(err error) *AsyncAppend {
 // rolled back. Otherwise, the caller may then select on Done to determine when
 // the AsyncAppend has committed and its Response may be examined.
 func (p *AsyncAppend) Release() error {
+	var _, _, err = p.release()
+	return err
+}
+
+// ReleaseCall is like Release, but additionally returns an AppendCall which
+// tracks the content written by this caller (since its StartAppend). An
+// AsyncAppend may batch the writes of many callers into a single Append RPC,
+// and the AppendCall Result reports the journal offsets of this caller's
+// content alone.
+func (p *AsyncAppend) ReleaseCall() (*AppendCall, error) {
+	var begin, end, err = p.release()
+	if err != nil {
+		return nil, err
+	}
+	return &AppendCall{aa: p, begin: begin, end: end}, nil
+}
+
+// release implements Release, returning the offsets of the appendBuffer
+// content written by the caller.
+func (p *AsyncAppend) release() (begin, end int64, err error) {
 	// Require that a bufio.Writer error is not set.
-	var _, err = p.fb.buf.Write(nil)
+	_, err = p.fb.buf.Write(nil)
 	p.Require(err)
 
 	// Swap and test whether |p.op.err| was set.
@@ -303,20 +524,22 @@ func (p *AsyncAppend) Release() error {
 		// rollback in background, as it may block until an underlying disk
 		// error is resolved. Note |mu| is still held until rollback completes.
 		go p.rollback()
-		return err
+		return 0, 0, err
 	}
+	begin = p.checkpoint
 	p.checkpoint = p.fb.offset + int64(p.fb.buf.Buffered())
+	end = p.checkpoint
 	p.mu.Unlock()
 
-	return nil
+	return begin, end, nil
 }
 
 // rollback discards all content written to the Writer and releases the AsyncAppend.
 func (p *AsyncAppend) rollback() {
 	// flush as |p.checkpoint| may reference still-buffered content.
-	retryUntil(p.fb.flush, p.app.Request.Journal, "failed to flush appendBuffer")
+	retryUntil(p.app.ctx, p.fb.flush, p.app.Request.Journal, "failed to flush appendBuffer")
 	p.fb.offset = p.checkpoint
-	retryUntil(p.fb.seek, p.app.Request.Journal, "failed to seek appendBuffer")
+	retryUntil(p.app.ctx, p.fb.seek, p.app.Request.Journal, "failed to seek appendBuffer")
 
 	p.mu.Unlock()
 }
@@ -336,6 +559,63 @@ func (p *AsyncAppend) Done() <-chan struct{} { return p.op.Done() }
 // Err blocks until Done, and returns the final operation error.
 func (p *AsyncAppend) Err() error { return p.op.Err() }
 
+// Result returns the AppendResult of the AsyncAppend, and may be called only
+// after Done selects. Its Begin and End span all batched content of the
+// Append RPC. Use ReleaseCall to obtain the offsets of a caller's content.
+func (p *AsyncAppend) Result() AppendResult {
+	return newAppendResult(p.app.Request.Journal, p.app.Response, 0, p.checkpoint)
+}
+
+// AppendCall is the content written to an AsyncAppend by a single caller,
+// between its StartAppend and ReleaseCall.
+type AppendCall struct {
+	aa         *AsyncAppend
+	begin, end int64 // Offsets of the caller's content within the AsyncAppend.
+}
+
+// Done returns a channel which selects when the AsyncAppend has committed
+// or has been aborted along with the AppendService's Context.
+func (c *AppendCall) Done() <-chan struct{} { return c.aa.Done() }
+
+// Err blocks until Done, and returns the final operation error.
+func (c *AppendCall) Err() error { return c.aa.Err() }
+
+// Result returns the AppendResult of the caller's content, and may be called
+// only after Done selects. Its Begin and End are the journal offsets of the
+// caller's content, while its Fragment spans the entire Append RPC.
+func (c *AppendCall) Result() AppendResult {
+	return newAppendResult(c.aa.app.Request.Journal, c.aa.app.Response, c.begin, c.end)
+}
+
+// AppendResult is the result of a committed append.
+type AppendResult struct {
+	// Journal which was appended to.
+	Journal pb.Journal
+	// Begin and End offsets of the committed content.
+	Begin, End pb.Offset
+	// Fragment of the Append RPC which committed the content. If the content
+	// was batched with that of other appends, the Fragment spans them all.
+	Fragment pb.Fragment
+	// Route of the journal, as reported by the broker which served the RPC.
+	Route pb.Route
+}
+
+// newAppendResult returns an AppendResult of content at [|begin|, |end|)
+// of the Append RPC having response |resp|. If the RPC didn't commit,
+// the returned AppendResult has only its Journal and Route.
+func newAppendResult(journal pb.Journal, resp pb.AppendResponse, begin, end int64) AppendResult {
+	var out = AppendResult{
+		Journal: journal,
+		Route:   resp.Header.Route,
+	}
+	if resp.Commit != nil {
+		out.Begin = resp.Commit.Begin + begin
+		out.End = resp.Commit.Begin + end
+		out.Fragment = *resp.Commit
+	}
+	return out
+}
+
 // serveAppends executes Append RPCs specified by a (potentially growing) chain
 // of ordered AsyncAppends until none remain. Each RPC is retried until
 // successful. As each AsyncAppend is completed (up to and including the last
@@ -350,16 +630,22 @@ var serveAppends = func(s *AppendService, aa *AsyncAppend, err error) {
 		if aa.next == nil && aa.fb == nil && aa.dependencies == nil {
 
 			s.mu.Lock()
-			delete(s.appends, aa.Request().Journal)
-			if err != nil {
-				s.errs[aa.Request().Journal] = err
+			// If callers are queued for a strictly ordered journal, continue to
+			// serve the chain rather than have them begin a new one, which
+			// a later caller could otherwise begin first.
+			if fm, strict := aa.mu.(*fifoMutex); !strict || fm.queued() == 0 {
+				delete(s.appends, aa.Request().Journal)
+				if err != nil {
+					s.errs[aa.Request().Journal] = err
+				}
+				s.mu.Unlock()
+
+				aa.next = aa // Mark |aa| as completed.
+				aa.op.Resolve(err)
+				aa.mu.Unlock()
+				return
 			}
 			s.mu.Unlock()
-
-			aa.next = aa // Mark |aa| as completed.
-			aa.op.Resolve(err)
-			aa.mu.Unlock()
-			return
 		}
 
 		if aa.next == nil {
@@ -383,25 +669,25 @@ var serveAppends = func(s *AppendService, aa *AsyncAppend, err error) {
 		// client can possibly be waiting on its response. We skip performing
 		// an Append RPC altogether in this case.
 		if aa.fb != nil {
-			retryUntil(aa.fb.flush, aa.app.Request.Journal, "failed to flush appendBuffer")
+			retryUntil(aa.app.ctx, aa.fb.flush, aa.app.Request.Journal, "failed to flush appendBuffer")
 
 			if err == nil {
-				retryUntil(func() error {
-					var _, err2 = io.Copy(&aa.app, io.NewSectionReader(aa.fb.file, 0, aa.checkpoint))
-					if err2 == nil {
-						err2 = aa.app.Close()
-					}
+				retryUntil(s.ctx, func() error {
+					var err2 = s.appendOnce(aa)
+					var tpe *TenantPrefixError
 
-					if err2 == context.Canceled || err2 == context.DeadlineExceeded {
+					if err2 == context.Canceled || err2 == context.DeadlineExceeded || errors.As(err2, &tpe) {
 						err = err2
 						return nil // Break retry loop.
 					} else if err2 != nil {
+						s.setFailing(aa.app.Request.Journal, err2)
 						aa.app.Reset()
 						return err2 // Retry by returning |err2|.
 					} else {
 						return nil // Success; break loop.
 					}
 				}, aa.app.Request.Journal, "failed to append to journal")
+				s.setFailing(aa.app.Request.Journal, nil)
 			}
 
 			aa.fb.releaseToPool()
@@ -419,6 +705,162 @@ var serveAppends = func(s *AppendService, aa *AsyncAppend, err error) {
 	}
 }
 
+// appendOnce makes a single attempt of the Append RPC of |aa|, which is
+// hedged if the AppendService has a HedgeDelay and the journal offset is known.
+func (s *AppendService) appendOnce(aa *AsyncAppend) error {
+	if s.HedgeDelay == 0 {
+		return appendContent(&aa.app, aa)
+	}
+	var journal = aa.app.Request.Journal
+	var offset = aa.app.Request.Offset
+
+	s.mu.Lock()
+	if offset == 0 {
+		offset = s.offsets[journal]
+	}
+	s.mu.Unlock()
+
+	var resp, err = s.hedgedAppend(aa, offset)
+
+	if errors.Is(err, ErrWrongAppendOffset) && aa.app.Request.Offset == 0 {
+		// The hedge was fenced by an offset made stale by another writer of the
+		// journal, and its attempt failed last. This isn't a failure of the
+		// journal: retry immediately, without an offset or a hedge.
+		aa.app.Reset()
+		if err = appendContent(&aa.app, aa); err == nil {
+			resp = aa.app.Response
+		}
+	}
+
+	s.mu.Lock()
+	if err == nil {
+		aa.app.Response = resp
+		s.offsets[journal] = resp.Commit.End
+	} else {
+		delete(s.offsets, journal)
+	}
+	s.mu.Unlock()
+
+	return err
+}
+
+// appendContent appends the content of |aa| using Appender |app|.
+func appendContent(app *Appender, aa *AsyncAppend) error {
+	var _, err = io.Copy(app, io.NewSectionReader(aa.fb.file, 0, aa.checkpoint))
+	if err == nil {
+		err = app.Close()
+	}
+	return err
+}
+
+// hedgedAppend appends the content of |aa|. If the append isn't acknowledged
+// within HedgeDelay, it's also sent at |offset| through an alternate Route
+// member, and the response of the first attempt to commit is returned.
+// A zero |offset| can't fence the hedge, and the append isn't hedged.
+func (s *AppendService) hedgedAppend(aa *AsyncAppend, offset pb.Offset) (pb.AppendResponse, error) {
+	var journal = aa.app.Request.Journal
+
+	var ctx, cancel = context.WithCancel(s.ctx)
+	defer cancel()
+
+	type result struct {
+		app   *Appender
+		hedge bool
+		err   error
+	}
+	var resultCh = make(chan result, 2)
+	var pending int
+
+	var start = func(req pb.AppendRequest, dispatchTo pb.ProcessSpec_ID) {
+		var app = NewAppender(ctx, s.RoutedJournalClient, req)
+		app.dispatchTo = dispatchTo
+		pending++
+
+		go func() {
+			var _, err = io.Copy(app, io.NewSectionReader(aa.fb.file, 0, aa.checkpoint))
+			if err == nil {
+				err = app.Close()
+			} else {
+				app.Abort()
+			}
+			resultCh <- result{app: app, hedge: dispatchTo != (pb.ProcessSpec_ID{}), err: err}
+		}()
+	}
+	start(aa.app.Request, pb.ProcessSpec_ID{})
+
+	var hedgeCh <-chan time.Time
+	if offset != 0 {
+		var timer = time.NewTimer(s.HedgeDelay)
+		defer timer.Stop()
+		hedgeCh = timer.C
+	}
+
+	var out result
+	for {
+		select {
+		case <-hedgeCh:
+			hedgeCh = nil
+
+			if id, ok := s.hedgeMember(ctx, journal); ok {
+				appendHedgesTriggered.WithLabelValues(journal.String()).Inc()
+
+				var req = aa.app.Request
+				req.Offset = offset
+				start(req, id)
+			}
+			continue
+
+		case r := <-resultCh:
+			pending--
+
+			if out.app == nil || out.err != nil {
+				out = r
+			}
+		}
+
+		if out.err == nil {
+			cancel() // Cancel the other attempt.
+		}
+		if out.err == nil || pending == 0 {
+			break
+		}
+	}
+	// Attempts read |aa.fb|, and must finish before it's released.
+	for ; pending != 0; pending-- {
+		<-resultCh
+	}
+
+	if out.err != nil {
+		return pb.AppendResponse{}, out.err
+	} else if out.hedge {
+		appendHedgesWon.WithLabelValues(journal.String()).Inc()
+	}
+	return out.app.Response, nil
+}
+
+// hedgeMember returns a member of the journal's Route other than its primary,
+// or false if there is none.
+func (s *AppendService) hedgeMember(ctx context.Context, journal pb.Journal) (pb.ProcessSpec_ID, bool) {
+	var rt = s.Route(ctx, journal.String())
+
+	if rt.Primary == -1 || len(rt.Members) < 2 {
+		return pb.ProcessSpec_ID{}, false
+	}
+	return rt.Members[(int(rt.Primary)+1)%len(rt.Members)], true
+}
+
+// setFailing sets or (if |err| is nil) clears the error of a failing
+// Append RPC of the journal.
+func (s *AppendService) setFailing(journal pb.Journal, err error) {
+	s.mu.Lock()
+	if err != nil {
+		s.failing[journal] = err
+	} else {
+		delete(s.failing, journal)
+	}
+	s.mu.Unlock()
+}
+
 // appendBuffer composes a backing File with a bufio.Writer, and additionally
 // tracks the offset through which the file is written.
 type appendBuffer struct {
@@ -501,7 +943,7 @@ func (r returnNReader) Read(p []byte) (int, error) {
 
 func (fb *appendBuffer) releaseToPool() {
 	fb.offset = 0
-	retryUntil(fb.seek, "", "failed to seek appendBuffer")
+	retryUntil(context.Background(), fb.seek, "", "failed to seek appendBuffer")
 	fb.pool.Put(fb)
 }
 
@@ -511,7 +953,7 @@ func newAppendBufferPool() *sync.Pool {
 	pool.New = func() interface{} {
 		var fb *appendBuffer
 
-		retryUntil(func() (err error) {
+		retryUntil(context.Background(), func() (err error) {
 			fb, err = newAppendBuffer()
 			return
 		}, "", "failed to create appendBuffer")
@@ -523,24 +965,109 @@ func newAppendBufferPool() *sync.Pool {
 	return pool
 }
 
-func retryUntil(fn func() error, journal pb.Journal, msg string) {
+// retryUntil calls |fn| until it succeeds, logging failures through the
+// Logger of |ctx| (see WithLogger).
+func retryUntil(ctx context.Context, fn func() error, journal pb.Journal, msg string) {
 	for attempt := 0; true; attempt++ {
 		var err = fn()
 		if err == nil {
 			return
 		}
-		var fields = log.Fields{"err": err, "attempt": attempt}
+		var fields = LogFields{"err": err, "attempt": attempt}
 		if journal != "" {
 			fields["journal"] = journal
 		}
 		if attempt != 0 {
-			log.WithFields(fields).Warn(msg + " (will retry)")
+			loggerFrom(ctx).Log(LogWarn, msg+" (will retry)", fields)
 		}
 		time.Sleep(backoff(attempt))
 	}
 }
 
+var (
+	// ErrAppendWouldBlock is returned by TryAppend if another client holds
+	// the current AsyncAppend of the journal.
+	ErrAppendWouldBlock = errors.New("append would block")
+	// ErrJournalUnavailable is returned by TryAppend if Append RPCs of the
+	// journal are failing.
+	ErrJournalUnavailable = errors.New("journal unavailable")
+	// ErrBatchTooLarge is returned by AppendBatch if a batch which may not be
+	// split exceeds the AppendService's MaxAppendSize.
+	ErrBatchTooLarge = errors.New("batch exceeds the maximum size of an append")
+)
+
 var (
 	appendBufferSize         = 8 * 1024 // 8KB.
 	appendBufferCutoff int64 = 1 << 26  // 64MB.
 )
+
+// journalMutex is shared by all AsyncAppends of a journal. It's a *sync.Mutex,
+// or a *fifoMutex if the journal is strictly ordered.
+type journalMutex interface {
+	Lock()
+	TryLock() bool
+	Unlock()
+}
+
+// fifoMutex is a mutex which is acquired in the FIFO order of its Lock (or
+// enqueue) calls. Unlike sync.Mutex, a goroutine never barges ahead of others
+// which are already waiting: Unlock hands the fifoMutex directly to the next.
+type fifoMutex struct {
+	mu      sync.Mutex
+	locked  bool
+	waiters []chan struct{}
+}
+
+// Lock the fifoMutex, blocking until it's acquired.
+func (m *fifoMutex) Lock() { <-m.enqueue() }
+
+// TryLock acquires the fifoMutex only if it's unlocked, and returns whether it did.
+func (m *fifoMutex) TryLock() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.locked {
+		return false
+	}
+	m.locked = true
+	return true
+}
+
+// Unlock the fifoMutex, handing it to the next waiter (if any).
+func (m *fifoMutex) Unlock() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if !m.locked {
+		panic("unlock of unlocked fifoMutex")
+	} else if len(m.waiters) == 0 {
+		m.locked = false
+		return
+	}
+	close(m.waiters[0])
+	m.waiters[0], m.waiters = nil, m.waiters[1:]
+}
+
+// enqueue the caller for the fifoMutex, returning a channel which is closed
+// when it has been acquired.
+func (m *fifoMutex) enqueue() <-chan struct{} {
+	var ch = make(chan struct{})
+
+	m.mu.Lock()
+	if !m.locked {
+		m.locked = true
+		close(ch)
+	} else {
+		m.waiters = append(m.waiters, ch)
+	}
+	m.mu.Unlock()
+
+	return ch
+}
+
+// queued returns the number of callers waiting to acquire the fifoMutex.
+func (m *fifoMutex) queued() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.waiters)
+}
@@ -5,9 +5,13 @@ import (
 	"bytes"
 	"context"
 	"errors"
+	"fmt"
 	"io"
+	"strings"
 	"sync"
+	"time"
 
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	pb "go.gazette.dev/core/broker/protocol"
 	"go.gazette.dev/core/broker/teststub"
 	gc "gopkg.in/check.v1"
@@ -578,6 +582,398 @@ func (mf *mockFile) Seek(offset int64, whence int) (int64, error) {
 	return offset, nil
 }
 
+func (s *AppendServiceSuite) TestTryAppend(c *gc.C) {
+	var broker = teststub.NewBroker(c)
+	defer broker.Cleanup()
+
+	var rjc = pb.NewRoutedJournalClient(broker.Client(), pb.NoopDispatchRouter{})
+	var as = NewAppendService(context.Background(), rjc)
+	var req = pb.AppendRequest{Journal: "a/journal"}
+
+	var serveCh, cleanup = gateServeAppends()
+
+	// Case: another client holds the journal's AsyncAppend.
+	var aa = as.StartAppend(req, nil)
+	var _, err = as.TryAppend(req, []byte("dropped"))
+	c.Check(err, gc.Equals, ErrAppendWouldBlock)
+
+	_, _ = aa.Writer().WriteString("hello, ")
+	c.Assert(aa.Release(), gc.IsNil)
+
+	// Case: TryAppend is batched with the prior append, which isn't yet dispatched.
+	tryAA, err := as.TryAppend(req, []byte("world"))
+	c.Assert(err, gc.IsNil)
+	c.Check(tryAA, gc.Equals, aa)
+
+	serveCh <- struct{}{} // Allow serveAppends to begin.
+	cleanup()
+
+	// Expect the failed TryAppend left no partial content.
+	readHelloWorldAppendRequest(c, broker)
+
+	// Case: the Append RPC fails, and is being retried.
+	broker.WriteLoopErrCh <- errors.New("first attempt fails")
+	readHelloWorldAppendRequest(c, broker) // RPC is retried.
+
+	_, err = as.TryAppend(req, []byte("dropped"))
+	c.Check(errors.Is(err, ErrJournalUnavailable), gc.Equals, true)
+	c.Check(err, gc.ErrorMatches, "journal unavailable: .*first attempt fails.*")
+
+	broker.AppendRespCh <- buildAppendResponseFixture(broker) // Success.
+	c.Check(aa.Err(), gc.IsNil)
+
+	// Case: the journal is once again available.
+	tryAA, err = as.TryAppend(req, []byte("hello, world"))
+	c.Assert(err, gc.IsNil)
+
+	readHelloWorldAppendRequest(c, broker)
+	broker.AppendRespCh <- buildAppendResponseFixture(broker)
+	c.Check(tryAA.Err(), gc.IsNil)
+
+	// Case: a prior append of the journal failed terminally.
+	var failAA = as.StartAppend(req, OpFutures{FinishedOperation(errors.New("an error")): {}})
+	c.Assert(failAA.Release(), gc.IsNil)
+	c.Check(failAA.Err(), gc.ErrorMatches, "dependency failed: an error")
+
+	// Wait for serveAppends to index the terminal error of the journal.
+	for ok := false; !ok; {
+		as.mu.Lock()
+		_, ok = as.errs[req.Journal]
+		as.mu.Unlock()
+	}
+	_, err = as.TryAppend(req, []byte("dropped"))
+	c.Check(errors.Is(err, ErrJournalUnavailable), gc.Equals, true)
+	c.Check(err, gc.ErrorMatches, "journal unavailable: dependency failed: an error")
+}
+
+func (s *AppendServiceSuite) TestAppendBatch(c *gc.C) {
+	var broker = teststub.NewBroker(c)
+	defer broker.Cleanup()
+
+	var rjc = pb.NewRoutedJournalClient(broker.Client(), pb.NoopDispatchRouter{})
+	var as = NewAppendService(context.Background(), rjc)
+	as.MaxAppendSize = 12
+
+	var req = pb.AppendRequest{Journal: "a/journal"}
+	var records = [][]byte{[]byte("hello, "), []byte("world"), []byte("hello, world")}
+
+	// Case: a record alone exceeds MaxAppendSize.
+	var _, err = as.AppendBatch(req, nil, [][]byte{[]byte("hello, world!")}, true)
+	c.Check(errors.Is(err, ErrRecordTooLarge), gc.Equals, true)
+	c.Check(err, gc.ErrorMatches, "record exceeds the maximum size of an append: "+
+		"record 0 of a/journal is 13 bytes, and MaxAppendSize is 12")
+
+	// Case: the batch exceeds MaxAppendSize, and may not be split.
+	_, err = as.AppendBatch(req, nil, records, false)
+	c.Check(errors.Is(err, ErrBatchTooLarge), gc.Equals, true)
+	c.Check(err, gc.ErrorMatches, "batch exceeds the maximum size of an append: "+
+		"batch of 3 records to a/journal is 24 bytes, and MaxAppendSize is 12 .*")
+
+	var serveCh, cleanup = gateServeAppends()
+	defer cleanup()
+
+	// Case: the batch is split at record boundaries into separate appends.
+	// Expect the first isn't batched with a prior, partial append.
+	var aa = as.StartAppend(req, nil)
+	_, _ = aa.Writer().WriteString("hi")
+	c.Assert(aa.Release(), gc.IsNil)
+
+	split, err := as.AppendBatch(req, nil, records, true)
+	c.Assert(err, gc.IsNil)
+	c.Assert(split, gc.HasLen, 2)
+	c.Check(split[0] != aa, gc.Equals, true)
+
+	close(serveCh)
+	c.Check(<-broker.AppendReqCh, gc.DeepEquals, req)
+	c.Check(<-broker.AppendReqCh, gc.DeepEquals, pb.AppendRequest{Content: []byte("hi")})
+	c.Check(<-broker.AppendReqCh, gc.DeepEquals, pb.AppendRequest{})
+	c.Check(<-broker.ReadLoopErrCh, gc.Equals, io.EOF)
+	broker.AppendRespCh <- buildAppendResponseFixture(broker)
+
+	for _, aa := range split {
+		readHelloWorldAppendRequest(c, broker)
+		broker.AppendRespCh <- buildAppendResponseFixture(broker)
+		c.Check(aa.Err(), gc.IsNil)
+		c.Check(aa.Response().Commit.End, gc.Equals, pb.Offset(106))
+	}
+
+	// Case: a batch within MaxAppendSize is a single append.
+	batch, err := as.AppendBatch(req, nil, records[:2], false)
+	c.Assert(err, gc.IsNil)
+	c.Assert(batch, gc.HasLen, 1)
+
+	readHelloWorldAppendRequest(c, broker)
+	broker.AppendRespCh <- buildAppendResponseFixture(broker)
+	c.Check(batch[0].Err(), gc.IsNil)
+}
+
+func (s *AppendServiceSuite) TestReleaseCallResults(c *gc.C) {
+	var broker = teststub.NewBroker(c)
+	defer broker.Cleanup()
+
+	var rjc = pb.NewRoutedJournalClient(broker.Client(), pb.NoopDispatchRouter{})
+	var as = NewAppendService(context.Background(), rjc)
+
+	var serveCh, cleanup = gateServeAppends()
+	defer cleanup()
+
+	// Two callers write content which is batched into a single Append RPC.
+	var aa = as.StartAppend(pb.AppendRequest{Journal: "a/journal"}, nil)
+	_, _ = aa.Writer().WriteString("hello, ")
+	first, err := aa.ReleaseCall()
+	c.Assert(err, gc.IsNil)
+
+	aa = as.StartAppend(pb.AppendRequest{Journal: "a/journal"}, nil)
+	_, _ = aa.Writer().WriteString("world")
+	second, err := aa.ReleaseCall()
+	c.Assert(err, gc.IsNil)
+
+	// A rolled-back call returns no AppendCall.
+	aa = as.StartAppend(pb.AppendRequest{Journal: "a/journal"}, nil)
+	_, _ = aa.Writer().WriteString("discarded")
+	third, err := aa.Require(errors.New("whoops")).ReleaseCall()
+	c.Check(err, gc.ErrorMatches, "whoops")
+	c.Check(third, gc.IsNil)
+
+	close(serveCh)
+	readHelloWorldAppendRequest(c, broker)
+
+	var resp = buildAppendResponseFixture(broker)
+	resp.Commit.End = 112
+	broker.AppendRespCh <- resp
+
+	c.Check(first.Err(), gc.IsNil)
+	c.Check(second.Err(), gc.IsNil)
+
+	// Expect each AppendCall reports the offsets of its own content,
+	// and the Fragment and Route of the Append RPC.
+	c.Check(first.Result(), gc.DeepEquals, AppendResult{
+		Journal:  "a/journal",
+		Begin:    100,
+		End:      107,
+		Fragment: *resp.Commit,
+		Route:    resp.Header.Route,
+	})
+	c.Check(second.Result(), gc.DeepEquals, AppendResult{
+		Journal:  "a/journal",
+		Begin:    107,
+		End:      112,
+		Fragment: *resp.Commit,
+		Route:    resp.Header.Route,
+	})
+	// The AsyncAppend Result spans all batched content.
+	c.Check(aa.Result(), gc.DeepEquals, AppendResult{
+		Journal:  "a/journal",
+		Begin:    100,
+		End:      112,
+		Fragment: *resp.Commit,
+		Route:    resp.Header.Route,
+	})
+}
+
+func (s *AppendServiceSuite) TestConcurrentAppendsPreserveGoroutineOrder(c *gc.C) {
+	for _, strict := range []bool{false, true} {
+		var broker = teststub.NewBroker(c)
+
+		var rjc = pb.NewRoutedJournalClient(broker.Client(), pb.NoopDispatchRouter{})
+		var as = NewAppendService(context.Background(), rjc)
+		as.MaxAppendSize = 64 // Chain many Append RPCs.
+		as.StrictOrder = func(pb.Journal) bool { return strict }
+
+		var content = serveAppendContent(broker)
+		const goroutines, appends = 8, 200
+
+		var wg sync.WaitGroup
+		for g := 0; g != goroutines; g++ {
+			wg.Add(1)
+
+			go func(g int) {
+				defer wg.Done()
+				var req = pb.AppendRequest{Journal: "a/journal"}
+
+				for n := 0; n != appends; n++ {
+					var record = []byte(fmt.Sprintf("%d:%d\n", g, n))
+					var aa *AsyncAppend
+
+					// Interleave each of the ways content may be appended.
+					switch n % 3 {
+					case 0:
+						aa = as.StartAppend(req, nil)
+						_, _ = aa.Writer().Write(record)
+						c.Check(aa.Release(), gc.IsNil)
+					case 1:
+						var out, err = as.AppendBatch(req, nil, [][]byte{record}, false)
+						c.Check(err, gc.IsNil)
+						aa = out[0]
+					case 2:
+						var err error
+						for aa, err = as.TryAppend(req, record); err == ErrAppendWouldBlock; {
+							aa, err = as.TryAppend(req, record)
+						}
+						c.Check(err, gc.IsNil)
+					}
+					if n == appends-1 {
+						c.Check(aa.Err(), gc.IsNil)
+					}
+				}
+			}(g)
+		}
+		wg.Wait()
+
+		// Expect each goroutine's records were appended in order, and exactly once.
+		var next = make(map[int]int)
+		for _, line := range strings.Split(strings.TrimSuffix(<-content, "\n"), "\n") {
+			var g, n int
+			var _, err = fmt.Sscanf(line, "%d:%d", &g, &n)
+			c.Assert(err, gc.IsNil)
+			c.Check(n, gc.Equals, next[g])
+			next[g] = n + 1
+		}
+		for g := 0; g != goroutines; g++ {
+			c.Check(next[g], gc.Equals, appends)
+		}
+		broker.Cleanup()
+	}
+}
+
+func (s *AppendServiceSuite) TestStrictOrderIsFIFO(c *gc.C) {
+	var broker = teststub.NewBroker(c)
+	defer broker.Cleanup()
+
+	var rjc = pb.NewRoutedJournalClient(broker.Client(), pb.NoopDispatchRouter{})
+	var as = NewAppendService(context.Background(), rjc)
+	as.StrictOrder = func(pb.Journal) bool { return true }
+	var req = pb.AppendRequest{Journal: "a/journal"}
+
+	var serveCh, cleanup = gateServeAppends()
+	defer cleanup()
+
+	// Hold the journal, and queue further appends behind it one at a time.
+	var aa = as.StartAppend(req, nil)
+	_, _ = aa.Writer().WriteString("0 ")
+
+	var fm = aa.mu.(*fifoMutex)
+	var done = make(chan *AsyncAppend)
+
+	for i := 1; i != 10; i++ {
+		go func(i int) {
+			var aa = as.StartAppend(req, nil)
+			_, _ = aa.Writer().WriteString(fmt.Sprintf("%d ", i))
+			c.Check(aa.Release(), gc.IsNil)
+			done <- aa
+		}(i)
+
+		for fm.queued() != i {
+			time.Sleep(time.Millisecond)
+		}
+		// A TryAppend never barges ahead of queued appends.
+		var _, err = as.TryAppend(req, []byte("dropped"))
+		c.Check(err, gc.Equals, ErrAppendWouldBlock)
+	}
+	close(serveCh) // serveAppends queues after all clients.
+
+	var content = serveAppendContent(broker)
+	c.Check(aa.Release(), gc.IsNil)
+
+	for i := 1; i != 10; i++ {
+		c.Check(<-done, gc.Equals, aa) // All appends were batched.
+	}
+	c.Check(aa.Err(), gc.IsNil)
+	c.Check(<-content, gc.Equals, "0 1 2 3 4 5 6 7 8 9 ")
+}
+
+func (s *AppendServiceSuite) TestHedgedAppends(c *gc.C) {
+	var primary, alternate = teststub.NewBroker(c), teststub.NewBroker(c)
+	defer primary.Cleanup()
+	defer alternate.Cleanup()
+
+	var route = pb.Route{
+		Members:   []pb.ProcessSpec_ID{{Zone: "a", Suffix: "broker-one"}, {Zone: "a", Suffix: "broker-two"}},
+		Endpoints: []pb.Endpoint{primary.Endpoint(), alternate.Endpoint()},
+		Primary:   0,
+	}
+	var rc = NewRouteCache(1, time.Hour)
+	rc.UpdateRoute("a/journal", &route)
+
+	var rjc = pb.NewRoutedJournalClient(primary.Client(), rc)
+	var as = NewAppendService(context.Background(), rjc)
+	as.HedgeDelay = time.Millisecond
+
+	var respAt = func(end pb.Offset, status pb.Status) pb.AppendResponse {
+		var resp = buildAppendResponseFixture(primary)
+		resp.Status = status
+		resp.Header.ProcessId = route.Members[0]
+		resp.Header.Route = route
+		resp.Commit.Begin, resp.Commit.End = end-12, end
+		if status != pb.Status_OK {
+			resp.Commit = nil
+		}
+		return resp
+	}
+	var readRequest = func(broker *teststub.Broker, offset pb.Offset) {
+		c.Check(<-broker.AppendReqCh, gc.DeepEquals, pb.AppendRequest{Journal: "a/journal", Offset: offset})
+		c.Check(<-broker.AppendReqCh, gc.DeepEquals, pb.AppendRequest{Content: []byte("hello, world")})
+		c.Check(<-broker.AppendReqCh, gc.DeepEquals, pb.AppendRequest{})
+		c.Check(<-broker.ReadLoopErrCh, gc.Equals, io.EOF)
+	}
+	var write = func() *AsyncAppend {
+		var aa = as.StartAppend(pb.AppendRequest{Journal: "a/journal"}, nil)
+		_, _ = aa.Writer().WriteString("hello, world")
+		c.Assert(aa.Release(), gc.IsNil)
+		return aa
+	}
+	var triggered = appendHedgesTriggered.WithLabelValues("a/journal")
+	var won = appendHedgesWon.WithLabelValues("a/journal")
+	var triggered0, won0 = testutil.ToFloat64(triggered), testutil.ToFloat64(won)
+
+	// Case: the journal offset isn't known, and the append isn't hedged.
+	var aa = write()
+	readRequest(primary, 0)
+	primary.AppendRespCh <- respAt(112, pb.Status_OK)
+	c.Check(aa.Err(), gc.IsNil)
+	c.Check(testutil.ToFloat64(triggered)-triggered0, gc.Equals, 0.0)
+
+	// Case: the primary is slow, and the append is hedged at the offset of
+	// the prior commit. The hedge commits first, and the primary is cancelled.
+	aa = write()
+	readRequest(primary, 0)
+	readRequest(alternate, 112)
+	alternate.AppendRespCh <- respAt(124, pb.Status_OK)
+	c.Check(aa.Err(), gc.IsNil)
+	c.Check(aa.Response().Commit.End, gc.Equals, pb.Offset(124))
+	primary.WriteLoopErrCh <- errors.New("cancelled")
+
+	c.Check(testutil.ToFloat64(triggered)-triggered0, gc.Equals, 1.0)
+	c.Check(testutil.ToFloat64(won)-won0, gc.Equals, 1.0)
+
+	// Case: another writer also appends to the journal. The hedge fails on its
+	// stale offset, while the primary attempt (having no offset) commits.
+	aa = write()
+	readRequest(primary, 0)
+	readRequest(alternate, 124)
+	alternate.AppendRespCh <- respAt(0, pb.Status_WRONG_APPEND_OFFSET)
+	primary.AppendRespCh <- respAt(300, pb.Status_OK)
+	c.Check(aa.Err(), gc.IsNil)
+	c.Check(aa.Response().Commit.End, gc.Equals, pb.Offset(300))
+
+	c.Check(testutil.ToFloat64(triggered)-triggered0, gc.Equals, 2.0)
+	c.Check(testutil.ToFloat64(won)-won0, gc.Equals, 1.0)
+
+	// The journal isn't marked as failing, and TryAppend remains available.
+	as.mu.Lock()
+	c.Check(as.failing, gc.HasLen, 0)
+	as.mu.Unlock()
+
+	aa, err := as.TryAppend(pb.AppendRequest{Journal: "a/journal"}, []byte("hello, world"))
+	c.Assert(err, gc.IsNil)
+	readRequest(primary, 0)
+	readRequest(alternate, 300)
+	alternate.AppendRespCh <- respAt(0, pb.Status_WRONG_APPEND_OFFSET)
+	primary.AppendRespCh <- respAt(400, pb.Status_OK)
+	c.Check(aa.Err(), gc.IsNil)
+	c.Check(aa.Response().Commit.End, gc.Equals, pb.Offset(400))
+}
+
 func readHelloWorldAppendRequest(c *gc.C, broker *teststub.Broker) {
 	c.Check(<-broker.AppendReqCh, gc.DeepEquals, pb.AppendRequest{Journal: "a/journal"})
 	c.Check(<-broker.AppendReqCh, gc.DeepEquals, pb.AppendRequest{Content: []byte("hello, world")})
@@ -585,6 +981,30 @@ func readHelloWorldAppendRequest(c *gc.C, broker *teststub.Broker) {
 	c.Check(<-broker.ReadLoopErrCh, gc.Equals, io.EOF)
 }
 
+// serveAppendContent serves Append RPCs of the |broker| until no further RPC
+// is started for a short while, and then sends all appended content.
+func serveAppendContent(broker *teststub.Broker) <-chan string {
+	var out = make(chan string, 1)
+
+	go func() {
+		var content bytes.Buffer
+		for {
+			select {
+			case <-broker.AppendReqCh: // Header of next Append RPC.
+			case <-time.After(100 * time.Millisecond):
+				out <- content.String()
+				return
+			}
+			for req := <-broker.AppendReqCh; len(req.Content) != 0; req = <-broker.AppendReqCh {
+				content.Write(req.Content)
+			}
+			<-broker.ReadLoopErrCh
+			broker.AppendRespCh <- buildAppendResponseFixture(broker)
+		}
+	}()
+	return out
+}
+
 func gateServeAppends() (chan<- struct{}, func()) {
 	var ch = make(chan struct{})
 	var realServeAppends = serveAppends
@@ -9,6 +9,7 @@ import (
 	"github.com/pkg/errors"
 	"github.com/prometheus/client_golang/prometheus"
 	pb "go.gazette.dev/core/broker/protocol"
+	"go.gazette.dev/core/labels"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
@@ -26,14 +27,22 @@ import (
 // the append may or may commit.
 //
 // The application can cleanly roll-back a started Appender by Aborting it.
+//
+// By default, Close returns once the append is committed by all replicas of
+// the journal. Durability may be set to further await the persistence of
+// committed content to the journal's fragment store. If the append commits
+// but its Durability cannot be awaited, Close returns an error and Response
+// holds the broker's OK response of the committed append.
 type Appender struct {
-	Request  pb.AppendRequest  // AppendRequest of the Append.
-	Response pb.AppendResponse // AppendResponse sent by broker.
+	Request    pb.AppendRequest  // AppendRequest of the Append.
+	Response   pb.AppendResponse // AppendResponse sent by broker.
+	Durability Durability        // Durability awaited by Close.
 
-	ctx     context.Context
-	client  pb.RoutedJournalClient  // Client against which Read is dispatched.
-	counter prometheus.Counter      // Counter of appended bytes.
-	stream  pb.Journal_AppendClient // Server stream.
+	ctx        context.Context
+	client     pb.RoutedJournalClient  // Client against which Read is dispatched.
+	counter    prometheus.Counter      // Counter of appended bytes.
+	stream     pb.Journal_AppendClient // Server stream.
+	dispatchTo pb.ProcessSpec_ID       // If non-zero, Route member to which the RPC is dispatched.
 }
 
 // NewAppender returns an initialized Appender of the given AppendRequest.
@@ -47,6 +56,16 @@ func NewAppender(ctx context.Context, client pb.RoutedJournalClient, req pb.Appe
 	return a
 }
 
+// Result returns the AppendResult of the Appender, and may be called
+// after Close returns without error.
+func (a *Appender) Result() AppendResult {
+	var end int64
+	if a.Response.Commit != nil {
+		end = a.Response.Commit.ContentLength()
+	}
+	return newAppendResult(a.Request.Journal, a.Response, 0, end)
+}
+
 // Reset the Appender to its post-construction state, allowing it to be re-used
 // or re-tried. Reset without a prior Close or Abort will leak resources.
 func (a *Appender) Reset() { a.Response, a.stream = pb.AppendResponse{}, nil }
@@ -114,6 +133,10 @@ func (a *Appender) Close() (err error) {
 		_ = a.stream.RecvMsg(new(pb.AppendResponse))
 	}
 
+	if err == nil && a.Durability != DurabilityReplicated {
+		err = AwaitPersisted(a.ctx, a.client, *a.Response.Commit, a.Durability == DurabilityFlushed)
+	}
+
 	if err != nil {
 		err = mapGRPCCtxErr(a.ctx, err)
 	}
@@ -136,8 +159,14 @@ func (a *Appender) lazyInit() (err error) {
 			return pb.ExtendContext(err, "Request")
 		}
 
-		a.stream, err = a.client.Append(
-			pb.WithDispatchItemRoute(a.ctx, a.client, a.Request.Journal.String(), true))
+		var ctx context.Context
+		if a.dispatchTo != (pb.ProcessSpec_ID{}) {
+			ctx = pb.WithDispatchRoute(a.ctx,
+				a.client.Route(a.ctx, a.Request.Journal.String()), a.dispatchTo)
+		} else {
+			ctx = pb.WithDispatchItemRoute(a.ctx, a.client, a.Request.Journal.String(), true)
+		}
+		a.stream, err = a.client.Append(ctx)
 
 		if err == nil {
 			// Send request preamble metadata prior to append content chunks.
@@ -178,6 +207,10 @@ func Append(ctx context.Context, rjc pb.RoutedJournalClient, req pb.AppendReques
 
 		if err == nil {
 			return a.Response, nil
+		} else if a.Response.Status == pb.Status_OK && a.Response.Commit != nil {
+			// The append committed, but its Durability was not met. A retry
+			// would append a duplicate of its content, so don't.
+			return a.Response, err
 		} else if s, ok := status.FromError(err); ok && s.Code() == codes.Unavailable {
 			// Fallthrough to retry
 		} else if err == ErrNotJournalPrimaryBroker || err == ErrInsufficientJournalBrokers {
@@ -194,3 +227,44 @@ func Append(ctx context.Context, rjc pb.RoutedJournalClient, req pb.AppendReques
 	}
 	panic("not reached")
 }
+
+// AppendIfHead appends zero or more ReaderAts to a journal as a single Append
+// transaction, as does Append, but only if the journal's write head is |head|
+// (compare-and-append). If the journal's write head differs, as when another
+// writer appended first, AppendIfHead fails with ErrWrongAppendOffset. This
+// allows a single logical writer of a journal to be enforced through the
+// journal's offsets alone.
+//
+// |token| must uniquely identify this append (eg, a UUID), and the append
+// records it in the labels.AppendToken register of the journal. Should an
+// attempt commit but its outcome be ambiguous (eg, the connection is lost
+// before the broker responds), a retry of the append fails its offset check
+// against the head written by the prior attempt. AppendIfHead detects this
+// case by the |token| in the journal's registers, and returns the broker's
+// WRONG_APPEND_OFFSET response (which has no Commit) without error. The
+// same holds if AppendIfHead is itself retried with the same |head| and
+// |token|. Detection requires that no other conditional append has since
+// committed, as it would replace the register: in that case, the offset
+// check fails as usual.
+//
+// Registers are updated only by appends of at least one byte, so content
+// must not be empty.
+func AppendIfHead(ctx context.Context, rjc pb.RoutedJournalClient, req pb.AppendRequest,
+	head pb.Offset, token string, content ...io.ReaderAt) (pb.AppendResponse, error) {
+
+	if token == "" {
+		return pb.AppendResponse{}, pb.NewValidationError("expected token")
+	}
+	var union pb.LabelSet
+	union.Assign(req.UnionRegisters)
+	union.SetValue(labels.AppendToken, token)
+
+	req.Offset, req.CheckOffset, req.UnionRegisters = head, true, &union
+
+	var resp, err = Append(ctx, rjc, req, content...)
+	if err == ErrWrongAppendOffset && resp.Registers != nil &&
+		resp.Registers.ValueOf(labels.AppendToken) == token {
+		err = nil // Committed by a prior attempt.
+	}
+	return resp, err
+}
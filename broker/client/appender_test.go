@@ -54,6 +54,13 @@ func (s *AppenderSuite) TestCommitSuccess(c *gc.C) {
 	c.Check(a.Close(), gc.IsNil)
 	c.Check(a.Response.Commit.Journal, gc.Equals, pb.Journal("a/journal"))
 
+	var result = a.Result()
+	c.Check(result.Journal, gc.Equals, pb.Journal("a/journal"))
+	c.Check(result.Begin, gc.Equals, pb.Offset(100))
+	c.Check(result.End, gc.Equals, pb.Offset(106))
+	c.Check(result.Fragment, gc.DeepEquals, *a.Response.Commit)
+	c.Check(result.Route, gc.DeepEquals, buildHeaderFixture(broker).Route)
+
 	// Expect Appender advised of the updated Route.
 	c.Check(rjc.Route(context.Background(), "a/journal"), gc.DeepEquals, pb.Route{
 		Members:   []pb.ProcessSpec_ID{{Zone: "a", Suffix: "broker"}},
@@ -284,6 +291,108 @@ func (s *AppenderSuite) TestAppendCases(c *gc.C) {
 	c.Check(err, gc.ErrorMatches, "readerAt error")
 }
 
+func (s *AppenderSuite) TestDurability(c *gc.C) {
+	var broker = teststub.NewBroker(c)
+	defer broker.Cleanup()
+
+	defer func(d time.Duration) { awaitPersistedInterval = d }(awaitPersistedInterval)
+	awaitPersistedInterval = time.Millisecond
+
+	var ctx = pb.WithDispatchDefault(context.Background())
+	var rjc = pb.NewRoutedJournalClient(broker.Client(), pb.NoopDispatchRouter{})
+	var hdr = *buildHeaderFixture(broker)
+
+	var journals = buildListResponseFixture("a/journal")
+	journals[0].Spec.Fragment.Stores = []pb.FragmentStore{"s3://bucket/"}
+	broker.ListFunc = func(context.Context, *pb.ListRequest) (*pb.ListResponse, error) {
+		return &pb.ListResponse{Status: pb.Status_OK, Header: hdr, Journals: journals}, nil
+	}
+	var persisted bool
+	broker.ListFragmentsFunc = func(_ context.Context, req *pb.FragmentsRequest) (*pb.FragmentsResponse, error) {
+		c.Check(req.Journal, gc.Equals, pb.Journal("a/journal"))
+
+		var resp = &pb.FragmentsResponse{Status: pb.Status_OK, Header: hdr}
+		var spec = pb.Fragment{
+			Journal:          "a/journal",
+			Begin:            0,
+			End:              100,
+			CompressionCodec: pb.CompressionCodec_NONE,
+			BackingStore:     "s3://bucket/",
+		}
+		resp.Fragments = append(resp.Fragments, pb.FragmentsResponse__Fragment{Spec: spec})
+
+		// Fragment [100, 106) is first local, and then persisted.
+		spec.Begin, spec.End = 100, 106
+		if spec.BackingStore = ""; persisted {
+			spec.BackingStore = "s3://bucket/"
+		}
+		persisted = true
+		resp.Fragments = append(resp.Fragments, pb.FragmentsResponse__Fragment{Spec: spec})
+
+		return resp, nil
+	}
+	var expectAppend = func(req pb.AppendRequest, content string, begin, end int64) {
+		c.Check(<-broker.AppendReqCh, gc.DeepEquals, req)
+		if content != "" {
+			c.Check(<-broker.AppendReqCh, gc.DeepEquals, pb.AppendRequest{Content: []byte(content)})
+		}
+		c.Check(<-broker.AppendReqCh, gc.DeepEquals, pb.AppendRequest{})
+		c.Check(<-broker.ReadLoopErrCh, gc.Equals, io.EOF)
+
+		broker.AppendRespCh <- pb.AppendResponse{
+			Status: pb.Status_OK,
+			Header: hdr,
+			Commit: &pb.Fragment{
+				Journal:          "a/journal",
+				Begin:            begin,
+				End:              end,
+				CompressionCodec: pb.CompressionCodec_NONE,
+			},
+			Registers: new(pb.LabelSet),
+		}
+	}
+
+	// Case: DurabilityFlushed flushes the journal, and then awaits persistence.
+	go func() {
+		expectAppend(pb.AppendRequest{Journal: "a/journal"}, "foobar", 100, 106)
+		expectAppend(pb.AppendRequest{Journal: "a/journal", Flush: true}, "", 106, 106)
+	}()
+
+	var a = NewAppender(ctx, rjc, pb.AppendRequest{Journal: "a/journal"})
+	a.Durability = DurabilityFlushed
+
+	var _, err = a.Write([]byte("foobar"))
+	c.Check(err, gc.IsNil)
+	c.Check(a.Close(), gc.IsNil)
+	c.Check(persisted, gc.Equals, true)
+
+	// Case: DurabilityPersisted polls until content is persisted.
+	persisted = false
+	go expectAppend(pb.AppendRequest{Journal: "a/journal"}, "foobar", 100, 106)
+
+	a = NewAppender(ctx, rjc, pb.AppendRequest{Journal: "a/journal"})
+	a.Durability = DurabilityPersisted
+
+	_, err = a.Write([]byte("foobar"))
+	c.Check(err, gc.IsNil)
+	c.Check(a.Close(), gc.IsNil)
+	c.Check(persisted, gc.Equals, true)
+
+	// Case: DurabilityPersisted of a journal without stores fails,
+	// but retains the response of the committed append.
+	journals[0].Spec.Fragment.Stores = nil
+	go expectAppend(pb.AppendRequest{Journal: "a/journal"}, "foobar", 100, 106)
+
+	a = NewAppender(ctx, rjc, pb.AppendRequest{Journal: "a/journal"})
+	a.Durability = DurabilityPersisted
+
+	_, err = a.Write([]byte("foobar"))
+	c.Check(err, gc.IsNil)
+	c.Check(a.Close(), gc.ErrorMatches, `journal has no fragment stores \(a/journal\)`)
+	c.Check(a.Response.Status, gc.Equals, pb.Status_OK)
+	c.Check(a.Response.Commit.End, gc.Equals, int64(106))
+}
+
 func (s *AppenderSuite) TestContextErrorCases(c *gc.C) {
 	var broker = teststub.NewBroker(c)
 	defer broker.Cleanup()
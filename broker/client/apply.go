@@ -0,0 +1,163 @@
+package client
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	pb "go.gazette.dev/core/broker/protocol"
+)
+
+// JournalPlanAction is the effect of an ApplyRequest_Change upon its journal.
+type JournalPlanAction string
+
+const (
+	// PlanCreate creates a journal which doesn't exist.
+	PlanCreate JournalPlanAction = "create"
+	// PlanUpdate updates the JournalSpec of an existing journal.
+	PlanUpdate JournalPlanAction = "update"
+	// PlanDelete deletes an existing journal.
+	PlanDelete JournalPlanAction = "delete"
+	// PlanUnchanged leaves the journal as it is: the change upserts its
+	// current JournalSpec, or deletes a journal which doesn't exist.
+	PlanUnchanged JournalPlanAction = "unchanged"
+)
+
+// JournalPlan is the planned effect of an ApplyRequest_Change.
+type JournalPlan struct {
+	// Journal which is changed.
+	Journal pb.Journal
+	// Action of the Change upon the Journal.
+	Action JournalPlanAction
+	// Current JournalSpec of the Journal, or nil if it doesn't exist.
+	Current *pb.JournalSpec
+	// ModRevision of the Current JournalSpec, or zero if it doesn't exist.
+	ModRevision int64
+	// Change of the ApplyRequest.
+	Change pb.ApplyRequest_Change
+}
+
+// PlanJournals validates the ApplyRequest and returns a JournalPlan of each
+// of its Changes, in order, without applying them. Current JournalSpecs are
+// fetched by List RPCs having selectors of up to |size| journals each (see
+// GetJournals). It's an error for the ApplyRequest to change a journal more
+// than once, or for a Change to expect a ModRevision other than the current
+// one of its journal, as the ApplyRequest would then fail to apply.
+func PlanJournals(ctx context.Context, jc pb.JournalClient, req *pb.ApplyRequest, size int) ([]JournalPlan, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	var names = make([]pb.Journal, len(req.Changes))
+	var seen = make(map[pb.Journal]struct{}, len(req.Changes))
+
+	for i, change := range req.Changes {
+		if change.Upsert != nil {
+			names[i] = change.Upsert.Name
+		} else {
+			names[i] = change.Delete
+		}
+		if _, ok := seen[names[i]]; ok {
+			return nil, errors.Errorf("journal %s is changed more than once", names[i])
+		}
+		seen[names[i]] = struct{}{}
+	}
+	var current = GetJournals(ctx, jc, names, size)
+	var out = make([]JournalPlan, len(req.Changes))
+
+	for i, change := range req.Changes {
+		var result = current[names[i]]
+		var plan = JournalPlan{
+			Journal:     names[i],
+			Current:     result.Spec,
+			ModRevision: result.ModRevision,
+			Change:      change,
+		}
+		if result.Err != nil && result.Err != ErrJournalNotFound {
+			return nil, errors.WithMessagef(result.Err, "fetching journal %s", names[i])
+		} else if change.ExpectModRevision != -1 && change.ExpectModRevision != plan.ModRevision {
+			return nil, errors.Errorf("journal %s has ModRevision %d (expected %d)",
+				names[i], plan.ModRevision, change.ExpectModRevision)
+		}
+
+		switch {
+		case change.Upsert != nil && plan.Current == nil:
+			plan.Action = PlanCreate
+		case change.Upsert != nil && change.Upsert.Equal(plan.Current):
+			plan.Action = PlanUnchanged
+		case change.Upsert != nil:
+			plan.Action = PlanUpdate
+		case plan.Current == nil:
+			plan.Action = PlanUnchanged
+		default:
+			plan.Action = PlanDelete
+		}
+		out[i] = plan
+	}
+	return out, nil
+}
+
+// ApplyJournalsAtomically applies journal changes detailed in the ApplyRequest
+// such that either all, or none of them are applied. Changes are first planned
+// with PlanJournals, which validates them. If there are no more than |size|
+// Changes (or |size| is zero) they're applied as a single Etcd transaction.
+//
+// Otherwise, Changes are applied in batches of |size| Changes, as with
+// ApplyJournalsInBatches. If a batch fails to apply, the Changes of prior
+// batches are rolled back by applying compensating batches, which restore
+// the planned Current JournalSpecs of their journals, in reverse order.
+// Compensating Changes expect the ModRevisions written by ApplyJournalsAtomically,
+// and a journal which is concurrently modified by another writer fails the
+// rollback rather than losing the other writer's change. The error of the
+// failed batch is returned, and if rollback also fails, its error is returned
+// as well and the journals are only partially changed.
+// ApplyResponse statuses other than OK are mapped to an error.
+func ApplyJournalsAtomically(ctx context.Context, jc pb.JournalClient, req *pb.ApplyRequest, size int) (*pb.ApplyResponse, error) {
+	var plans, err = PlanJournals(ctx, jc, req, size)
+	if err != nil {
+		return nil, err
+	} else if size == 0 || len(req.Changes) <= size {
+		return ApplyJournals(ctx, jc, req)
+	}
+
+	var resp *pb.ApplyResponse
+	var revisions []int64 // Etcd revision of each applied batch.
+
+	for offset, end := 0, 0; offset != len(plans); offset = end {
+		if end = offset + size; end > len(plans) {
+			end = len(plans)
+		}
+		resp, err = ApplyJournals(ctx, jc, &pb.ApplyRequest{Changes: req.Changes[offset:end]})
+
+		if err != nil {
+			if rbErr := rollbackJournals(ctx, jc, plans[:offset], revisions, size); rbErr != nil {
+				return resp, errors.WithMessagef(err, "rolling back applied changes failed (%v)", rbErr)
+			}
+			return resp, err
+		}
+		revisions = append(revisions, resp.Header.Etcd.Revision)
+	}
+	return resp, nil
+}
+
+// rollbackJournals restores the Current JournalSpecs of applied JournalPlans,
+// where |plans| were applied in batches of |size| at Etcd |revisions|.
+func rollbackJournals(ctx context.Context, jc pb.JournalClient, plans []JournalPlan, revisions []int64, size int) error {
+	var changes []pb.ApplyRequest_Change
+
+	for i := len(plans) - 1; i >= 0; i-- {
+		var plan, revision = plans[i], revisions[i/size]
+
+		switch {
+		case plan.Change.Upsert != nil && plan.Current == nil:
+			changes = append(changes, pb.ApplyRequest_Change{Delete: plan.Journal, ExpectModRevision: revision})
+		case plan.Change.Upsert != nil:
+			changes = append(changes, pb.ApplyRequest_Change{Upsert: plan.Current, ExpectModRevision: revision})
+		case plan.Current != nil:
+			changes = append(changes, pb.ApplyRequest_Change{Upsert: plan.Current, ExpectModRevision: 0})
+		}
+	}
+	if len(changes) == 0 {
+		return nil
+	}
+	var _, err = ApplyJournalsInBatches(ctx, jc, &pb.ApplyRequest{Changes: changes}, size)
+	return err
+}
@@ -70,6 +70,14 @@ var (
 		Name: "gazette_append_bytes_total",
 		Help: "Total number of journal bytes appended.",
 	}, []string{"journal"})
+	appendHedgesTriggered = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "gazette_append_hedges_triggered_total",
+		Help: "Total number of Append RPCs of an AppendService which were hedged to an alternate broker.",
+	}, []string{"journal"})
+	appendHedgesWon = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "gazette_append_hedges_won_total",
+		Help: "Total number of hedged Append RPCs of an AppendService which committed through the alternate broker.",
+	}, []string{"journal"})
 	readBytes = promauto.NewCounterVec(prometheus.CounterOpts{
 		Name: "gazette_read_bytes_total",
 		Help: "Total number of journal bytes read.",
@@ -94,4 +102,44 @@ var (
 		Name: "gazette_discard_fragment_bytes_total",
 		Help: "Total number of uncompressed journal fragment bytes discarded while seeking to desired offset.",
 	}, []string{"journal", "codec"})
+	skipFragmentBytes = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "gazette_skip_fragment_bytes_total",
+		Help: "Total number of uncompressed journal fragment bytes skipped without decompression while seeking to desired offset.",
+	}, []string{"journal", "codec"})
+	fragmentDecompressDiscards = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "gazette_fragment_decompress_discards_total",
+		Help: "Total number of opened journal fragments which were decompressed and discarded while seeking to desired offset, as their codec doesn't support skipping.",
+	}, []string{"journal", "codec"})
+	fragmentStoreFailures = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "gazette_fragment_store_fetch_failures_total",
+		Help: "Total number of failed fetches of journal fragments from their stores, by reason.",
+	}, []string{"journal", "reason"})
+	fragmentStoreRetries = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "gazette_fragment_store_fetch_retries_total",
+		Help: "Total number of retried fetches of journal fragments from unavailable stores.",
+	}, []string{"journal"})
+	fragmentCacheHits = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "gazette_fragment_cache_hits_total",
+		Help: "Total number of journal fragments opened from a local FragmentCache.",
+	}, []string{"journal"})
+	fragmentCacheMisses = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "gazette_fragment_cache_misses_total",
+		Help: "Total number of journal fragments which were fetched into a local FragmentCache upon being opened.",
+	}, []string{"journal"})
+	fragmentCacheEvictions = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "gazette_fragment_cache_evictions_total",
+		Help: "Total number of journal fragments evicted from local FragmentCaches.",
+	})
+	fragmentCacheBytes = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "gazette_fragment_cache_bytes",
+		Help: "Total bytes of journal fragments held by local FragmentCaches.",
+	})
+	persistLagBytes = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "gazette_journal_persist_lag_bytes",
+		Help: "Bytes by which the persisted fragments of a journal trail its write head, as last observed by GetPersistLag.",
+	}, []string{"journal"})
+	persistBacklogFragments = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "gazette_journal_persist_backlog_fragments",
+		Help: "Number of rolled fragments of a journal awaiting persistence to a fragment store, as last observed by GetPersistLag.",
+	}, []string{"journal"})
 )
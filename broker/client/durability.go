@@ -0,0 +1,101 @@
+package client
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	pb "go.gazette.dev/core/broker/protocol"
+)
+
+// Durability is the degree to which an Appender awaits the durability of
+// committed content, before Appender.Close reports a successful append.
+// Stronger durability trades append latency for guarantees of persistence.
+type Durability int
+
+const (
+	// DurabilityReplicated awaits the commit of appended content by every
+	// replica of the journal. This is the default, and is also the weakest
+	// available durability: brokers acknowledge an append only after all
+	// replicas of the journal's current route have acknowledged it, so there
+	// is no weaker mode which awaits only the journal's primary broker.
+	DurabilityReplicated Durability = iota
+	// DurabilityPersisted further awaits the persistence of appended content
+	// to a fragment store of the journal. Content is persisted once its
+	// Fragment is rolled, which happens only as the Fragment reaches its target
+	// length or flush interval, and persistence is then observed only as the
+	// broker next refreshes its index of persisted Fragments.
+	//
+	// An append to an idle journal may therefore await the full flush interval
+	// and refresh interval of the journal's JournalSpec_Fragment, and awaits
+	// indefinitely if the journal has no flush interval. Use DurabilityFlushed
+	// to bound the latency of appends to infrequently written journals.
+	DurabilityPersisted
+	// DurabilityFlushed is DurabilityPersisted, but forces a flush of the
+	// journal's current Fragment upon the commit of appended content. Latency
+	// is then bounded by the upload of the Fragment to its store and the
+	// refresh interval of the journal. Forced flushes produce smaller
+	// Fragments, and should be used judiciously.
+	DurabilityFlushed
+)
+
+// AwaitPersisted blocks until the committed Fragment of an append has been
+// persisted to a fragment store of its journal, as observed through the
+// Fragments listed by the journal's brokers. If |flush| is true, the journal's
+// current Fragment is first flushed by an empty Append of AppendRequest.Flush,
+// which prompts the immediate persistence of the committed content. See
+// DurabilityPersisted and DurabilityFlushed for discussion of the expected
+// latency of each.
+//
+// AwaitPersisted returns an error if the journal has no fragment stores, as
+// its content will never be persisted.
+func AwaitPersisted(ctx context.Context, rjc pb.RoutedJournalClient, commit pb.Fragment, flush bool) error {
+	if commit.ContentLength() == 0 {
+		return nil // Nothing to persist.
+	}
+
+	var spec, err = GetJournal(ctx, rjc, commit.Journal)
+	if err != nil {
+		return err
+	} else if len(spec.Fragment.Stores) == 0 {
+		return errors.Errorf("journal has no fragment stores (%s)", commit.Journal)
+	}
+
+	if flush {
+		if _, err = Append(ctx, rjc, pb.AppendRequest{Journal: commit.Journal, Flush: true}); err != nil {
+			return errors.WithMessage(err, "flushing journal")
+		}
+	}
+
+	for {
+		var resp, err = ListAllFragments(ctx, rjc, pb.FragmentsRequest{Journal: commit.Journal})
+		if err != nil {
+			return errors.WithMessage(err, "listing fragments")
+		} else if persistedThrough(resp.Fragments, commit.Begin) >= commit.End {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(awaitPersistedInterval):
+		}
+	}
+}
+
+// persistedThrough returns the offset through which journal content beginning
+// at |offset| is continuously covered by persisted, ordered |fragments|.
+func persistedThrough(fragments []pb.FragmentsResponse__Fragment, offset pb.Offset) pb.Offset {
+	for _, f := range fragments {
+		if f.Spec.BackingStore == "" {
+			continue // Local Fragment which isn't yet persisted.
+		} else if f.Spec.Begin <= offset && f.Spec.End > offset {
+			offset = f.Spec.End
+		}
+	}
+	return offset
+}
+
+// awaitPersistedInterval is the interval with which AwaitPersisted polls
+// for the persistence of committed content.
+var awaitPersistedInterval = 5 * time.Second
@@ -0,0 +1,316 @@
+package client
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha1"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	pb "go.gazette.dev/core/broker/protocol"
+)
+
+// FragmentCache is a disk-backed cache of persisted Fragments, which is
+// bounded to a maximum size and evicts its least-recently used Fragments.
+// It's intended for read-heavy workloads which repeatedly read the same
+// Fragments, such as analytical jobs which re-scan a journal, and which would
+// otherwise re-fetch them from their stores with each read. Set Reader.Cache
+// (or RetryReader.Reader.Cache) to read Fragments through the cache, and use
+// Prewarm to fetch a journal's Fragments ahead of reading them.
+//
+// Persisted Fragments are immutable, and are identified by their content
+// path, which includes their journal, offset range, and content SHA1. A
+// cached Fragment is therefore never stale. Fragments are downloaded to a
+// temporary file of the cache directory, and are renamed into place only
+// once their download completes (and, if uncompressed, their SHA1 is
+// verified), so that a partial or interrupted download is never served.
+//
+// Fragments which cannot be cached are instead read directly from their
+// stores. These are Fragments having GZIP_OFFLOAD_DECOMPRESSION, whose
+// content encoding depends on the store, and Fragments larger than the
+// cache itself.
+type FragmentCache struct {
+	dir      string
+	maxBytes int64
+
+	mu      sync.Mutex
+	lru     *list.List               // LRU order of *cachedFragment, most-recent first.
+	entries map[string]*list.Element // Entries of |lru|, keyed on content path.
+	size    int64                    // Total bytes of cached Fragments.
+}
+
+// cachedFragment is a Fragment file of the FragmentCache.
+type cachedFragment struct {
+	path string // Fragment content path, relative to the cache directory.
+	size int64  // Size of the Fragment file.
+}
+
+// NewFragmentCache returns a FragmentCache of the directory, which is
+// bounded to |maxBytes|. The directory is created if it doesn't exist.
+// Fragments already in the directory, such as those cached by a prior
+// process, are retained as cache entries in order of their modification
+// times. Incomplete downloads of the prior process are removed.
+func NewFragmentCache(dir string, maxBytes int64) (*FragmentCache, error) {
+	if maxBytes <= 0 {
+		return nil, fmt.Errorf("invalid maxBytes (%d; expected > 0)", maxBytes)
+	} else if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+	var c = &FragmentCache{
+		dir:      dir,
+		maxBytes: maxBytes,
+		lru:      list.New(),
+		entries:  make(map[string]*list.Element),
+	}
+
+	var existing []os.FileInfo
+	var paths []string
+
+	if err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		} else if strings.HasSuffix(path, partialSuffix) {
+			return os.Remove(path)
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		existing, paths = append(existing, info), append(paths, filepath.ToSlash(rel))
+		return nil
+	}); err != nil {
+		return nil, fmt.Errorf("walking cache directory: %w", err)
+	}
+	// Add in order of modification time, so that the most recently
+	// modified Fragment is the most-recently used entry.
+	var order = make([]int, len(existing))
+	for i := range order {
+		order[i] = i
+	}
+	sort.Slice(order, func(i, j int) bool {
+		return existing[order[i]].ModTime().Before(existing[order[j]].ModTime())
+	})
+	for _, i := range order {
+		c.add(&cachedFragment{path: paths[i], size: existing[i].Size()})
+	}
+	return c, nil
+}
+
+// Open the Fragment through the cache, and return a *FragmentReader which
+// has been pre-seeked to the given offset. If the Fragment isn't cached,
+// it's first fetched from |url| into the cache. Errors of fetching the
+// Fragment are as those of OpenFragmentURL.
+func (c *FragmentCache) Open(ctx context.Context, fragment pb.Fragment, offset int64, url string) (*FragmentReader, error) {
+	if !c.cacheable(fragment) {
+		return OpenFragmentURL(ctx, fragment, offset, url)
+	}
+	var path = fragment.ContentPath()
+
+	if file, err := c.openCached(path); err != nil {
+		return nil, err
+	} else if file != nil {
+		fragmentCacheHits.WithLabelValues(fragment.Journal.String()).Inc()
+		return NewFragmentReader(file, fragment, offset)
+	}
+	fragmentCacheMisses.WithLabelValues(fragment.Journal.String()).Inc()
+
+	if err := c.fetch(ctx, fragment, url); err != nil {
+		return nil, err
+	}
+	if file, err := c.openCached(path); err != nil {
+		return nil, err
+	} else if file != nil {
+		return NewFragmentReader(file, fragment, offset)
+	}
+	// The Fragment was evicted by concurrent fetches before we could open it.
+	return OpenFragmentURL(ctx, fragment, offset, url)
+}
+
+// Prewarm lists persisted Fragments of the FragmentsRequest and fetches
+// them into the cache, in order of their offsets. If the request has no
+// SignatureTTL, a TTL of one hour is used. Prewarm stops upon fetching as
+// many Fragments as the cache can hold, as further Fragments would evict
+// those already fetched. It returns the number of Fragments which are now
+// cached.
+func (c *FragmentCache) Prewarm(ctx context.Context, rjc pb.RoutedJournalClient, req pb.FragmentsRequest) (int, error) {
+	if req.SignatureTTL == nil {
+		var ttl = time.Hour
+		req.SignatureTTL = &ttl
+	}
+	var resp, err = ListAllFragments(ctx, rjc, req)
+	if err != nil {
+		return 0, err
+	}
+
+	var warmed int
+	var total int64
+
+	for _, f := range resp.Fragments {
+		if f.SignedUrl == "" || !c.cacheable(f.Spec) {
+			continue
+		} else if total += f.Spec.ContentLength(); total > c.maxBytes {
+			break
+		}
+
+		var path = f.Spec.ContentPath()
+		c.mu.Lock()
+		var elem, ok = c.entries[path]
+		if ok {
+			c.lru.MoveToFront(elem)
+		}
+		c.mu.Unlock()
+
+		if !ok {
+			if err = c.fetch(ctx, f.Spec, f.SignedUrl); err != nil {
+				return warmed, err
+			}
+		}
+		warmed++
+	}
+	return warmed, nil
+}
+
+// cacheable returns whether the Fragment may be cached.
+func (c *FragmentCache) cacheable(fragment pb.Fragment) bool {
+	return fragment.CompressionCodec != pb.CompressionCodec_GZIP_OFFLOAD_DECOMPRESSION &&
+		fragment.ContentLength() <= c.maxBytes
+}
+
+// openCached opens the cached Fragment file of the content path, marking
+// it as most-recently used. It returns a nil *os.File if it isn't cached.
+func (c *FragmentCache) openCached(path string) (*os.File, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var elem, ok = c.entries[path]
+	if !ok {
+		return nil, nil
+	}
+	var file, err = os.Open(c.filePath(path))
+	if os.IsNotExist(err) {
+		// Removed out from under us. Treat as a miss.
+		c.remove(elem)
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	c.lru.MoveToFront(elem)
+	return file, nil
+}
+
+// fetch downloads the Fragment from |url| into the cache.
+func (c *FragmentCache) fetch(ctx context.Context, fragment pb.Fragment, url string) error {
+	var req, err = http.NewRequest("GET", url, nil)
+	if err != nil {
+		return err
+	}
+	if fragment.CompressionCodec == pb.CompressionCodec_GZIP {
+		// Fetch the gzip'd bytes as-is, without transparent decompression.
+		req.Header.Set("Accept-Encoding", "gzip")
+	}
+
+	resp, err := httpClient.Do(req.WithContext(ctx))
+	if err != nil {
+		if ctx.Err() != nil {
+			return err
+		}
+		fragmentStoreFailures.WithLabelValues(fragment.Journal.String(), "unavailable").Inc()
+		return fragmentStoreError{err: err, kind: ErrFragmentStoreUnavailable}
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		var body, _ = ioutil.ReadAll(io.LimitReader(resp.Body, 4096))
+		var reason, kind = fragmentStoreErrorKind(resp.StatusCode, body)
+		fragmentStoreFailures.WithLabelValues(fragment.Journal.String(), reason).Inc()
+
+		return fragmentStoreError{
+			err:  fmt.Errorf("!OK fetching (%s, %q)", resp.Status, url),
+			kind: kind,
+		}
+	}
+
+	// Download into a temporary file, which is renamed into place only after
+	// the download has completed and been verified.
+	var path = fragment.ContentPath()
+	var target = c.filePath(path)
+
+	if err = os.MkdirAll(filepath.Dir(target), 0700); err != nil {
+		return err
+	}
+	tmp, err := ioutil.TempFile(filepath.Dir(target), filepath.Base(target)+".*"+partialSuffix)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) // Fails with NotExist if renamed.
+
+	var summer = sha1.New()
+	var w io.Writer = tmp
+
+	if fragment.CompressionCodec == pb.CompressionCodec_NONE {
+		w = io.MultiWriter(tmp, summer)
+	}
+	size, err := io.Copy(w, resp.Body)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("downloading fragment %s: %w", path, err)
+	} else if fragment.CompressionCodec != pb.CompressionCodec_NONE {
+		// We can't verify compressed content without decompressing it.
+	} else if size != fragment.ContentLength() {
+		return fmt.Errorf("downloaded fragment %s has length %d (expected %d)", path, size, fragment.ContentLength())
+	} else if sum := pb.SHA1SumFromDigest(summer.Sum(nil)); sum != fragment.Sum {
+		return fmt.Errorf("downloaded fragment %s has SHA1 %x (expected %x)", path, sum.ToDigest(), fragment.Sum.ToDigest())
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, ok := c.entries[path]; ok {
+		return nil // Raced with a concurrent fetch of the same Fragment.
+	} else if err = os.Rename(tmp.Name(), target); err != nil {
+		return err
+	}
+	c.add(&cachedFragment{path: path, size: size})
+	return nil
+}
+
+// add the cachedFragment as the most-recently used entry, evicting least-
+// recently used entries as required. |mu| must be held.
+func (c *FragmentCache) add(f *cachedFragment) {
+	c.entries[f.path] = c.lru.PushFront(f)
+	c.size += f.size
+	fragmentCacheBytes.Add(float64(f.size))
+
+	for c.size > c.maxBytes {
+		var elem = c.lru.Back()
+		_ = os.Remove(c.filePath(elem.Value.(*cachedFragment).path))
+		c.remove(elem)
+		fragmentCacheEvictions.Inc()
+	}
+}
+
+// remove the entry from the cache. |mu| must be held.
+func (c *FragmentCache) remove(elem *list.Element) {
+	var f = c.lru.Remove(elem).(*cachedFragment)
+	delete(c.entries, f.path)
+	c.size -= f.size
+	fragmentCacheBytes.Sub(float64(f.size))
+}
+
+// filePath returns the cache file of the content path.
+func (c *FragmentCache) filePath(path string) string {
+	return filepath.Join(c.dir, filepath.FromSlash(path))
+}
+
+// partialSuffix is the file suffix of an incomplete Fragment download.
+const partialSuffix = ".partial"
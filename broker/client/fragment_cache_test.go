@@ -0,0 +1,208 @@
+package client
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync/atomic"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	pb "go.gazette.dev/core/broker/protocol"
+	"go.gazette.dev/core/broker/teststub"
+	gc "gopkg.in/check.v1"
+)
+
+type FragmentCacheSuite struct{}
+
+func (s *FragmentCacheSuite) TestOpenThroughCache(c *gc.C) {
+	var fragA, _, storeDir, cleanup = buildFragmentFixture(c)
+	defer cleanup()
+
+	var fragB = writeUncompressedFixture(c, storeDir, 120, "0123456789")
+	var fragC = writeUncompressedFixture(c, storeDir, 130, "abcdefghij")
+	var name = fragC.ContentName()
+	fragC.Sum = pb.SHA1SumOf("not-abcdefghij") // Corrupt.
+	c.Assert(os.Rename(filepath.Join(storeDir, name), filepath.Join(storeDir, fragC.ContentName())), gc.IsNil)
+
+	var store, requests, truncate = newCacheStoreFixture(storeDir)
+	defer store.Close()
+
+	var cacheDir, err = ioutil.TempDir("", "FragmentCacheSuite")
+	c.Assert(err, gc.IsNil)
+	defer os.RemoveAll(cacheDir)
+
+	cache, err := NewFragmentCache(cacheDir, 1<<20)
+	c.Assert(err, gc.IsNil)
+
+	var ctx = context.Background()
+	var hits = fragmentCacheHits.WithLabelValues("a/journal")
+	var misses = fragmentCacheMisses.WithLabelValues("a/journal")
+	var hitsBefore, missesBefore = testutil.ToFloat64(hits), testutil.ToFloat64(misses)
+
+	var read = func(frag pb.Fragment, offset int64) (string, error) {
+		var fr, err = cache.Open(ctx, frag, offset, store.URL+"/"+frag.ContentName())
+		if err != nil {
+			return "", err
+		}
+		defer fr.Close()
+
+		var b []byte
+		b, err = ioutil.ReadAll(fr)
+		return string(b), err
+	}
+
+	// Case: a miss fetches the Fragment from its store, and a hit doesn't.
+	for range []int{0, 1} {
+		var content, err = read(fragA, 105)
+		c.Check(err, gc.IsNil)
+		c.Check(content, gc.Equals, "hello, world!!!")
+	}
+	c.Check(atomic.LoadInt32(requests), gc.Equals, int32(1))
+	c.Check(testutil.ToFloat64(hits)-hitsBefore, gc.Equals, 1.0)
+	c.Check(testutil.ToFloat64(misses)-missesBefore, gc.Equals, 1.0)
+
+	// Case: an interrupted download isn't cached, and is re-fetched.
+	atomic.StoreInt32(truncate, 1)
+	_, err = read(fragB, 125)
+	c.Check(err, gc.ErrorMatches, `downloading fragment .*: unexpected EOF`)
+
+	atomic.StoreInt32(truncate, 0)
+	content, err := read(fragB, 125)
+	c.Check(err, gc.IsNil)
+	c.Check(content, gc.Equals, "56789")
+	c.Check(atomic.LoadInt32(requests), gc.Equals, int32(3))
+
+	// Case: uncompressed content which fails verification isn't cached.
+	_, err = read(fragC, 130)
+	c.Check(err, gc.ErrorMatches, `downloaded fragment .* has SHA1 [0-9a-f]+ \(expected [0-9a-f]+\)`)
+
+	// Expect only completed downloads are present in the cache directory.
+	c.Check(listCacheDir(c, cacheDir), gc.DeepEquals, []string{
+		fragA.ContentPath(),
+		fragB.ContentPath(),
+	})
+
+	// Case: a new cache of the directory retains cached Fragments,
+	// and removes an incomplete download of a prior process.
+	c.Assert(ioutil.WriteFile(filepath.Join(cacheDir, "a/journal/partial.abc"+partialSuffix), nil, 0600), gc.IsNil)
+
+	cache, err = NewFragmentCache(cacheDir, 1<<20)
+	c.Assert(err, gc.IsNil)
+
+	content, err = read(fragB, 120)
+	c.Check(err, gc.IsNil)
+	c.Check(content, gc.Equals, "0123456789")
+	c.Check(atomic.LoadInt32(requests), gc.Equals, int32(4))
+	c.Check(listCacheDir(c, cacheDir), gc.HasLen, 2)
+}
+
+func (s *FragmentCacheSuite) TestEvictionAndPrewarm(c *gc.C) {
+	var fragA, _, storeDir, cleanup = buildFragmentFixture(c)
+	defer cleanup()
+
+	var fragB = writeUncompressedFixture(c, storeDir, 120, "0123456789")
+	var fragC = writeUncompressedFixture(c, storeDir, 130, "abcdefghij")
+
+	var store, requests, _ = newCacheStoreFixture(storeDir)
+	defer store.Close()
+
+	var cacheDir, err = ioutil.TempDir("", "FragmentCacheSuite")
+	c.Assert(err, gc.IsNil)
+	defer os.RemoveAll(cacheDir)
+
+	// Size the cache to hold just one of |fragB| or |fragC|.
+	cache, err := NewFragmentCache(cacheDir, 15)
+	c.Assert(err, gc.IsNil)
+
+	var broker = teststub.NewBroker(c)
+	defer broker.Cleanup()
+
+	broker.ListFragmentsFunc = func(_ context.Context, req *pb.FragmentsRequest) (*pb.FragmentsResponse, error) {
+		c.Check(req.SignatureTTL, gc.NotNil)
+
+		var resp = &pb.FragmentsResponse{Status: pb.Status_OK, Header: *buildHeaderFixture(broker)}
+		for _, f := range []pb.Fragment{fragA, fragB, fragC} {
+			resp.Fragments = append(resp.Fragments, pb.FragmentsResponse__Fragment{
+				Spec:      f,
+				SignedUrl: store.URL + "/" + f.ContentName(),
+			})
+		}
+		return resp, nil
+	}
+	var ctx = context.Background()
+	var rjc = pb.NewRoutedJournalClient(broker.Client(), pb.NoopDispatchRouter{})
+
+	// Expect |fragA| is larger than the cache and is skipped, and |fragC| isn't
+	// fetched as it would evict |fragB|.
+	warmed, err := cache.Prewarm(ctx, rjc, pb.FragmentsRequest{Journal: "a/journal"})
+	c.Check(err, gc.IsNil)
+	c.Check(warmed, gc.Equals, 1)
+	c.Check(listCacheDir(c, cacheDir), gc.DeepEquals, []string{fragB.ContentPath()})
+	c.Check(atomic.LoadInt32(requests), gc.Equals, int32(1))
+
+	// Opening |fragC| evicts |fragB|.
+	fr, err := cache.Open(ctx, fragC, 130, store.URL+"/"+fragC.ContentName())
+	c.Assert(err, gc.IsNil)
+	c.Check(fr.Close(), gc.IsNil)
+	c.Check(listCacheDir(c, cacheDir), gc.DeepEquals, []string{fragC.ContentPath()})
+
+	// |fragA| is read directly from its store, and isn't cached.
+	fr, err = cache.Open(ctx, fragA, 100, store.URL+"/"+fragA.ContentName())
+	c.Assert(err, gc.IsNil)
+	c.Check(fr.Close(), gc.IsNil)
+	c.Check(listCacheDir(c, cacheDir), gc.DeepEquals, []string{fragC.ContentPath()})
+	c.Check(atomic.LoadInt32(requests), gc.Equals, int32(3))
+}
+
+func writeUncompressedFixture(c *gc.C, dir string, begin int64, content string) pb.Fragment {
+	var frag = pb.Fragment{
+		Journal:          "a/journal",
+		Begin:            begin,
+		End:              begin + int64(len(content)),
+		Sum:              pb.SHA1SumOf(content),
+		CompressionCodec: pb.CompressionCodec_NONE,
+		BackingStore:     pb.FragmentStore("file:///"),
+	}
+	c.Assert(ioutil.WriteFile(filepath.Join(dir, frag.ContentName()), []byte(content), 0600), gc.IsNil)
+	return frag
+}
+
+// newCacheStoreFixture returns a store which serves files of |dir|, and which
+// counts |requests| and truncates responses while |truncate| is non-zero.
+func newCacheStoreFixture(dir string) (store *httptest.Server, requests, truncate *int32) {
+	requests, truncate = new(int32), new(int32)
+
+	store = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(requests, 1)
+
+		var b, err = ioutil.ReadFile(filepath.Join(dir, r.URL.Path))
+		if err != nil {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Length", strconv.Itoa(len(b)))
+		if atomic.LoadInt32(truncate) != 0 {
+			b = b[:len(b)/2]
+		}
+		_, _ = w.Write(b)
+	}))
+	return
+}
+
+func listCacheDir(c *gc.C, dir string) []string {
+	var out []string
+	c.Assert(filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err == nil && !info.IsDir() {
+			var rel, _ = filepath.Rel(dir, path)
+			out = append(out, filepath.ToSlash(rel))
+		}
+		return err
+	}), gc.IsNil)
+	return out
+}
+
+var _ = gc.Suite(&FragmentCacheSuite{})
@@ -0,0 +1,107 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	pb "go.gazette.dev/core/broker/protocol"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// GetHead returns the current write head of the journal, which is the offset
+// at which its next append will be written. It's intended for cheaply polling
+// journals (eg, to compute consumer lag), and issues a metadata-only Read RPC
+// which returns without reading journal content. An empty journal has a
+// write head of zero. If the journal doesn't exist, ErrJournalNotFound is
+// returned.
+func GetHead(ctx context.Context, client pb.RoutedJournalClient, journal pb.Journal) (pb.Offset, error) {
+	var r = NewReader(ctx, client, pb.ReadRequest{
+		Journal:      journal,
+		Offset:       -1,
+		Block:        false,
+		MetadataOnly: true,
+	})
+	// Reading from the write head (offset -1) without blocking is expected to
+	// fail with ErrOffsetNotYetAvailable, having a Response with the WriteHead.
+	var _, err = r.Read(nil)
+
+	switch {
+	case err == ErrOffsetNotYetAvailable:
+		return r.Response.WriteHead, nil
+	case r.Response.Status == pb.Status_JOURNAL_NOT_FOUND:
+		return 0, ErrJournalNotFound
+	case err == nil || err == ErrOffsetJump:
+		// The broker (unexpectedly) began to serve a read of journal content.
+		return 0, fmt.Errorf("unexpected %s response of metadata-only read at the write head (offset %d)",
+			r.Response.Status, r.Response.Offset)
+	default:
+		return 0, err
+	}
+}
+
+// AwaitOffset blocks until the write head of the journal is at least |offset|,
+// and returns the observed write head. Rather than polling, it issues a blocking
+// and metadata-only Read RPC of the offset just prior to |offset|, which the
+// broker answers as soon as that offset has been written.
+//
+// The Read RPC is restarted if it's closed by the broker or fails due to a
+// transport or routing error, as may happen if the journal is re-assigned.
+// Other errors, such as ErrJournalNotFound, are returned. If the Context is
+// cancelled or its deadline passes before the journal reaches |offset|, then
+// the Context error is returned. A nil error is returned only if the journal
+// reached |offset|.
+func AwaitOffset(ctx context.Context, client pb.RoutedJournalClient, journal pb.Journal, offset pb.Offset) (pb.Offset, error) {
+	if offset <= 0 {
+		return GetHead(ctx, client, journal) // Trivially reached.
+	}
+
+	for attempt := 0; ; attempt++ {
+		var head, err = awaitOffset(ctx, client, journal, offset)
+
+		if err == nil {
+			return head, nil
+		} else if s, ok := status.FromError(err); ok && s.Code() == codes.Unavailable {
+			// Fallthrough to retry.
+		} else if err == io.EOF || err == ErrNotJournalBroker || err == ErrInsufficientJournalBrokers {
+			// Fallthrough.
+		} else {
+			return 0, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return 0, ctx.Err()
+		case <-time.After(backoff(attempt)):
+		}
+	}
+}
+
+// awaitOffset performs a single blocking Read RPC of AwaitOffset.
+func awaitOffset(ctx context.Context, client pb.RoutedJournalClient, journal pb.Journal, offset pb.Offset) (pb.Offset, error) {
+	// Cancel the blocking Read RPC upon its first response.
+	var readCtx, cancel = context.WithCancel(ctx)
+	defer cancel()
+
+	var r = NewReader(readCtx, client, pb.ReadRequest{
+		Journal:      journal,
+		Offset:       offset - 1,
+		Block:        true,
+		MetadataOnly: true,
+	})
+	// Expect the first response has an OK status. The Read may have
+	// jumped to a greater offset if |offset| falls in a journal "hole",
+	// in which case the write head is still beyond |offset|.
+	var _, err = r.Read(nil)
+
+	switch {
+	case err == nil || err == ErrOffsetJump:
+		return r.Response.WriteHead, nil
+	case r.Response.Status == pb.Status_JOURNAL_NOT_FOUND:
+		return 0, ErrJournalNotFound
+	default:
+		return 0, err
+	}
+}
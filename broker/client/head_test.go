@@ -0,0 +1,189 @@
+package client
+
+import (
+	"context"
+	"time"
+
+	pb "go.gazette.dev/core/broker/protocol"
+	"go.gazette.dev/core/broker/teststub"
+	gc "gopkg.in/check.v1"
+)
+
+type HeadSuite struct{}
+
+func (s *HeadSuite) TestGetHead(c *gc.C) {
+	var broker = teststub.NewBroker(c)
+	defer broker.Cleanup()
+
+	var ctx = context.Background()
+	var rjc = pb.NewRoutedJournalClient(broker.Client(), pb.NoopDispatchRouter{})
+
+	go func() {
+		serveReadFixtures(c, broker,
+			readFixture{status: pb.Status_OFFSET_NOT_YET_AVAILABLE, offset: 1000})
+
+		// An empty journal has no Fragment, and a WriteHead of zero.
+		var req = <-broker.ReadReqCh
+		c.Check(req, gc.DeepEquals, pb.ReadRequest{
+			Journal:      "a/journal",
+			Offset:       -1,
+			MetadataOnly: true,
+		})
+		broker.ReadRespCh <- pb.ReadResponse{
+			Status: pb.Status_OFFSET_NOT_YET_AVAILABLE,
+			Header: buildHeaderFixture(broker),
+		}
+		broker.WriteLoopErrCh <- nil
+
+		serveReadFixtures(c, broker,
+			readFixture{status: pb.Status_JOURNAL_NOT_FOUND, offset: 1000},
+			readFixture{status: pb.Status_NOT_JOURNAL_BROKER, offset: 1000},
+		)
+
+		// A misbehaving broker responds with OK at the write head.
+		<-broker.ReadReqCh
+		broker.ReadRespCh <- pb.ReadResponse{
+			Status:    pb.Status_OK,
+			Header:    buildHeaderFixture(broker),
+			Offset:    1000,
+			WriteHead: 1024,
+			Fragment: &pb.Fragment{
+				Journal:          "a/journal",
+				Begin:            1000,
+				End:              1024,
+				CompressionCodec: pb.CompressionCodec_NONE,
+			},
+		}
+		broker.WriteLoopErrCh <- nil
+	}()
+
+	var head, err = GetHead(ctx, rjc, "a/journal")
+	c.Check(err, gc.IsNil)
+	c.Check(head, gc.Equals, pb.Offset(1024))
+
+	head, err = GetHead(ctx, rjc, "a/journal")
+	c.Check(err, gc.IsNil)
+	c.Check(head, gc.Equals, pb.Offset(0))
+
+	_, err = GetHead(ctx, rjc, "a/journal")
+	c.Check(err, gc.Equals, ErrJournalNotFound)
+
+	_, err = GetHead(ctx, rjc, "a/journal")
+	c.Check(err, gc.Equals, ErrNotJournalBroker)
+
+	_, err = GetHead(ctx, rjc, "a/journal")
+	c.Check(err, gc.ErrorMatches, `unexpected OK response of metadata-only read at the write head \(offset 1000\)`)
+}
+
+func (s *HeadSuite) TestAwaitOffset(c *gc.C) {
+	var broker = teststub.NewBroker(c)
+	defer broker.Cleanup()
+
+	var ctx, cancel = context.WithCancel(context.Background())
+	var rjc = pb.NewRoutedJournalClient(broker.Client(), pb.NoopDispatchRouter{})
+
+	var serve = func(offset, writeHead int64, status pb.Status) {
+		var req = <-broker.ReadReqCh
+		c.Check(req, gc.DeepEquals, pb.ReadRequest{
+			Journal:      "a/journal",
+			Offset:       1999,
+			Block:        true,
+			MetadataOnly: true,
+		})
+		broker.ReadRespCh <- pb.ReadResponse{
+			Status:    status,
+			Header:    buildHeaderFixture(broker),
+			Offset:    offset,
+			WriteHead: writeHead,
+			Fragment: &pb.Fragment{
+				Journal:          "a/journal",
+				Begin:            offset,
+				End:              writeHead,
+				CompressionCodec: pb.CompressionCodec_NONE,
+			},
+		}
+		broker.WriteLoopErrCh <- nil
+	}
+
+	go func() {
+		// Case: the broker closes the RPC (eg, due to re-assignment) and
+		// then reports a routing error. Both are retried.
+		<-broker.ReadReqCh
+		broker.WriteLoopErrCh <- nil
+		serve(1999, 2048, pb.Status_NOT_JOURNAL_BROKER)
+		serve(1999, 2048, pb.Status_OK)
+
+		// Case: the offset is within a journal "hole".
+		serve(2500, 3000, pb.Status_OK)
+
+		// Case: the journal doesn't exist.
+		serve(1999, 2048, pb.Status_JOURNAL_NOT_FOUND)
+
+		// Case: the journal never reaches the offset, and the
+		// Context is cancelled while the RPC blocks.
+		<-broker.ReadReqCh
+		cancel()
+		broker.WriteLoopErrCh <- nil
+	}()
+
+	var head, err = AwaitOffset(ctx, rjc, "a/journal", 2000)
+	c.Check(err, gc.IsNil)
+	c.Check(head, gc.Equals, pb.Offset(2048))
+
+	head, err = AwaitOffset(ctx, rjc, "a/journal", 2000)
+	c.Check(err, gc.IsNil)
+	c.Check(head, gc.Equals, pb.Offset(3000))
+
+	_, err = AwaitOffset(ctx, rjc, "a/journal", 2000)
+	c.Check(err, gc.Equals, ErrJournalNotFound)
+
+	_, err = AwaitOffset(ctx, rjc, "a/journal", 2000)
+	c.Check(err, gc.Equals, context.Canceled)
+}
+
+func (s *HeadSuite) TestFragmentOffsetAt(c *gc.C) {
+	var broker = teststub.NewBroker(c)
+	defer broker.Cleanup()
+
+	var ctx = context.Background()
+	var rjc = pb.NewRoutedJournalClient(broker.Client(), pb.NoopDispatchRouter{})
+	var hdr = buildHeaderFixture(broker)
+
+	var resp = &pb.FragmentsResponse{
+		Header:    *hdr,
+		Fragments: buildSignedFragmentsFixture("a/journal", 100)[:1],
+	}
+	broker.ListFragmentsFunc = func(_ context.Context, req *pb.FragmentsRequest) (*pb.FragmentsResponse, error) {
+		// Expect the broker is asked for the first Fragment modified at or after the time.
+		c.Check(req, gc.DeepEquals, &pb.FragmentsRequest{
+			Header:       req.Header,
+			Journal:      "a/journal",
+			BeginModTime: 1600000000,
+			PageLimit:    1,
+		})
+		return resp, nil
+	}
+	var ts = time.Unix(1600000000, 500)
+
+	var offset, err = FragmentOffsetAt(ctx, rjc, "a/journal", ts)
+	c.Check(err, gc.IsNil)
+	c.Check(offset, gc.Equals, pb.Offset(100))
+
+	// Case: all Fragments were modified before the time. Expect the write head is returned.
+	resp = &pb.FragmentsResponse{Header: *hdr}
+
+	go serveReadFixtures(c, broker,
+		readFixture{status: pb.Status_OFFSET_NOT_YET_AVAILABLE, offset: 1000})
+
+	offset, err = FragmentOffsetAt(ctx, rjc, "a/journal", ts)
+	c.Check(err, gc.IsNil)
+	c.Check(offset, gc.Equals, pb.Offset(1024))
+
+	// Case: the journal doesn't exist.
+	resp = &pb.FragmentsResponse{Header: *hdr, Status: pb.Status_JOURNAL_NOT_FOUND}
+
+	_, err = FragmentOffsetAt(ctx, rjc, "a/journal", ts)
+	c.Check(err, gc.Equals, ErrJournalNotFound)
+}
+
+var _ = gc.Suite(&HeadSuite{})
@@ -2,11 +2,11 @@ package client
 
 import (
 	"context"
+	"sort"
 	"sync/atomic"
 	"time"
 
 	"github.com/pkg/errors"
-	log "github.com/sirupsen/logrus"
 	pb "go.gazette.dev/core/broker/protocol"
 	"google.golang.org/grpc"
 )
@@ -16,11 +16,10 @@ import (
 // applications which interact with dynamic journal sets and wish to react
 // to changes in their set membership over time.
 //
-//      var partitions, _ = protocol.ParseLabelSelector("logs=clicks, source=mobile")
-//      var pl, err = NewPolledList(ctx, client, time.Minute, protocol.ListRequest{
-//          Selector: partitions,
-//      })
-//
+//	var partitions, _ = protocol.ParseLabelSelector("logs=clicks, source=mobile")
+//	var pl, err = NewPolledList(ctx, client, time.Minute, protocol.ListRequest{
+//	    Selector: partitions,
+//	})
 type PolledList struct {
 	ctx      context.Context
 	client   pb.JournalClient
@@ -66,8 +65,8 @@ func (pl *PolledList) periodicRefresh(dur time.Duration) {
 		case <-ticker.C:
 			var resp, err = ListAllJournals(pl.ctx, pl.client, pl.req)
 			if err != nil {
-				log.WithFields(log.Fields{"err": err, "req": pl.req.String()}).
-					Warn("periodic List refresh failed (will retry)")
+				loggerFrom(pl.ctx).Log(LogWarn, "periodic List refresh failed (will retry)",
+					LogFields{"err": err, "req": pl.req.String()})
 			} else {
 				pl.resp.Store(resp)
 
@@ -83,6 +82,162 @@ func (pl *PolledList) periodicRefresh(dur time.Duration) {
 	}
 }
 
+// WatchedList is like PolledList, but is updated by the Watch RPC as journals
+// change rather than by periodic List RPCs. Its List is current with respect
+// to the broker serving the Watch, and costs no RPCs to maintain while
+// journals are unchanged. Should the Watch fail or fall behind, or the broker
+// go away, the WatchedList re-connects and resumes from its last revision, or
+// receives a new snapshot if the broker no longer retains changes since then.
+//
+//	var partitions, _ = protocol.ParseLabelSelector("logs=clicks, source=mobile")
+//	var wl, err = NewWatchedList(ctx, client, protocol.ListRequest{
+//	    Selector: partitions,
+//	})
+type WatchedList struct {
+	ctx      context.Context
+	client   pb.JournalClient
+	req      pb.ListRequest
+	resp     atomic.Value
+	updateCh chan struct{}
+
+	journals map[pb.Journal]pb.ListResponse_Journal // Current journals.
+	revision int64                                  // Revision of |journals|.
+}
+
+// NewWatchedList returns a WatchedList of the ListRequest which is initialized
+// and ready for immediate use. An error encountered in beginning the Watch
+// RPC, or in reading its first response, is returned. Subsequent RPC errors
+// will be logged as warnings and retried.
+func NewWatchedList(ctx context.Context, client pb.JournalClient, req pb.ListRequest) (*WatchedList, error) {
+	var wl = &WatchedList{
+		ctx:      ctx,
+		client:   client,
+		req:      req,
+		updateCh: make(chan struct{}, 1),
+		journals: make(map[pb.Journal]pb.ListResponse_Journal),
+	}
+	var stream, err = wl.watch()
+	if err != nil {
+		return nil, err
+	}
+	go wl.serveWatch(stream)
+	return wl, nil
+}
+
+// List returns the most recent ListResponse of the WatchedList,
+// having journals ordered on name.
+func (wl *WatchedList) List() *pb.ListResponse { return wl.resp.Load().(*pb.ListResponse) }
+
+// UpdateCh returns a channel which is signaled with each update of the
+// WatchedList. Only one channel is allocated and one signal sent per-update,
+// so if multiple goroutines select from UpdateCh only one will wake.
+func (wl *WatchedList) UpdateCh() <-chan struct{} { return wl.updateCh }
+
+// watch begins a Watch RPC which resumes from the current revision,
+// and applies its first response.
+func (wl *WatchedList) watch() (pb.Journal_WatchClient, error) {
+	// Watch RPCs may be dispatched to any broker.
+	var stream, err = wl.client.Watch(pb.WithDispatchDefault(wl.ctx), &pb.WatchRequest{
+		Selector:       wl.req.Selector,
+		ResumeRevision: wl.revision,
+	}, grpc.WaitForReady(true))
+
+	if err != nil {
+		return nil, mapGRPCCtxErr(wl.ctx, err)
+	} else if err = wl.recv(stream); err != nil {
+		return nil, err
+	}
+	return stream, nil
+}
+
+// recv reads and applies the next WatchResponse of the |stream|.
+func (wl *WatchedList) recv(stream pb.Journal_WatchClient) error {
+	var resp, err = stream.Recv()
+	if err != nil {
+		return mapGRPCCtxErr(wl.ctx, err)
+	} else if err = resp.Validate(); err != nil {
+		return err
+	} else if resp.Status != pb.Status_OK {
+		return errors.New(resp.Status.String())
+	}
+	var dr, _ = wl.client.(pb.DispatchRouter)
+	var deleted = resp.Deleted
+
+	if resp.Snapshot {
+		// Journals not in the snapshot were deleted while we weren't watching.
+		var prior = wl.journals
+		wl.journals = make(map[pb.Journal]pb.ListResponse_Journal, len(resp.Journals))
+
+		for _, j := range resp.Journals {
+			delete(prior, j.Spec.Name)
+		}
+		for name := range prior {
+			deleted = append(deleted, name)
+		}
+	}
+	for _, j := range resp.Journals {
+		wl.journals[j.Spec.Name] = j
+
+		if dr != nil {
+			dr.UpdateRoute(j.Spec.Name.String(), &j.Route)
+		}
+	}
+	for _, name := range deleted {
+		delete(wl.journals, name)
+
+		if dr != nil {
+			dr.UpdateRoute(name.String(), nil)
+		}
+	}
+	wl.revision = resp.Revision
+
+	var out = &pb.ListResponse{
+		Status:   pb.Status_OK,
+		Header:   resp.Header,
+		Journals: make([]pb.ListResponse_Journal, 0, len(wl.journals)),
+	}
+	for _, j := range wl.journals {
+		out.Journals = append(out.Journals, j)
+	}
+	sort.Slice(out.Journals, func(i, j int) bool {
+		return out.Journals[i].Spec.Name < out.Journals[j].Spec.Name
+	})
+	wl.resp.Store(out)
+
+	select {
+	case wl.updateCh <- struct{}{}:
+	default: // Don't block if nobody's reading.
+	}
+	return nil
+}
+
+func (wl *WatchedList) serveWatch(stream pb.Journal_WatchClient) {
+	for attempt := 0; true; attempt++ {
+		var err error
+		if stream == nil {
+			stream, err = wl.watch()
+		} else {
+			err = wl.recv(stream)
+		}
+
+		if err == nil {
+			attempt = -1
+			continue
+		} else if wl.ctx.Err() != nil {
+			return
+		}
+		loggerFrom(wl.ctx).Log(LogWarn, "journal Watch failed (will retry)",
+			LogFields{"err": err, "req": wl.req.String(), "revision": wl.revision})
+
+		stream = nil
+		select {
+		case <-time.After(backoff(attempt)):
+		case <-wl.ctx.Done():
+			return
+		}
+	}
+}
+
 // ListAllJournals performs a broker journal listing.
 // Any encountered error is returned.
 func ListAllJournals(ctx context.Context, client pb.JournalClient, req pb.ListRequest) (*pb.ListResponse, error) {
@@ -120,6 +275,81 @@ func GetJournal(ctx context.Context, jc pb.JournalClient, journal pb.Journal) (*
 	return &lr.Journals[0].Spec, nil
 }
 
+// JournalResult is the outcome of an individual journal of GetJournals.
+type JournalResult struct {
+	// Spec of the journal, or nil if Err is set.
+	Spec *pb.JournalSpec
+	// ModRevision of the JournalSpec.
+	ModRevision int64
+	// Err is ErrJournalNotFound if the journal doesn't exist, or is an error
+	// encountered while fetching the journal (eg, of a failed List RPC).
+	Err error
+}
+
+// GetJournals retrieves the JournalSpecs of many named journals, which are
+// fetched by List RPCs having selectors of up to |size| journal names each.
+// If |size| is zero, all journals are fetched by a single List RPC.
+//
+// GetJournals returns a JournalResult for each distinct journal, keyed on its
+// name as provided (which may include metadata). The result of a journal which
+// doesn't exist has an Err of ErrJournalNotFound. An invalid journal name, or
+// an error of a List RPC, is an Err of only the affected journals: other
+// journals are still fetched, and partial results are returned. Callers may
+// retry journals having errors other than ErrJournalNotFound.
+func GetJournals(ctx context.Context, jc pb.JournalClient, journals []pb.Journal, size int) map[pb.Journal]JournalResult {
+	var out = make(map[pb.Journal]JournalResult, len(journals))
+	var names []pb.Journal                           // Distinct, valid journal names.
+	var provided = make(map[pb.Journal][]pb.Journal) // Journal name => provided names.
+
+	for _, journal := range journals {
+		var name = journal.StripMeta()
+
+		if _, ok := out[journal]; ok {
+			continue // Duplicate.
+		} else if err := name.Validate(); err != nil {
+			out[journal] = JournalResult{Err: err}
+			continue
+		} else if _, ok := provided[name]; !ok {
+			names = append(names, name)
+		}
+		provided[name] = append(provided[name], journal)
+		out[journal] = JournalResult{Err: ErrJournalNotFound} // Until fetched.
+	}
+	if size == 0 {
+		size = len(names)
+	}
+
+	for len(names) != 0 {
+		var batch = names
+		if len(batch) > size {
+			batch = batch[:size]
+		}
+		names = names[len(batch):]
+
+		var selector pb.LabelSelector
+		for _, name := range batch {
+			selector.Include.AddValue("name", name.String())
+		}
+		var resp, err = ListAllJournals(ctx, jc, pb.ListRequest{Selector: selector})
+
+		if err != nil {
+			for _, name := range batch {
+				for _, journal := range provided[name] {
+					out[journal] = JournalResult{Err: err}
+				}
+			}
+			continue
+		}
+		for _, j := range resp.Journals {
+			for _, journal := range provided[j.Spec.Name] {
+				var spec = j.Spec
+				out[journal] = JournalResult{Spec: &spec, ModRevision: j.ModRevision}
+			}
+		}
+	}
+	return out
+}
+
 // ApplyJournals applies journal changes detailed in the ApplyRequest via the broker Apply RPC.
 // Changes are applied as a single Etcd transaction. If the change list is larger than an
 // Etcd transaction can accommodate, ApplyJournalsInBatches should be used instead.
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/stretchr/testify/require"
@@ -99,6 +100,57 @@ func (s *ListSuite) TestGetJournal(c *gc.C) {
 	c.Check(spec, gc.IsNil)
 }
 
+func (s *ListSuite) TestGetJournals(c *gc.C) {
+	var broker = teststub.NewBroker(c)
+	defer broker.Cleanup()
+
+	var hdr = *buildHeaderFixture(broker)
+	var calls int
+
+	// Journals "missing" don't exist, and a List RPC of "fails" returns an error.
+	broker.ListFunc = func(_ context.Context, req *pb.ListRequest) (*pb.ListResponse, error) {
+		calls++
+
+		var names []pb.Journal
+		for _, value := range req.Selector.Include.ValuesOf("name") {
+			if value == "fails/1" {
+				return nil, errors.New("unavailable")
+			} else if !strings.HasPrefix(value, "missing/") {
+				names = append(names, pb.Journal(value))
+			}
+		}
+		return &pb.ListResponse{Header: hdr, Journals: buildListResponseFixture(names...)}, nil
+	}
+
+	var rjc = pb.NewRoutedJournalClient(broker.Client(), pb.NoopDispatchRouter{})
+	var journals = []pb.Journal{
+		"foo/1", "foo/2;with/meta", "fails/1", "foo/1", "foo/2", "foo/3", "missing/1", "invalid name",
+	}
+
+	// Case: journals are fetched in batches of two distinct names.
+	var out = GetJournals(context.Background(), rjc, journals, 2)
+	c.Check(calls, gc.Equals, 3)
+	c.Check(out, gc.HasLen, 7)
+
+	for _, j := range []pb.Journal{"foo/1", "foo/2;with/meta", "foo/2"} {
+		c.Check(out[j].Err, gc.IsNil)
+		c.Check(out[j].Spec.Name, gc.Equals, j.StripMeta())
+		c.Check(out[j].ModRevision, gc.Equals, int64(1234))
+	}
+	c.Check(out["missing/1"], gc.DeepEquals, JournalResult{Err: ErrJournalNotFound})
+	// "foo/3" is batched with "fails/1", and its List RPC fails.
+	c.Check(out["fails/1"].Err, gc.ErrorMatches, `rpc error: code = Unknown desc = unavailable`)
+	c.Check(out["foo/3"].Err, gc.ErrorMatches, `rpc error: code = Unknown desc = unavailable`)
+	c.Check(out["invalid name"].Err, gc.ErrorMatches, `not a valid token \(invalid name\)`)
+
+	// Case: journals are fetched by a single List RPC.
+	calls = 0
+	out = GetJournals(context.Background(), rjc, []pb.Journal{"foo/1", "foo/2", "missing/2"}, 0)
+	c.Check(calls, gc.Equals, 1)
+	c.Check(out["foo/2"].Spec.Name, gc.Equals, pb.Journal("foo/2"))
+	c.Check(out["missing/2"].Err, gc.Equals, ErrJournalNotFound)
+}
+
 func (s *ListSuite) TestPolledList(c *gc.C) {
 	var broker = teststub.NewBroker(c)
 	defer broker.Cleanup()
@@ -140,6 +192,69 @@ func (s *ListSuite) TestPolledList(c *gc.C) {
 	c.Check(pl.List(), gc.DeepEquals, &fixture)
 }
 
+func (s *ListSuite) TestWatchedList(c *gc.C) {
+	var broker = teststub.NewBroker(c)
+	defer broker.Cleanup()
+
+	var mk = buildListResponseFixture // Alias.
+	var hdr = *buildHeaderFixture(broker)
+
+	var reqCh = make(chan pb.WatchRequest, 1)
+	var respCh = make(chan *pb.WatchResponse, 1)
+
+	broker.WatchFunc = func(req *pb.WatchRequest, srv pb.Journal_WatchServer) error {
+		reqCh <- *req
+		for {
+			select {
+			case resp := <-respCh:
+				if resp == nil {
+					return errors.New("broker went away")
+				} else if err := srv.Send(resp); err != nil {
+					return err
+				}
+			case <-srv.Context().Done():
+				return nil
+			}
+		}
+	}
+
+	var ctx, cancel = context.WithCancel(context.Background())
+	defer cancel()
+
+	var rc = NewRouteCache(10, time.Minute)
+	var rjc = pb.NewRoutedJournalClient(broker.Client(), rc)
+	var sel = pb.LabelSelector{Include: pb.MustLabelSet("prefix", "part-")}
+
+	// Expect NewWatchedList begins with a snapshot, and List is prepared before return.
+	respCh <- &pb.WatchResponse{Header: hdr, Revision: 10, Snapshot: true, Journals: mk("part-one", "part-two")}
+
+	var wl, err = NewWatchedList(ctx, rjc, pb.ListRequest{Selector: sel})
+	c.Assert(err, gc.IsNil)
+	c.Check(<-reqCh, gc.DeepEquals, pb.WatchRequest{Selector: sel})
+	c.Check(wl.List(), gc.DeepEquals, &pb.ListResponse{Header: hdr, Journals: mk("part-one", "part-two")})
+	c.Check(rc.Route(ctx, "part-one").Members, gc.HasLen, 1)
+	<-wl.UpdateCh() // Expect UpdateCh is initially ready to select.
+
+	// Changes are applied as they're streamed, and deleted journals are removed.
+	respCh <- &pb.WatchResponse{Header: hdr, Revision: 11, Journals: mk("part-three"), Deleted: []pb.Journal{"part-one"}}
+	<-wl.UpdateCh()
+
+	c.Check(wl.List(), gc.DeepEquals, &pb.ListResponse{Header: hdr, Journals: mk("part-three", "part-two")})
+	c.Check(rc.Route(ctx, "part-one").Members, gc.HasLen, 0)
+
+	// The Watch fails. Expect it's resumed from the last revision.
+	respCh <- nil
+	c.Check(<-reqCh, gc.DeepEquals, pb.WatchRequest{Selector: sel, ResumeRevision: 11})
+
+	// The broker responds with a snapshot, as if the revision were compacted.
+	respCh <- &pb.WatchResponse{Header: hdr, Revision: 20, Snapshot: true, Journals: mk("part-four", "part-two")}
+	<-wl.UpdateCh()
+
+	c.Check(wl.List(), gc.DeepEquals, &pb.ListResponse{Header: hdr, Journals: mk("part-four", "part-two")})
+	c.Check(rc.Route(ctx, "part-three").Members, gc.HasLen, 0) // Not in the snapshot.
+	c.Check(rc.Route(ctx, "part-two").Members, gc.HasLen, 1)
+}
+
 func (s *ListSuite) TestListAllFragments(c *gc.C) {
 	var broker = teststub.NewBroker(c)
 	defer broker.Cleanup()
@@ -287,6 +402,141 @@ func (s *ListSuite) TestApplyJournalsInBatches(c *gc.C) {
 	c.Check(err, gc.ErrorMatches, `Header.Route: invalid Primary \(0; expected -1 <= Primary < 0\)`)
 }
 
+func (s *ListSuite) TestApplyJournalsAtomically(c *gc.C) {
+	var broker = teststub.NewBroker(c)
+	defer broker.Cleanup()
+
+	var ctx = context.Background()
+	var hdr = buildHeaderFixture(broker)
+
+	// Journals "journal/exists", "journal/gone", and "journal/same" exist,
+	// at ModRevision 1234.
+	var current = buildListResponseFixture("journal/exists", "journal/gone", "journal/same")
+	broker.ListFunc = func(_ context.Context, req *pb.ListRequest) (*pb.ListResponse, error) {
+		var resp = &pb.ListResponse{Header: *hdr}
+		for _, name := range req.Selector.Include.ValuesOf("name") {
+			for _, j := range current {
+				if j.Spec.Name.String() == name {
+					resp.Journals = append(resp.Journals, j)
+				}
+			}
+		}
+		return resp, nil
+	}
+
+	var updated = current[0].Spec
+	updated.Replication = 2
+	var created = current[0].Spec
+	created.Name = "journal/new"
+
+	var unchanged = current[2].Spec
+
+	var req = &pb.ApplyRequest{
+		Changes: []pb.ApplyRequest_Change{
+			{Upsert: &unchanged, ExpectModRevision: -1},
+			{Upsert: &created, ExpectModRevision: 0},
+			{Delete: "journal/gone", ExpectModRevision: 1234},
+			{Upsert: &updated, ExpectModRevision: 1234},
+		},
+	}
+
+	// Case: plan the ApplyRequest, without applying it.
+	broker.ApplyFunc = func(context.Context, *pb.ApplyRequest) (*pb.ApplyResponse, error) {
+		c.Error("unexpected Apply")
+		return nil, nil
+	}
+	var plans, err = PlanJournals(ctx, broker.Client(), req, 0)
+	c.Assert(err, gc.IsNil)
+	c.Check(plans, gc.HasLen, 4)
+
+	var actions []JournalPlanAction
+	for _, plan := range plans {
+		actions = append(actions, plan.Action)
+	}
+	c.Check(actions, gc.DeepEquals, []JournalPlanAction{PlanUnchanged, PlanCreate, PlanDelete, PlanUpdate})
+	c.Check(plans[2].Current, gc.DeepEquals, &current[1].Spec)
+	c.Check(plans[2].ModRevision, gc.Equals, int64(1234))
+	c.Check(plans[1].Current, gc.IsNil)
+
+	// Case: changes don't fit in one transaction, and a later batch fails.
+	// Expect applied batches are rolled back.
+	var applied []*pb.ApplyRequest
+	broker.ApplyFunc = func(_ context.Context, req *pb.ApplyRequest) (*pb.ApplyResponse, error) {
+		applied = append(applied, req)
+
+		var resp = &pb.ApplyResponse{Status: pb.Status_OK, Header: *hdr}
+		resp.Header.Etcd.Revision = 100 + int64(len(applied))
+		if len(applied) == 4 {
+			resp.Status = pb.Status_ETCD_TRANSACTION_FAILED
+		}
+		return resp, nil
+	}
+	_, err = ApplyJournalsAtomically(ctx, broker.Client(), req, 1)
+	c.Check(err, gc.ErrorMatches, pb.Status_ETCD_TRANSACTION_FAILED.String())
+
+	c.Check(applied, gc.DeepEquals, []*pb.ApplyRequest{
+		{Changes: req.Changes[0:1]},
+		{Changes: req.Changes[1:2]},
+		{Changes: req.Changes[2:3]},
+		{Changes: req.Changes[3:4]},
+		// Rollback of the deletion, creation, and unchanged upsert.
+		{Changes: []pb.ApplyRequest_Change{{Upsert: &current[1].Spec, ExpectModRevision: 0}}},
+		{Changes: []pb.ApplyRequest_Change{{Delete: "journal/new", ExpectModRevision: 102}}},
+		{Changes: []pb.ApplyRequest_Change{{Upsert: &current[2].Spec, ExpectModRevision: 101}}},
+	})
+
+	// Case: the rollback also fails.
+	applied = applied[:0]
+	broker.ApplyFunc = func(_ context.Context, req *pb.ApplyRequest) (*pb.ApplyResponse, error) {
+		if applied = append(applied, req); len(applied) != 1 {
+			return nil, errors.New("whoops")
+		}
+		return &pb.ApplyResponse{Status: pb.Status_OK, Header: *hdr}, nil
+	}
+	_, err = ApplyJournalsAtomically(ctx, broker.Client(), req, 2)
+	c.Check(err, gc.ErrorMatches, `rolling back applied changes failed \(.*whoops\): .*whoops`)
+	c.Check(applied, gc.HasLen, 3)
+
+	// Case: all batches apply.
+	applied = applied[:0]
+	broker.ApplyFunc = func(_ context.Context, req *pb.ApplyRequest) (*pb.ApplyResponse, error) {
+		applied = append(applied, req)
+		return &pb.ApplyResponse{Status: pb.Status_OK, Header: *hdr}, nil
+	}
+	_, err = ApplyJournalsAtomically(ctx, broker.Client(), req, 3)
+	c.Check(err, gc.IsNil)
+	c.Check(applied, gc.DeepEquals, []*pb.ApplyRequest{
+		{Changes: req.Changes[0:3]},
+		{Changes: req.Changes[3:4]},
+	})
+
+	// Case: changes which fit in one transaction are applied directly.
+	applied = applied[:0]
+	_, err = ApplyJournalsAtomically(ctx, broker.Client(), req, 0)
+	c.Check(err, gc.IsNil)
+	c.Check(applied, gc.DeepEquals, []*pb.ApplyRequest{req})
+
+	// Case: a change expects a ModRevision other than the current one.
+	applied = applied[:0]
+	_, err = ApplyJournalsAtomically(ctx, broker.Client(), &pb.ApplyRequest{
+		Changes: []pb.ApplyRequest_Change{{Delete: "journal/gone", ExpectModRevision: 1}},
+	}, 0)
+	c.Check(err, gc.ErrorMatches, `journal journal/gone has ModRevision 1234 \(expected 1\)`)
+
+	// Case: a journal is changed more than once.
+	_, err = PlanJournals(ctx, broker.Client(), &pb.ApplyRequest{
+		Changes: []pb.ApplyRequest_Change{req.Changes[1], req.Changes[1]},
+	}, 0)
+	c.Check(err, gc.ErrorMatches, `journal journal/new is changed more than once`)
+
+	// Case: an invalid change.
+	_, err = PlanJournals(ctx, broker.Client(), &pb.ApplyRequest{
+		Changes: []pb.ApplyRequest_Change{{Delete: "journal/gone"}},
+	}, 0)
+	c.Check(err, gc.ErrorMatches, `Changes\[0\]: invalid ExpectModRevision .*`)
+	c.Check(applied, gc.HasLen, 0)
+}
+
 func buildApplyReqFixtue() *pb.ApplyRequest {
 	// Create a fixture of JournalSpecs which we'll list.
 	var fragSpec = pb.JournalSpec_Fragment{
@@ -0,0 +1,152 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Logger is a structured logger of broker client operations. Operations log
+// through the Logger attached to their Context (see WithLogger), or through
+// the standard logrus Logger if there is none. Logged fields identify the
+// operation, such as its "journal", "offset", and retry "attempt", as well as
+// the "err" which was encountered.
+type Logger interface {
+	// Log a |msg| at |level| with structured |fields|.
+	Log(level LogLevel, msg string, fields LogFields)
+}
+
+// LogLevel is the severity of a log of a Logger.
+type LogLevel int
+
+const (
+	LogDebug LogLevel = iota
+	LogInfo
+	LogWarn
+	LogError
+)
+
+func (l LogLevel) String() string {
+	switch l {
+	case LogDebug:
+		return "debug"
+	case LogInfo:
+		return "info"
+	case LogWarn:
+		return "warning"
+	case LogError:
+		return "error"
+	default:
+		return fmt.Sprintf("LogLevel(%d)", int(l))
+	}
+}
+
+// LogFields are the structured fields of a log of a Logger, keyed on name.
+type LogFields map[string]interface{}
+
+// WithLogger attaches a Logger to the Context. Readers, RetryReaders,
+// Appenders, AppendServices, PolledLists, and WatchedLists of the returned
+// Context log through |logger|. Applications may use it to route client logs
+// through their own structured logging, and to add request-scoped fields
+// (eg, a trace ID) which correlate client logs with their own.
+func WithLogger(ctx context.Context, logger Logger) context.Context {
+	return context.WithValue(ctx, loggerCtxKey{}, logger)
+}
+
+// loggerFrom returns the Logger attached to the Context,
+// or a Logger of the standard logrus Logger if there is none.
+func loggerFrom(ctx context.Context) Logger {
+	if ctx != nil {
+		if l, ok := ctx.Value(loggerCtxKey{}).(Logger); ok {
+			return l
+		}
+	}
+	return logrusLogger{}
+}
+
+// NewSampledLogger returns a Logger which passes at most |burst| logs of each
+// message within each |interval| through to |logger|, and drops the rest.
+// The next log of a message which is passed through after drops reports their
+// number as field "dropped". Use it to bound the volume of logs of an
+// operation which is retried many times, such as while brokers are unavailable.
+func NewSampledLogger(logger Logger, burst int, interval time.Duration) Logger {
+	return &sampledLogger{
+		logger:   logger,
+		burst:    burst,
+		interval: interval,
+		samples:  make(map[string]*logSample),
+		now:      time.Now,
+	}
+}
+
+type sampledLogger struct {
+	logger   Logger
+	burst    int
+	interval time.Duration
+
+	mu      sync.Mutex
+	samples map[string]*logSample // Keyed on message.
+	now     func() time.Time
+}
+
+type logSample struct {
+	begin   time.Time // Beginning of the current interval.
+	count   int       // Logs of the current interval.
+	dropped int       // Logs dropped since the last passed-through log.
+}
+
+func (l *sampledLogger) Log(level LogLevel, msg string, fields LogFields) {
+	var now = l.now()
+
+	l.mu.Lock()
+	var s, ok = l.samples[msg]
+	if !ok {
+		s = &logSample{begin: now}
+		l.samples[msg] = s
+	} else if now.Sub(s.begin) >= l.interval {
+		s.begin, s.count = now, 0
+	}
+
+	if s.count++; s.count > l.burst {
+		s.dropped++
+		l.mu.Unlock()
+		return
+	}
+	var dropped = s.dropped
+	s.dropped = 0
+	l.mu.Unlock()
+
+	if dropped != 0 {
+		var withDropped = make(LogFields, len(fields)+1)
+		for k, v := range fields {
+			withDropped[k] = v
+		}
+		withDropped["dropped"] = dropped
+		fields = withDropped
+	}
+	l.logger.Log(level, msg, fields)
+}
+
+// logrusLogger is a Logger of the standard logrus Logger.
+type logrusLogger struct{}
+
+func (logrusLogger) Log(level LogLevel, msg string, fields LogFields) {
+	var lvl log.Level
+
+	switch level {
+	case LogDebug:
+		lvl = log.DebugLevel
+	case LogInfo:
+		lvl = log.InfoLevel
+	case LogWarn:
+		lvl = log.WarnLevel
+	default:
+		lvl = log.ErrorLevel
+	}
+	log.WithFields(log.Fields(fields)).Log(lvl, msg)
+}
+
+type loggerCtxKey struct{}
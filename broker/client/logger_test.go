@@ -0,0 +1,95 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"sync"
+	"time"
+
+	pb "go.gazette.dev/core/broker/protocol"
+	"go.gazette.dev/core/broker/teststub"
+	gc "gopkg.in/check.v1"
+)
+
+type LoggerSuite struct{}
+
+func (s *LoggerSuite) TestRetryReaderLogsThroughContextLogger(c *gc.C) {
+	var broker = teststub.NewBroker(c)
+	defer broker.Cleanup()
+
+	var logger = new(recordingLogger)
+	var ctx = WithLogger(context.Background(), logger)
+	var rjc = pb.NewRoutedJournalClient(broker.Client(), pb.NoopDispatchRouter{})
+	var rr = NewRetryReader(ctx, rjc, pb.ReadRequest{Journal: "a/journal", Offset: 100})
+
+	go serveReadFixtures(c, broker,
+		readFixture{content: "foo", err: errors.New("whoops")},
+		readFixture{content: "bar", status: pb.Status_OFFSET_NOT_YET_AVAILABLE},
+	)
+
+	var b, err = ioutil.ReadAll(rr)
+	c.Check(string(b), gc.Equals, "foobar")
+	c.Check(err, gc.Equals, ErrOffsetNotYetAvailable)
+
+	c.Assert(logger.entries, gc.HasLen, 1)
+	c.Check(logger.entries[0].level, gc.Equals, LogWarn)
+	c.Check(logger.entries[0].msg, gc.Equals, "read failure (will retry)")
+	c.Check(logger.entries[0].fields["journal"], gc.Equals, pb.Journal("a/journal"))
+	c.Check(logger.entries[0].fields["offset"], gc.Equals, int64(103))
+	c.Check(logger.entries[0].fields["attempt"], gc.Equals, 0)
+	c.Check(logger.entries[0].fields["err"], gc.ErrorMatches, "rpc error: .* whoops")
+}
+
+func (s *LoggerSuite) TestSampledLogger(c *gc.C) {
+	var logger = new(recordingLogger)
+	var sampled = NewSampledLogger(logger, 2, time.Minute).(*sampledLogger)
+
+	var now = time.Unix(1000, 0)
+	sampled.now = func() time.Time { return now }
+
+	for i := 0; i != 5; i++ {
+		sampled.Log(LogWarn, "retry", LogFields{"attempt": i})
+	}
+	// Messages are sampled independently.
+	sampled.Log(LogInfo, "other", nil)
+
+	// Logs of a following interval pass through, reporting drops.
+	now = now.Add(time.Minute)
+	sampled.Log(LogWarn, "retry", LogFields{"attempt": 5})
+	sampled.Log(LogWarn, "retry", LogFields{"attempt": 6})
+
+	c.Check(logger.entries, gc.DeepEquals, []logEntry{
+		{LogWarn, "retry", LogFields{"attempt": 0}},
+		{LogWarn, "retry", LogFields{"attempt": 1}},
+		{LogInfo, "other", nil},
+		{LogWarn, "retry", LogFields{"attempt": 5, "dropped": 3}},
+		{LogWarn, "retry", LogFields{"attempt": 6}},
+	})
+}
+
+func (s *LoggerSuite) TestDefaultLogger(c *gc.C) {
+	c.Check(loggerFrom(context.Background()), gc.Equals, Logger(logrusLogger{}))
+
+	var logger = new(recordingLogger)
+	c.Check(loggerFrom(WithLogger(context.Background(), logger)), gc.Equals, Logger(logger))
+}
+
+type logEntry struct {
+	level  LogLevel
+	msg    string
+	fields LogFields
+}
+
+type recordingLogger struct {
+	mu      sync.Mutex
+	entries []logEntry
+}
+
+func (l *recordingLogger) Log(level LogLevel, msg string, fields LogFields) {
+	l.mu.Lock()
+	l.entries = append(l.entries, logEntry{level, msg, fields})
+	l.mu.Unlock()
+}
+
+var _ = gc.Suite(&LoggerSuite{})
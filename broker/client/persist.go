@@ -0,0 +1,131 @@
+package client
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	pb "go.gazette.dev/core/broker/protocol"
+)
+
+// PersistLag is the lag of a journal's persisted Fragments behind its write head.
+type PersistLag struct {
+	Journal pb.Journal
+	// WriteHead of the journal.
+	WriteHead pb.Offset
+	// PersistedThrough is the greatest End offset of a Fragment which has been
+	// persisted to a fragment store of the journal. If no Fragment has been
+	// persisted, it's the Begin offset of the journal's oldest local Fragment
+	// (or the write head, if the journal has no Fragments).
+	PersistedThrough pb.Offset
+	// Bytes is the number of bytes by which PersistedThrough trails WriteHead.
+	Bytes int64
+	// SpooledBytes are the bytes of Bytes which are held by the journal's
+	// current spool. Spooled content is persisted only once the spool reaches
+	// its target length or flush interval, and an idle or infrequently written
+	// journal may hold spooled content for the full flush interval (or
+	// indefinitely, if it has none). Spooled bytes are not indicative of
+	// fragment store throughput.
+	SpooledBytes int64
+	// BacklogFragments is the number of local Fragments which have been rolled
+	// and are awaiting persistence to a fragment store. It's typically zero or
+	// one; a larger or growing backlog indicates that persistence is failing
+	// to keep pace with appends, due to insufficient store throughput or an
+	// unavailable store.
+	BacklogFragments int
+}
+
+// GetPersistLag returns the PersistLag of the journal, as determined from its
+// current write head and its listed Fragments. Persistence of Fragments is
+// observed only as the journal's brokers refresh their index of persisted
+// Fragments, and PersistLag may therefore overstate the true lag by up to the
+// refresh interval of the JournalSpec_Fragment. GetPersistLag also updates the
+// gazette_journal_persist_lag_bytes and gazette_journal_persist_backlog_fragments
+// gauges of the journal.
+//
+// A journal without fragment stores is never persisted, and ErrNoFragmentStores
+// is returned. Gauges of a journal without stores, or which no longer exists,
+// are deleted.
+func GetPersistLag(ctx context.Context, rjc pb.RoutedJournalClient, journal pb.Journal) (PersistLag, error) {
+	var result = GetJournals(ctx, rjc, []pb.Journal{journal}, 0)[journal]
+
+	if result.Err == ErrJournalNotFound {
+		deletePersistLagGauges(journal)
+		return PersistLag{}, result.Err
+	} else if result.Err != nil {
+		return PersistLag{}, errors.WithMessage(result.Err, "fetching journal")
+	} else if len(result.Spec.Fragment.Stores) == 0 {
+		deletePersistLagGauges(journal)
+		return PersistLag{}, ErrNoFragmentStores
+	}
+
+	var head, err = GetHead(ctx, rjc, journal)
+	if err == ErrJournalNotFound {
+		deletePersistLagGauges(journal) // Deleted since it was fetched.
+	}
+	if err != nil {
+		return PersistLag{}, errors.WithMessage(err, "fetching write head")
+	}
+	resp, err := ListAllFragments(ctx, rjc, pb.FragmentsRequest{Journal: journal})
+	if err != nil {
+		return PersistLag{}, errors.WithMessage(err, "listing fragments")
+	}
+	var lag = persistLag(journal, head, resp.Fragments)
+
+	persistLagBytes.WithLabelValues(journal.String()).Set(float64(lag.Bytes))
+	persistBacklogFragments.WithLabelValues(journal.String()).Set(float64(lag.BacklogFragments))
+
+	return lag, nil
+}
+
+// ErrNoFragmentStores is returned by GetPersistLag for a journal which has no
+// fragment stores, and which therefore has no PersistLag.
+var ErrNoFragmentStores = errors.New("journal has no fragment stores")
+
+func deletePersistLagGauges(journal pb.Journal) {
+	persistLagBytes.DeleteLabelValues(journal.String())
+	persistBacklogFragments.DeleteLabelValues(journal.String())
+}
+
+// persistLag computes the PersistLag of the journal from its write head and
+// its ordered |fragments|.
+func persistLag(journal pb.Journal, head pb.Offset, fragments []pb.FragmentsResponse__Fragment) PersistLag {
+	var lag = PersistLag{Journal: journal, WriteHead: head, PersistedThrough: head}
+	var local []pb.Fragment
+
+	var persisted bool
+	for _, f := range fragments {
+		if f.Spec.BackingStore != "" {
+			if !persisted || f.Spec.End > lag.PersistedThrough {
+				lag.PersistedThrough = f.Spec.End
+			}
+			persisted = true
+		} else {
+			local = append(local, f.Spec)
+		}
+	}
+	if !persisted && len(local) != 0 {
+		lag.PersistedThrough = local[0].Begin
+	}
+	if lag.PersistedThrough > head {
+		// The listing may be more recent than |head|.
+		lag.PersistedThrough = head
+	}
+	lag.Bytes = head - lag.PersistedThrough
+
+	// Local Fragments which extend beyond PersistedThrough are unpersisted.
+	// The final one, if it extends through the write head, is the current
+	// spool and others were rolled and await persistence.
+	for i, f := range local {
+		if f.End <= lag.PersistedThrough {
+			continue // Persisted, but not yet removed from the index.
+		} else if i == len(local)-1 && f.End >= head {
+			lag.SpooledBytes = head - f.Begin
+			if f.Begin < lag.PersistedThrough {
+				lag.SpooledBytes = lag.Bytes
+			}
+		} else {
+			lag.BacklogFragments++
+		}
+	}
+	return lag
+}
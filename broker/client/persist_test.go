@@ -0,0 +1,114 @@
+package client
+
+import (
+	"context"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	pb "go.gazette.dev/core/broker/protocol"
+	"go.gazette.dev/core/broker/teststub"
+	gc "gopkg.in/check.v1"
+)
+
+type PersistSuite struct{}
+
+func (s *PersistSuite) TestPersistLagCases(c *gc.C) {
+	var frag = func(begin, end int64, store pb.FragmentStore) pb.FragmentsResponse__Fragment {
+		return pb.FragmentsResponse__Fragment{Spec: pb.Fragment{
+			Journal: "a/journal", Begin: begin, End: end, BackingStore: store}}
+	}
+	const store = "s3://bucket/"
+
+	for _, tc := range []struct {
+		head      int64
+		fragments []pb.FragmentsResponse__Fragment
+		expect    PersistLag
+	}{
+		// Case: an empty journal.
+		{0, nil, PersistLag{}},
+		// Case: fully persisted, with a persisted local Fragment still indexed.
+		{200, []pb.FragmentsResponse__Fragment{
+			frag(0, 100, store), frag(100, 200, ""), frag(100, 200, store)},
+			PersistLag{WriteHead: 200, PersistedThrough: 200}},
+		// Case: an idle journal, with content held only by its spool.
+		{250, []pb.FragmentsResponse__Fragment{
+			frag(0, 100, store), frag(100, 200, store), frag(200, 250, "")},
+			PersistLag{WriteHead: 250, PersistedThrough: 200, Bytes: 50, SpooledBytes: 50}},
+		// Case: rolled Fragments are backlogged behind the spool.
+		{400, []pb.FragmentsResponse__Fragment{
+			frag(0, 100, store), frag(100, 200, ""), frag(200, 300, ""), frag(300, 400, "")},
+			PersistLag{WriteHead: 400, PersistedThrough: 100, Bytes: 300, SpooledBytes: 100, BacklogFragments: 2}},
+		// Case: nothing is persisted.
+		{300, []pb.FragmentsResponse__Fragment{frag(100, 200, ""), frag(200, 300, "")},
+			PersistLag{WriteHead: 300, PersistedThrough: 100, Bytes: 200, SpooledBytes: 100, BacklogFragments: 1}},
+	} {
+		tc.expect.Journal = "a/journal"
+		c.Check(persistLag("a/journal", tc.head, tc.fragments), gc.DeepEquals, tc.expect)
+	}
+}
+
+func (s *PersistSuite) TestGetPersistLag(c *gc.C) {
+	var broker = teststub.NewBroker(c)
+	defer broker.Cleanup()
+
+	var ctx = context.Background()
+	var rjc = pb.NewRoutedJournalClient(broker.Client(), pb.NoopDispatchRouter{})
+
+	var journals = buildListResponseFixture("a/journal")
+	journals[0].Spec.Fragment.Stores = []pb.FragmentStore{"s3://bucket/"}
+
+	broker.ListFunc = func(_ context.Context, req *pb.ListRequest) (*pb.ListResponse, error) {
+		return &pb.ListResponse{Header: *buildHeaderFixture(broker), Journals: journals}, nil
+	}
+	broker.ListFragmentsFunc = func(_ context.Context, req *pb.FragmentsRequest) (*pb.FragmentsResponse, error) {
+		c.Check(req.Journal, gc.Equals, pb.Journal("a/journal"))
+
+		return &pb.FragmentsResponse{
+			Header: *buildHeaderFixture(broker),
+			Fragments: []pb.FragmentsResponse__Fragment{
+				{Spec: pb.Fragment{Journal: "a/journal", Begin: 0, End: 1000,
+					CompressionCodec: pb.CompressionCodec_NONE, BackingStore: "s3://bucket/"}},
+				{Spec: pb.Fragment{Journal: "a/journal", Begin: 1000, End: 1024,
+					CompressionCodec: pb.CompressionCodec_NONE}},
+			},
+		}, nil
+	}
+	go serveReadFixtures(c, broker,
+		readFixture{status: pb.Status_OFFSET_NOT_YET_AVAILABLE, offset: 1000},
+		readFixture{status: pb.Status_JOURNAL_NOT_FOUND, offset: 1000},
+	)
+
+	var lag, err = GetPersistLag(ctx, rjc, "a/journal")
+	c.Check(err, gc.IsNil)
+	c.Check(lag, gc.DeepEquals, PersistLag{
+		Journal:          "a/journal",
+		WriteHead:        1024,
+		PersistedThrough: 1000,
+		Bytes:            24,
+		SpooledBytes:     24,
+	})
+	c.Check(testutil.ToFloat64(persistLagBytes.WithLabelValues("a/journal")), gc.Equals, 24.0)
+
+	// Case: the journal is deleted after it's fetched. Its gauges are deleted.
+	_, err = GetPersistLag(ctx, rjc, "a/journal")
+	c.Check(err, gc.ErrorMatches, "fetching write head: "+ErrJournalNotFound.Error())
+	c.Check(testutil.CollectAndCount(persistLagBytes), gc.Equals, 0)
+	c.Check(testutil.CollectAndCount(persistBacklogFragments), gc.Equals, 0)
+
+	// Case: the journal has no fragment stores, and is skipped.
+	persistLagBytes.WithLabelValues("a/journal").Set(24)
+	journals[0].Spec.Fragment.Stores = nil
+
+	_, err = GetPersistLag(ctx, rjc, "a/journal")
+	c.Check(err, gc.Equals, ErrNoFragmentStores)
+	c.Check(testutil.CollectAndCount(persistLagBytes), gc.Equals, 0)
+
+	// Case: the journal doesn't exist.
+	persistLagBytes.WithLabelValues("a/journal").Set(24)
+	journals = nil
+
+	_, err = GetPersistLag(ctx, rjc, "a/journal")
+	c.Check(err, gc.Equals, ErrJournalNotFound)
+	c.Check(testutil.CollectAndCount(persistLagBytes), gc.Equals, 0)
+}
+
+var _ = gc.Suite(&PersistSuite{})
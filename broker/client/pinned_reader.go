@@ -0,0 +1,159 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	pb "go.gazette.dev/core/broker/protocol"
+)
+
+// JournalPin is the set of Fragments of a journal as they existed at an Etcd
+// revision of the broker cluster. A JournalPin may be retained (for example,
+// serialized alongside the results of a processing job) and later read with
+// a PinnedReader to reproduce the very same journal content, regardless of
+// appends or Fragment changes which happen after the pin.
+type JournalPin struct {
+	Journal pb.Journal
+	// Revision is the Etcd revision of the broker's listing of Fragments.
+	Revision int64
+	// Fragments of the journal as of Revision, ordered on Begin offset.
+	Fragments []pb.Fragment
+}
+
+// PinJournal lists the Fragments of the journal, returning a JournalPin of
+// the Fragments and the Etcd revision at which the broker listed them. Note
+// the journal's brokers observe Etcd asynchronously, and the pinned Revision
+// is that of the serving broker rather than of the Etcd cluster.
+func PinJournal(ctx context.Context, client pb.RoutedJournalClient, journal pb.Journal) (JournalPin, error) {
+	var resp, err = ListAllFragments(ctx, client, pb.FragmentsRequest{Journal: journal})
+	if err != nil {
+		return JournalPin{}, fmt.Errorf("listing fragments of %s: %w", journal, err)
+	}
+	var pin = JournalPin{
+		Journal:   journal,
+		Revision:  resp.Header.Etcd.Revision,
+		Fragments: make([]pb.Fragment, len(resp.Fragments)),
+	}
+	for i, f := range resp.Fragments {
+		pin.Fragments[i] = f.Spec
+	}
+	return pin, nil
+}
+
+// End returns the exclusive end offset of the pinned journal content,
+// or zero if the JournalPin has no Fragments.
+func (p JournalPin) End() pb.Offset {
+	var end pb.Offset
+	for _, f := range p.Fragments {
+		if f.End > end {
+			end = f.End
+		}
+	}
+	return end
+}
+
+// covered returns the first offset at or after |offset| which is covered by
+// a pinned Fragment, or End() if there is none.
+func (p JournalPin) covered(offset pb.Offset) pb.Offset {
+	var next = p.End()
+	for _, f := range p.Fragments {
+		if f.Begin <= offset && offset < f.End {
+			return offset
+		} else if f.Begin > offset && f.Begin < next {
+			next = f.Begin
+		}
+	}
+	return next
+}
+
+// ErrPinnedContentRemoved is returned by PinnedReader.Read if content of its
+// JournalPin is no longer available to be read, as happens if its Fragments
+// were removed (such as by Fragment retention) after the pin was taken.
+var ErrPinnedContentRemoved = errors.New("pinned journal content was removed")
+
+// PinnedReader reads the content of a journal as of a JournalPin. Content
+// appended to the journal after the pin's Revision is not read, and reads
+// return io.EOF at the pin's End. Spans of the journal which had no Fragments
+// as of the pin are skipped, just as a Reader would have skipped them at the
+// time of the pin.
+//
+// Journal content is immutable once written, and Fragments which were added
+// after the pin (such as the persisted Fragments of then-spooled content, or
+// Fragments which compact many smaller ones) serve the very same content.
+// However, if pinned content was removed after the pin, a PinnedReader
+// cannot reproduce it and Read returns an error wrapping
+// ErrPinnedContentRemoved, rather than skipping over the missing content.
+// The error is terminal: the PinnedReader is invalidated.
+type PinnedReader struct {
+	Pin JournalPin
+	// RetryReader of the pinned journal content.
+	RetryReader *RetryReader
+
+	err error // Terminal error of the PinnedReader.
+}
+
+// NewPinnedReader returns a PinnedReader of the JournalPin which begins
+// reading at the first pinned offset at or after |offset|.
+func NewPinnedReader(ctx context.Context, client pb.RoutedJournalClient, pin JournalPin, offset pb.Offset) *PinnedReader {
+	var end = pin.End()
+	if offset = pin.covered(offset); offset == end {
+		return &PinnedReader{Pin: pin, err: io.EOF}
+	}
+	return &PinnedReader{
+		Pin: pin,
+		RetryReader: NewRetryReader(ctx, client, pb.ReadRequest{
+			Journal:   pin.Journal,
+			Offset:    offset,
+			EndOffset: end,
+			Block:     true,
+		}),
+	}
+}
+
+// Offset of the next pinned journal byte to be returned by Read.
+func (r *PinnedReader) Offset() pb.Offset {
+	if r.RetryReader == nil {
+		return r.Pin.End()
+	}
+	return r.RetryReader.Offset()
+}
+
+// Read the next bytes of pinned journal content. Read returns io.EOF upon
+// reaching the End of the JournalPin.
+func (r *PinnedReader) Read(p []byte) (int, error) {
+	for {
+		if r.err != nil {
+			return 0, r.err
+		}
+		var from = r.RetryReader.Offset()
+		if from >= r.Pin.End() {
+			r.err = io.EOF
+			continue
+		}
+		var n, err = r.RetryReader.Read(p)
+
+		if err == ErrOffsetJump {
+			// The jump is expected only if no pinned Fragment covers the skipped span.
+			var to = r.RetryReader.Offset()
+			if skip := r.Pin.covered(from); skip < to {
+				r.err = fmt.Errorf("%w: offsets [%d, %d) of %s as of revision %d are no longer available",
+					ErrPinnedContentRemoved, skip, to, r.Pin.Journal, r.Pin.Revision)
+				r.RetryReader.Cancel()
+			}
+			continue
+		} else if err != nil && n == 0 {
+			r.err = err
+		}
+		return n, err
+	}
+}
+
+// Close the PinnedReader, cancelling any ongoing read.
+func (r *PinnedReader) Close() error {
+	if r.RetryReader != nil {
+		r.RetryReader.Cancel()
+	}
+	return nil
+}
@@ -0,0 +1,129 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	pb "go.gazette.dev/core/broker/protocol"
+	"go.gazette.dev/core/broker/teststub"
+	gc "gopkg.in/check.v1"
+)
+
+type PinnedReaderSuite struct{}
+
+func (s *PinnedReaderSuite) TestPinJournal(c *gc.C) {
+	var broker = teststub.NewBroker(c)
+	defer broker.Cleanup()
+
+	var ctx = context.Background()
+	var rjc = pb.NewRoutedJournalClient(broker.Client(), pb.NoopDispatchRouter{})
+	var hdr = buildHeaderFixture(broker)
+
+	var fragments = []pb.Fragment{
+		{Journal: "a/journal", Begin: 0, End: 10, CompressionCodec: pb.CompressionCodec_NONE},
+		{Journal: "a/journal", Begin: 200, End: 203, CompressionCodec: pb.CompressionCodec_NONE},
+	}
+	broker.ListFragmentsFunc = func(_ context.Context, req *pb.FragmentsRequest) (*pb.FragmentsResponse, error) {
+		c.Check(req.Journal, gc.Equals, pb.Journal("a/journal"))
+
+		var resp = &pb.FragmentsResponse{Header: *hdr}
+		for _, f := range fragments {
+			resp.Fragments = append(resp.Fragments, pb.FragmentsResponse__Fragment{Spec: f})
+		}
+		return resp, nil
+	}
+
+	var pin, err = PinJournal(ctx, rjc, "a/journal")
+	c.Check(err, gc.IsNil)
+	c.Check(pin, gc.DeepEquals, JournalPin{Journal: "a/journal", Revision: 56, Fragments: fragments})
+	c.Check(pin.End(), gc.Equals, pb.Offset(203))
+
+	// Case: listing fragments fails.
+	broker.ListFragmentsFunc = func(_ context.Context, req *pb.FragmentsRequest) (*pb.FragmentsResponse, error) {
+		return &pb.FragmentsResponse{Header: *hdr, Status: pb.Status_JOURNAL_NOT_FOUND}, nil
+	}
+	_, err = PinJournal(ctx, rjc, "a/journal")
+	c.Check(err, gc.ErrorMatches, "listing fragments of a/journal: JOURNAL_NOT_FOUND")
+}
+
+func (s *PinnedReaderSuite) TestReadThroughGapsOfPin(c *gc.C) {
+	var broker = teststub.NewBroker(c)
+	defer broker.Cleanup()
+
+	var ctx = context.Background()
+	var rjc = pb.NewRoutedJournalClient(broker.Client(), pb.NoopDispatchRouter{})
+	var pin = pinFixture()
+
+	// Case: the start offset falls within a gap of the pin.
+	var pr = NewPinnedReader(ctx, rjc, pin, 100)
+	c.Check(pr.Offset(), gc.Equals, pb.Offset(200))
+	c.Check(pr.RetryReader.Reader.Request.EndOffset, gc.Equals, pb.Offset(203))
+	c.Check(pr.Close(), gc.IsNil)
+
+	// Case: the start offset is beyond the pin.
+	pr = NewPinnedReader(ctx, rjc, pin, 203)
+	c.Check(pr.Offset(), gc.Equals, pb.Offset(203))
+	var _, err = pr.Read(nil)
+	c.Check(err, gc.Equals, io.EOF)
+
+	go serveReadFixtures(c, broker,
+		readFixture{content: "0123456789"},
+		// Jumps over a gap which existed as of the pin.
+		readFixture{offset: 200, content: "abc"},
+	)
+
+	// Content appended since the pin (offsets 203 and beyond) isn't read.
+	pr = NewPinnedReader(ctx, rjc, pin, 0)
+	c.Check(readAllPinned(c, pr), gc.Equals, "0123456789abc")
+	c.Check(pr.Offset(), gc.Equals, pb.Offset(203))
+}
+
+func (s *PinnedReaderSuite) TestReadOfRemovedContent(c *gc.C) {
+	var broker = teststub.NewBroker(c)
+	defer broker.Cleanup()
+
+	var ctx = context.Background()
+	var rjc = pb.NewRoutedJournalClient(broker.Client(), pb.NoopDispatchRouter{})
+
+	// Fragment [0, 10) was removed by retention after the pin.
+	go serveReadFixtures(c, broker, readFixture{offset: 200})
+
+	var pr = NewPinnedReader(ctx, rjc, pinFixture(), 0)
+	var _, err = pr.Read(nil)
+	c.Check(errors.Is(err, ErrPinnedContentRemoved), gc.Equals, true)
+	c.Check(err, gc.ErrorMatches, "pinned journal content was removed: offsets "+
+		`\[0, 200\) of a/journal as of revision 56 are no longer available`)
+
+	// The error is terminal.
+	_, err = pr.Read(nil)
+	c.Check(errors.Is(err, ErrPinnedContentRemoved), gc.Equals, true)
+}
+
+func pinFixture() JournalPin {
+	return JournalPin{
+		Journal:  "a/journal",
+		Revision: 56,
+		Fragments: []pb.Fragment{
+			{Journal: "a/journal", Begin: 0, End: 10, CompressionCodec: pb.CompressionCodec_NONE},
+			{Journal: "a/journal", Begin: 200, End: 203, CompressionCodec: pb.CompressionCodec_NONE},
+		},
+	}
+}
+
+func readAllPinned(c *gc.C, pr *PinnedReader) string {
+	var out []byte
+	var buf [64]byte
+
+	for {
+		var n, err = pr.Read(buf[:])
+		out = append(out, buf[:n]...)
+
+		if err == io.EOF {
+			return string(out)
+		}
+		c.Assert(err, gc.IsNil)
+	}
+}
+
+var _ = gc.Suite(&PinnedReaderSuite{})
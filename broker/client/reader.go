@@ -8,6 +8,8 @@ import (
 	"io"
 	"io/ioutil"
 	"net/http"
+	"strings"
+	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
 	"go.gazette.dev/core/broker/codecs"
@@ -36,15 +38,57 @@ import (
 // offset to be Read is not the offset that was requested (eg, because a portion of
 // the Journal was deleted), but the Reader is prepared to continue at the updated,
 // strictly larger offset.
+//
+// If PreferZone is set, the Read RPC is dispatched directly to a replica of the
+// journal within that zone (a "follower read"), where one is known from the
+// journal's cached Route. Replicas acknowledge all committed content before it
+// may be read, so a follower serves the same content as the primary, but it may
+// lag the primary in observing the current write head. RetryReader accounts for
+// this by falling back to other replicas, and then to the primary, if a follower
+// cannot serve the requested offset.
+//
+// If Balancer is set (and a follower read isn't applicable), it picks the
+// member of the journal's Route to which the Read RPC is dispatched. A
+// RetryReader continues to read from the member its Balancer picked for as
+// long as that member remains in the journal's Route and serves without error.
+//
+// If StoreRetries is set, a persisted Fragment which can't be directly opened
+// because its store is unavailable is retried up to StoreRetries times, with
+// backoff. If the store remains unavailable, Read returns an error which
+// wraps ErrFragmentStoreUnavailable, and which is distinguishable from a
+// Fragment which doesn't exist in the store (ErrFragmentNotFound). Reads of
+// Fragments which are local to brokers, and not yet persisted, never contact
+// the store and are unaffected by its availability.
+//
+// If Cache is set, directly opened Fragments are read through the
+// FragmentCache, and are fetched from their stores only if not yet cached.
+//
+// If ReadToHead is set, Reader reads only through the journal write head as
+// of the start of the Read RPC, and then returns EOF (rather than blocking,
+// or returning ErrOffsetNotYetAvailable). The head is captured as the
+// Request EndOffset (or lowers an EndOffset which is greater), so that content
+// appended during the read is ignored even if the RPC is restarted.
+// ReadToHead reads are always non-blocking. It's intended for bounded batch
+// reads of live journals.
 type Reader struct {
-	Request  pb.ReadRequest  // ReadRequest of the Reader.
-	Response pb.ReadResponse // Most recent ReadResponse from broker.
+	Request      pb.ReadRequest  // ReadRequest of the Reader.
+	Response     pb.ReadResponse // Most recent ReadResponse from broker.
+	PreferZone   string          // Preferred zone of a follower replica to read from.
+	Balancer     ReadBalancer    // Optional ReadBalancer of independent Read RPCs.
+	StoreRetries int             // Retries of an unavailable Fragment store.
+	Cache        *FragmentCache  // Optional cache of directly-read Fragments.
+	ReadToHead   bool            // Read only through the write head observed at open.
 
 	ctx     context.Context
 	client  pb.RoutedJournalClient // Client against which Read is dispatched.
 	counter prometheus.Counter     // Counter of read bytes.
 	stream  pb.Journal_ReadClient  // Server stream.
 	direct  io.ReadCloser          // Directly opened Fragment URL.
+	cancel  context.CancelFunc     // Cancels |stream|, if ReadToHead.
+
+	follower pb.ProcessSpec_ID   // Follower replica to which the RPC was dispatched, if any.
+	skipped  []pb.ProcessSpec_ID // Replicas which previously failed to serve a follower read.
+	balanced pb.ProcessSpec_ID   // Member picked by the Balancer, if any.
 }
 
 // NewReader returns an initialized Reader of the given ReadRequest.
@@ -83,9 +127,22 @@ func (r *Reader) Read(p []byte) (n int, err error) {
 		return
 	}
 
+	// If ReadToHead, has the captured write head been read through? The broker
+	// may be unaware of the captured EndOffset, so cancel its RPC.
+	if r.ReadToHead && r.Request.EndOffset != 0 && r.Request.Offset >= r.Request.EndOffset {
+		if r.cancel != nil {
+			r.cancel()
+		}
+		return 0, io.EOF
+	}
+
 	// Is there remaining content in the last ReadResponse?
 	if l, d := len(r.Response.Content), int(r.Request.Offset-r.Response.Offset); l != 0 && l > d {
-		n = copy(p, r.Response.Content[d:])
+		var content = r.Response.Content[d:]
+		if remain := r.Request.EndOffset - r.Request.Offset; r.ReadToHead && r.Request.EndOffset != 0 && int64(len(content)) > remain {
+			content = content[:remain]
+		}
+		n = copy(p, content)
 		r.Request.Offset += int64(n)
 		r.counter.Add(float64(n))
 		return
@@ -93,10 +150,12 @@ func (r *Reader) Read(p []byte) (n int, err error) {
 
 	// Lazy initialization: begin the Read RPC.
 	if r.stream == nil {
-		if r.stream, err = r.client.Read(
-			pb.WithDispatchItemRoute(r.ctx, r.client, r.Request.Journal.String(), false),
-			&r.Request,
-		); err == nil {
+		var ctx = r.dispatchContext()
+		if r.ReadToHead {
+			r.Request.Block = false
+			ctx, r.cancel = context.WithCancel(ctx)
+		}
+		if r.stream, err = r.client.Read(ctx, &r.Request); err == nil {
 			n, err = r.Read(p) // Recurse to attempt read against opened |r.stream|.
 		} else {
 			err = mapGRPCCtxErr(r.ctx, err)
@@ -129,6 +188,12 @@ func (r *Reader) Read(p []byte) (n int, err error) {
 		if r.Response.Header != nil {
 			r.client.UpdateRoute(r.Request.Journal.String(), &r.Response.Header.Route)
 		}
+		// If ReadToHead, capture the write head of the first metadata response.
+		// Heads of later responses are never less than the first.
+		if head := r.Response.WriteHead; r.ReadToHead && head != 0 &&
+			(r.Request.EndOffset == 0 || head < r.Request.EndOffset) {
+			r.Request.EndOffset = head
+		}
 
 		if r.Request.Offset < r.Response.Offset {
 			// Offset jumps are uncommon, but possible if fragments were removed,
@@ -164,8 +229,7 @@ func (r *Reader) Read(p []byte) (n int, err error) {
 
 	// If the frame preceding EOF provided a fragment URL, open it directly.
 	if !r.Request.MetadataOnly && r.Response.Status == pb.Status_OK && r.Response.FragmentUrl != "" {
-		if r.direct, err = OpenFragmentURL(r.ctx, *r.Response.Fragment,
-			r.Request.Offset, r.Response.FragmentUrl); err == nil {
+		if r.direct, err = r.openFragment(); err == nil {
 			n, err = r.Read(p) // Recurse to attempt read against opened |r.direct|.
 		}
 		return
@@ -182,13 +246,107 @@ func (r *Reader) Read(p []byte) (n int, err error) {
 	case pb.Status_INSUFFICIENT_JOURNAL_BROKERS:
 		err = ErrInsufficientJournalBrokers
 	case pb.Status_OFFSET_NOT_YET_AVAILABLE:
-		err = ErrOffsetNotYetAvailable
+		if r.ReadToHead {
+			err = io.EOF // Read through the write head.
+		} else {
+			err = ErrOffsetNotYetAvailable
+		}
 	default:
 		err = errors.New(r.Response.Status.String())
 	}
 	return
 }
 
+// openFragment directly opens the Fragment of the current ReadResponse,
+// retrying up to StoreRetries times while its store is unavailable.
+func (r *Reader) openFragment() (*FragmentReader, error) {
+	for attempt := 0; ; attempt++ {
+		var fr *FragmentReader
+		var err error
+
+		if r.Cache != nil {
+			fr, err = r.Cache.Open(r.ctx, *r.Response.Fragment,
+				r.Request.Offset, r.Response.FragmentUrl)
+		} else {
+			fr, err = OpenFragmentURL(r.ctx, *r.Response.Fragment,
+				r.Request.Offset, r.Response.FragmentUrl)
+		}
+
+		if err == nil || attempt >= r.StoreRetries || !errors.Is(err, ErrFragmentStoreUnavailable) {
+			return fr, err
+		}
+		fragmentStoreRetries.WithLabelValues(r.Request.Journal.String()).Inc()
+
+		select {
+		case <-r.ctx.Done():
+			return nil, r.ctx.Err()
+		case <-time.After(backoff(attempt)):
+		}
+	}
+}
+
+// dispatchContext returns a Context for dispatch of the Read RPC. If PreferZone
+// is set and a suitable follower replica is available, the RPC is dispatched to
+// it. If a follower was previously skipped, the RPC is instead dispatched to the
+// primary broker. Otherwise, if a Balancer is set, the RPC is dispatched to a
+// previously balanced member which remains in the Route, or to a newly picked
+// one. Failing these, the dispatcher selects from the journal's Route.
+func (r *Reader) dispatchContext() context.Context {
+	var item = r.Request.Journal.String()
+
+	if r.PreferZone != "" {
+		var rt = r.client.Route(r.ctx, item)
+
+		if id, ok := selectFollower(rt, r.PreferZone, r.skipped); ok {
+			r.follower = id
+			return pb.WithDispatchRoute(r.ctx, rt, id)
+		}
+	}
+	if r.Balancer != nil && len(r.skipped) == 0 {
+		var rt = r.client.Route(r.ctx, item)
+
+		if r.balanced != (pb.ProcessSpec_ID{}) && routeHasEndpoint(rt, r.balanced) {
+			return pb.WithDispatchRoute(r.ctx, rt, r.balanced)
+		} else if id, ok := r.Balancer.PickReadMember(r.Request.Journal, rt); ok {
+			r.balanced = id
+			return pb.WithDispatchRoute(r.ctx, rt, id)
+		}
+		r.balanced = pb.ProcessSpec_ID{}
+	}
+	return pb.WithDispatchItemRoute(r.ctx, r.client, item, len(r.skipped) != 0)
+}
+
+// routeHasEndpoint returns true if |id| is a member of the Route with an Endpoint.
+func routeHasEndpoint(rt pb.Route, id pb.ProcessSpec_ID) bool {
+	for i := range rt.Members {
+		if rt.Members[i] == id && i < len(rt.Endpoints) && rt.Endpoints[i] != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// selectFollower returns the first member of the Route which is in |zone|, has
+// a known Endpoint, and is not in |skipped|.
+func selectFollower(rt pb.Route, zone string, skipped []pb.ProcessSpec_ID) (pb.ProcessSpec_ID, bool) {
+	if len(rt.Endpoints) != len(rt.Members) {
+		return pb.ProcessSpec_ID{}, false // Endpoints are required to dispatch.
+	}
+	for i, id := range rt.Members {
+		if id.Zone != zone || rt.Endpoints[i] == "" {
+			continue
+		}
+		var skip bool
+		for _, s := range skipped {
+			skip = skip || s == id
+		}
+		if !skip {
+			return id, true
+		}
+	}
+	return pb.ProcessSpec_ID{}, false
+}
+
 // AdjustedOffset returns the current journal offset adjusted for content read
 // by the bufio.Reader (which must wrap this Reader), which has not yet been
 // consumed from the bufio.Reader's buffer.
@@ -243,14 +401,30 @@ func OpenFragmentURL(ctx context.Context, fragment pb.Fragment, offset int64, ur
 		// "Content-Encoding: gzip", and it instead directly surfaces the compressed
 		// bytes to us.
 		req.Header.Set("Accept-Encoding", "gzip")
+	} else if fragment.CompressionCodec == pb.CompressionCodec_NONE && offset > fragment.Begin {
+		// Content is uncompressed. Request only the portion we'll read.
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset-fragment.Begin))
 	}
 
 	resp, err := httpClient.Do(req.WithContext(ctx))
 	if err != nil {
-		return nil, err
-	} else if resp.StatusCode != http.StatusOK {
+		if ctx.Err() != nil {
+			return nil, err
+		}
+		fragmentStoreFailures.WithLabelValues(fragment.Journal.String(), "unavailable").Inc()
+		return nil, fragmentStoreError{err: err, kind: ErrFragmentStoreUnavailable}
+	} else if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusPartialContent {
+		// Error bodies are small, and may name a store-specific error code.
+		var body, _ = ioutil.ReadAll(io.LimitReader(resp.Body, 4096))
 		_ = resp.Body.Close()
-		return nil, fmt.Errorf("!OK fetching (%s, %q)", resp.Status, url)
+
+		var reason, kind = fragmentStoreErrorKind(resp.StatusCode, body)
+		fragmentStoreFailures.WithLabelValues(fragment.Journal.String(), reason).Inc()
+
+		return nil, fragmentStoreError{
+			err:  fmt.Errorf("!OK fetching (%s, %q)", resp.Status, url),
+			kind: kind,
+		}
 	}
 
 	// Technically the store _must_ decompress in response to honor our
@@ -269,34 +443,63 @@ func OpenFragmentURL(ctx context.Context, fragment pb.Fragment, offset int64, ur
 		fragmentOpenContentLength.With(labels).Add(float64(resp.ContentLength))
 	}
 
-	return NewFragmentReader(resp.Body, fragment, offset)
+	// If the store honored our Range request, the response body begins at |offset|.
+	var begin = fragment.Begin
+	if resp.StatusCode == http.StatusPartialContent {
+		var expect = fmt.Sprintf("bytes %d-", offset-fragment.Begin)
+
+		if cr := resp.Header.Get("Content-Range"); !strings.HasPrefix(cr, expect) {
+			_ = resp.Body.Close()
+			return nil, fmt.Errorf("unexpected Content-Range %q (expected prefix %q)", cr, expect)
+		}
+		begin = offset
+	}
+	return newFragmentReader(resp.Body, fragment, begin, offset)
 }
 
 // NewFragmentReader wraps a io.ReadCloser of raw Fragment bytes with a
 // returned *FragmentReader which has been pre-seeked to the given offset.
+//
+// Where the Fragment's codec allows, content preceding |offset| is skipped
+// without being decompressed (see codecs.NewCodecReaderOffset). Otherwise
+// the content is decompressed and discarded.
 func NewFragmentReader(rc io.ReadCloser, fragment pb.Fragment, offset int64) (*FragmentReader, error) {
-	var decomp, err = codecs.NewCodecReader(rc, fragment.CompressionCodec)
+	return newFragmentReader(rc, fragment, fragment.Begin, offset)
+}
+
+// newFragmentReader is NewFragmentReader, where |rc| begins at journal offset
+// |begin| of the Fragment rather than at its beginning. |begin| must be
+// Fragment.Begin if the Fragment is compressed.
+func newFragmentReader(rc io.ReadCloser, fragment pb.Fragment, begin, offset int64) (*FragmentReader, error) {
+	var labels = fragmentLabels(fragment)
+	var decomp, skipped, err = codecs.NewCodecReaderOffset(rc, fragment.CompressionCodec, offset-begin)
 	if err != nil {
 		_ = rc.Close()
 		return nil, err
 	}
+	skipFragmentBytes.With(labels).Add(float64(skipped))
 
 	var fr = &FragmentReader{
 		decomp:   decomp,
 		raw:      rc,
 		Fragment: fragment,
-		Offset:   fragment.Begin,
-		counter:  discardFragmentBytes.With(fragmentLabels(fragment)),
+		Offset:   begin + skipped,
+		counter:  discardFragmentBytes.With(labels),
 	}
 
 	// Attempt to seek to |offset| within the fragment.
-	var delta = offset - fragment.Begin
+	var delta = offset - fr.Offset
+	if delta != 0 && !codecs.Skippable(fragment.CompressionCodec) {
+		// The codec doesn't support skipping, and all content preceding
+		// |offset| must be decompressed only to be discarded.
+		fragmentDecompressDiscards.With(labels).Inc()
+	}
 	if _, err = io.CopyN(ioutil.Discard, fr, delta); err != nil {
 		_ = fr.Close()
 		return nil, err
 	}
 	// We've finished discarding required bytes.
-	fr.counter = readFragmentBytes.With(fragmentLabels(fragment))
+	fr.counter = readFragmentBytes.With(labels)
 
 	return fr, nil
 }
@@ -382,6 +585,33 @@ func mapGRPCCtxErr(ctx context.Context, err error) error {
 	return err
 }
 
+// fragmentStoreError is an error encountered in fetching a Fragment from its
+// store, which wraps a sentinel error describing its kind (if known).
+type fragmentStoreError struct {
+	err  error
+	kind error
+}
+
+func (e fragmentStoreError) Error() string { return e.err.Error() }
+func (e fragmentStoreError) Unwrap() error { return e.kind }
+
+// fragmentStoreErrorKind maps an HTTP status code and error body of a Fragment
+// store into a metrics reason and kind of fragmentStoreError.
+func fragmentStoreErrorKind(code int, body []byte) (string, error) {
+	switch {
+	// S3 (403) and Azure (409) report error codes of archived objects.
+	case code == http.StatusForbidden && strings.Contains(string(body), "<Code>InvalidObjectState</Code>"),
+		code == http.StatusConflict && strings.Contains(string(body), "<Code>BlobArchived</Code>"):
+		return "needs_restore", ErrFragmentNeedsRestore
+	case code == http.StatusNotFound, code == http.StatusGone:
+		return "not_found", ErrFragmentNotFound
+	case code == http.StatusRequestTimeout, code == http.StatusTooManyRequests, code >= 500:
+		return "unavailable", ErrFragmentStoreUnavailable
+	default:
+		return "other", nil
+	}
+}
+
 func fragmentLabels(fragment pb.Fragment) prometheus.Labels {
 	return prometheus.Labels{
 		"journal": fragment.Journal.String(),
@@ -410,6 +640,22 @@ var (
 	// ErrDidNotReadExpectedEOF is returned by FragmentReader.Read if the
 	// underlying file did not return EOF at the expected Fragment End offset.
 	ErrDidNotReadExpectedEOF = errors.New("did not read EOF at expected Fragment.End")
+	// ErrFragmentStoreUnavailable is wrapped by errors of OpenFragmentURL
+	// (and Reader.Read) which reflect a transient unavailability of the
+	// Fragment store, such as a failure to connect or a 503 status. The
+	// operation may succeed if retried.
+	ErrFragmentStoreUnavailable = errors.New("fragment store unavailable")
+	// ErrFragmentNotFound is wrapped by errors of OpenFragmentURL (and
+	// Reader.Read) where the Fragment store reports that the Fragment
+	// doesn't exist. This is a permanent condition.
+	ErrFragmentNotFound = errors.New("fragment not found in store")
+	// ErrFragmentNeedsRestore is wrapped by errors of OpenFragmentURL (and
+	// Reader.Read) where the Fragment store reports that the Fragment has
+	// transitioned to an archival storage class, such as S3 Glacier or the
+	// Azure Archive tier. The Fragment must be restored before it's read.
+	// It's the same error as protocol.ErrFragmentNeedsRestore, which is
+	// wrapped by errors of broker fragment store Opens.
+	ErrFragmentNeedsRestore = pb.ErrFragmentNeedsRestore
 
 	// httpClient is the http.Client used by OpenFragmentURL
 	httpClient = http.DefaultClient
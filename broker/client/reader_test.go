@@ -2,15 +2,21 @@ package client
 
 import (
 	"bufio"
+	"bytes"
 	"context"
 	"errors"
 	"io"
 	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"go.gazette.dev/core/broker/codecs"
 	pb "go.gazette.dev/core/broker/protocol"
 	"go.gazette.dev/core/broker/teststub"
@@ -70,6 +76,7 @@ func (s *ReaderSuite) TestOpenFragmentURLCases(c *gc.C) {
 	// Case: doesn't exist.
 	rc, err = OpenFragmentURL(ctx, frag, frag.Begin, url+"does-not-exist")
 	c.Check(err, gc.ErrorMatches, `!OK fetching \(404 Not Found, "file:///.*\)`)
+	c.Check(errors.Is(err, ErrFragmentNotFound), gc.Equals, true)
 
 	// Case: decompression fails.
 	frag.CompressionCodec = pb.CompressionCodec_SNAPPY
@@ -77,6 +84,102 @@ func (s *ReaderSuite) TestOpenFragmentURLCases(c *gc.C) {
 	c.Check(err, gc.ErrorMatches, `snappy: corrupt input`)
 }
 
+func (s *ReaderSuite) TestFragmentReaderSkipsContent(c *gc.C) {
+	// Build content spanning several snappy chunks, which are 64KB each.
+	var data = make([]byte, 200000)
+	for i := range data {
+		data[i] = byte(i % 251)
+	}
+	var build = func(codec pb.CompressionCodec) (pb.Fragment, []byte) {
+		var buf bytes.Buffer
+		var comp, err = codecs.NewCodecWriter(&buf, codec)
+		c.Assert(err, gc.IsNil)
+		_, err = comp.Write(data)
+		c.Assert(err, gc.IsNil)
+		c.Assert(comp.Close(), gc.IsNil)
+
+		return pb.Fragment{
+			Journal:          "skip/journal",
+			Begin:            1000,
+			End:              1000 + int64(len(data)),
+			CompressionCodec: codec,
+		}, buf.Bytes()
+	}
+	var readAt = func(frag pb.Fragment, raw []byte, offset int64) (skipped, discarded, discards float64) {
+		var labels = fragmentLabels(frag)
+		var s0 = testutil.ToFloat64(skipFragmentBytes.With(labels))
+		var d0 = testutil.ToFloat64(discardFragmentBytes.With(labels))
+		var n0 = testutil.ToFloat64(fragmentDecompressDiscards.With(labels))
+
+		var fr, err = NewFragmentReader(ioutil.NopCloser(bytes.NewReader(raw)), frag, offset)
+		c.Assert(err, gc.IsNil)
+		c.Check(fr.Offset, gc.Equals, offset)
+
+		b, err := ioutil.ReadAll(fr)
+		c.Check(err, gc.IsNil)
+		c.Check(b, gc.DeepEquals, data[offset-frag.Begin:])
+		c.Check(fr.Close(), gc.IsNil)
+
+		return testutil.ToFloat64(skipFragmentBytes.With(labels)) - s0,
+			testutil.ToFloat64(discardFragmentBytes.With(labels)) - d0,
+			testutil.ToFloat64(fragmentDecompressDiscards.With(labels)) - n0
+	}
+
+	// Case: SNAPPY skips whole chunks preceding the offset, and decompresses
+	// only the chunk which contains it.
+	var frag, raw = build(pb.CompressionCodec_SNAPPY)
+	var skipped, discarded, discards = readAt(frag, raw, frag.Begin+150000)
+	c.Check(skipped, gc.Equals, float64(2*65536))
+	c.Check(discarded, gc.Equals, float64(150000-2*65536))
+	c.Check(discards, gc.Equals, float64(0))
+
+	// Offsets within the first chunk, or at its boundary.
+	_, discarded, _ = readAt(frag, raw, frag.Begin+10)
+	c.Check(discarded, gc.Equals, float64(10))
+	skipped, discarded, _ = readAt(frag, raw, frag.Begin+65536)
+	c.Check(skipped, gc.Equals, float64(65536))
+	c.Check(discarded, gc.Equals, float64(0))
+
+	// Case: GZIP cannot skip, and falls back to decompressing all content.
+	frag, raw = build(pb.CompressionCodec_GZIP)
+	skipped, discarded, discards = readAt(frag, raw, frag.Begin+150000)
+	c.Check(skipped, gc.Equals, float64(0))
+	c.Check(discarded, gc.Equals, float64(150000))
+	c.Check(discards, gc.Equals, float64(1))
+
+	// Reads from the beginning of the fragment don't count as a fallback.
+	_, _, discards = readAt(frag, raw, frag.Begin)
+	c.Check(discards, gc.Equals, float64(0))
+}
+
+func (s *ReaderSuite) TestOpenFragmentURLRequestsRange(c *gc.C) {
+	var frag, url, dir, cleanup = buildFragmentFixture(c)
+	defer cleanup()
+	defer InstallFileTransport(dir)()
+
+	// Re-write the fixture as uncompressed.
+	frag.CompressionCodec = pb.CompressionCodec_NONE
+	url = string(frag.BackingStore) + frag.ContentName()
+	c.Assert(ioutil.WriteFile(filepath.Join(dir, frag.ContentName()),
+		[]byte("XXXXXhello, world!!!"), 0600), gc.IsNil)
+
+	var labels = fragmentLabels(frag)
+	var d0 = testutil.ToFloat64(discardFragmentBytes.With(labels))
+
+	// Expect the store is asked for only the content beginning at the offset.
+	var rc, err = OpenFragmentURL(context.Background(), frag, frag.Begin+5, url)
+	c.Assert(err, gc.IsNil)
+	c.Check(rc.Offset, gc.Equals, frag.Begin+5)
+
+	b, err := ioutil.ReadAll(rc)
+	c.Check(err, gc.IsNil)
+	c.Check(string(b), gc.Equals, "hello, world!!!")
+	c.Check(rc.Offset, gc.Equals, rc.Fragment.End)
+	c.Check(rc.Close(), gc.IsNil)
+
+	c.Check(testutil.ToFloat64(discardFragmentBytes.With(labels))-d0, gc.Equals, float64(0))
+}
+
 func (s *ReaderSuite) TestReaderCases(c *gc.C) {
 	var frag, url, dir, cleanup = buildFragmentFixture(c)
 	defer cleanup()
@@ -255,6 +358,205 @@ func (s *ReaderSuite) TestReaderCases(c *gc.C) {
 	c.Check(err, gc.IsNil)
 }
 
+func (s *ReaderSuite) TestReadToHead(c *gc.C) {
+	var broker = teststub.NewBroker(c)
+	defer broker.Cleanup()
+
+	var ctx = context.Background()
+	var rjc = pb.NewRoutedJournalClient(broker.Client(), pb.NoopDispatchRouter{})
+	var frag = &pb.Fragment{Journal: "a/journal", Begin: 100, End: 110, CompressionCodec: pb.CompressionCodec_NONE}
+
+	// Case: content appended after the read begins is ignored.
+	go func() {
+		var req = <-broker.ReadReqCh
+		c.Check(req.Block, gc.Equals, false)
+		c.Check(req.EndOffset, gc.Equals, int64(0))
+
+		broker.ReadRespCh <- pb.ReadResponse{
+			Status:    pb.Status_OK,
+			Header:    buildHeaderFixture(broker),
+			Offset:    100,
+			WriteHead: 110,
+			Fragment:  frag,
+		}
+		broker.ReadRespCh <- pb.ReadResponse{Offset: 100, Content: []byte("0123456")}
+		broker.ReadRespCh <- pb.ReadResponse{Offset: 107, Content: []byte("789appended")}
+		broker.WriteLoopErrCh <- nil
+	}()
+
+	var r = NewReader(ctx, rjc, pb.ReadRequest{Journal: "a/journal", Offset: 100, Block: true})
+	r.ReadToHead = true
+
+	var b, err = ioutil.ReadAll(r)
+	c.Check(err, gc.IsNil)
+	c.Check(string(b), gc.Equals, "0123456789")
+	c.Check(r.Request.EndOffset, gc.Equals, int64(110))
+
+	// Case: a read at the write head returns EOF.
+	go func() {
+		<-broker.ReadReqCh
+		broker.ReadRespCh <- pb.ReadResponse{
+			Status:    pb.Status_OFFSET_NOT_YET_AVAILABLE,
+			Header:    buildHeaderFixture(broker),
+			Offset:    110,
+			WriteHead: 110,
+		}
+		broker.WriteLoopErrCh <- nil
+	}()
+
+	r = NewReader(ctx, rjc, pb.ReadRequest{Journal: "a/journal", Offset: 110})
+	r.ReadToHead = true
+
+	b, err = ioutil.ReadAll(r)
+	c.Check(err, gc.IsNil)
+	c.Check(b, gc.HasLen, 0)
+
+	// Case: the broker doesn't report a write head, and no EndOffset is captured.
+	go func() {
+		<-broker.ReadReqCh
+		broker.ReadRespCh <- pb.ReadResponse{
+			Status: pb.Status_OK,
+			Header: buildHeaderFixture(broker),
+		}
+		broker.ReadRespCh <- pb.ReadResponse{Offset: 0, Content: []byte("0123456789")}
+		broker.WriteLoopErrCh <- nil
+	}()
+
+	r = NewReader(ctx, rjc, pb.ReadRequest{Journal: "a/journal", Offset: 0})
+	r.ReadToHead = true
+
+	b, err = ioutil.ReadAll(r)
+	c.Check(err, gc.IsNil)
+	c.Check(string(b), gc.Equals, "0123456789")
+	c.Check(r.Request.EndOffset, gc.Equals, int64(0))
+
+	// Case: a RetryReader which is restarted before reaching the head
+	// continues to read only through the head captured at open.
+	go func() {
+		<-broker.ReadReqCh
+		broker.ReadRespCh <- pb.ReadResponse{
+			Status:    pb.Status_OK,
+			Header:    buildHeaderFixture(broker),
+			Offset:    100,
+			WriteHead: 110,
+			Fragment:  frag,
+		}
+		broker.ReadRespCh <- pb.ReadResponse{Offset: 100, Content: []byte("01234")}
+		broker.WriteLoopErrCh <- nil // Broker closes the RPC.
+
+		var req = <-broker.ReadReqCh
+		c.Check(req.Offset, gc.Equals, int64(105))
+		c.Check(req.EndOffset, gc.Equals, int64(110))
+		c.Check(req.Block, gc.Equals, false)
+
+		broker.ReadRespCh <- pb.ReadResponse{
+			Status:    pb.Status_OK,
+			Header:    buildHeaderFixture(broker),
+			Offset:    105,
+			WriteHead: 120,
+			Fragment:  &pb.Fragment{Journal: "a/journal", Begin: 100, End: 120, CompressionCodec: pb.CompressionCodec_NONE},
+		}
+		broker.ReadRespCh <- pb.ReadResponse{Offset: 105, Content: []byte("56789")}
+		broker.WriteLoopErrCh <- nil
+	}()
+
+	var rr = NewRetryReader(ctx, rjc, pb.ReadRequest{Journal: "a/journal", Offset: 100})
+	rr.Reader.ReadToHead = true
+
+	b, err = ioutil.ReadAll(rr)
+	c.Check(err, gc.IsNil)
+	c.Check(string(b), gc.Equals, "0123456789")
+	c.Check(rr.Offset(), gc.Equals, int64(110))
+}
+
+func (s *ReaderSuite) TestReaderRetriesUnavailableStore(c *gc.C) {
+	var frag, _, dir, cleanup = buildFragmentFixture(c)
+	defer cleanup()
+
+	// Store fixture which fails with 503 while |unavailable| is positive.
+	var unavailable, requests int32
+	var store = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		if atomic.AddInt32(&unavailable, -1) >= 0 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		} else if strings.HasSuffix(r.URL.Path, "-archived") {
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`<Error><Code>InvalidObjectState</Code></Error>`))
+			return
+		}
+		http.ServeFile(w, r, filepath.Join(dir, r.URL.Path))
+	}))
+	defer store.Close()
+
+	var url = store.URL + "/" + frag.ContentName()
+
+	var broker = teststub.NewBroker(c)
+	defer broker.Cleanup()
+
+	var ctx = context.Background()
+	var rjc = pb.NewRoutedJournalClient(broker.Client(), pb.NoopDispatchRouter{})
+	var retries = fragmentStoreRetries.WithLabelValues("a/journal")
+	var retriesBefore = testutil.ToFloat64(retries)
+
+	go serveReadFixtures(c, broker,
+		readFixture{fragment: &frag, fragmentUrl: url},
+		readFixture{fragment: &frag, fragmentUrl: url},
+		readFixture{fragment: &frag, fragmentUrl: url + "-not-found"},
+		readFixture{fragment: &frag, fragmentUrl: url + "-archived"},
+		readFixture{content: "local content", offset: 120},
+	)
+
+	// Case: the store is unavailable for two attempts, and then recovers.
+	atomic.StoreInt32(&unavailable, 2)
+	var r = NewReader(ctx, rjc, pb.ReadRequest{Journal: "a/journal", Offset: 105})
+	r.StoreRetries = 2
+
+	var b, err = ioutil.ReadAll(r)
+	c.Check(string(b), gc.Equals, "hello, world!!!")
+	c.Check(err, gc.IsNil)
+	c.Check(testutil.ToFloat64(retries)-retriesBefore, gc.Equals, 2.0)
+
+	// Case: the store remains unavailable after retries.
+	atomic.StoreInt32(&unavailable, 2)
+	r = NewReader(ctx, rjc, pb.ReadRequest{Journal: "a/journal", Offset: 105})
+	r.StoreRetries = 1
+
+	_, err = ioutil.ReadAll(r)
+	c.Check(err, gc.ErrorMatches, `!OK fetching \(503 Service Unavailable, ".*"\)`)
+	c.Check(errors.Is(err, ErrFragmentStoreUnavailable), gc.Equals, true)
+	c.Check(testutil.ToFloat64(retries)-retriesBefore, gc.Equals, 3.0)
+
+	// Case: the fragment doesn't exist. This is permanent, and isn't retried.
+	atomic.StoreInt32(&requests, 0)
+	r = NewReader(ctx, rjc, pb.ReadRequest{Journal: "a/journal", Offset: 105})
+	r.StoreRetries = 2
+
+	_, err = ioutil.ReadAll(r)
+	c.Check(err, gc.ErrorMatches, `!OK fetching \(404 Not Found, ".*"\)`)
+	c.Check(errors.Is(err, ErrFragmentNotFound), gc.Equals, true)
+	c.Check(errors.Is(err, ErrFragmentStoreUnavailable), gc.Equals, false)
+	c.Check(atomic.LoadInt32(&requests), gc.Equals, int32(1))
+
+	// Case: the fragment is archived, and must be restored. It isn't retried.
+	atomic.StoreInt32(&requests, 0)
+	r = NewReader(ctx, rjc, pb.ReadRequest{Journal: "a/journal", Offset: 105})
+	r.StoreRetries = 2
+
+	_, err = ioutil.ReadAll(r)
+	c.Check(err, gc.ErrorMatches, `!OK fetching \(403 Forbidden, ".*"\)`)
+	c.Check(errors.Is(err, ErrFragmentNeedsRestore), gc.Equals, true)
+	c.Check(atomic.LoadInt32(&requests), gc.Equals, int32(1))
+
+	// Case: a broker-local fragment is read while the store is unavailable.
+	atomic.StoreInt32(&unavailable, 100)
+	r = NewReader(ctx, rjc, pb.ReadRequest{Journal: "a/journal", Offset: 120})
+
+	b, err = ioutil.ReadAll(r)
+	c.Check(string(b), gc.Equals, "local content")
+	c.Check(err, gc.IsNil)
+}
+
 func (s *ReaderSuite) TestBufferedOffsetAdjustment(c *gc.C) {
 	var broker = teststub.NewBroker(c)
 	defer broker.Cleanup()
@@ -7,7 +7,6 @@ import (
 	"io"
 	"time"
 
-	log "github.com/sirupsen/logrus"
 	pb "go.gazette.dev/core/broker/protocol"
 )
 
@@ -58,7 +57,8 @@ func (rr *RetryReader) Offset() int64 {
 //    for a non-blocking ReadRequest.
 //  * An offset jump occurred (ErrOffsetJump), in which case the client
 //    should inspect the new Offset and may continue reading if desired.
-//  * The broker returns io.EOF upon reaching the requested EndOffset.
+//  * The broker returns io.EOF upon reaching the requested EndOffset
+//    (or the write head, if the Reader is ReadToHead).
 // All other errors are retried.
 func (rr *RetryReader) Read(p []byte) (n int, err error) {
 	for attempt := 0; true; attempt++ {
@@ -68,6 +68,9 @@ func (rr *RetryReader) Read(p []byte) (n int, err error) {
 		} else if err == io.EOF && rr.Reader.Request.EndOffset != 0 &&
 			rr.Reader.Request.Offset >= rr.Reader.Request.EndOffset {
 			return // Success (read through requested EndOffset).
+		} else if err == io.EOF && rr.Reader.ReadToHead &&
+			rr.Reader.Response.Status == pb.Status_OFFSET_NOT_YET_AVAILABLE {
+			return // Success (read through the write head).
 		} else if err == ErrOffsetJump {
 			return // Note |rr.Reader| is not invalidated by this error.
 		}
@@ -79,12 +82,21 @@ func (rr *RetryReader) Read(p []byte) (n int, err error) {
 		// Restart the Reader, carrying forward fields which continue to apply.
 		// Note we're re-using the same context (and we could be racing
 		// this restart with a concurrent call to |rr.Cancel|).
-		rr.Reader = &Reader{
-			Request:  rr.Reader.Request,
-			Response: rr.Reader.Response,
-			ctx:      rr.Reader.ctx,
-			client:   rr.Reader.client,
-			counter:  rr.Reader.counter,
+		var prev = rr.Reader
+		var follower = prev.follower
+
+		rr.Reader = renewReader(prev.ctx, prev.client, prev.Request, prev)
+		rr.Reader.Response = prev.Response
+		rr.Reader.skipped = prev.skipped
+
+		// If this Reader was a follower read, skip the follower on the next attempt.
+		if follower != (pb.ProcessSpec_ID{}) {
+			rr.Reader.skipped = append(rr.Reader.skipped, follower)
+		}
+		// Continue to read from a balanced member only if it closed the RPC
+		// gracefully. Otherwise, we'll pick another.
+		if err != io.EOF {
+			rr.Reader.balanced = pb.ProcessSpec_ID{}
 		}
 
 		var squelch bool
@@ -93,7 +105,12 @@ func (rr *RetryReader) Read(p []byte) (n int, err error) {
 		case context.DeadlineExceeded, context.Canceled:
 			return // Surface to caller.
 		case ErrOffsetNotYetAvailable:
-			if rr.Reader.Request.Block {
+			if follower != (pb.ProcessSpec_ID{}) && n == 0 {
+				// The follower may lag the primary in observing the write head.
+				// Immediately retry against another replica or the primary,
+				// which determines whether the offset is in fact not yet available.
+				continue
+			} else if rr.Reader.Request.Block {
 				// |Block| was set after a non-blocking reader was started. Restart in blocking mode.
 				squelch = true
 			} else {
@@ -104,15 +121,21 @@ func (rr *RetryReader) Read(p []byte) (n int, err error) {
 			// We may be racing a concurrent Etcd watch and assignment of the broker cluster.
 			squelch = attempt == 0
 		default:
+			if errors.Is(err, ErrFragmentNotFound) && attempt == 0 && n == 0 {
+				// The Fragment was removed from the store of its listing, as happens
+				// when a journal's fragment stores are migrated. Immediately retry,
+				// and the broker resolves the offset from its refreshed index.
+				continue
+			}
 		}
 
 		if !squelch {
-			log.WithFields(log.Fields{
+			loggerFrom(rr.Context).Log(LogWarn, "read failure (will retry)", LogFields{
 				"journal": rr.Journal(),
 				"offset":  rr.Offset(),
 				"err":     err,
 				"attempt": attempt,
-			}).Warn("read failure (will retry)")
+			})
 		}
 
 		if n != 0 {
@@ -147,8 +170,8 @@ func (rr *RetryReader) Seek(offset int64, whence int) (int64, error) {
 
 	if _, err := rr.Reader.Seek(offset, io.SeekStart); err != nil {
 		if err != ErrSeekRequiresNewReader {
-			log.WithFields(log.Fields{"journal": rr.Journal(), "offset": offset, "err": err}).
-				Warn("failed to seek open Reader (will retry)")
+			loggerFrom(rr.Context).Log(LogWarn, "failed to seek open Reader (will retry)",
+				LogFields{"journal": rr.Journal(), "offset": offset, "err": err})
 		}
 
 		var req = rr.Reader.Request
@@ -193,13 +216,32 @@ func (rr *RetryReader) AdjustedSeek(offset int64, whence int, br *bufio.Reader)
 
 // Restart the RetryReader with a new ReadRequest.
 // Restart without a prior Cancel will leak resources.
+// The PreferZone, Balancer, StoreRetries, Cache, and ReadToHead of a current Reader
+// are carried forward to the new Reader, as is a member picked by the Balancer.
 func (rr *RetryReader) Restart(req pb.ReadRequest) {
 	var ctx, cancel = context.WithCancel(rr.Context)
 
-	rr.Reader = NewReader(ctx, rr.Client, req)
+	rr.Reader = renewReader(ctx, rr.Client, req, rr.Reader)
 	rr.Cancel = cancel
 }
 
+// renewReader returns a new Reader of |req|, carrying forward the PreferZone,
+// Balancer, StoreRetries, Cache, ReadToHead, and balanced member of |prev|
+// (if non-nil).
+func renewReader(ctx context.Context, client pb.RoutedJournalClient, req pb.ReadRequest, prev *Reader) *Reader {
+	var r = NewReader(ctx, client, req)
+
+	if prev != nil {
+		r.PreferZone = prev.PreferZone
+		r.Balancer = prev.Balancer
+		r.StoreRetries = prev.StoreRetries
+		r.Cache = prev.Cache
+		r.ReadToHead = prev.ReadToHead
+		r.balanced = prev.balanced
+	}
+	return r
+}
+
 func backoff(attempt int) time.Duration {
 	// The choices of backoff time reflect that we're usually waiting for the
 	// cluster to converge on a shared understanding of ownership, and that
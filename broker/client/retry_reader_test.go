@@ -8,6 +8,7 @@ import (
 	"io/ioutil"
 	"strings"
 	"testing/iotest"
+	"time"
 
 	pb "go.gazette.dev/core/broker/protocol"
 	"go.gazette.dev/core/broker/teststub"
@@ -84,6 +85,130 @@ func (s *RetrySuite) TestReaderRetries(c *gc.C) {
 	c.Check(rr.Offset(), gc.Equals, int64(110))
 }
 
+func (s *RetrySuite) TestFollowerReadFallback(c *gc.C) {
+	var primary, follower = teststub.NewBroker(c), teststub.NewBroker(c)
+	defer primary.Cleanup()
+	defer follower.Cleanup()
+
+	var router = fixedRouter{route: pb.Route{
+		Members: []pb.ProcessSpec_ID{
+			{Zone: "a", Suffix: "primary"},
+			{Zone: "b", Suffix: "follower"},
+		},
+		Endpoints: []pb.Endpoint{primary.Endpoint(), follower.Endpoint()},
+		Primary:   0,
+	}}
+	var rjc = pb.NewRoutedJournalClient(primary.Client(), router)
+
+	var rr = NewRetryReader(context.Background(), rjc,
+		pb.ReadRequest{Journal: "a/journal", Offset: 100})
+	rr.Reader.PreferZone = "b"
+
+	go func() {
+		// The follower serves content, but lags in observing the write head.
+		serveReadFixtures(c, follower,
+			readFixture{content: "foo", status: pb.Status_OFFSET_NOT_YET_AVAILABLE})
+		// We fall back to the primary, which serves further content.
+		serveReadFixtures(c, primary,
+			readFixture{content: "bar", status: pb.Status_OFFSET_NOT_YET_AVAILABLE})
+	}()
+
+	// Expect the follower's OFFSET_NOT_YET_AVAILABLE is masked,
+	// while that of the primary is surfaced.
+	var b, err = ioutil.ReadAll(rr)
+	c.Check(string(b), gc.Equals, "foobar")
+	c.Check(err, gc.Equals, ErrOffsetNotYetAvailable)
+	c.Check(rr.Reader.skipped, gc.DeepEquals, []pb.ProcessSpec_ID{{Zone: "b", Suffix: "follower"}})
+
+	// A Restart carries forward PreferZone, and clears skipped followers.
+	rr.Cancel()
+	rr.Restart(pb.ReadRequest{Journal: "a/journal", Offset: 100, EndOffset: 103})
+	c.Check(rr.Reader.PreferZone, gc.Equals, "b")
+	c.Check(rr.Reader.skipped, gc.IsNil)
+
+	go serveReadFixtures(c, follower, readFixture{content: "foo"})
+
+	b, err = ioutil.ReadAll(rr)
+	c.Check(string(b), gc.Equals, "foo")
+	c.Check(err, gc.IsNil)
+}
+
+func (s *RetrySuite) TestBalancedReadsAreSticky(c *gc.C) {
+	var one, two = teststub.NewBroker(c), teststub.NewBroker(c)
+	defer one.Cleanup()
+	defer two.Cleanup()
+
+	var router = fixedRouter{route: pb.Route{
+		Members: []pb.ProcessSpec_ID{
+			{Zone: "a", Suffix: "one"},
+			{Zone: "a", Suffix: "two"},
+		},
+		Endpoints: []pb.Endpoint{one.Endpoint(), two.Endpoint()},
+		Primary:   0,
+	}}
+	var rjc = pb.NewRoutedJournalClient(one.Client(), router)
+
+	var balancer = NewRouteCache(1, time.Minute)
+	balancer.SetReadWeights(func(pb.ProcessSpec_ID) int { return 1 })
+
+	var newReader = func() *RetryReader {
+		var rr = NewRetryReader(context.Background(), rjc,
+			pb.ReadRequest{Journal: "a/journal", Offset: 100, EndOffset: 106})
+		rr.Reader.Balancer = balancer
+		return rr
+	}
+
+	// Case: a RetryReader continues to read from its picked member,
+	// across RPCs which it restarts after a graceful close.
+	go serveReadFixtures(c, one, readFixture{content: "foo"}, readFixture{content: "bar"})
+
+	var b, err = ioutil.ReadAll(newReader())
+	c.Check(string(b), gc.Equals, "foobar")
+	c.Check(err, gc.IsNil)
+
+	// Case: an independent RetryReader is balanced to another member.
+	// If the member fails, another is picked.
+	go func() {
+		serveReadFixtures(c, two, readFixture{content: "foo", err: errors.New("whoops")})
+		serveReadFixtures(c, one, readFixture{content: "bar"})
+	}()
+
+	b, err = ioutil.ReadAll(newReader())
+	c.Check(string(b), gc.Equals, "foobar")
+	c.Check(err, gc.IsNil)
+}
+
+func (s *RetrySuite) TestFollowerSelection(c *gc.C) {
+	var rt = pb.Route{
+		Members: []pb.ProcessSpec_ID{
+			{Zone: "a", Suffix: "one"},
+			{Zone: "b", Suffix: "two"},
+			{Zone: "b", Suffix: "three"},
+		},
+		Endpoints: []pb.Endpoint{"http://one", "", "http://three"},
+		Primary:   0,
+	}
+	var id, ok = selectFollower(rt, "a", nil)
+	c.Check(id, gc.Equals, pb.ProcessSpec_ID{Zone: "a", Suffix: "one"})
+	c.Check(ok, gc.Equals, true)
+
+	// Members without an Endpoint are not selected.
+	id, ok = selectFollower(rt, "b", nil)
+	c.Check(id, gc.Equals, pb.ProcessSpec_ID{Zone: "b", Suffix: "three"})
+	c.Check(ok, gc.Equals, true)
+
+	// Skipped members are not selected.
+	_, ok = selectFollower(rt, "b", []pb.ProcessSpec_ID{{Zone: "b", Suffix: "three"}})
+	c.Check(ok, gc.Equals, false)
+	_, ok = selectFollower(rt, "c", nil)
+	c.Check(ok, gc.Equals, false)
+
+	// Routes without Endpoints cannot be dispatched to.
+	rt.Endpoints = nil
+	_, ok = selectFollower(rt, "a", nil)
+	c.Check(ok, gc.Equals, false)
+}
+
 func (s *RetrySuite) TestMisbehavingReaderCases(c *gc.C) {
 	var broker = teststub.NewBroker(c)
 	defer broker.Cleanup()
@@ -119,6 +244,42 @@ func (s *RetrySuite) TestMisbehavingReaderCases(c *gc.C) {
 	}
 }
 
+func (s *RetrySuite) TestFragmentNotFoundIsRetried(c *gc.C) {
+	var frag, url, dir, cleanup = buildFragmentFixture(c)
+	defer cleanup()
+	defer InstallFileTransport(dir)()
+
+	var broker = teststub.NewBroker(c)
+	defer broker.Cleanup()
+
+	var rjc = pb.NewRoutedJournalClient(broker.Client(), pb.NoopDispatchRouter{})
+
+	// The first fixture returns a URL of the Fragment within a store which no
+	// longer holds it (eg, because the journal's stores were migrated). The
+	// second returns its URL within the current store.
+	go serveReadFixtures(c, broker,
+		readFixture{fragment: &frag, fragmentUrl: url + "-moved", offset: frag.Begin + 5},
+		readFixture{fragment: &frag, fragmentUrl: url},
+	)
+
+	var rr = NewRetryReader(context.Background(), rjc, pb.ReadRequest{Journal: "a/journal", Offset: frag.Begin + 5})
+
+	var _, err = rr.Read(nil) // Read initial response message.
+	c.Check(err, gc.IsNil)
+
+	// Expect the failure to open the moved Fragment is retried at the same
+	// offset, and the Fragment is read from its current location.
+	var b = make([]byte, 5)
+	n, err := rr.Read(b[:]) // Reads response message of the restarted RPC.
+	c.Check(n, gc.Equals, 0)
+	c.Check(err, gc.IsNil)
+
+	n, err = rr.Read(b[:]) // Opens fragment URL.
+	c.Check(err, gc.IsNil)
+	c.Check(string(b[:n]), gc.Equals, "hello")
+	c.Check(rr.Offset(), gc.Equals, frag.Begin+10)
+}
+
 func (s *RetrySuite) TestSeeking(c *gc.C) {
 	var frag, url, dir, cleanup = buildFragmentFixture(c)
 	defer cleanup()
@@ -219,4 +380,11 @@ func (s *RetrySuite) TestBufferedSeekAdjustment(c *gc.C) {
 	<-readerCtx.Done()                          // Previous reader context was canceled.
 }
 
+// fixedRouter is a DispatchRouter which always returns a fixed Route.
+type fixedRouter struct{ route pb.Route }
+
+func (r fixedRouter) Route(context.Context, string) pb.Route { return r.route }
+func (r fixedRouter) UpdateRoute(string, *pb.Route)          {}
+func (r fixedRouter) IsNoopRouter() bool                     { return false }
+
 var _ = gc.Suite(&RetrySuite{})
@@ -2,6 +2,7 @@ package client
 
 import (
 	"context"
+	"sync"
 	"time"
 
 	"github.com/hashicorp/golang-lru"
@@ -23,9 +24,15 @@ import (
 //     var jc protocol.JournalClient
 //     var rjc = protocol.NewRoutedJournalClient(jc, NewRouteCache(256, time.Hour))
 //
+// RouteCache may also balance independent Read RPCs across the members of a
+// journal's Route, in proportion to weights provided by SetReadWeights.
 type RouteCache struct {
 	cache lru.Cache
 	ttl   time.Duration
+
+	weights func(pb.ProcessSpec_ID) int // Read weights of members, or nil.
+	current *lru.Cache                  // Current weights of the weighted round-robin.
+	mu      sync.Mutex                  // Guards |weights| and |current|.
 }
 
 // NewRouteCache returns a RouteCache of the given size (which must be > 0)
@@ -71,9 +78,76 @@ func (rc *RouteCache) Route(_ context.Context, item string) pb.Route {
 // IsNoopRouter returns false.
 func (rc *RouteCache) IsNoopRouter() bool { return false }
 
+// SetReadWeights enables balancing of Read RPCs by PickReadMember. The weight
+// function returns the relative weight of a member: members with greater
+// weight are picked proportionally more often, and members having a weight
+// <= 0 aren't picked. Weights may be dynamic, and for example could reflect
+// observed health or latency of the member. A nil function disables balancing.
+func (rc *RouteCache) SetReadWeights(weight func(pb.ProcessSpec_ID) int) {
+	var current, err = lru.New(maxReadBalancedMembers)
+	if err != nil {
+		panic(err.Error()) // Only errors on size <= 0.
+	}
+	rc.mu.Lock()
+	rc.weights, rc.current = weight, current
+	rc.mu.Unlock()
+}
+
+// PickReadMember implements ReadBalancer using a smooth weighted round-robin
+// over members of the Route. Round-robin state is shared across all journals,
+// so that reads are balanced over brokers rather than per-journal. State is
+// retained for up to maxReadBalancedMembers recently-picked members, and the
+// state of a member which is evicted (eg, a long-departed broker) restarts
+// from zero should it reappear.
+func (rc *RouteCache) PickReadMember(_ pb.Journal, rt pb.Route) (pb.ProcessSpec_ID, bool) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	if rc.weights == nil || len(rt.Endpoints) != len(rt.Members) {
+		return pb.ProcessSpec_ID{}, false
+	}
+
+	var pick, pickCurrent = -1, 0
+	var total int
+
+	for i, id := range rt.Members {
+		var w = rc.weights(id)
+		if w <= 0 || rt.Endpoints[i] == "" {
+			continue
+		}
+		var cur = w
+		if v, ok := rc.current.Get(id); ok {
+			cur += v.(int)
+		}
+		rc.current.Add(id, cur)
+		total += w
+
+		if pick == -1 || cur > pickCurrent {
+			pick, pickCurrent = i, cur
+		}
+	}
+	if pick == -1 {
+		return pb.ProcessSpec_ID{}, false
+	}
+	rc.current.Add(rt.Members[pick], pickCurrent-total)
+	return rt.Members[pick], true
+}
+
+// ReadBalancer picks a member of a journal Route to which a new Read RPC
+// should be dispatched. It returns false if it has no preference, in which
+// case the RPC is dispatched per the usual member preferences (eg, for
+// members of the same zone).
+type ReadBalancer interface {
+	PickReadMember(journal pb.Journal, rt pb.Route) (pb.ProcessSpec_ID, bool)
+}
+
 type cachedRoute struct {
 	route pb.Route
 	at    time.Time
 }
 
-var timeNow = time.Now
+var (
+	timeNow = time.Now
+	// Maximum number of members for which PickReadMember retains state.
+	maxReadBalancedMembers = 1024
+)
@@ -50,6 +50,59 @@ func (s *RouteCacheSuite) TestCachingCases(c *gc.C) {
 	c.Check(rc.Route(ctx, "D"), gc.DeepEquals, pb.Route{Primary: -1})
 }
 
+func (s *RouteCacheSuite) TestReadBalancing(c *gc.C) {
+	var rt = pb.Route{
+		Members: []pb.ProcessSpec_ID{
+			{Zone: "a", Suffix: "one"},
+			{Zone: "a", Suffix: "two"},
+			{Zone: "b", Suffix: "three"},
+			{Zone: "b", Suffix: "four"},
+		},
+		Endpoints: []pb.Endpoint{"http://one", "http://two", "", "http://four"},
+		Primary:   0,
+	}
+	var rc = NewRouteCache(3, time.Minute)
+	var pick = func() string {
+		var id, ok = rc.PickReadMember("a/journal", rt)
+		if !ok {
+			return ""
+		}
+		return id.Suffix
+	}
+
+	// Case: without weights, there's no preference.
+	c.Check(pick(), gc.Equals, "")
+
+	// Case: members are picked in proportion to their weights.
+	// Members with zero weight or without an Endpoint are never picked.
+	rc.SetReadWeights(func(id pb.ProcessSpec_ID) int {
+		return map[string]int{"one": 1, "two": 0, "three": 5, "four": 3}[id.Suffix]
+	})
+	var picks []string
+	for i := 0; i != 8; i++ {
+		picks = append(picks, pick())
+	}
+	// Picks are smoothly interleaved, rather than bunched.
+	c.Check(picks, gc.DeepEquals, []string{
+		"four", "one", "four", "four", "four", "one", "four", "four"})
+
+	// Case: round-robin state of members is bounded, and a member whose state
+	// was evicted restarts from zero.
+	c.Check(rc.current.Len(), gc.Equals, 2)
+	rc.current.Resize(1)
+	c.Check(rc.current.Keys(), gc.DeepEquals, []interface{}{rt.Members[3]})
+	c.Check(pick(), gc.Equals, "four")
+
+	// Case: a Route without Endpoints has no preference.
+	rt.Endpoints = nil
+	c.Check(pick(), gc.Equals, "")
+
+	// Case: clearing weights disables balancing.
+	rt.Endpoints = []pb.Endpoint{"http://one", "http://two", "", "http://four"}
+	rc.SetReadWeights(nil)
+	c.Check(pick(), gc.Equals, "")
+}
+
 func buildRouteFixture(id string) *pb.Route {
 	return &pb.Route{
 		Primary: 0,
@@ -0,0 +1,93 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	pb "go.gazette.dev/core/broker/protocol"
+)
+
+// Snapshot is a stable, read-only view over a set of journals, where each
+// journal is bounded by an exclusive end offset. Readers of a Snapshot read
+// each journal only through its end offset, such that their combined view is
+// repeatable and is unaffected by appends which happen while the Snapshot is
+// read. Whether the end offsets reflect a single point in time depends on how
+// they were resolved: see NewSnapshotOfWriteHeads and NewSnapshotAt.
+type Snapshot struct {
+	// Offsets are the exclusive end offsets of each journal of the Snapshot.
+	Offsets pb.Offsets
+}
+
+// NewSnapshotOfWriteHeads captures the current write heads of |journals|
+// as a Snapshot. Each journal of the Snapshot includes all content which
+// was committed at the time its write head was queried. Write heads are
+// queried one journal at a time and not at a common revision, so appends to
+// different journals which are made while the Snapshot is being captured may
+// be partially included: a later append to one journal can be included while
+// an earlier append to another is not.
+func NewSnapshotOfWriteHeads(ctx context.Context, client pb.RoutedJournalClient, journals []pb.Journal) (Snapshot, error) {
+	var out = Snapshot{Offsets: make(pb.Offsets, len(journals))}
+
+	for _, journal := range journals {
+		var head, err = GetHead(ctx, client, journal)
+		if err != nil {
+			return Snapshot{}, fmt.Errorf("reading write head of %s: %w", journal, err)
+		}
+		out.Offsets[journal] = head
+	}
+	return out, nil
+}
+
+// NewSnapshotAt resolves a Snapshot of |journals| as of time |at|, using the
+// modification times of persisted journal Fragments.
+//
+// Fragments don't record the times at which their individual writes were
+// made, so the resolution is approximate: each journal is bounded by the
+// end offset of its longest prefix of Fragments which were persisted at
+// or before |at|. All content of the Snapshot was therefore written at or
+// before |at|, but content written shortly before |at| may be excluded if
+// its Fragment was persisted after |at|. Fragments which have not yet been
+// persisted to a store have no modification time, and also bound the prefix.
+// For applications which record event times within messages, a Snapshot
+// resolved in this way may be further filtered by the reader.
+func NewSnapshotAt(ctx context.Context, client pb.RoutedJournalClient, journals []pb.Journal, at time.Time) (Snapshot, error) {
+	var out = Snapshot{Offsets: make(pb.Offsets, len(journals))}
+
+	for _, journal := range journals {
+		var resp, err = ListAllFragments(ctx, client, pb.FragmentsRequest{Journal: journal})
+		if err != nil {
+			return Snapshot{}, fmt.Errorf("listing fragments of %s: %w", journal, err)
+		}
+
+		var end pb.Offset
+		for i, f := range resp.Fragments {
+			if i == 0 {
+				end = f.Spec.Begin
+			}
+			if f.Spec.ModTime == 0 || f.Spec.ModTime > at.Unix() || f.Spec.Begin > end {
+				break // Not persisted by |at|, or a gap in the journal.
+			}
+			end = f.Spec.End
+		}
+		out.Offsets[journal] = end
+	}
+	return out, nil
+}
+
+// NewReader returns a RetryReader of the Snapshot |journal| which begins at
+// |offset| and reads through the journal's Snapshot end offset, at which
+// point it returns io.EOF. If the Snapshot doesn't include |journal|, or has
+// no content of the journal at or beyond |offset|, NewReader returns false.
+func (s Snapshot) NewReader(ctx context.Context, client pb.RoutedJournalClient, journal pb.Journal, offset pb.Offset) (*RetryReader, bool) {
+	var end, ok = s.Offsets[journal]
+	if !ok || offset >= end {
+		return nil, false
+	}
+	return NewRetryReader(ctx, client, pb.ReadRequest{
+		Journal:   journal,
+		Offset:    offset,
+		EndOffset: end,
+		Block:     true,
+	}), true
+}
@@ -0,0 +1,120 @@
+package client
+
+import (
+	"context"
+	"time"
+
+	pb "go.gazette.dev/core/broker/protocol"
+	"go.gazette.dev/core/broker/teststub"
+	gc "gopkg.in/check.v1"
+)
+
+type SnapshotSuite struct{}
+
+func (s *SnapshotSuite) TestSnapshotOfWriteHeads(c *gc.C) {
+	var broker = teststub.NewBroker(c)
+	defer broker.Cleanup()
+
+	var ctx = context.Background()
+	var rjc = pb.NewRoutedJournalClient(broker.Client(), pb.NoopDispatchRouter{})
+
+	go serveReadFixtures(c, broker,
+		readFixture{status: pb.Status_OFFSET_NOT_YET_AVAILABLE, offset: 1000},
+		readFixture{status: pb.Status_JOURNAL_NOT_FOUND, offset: 1000},
+	)
+
+	var snapshot, err = NewSnapshotOfWriteHeads(ctx, rjc, []pb.Journal{"a/journal"})
+	c.Check(err, gc.IsNil)
+	c.Check(snapshot, gc.DeepEquals, Snapshot{Offsets: pb.Offsets{"a/journal": 1024}})
+
+	_, err = NewSnapshotOfWriteHeads(ctx, rjc, []pb.Journal{"a/journal"})
+	c.Check(err, gc.ErrorMatches, "reading write head of a/journal: "+ErrJournalNotFound.Error())
+}
+
+func (s *SnapshotSuite) TestSnapshotAtTime(c *gc.C) {
+	var broker = teststub.NewBroker(c)
+	defer broker.Cleanup()
+
+	var ctx = context.Background()
+	var rjc = pb.NewRoutedJournalClient(broker.Client(), pb.NoopDispatchRouter{})
+	var hdr = buildHeaderFixture(broker)
+
+	var fragment = func(journal pb.Journal, begin, end, modTime int64) pb.FragmentsResponse__Fragment {
+		return pb.FragmentsResponse__Fragment{Spec: pb.Fragment{
+			Journal:          journal,
+			Begin:            begin,
+			End:              end,
+			ModTime:          modTime,
+			CompressionCodec: pb.CompressionCodec_NONE,
+		}}
+	}
+	var fixtures = map[pb.Journal][]pb.FragmentsResponse__Fragment{
+		"a/journal": {
+			fragment("a/journal", 100, 200, 1000),
+			fragment("a/journal", 150, 250, 1010), // Overlaps.
+			fragment("a/journal", 250, 300, 1020),
+			fragment("a/journal", 300, 310, 0), // Not yet persisted.
+		},
+		"b/journal": {
+			fragment("b/journal", 0, 10, 1005),
+			fragment("b/journal", 20, 30, 1006), // Follows a gap.
+		},
+		"c/journal": nil,
+	}
+	broker.ListFragmentsFunc = func(_ context.Context, req *pb.FragmentsRequest) (*pb.FragmentsResponse, error) {
+		return &pb.FragmentsResponse{Header: *hdr, Fragments: fixtures[req.Journal]}, nil
+	}
+	var journals = []pb.Journal{"a/journal", "b/journal", "c/journal"}
+
+	for _, tc := range []struct {
+		at     int64
+		expect pb.Offsets
+	}{
+		{999, pb.Offsets{"a/journal": 100, "b/journal": 0, "c/journal": 0}},
+		{1005, pb.Offsets{"a/journal": 200, "b/journal": 10, "c/journal": 0}},
+		{1010, pb.Offsets{"a/journal": 250, "b/journal": 10, "c/journal": 0}},
+		{5000, pb.Offsets{"a/journal": 300, "b/journal": 10, "c/journal": 0}},
+	} {
+		var snapshot, err = NewSnapshotAt(ctx, rjc, journals, time.Unix(tc.at, 0))
+		c.Check(err, gc.IsNil)
+		c.Check(snapshot.Offsets, gc.DeepEquals, tc.expect)
+	}
+
+	// Case: listing fragments fails.
+	broker.ListFragmentsFunc = func(_ context.Context, req *pb.FragmentsRequest) (*pb.FragmentsResponse, error) {
+		return &pb.FragmentsResponse{Header: *hdr, Status: pb.Status_JOURNAL_NOT_FOUND}, nil
+	}
+	var _, err = NewSnapshotAt(ctx, rjc, journals, time.Unix(5000, 0))
+	c.Check(err, gc.ErrorMatches, "listing fragments of a/journal: JOURNAL_NOT_FOUND")
+}
+
+func (s *SnapshotSuite) TestSnapshotReaders(c *gc.C) {
+	var ctx = context.Background()
+	var rjc = pb.NewRoutedJournalClient(nil, pb.NoopDispatchRouter{})
+	var snapshot = Snapshot{Offsets: pb.Offsets{"a/journal": 1024, "b/journal": 0}}
+
+	var rr, ok = snapshot.NewReader(ctx, rjc, "a/journal", 100)
+	c.Check(ok, gc.Equals, true)
+	c.Check(rr.Reader.Request, gc.DeepEquals, pb.ReadRequest{
+		Journal:   "a/journal",
+		Offset:    100,
+		EndOffset: 1024,
+		Block:     true,
+	})
+
+	// Cases: no content at or beyond the offset, or the journal isn't included.
+	for _, tc := range []struct {
+		journal pb.Journal
+		offset  pb.Offset
+	}{
+		{"a/journal", 1024},
+		{"b/journal", 0},
+		{"c/journal", 0},
+	} {
+		rr, ok = snapshot.NewReader(ctx, rjc, tc.journal, tc.offset)
+		c.Check(rr, gc.IsNil)
+		c.Check(ok, gc.Equals, false)
+	}
+}
+
+var _ = gc.Suite(&SnapshotSuite{})
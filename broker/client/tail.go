@@ -0,0 +1,242 @@
+package client
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	pb "go.gazette.dev/core/broker/protocol"
+)
+
+// UnpackFunc unpacks the next message frame from a bufio.Reader. The frame
+// may reference the Reader's internal buffer, and be invalidated by its next
+// read. message.UnpackLine and message.UnpackFixedFrame are UnpackFuncs.
+type UnpackFunc func(*bufio.Reader) ([]byte, error)
+
+// TailHandler handles a message frame of a journal read by Tail, which spans
+// the journal offsets [begin, end). |frame| is valid only for the duration of
+// the call, and must be copied if it's retained.
+type TailHandler func(ctx context.Context, frame []byte, begin, end pb.Offset) error
+
+// OffsetStore stores the offset through which Tail has handled the messages
+// of a journal.
+type OffsetStore interface {
+	// LoadOffset returns the stored offset of the journal,
+	// or zero if no offset has been stored.
+	LoadOffset(ctx context.Context, journal pb.Journal) (pb.Offset, error)
+	// StoreOffset stores the offset of the journal.
+	StoreOffset(ctx context.Context, journal pb.Journal, offset pb.Offset) error
+}
+
+// TailErrorPolicy determines how Tail handles an error of its TailHandler.
+type TailErrorPolicy int
+
+const (
+	// TailStop stops Tail, which returns the handler error.
+	TailStop TailErrorPolicy = iota
+	// TailSkip logs the handler error and skips the message.
+	TailSkip
+	// TailRetry retries the handler with a back-off, until it succeeds or its
+	// TailOptions.MaxRetries are exhausted (at which point Tail stops).
+	TailRetry
+)
+
+// DefaultTailCheckpointInterval is the default TailOptions.CheckpointInterval.
+const DefaultTailCheckpointInterval = 5 * time.Second
+
+// TailOptions configure Tail.
+type TailOptions struct {
+	// Store of the journal offset through which messages have been handled.
+	// Tail begins reading from its loaded offset, and periodically stores the
+	// offset through which it has handled messages. Required.
+	Store OffsetStore
+	// CheckpointInterval is the interval with which handled offsets are stored.
+	// If zero, DefaultTailCheckpointInterval is used.
+	CheckpointInterval time.Duration
+	// OnError is the TailErrorPolicy of handler errors.
+	OnError TailErrorPolicy
+	// MaxRetries of a message under the TailRetry policy.
+	// If zero, a message is retried indefinitely.
+	MaxRetries int
+}
+
+// Tail reads the journal from the offset loaded from the OffsetStore, and
+// invokes |handler| with each message frame unpacked by |unpack|. It blocks
+// for, and handles, messages as they're appended to the journal. Tail is a
+// lightweight alternative to the consumer framework, for simple processing of
+// a single journal without its transactions, stores, or shard assignment.
+//
+// Transient read errors, such as from a broker or network failure, are
+// retried by reconnecting at the current read offset. Tail returns when
+// |ctx| is cancelled, when the handler fails under the TailStop policy
+// (or exhausts its TailRetry retries), or upon an error of |unpack| such as
+// a malformed frame. The offset of handled messages is stored prior to
+// returning, unless |ctx| was cancelled.
+//
+// Messages are delivered at least once: a restarted Tail resumes from the
+// last stored offset, and messages handled after it was stored are handled
+// again. Handlers should be idempotent.
+func Tail(ctx context.Context, rjc pb.RoutedJournalClient, journal pb.Journal,
+	unpack UnpackFunc, handler TailHandler, opts TailOptions) error {
+
+	if opts.Store == nil {
+		return errors.New("TailOptions.Store is required")
+	} else if opts.CheckpointInterval == 0 {
+		opts.CheckpointInterval = DefaultTailCheckpointInterval
+	}
+
+	var offset, err = opts.Store.LoadOffset(ctx, journal)
+	if err != nil {
+		return errors.WithMessage(err, "loading offset")
+	}
+	var ckpt = &tailCheckpoint{journal: journal, offsets: opts.Store, stored: offset, handled: offset}
+
+	var rr = NewRetryReader(ctx, rjc, pb.ReadRequest{Journal: journal, Offset: offset, Block: true})
+	var br = bufio.NewReader(rr)
+	defer rr.Cancel()
+
+	var ckptCtx, ckptCancel = context.WithCancel(ctx)
+	var ckptDone = make(chan struct{})
+	go func() {
+		defer close(ckptDone)
+		ckpt.serve(ckptCtx, opts.CheckpointInterval)
+	}()
+
+	err = tailMessages(ctx, rr, br, unpack, handler, opts, ckpt)
+
+	ckptCancel()
+	<-ckptDone
+
+	if ctx.Err() != nil {
+		return ctx.Err()
+	} else if storeErr := ckpt.store(ctx); storeErr != nil && err == nil {
+		err = errors.WithMessage(storeErr, "storing offset")
+	}
+	return err
+}
+
+// tailMessages reads and handles messages of |br| until an error occurs.
+func tailMessages(ctx context.Context, rr *RetryReader, br *bufio.Reader,
+	unpack UnpackFunc, handler TailHandler, opts TailOptions, ckpt *tailCheckpoint) error {
+
+	for {
+		var begin = rr.AdjustedOffset(br)
+		var frame, err = unpack(br)
+
+		switch err {
+		case nil:
+		case io.ErrNoProgress:
+			// Swallow ErrNoProgress from our bufio.Reader. Reader returns an empty
+			// read to allow for inspection of the ReadResponse message.
+			continue
+		case ErrOffsetJump:
+			// Content was removed from the journal. Continue at the jumped offset.
+			loggerFrom(ctx).Log(LogWarn, "tailed journal offset jump", LogFields{
+				"journal": rr.Journal(),
+				"from":    begin,
+				"to":      rr.Offset(),
+			})
+			ckpt.handledThrough(rr.AdjustedOffset(br))
+			continue
+		default:
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return errors.WithMessagef(err, "unpacking frame (offset %d)", begin)
+		}
+		var end = rr.AdjustedOffset(br)
+
+		for attempt := 0; true; attempt++ {
+			if err = handler(ctx, frame, begin, end); err == nil || ctx.Err() != nil {
+				break
+			} else if opts.OnError == TailSkip {
+				loggerFrom(ctx).Log(LogWarn, "skipping message of tailed journal", LogFields{
+					"err":     err,
+					"journal": rr.Journal(),
+					"begin":   begin,
+					"end":     end,
+				})
+				err = nil
+				break
+			} else if opts.OnError != TailRetry || (opts.MaxRetries != 0 && attempt == opts.MaxRetries) {
+				break
+			}
+			loggerFrom(ctx).Log(LogWarn, "handling message of tailed journal failed (will retry)", LogFields{
+				"err":     err,
+				"journal": rr.Journal(),
+				"begin":   begin,
+				"attempt": attempt,
+			})
+
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(backoff(attempt)):
+			}
+		}
+
+		if ctx.Err() != nil {
+			return ctx.Err()
+		} else if err != nil {
+			return errors.WithMessagef(err, "handling message (offset %d)", begin)
+		}
+		ckpt.handledThrough(end)
+	}
+}
+
+// tailCheckpoint tracks the offset through which Tail has handled messages,
+// and stores it to the OffsetStore.
+type tailCheckpoint struct {
+	journal pb.Journal
+	offsets OffsetStore
+
+	mu      sync.Mutex
+	handled pb.Offset // Offset through which messages have been handled.
+	stored  pb.Offset // Offset which was last stored.
+}
+
+func (c *tailCheckpoint) handledThrough(offset pb.Offset) {
+	c.mu.Lock()
+	c.handled = offset
+	c.mu.Unlock()
+}
+
+// store the handled offset, if it's changed since it was last stored.
+// Calls of store must not be concurrent.
+func (c *tailCheckpoint) store(ctx context.Context) error {
+	c.mu.Lock()
+	var handled, stored = c.handled, c.stored
+	c.mu.Unlock()
+
+	if handled == stored {
+		return nil
+	} else if err := c.offsets.StoreOffset(ctx, c.journal, handled); err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	c.stored = handled
+	c.mu.Unlock()
+	return nil
+}
+
+// serve stores the handled offset every |interval|, until |ctx| is cancelled.
+func (c *tailCheckpoint) serve(ctx context.Context, interval time.Duration) {
+	var ticker = time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		if err := c.store(ctx); err != nil && ctx.Err() == nil {
+			loggerFrom(ctx).Log(LogWarn, "failed to store offset of tailed journal (will retry)",
+				LogFields{"err": err, "journal": c.journal})
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package client
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"sync"
+
+	pb "go.gazette.dev/core/broker/protocol"
+	"go.gazette.dev/core/broker/teststub"
+	gc "gopkg.in/check.v1"
+)
+
+type TailSuite struct{}
+
+func (s *TailSuite) TestRetriesThenStopsAndStoresOffset(c *gc.C) {
+	var broker = teststub.NewBroker(c)
+	defer broker.Cleanup()
+
+	var rjc = pb.NewRoutedJournalClient(broker.Client(), pb.NoopDispatchRouter{})
+	var store = &memOffsetStore{offsets: map[pb.Journal]pb.Offset{"a/journal": 100}}
+	var handled []string
+	var failed bool
+
+	var done = make(chan struct{})
+	go func() {
+		serveReadFixtures(c, broker, readFixture{content: "a\nbad\nstop\n"})
+		close(done)
+	}()
+
+	var err = Tail(context.Background(), rjc, "a/journal", unpackLineFixture,
+		func(_ context.Context, frame []byte, begin, end pb.Offset) error {
+			handled = append(handled, string(frame))
+
+			if string(frame) == "bad\n" && !failed {
+				c.Check(begin, gc.Equals, int64(102))
+				c.Check(end, gc.Equals, int64(106))
+				failed = true
+				return errors.New("transient")
+			} else if string(frame) == "stop\n" {
+				return errors.New("stop!")
+			}
+			return nil
+		}, TailOptions{Store: store, OnError: TailRetry, MaxRetries: 2})
+	<-done
+
+	c.Check(err, gc.ErrorMatches, `handling message \(offset 106\): stop!`)
+	c.Check(handled, gc.DeepEquals, []string{"a\n", "bad\n", "bad\n", "stop\n", "stop\n", "stop\n"})
+	// Expect the offset through the last handled message was stored.
+	c.Check(store.offsets["a/journal"], gc.Equals, pb.Offset(106))
+}
+
+func (s *TailSuite) TestSkipsHandlerErrors(c *gc.C) {
+	var broker = teststub.NewBroker(c)
+	defer broker.Cleanup()
+
+	var rjc = pb.NewRoutedJournalClient(broker.Client(), pb.NoopDispatchRouter{})
+	var store = &memOffsetStore{offsets: make(map[pb.Journal]pb.Offset)}
+	var ctx, cancel = context.WithCancel(context.Background())
+	var handled []string
+
+	var done = make(chan struct{})
+	go func() {
+		serveReadFixtures(c, broker, readFixture{content: "a\nbad\nc\n"})
+		close(done)
+	}()
+
+	var err = Tail(ctx, rjc, "a/journal", unpackLineFixture,
+		func(_ context.Context, frame []byte, _, _ pb.Offset) error {
+			handled = append(handled, string(frame))
+
+			if string(frame) == "bad\n" {
+				return errors.New("whoops")
+			} else if string(frame) == "c\n" {
+				cancel()
+			}
+			return nil
+		}, TailOptions{Store: store, OnError: TailSkip})
+	<-done
+
+	c.Check(err, gc.Equals, context.Canceled)
+	c.Check(handled, gc.DeepEquals, []string{"a\n", "bad\n", "c\n"})
+	// The checkpoint interval didn't elapse, and the offset wasn't stored.
+	c.Check(store.offsets, gc.HasLen, 0)
+}
+
+func (s *TailSuite) TestRequiresStore(c *gc.C) {
+	var err = Tail(context.Background(), nil, "a/journal", unpackLineFixture,
+		func(context.Context, []byte, pb.Offset, pb.Offset) error { return nil }, TailOptions{})
+	c.Check(err, gc.ErrorMatches, `TailOptions.Store is required`)
+}
+
+type memOffsetStore struct {
+	mu      sync.Mutex
+	offsets map[pb.Journal]pb.Offset
+}
+
+func (s *memOffsetStore) LoadOffset(_ context.Context, journal pb.Journal) (pb.Offset, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.offsets[journal], nil
+}
+
+func (s *memOffsetStore) StoreOffset(_ context.Context, journal pb.Journal, offset pb.Offset) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.offsets[journal] = offset
+	return nil
+}
+
+func unpackLineFixture(br *bufio.Reader) ([]byte, error) { return br.ReadBytes('\n') }
+
+var _ = gc.Suite(&TailSuite{})
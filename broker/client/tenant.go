@@ -0,0 +1,166 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	pb "go.gazette.dev/core/broker/protocol"
+	"google.golang.org/grpc"
+)
+
+// TenantPrefixError is returned by a tenant RoutedJournalClient (see
+// NewTenantJournalClient) for a request of a journal which is outside of
+// the tenant's journal prefix. The request is not sent to brokers.
+type TenantPrefixError struct {
+	// Journal of the attempted request.
+	Journal pb.Journal
+	// Prefix to which the tenant's journals are restricted.
+	Prefix string
+}
+
+func (e *TenantPrefixError) Error() string {
+	return fmt.Sprintf("journal %s is outside of the allowed tenant prefix %s", e.Journal, e.Prefix)
+}
+
+// NewTenantJournalClient returns a RoutedJournalClient which restricts |rjc| to
+// journals having the tenant |prefix|, which must end in '/'. It's intended
+// for clusters where brokers are shared by many tenants, as a client-side
+// defense-in-depth which complements server-side authorization.
+//
+// Read, Append, ListFragments, and Apply requests of journals outside of the
+// prefix fail with a *TenantPrefixError, without being sent to brokers. List
+// and Watch responses are filtered to journals of the prefix, regardless of
+// the request LabelSelector. Replicate is used only between broker peers, and always fails.
+func NewTenantJournalClient(rjc pb.RoutedJournalClient, prefix string) (pb.RoutedJournalClient, error) {
+	if !strings.HasSuffix(prefix, "/") {
+		return nil, fmt.Errorf("tenant prefix must end in '/' (%s)", prefix)
+	} else if err := pb.Journal(prefix[:len(prefix)-1]).Validate(); err != nil {
+		return nil, fmt.Errorf("tenant prefix: %w", err)
+	}
+	return &tenantClient{RoutedJournalClient: rjc, prefix: prefix}, nil
+}
+
+type tenantClient struct {
+	pb.RoutedJournalClient
+	prefix string
+}
+
+// check returns a *TenantPrefixError if |journal| is outside of the prefix.
+func (c *tenantClient) check(journal pb.Journal) error {
+	if !strings.HasPrefix(journal.StripMeta().String(), c.prefix) {
+		return &TenantPrefixError{Journal: journal, Prefix: c.prefix}
+	}
+	return nil
+}
+
+func (c *tenantClient) List(ctx context.Context, req *pb.ListRequest, opts ...grpc.CallOption) (*pb.ListResponse, error) {
+	var resp, err = c.RoutedJournalClient.List(ctx, req, opts...)
+	if err != nil {
+		return resp, err
+	}
+	// Filter Journals in-place. Note the response is freshly decoded, and
+	// not shared with other callers.
+	var journals = resp.Journals[:0]
+	for _, j := range resp.Journals {
+		if c.check(j.Spec.Name) == nil {
+			journals = append(journals, j)
+		}
+	}
+	resp.Journals = journals
+	return resp, nil
+}
+
+func (c *tenantClient) Apply(ctx context.Context, req *pb.ApplyRequest, opts ...grpc.CallOption) (*pb.ApplyResponse, error) {
+	for _, change := range req.Changes {
+		var journal = change.Delete
+		if change.Upsert != nil {
+			journal = change.Upsert.Name
+		}
+		if err := c.check(journal); err != nil {
+			return nil, err
+		}
+	}
+	return c.RoutedJournalClient.Apply(ctx, req, opts...)
+}
+
+func (c *tenantClient) Read(ctx context.Context, req *pb.ReadRequest, opts ...grpc.CallOption) (pb.Journal_ReadClient, error) {
+	if err := c.check(req.Journal); err != nil {
+		return nil, err
+	}
+	return c.RoutedJournalClient.Read(ctx, req, opts...)
+}
+
+func (c *tenantClient) Append(ctx context.Context, opts ...grpc.CallOption) (pb.Journal_AppendClient, error) {
+	var stream, err = c.RoutedJournalClient.Append(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return tenantAppendClient{Journal_AppendClient: stream, c: c}, nil
+}
+
+func (c *tenantClient) Replicate(context.Context, ...grpc.CallOption) (pb.Journal_ReplicateClient, error) {
+	return nil, fmt.Errorf("Replicate is not available to tenant clients")
+}
+
+func (c *tenantClient) ListFragments(ctx context.Context, req *pb.FragmentsRequest, opts ...grpc.CallOption) (*pb.FragmentsResponse, error) {
+	if err := c.check(req.Journal); err != nil {
+		return nil, err
+	}
+	return c.RoutedJournalClient.ListFragments(ctx, req, opts...)
+}
+
+func (c *tenantClient) Watch(ctx context.Context, req *pb.WatchRequest, opts ...grpc.CallOption) (pb.Journal_WatchClient, error) {
+	var stream, err = c.RoutedJournalClient.Watch(ctx, req, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return tenantWatchClient{Journal_WatchClient: stream, c: c}, nil
+}
+
+// tenantWatchClient filters WatchResponses to journals of the prefix.
+type tenantWatchClient struct {
+	pb.Journal_WatchClient
+	c *tenantClient
+}
+
+func (wc tenantWatchClient) Recv() (*pb.WatchResponse, error) {
+	var resp, err = wc.Journal_WatchClient.Recv()
+	if err != nil {
+		return resp, err
+	}
+	var journals = resp.Journals[:0]
+	for _, j := range resp.Journals {
+		if wc.c.check(j.Spec.Name) == nil {
+			journals = append(journals, j)
+		}
+	}
+	var deleted = resp.Deleted[:0]
+	for _, name := range resp.Deleted {
+		if wc.c.check(name) == nil {
+			deleted = append(deleted, name)
+		}
+	}
+	resp.Journals, resp.Deleted = journals, deleted
+	return resp, nil
+}
+
+// tenantAppendClient checks the journal of AppendRequests as they're sent.
+// The journal is known only once the first AppendRequest is sent, and a
+// rejected request closes the stream without having sent to the broker.
+type tenantAppendClient struct {
+	pb.Journal_AppendClient
+	c *tenantClient
+}
+
+func (ac tenantAppendClient) Send(req *pb.AppendRequest) error { return ac.SendMsg(req) }
+
+func (ac tenantAppendClient) SendMsg(m interface{}) error {
+	if req, ok := m.(*pb.AppendRequest); !ok || req.Journal == "" {
+		// Content chunks have no journal.
+	} else if err := ac.c.check(req.Journal); err != nil {
+		_ = ac.Journal_AppendClient.CloseSend()
+		return err
+	}
+	return ac.Journal_AppendClient.SendMsg(m)
+}
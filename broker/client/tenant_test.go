@@ -0,0 +1,126 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	pb "go.gazette.dev/core/broker/protocol"
+	"go.gazette.dev/core/broker/teststub"
+	gc "gopkg.in/check.v1"
+)
+
+type TenantSuite struct{}
+
+func (s *TenantSuite) TestPrefixValidation(c *gc.C) {
+	var _, err = NewTenantJournalClient(nil, "tenant/a")
+	c.Check(err, gc.ErrorMatches, `tenant prefix must end in '/' \(tenant/a\)`)
+	_, err = NewTenantJournalClient(nil, "/")
+	c.Check(err, gc.ErrorMatches, `tenant prefix: .*`)
+	_, err = NewTenantJournalClient(nil, "tenant/a/")
+	c.Check(err, gc.IsNil)
+}
+
+func (s *TenantSuite) TestRequestsAreRestrictedToPrefix(c *gc.C) {
+	var broker = teststub.NewBroker(c)
+	defer broker.Cleanup()
+
+	var ctx = pb.WithDispatchDefault(context.Background())
+	var hdr = *buildHeaderFixture(broker)
+	var tc, err = NewTenantJournalClient(
+		pb.NewRoutedJournalClient(broker.Client(), pb.NoopDispatchRouter{}), "tenant/a/")
+	c.Assert(err, gc.IsNil)
+
+	var checkRejected = func(err error, journal pb.Journal) {
+		var tpe *TenantPrefixError
+		c.Check(errors.As(err, &tpe), gc.Equals, true)
+		c.Check(tpe, gc.DeepEquals, &TenantPrefixError{Journal: journal, Prefix: "tenant/a/"})
+	}
+
+	// List responses are filtered to journals of the prefix.
+	broker.ListFunc = func(context.Context, *pb.ListRequest) (*pb.ListResponse, error) {
+		return &pb.ListResponse{
+			Header: hdr,
+			Journals: buildListResponseFixture(
+				"tenant/a/one", "tenant/b/two", "tenant/a/three", "tenant/ab/four"),
+		}, nil
+	}
+	listResp, err := tc.List(ctx, &pb.ListRequest{})
+	c.Check(err, gc.IsNil)
+	c.Check(listResp.Journals, gc.DeepEquals,
+		buildListResponseFixture("tenant/a/one", "tenant/a/three"))
+
+	// As are Watch responses.
+	broker.WatchFunc = func(_ *pb.WatchRequest, srv pb.Journal_WatchServer) error {
+		return srv.Send(&pb.WatchResponse{
+			Header:   hdr,
+			Revision: 1234,
+			Journals: buildListResponseFixture("tenant/a/one", "tenant/b/two"),
+			Deleted:  []pb.Journal{"tenant/ab/four", "tenant/a/three"},
+		})
+	}
+	watch, err := tc.Watch(ctx, &pb.WatchRequest{})
+	c.Assert(err, gc.IsNil)
+	watchResp, err := watch.Recv()
+	c.Check(err, gc.IsNil)
+	c.Check(watchResp.Journals, gc.DeepEquals, buildListResponseFixture("tenant/a/one"))
+	c.Check(watchResp.Deleted, gc.DeepEquals, []pb.Journal{"tenant/a/three"})
+
+	// Requests of journals outside the prefix are rejected without being
+	// sent (the stub broker has no Apply or ListFragments implementations).
+	_, err = tc.Read(ctx, &pb.ReadRequest{Journal: "tenant/b/journal"})
+	checkRejected(err, "tenant/b/journal")
+
+	_, err = tc.ListFragments(ctx, &pb.FragmentsRequest{Journal: "tenant/ab/journal"})
+	checkRejected(err, "tenant/ab/journal")
+
+	_, err = tc.Apply(ctx, &pb.ApplyRequest{Changes: []pb.ApplyRequest_Change{
+		{Upsert: &pb.JournalSpec{Name: "tenant/a/journal"}},
+		{Delete: "tenant/b/journal"},
+	}})
+	checkRejected(err, "tenant/b/journal")
+
+	// Append streams are closed upon a rejected AppendRequest.
+	var app = NewAppender(ctx, tc, pb.AppendRequest{Journal: "other/journal"})
+	_, err = app.Write([]byte("foo"))
+	checkRejected(err, "other/journal")
+	c.Check(<-broker.ReadLoopErrCh, gc.Equals, io.EOF)
+	broker.WriteLoopErrCh <- nil
+
+	// An AppendService fails a rejected append, rather than retrying it.
+	var aa = NewAppendService(ctx, tc).StartAppend(pb.AppendRequest{Journal: "tenant/b/journal"}, nil)
+	_, _ = aa.Writer().WriteString("foo")
+	c.Check(aa.Release(), gc.IsNil)
+	c.Check(<-broker.ReadLoopErrCh, gc.Equals, io.EOF)
+	broker.WriteLoopErrCh <- nil
+
+	<-aa.Done()
+	checkRejected(aa.Err(), "tenant/b/journal")
+
+	// Meta-data of the journal name is ignored.
+	_, err = tc.ListFragments(ctx, &pb.FragmentsRequest{Journal: "tenant/b/journal;tenant/a/"})
+	checkRejected(err, "tenant/b/journal;tenant/a/")
+
+	// Requests of journals within the prefix are passed through.
+	broker.ListFragmentsFunc = func(_ context.Context, req *pb.FragmentsRequest) (*pb.FragmentsResponse, error) {
+		c.Check(req.Journal, gc.Equals, pb.Journal("tenant/a/journal;meta"))
+		return &pb.FragmentsResponse{Status: pb.Status_OK, Header: hdr}, nil
+	}
+	_, err = tc.ListFragments(ctx, &pb.FragmentsRequest{Journal: "tenant/a/journal;meta"})
+	c.Check(err, gc.IsNil)
+
+	broker.ApplyFunc = func(_ context.Context, req *pb.ApplyRequest) (*pb.ApplyResponse, error) {
+		c.Check(req.Changes, gc.HasLen, 2)
+		return &pb.ApplyResponse{Status: pb.Status_OK, Header: hdr}, nil
+	}
+	_, err = tc.Apply(ctx, &pb.ApplyRequest{Changes: []pb.ApplyRequest_Change{
+		{Upsert: &pb.JournalSpec{Name: "tenant/a/journal"}},
+		{Delete: "tenant/a/other"},
+	}})
+	c.Check(err, gc.IsNil)
+
+	_, err = tc.Replicate(ctx)
+	c.Check(err, gc.ErrorMatches, "Replicate is not available to tenant clients")
+}
+
+var _ = gc.Suite(&TenantSuite{})
@@ -0,0 +1,51 @@
+package client
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	pb "go.gazette.dev/core/broker/protocol"
+)
+
+// FragmentOffsetAt returns the Begin offset of the first Fragment of the
+// journal which may hold content written at or after time |t|, or the write
+// head of the journal if none may. It's the approximate offset of the first
+// message written at or after |t|: any such message is at or after the
+// returned offset, but the Fragment may also hold earlier messages.
+//
+// Fragments are searched by the broker, which filters its Fragment index on
+// the ModTime of each persisted Fragment (see FragmentsRequest.BeginModTime).
+// A Fragment is persisted only after all of its content is written, so its
+// ModTime bounds the write times of its messages. Only a single Fragment
+// is returned to the client, regardless of the size of the index. Fragments
+// which aren't yet persisted have no ModTime, and are always candidates.
+// Since ModTimes have a resolution of seconds, so does the search.
+func FragmentOffsetAt(ctx context.Context, client pb.RoutedJournalClient, journal pb.Journal, t time.Time) (pb.Offset, error) {
+	var routedCtx = pb.WithDispatchItemRoute(ctx, client, journal.String(), false)
+
+	var resp, err = client.ListFragments(routedCtx, &pb.FragmentsRequest{
+		Journal:      journal,
+		BeginModTime: t.Unix(),
+		PageLimit:    1,
+	})
+	if err != nil {
+		return 0, mapGRPCCtxErr(ctx, err)
+	} else if err = resp.Validate(); err != nil {
+		return 0, err
+	}
+
+	switch resp.Status {
+	case pb.Status_OK:
+	case pb.Status_JOURNAL_NOT_FOUND:
+		return 0, ErrJournalNotFound
+	default:
+		return 0, errors.New(resp.Status.String())
+	}
+
+	if len(resp.Fragments) != 0 {
+		return resp.Fragments[0].Spec.Begin, nil
+	}
+	// All content was written before |t|.
+	return GetHead(ctx, client, journal)
+}
@@ -37,15 +37,25 @@ func NewCodecReader(r io.Reader, codec pb.CompressionCodec) (Decompressor, error
 
 // NewCodecWriter returns a Compressor wrapping the Writer encoding with CompressionCodec.
 func NewCodecWriter(w io.Writer, codec pb.CompressionCodec) (Compressor, error) {
+	return NewCodecWriterLevel(w, codec, 0)
+}
+
+// NewCodecWriterLevel returns a Compressor wrapping the Writer encoding with
+// CompressionCodec at the given compression |level|. A zero |level| uses the
+// codec's default level. Codecs which don't support levels ignore |level|.
+func NewCodecWriterLevel(w io.Writer, codec pb.CompressionCodec, level int) (Compressor, error) {
 	switch codec {
 	case pb.CompressionCodec_NONE:
 		return nopWriteCloser{w}, nil
 	case pb.CompressionCodec_GZIP, pb.CompressionCodec_GZIP_OFFLOAD_DECOMPRESSION:
-		return gzip.NewWriter(w), nil
+		if level == 0 {
+			level = gzip.DefaultCompression
+		}
+		return gzip.NewWriterLevel(w, level)
 	case pb.CompressionCodec_SNAPPY:
 		return snappy.NewBufferedWriter(w), nil
 	case pb.CompressionCodec_ZSTANDARD:
-		return zstdNewWriter(w)
+		return zstdNewWriter(w, level)
 	default:
 		return nil, fmt.Errorf("unsupported codec %s", codec.String())
 	}
@@ -59,7 +69,7 @@ var (
 	zstdNewReader = func(io.Reader) (io.ReadCloser, error) {
 		return nil, fmt.Errorf("ZSTANDARD was not enabled at compile time")
 	}
-	zstdNewWriter = func(io.Writer) (io.WriteCloser, error) {
+	zstdNewWriter = func(io.Writer, int) (io.WriteCloser, error) {
 		return nil, fmt.Errorf("ZSTANDARD was not enabled at compile time")
 	}
 )
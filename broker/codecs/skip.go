@@ -0,0 +1,122 @@
+package codecs
+
+import (
+	"bufio"
+	"encoding/binary"
+	"io"
+	"strings"
+
+	"github.com/golang/snappy"
+	pb "go.gazette.dev/core/broker/protocol"
+)
+
+// NewCodecReaderOffset returns a Decompressor of the Reader encoded with
+// CompressionCodec, which has skipped up to |offset| leading bytes of
+// decompressed content without decompressing them. It also returns the number
+// of bytes which were skipped, which may be less than |offset|. The caller
+// must read and discard remaining content through |offset|.
+//
+// Codecs differ in their support for skipping:
+//   - NONE and GZIP_OFFLOAD_DECOMPRESSION content isn't decompressed by the
+//     client. It's skipped entirely if the Reader is an io.Seeker, and is
+//     otherwise left to be discarded by the caller.
+//   - SNAPPY skips whole chunks of its framing format, each of which records
+//     the decoded length of its independently compressed content. Skipped
+//     chunks are read, but are neither decompressed nor checksummed.
+//   - GZIP and ZSTANDARD streams can't be entered except at their beginning,
+//     and skip no content.
+func NewCodecReaderOffset(r io.Reader, codec pb.CompressionCodec, offset int64) (Decompressor, int64, error) {
+	var skipped int64
+
+	if offset == 0 {
+		// Nothing to skip.
+	} else if codec == pb.CompressionCodec_SNAPPY {
+		r, skipped = skipSnappyChunks(r, offset)
+	} else if !Skippable(codec) {
+		// Codec doesn't support skipping.
+	} else if s, ok := r.(io.Seeker); ok {
+		if _, err := s.Seek(offset, io.SeekCurrent); err != nil {
+			return nil, 0, err
+		}
+		skipped = offset
+	}
+
+	var d, err = NewCodecReader(r, codec)
+	if err != nil {
+		return nil, 0, err
+	}
+	return d, skipped, nil
+}
+
+// Skippable returns true if content of the codec may be skipped without
+// being decompressed, as by NewCodecReaderOffset.
+func Skippable(codec pb.CompressionCodec) bool {
+	switch codec {
+	case pb.CompressionCodec_NONE, pb.CompressionCodec_GZIP_OFFLOAD_DECOMPRESSION, pb.CompressionCodec_SNAPPY:
+		return true
+	default:
+		return false
+	}
+}
+
+// skipSnappyChunks discards leading chunks of the framed snappy stream |r|
+// having a total decoded length of at most |offset|. It returns a Reader of
+// the remaining stream, which is prefixed with a snappy stream identifier,
+// and the decoded length of discarded chunks.
+//
+// Malformed streams and chunks aren't skipped, and are left to the snappy
+// Reader to surface.
+func skipSnappyChunks(r io.Reader, offset int64) (io.Reader, int64) {
+	var br = bufio.NewReader(r)
+	var skipped int64
+
+	if b, err := br.Peek(len(snappyStreamIdentifier)); err != nil || string(b) != snappyStreamIdentifier {
+		return br, 0
+	}
+	for {
+		var hdr, err = br.Peek(snappyChunkHeaderLen)
+		if err != nil {
+			break
+		}
+		var kind = hdr[0]
+		var length = int(hdr[1]) | int(hdr[2])<<8 | int(hdr[3])<<16
+		var decoded int
+
+		switch {
+		case kind == snappyChunkCompressed:
+			// Peek the varint-encoded decoded length which leads the block.
+			var n = snappyChunkHeaderLen + snappyChecksumLen + binary.MaxVarintLen32
+			if n > snappyChunkHeaderLen+length {
+				n = snappyChunkHeaderLen + length
+			}
+			if block, err := br.Peek(n); err != nil || length < snappyChecksumLen {
+				decoded = -1
+			} else if decoded, err = snappy.DecodedLen(block[snappyChunkHeaderLen+snappyChecksumLen:]); err != nil {
+				decoded = -1
+			}
+		case kind == snappyChunkUncompressed:
+			decoded = length - snappyChecksumLen // -1 or less if malformed.
+		case kind >= 0x80:
+			// Stream identifiers, padding, and reserved skippable chunks.
+		default:
+			decoded = -1 // Reserved unskippable chunk.
+		}
+
+		if decoded < 0 || skipped+int64(decoded) > offset {
+			break
+		} else if _, err = br.Discard(snappyChunkHeaderLen + length); err != nil {
+			break
+		}
+		skipped += int64(decoded)
+	}
+	// The snappy Reader requires that a stream begin with its identifier.
+	return io.MultiReader(strings.NewReader(snappyStreamIdentifier), br), skipped
+}
+
+const (
+	snappyChunkHeaderLen    = 4
+	snappyChecksumLen       = 4
+	snappyChunkCompressed   = 0x00
+	snappyChunkUncompressed = 0x01
+	snappyStreamIdentifier  = "\xff\x06\x00\x00sNaPpY"
+)
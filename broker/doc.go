@@ -24,4 +24,21 @@ var (
 		Name: "gazette_write_head",
 		Help: "Current write head of the journal (i.e., next byte offset to be written).",
 	}, []string{"journal"})
+	readDeniedBytesTotal = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "gazette_read_denied_bytes_total",
+		Help: "Total number of journal bytes skipped by Read RPCs because their client wasn't authorized to read them.",
+	})
+	fragmentRefreshesTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "gazette_fragment_refreshes_total",
+		Help: "Total number of refreshes of remote fragment listings, by status.",
+	}, []string{"status"})
+	fragmentTargetLengthGauge = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "gazette_fragment_target_length_bytes",
+		Help: "Current target length of fragments of journals having adaptive fragment lengths.",
+	}, []string{"journal"})
+	fragmentListingSeconds = promauto.NewHistogram(prometheus.HistogramOpts{
+		Name:    "gazette_fragment_listing_seconds",
+		Help:    "Latency of listing the remote fragment stores of a journal.",
+		Buckets: prometheus.ExponentialBuckets(0.01, 2, 12), // 10ms => ~20s.
+	})
 )
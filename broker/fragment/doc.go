@@ -46,6 +46,14 @@ var (
 		Name: "gazette_spool_persisted_total",
 		Help: "Total number of journal fragment spools which were persisted (by this server, or by another and then verified by this server).",
 	})
+	_ = promauto.NewGaugeFunc(prometheus.GaugeOpts{
+		Name: "gazette_spool_bytes",
+		Help: "Number of content bytes held by open journal fragment spools.",
+	}, func() float64 { var b, _ = SpoolUsage(); return float64(b) })
+	_ = promauto.NewGaugeFunc(prometheus.GaugeOpts{
+		Name: "gazette_spools_open",
+		Help: "Number of open journal fragment spools which hold content.",
+	}, func() float64 { var _, n = SpoolUsage(); return float64(n) })
 
 	// DEPRECATED metrics to be removed:
 	committedBytesTotal = promauto.NewCounter(prometheus.CounterOpts{
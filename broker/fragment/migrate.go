@@ -0,0 +1,245 @@
+package fragment
+
+import (
+	"context"
+	"crypto/sha1"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"go.gazette.dev/core/broker/codecs"
+	pb "go.gazette.dev/core/broker/protocol"
+)
+
+// MigrateArgs are arguments of Migrate.
+type MigrateArgs struct {
+	// Journal of the migrated Fragments.
+	Journal pb.Journal
+	// From is the FragmentStore from which Fragments are copied.
+	From pb.FragmentStore
+	// To is the FragmentStore to which Fragments are copied.
+	To pb.FragmentStore
+	// BytesPerSecond bounds the average rate at which Fragment content is
+	// read from the From store. If zero, the rate is unlimited.
+	BytesPerSecond int64
+}
+
+// MigrateReport is the result of Migrate.
+type MigrateReport struct {
+	// Copied Fragments, as persisted to the To store.
+	Copied []pb.Fragment
+	// Skipped Fragments of the From store were already present in the To store,
+	// such as Fragments copied by a prior, interrupted Migrate.
+	Skipped []pb.Fragment
+	// Bytes of Fragment content which were copied.
+	Bytes int64
+}
+
+// Migrate copies the persisted Fragments of a journal from one FragmentStore
+// to another, such as when moving a journal between cloud providers.
+// Fragments are copied in offset order, and content of each is verified
+// against its Fragment SHA1 sum before it's persisted. Fragments already
+// present in the To store are skipped, so an interrupted Migrate may
+// simply be run again to resume. The From store is never modified.
+//
+// Migrate doesn't modify the JournalSpec, and the journal may continue to be
+// appended to and read during migration. A migration is typically:
+//
+//   - Update the JournalSpec Fragment.Stores to place the To store first,
+//     and to retain the From store. New Fragments are then persisted to the
+//     To store, while brokers list and serve Fragments of both stores. A
+//     Fragment present in both stores is indexed only once.
+//   - Run Migrate, repeating it if interrupted.
+//   - Update the JournalSpec to remove the From store.
+//
+// Fragments persisted to the From store while Migrate runs, such as by
+// brokers which haven't yet observed the JournalSpec update, are also copied:
+// Migrate re-lists the From store until a listing has no Fragments to copy.
+func Migrate(ctx context.Context, args MigrateArgs) (MigrateReport, error) {
+	var out MigrateReport
+
+	if DisableStores {
+		return MigrateReport{}, fmt.Errorf("fragment stores are disabled")
+	} else if err := args.From.Validate(); err != nil {
+		return MigrateReport{}, fmt.Errorf("From: %w", err)
+	} else if err = args.To.Validate(); err != nil {
+		return MigrateReport{}, fmt.Errorf("To: %w", err)
+	} else if args.From == args.To {
+		return MigrateReport{}, fmt.Errorf("From and To are the same store")
+	}
+
+	var present = make(map[migrateKey]struct{})
+	var limiter = newRateLimiter(args.BytesPerSecond)
+
+	var err = List(ctx, args.To, args.Journal, func(f pb.Fragment) {
+		present[migrateKey{f.Begin, f.End, f.Sum}] = struct{}{}
+	})
+	if err != nil {
+		return MigrateReport{}, fmt.Errorf("listing store %s: %w", args.To, err)
+	}
+
+	for pass := 0; true; pass++ {
+		var listed []pb.Fragment
+		if err = List(ctx, args.From, args.Journal, func(f pb.Fragment) {
+			listed = append(listed, f)
+		}); err != nil {
+			return out, fmt.Errorf("listing store %s: %w", args.From, err)
+		}
+		sort.Slice(listed, func(i, j int) bool {
+			if listed[i].Begin != listed[j].Begin {
+				return listed[i].Begin < listed[j].Begin
+			}
+			return listed[i].End < listed[j].End
+		})
+
+		var copied int
+		for _, f := range listed {
+			var key = migrateKey{f.Begin, f.End, f.Sum}
+
+			if _, ok := present[key]; ok {
+				if pass == 0 {
+					out.Skipped = append(out.Skipped, f)
+				}
+				continue
+			}
+
+			var to, err = migrateFragment(ctx, f, args.To, limiter)
+			if err != nil {
+				return out, fmt.Errorf("copying fragment %s: %w", f.ContentPath(), err)
+			}
+			present[key] = struct{}{}
+			out.Copied = append(out.Copied, to)
+			out.Bytes += f.ContentLength()
+			copied++
+
+			log.WithFields(log.Fields{
+				"journal":  args.Journal,
+				"fragment": f.ContentName(),
+				"from":     args.From,
+				"to":       args.To,
+			}).Debug("migrated fragment")
+		}
+
+		if copied == 0 {
+			break
+		}
+	}
+	return out, nil
+}
+
+// migrateFragment copies Fragment |f| to FragmentStore |to|, returning the
+// Fragment as persisted to |to|.
+func migrateFragment(ctx context.Context, f pb.Fragment, to pb.FragmentStore, limiter *rateLimiter) (pb.Fragment, error) {
+	var spool = Spool{Fragment: Fragment{Fragment: f}}
+	spool.BackingStore = to
+
+	var err error
+	if spool.File, err = newSpoolFile(); err != nil {
+		return pb.Fragment{}, fmt.Errorf("creating spool file: %w", err)
+	}
+	defer spool.File.Close()
+
+	// Read and verify decompressed content into the local File.
+	rc, err := Open(ctx, f)
+	if err != nil {
+		return pb.Fragment{}, fmt.Errorf("opening: %w", err)
+	}
+	defer rc.Close()
+
+	dec, err := codecs.NewCodecReader(&rateLimitedReader{ctx: ctx, r: rc, limiter: limiter}, f.CompressionCodec)
+	if err != nil {
+		return pb.Fragment{}, fmt.Errorf("decompressing: %w", err)
+	}
+	var summer = sha1.New()
+
+	if n, err := io.Copy(io.MultiWriter(spool.File, summer), dec); err != nil {
+		return pb.Fragment{}, fmt.Errorf("reading: %w", err)
+	} else if n != f.ContentLength() {
+		return pb.Fragment{}, fmt.Errorf("read %d bytes, but fragment has %d", n, f.ContentLength())
+	} else if sum := pb.SHA1SumFromDigest(summer.Sum(nil)); sum != f.Sum {
+		return pb.Fragment{}, fmt.Errorf("content SHA1 (%x) doesn't match the fragment's (%x)",
+			sum.ToDigest(), f.Sum.ToDigest())
+	} else if err = dec.Close(); err != nil {
+		return pb.Fragment{}, fmt.Errorf("closing decompressor: %w", err)
+	}
+
+	// Re-compress under the Fragment's codec for persisting.
+	if spool.CompressionCodec != pb.CompressionCodec_NONE {
+		spool.finishCompression()
+		defer spool.compressedFile.Close()
+	}
+
+	var ep = to.URL()
+	var b = getBackend(ep.Scheme)
+
+	var timeoutCtx, cancel = context.WithTimeout(ctx, 5*time.Minute)
+	defer cancel()
+
+	var op = startStoreOp(b, "persist", spool.Fragment.Fragment)
+	if err = b.Persist(timeoutCtx, ep, spool); err == nil {
+		storePersistedBytesTotal.WithLabelValues(b.Provider()).Add(float64(spool.ContentLength()))
+	}
+	op.finish(err)
+
+	return spool.Fragment.Fragment, err
+}
+
+// migrateKey identifies a Fragment independent of its store and path.
+type migrateKey struct {
+	begin, end int64
+	sum        pb.SHA1Sum
+}
+
+// rateLimiter paces reads to an average of |rate| bytes per second,
+// measured from its first read.
+type rateLimiter struct {
+	rate  int64
+	start time.Time
+	bytes int64
+}
+
+func newRateLimiter(rate int64) *rateLimiter { return &rateLimiter{rate: rate} }
+
+// wait records that |n| bytes were read, and blocks until the average
+// rate of reads is within the limit.
+func (l *rateLimiter) wait(ctx context.Context, n int) error {
+	if l.rate == 0 || n == 0 {
+		return nil
+	} else if l.start.IsZero() {
+		l.start = time.Now()
+	}
+	l.bytes += int64(n)
+
+	var target = time.Duration(float64(l.bytes) / float64(l.rate) * float64(time.Second))
+	var delay = target - time.Since(l.start)
+
+	if delay <= 0 {
+		return nil
+	}
+	var timer = time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// rateLimitedReader is an io.Reader which waits on a rateLimiter.
+type rateLimitedReader struct {
+	ctx     context.Context
+	r       io.Reader
+	limiter *rateLimiter
+}
+
+func (r *rateLimitedReader) Read(p []byte) (int, error) {
+	var n, err = r.r.Read(p)
+	if err2 := r.limiter.wait(r.ctx, n); err == nil && err2 != nil {
+		err = err2
+	}
+	return n, err
+}
@@ -0,0 +1,106 @@
+package fragment
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	pb "go.gazette.dev/core/broker/protocol"
+)
+
+func TestMigrateBetweenStores(t *testing.T) {
+	var dir = t.TempDir()
+	defer func(s string) { FileSystemStoreRoot = s }(FileSystemStoreRoot)
+	FileSystemStoreRoot = dir
+
+	var (
+		ctx  = context.Background()
+		from = pb.FragmentStore("file:///from/")
+		to   = pb.FragmentStore("file:///to/")
+	)
+	var persist = func(spool Spool, store pb.FragmentStore) {
+		require.NoError(t, Persist(ctx, spool, &pb.JournalSpec{
+			Name:     spool.Journal,
+			Fragment: pb.JournalSpec_Fragment{Stores: []pb.FragmentStore{store}},
+		}))
+	}
+	var list = func(store pb.FragmentStore) (out []pb.Fragment) {
+		require.NoError(t, List(ctx, store, tstRWFoo, func(f pb.Fragment) { out = append(out, f) }))
+		return
+	}
+
+	// Fixtures are compressed Fragments of journal tstRWFoo.
+	var spools = buildSpoolFixtures(t)[:len(tstRWFooData)]
+	var total int64
+	for _, spool := range spools {
+		persist(spool, from)
+		total += spool.ContentLength()
+	}
+	// A prior, interrupted migration copied the second Fragment.
+	// An append during migration persisted a new Fragment to |to|.
+	persist(spools[1], to)
+
+	var appended = NewSpool(tstRWFoo, &testSpoolObserver{})
+	appended.Begin, appended.End = spools[2].End, spools[2].End
+	require.NoError(t, appended.applyContent(&pb.ReplicateRequest{Content: []byte("appended")}))
+	var proposal = appended.Next()
+	require.Equal(t, pb.Status_OK, appended.applyCommit(&pb.ReplicateRequest{
+		Proposal:  &proposal,
+		Registers: new(pb.LabelSet),
+	}, true).Status)
+	persist(appended, to)
+
+	var start = time.Now()
+	var rep, err = Migrate(ctx, MigrateArgs{
+		Journal:        tstRWFoo,
+		From:           from,
+		To:             to,
+		BytesPerSecond: 1000,
+	})
+	require.NoError(t, err)
+
+	require.Len(t, rep.Copied, 2)
+	require.Equal(t, spools[0].Fragment.Fragment.Begin, rep.Copied[0].Begin)
+	require.Equal(t, spools[2].Fragment.Fragment.Begin, rep.Copied[1].Begin)
+	require.Equal(t, to, rep.Copied[0].BackingStore)
+	require.Len(t, rep.Skipped, 1)
+	require.Equal(t, spools[1].Sum, rep.Skipped[0].Sum)
+	require.Equal(t, total-spools[1].ContentLength(), rep.Bytes)
+
+	// Copied content was read at no more than BytesPerSecond. Compressed
+	// content is somewhat smaller than |rep.Bytes|, so allow for it.
+	require.True(t, time.Since(start) >= time.Duration(rep.Bytes/2)*time.Millisecond)
+
+	// Expect the |to| store has each Fragment exactly once, with identical
+	// content, and that together they cover the journal without gaps.
+	var listed = list(to)
+	require.Len(t, listed, len(spools)+1)
+
+	set, err := WalkAllStores(ctx, tstRWFoo, []pb.FragmentStore{to})
+	require.NoError(t, err)
+	require.Len(t, set, len(spools)+1)
+
+	for i, spool := range spools {
+		require.Equal(t, spool.Fragment.Fragment.Begin, set[i].Begin)
+		require.Equal(t, spool.Sum, set[i].Sum)
+		require.Equal(t, tstRWFooData[i], readFrag(t, set[i].Fragment))
+	}
+	require.Equal(t, "appended", readFrag(t, set[len(spools)].Fragment))
+
+	// The |from| store is unmodified.
+	require.Len(t, list(from), len(spools))
+
+	// Resuming a completed migration is a no-op.
+	rep, err = Migrate(ctx, MigrateArgs{Journal: tstRWFoo, From: from, To: to})
+	require.NoError(t, err)
+	require.Empty(t, rep.Copied)
+	require.Len(t, rep.Skipped, len(spools))
+	require.Len(t, list(to), len(spools)+1)
+
+	// Case: invalid arguments.
+	_, err = Migrate(ctx, MigrateArgs{Journal: tstRWFoo, From: from, To: from})
+	require.EqualError(t, err, "From and To are the same store")
+	_, err = Migrate(ctx, MigrateArgs{Journal: tstRWFoo, From: from, To: "invalid"})
+	require.Error(t, err)
+}
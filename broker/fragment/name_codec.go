@@ -0,0 +1,88 @@
+package fragment
+
+import (
+	"fmt"
+	"path"
+	"sync"
+
+	pb "go.gazette.dev/core/broker/protocol"
+)
+
+// NameCodec maps Fragments to and from the names of their objects within a
+// fragment store. Names are relative to the journal's path within the store,
+// and may include a PathPostfix as leading path components.
+//
+// Gazette's own ContentNameCodec is used by default. Other NameCodecs allow
+// brokers to interoperate with external writers which persist fragments
+// directly to the store under their own naming scheme. A NameCodec is
+// registered with RegisterNameCodec, and is selected by a fragment store
+// through its "nameCodec" URL argument, as in:
+//
+//	s3://my-bucket/a/prefix/?nameCodec=my-codec
+//
+// Fragments persisted by brokers are also named by the selected NameCodec,
+// and listed Fragments are later opened by their encoded names, so NameCodecs
+// must round-trip: EncodeName of a parsed Fragment must return its name.
+type NameCodec interface {
+	// EncodeName returns the relative object name of the Fragment. The name
+	// may depend only on the Fragment's Journal, Begin, End, Sum,
+	// CompressionCodec, and PathPostfix, which are known prior to its
+	// persistence.
+	EncodeName(pb.Fragment) string
+	// ParseName returns the Fragment of the journal which is named by the
+	// relative object |name|, or an error if |name| isn't a Fragment of the
+	// codec's naming scheme. At minimum, the returned Fragment must have its
+	// Begin and End offsets and CompressionCodec. An unknown content Sum may
+	// be left zero-valued. ParseName may also return a ModTime, such as a
+	// timestamp encoded by an external writer into the name's PathPostfix,
+	// which takes precedence over the object's modification time.
+	ParseName(journal pb.Journal, name string) (pb.Fragment, error)
+}
+
+// ContentNameCodec is the default NameCodec, which names Fragments by their
+// content-addressed pb.Fragment.ContentName.
+type ContentNameCodec struct{}
+
+// EncodeName returns the PathPostfix and ContentName of the Fragment.
+func (ContentNameCodec) EncodeName(f pb.Fragment) string {
+	return path.Join(f.PathPostfix, f.ContentName())
+}
+
+// ParseName parses a Fragment via pb.ParseFragmentFromRelativePath.
+func (ContentNameCodec) ParseName(journal pb.Journal, name string) (pb.Fragment, error) {
+	return pb.ParseFragmentFromRelativePath(journal, name)
+}
+
+// RegisterNameCodec registers the NameCodec under |name|, for selection by
+// fragment stores having a "nameCodec=|name|" URL argument. It's typically
+// called from an init function. RegisterNameCodec panics if |name| is
+// already registered.
+func RegisterNameCodec(name string, codec NameCodec) {
+	nameCodecs.mu.Lock()
+	defer nameCodecs.mu.Unlock()
+
+	if _, ok := nameCodecs.m[name]; ok {
+		panic(fmt.Sprintf("NameCodec %q is already registered", name))
+	}
+	nameCodecs.m[name] = codec
+}
+
+// lookupNameCodec returns the NameCodec registered under |name|,
+// or ContentNameCodec if |name| is empty.
+func lookupNameCodec(name string) (NameCodec, error) {
+	if name == "" {
+		return ContentNameCodec{}, nil
+	}
+	nameCodecs.mu.RLock()
+	defer nameCodecs.mu.RUnlock()
+
+	if codec, ok := nameCodecs.m[name]; ok {
+		return codec, nil
+	}
+	return nil, fmt.Errorf("NameCodec %q is not registered", name)
+}
+
+var nameCodecs = struct {
+	mu sync.RWMutex
+	m  map[string]NameCodec
+}{m: make(map[string]NameCodec)}
@@ -0,0 +1,129 @@
+package fragment
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	pb "go.gazette.dev/core/broker/protocol"
+)
+
+func TestNameCodecOfExternalWriter(t *testing.T) {
+	var dir, err = ioutil.TempDir("", "name_codec_test")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	defer func(s string) { FileSystemStoreRoot = s }(FileSystemStoreRoot)
+	FileSystemStoreRoot = dir
+
+	var fs = pb.FragmentStore("file:///ext/?nameCodec=test-external")
+	require.NoError(t, fs.Validate())
+
+	// Fixtures of an external writer, and an object which isn't a fragment.
+	var write = func(name, content string) {
+		var path = filepath.Join(dir, "ext", "a", "journal", name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0700))
+		require.NoError(t, ioutil.WriteFile(path, []byte(content), 0600))
+	}
+	write("ts=1700000000/part_0_5.raw", "hello")
+	write("ts=1700000060/part_5_11.raw", " world")
+	write("_SUCCESS", "")
+
+	var frags []pb.Fragment
+	var unknown []string
+	require.NoError(t, ListObjects(context.Background(), fs, "a/journal",
+		func(f pb.Fragment) { frags = append(frags, f) },
+		func(name string, err error) { unknown = append(unknown, name) }))
+
+	// Expect the unparseable name was reported and skipped.
+	require.Equal(t, []string{"_SUCCESS"}, unknown)
+	require.Equal(t, []pb.Fragment{
+		{Journal: "a/journal", Begin: 0, End: 5, CompressionCodec: pb.CompressionCodec_NONE,
+			BackingStore: fs, ModTime: 1700000000, PathPostfix: "ts=1700000000"},
+		{Journal: "a/journal", Begin: 5, End: 11, CompressionCodec: pb.CompressionCodec_NONE,
+			BackingStore: fs, ModTime: 1700000060, PathPostfix: "ts=1700000060"},
+	}, frags)
+
+	// Fragments are opened by their external names.
+	require.Equal(t, "hello", readFrag(t, frags[0]))
+	require.Equal(t, " world", readFrag(t, frags[1]))
+
+	// Fragments persisted by the broker are also named by the NameCodec.
+	var obv testSpoolObserver
+	var spool = NewSpool("a/journal", &obv)
+	spool.Begin, spool.End = 11, 11
+	spool.CompressionCodec = pb.CompressionCodec_NONE
+	require.NoError(t, spool.applyContent(&pb.ReplicateRequest{Content: []byte("!")}))
+	var p = spool.Next()
+	require.Equal(t, pb.Status_OK, spool.applyCommit(&pb.ReplicateRequest{
+		Proposal:  &p,
+		Registers: new(pb.LabelSet),
+	}, true).Status)
+
+	require.NoError(t, Persist(context.Background(), spool, &pb.JournalSpec{
+		Fragment: pb.JournalSpec_Fragment{
+			Stores:              []pb.FragmentStore{fs},
+			PathPostfixTemplate: `ts={{ .Spool.FirstAppendTime.Unix }}`,
+		},
+	}))
+	var name = fmt.Sprintf("ts=%d/part_11_12.raw", spool.FirstAppendTime.Unix())
+	_, err = os.Stat(filepath.Join(dir, "ext", "a", "journal", name))
+	require.NoError(t, err)
+
+	// A store having an unregistered NameCodec fails.
+	_, err = Open(context.Background(), pb.Fragment{
+		Journal:      "a/journal",
+		BackingStore: "file:///ext/?nameCodec=unknown",
+	})
+	require.EqualError(t, err, `NameCodec "unknown" is not registered`)
+
+	// NameCodecs may not be registered twice.
+	require.Panics(t, func() { RegisterNameCodec("test-external", testExternalCodec{}) })
+}
+
+func TestContentNameCodecRoundTrip(t *testing.T) {
+	var f = pb.Fragment{
+		Journal:          "a/journal",
+		Begin:            1234,
+		End:              5678,
+		Sum:              pb.SHA1SumOf("content"),
+		CompressionCodec: pb.CompressionCodec_GZIP,
+		PathPostfix:      "date=2026-01-01",
+	}
+	var codec ContentNameCodec
+	var name = codec.EncodeName(f)
+	require.Equal(t, "date=2026-01-01/"+f.ContentName(), name)
+
+	var out, err = codec.ParseName("a/journal", name)
+	require.NoError(t, err)
+	require.Equal(t, f, out)
+}
+
+// testExternalCodec names uncompressed fragments as "ts={unix}/part_{begin}_{end}.raw",
+// where {unix} is the time of the fragment's first write.
+type testExternalCodec struct{}
+
+func (testExternalCodec) EncodeName(f pb.Fragment) string {
+	return path.Join(f.PathPostfix, fmt.Sprintf("part_%d_%d.raw", f.Begin, f.End))
+}
+
+func (testExternalCodec) ParseName(journal pb.Journal, name string) (pb.Fragment, error) {
+	var f = pb.Fragment{Journal: journal, CompressionCodec: pb.CompressionCodec_NONE}
+	var postfix, base = path.Split(name)
+	f.PathPostfix = strings.TrimSuffix(postfix, "/")
+
+	if _, err := fmt.Sscanf(base, "part_%d_%d.raw", &f.Begin, &f.End); err != nil {
+		return pb.Fragment{}, fmt.Errorf("not a fragment name: %s", name)
+	} else if _, err = fmt.Sscanf(f.PathPostfix, "ts=%d", &f.ModTime); err != nil {
+		return pb.Fragment{}, fmt.Errorf("expected a timestamp: %s", name)
+	}
+	return f, nil
+}
+
+func init() { RegisterNameCodec("test-external", testExternalCodec{}) }
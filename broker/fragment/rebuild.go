@@ -0,0 +1,157 @@
+package fragment
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	pb "go.gazette.dev/core/broker/protocol"
+)
+
+// RebuildArgs are arguments of RebuildIndex.
+type RebuildArgs struct {
+	// Journal of the rebuilt Fragments.
+	Journal pb.Journal
+	// Stores of the Journal, which are listed for Fragments.
+	Stores []pb.FragmentStore
+	// Index of the Journal, which is compared with the rebuilt Fragments.
+	// Optional, unless Apply is set.
+	Index *Index
+	// Apply the rebuilt Fragments to the Index, replacing all of its remote
+	// Fragments. If false, RebuildIndex is read-only.
+	Apply bool
+}
+
+// RebuildReport is the result of RebuildIndex.
+type RebuildReport struct {
+	// Set is the authoritative CoverSet of Fragments listed from the Stores.
+	Set CoverSet
+	// Redundant Fragments were listed from the Stores, but are fully covered
+	// by other Fragments and are not part of the Set.
+	Redundant []pb.Fragment
+	// Overlaps are spans covered by more than one Fragment of the Set.
+	Overlaps []Span
+	// Gaps are spans between Set.BeginOffset and Set.EndOffset which aren't
+	// covered by any Fragment of the Set.
+	Gaps []Span
+	// Unknown objects were listed from the Stores, but aren't valid Fragments.
+	Unknown []UnknownObject
+	// Missing remote Fragments of the Index have content which isn't covered
+	// by the Set, such as a Fragment removed out-of-band from its store.
+	Missing CoverSet
+	// Added Fragments of the Set have content which isn't covered by the Index.
+	Added CoverSet
+	// Applied is true if the Set was applied to the Index.
+	Applied bool
+}
+
+// Span is a [Begin, End) range of journal offsets.
+type Span struct {
+	Begin, End int64
+}
+
+// UnknownObject is a listed object of a FragmentStore which isn't a valid
+// Fragment, such as an object not matching the Fragment naming convention.
+type UnknownObject struct {
+	// Store of the object.
+	Store pb.FragmentStore
+	// Name of the object, relative to the journal's path within the Store.
+	Name string
+	// Err is the reason the object isn't a valid Fragment.
+	Err error
+}
+
+// RebuildIndex lists the Fragments of a journal's stores, and rebuilds the
+// authoritative CoverSet of the journal from store contents. It's intended
+// for operational recovery where an Index has drifted from actual store
+// contents, such as after an out-of-band deletion or a botched migration.
+//
+// The returned RebuildReport details overlaps and gaps of the rebuilt CoverSet,
+// and objects of the stores which couldn't be parsed as Fragments. If an Index
+// is provided, the report also details Fragments missing from or added to the
+// stores, as compared with the Index. RebuildIndex modifies the Index only if
+// RebuildArgs.Apply is set, and stores are never modified.
+func RebuildIndex(ctx context.Context, args RebuildArgs) (RebuildReport, error) {
+	var out RebuildReport
+
+	if DisableStores {
+		return RebuildReport{}, fmt.Errorf("fragment stores are disabled")
+	} else if args.Apply && args.Index == nil {
+		return RebuildReport{}, fmt.Errorf("Apply requires an Index")
+	}
+
+	var listed []pb.Fragment
+	for _, store := range args.Stores {
+		var err = ListObjects(ctx, store, args.Journal,
+			func(f pb.Fragment) { listed = append(listed, f) },
+			func(name string, err error) {
+				out.Unknown = append(out.Unknown, UnknownObject{Store: store, Name: name, Err: err})
+			})
+
+		if err != nil {
+			return RebuildReport{}, fmt.Errorf("listing store %s: %w", store, err)
+		}
+	}
+
+	// Order on offsets, so that CoverSet construction and the
+	// determination of redundant Fragments are stable.
+	sort.SliceStable(listed, func(i, j int) bool {
+		if listed[i].Begin != listed[j].Begin {
+			return listed[i].Begin < listed[j].Begin
+		}
+		return listed[i].End < listed[j].End
+	})
+	for _, f := range listed {
+		out.Set, _ = out.Set.Add(Fragment{Fragment: f})
+	}
+
+	var inSet = make(map[rebuildKey]struct{}, len(out.Set))
+	for _, f := range out.Set {
+		inSet[rebuildKey{f.BackingStore, f.ContentPath()}] = struct{}{}
+	}
+	for _, f := range listed {
+		if _, ok := inSet[rebuildKey{f.BackingStore, f.ContentPath()}]; !ok {
+			out.Redundant = append(out.Redundant, f)
+		}
+	}
+
+	for i := 1; i < len(out.Set); i++ {
+		if prev, cur := out.Set[i-1], out.Set[i]; cur.Begin < prev.End {
+			out.Overlaps = append(out.Overlaps, Span{Begin: cur.Begin, End: prev.End})
+		} else if cur.Begin > prev.End {
+			out.Gaps = append(out.Gaps, Span{Begin: prev.End, End: cur.Begin})
+		}
+	}
+
+	if args.Index == nil {
+		return out, nil
+	}
+
+	var err = args.Index.Inspect(ctx, func(set CoverSet) error {
+		var remote CoverSet
+		for _, f := range set {
+			if f.BackingStore != "" {
+				remote, _ = remote.Add(f)
+			}
+		}
+		out.Missing = CoverSetDifference(remote, out.Set)
+		out.Added = CoverSetDifference(out.Set, set)
+		return nil
+	})
+	if err != nil {
+		return RebuildReport{}, fmt.Errorf("inspecting index: %w", err)
+	}
+
+	if args.Apply {
+		// ReplaceRemote retains and modifies its CoverSet. Pass a copy.
+		args.Index.ReplaceRemote(append(CoverSet(nil), out.Set...))
+		out.Applied = true
+	}
+	return out, nil
+}
+
+// rebuildKey uniquely identifies a listed Fragment.
+type rebuildKey struct {
+	store pb.FragmentStore
+	path  string
+}
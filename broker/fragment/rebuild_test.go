@@ -0,0 +1,116 @@
+package fragment
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	pb "go.gazette.dev/core/broker/protocol"
+)
+
+func TestRebuildIndexFromStore(t *testing.T) {
+	var dir = t.TempDir()
+	defer func(s string) { FileSystemStoreRoot = s }(FileSystemStoreRoot)
+	FileSystemStoreRoot = dir
+
+	var store = pb.FragmentStore("file:///")
+	var mk = func(begin, end int64) pb.Fragment {
+		return pb.Fragment{
+			Journal:          "a/journal",
+			Begin:            begin,
+			End:              end,
+			CompressionCodec: pb.CompressionCodec_NONE,
+			BackingStore:     store,
+		}
+	}
+	var write = func(name string, content string) {
+		var path = filepath.Join(dir, "a/journal", name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0700))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0600))
+	}
+
+	// Fixtures: [150, 300) overlaps [100, 200), [120, 180) is fully covered
+	// by [100, 200), and [300, 400) is a gap.
+	for _, f := range []pb.Fragment{
+		mk(0, 100), mk(100, 200), mk(120, 180), mk(150, 300), mk(400, 500),
+	} {
+		write(f.ContentName(), "content")
+	}
+	// Objects which aren't valid Fragments.
+	write("not-a-fragment.txt", "content")
+	var empty = mk(500, 600)
+	write(empty.ContentName(), "")
+
+	var ctx = context.Background()
+	var rep, err = RebuildIndex(ctx, RebuildArgs{Journal: "a/journal", Stores: []pb.FragmentStore{store}})
+	require.NoError(t, err)
+
+	var offsets = func(set CoverSet) (out []Span) {
+		for _, f := range set {
+			out = append(out, Span{Begin: f.Begin, End: f.End})
+		}
+		return
+	}
+	require.Equal(t, []Span{{0, 100}, {100, 200}, {150, 300}, {400, 500}}, offsets(rep.Set))
+	require.Len(t, rep.Redundant, 1)
+	require.Equal(t, int64(120), rep.Redundant[0].Begin)
+	require.Equal(t, int64(180), rep.Redundant[0].End)
+	require.Equal(t, []Span{{150, 200}}, rep.Overlaps)
+	require.Equal(t, []Span{{300, 400}}, rep.Gaps)
+
+	require.Len(t, rep.Unknown, 2)
+	require.Equal(t, empty.ContentName(), rep.Unknown[0].Name)
+	require.Equal(t, errZeroLengthFragment, rep.Unknown[0].Err)
+	require.Equal(t, "not-a-fragment.txt", rep.Unknown[1].Name)
+	require.Equal(t, store, rep.Unknown[1].Store)
+	require.Error(t, rep.Unknown[1].Err)
+
+	require.Nil(t, rep.Missing)
+	require.Nil(t, rep.Added)
+	require.False(t, rep.Applied)
+
+	// Build an Index which has drifted from the store: it's missing listed
+	// Fragments, and has a remote Fragment removed from the store.
+	// It also has a local Fragment which hasn't been persisted.
+	var index = NewIndex(ctx)
+	index.SpoolCommit(Fragment{Fragment: pb.Fragment{Journal: "a/journal", Begin: 700, End: 800}})
+	index.ReplaceRemote(CoverSet{{Fragment: mk(0, 100)}, {Fragment: mk(600, 700)}})
+
+	var args = RebuildArgs{Journal: "a/journal", Stores: []pb.FragmentStore{store}, Index: index}
+	rep, err = RebuildIndex(ctx, args)
+	require.NoError(t, err)
+	require.Equal(t, []Span{{600, 700}}, offsets(rep.Missing))
+	require.Equal(t, []Span{{100, 200}, {150, 300}, {400, 500}}, offsets(rep.Added))
+	require.False(t, rep.Applied)
+
+	var indexOffsets = func() (out []Span) {
+		require.NoError(t, index.Inspect(ctx, func(set CoverSet) error {
+			out = offsets(set)
+			return nil
+		}))
+		return
+	}
+	// Expect the read-only rebuild didn't modify the Index.
+	require.Equal(t, []Span{{0, 100}, {600, 700}, {700, 800}}, indexOffsets())
+
+	// Apply the rebuild. Remote Fragments are replaced, and local ones retained.
+	args.Apply = true
+	rep, err = RebuildIndex(ctx, args)
+	require.NoError(t, err)
+	require.True(t, rep.Applied)
+	require.Equal(t, []Span{{0, 100}, {100, 200}, {150, 300}, {400, 500}, {700, 800}}, indexOffsets())
+	require.Equal(t, []Span{{0, 100}, {100, 200}, {150, 300}, {400, 500}}, offsets(rep.Set))
+
+	// The Index now matches the store.
+	args.Apply = false
+	rep, err = RebuildIndex(ctx, args)
+	require.NoError(t, err)
+	require.Nil(t, rep.Missing)
+	require.Nil(t, rep.Added)
+
+	// Apply requires an Index.
+	_, err = RebuildIndex(ctx, RebuildArgs{Journal: "a/journal", Apply: true})
+	require.EqualError(t, err, "Apply requires an Index")
+}
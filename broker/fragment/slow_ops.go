@@ -0,0 +1,208 @@
+package fragment
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	pb "go.gazette.dev/core/broker/protocol"
+)
+
+var (
+	// SlowOpThreshold is the duration at or beyond which a store operation is
+	// recorded to the log of slow operations (see SlowOps). If zero, store
+	// operations aren't recorded.
+	SlowOpThreshold = time.Second
+	// SlowOpWindow is the duration for which a recorded slow operation is
+	// retained by the log of slow operations.
+	SlowOpWindow = 10 * time.Minute
+)
+
+// SlowOp is a store operation which took at least SlowOpThreshold.
+type SlowOp struct {
+	// Provider of the store, such as "s3" or "gcs".
+	Provider string
+	// Operation of the store, such as "list", "open", or "persist".
+	Operation string
+	// Journal of the operation.
+	Journal pb.Journal
+	// Object of the operation: the path of an operated Fragment, or
+	// the FragmentStore of a listing.
+	Object string
+	// Bytes is the content length of an operated Fragment, or the total
+	// content length of the Fragments of a listing.
+	Bytes int64
+	// Duration of the operation. Duration of an "open" is the time until its
+	// content may begin to be read, and doesn't include reads of the content.
+	Duration time.Duration
+	// Time at which the operation completed.
+	Time time.Time
+	// Error of the operation, if it failed.
+	Error string `json:",omitempty"`
+}
+
+// SlowOps returns up to |n| of the slowest store operations which completed
+// within the last SlowOpWindow, ordered on descending Duration. The log of
+// slow operations is bounded, and |n| is at most MaxSlowOps.
+func SlowOps(n int) []SlowOp { return slowOps.slowest(n, time.Now(), SlowOpWindow) }
+
+// MaxSlowOps is the maximum number of operations returned by SlowOps.
+const MaxSlowOps = slowOpShardSize
+
+// ServeSlowOps is an http.HandlerFunc which serves SlowOps as JSON.
+// The number of operations may be given by query parameter "n",
+// which defaults to MaxSlowOps.
+func ServeSlowOps(w http.ResponseWriter, r *http.Request) {
+	var n = MaxSlowOps
+	if s := r.URL.Query().Get("n"); s != "" {
+		var err error
+		if n, err = strconv.Atoi(s); err != nil {
+			http.Error(w, "invalid n: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	var ops = SlowOps(n)
+
+	w.Header().Set("Content-Type", "application/json")
+	var enc = json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	if err := enc.Encode(ops); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// storeOp is an in-progress store operation of a Fragment, or a listing of
+// a FragmentStore, which is instrumented and recorded (if slow) as it finishes.
+type storeOp struct {
+	provider  string
+	operation string
+	fragment  pb.Fragment // Operated Fragment, or the Journal & BackingStore of a listing.
+	listed    int64       // Total content length of listed Fragments.
+	started   time.Time
+}
+
+func startStoreOp(b backend, operation string, fragment pb.Fragment) storeOp {
+	return storeOp{
+		provider:  b.Provider(),
+		operation: operation,
+		fragment:  fragment,
+		started:   time.Now(),
+	}
+}
+
+// finish the storeOp with its error.
+func (op *storeOp) finish(err error) {
+	instrumentStoreOp(op.provider, op.operation, err)
+
+	var threshold = SlowOpThreshold
+	if threshold == 0 {
+		return
+	}
+	var now = time.Now()
+	var dur = now.Sub(op.started)
+
+	if dur < threshold {
+		return // Fast path: no synchronization is required.
+	}
+	var slow = SlowOp{
+		Provider:  op.provider,
+		Operation: op.operation,
+		Journal:   op.fragment.Journal,
+		Duration:  dur,
+		Time:      now,
+	}
+	if op.operation == "list" {
+		slow.Object, slow.Bytes = string(op.fragment.BackingStore), op.listed
+	} else {
+		slow.Object, slow.Bytes = op.fragment.ContentPath(), op.fragment.ContentLength()
+	}
+	if err != nil {
+		slow.Error = err.Error()
+	}
+	slowOps.record(slow, SlowOpWindow)
+}
+
+// slowOpLog is a bounded log of slow operations. It's sharded to reduce
+// contention of concurrent records, and each shard retains its slowest
+// operations, so the slowest operations of the log are the slowest of
+// its shards.
+type slowOpLog struct {
+	next   uint32 // Shard of the next record.
+	shards [slowOpShards]slowOpShard
+}
+
+type slowOpShard struct {
+	mu  sync.Mutex
+	ops []SlowOp
+}
+
+// record |op|, evicting operations which completed before |window| or,
+// if the shard is full, its fastest operation.
+func (l *slowOpLog) record(op SlowOp, window time.Duration) {
+	var s = &l.shards[atomic.AddUint32(&l.next, 1)%slowOpShards]
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var ops = s.ops[:0]
+	for _, o := range s.ops {
+		if op.Time.Sub(o.Time) < window {
+			ops = append(ops, o)
+		}
+	}
+	s.ops = ops
+
+	if len(s.ops) < slowOpShardSize {
+		s.ops = append(s.ops, op)
+		return
+	}
+	var fastest int
+	for i := range s.ops {
+		if s.ops[i].Duration < s.ops[fastest].Duration {
+			fastest = i
+		}
+	}
+	if op.Duration > s.ops[fastest].Duration {
+		s.ops[fastest] = op
+	}
+}
+
+// slowest returns up to |n| of the slowest operations which completed
+// within |window| of |now|, ordered on descending Duration.
+func (l *slowOpLog) slowest(n int, now time.Time, window time.Duration) []SlowOp {
+	var out []SlowOp
+	for i := range l.shards {
+		var s = &l.shards[i]
+		s.mu.Lock()
+		for _, o := range s.ops {
+			if now.Sub(o.Time) < window {
+				out = append(out, o)
+			}
+		}
+		s.mu.Unlock()
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Duration > out[j].Duration })
+
+	if n > MaxSlowOps {
+		n = MaxSlowOps
+	}
+	if n < 0 {
+		n = 0
+	}
+	if len(out) > n {
+		out = out[:n]
+	}
+	return out
+}
+
+const (
+	slowOpShards    = 16
+	slowOpShardSize = 32
+)
+
+// slowOps is the log of slow store operations.
+var slowOps slowOpLog
@@ -0,0 +1,136 @@
+package fragment
+
+import (
+	"context"
+	"encoding/json"
+	"math/rand"
+	"net/http/httptest"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	pb "go.gazette.dev/core/broker/protocol"
+)
+
+func TestSlowOpLogRetainsSlowestWithinWindow(t *testing.T) {
+	var l slowOpLog
+	var now = time.Now()
+	var window = time.Minute
+
+	// Record many operations from concurrent goroutines.
+	var all []time.Duration
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+
+	for g := 0; g != 8; g++ {
+		wg.Add(1)
+		go func(seed int64) {
+			defer wg.Done()
+			var rnd = rand.New(rand.NewSource(seed))
+
+			for i := 0; i != 500; i++ {
+				var op = SlowOp{
+					Operation: "open",
+					Duration:  time.Duration(rnd.Int63n(int64(time.Hour))),
+					Time:      now,
+				}
+				l.record(op, window)
+
+				mu.Lock()
+				all = append(all, op.Duration)
+				mu.Unlock()
+			}
+		}(int64(g))
+	}
+	wg.Wait()
+
+	// Expect the slowest operations of the log are the slowest recorded.
+	sort.Slice(all, func(i, j int) bool { return all[i] > all[j] })
+
+	var ops = l.slowest(10, now, window)
+	require.Len(t, ops, 10)
+	for i := range ops {
+		require.Equal(t, all[i], ops[i].Duration)
+	}
+	// Requests are bounded by MaxSlowOps.
+	require.Len(t, l.slowest(1000, now, window), MaxSlowOps)
+	require.Len(t, l.slowest(-1, now, window), 0)
+
+	// Operations outside of the window are excluded, and evicted by later records.
+	var later = now.Add(window)
+	require.Len(t, l.slowest(10, later, window), 0)
+
+	l.record(SlowOp{Operation: "list", Duration: time.Second, Time: later}, window)
+	ops = l.slowest(10, later, window)
+	require.Len(t, ops, 1)
+	require.Equal(t, "list", ops[0].Operation)
+
+	var count int
+	for i := range l.shards {
+		count += len(l.shards[i].ops)
+	}
+	require.True(t, count <= slowOpShards*slowOpShardSize, count)
+}
+
+func TestSlowOpsOfStoreOperations(t *testing.T) {
+	defer func(s string) { FileSystemStoreRoot = s }(FileSystemStoreRoot)
+	FileSystemStoreRoot = t.TempDir()
+
+	defer func(d time.Duration) { SlowOpThreshold = d }(SlowOpThreshold)
+	slowOps = slowOpLog{}
+
+	var ctx = context.Background()
+	var store = pb.FragmentStore("file:///root/")
+	var spool = buildSpoolFixtures(t)[0]
+
+	// Operations faster than the threshold aren't recorded.
+	SlowOpThreshold = time.Hour
+	require.NoError(t, Persist(ctx, spool, &pb.JournalSpec{
+		Name:     spool.Journal,
+		Fragment: pb.JournalSpec_Fragment{Stores: []pb.FragmentStore{store}},
+	}))
+	require.Empty(t, SlowOps(MaxSlowOps))
+
+	// All operations take at least a nanosecond.
+	SlowOpThreshold = time.Nanosecond
+
+	var listed []pb.Fragment
+	require.NoError(t, List(ctx, store, spool.Journal, func(f pb.Fragment) { listed = append(listed, f) }))
+	require.Len(t, listed, 1)
+
+	_, err := Open(ctx, pb.Fragment{Journal: spool.Journal, Begin: 1, End: 2,
+		CompressionCodec: pb.CompressionCodec_NONE, BackingStore: store})
+	require.Error(t, err)
+
+	var ops = SlowOps(MaxSlowOps)
+	require.Len(t, ops, 2)
+
+	var byOp = make(map[string]SlowOp)
+	for _, op := range ops {
+		byOp[op.Operation] = op
+		require.Equal(t, "fs", op.Provider)
+		require.Equal(t, spool.Journal, op.Journal)
+		require.True(t, op.Duration > 0)
+	}
+	require.Equal(t, string(store), byOp["list"].Object)
+	require.Equal(t, spool.ContentLength(), byOp["list"].Bytes)
+	require.Empty(t, byOp["list"].Error)
+	require.Equal(t, int64(1), byOp["open"].Bytes)
+	require.NotEmpty(t, byOp["open"].Error)
+
+	// Expect operations are served as JSON.
+	var w = httptest.NewRecorder()
+	ServeSlowOps(w, httptest.NewRequest("GET", "/debug/store-slow-ops?n=1", nil))
+
+	var served []SlowOp
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &served))
+	require.Len(t, served, 1)
+	require.Equal(t, ops[0].Operation, served[0].Operation)
+	require.Equal(t, ops[0].Duration, served[0].Duration)
+
+	w = httptest.NewRecorder()
+	ServeSlowOps(w, httptest.NewRequest("GET", "/debug/store-slow-ops?n=foo", nil))
+	require.Equal(t, 400, w.Code)
+}
@@ -7,6 +7,7 @@ import (
 	"hash"
 	"io"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	log "github.com/sirupsen/logrus"
@@ -25,6 +26,11 @@ type Spool struct {
 	FirstAppendTime time.Time
 	// Registers of the journal.
 	Registers pb.LabelSet
+	// CompressionLevel of the Fragment.CompressionCodec. If zero, the
+	// codec's default level is used.
+	CompressionLevel int32
+	// Tags applied to the persisted Fragment object (see labels.FragmentTagPrefix).
+	Tags map[string]string
 
 	// Compressed form of the Fragment, compressed under Fragment.CompressionCodec.
 	compressedFile File
@@ -96,6 +102,15 @@ func (s *Spool) Next() pb.Fragment {
 	return f
 }
 
+// SpoolUsage returns the number of content bytes held by all open Spools of
+// the process, and the number of open Spools which hold content. A Spool is
+// open until it's completed, at which point its content is handed off to its
+// SpoolObserver for persistence. Content includes uncommitted bytes of an
+// in-progress append, but not the compressed form of the Spool's content.
+func SpoolUsage() (bytes, spools int64) {
+	return atomic.LoadInt64(&spoolUsageBytes), atomic.LoadInt64(&spoolUsageSpools)
+}
+
 // String returns a debugging representation of the Spool.
 func (s Spool) String() string {
 	return fmt.Sprintf("Spool<Fragment: %s, Registers: %s, delta: %d>", &s.Fragment, &s.Registers, s.delta)
@@ -122,6 +137,8 @@ func (s *Spool) applyCommit(r *pb.ReplicateRequest, primary bool) pb.ReplicateRe
 		if s.compressor != nil {
 			s.finishCompression()
 		}
+		accountSpoolUsage(s.ContentLength()+s.delta, 0)
+
 		if s.ContentLength() != 0 {
 			spoolCompletedTotal.Inc()
 			s.observer.SpoolComplete(*s, primary)
@@ -139,10 +156,11 @@ func (s *Spool) applyCommit(r *pb.ReplicateRequest, primary bool) pb.ReplicateRe
 					CompressionCodec: r.Proposal.CompressionCodec,
 				},
 			},
-			Registers: s.Registers,
-			summer:    sha1.New(),
-			sumState:  zeroedSHA1State,
-			observer:  s.observer,
+			Registers:        s.Registers,
+			CompressionLevel: s.CompressionLevel,
+			summer:           sha1.New(),
+			sumState:         zeroedSHA1State,
+			observer:         s.observer,
 		}
 	}
 
@@ -155,6 +173,7 @@ func (s *Spool) applyCommit(r *pb.ReplicateRequest, primary bool) pb.ReplicateRe
 		if s.delta != 0 {
 			spoolRollbacksTotal.Inc()
 			spoolRollbackBytesTotal.Add(float64(s.delta))
+			accountSpoolUsage(s.ContentLength()+s.delta, s.ContentLength())
 		}
 		s.delta = 0
 		s.restoreSumState()
@@ -229,6 +248,7 @@ func (s *Spool) applyContent(r *pb.ReplicateRequest) error {
 	if _, err := s.summer.Write(r.Content); err != nil {
 		panic("SHA1.Write cannot fail: " + err.Error())
 	}
+	accountSpoolUsage(s.ContentLength()+s.delta, s.ContentLength()+s.delta+int64(len(r.Content)))
 	s.delta += int64(len(r.Content))
 
 	return nil
@@ -275,7 +295,7 @@ func (s *Spool) compressThrough(end int64) {
 			err = fmt.Errorf("seeking compressedFile to start: %s", err)
 			continue
 		}
-		if s.compressor, err = codecs.NewCodecWriter(s.compressedFile, s.CompressionCodec); err != nil {
+		if s.compressor, err = newCompressor(s.compressedFile, s.CompressionCodec, int(s.CompressionLevel)); err != nil {
 			err = fmt.Errorf("initializing compressor: %s", err)
 			continue
 		}
@@ -327,6 +347,18 @@ func (s *Spool) finishCompression() {
 	}
 }
 
+// accountSpoolUsage updates SpoolUsage to reflect that an open Spool's
+// content has changed from |before| to |after| bytes.
+func accountSpoolUsage(before, after int64) {
+	atomic.AddInt64(&spoolUsageBytes, after-before)
+
+	if before == 0 && after != 0 {
+		atomic.AddInt64(&spoolUsageSpools, 1)
+	} else if before != 0 && after == 0 {
+		atomic.AddInt64(&spoolUsageSpools, -1)
+	}
+}
+
 // saveSumState marshals internal state of |summer| into |sumState|.
 func (s *Spool) saveSumState() {
 	if state, err := s.summer.(encoding.BinaryMarshaler).MarshalBinary(); err != nil {
@@ -347,4 +379,8 @@ var (
 	zeroedSHA1State, _ = sha1.New().(encoding.BinaryMarshaler).MarshalBinary()
 	spoolRetryInterval = time.Second * 5
 	bufferPool         = sync.Pool{New: func() interface{} { return make([]byte, 32*1024) }}
+	newCompressor      = codecs.NewCodecWriterLevel
+
+	// Content bytes and count of open Spools having content. See SpoolUsage.
+	spoolUsageBytes, spoolUsageSpools int64
 )
@@ -119,6 +119,65 @@ func (s *SpoolSuite) TestCompressionNotPrimary(c *gc.C) {
 		gc.Equals, "an initial write final write")
 }
 
+func (s *SpoolSuite) TestCompressionLevel(c *gc.C) {
+	var levels []int
+	defer func(fn func(io.Writer, pb.CompressionCodec, int) (codecs.Compressor, error)) { newCompressor = fn }(newCompressor)
+
+	newCompressor = func(w io.Writer, codec pb.CompressionCodec, level int) (codecs.Compressor, error) {
+		levels = append(levels, level)
+		return codecs.NewCodecWriterLevel(w, codec, level)
+	}
+
+	for _, primary := range []bool{true, false} {
+		var obv testSpoolObserver
+		var spool = NewSpool("a/journal", &obv)
+		spool.CompressionLevel = 9
+
+		// CompressionLevel is retained as the Spool rolls forward.
+		runReplicateSequence(c, &spool, pb.CompressionCodec_GZIP, primary)
+		c.Check(obv.completes[0].CompressionLevel, gc.Equals, int32(9))
+
+		obv.completes[0].finishCompression()
+		c.Check(contentString(c, obv.completes[0], pb.CompressionCodec_GZIP),
+			gc.Equals, "an initial write final write")
+	}
+	// Expect the level reached the compressor, whether incrementally
+	// compressed as primary or compressed on demand.
+	c.Check(levels, gc.DeepEquals, []int{9, 9})
+}
+
+func (s *SpoolSuite) TestUsageAccounting(c *gc.C) {
+	var obv testSpoolObserver
+	var spool = NewSpool("a/journal", &obv)
+	var bytes0, spools0 = SpoolUsage()
+
+	var expect = func(bytes, spools int64) {
+		var b, n = SpoolUsage()
+		c.Check(b-bytes0, gc.Equals, bytes)
+		c.Check(n-spools0, gc.Equals, spools)
+	}
+
+	// Content is accounted as it's written, and remains so on commit.
+	c.Check(spool.applyContent(&pb.ReplicateRequest{Content: []byte("some"), ContentDelta: 0}), gc.IsNil)
+	c.Check(spool.applyContent(&pb.ReplicateRequest{Content: []byte(" content"), ContentDelta: 4}), gc.IsNil)
+	expect(12, 1)
+	spool.MustApply(newProposal(spool.Next(), regEmpty))
+	expect(12, 1)
+
+	// Rolled-back content is released.
+	c.Check(spool.applyContent(&pb.ReplicateRequest{Content: []byte("more"), ContentDelta: 0}), gc.IsNil)
+	expect(16, 1)
+	spool.MustApply(newProposal(spool.Fragment.Fragment, regEmpty))
+	expect(12, 1)
+
+	// Content of a completed Spool is released.
+	var next = spool.Fragment.Fragment
+	next.Begin, next.Sum = next.End, pb.SHA1Sum{}
+	spool.MustApply(newProposal(next, regEmpty))
+	expect(0, 0)
+	c.Check(obv.completes, gc.HasLen, 1)
+}
+
 func (s *SpoolSuite) TestRejectRollBeforeCurrentEnd(c *gc.C) {
 	var obv testSpoolObserver
 	var spool = NewSpool("a/journal", &obv)
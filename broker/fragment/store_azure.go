@@ -69,7 +69,7 @@ func (a *azureBackend) SignGet(endpoint *url.URL, fragment pb.Fragment, d time.D
 	if err != nil {
 		return "", err
 	}
-	blobName := cfg.rewritePath(cfg.prefix, fragment.ContentPath())
+	blobName := cfg.fragmentPath(cfg.prefix, fragment)
 
 	udc, err := a.getUserDelegationCredential(endpoint)
 	if err != nil {
@@ -105,7 +105,7 @@ func (a *azureBackend) Exists(ctx context.Context, ep *url.URL, fragment pb.Frag
 	if err != nil {
 		return false, err
 	}
-	blobURL, err := a.buildBlobURL(cfg, client, fragment.ContentPath())
+	blobURL, err := a.buildBlobURL(cfg, client, fragment)
 	if err != nil {
 		return false, err
 	}
@@ -127,12 +127,14 @@ func (a *azureBackend) Open(ctx context.Context, ep *url.URL, fragment pb.Fragme
 	if err != nil {
 		return nil, err
 	}
-	blobURL, err := a.buildBlobURL(cfg, client, fragment.ContentPath())
+	blobURL, err := a.buildBlobURL(cfg, client, fragment)
 	if err != nil {
 		return nil, err
 	}
 	download, err := blobURL.Download(ctx, 0, azblob.CountToEnd, azblob.BlobAccessConditions{}, false, azblob.ClientProvidedKeyOptions{})
-	if err != nil {
+	if storageErr, ok := err.(azblob.StorageError); ok && storageErr.ServiceCode() == azblob.ServiceCodeBlobArchived {
+		return nil, needsRestoreError(fragment, err)
+	} else if err != nil {
 		return nil, err
 	}
 	return download.Body(azblob.RetryReaderOptions{}), nil
@@ -143,7 +145,7 @@ func (a *azureBackend) Persist(ctx context.Context, ep *url.URL, spool Spool) er
 	if err != nil {
 		return err
 	}
-	blobURL, err := a.buildBlobURL(cfg, client, spool.ContentPath())
+	blobURL, err := a.buildBlobURL(cfg, client, spool.Fragment.Fragment)
 	if err != nil {
 		return err
 	}
@@ -157,11 +159,11 @@ func (a *azureBackend) Persist(ctx context.Context, ep *url.URL, spool Spool) er
 	} else {
 		body = io.NewSectionReader(spool.File, 0, spool.ContentLength())
 	}
-	_, err = blobURL.Upload(ctx, body, headers, azblob.Metadata{}, azblob.BlobAccessConditions{}, azblob.DefaultAccessTier, azblob.BlobTagsMap{}, azblob.ClientProvidedKeyOptions{}, azblob.ImmutabilityPolicyOptions{})
+	_, err = blobURL.Upload(ctx, body, headers, azblob.Metadata{}, azblob.BlobAccessConditions{}, azblob.DefaultAccessTier, azblob.BlobTagsMap(spool.Tags), azblob.ClientProvidedKeyOptions{}, azblob.ImmutabilityPolicyOptions{})
 	return err
 }
 
-func (a *azureBackend) List(ctx context.Context, store pb.FragmentStore, ep *url.URL, journal pb.Journal, callback func(pb.Fragment)) error {
+func (a *azureBackend) List(ctx context.Context, store pb.FragmentStore, ep *url.URL, journal pb.Journal, callback func(pb.Fragment), unknown func(string, error)) error {
 	cfg, client, err := a.getAzurePipeline(ep)
 	if err != nil {
 		return err
@@ -180,19 +182,23 @@ func (a *azureBackend) List(ctx context.Context, store pb.FragmentStore, ep *url
 		for _, blob := range segmentList.Segment.BlobItems {
 			if strings.HasSuffix(blob.Name, "/") {
 				//Ignore directory-like objects, usually created by mounting buckets with a FUSE driver.
-			} else if frag, err := pb.ParseFragmentFromRelativePath(journal, blob.Name[len(*segmentList.Prefix):]); err != nil {
+			} else if frag, err := cfg.parseFragment(journal, blob.Name[len(*segmentList.Prefix):]); err != nil {
 				log.WithFields(log.Fields{
 					"storageAccountName": cfg.storageAccountName,
 					"name":               blob.Name,
 					"err":                err,
 				}).Warning("parsing fragment")
+				unknown(blob.Name[len(*segmentList.Prefix):], err)
 			} else if *(blob.Properties.ContentLength) == 0 && frag.ContentLength() > 0 {
 				log.WithFields(log.Fields{
 					"storageAccountName": cfg.storageAccountName,
 					"name":               blob.Name,
 				}).Warning("zero-length fragment")
+				unknown(blob.Name[len(*segmentList.Prefix):], errZeroLengthFragment)
 			} else {
-				frag.ModTime = blob.Properties.LastModified.Unix()
+				if frag.ModTime == 0 { // Not set by the NameCodec.
+					frag.ModTime = blob.Properties.LastModified.Unix()
+				}
 				frag.BackingStore = store
 				callback(frag)
 			}
@@ -207,7 +213,7 @@ func (a *azureBackend) Remove(ctx context.Context, fragment pb.Fragment) error {
 	if err != nil {
 		return err
 	}
-	blobURL, err := a.buildBlobURL(cfg, client, fragment.ContentPath())
+	blobURL, err := a.buildBlobURL(cfg, client, fragment)
 	if err != nil {
 		return err
 	}
@@ -404,8 +410,8 @@ func (a *azureBackend) getAzurePipeline(ep *url.URL) (cfg azureStoreConfig, clie
 	return cfg, client, nil
 }
 
-func (a *azureBackend) buildBlobURL(cfg azureStoreConfig, client pipeline.Pipeline, path string) (*azblob.BlockBlobURL, error) {
-	u, err := url.Parse(fmt.Sprint(cfg.containerURL(), "/", cfg.rewritePath(cfg.prefix, path)))
+func (a *azureBackend) buildBlobURL(cfg azureStoreConfig, client pipeline.Pipeline, fragment pb.Fragment) (*azblob.BlockBlobURL, error) {
+	u, err := url.Parse(fmt.Sprint(cfg.containerURL(), "/", cfg.fragmentPath(cfg.prefix, fragment)))
 	if err != nil {
 		return nil, err
 	}
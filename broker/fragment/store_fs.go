@@ -35,7 +35,7 @@ func (s fsBackend) SignGet(ep *url.URL, fragment pb.Fragment, _ time.Duration) (
 		return "", err
 	}
 
-	return "file://" + cfg.rewritePath(ep.Path, fragment.ContentPath()), nil
+	return "file://" + cfg.fragmentPath(ep.Path, fragment), nil
 }
 
 func (s fsBackend) Exists(_ context.Context, ep *url.URL, fragment pb.Fragment) (bool, error) {
@@ -44,7 +44,7 @@ func (s fsBackend) Exists(_ context.Context, ep *url.URL, fragment pb.Fragment)
 		return false, err
 	}
 
-	var path = filepath.Join(FileSystemStoreRoot, filepath.FromSlash(cfg.rewritePath(ep.Path, fragment.ContentPath())))
+	var path = filepath.Join(FileSystemStoreRoot, filepath.FromSlash(cfg.fragmentPath(ep.Path, fragment)))
 
 	if _, err := os.Stat(path); os.IsNotExist(err) {
 		return false, nil
@@ -61,7 +61,7 @@ func (s fsBackend) Open(_ context.Context, ep *url.URL, fragment pb.Fragment) (i
 		return nil, err
 	}
 
-	var path = filepath.Join(FileSystemStoreRoot, filepath.FromSlash(cfg.rewritePath(ep.Path, fragment.ContentPath())))
+	var path = filepath.Join(FileSystemStoreRoot, filepath.FromSlash(cfg.fragmentPath(ep.Path, fragment)))
 	return os.Open(path)
 }
 
@@ -71,7 +71,7 @@ func (s fsBackend) Persist(_ context.Context, ep *url.URL, spool Spool) error {
 		return err
 	}
 
-	var path = filepath.Join(FileSystemStoreRoot, filepath.FromSlash(cfg.rewritePath(ep.Path, spool.ContentPath())))
+	var path = filepath.Join(FileSystemStoreRoot, filepath.FromSlash(cfg.fragmentPath(ep.Path, spool.Fragment.Fragment)))
 
 	// Create the fragment's directory, if not already present.
 	if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
@@ -108,7 +108,7 @@ func (s fsBackend) Persist(_ context.Context, ep *url.URL, spool Spool) error {
 	return err
 }
 
-func (s fsBackend) List(_ context.Context, store pb.FragmentStore, ep *url.URL, journal pb.Journal, callback func(pb.Fragment)) error {
+func (s fsBackend) List(_ context.Context, store pb.FragmentStore, ep *url.URL, journal pb.Journal, callback func(pb.Fragment), unknown func(string, error)) error {
 	var cfg, err = s.fsCfg(ep)
 	if err != nil {
 		return err
@@ -133,20 +133,24 @@ func (s fsBackend) List(_ context.Context, store pb.FragmentStore, ep *url.URL,
 				return nil
 			}
 
-			frag, err := pb.ParseFragmentFromRelativePath(journal, filepath.ToSlash(name))
+			frag, err := cfg.parseFragment(journal, filepath.ToSlash(name))
 			if err != nil {
 				log.WithFields(log.Fields{
 					"journal": journal,
 					"name":    name,
 					"err":     err,
 				}).Warning("parsing fragment")
+				unknown(filepath.ToSlash(name), err)
 			} else if info.Size() == 0 && frag.ContentLength() > 0 {
 				log.WithFields(log.Fields{
 					"journal": journal,
 					"name":    name,
 				}).Warning("zero-length fragment")
+				unknown(filepath.ToSlash(name), errZeroLengthFragment)
 			} else {
-				frag.ModTime = info.ModTime().Unix()
+				if frag.ModTime == 0 { // Not set by the NameCodec.
+					frag.ModTime = info.ModTime().Unix()
+				}
 				frag.BackingStore = store
 				callback(frag)
 			}
@@ -161,7 +165,7 @@ func (s fsBackend) Remove(_ context.Context, fragment pb.Fragment) error {
 		return err
 	}
 
-	var path = filepath.Join(FileSystemStoreRoot, filepath.FromSlash(cfg.rewritePath(ep.Path, fragment.ContentPath())))
+	var path = filepath.Join(FileSystemStoreRoot, filepath.FromSlash(cfg.fragmentPath(ep.Path, fragment)))
 	return os.Remove(path)
 }
 
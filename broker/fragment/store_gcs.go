@@ -24,6 +24,9 @@ type GSStoreConfig struct {
 	prefix string
 
 	RewriterConfig
+	// Storage class applied when persisting new fragments (eg, "COLDLINE").
+	// By default, the bucket's default storage class is used.
+	StorageClass string
 }
 
 type gcsBackend struct {
@@ -44,7 +47,7 @@ func (s *gcsBackend) SignGet(ep *url.URL, fragment pb.Fragment, d time.Duration)
 	opts.Method = "GET"
 	opts.Expires = time.Now().Add(d)
 
-	return storage.SignedURL(cfg.bucket, cfg.rewritePath(cfg.prefix, fragment.ContentPath()), &opts)
+	return storage.SignedURL(cfg.bucket, cfg.fragmentPath(cfg.prefix, fragment), &opts)
 }
 
 func (s *gcsBackend) Exists(ctx context.Context, ep *url.URL, fragment pb.Fragment) (exists bool, err error) {
@@ -52,7 +55,7 @@ func (s *gcsBackend) Exists(ctx context.Context, ep *url.URL, fragment pb.Fragme
 	if err != nil {
 		return false, err
 	}
-	_, err = client.Bucket(cfg.bucket).Object(cfg.rewritePath(cfg.prefix, fragment.ContentPath())).Attrs(ctx)
+	_, err = client.Bucket(cfg.bucket).Object(cfg.fragmentPath(cfg.prefix, fragment)).Attrs(ctx)
 	if err == nil {
 		exists = true
 	} else if err == storage.ErrObjectNotExist {
@@ -66,7 +69,7 @@ func (s *gcsBackend) Open(ctx context.Context, ep *url.URL, fragment pb.Fragment
 	if err != nil {
 		return nil, err
 	}
-	return client.Bucket(cfg.bucket).Object(cfg.rewritePath(cfg.prefix, fragment.ContentPath())).NewReader(ctx)
+	return client.Bucket(cfg.bucket).Object(cfg.fragmentPath(cfg.prefix, fragment)).NewReader(ctx)
 }
 
 func (s *gcsBackend) Persist(ctx context.Context, ep *url.URL, spool Spool) error {
@@ -75,7 +78,11 @@ func (s *gcsBackend) Persist(ctx context.Context, ep *url.URL, spool Spool) erro
 		return err
 	}
 	ctx, cancel := context.WithCancel(ctx)
-	var wc = client.Bucket(cfg.bucket).Object(cfg.rewritePath(cfg.prefix, spool.ContentPath())).NewWriter(ctx)
+	var wc = client.Bucket(cfg.bucket).Object(cfg.fragmentPath(cfg.prefix, spool.Fragment.Fragment)).NewWriter(ctx)
+
+	// GCS doesn't support object tags. Apply them as custom metadata.
+	wc.Metadata = spool.Tags
+	wc.StorageClass = cfg.StorageClass
 
 	if spool.CompressionCodec == pb.CompressionCodec_GZIP_OFFLOAD_DECOMPRESSION {
 		wc.ContentEncoding = "gzip"
@@ -93,7 +100,7 @@ func (s *gcsBackend) Persist(ctx context.Context, ep *url.URL, spool Spool) erro
 	return err
 }
 
-func (s *gcsBackend) List(ctx context.Context, store pb.FragmentStore, ep *url.URL, journal pb.Journal, callback func(pb.Fragment)) error {
+func (s *gcsBackend) List(ctx context.Context, store pb.FragmentStore, ep *url.URL, journal pb.Journal, callback func(pb.Fragment), unknown func(string, error)) error {
 	var cfg, client, _, err = s.gcsClient(ep)
 	if err != nil {
 		return err
@@ -108,12 +115,16 @@ func (s *gcsBackend) List(ctx context.Context, store pb.FragmentStore, ep *url.U
 	for obj, err = it.Next(); err == nil; obj, err = it.Next() {
 		if strings.HasSuffix(obj.Name, "/") {
 			// Ignore directory-like objects, usually created by mounting buckets with a FUSE driver.
-		} else if frag, err := pb.ParseFragmentFromRelativePath(journal, obj.Name[len(q.Prefix):]); err != nil {
+		} else if frag, err := cfg.parseFragment(journal, obj.Name[len(q.Prefix):]); err != nil {
 			log.WithFields(log.Fields{"bucket": cfg.bucket, "name": obj.Name, "err": err}).Warning("parsing fragment")
+			unknown(obj.Name[len(q.Prefix):], err)
 		} else if obj.Size == 0 && frag.ContentLength() > 0 {
 			log.WithFields(log.Fields{"bucket": cfg.bucket, "name": obj.Name}).Warning("zero-length fragment")
+			unknown(obj.Name[len(q.Prefix):], errZeroLengthFragment)
 		} else {
-			frag.ModTime = obj.Updated.Unix()
+			if frag.ModTime == 0 { // Not set by the NameCodec.
+				frag.ModTime = obj.Updated.Unix()
+			}
 			frag.BackingStore = store
 			callback(frag)
 		}
@@ -129,7 +140,7 @@ func (s *gcsBackend) Remove(ctx context.Context, fragment pb.Fragment) error {
 	if err != nil {
 		return err
 	}
-	return client.Bucket(cfg.bucket).Object(cfg.rewritePath(cfg.prefix, fragment.ContentPath())).Delete(ctx)
+	return client.Bucket(cfg.bucket).Object(cfg.fragmentPath(cfg.prefix, fragment)).Delete(ctx)
 }
 
 func (s *gcsBackend) gcsClient(ep *url.URL) (cfg GSStoreConfig, client *storage.Client, opts storage.SignedURLOptions, err error) {
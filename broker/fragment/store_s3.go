@@ -69,7 +69,7 @@ func (s *s3Backend) SignGet(ep *url.URL, fragment pb.Fragment, d time.Duration)
 
 	var getObj = s3.GetObjectInput{
 		Bucket: aws.String(cfg.bucket),
-		Key:    aws.String(cfg.rewritePath(cfg.prefix, fragment.ContentPath())),
+		Key:    aws.String(cfg.fragmentPath(cfg.prefix, fragment)),
 	}
 	var req, _ = client.GetObjectRequest(&getObj)
 	return req.Presign(d)
@@ -82,7 +82,7 @@ func (s *s3Backend) Exists(ctx context.Context, ep *url.URL, fragment pb.Fragmen
 	}
 	var headObj = s3.HeadObjectInput{
 		Bucket: aws.String(cfg.bucket),
-		Key:    aws.String(cfg.rewritePath(cfg.prefix, fragment.ContentPath())),
+		Key:    aws.String(cfg.fragmentPath(cfg.prefix, fragment)),
 	}
 	if _, err = client.HeadObjectWithContext(ctx, &headObj); err == nil {
 		return true, nil
@@ -102,10 +102,13 @@ func (s *s3Backend) Open(ctx context.Context, ep *url.URL, fragment pb.Fragment)
 
 	var getObj = s3.GetObjectInput{
 		Bucket: aws.String(cfg.bucket),
-		Key:    aws.String(cfg.rewritePath(cfg.prefix, fragment.ContentPath())),
+		Key:    aws.String(cfg.fragmentPath(cfg.prefix, fragment)),
 	}
 	var resp *s3.GetObjectOutput
 	if resp, err = client.GetObjectWithContext(ctx, &getObj); err != nil {
+		if awsErr, ok := err.(awserr.Error); ok && awsErr.Code() == s3.ErrCodeInvalidObjectState {
+			err = needsRestoreError(fragment, err)
+		}
 		return nil, err
 	}
 	return resp.Body, err
@@ -117,9 +120,16 @@ func (s *s3Backend) Persist(ctx context.Context, ep *url.URL, spool Spool) error
 		return err
 	}
 
+	var putObj = cfg.putObjectInput(spool)
+	_, err = client.PutObjectWithContext(ctx, &putObj)
+	return err
+}
+
+// putObjectInput returns the PutObjectInput which persists the Spool.
+func (cfg S3StoreConfig) putObjectInput(spool Spool) s3.PutObjectInput {
 	var putObj = s3.PutObjectInput{
 		Bucket: aws.String(cfg.bucket),
-		Key:    aws.String(cfg.rewritePath(cfg.prefix, spool.ContentPath())),
+		Key:    aws.String(cfg.fragmentPath(cfg.prefix, spool.Fragment.Fragment)),
 	}
 
 	if cfg.ACL != "" {
@@ -134,6 +144,13 @@ func (s *s3Backend) Persist(ctx context.Context, ep *url.URL, spool Spool) error
 	if cfg.SSEKMSKeyId != "" {
 		putObj.SSEKMSKeyId = aws.String(cfg.SSEKMSKeyId)
 	}
+	if len(spool.Tags) != 0 {
+		var tags = make(url.Values, len(spool.Tags))
+		for key, value := range spool.Tags {
+			tags.Set(key, value)
+		}
+		putObj.Tagging = aws.String(tags.Encode())
+	}
 	if spool.CompressionCodec == pb.CompressionCodec_GZIP_OFFLOAD_DECOMPRESSION {
 		putObj.ContentEncoding = aws.String("gzip")
 	}
@@ -142,11 +159,10 @@ func (s *s3Backend) Persist(ctx context.Context, ep *url.URL, spool Spool) error
 	} else {
 		putObj.Body = io.NewSectionReader(spool.File, 0, spool.ContentLength())
 	}
-	_, err = client.PutObjectWithContext(ctx, &putObj)
-	return err
+	return putObj
 }
 
-func (s *s3Backend) List(ctx context.Context, store pb.FragmentStore, ep *url.URL, journal pb.Journal, callback func(pb.Fragment)) error {
+func (s *s3Backend) List(ctx context.Context, store pb.FragmentStore, ep *url.URL, journal pb.Journal, callback func(pb.Fragment), unknown func(string, error)) error {
 	var cfg, client, err = s.s3Client(ep)
 	if err != nil {
 		return err
@@ -159,12 +175,16 @@ func (s *s3Backend) List(ctx context.Context, store pb.FragmentStore, ep *url.UR
 		for _, obj := range objs.Contents {
 			if strings.HasSuffix(*obj.Key, "/") {
 				// Ignore directory-like objects, usually created by mounting buckets with a FUSE driver.
-			} else if frag, err := pb.ParseFragmentFromRelativePath(journal, (*obj.Key)[len(*q.Prefix):]); err != nil {
+			} else if frag, err := cfg.parseFragment(journal, (*obj.Key)[len(*q.Prefix):]); err != nil {
 				log.WithFields(log.Fields{"bucket": cfg.bucket, "key": *obj.Key, "err": err}).Warning("parsing fragment")
+				unknown((*obj.Key)[len(*q.Prefix):], err)
 			} else if *obj.Size == 0 && frag.ContentLength() > 0 {
 				log.WithFields(log.Fields{"obj": obj}).Warning("zero-length fragment")
+				unknown((*obj.Key)[len(*q.Prefix):], errZeroLengthFragment)
 			} else {
-				frag.ModTime = obj.LastModified.Unix()
+				if frag.ModTime == 0 { // Not set by the NameCodec.
+					frag.ModTime = obj.LastModified.Unix()
+				}
 				frag.BackingStore = store
 				callback(frag)
 			}
@@ -180,7 +200,7 @@ func (s *s3Backend) Remove(ctx context.Context, fragment pb.Fragment) error {
 	}
 	var deleteObj = s3.DeleteObjectInput{
 		Bucket: aws.String(cfg.bucket),
-		Key:    aws.String(cfg.rewritePath(cfg.prefix, fragment.ContentPath())),
+		Key:    aws.String(cfg.fragmentPath(cfg.prefix, fragment)),
 	}
 
 	_, err = client.DeleteObjectWithContext(ctx, &deleteObj)
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io"
 	"net/url"
+	"path"
 	"strings"
 	"text/template"
 	"time"
@@ -15,19 +16,24 @@ import (
 	"github.com/gorilla/schema"
 	"github.com/pkg/errors"
 	pb "go.gazette.dev/core/broker/protocol"
+	"go.gazette.dev/core/labels"
 )
 
 // DisableStores disables the use of configured journal stores.
 // If true, fragments are not persisted, and stores are not listed for existing fragments.
 var DisableStores bool = false
 
+// errZeroLengthFragment is reported for listed objects which are empty, but
+// are named as a non-empty Fragment.
+var errZeroLengthFragment = errors.New("zero-length fragment")
+
 type backend interface {
 	Provider() string
 	SignGet(ep *url.URL, fragment pb.Fragment, d time.Duration) (string, error)
 	Exists(ctx context.Context, ep *url.URL, fragment pb.Fragment) (bool, error)
 	Open(ctx context.Context, ep *url.URL, fragment pb.Fragment) (io.ReadCloser, error)
 	Persist(ctx context.Context, ep *url.URL, spool Spool) error
-	List(ctx context.Context, store pb.FragmentStore, ep *url.URL, name pb.Journal, callback func(pb.Fragment)) error
+	List(ctx context.Context, store pb.FragmentStore, ep *url.URL, name pb.Journal, callback func(pb.Fragment), unknown func(string, error)) error
 	Remove(ctx context.Context, fragment pb.Fragment) error
 }
 
@@ -68,8 +74,9 @@ func SignGetURL(fragment pb.Fragment, d time.Duration) (string, error) {
 	var ep = fragment.BackingStore.URL()
 	var b = getBackend(ep.Scheme)
 
+	var op = startStoreOp(b, "get_signed_url", fragment)
 	var signedURL, err = b.SignGet(ep, fragment, d)
-	instrumentStoreOp(b.Provider(), "get_signed_url", err)
+	op.finish(err)
 	return signedURL, err
 }
 
@@ -80,8 +87,9 @@ func Open(ctx context.Context, fragment pb.Fragment) (io.ReadCloser, error) {
 	var ep = fragment.BackingStore.URL()
 	var b = getBackend(ep.Scheme)
 
+	var op = startStoreOp(b, "open", fragment)
 	var rc, err = b.Open(ctx, ep, fragment)
-	instrumentStoreOp(b.Provider(), "open", err)
+	op.finish(err)
 	return rc, err
 }
 
@@ -105,16 +113,21 @@ func Persist(ctx context.Context, spool Spool, spec *pb.JournalSpec) error {
 	var ep = spool.Fragment.BackingStore.URL()
 	var b = getBackend(ep.Scheme)
 
+	var op = startStoreOp(b, "exist", spool.Fragment.Fragment)
 	var exists, err = b.Exists(ctx, ep, spool.Fragment.Fragment)
-	instrumentStoreOp(b.Provider(), "exist", err)
+	op.finish(err)
 	if err != nil {
 		return err
 	} else if exists {
 		return nil // All done.
 	}
 
-	// Ensure |compressedFile| is ready. This is a no-op if compressed incrementally.
+	spool.Tags = fragmentTags(spec)
+
+	// Ensure |compressedFile| is ready. This is a no-op if compressed incrementally,
+	// and otherwise compresses at the journal's current CompressionLevel.
 	if spool.CompressionCodec != pb.CompressionCodec_NONE {
+		spool.CompressionLevel = spec.Fragment.CompressionLevel
 		spool.finishCompression()
 	}
 
@@ -126,29 +139,44 @@ func Persist(ctx context.Context, spool Spool, spec *pb.JournalSpec) error {
 	var timeoutCtx, cancel = context.WithTimeout(ctx, 5*time.Minute)
 	defer cancel()
 
+	op = startStoreOp(b, "persist", spool.Fragment.Fragment)
 	if err = b.Persist(timeoutCtx, ep, spool); err == nil {
 		storePersistedBytesTotal.WithLabelValues(b.Provider()).Add(float64(spool.ContentLength()))
 	}
-	instrumentStoreOp(b.Provider(), "persist", err)
+	op.finish(err)
 	return err
 }
 
 // List Fragments of the FragmentStore for a given journal. |callback| is
 // invoked with each listed Fragment, and any returned error aborts the listing.
+// Listed objects which aren't valid Fragments are logged and skipped.
 func List(ctx context.Context, store pb.FragmentStore, name pb.Journal, callback func(pb.Fragment)) error {
+	return ListObjects(ctx, store, name, callback, func(string, error) {})
+}
+
+// ListObjects is like List, but additionally invokes |unknown| with each
+// listed object which isn't a valid Fragment of the journal, along with the
+// reason it's invalid. Object names are relative to the journal's path within
+// the FragmentStore.
+func ListObjects(ctx context.Context, store pb.FragmentStore, name pb.Journal, callback func(pb.Fragment), unknown func(string, error)) error {
 	var ep = store.URL()
 	var b = getBackend(ep.Scheme)
 
-	var err = b.List(ctx, store, ep, name, callback)
-	instrumentStoreOp(b.Provider(), "list", err)
+	var op = startStoreOp(b, "list", pb.Fragment{Journal: name, BackingStore: store})
+	var err = b.List(ctx, store, ep, name, func(f pb.Fragment) {
+		op.listed += f.ContentLength()
+		callback(f)
+	}, unknown)
+	op.finish(err)
 	return err
 }
 
 // Remove |fragment| from its BackingStore.
 func Remove(ctx context.Context, fragment pb.Fragment) error {
 	var b = getBackend(fragment.BackingStore.URL().Scheme)
+	var op = startStoreOp(b, "remove", fragment)
 	var err = b.Remove(ctx, fragment)
-	instrumentStoreOp(b.Provider(), "remove", err)
+	op.finish(err)
 	return err
 }
 
@@ -160,6 +188,8 @@ func parseStoreArgs(ep *url.URL, args interface{}) error {
 		return err
 	} else if err = decoder.Decode(args, q); err != nil {
 		return fmt.Errorf("parsing store URL arguments: %s", err)
+	} else if r, ok := args.(interface{ resolveNameCodec() error }); ok {
+		return r.resolveNameCodec()
 	}
 	return nil
 }
@@ -177,6 +207,28 @@ func instrumentStoreOp(provider, op string, err error) {
 	}
 }
 
+// fragmentTags returns object tags of the JournalSpec's Fragments,
+// or nil if it has no labels of labels.FragmentTagPrefix.
+func fragmentTags(spec *pb.JournalSpec) map[string]string {
+	var tags map[string]string
+
+	for _, label := range spec.LabelSet.Labels {
+		if !strings.HasPrefix(label.Name, labels.FragmentTagPrefix) {
+			continue
+		} else if tags == nil {
+			tags = make(map[string]string)
+		}
+		tags[label.Name[len(labels.FragmentTagPrefix):]] = label.Value
+	}
+	return tags
+}
+
+// needsRestoreError wraps |err| of a store which reports that a Fragment
+// is archived, as an error which also wraps protocol.ErrFragmentNeedsRestore.
+func needsRestoreError(fragment pb.Fragment, err error) error {
+	return fmt.Errorf("%w (%s): %s", pb.ErrFragmentNeedsRestore, fragment.ContentPath(), err)
+}
+
 func evalPathPostfix(spool Spool, spec *pb.JournalSpec) (string, error) {
 	var tpl, err = template.New("").Parse(spec.Fragment.PathPostfixTemplate)
 	if err != nil {
@@ -207,11 +259,50 @@ func evalPathPostfix(spool Spool, spec *pb.JournalSpec) (string, error) {
 //	// Remaps journal name => fragment store URL:
 //	//  "/foo/bar/v1/page-views/part-000" => "s3://my-bucket/foo/old-path/page-views/part-000" // Matched.
 //	//  "/foo/bar/v2/page-views/part-000" => "s3://my-bucket/foo/bar/v2/page-views/part-000"   // Not matched.
+//
+// RewriterConfig also selects the NameCodec which names fragments under the
+// journal's path.
 type RewriterConfig struct {
 	// Find is the string to replace in the unmodified journal name.
 	Find string
 	// Replace is the string with which Find is replaced in the constructed store path.
 	Replace string
+	// NameCodec is the registered name of the NameCodec of the store's
+	// fragments. If empty, ContentNameCodec is used.
+	NameCodec string
+
+	codec NameCodec // Resolved NameCodec.
+}
+
+// resolveNameCodec resolves the NameCodec of the RewriterConfig.
+func (cfg *RewriterConfig) resolveNameCodec() (err error) {
+	cfg.codec, err = lookupNameCodec(cfg.NameCodec)
+	return err
+}
+
+// fragmentPath returns the path of |fragment| under fragment store path |s|.
+func (cfg RewriterConfig) fragmentPath(s string, fragment pb.Fragment) string {
+	return cfg.rewritePath(s, path.Join(fragment.Journal.String(), cfg.nameCodec().EncodeName(fragment)))
+}
+
+// parseFragment parses a Fragment of |journal| from its object |name|,
+// relative to the journal's path within the fragment store.
+func (cfg RewriterConfig) parseFragment(journal pb.Journal, name string) (pb.Fragment, error) {
+	var f, err = cfg.nameCodec().ParseName(journal, name)
+	if err == nil {
+		err = f.Validate()
+	}
+	if err == nil && f.Journal != journal {
+		err = fmt.Errorf("parsed Fragment journal %s doesn't match %s", f.Journal, journal)
+	}
+	return f, err
+}
+
+func (cfg RewriterConfig) nameCodec() NameCodec {
+	if cfg.codec == nil {
+		return ContentNameCodec{}
+	}
+	return cfg.codec
 }
 
 // rewritePath replace the first occurrence of the find string with the replace
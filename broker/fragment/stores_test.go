@@ -14,6 +14,7 @@ import (
 	"go.gazette.dev/core/broker/client"
 	"go.gazette.dev/core/broker/codecs"
 	pb "go.gazette.dev/core/broker/protocol"
+	"go.gazette.dev/core/labels"
 )
 
 // How to test individual FragmentStore implementations:
@@ -136,6 +137,39 @@ func TestParseStoreArgsS3(t *testing.T) {
 	require.Equal(t, "123", s3Cfg.SSEKMSKeyId)
 }
 
+func TestFragmentTagsOfJournalLabels(t *testing.T) {
+	var spec = &pb.JournalSpec{
+		LabelSet: pb.MustLabelSet(
+			labels.ContentType, labels.ContentType_JSONLines,
+			labels.FragmentTagPrefix+"lifecycle", "cold",
+			labels.FragmentTagPrefix+"team", "data/platform",
+		),
+	}
+	var tags = fragmentTags(spec)
+	require.Equal(t, map[string]string{"lifecycle": "cold", "team": "data/platform"}, tags)
+	require.Nil(t, fragmentTags(&pb.JournalSpec{}))
+
+	// Expect tags are applied to S3 objects, along with a configured StorageClass.
+	storeURL, _ := url.Parse("s3://bucket/prefix/?StorageClass=GLACIER_IR")
+	var s3Cfg S3StoreConfig
+	require.NoError(t, parseStoreArgs(storeURL, &s3Cfg))
+
+	var spool = Spool{Fragment: Fragment{Fragment: pb.Fragment{Journal: "a/journal", CompressionCodec: pb.CompressionCodec_NONE}}, Tags: tags}
+	var putObj = s3Cfg.putObjectInput(spool)
+	require.Equal(t, "GLACIER_IR", *putObj.StorageClass)
+	require.Equal(t, "lifecycle=cold&team=data%2Fplatform", *putObj.Tagging)
+
+	// A Spool without tags has no Tagging.
+	spool.Tags = nil
+	require.Nil(t, s3Cfg.putObjectInput(spool).Tagging)
+
+	// GCS stores may also configure a StorageClass.
+	storeURL, _ = url.Parse("gs://bucket/prefix/?StorageClass=COLDLINE")
+	var gsCfg GSStoreConfig
+	require.NoError(t, parseStoreArgs(storeURL, &gsCfg))
+	require.Equal(t, "COLDLINE", gsCfg.StorageClass)
+}
+
 func readFrag(t *testing.T, f pb.Fragment) string {
 	var rc, err = Open(context.Background(), f)
 	require.NoError(t, err)
@@ -5,6 +5,7 @@ import (
 	"net"
 	"sort"
 
+	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
 	"go.gazette.dev/core/broker/fragment"
 	pb "go.gazette.dev/core/broker/protocol"
@@ -52,8 +53,11 @@ func (svc *Service) ListFragments(ctx context.Context, req *pb.FragmentsRequest)
 		return &pb.FragmentsResponse{Status: pb.Status_NOT_ALLOWED, Header: res.Header}, nil
 	} else if res.replica == nil {
 		req.Header = &res.Header // Attach resolved Header to |req|, which we'll forward.
-		ctx = pb.WithDispatchRoute(ctx, req.Header.Route, req.Header.ProcessId)
-		return svc.jc.ListFragments(ctx, req)
+		var dispatchCtx = pb.WithDispatchRoute(withForwardedClaims(ctx), req.Header.Route, req.Header.ProcessId)
+		if resp, err = svc.jc.ListFragments(dispatchCtx, req); err == nil {
+			err = authorizeFragmentURLs(ctx, resp, svc.ReadAuthorizer)
+		}
+		return resp, err
 	}
 
 	resp = &pb.FragmentsResponse{
@@ -64,9 +68,30 @@ func (svc *Service) ListFragments(ctx context.Context, req *pb.FragmentsRequest)
 		resp.Fragments, resp.NextPageToken, err = listFragments(req, fragmentSet)
 		return err
 	})
+	if err == nil {
+		err = authorizeFragmentURLs(ctx, resp, svc.ReadAuthorizer)
+	}
 	return resp, err
 }
 
+// authorizeFragmentURLs removes signed URLs of Fragments of the response
+// which the client isn't authorized to read.
+func authorizeFragmentURLs(ctx context.Context, resp *pb.FragmentsResponse, authorize ReadAuthorizeFunc) error {
+	if authorize == nil {
+		return nil
+	}
+	for i := range resp.Fragments {
+		if resp.Fragments[i].SignedUrl == "" {
+			continue
+		} else if ok, err := authorize(ctx, resp.Fragments[i].Spec); err != nil {
+			return errors.WithMessage(err, "authorizing read")
+		} else if !ok {
+			resp.Fragments[i].SignedUrl = ""
+		}
+	}
+	return nil
+}
+
 // List FragmentsResponse__Fragment matching the query, and return the
 // NextPageToken to be used for subsequent requests. If NextPageToken is nil
 // there are no further Fragments to enumerate.
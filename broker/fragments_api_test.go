@@ -9,6 +9,7 @@ import (
 	"go.gazette.dev/core/broker/fragment"
 	pb "go.gazette.dev/core/broker/protocol"
 	"go.gazette.dev/core/etcdtest"
+	"google.golang.org/grpc/metadata"
 )
 
 func TestFragmentsResolutionCases(t *testing.T) {
@@ -45,6 +46,10 @@ func TestFragmentsResolutionCases(t *testing.T) {
 	var proxyHeader = broker.header("proxy/journal")
 
 	peer.ListFragmentsFunc = func(ctx context.Context, req *pb.FragmentsRequest) (*pb.FragmentsResponse, error) {
+		// Expect the client's claims are forwarded.
+		var md, _ = metadata.FromIncomingContext(ctx)
+		require.Equal(t, []string{"Bearer client-token"}, md.Get("authorization"))
+
 		require.Equal(t, &pb.FragmentsRequest{
 			Header:        proxyHeader,
 			Journal:       "proxy/journal",
@@ -62,7 +67,9 @@ func TestFragmentsResolutionCases(t *testing.T) {
 		}, nil
 	}
 
-	resp, err = broker.client().ListFragments(ctx, &pb.FragmentsRequest{Journal: "proxy/journal"})
+	resp, err = broker.client().ListFragments(
+		metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer client-token"),
+		&pb.FragmentsRequest{Journal: "proxy/journal"})
 	require.NoError(t, err)
 	require.Equal(t, &pb.FragmentsResponse{
 		Status:        pb.Status_OK,
@@ -0,0 +1,143 @@
+package broker
+
+import (
+	"context"
+	"strings"
+
+	"go.gazette.dev/core/allocator"
+	pb "go.gazette.dev/core/broker/protocol"
+	pbx "go.gazette.dev/core/broker/protocol/ext"
+	"google.golang.org/grpc/codes"
+	hv1 "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// HealthServiceJournals is the health-checked service which summarizes
+	// the readiness of every journal assigned to the broker. It's SERVING if
+	// the broker is serving, and every one of its assigned journals is ready.
+	HealthServiceJournals = "gazette.journals"
+	// HealthServiceJournalPrefix prefixes a health-checked service which is
+	// the readiness of a specific journal, as in "gazette.journal/my/journal".
+	// It's SERVING if the broker is serving, and it's a ready primary or
+	// replica of the journal. It's NOT_SERVING if the journal isn't assigned
+	// to the broker, and unknown if the journal doesn't exist.
+	HealthServiceJournalPrefix = "gazette.journal/"
+)
+
+// HealthServer implements the standard grpc.health.v1 Health service of a
+// broker, for use by load balancers and orchestrators. The empty service
+// name (or "protocol.Journal") is the overall status of the broker, which is
+// SERVING if the broker's BrokerSpec is announced to Etcd with a non-zero
+// JournalLimit and it's serving its local journal replicas. A broker which is
+// draining (its JournalLimit is zero) or shutting down is NOT_SERVING.
+//
+// Per-journal readiness is available through targeted queries of the service
+// HealthServiceJournalPrefix + journal, or summarized across all assigned
+// journals by HealthServiceJournals. Statuses are derived on demand from the
+// broker's KeySpace, and no per-journal state is retained.
+//
+// An assigned journal is ready if its Route has a primary, and all of its
+// replicas have synchronized and advertise the current Route (as with
+// JournalIsConsistent).
+type HealthServer struct {
+	hv1.UnimplementedHealthServer
+	svc *Service
+}
+
+// NewHealthServer returns a HealthServer of the Service.
+func NewHealthServer(svc *Service) *HealthServer { return &HealthServer{svc: svc} }
+
+// Check the status of the requested service.
+func (h *HealthServer) Check(_ context.Context, req *hv1.HealthCheckRequest) (*hv1.HealthCheckResponse, error) {
+	var ks = h.svc.resolver.state.KS
+	ks.Mu.RLock()
+	defer ks.Mu.RUnlock()
+
+	var s, ok = h.status(req.Service)
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "unknown service %q", req.Service)
+	}
+	return &hv1.HealthCheckResponse{Status: s}, nil
+}
+
+// Watch the status of the requested service, streaming its current status
+// and each subsequent change of status. An unknown service is streamed as
+// SERVICE_UNKNOWN, and may later become known (eg, as a journal is created).
+func (h *HealthServer) Watch(req *hv1.HealthCheckRequest, stream hv1.Health_WatchServer) error {
+	var ks = h.svc.resolver.state.KS
+	var last = hv1.HealthCheckResponse_ServingStatus(-1)
+
+	ks.Mu.RLock()
+	var s, ok = h.status(req.Service)
+	var rev = ks.Header.Revision
+	ks.Mu.RUnlock()
+
+	for {
+		if !ok {
+			s = hv1.HealthCheckResponse_SERVICE_UNKNOWN
+		}
+		// Send without holding the KeySpace lock, as a client which doesn't
+		// read its stream may block Send indefinitely.
+		if s != last {
+			if err := stream.Send(&hv1.HealthCheckResponse{Status: s}); err != nil {
+				return err
+			}
+			last = s
+		}
+
+		ks.Mu.RLock()
+		var err = ks.WaitForRevision(stream.Context(), rev+1)
+		if err == nil {
+			s, ok = h.status(req.Service)
+			rev = ks.Header.Revision
+		}
+		ks.Mu.RUnlock()
+
+		if err != nil {
+			return status.FromContextError(err).Err()
+		}
+	}
+}
+
+// status returns the status of the |service|, or false if it's unknown.
+// The KeySpace must be read-locked.
+func (h *HealthServer) status(service string) (hv1.HealthCheckResponse_ServingStatus, bool) {
+	var r = h.svc.resolver
+	var ready = r.replicas != nil && r.state.LocalMemberInd != -1 &&
+		r.state.Members[r.state.LocalMemberInd].Decoded.(allocator.Member).MemberValue.ItemLimit() != 0
+
+	switch {
+	case service == "" || service == "protocol.Journal":
+	case service == HealthServiceJournals:
+		for _, replica := range r.replicas {
+			ready = ready && replicaIsReady(replica)
+		}
+	case strings.HasPrefix(service, HealthServiceJournalPrefix):
+		var name = pb.Journal(strings.TrimPrefix(service, HealthServiceJournalPrefix))
+
+		if _, ok := allocator.LookupItem(r.state.KS, name.String()); !ok {
+			return hv1.HealthCheckResponse_UNKNOWN, false
+		} else if replica, ok := r.replicas[name]; !ok {
+			ready = false
+		} else {
+			ready = ready && replicaIsReady(replica)
+		}
+	default:
+		return hv1.HealthCheckResponse_UNKNOWN, false
+	}
+
+	if ready {
+		return hv1.HealthCheckResponse_SERVING, true
+	}
+	return hv1.HealthCheckResponse_NOT_SERVING, true
+}
+
+// replicaIsReady returns true if the journal of the local |replica| has a
+// primary, and all of its replicas advertise its current Route.
+func replicaIsReady(replica *resolverReplica) bool {
+	var rt pb.Route
+	pbx.Init(&rt, replica.assignments)
+
+	return rt.Primary != -1 && JournalRouteMatchesAssignments(rt, replica.assignments)
+}
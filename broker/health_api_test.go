@@ -0,0 +1,131 @@
+package broker
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	pb "go.gazette.dev/core/broker/protocol"
+	"go.gazette.dev/core/etcdtest"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	hv1 "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+)
+
+func TestHealthCheckCases(t *testing.T) {
+	var ctx, etcd = context.Background(), etcdtest.TestClient()
+	defer etcdtest.Cleanup()
+
+	var broker = newTestBroker(t, etcd, pb.ProcessSpec_ID{Zone: "local", Suffix: "broker"})
+	var peer = newMockBroker(t, etcd, pb.ProcessSpec_ID{Zone: "peer", Suffix: "broker"})
+	var health = NewHealthServer(broker.svc)
+
+	var check = func(service string) hv1.HealthCheckResponse_ServingStatus {
+		var resp, err = health.Check(ctx, &hv1.HealthCheckRequest{Service: service})
+		require.NoError(t, err)
+		return resp.Status
+	}
+	const (
+		serving    = hv1.HealthCheckResponse_SERVING
+		notServing = hv1.HealthCheckResponse_NOT_SERVING
+	)
+
+	// Start a Watch of the journal, which doesn't yet exist.
+	var watchCtx, cancel = context.WithCancel(ctx)
+	var stream = &healthWatchStream{ctx: watchCtx, ch: make(chan hv1.HealthCheckResponse_ServingStatus, 8)}
+	var watchErrCh = make(chan error, 1)
+	go func() {
+		watchErrCh <- health.Watch(&hv1.HealthCheckRequest{Service: "gazette.journal/a/journal"}, stream)
+	}()
+	require.Equal(t, hv1.HealthCheckResponse_SERVICE_UNKNOWN, <-stream.ch)
+
+	// Case: the broker has a zero JournalLimit, and is draining.
+	require.Equal(t, notServing, check(""))
+	require.Equal(t, notServing, check(HealthServiceJournals))
+
+	// Case: the broker is serving, and has no assigned journals.
+	var _, err = etcd.Put(ctx, broker.svc.resolver.state.LocalKey, (&pb.BrokerSpec{
+		ProcessSpec:  pb.ProcessSpec{Id: broker.id, Endpoint: broker.srv.Endpoint()},
+		JournalLimit: 10,
+	}).MarshalString())
+	require.NoError(t, err)
+	broker.catchUpKeySpace()
+
+	require.Equal(t, serving, check(""))
+	require.Equal(t, serving, check("protocol.Journal"))
+	require.Equal(t, serving, check(HealthServiceJournals))
+
+	// Case: unknown services and journals are NotFound.
+	for _, service := range []string{"other.Service", "gazette.journal/a/journal"} {
+		_, err = health.Check(ctx, &hv1.HealthCheckRequest{Service: service})
+		require.Equal(t, codes.NotFound, status.Code(err))
+	}
+
+	// Case: a journal is assigned, but its replicas haven't synchronized.
+	setTestJournal(broker, pb.JournalSpec{Name: "a/journal", Replication: 2}, broker.id, peer.id)
+	setTestJournal(broker, pb.JournalSpec{Name: "other/journal", Replication: 1}, peer.id)
+
+	require.Equal(t, serving, check(""))
+	require.Equal(t, notServing, check(HealthServiceJournals))
+	require.Equal(t, notServing, check("gazette.journal/a/journal"))
+	require.Equal(t, notServing, <-stream.ch)
+
+	// Case: the journal's replicas have synchronized, and it's ready.
+	var res = broker.resolve("a/journal")
+	rev, err := updateAssignments(ctx, res.assignments, etcd)
+	require.NoError(t, err)
+	broker.ks.Mu.RLock()
+	require.NoError(t, broker.ks.WaitForRevision(ctx, rev))
+	broker.ks.Mu.RUnlock()
+
+	require.Equal(t, serving, check(HealthServiceJournals))
+	require.Equal(t, serving, check("gazette.journal/a/journal"))
+	require.Equal(t, serving, <-stream.ch)
+
+	// Case: a journal which isn't assigned to the broker isn't served by it.
+	require.Equal(t, notServing, check("gazette.journal/other/journal"))
+
+	// Case: a Watch client which doesn't read its stream doesn't block
+	// updates of the KeySpace.
+	var stalled = &healthWatchStream{ctx: watchCtx, ch: make(chan hv1.HealthCheckResponse_ServingStatus)}
+	go func() { _ = health.Watch(&hv1.HealthCheckRequest{Service: ""}, stalled) }()
+
+	var lockedCh = make(chan struct{})
+	go func() {
+		time.Sleep(10 * time.Millisecond) // Allow Watch to block in Send.
+		broker.ks.Mu.Lock()
+		broker.ks.Mu.Unlock()
+		close(lockedCh)
+	}()
+	select {
+	case <-lockedCh:
+	case <-time.After(5 * time.Second):
+		t.Fatal("KeySpace lock is blocked by a stalled Watch")
+	}
+
+	cancel()
+	require.Equal(t, codes.Canceled, status.Code(<-watchErrCh))
+
+	broker.cleanup()
+	peer.Cleanup()
+}
+
+// healthWatchStream is a hv1.Health_WatchServer which passes sent statuses to |ch|.
+type healthWatchStream struct {
+	grpc.ServerStream
+	ctx context.Context
+	ch  chan hv1.HealthCheckResponse_ServingStatus
+}
+
+func (s *healthWatchStream) Context() context.Context { return s.ctx }
+
+func (s *healthWatchStream) Send(resp *hv1.HealthCheckResponse) error {
+	select {
+	case s.ch <- resp.Status:
+		return nil
+	case <-s.ctx.Done():
+		return s.ctx.Err()
+	}
+}
@@ -0,0 +1,101 @@
+// Package journalaudit records the Assignment changes of an allocator.Allocator
+// to a journal, giving a durable history of its decisions which is independent
+// of Etcd compaction.
+package journalaudit
+
+import (
+	"context"
+	"encoding/json"
+
+	log "github.com/sirupsen/logrus"
+	"go.gazette.dev/core/allocator"
+	"go.gazette.dev/core/broker/client"
+	pb "go.gazette.dev/core/broker/protocol"
+)
+
+// NewAuditFunc returns an allocator.AuditFunc which appends AuditRecords to
+// the journal as newline-delimited JSON, using the AsyncJournalClient.
+// Auditing is best-effort: records are queued to a goroutine which appends
+// them until the Context is cancelled, and are never awaited by the Allocator.
+//
+// At most one append of the journal is in progress at a time, and records
+// queued while it's pending are batched into the next append. While the
+// journal is unavailable the AsyncJournalClient logs and retries the pending
+// append, and should the queue fill further records are logged and dropped.
+//
+// The audit journal may itself be an Item of the Allocator (eg, if the
+// Allocator is that of the broker serving the journal). Records of changes
+// to the audit journal's own Assignments are not appended, as they'd need
+// the journal to be available in order to record that it's unavailable.
+func NewAuditFunc(ctx context.Context, ajc client.AsyncJournalClient, journal pb.Journal) allocator.AuditFunc {
+	var queue = make(chan []allocator.AuditRecord, auditQueueSize)
+	go serveAudits(ctx, ajc, journal, queue)
+
+	return func(records []allocator.AuditRecord) {
+		var filtered []allocator.AuditRecord
+
+		for _, r := range records {
+			if r.ItemID != journal.String() {
+				filtered = append(filtered, r) // Don't audit the audit journal.
+			}
+		}
+		if len(filtered) == 0 {
+			return
+		}
+
+		select {
+		case queue <- filtered:
+		default:
+			log.WithFields(log.Fields{"journal": journal, "records": len(filtered)}).
+				Warn("allocator audit queue is full (dropping records)")
+		}
+	}
+}
+
+// serveAudits appends queued AuditRecords to the journal until |ctx| is done.
+func serveAudits(ctx context.Context, ajc client.AsyncJournalClient, journal pb.Journal, queue <-chan []allocator.AuditRecord) {
+	for {
+		var records []allocator.AuditRecord
+
+		select {
+		case records = <-queue:
+		case <-ctx.Done():
+			return
+		}
+
+		var aa = ajc.StartAppend(pb.AppendRequest{Journal: journal}, nil)
+		var enc = json.NewEncoder(aa.Writer())
+
+		// Batch all queued records into this append.
+		for done := false; !done; {
+			for _, r := range records {
+				aa.Require(enc.Encode(r))
+			}
+			select {
+			case records = <-queue:
+			default:
+				done = true
+			}
+		}
+
+		if err := aa.Release(); err != nil {
+			log.WithFields(log.Fields{"journal": journal, "err": err}).
+				Warn("failed to marshal allocator audit records")
+			continue
+		}
+
+		select {
+		case <-aa.Done():
+			if err := aa.Err(); err != nil {
+				log.WithFields(log.Fields{"journal": journal, "err": err}).
+					Warn("failed to append allocator audit records")
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// auditQueueSize is the number of AuditFunc calls which may be queued while
+// an append of the audit journal is pending.
+var auditQueueSize = 64
@@ -0,0 +1,124 @@
+package journalaudit
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+
+	"go.gazette.dev/core/allocator"
+	"go.gazette.dev/core/broker/client"
+	pb "go.gazette.dev/core/broker/protocol"
+	"go.gazette.dev/core/broker/teststub"
+	gc "gopkg.in/check.v1"
+)
+
+type AuditSuite struct{}
+
+func (s *AuditSuite) TestAuditFunc(c *gc.C) {
+	var broker = teststub.NewBroker(c)
+	defer broker.Cleanup()
+
+	var ctx, cancel = context.WithCancel(context.Background())
+	defer cancel()
+
+	var as = client.NewAppendService(ctx, broker.Client())
+	var audit = NewAuditFunc(ctx, as, "audit/journal")
+
+	audit([]allocator.AuditRecord{
+		{Revision: 12, Op: "put", ItemID: "a/journal", MemberZone: "zone", MemberSuffix: "member", Slot: 0},
+		// Records of the audit journal itself are not appended.
+		{Revision: 12, Op: "delete", ItemID: "audit/journal", MemberZone: "zone", MemberSuffix: "member", Slot: 1},
+		{Revision: 12, Op: "delete", ItemID: "b/journal", MemberZone: "zone", MemberSuffix: "other", Slot: 2},
+	})
+	// A call having only records of the audit journal doesn't append.
+	audit([]allocator.AuditRecord{{Revision: 13, Op: "put", ItemID: "audit/journal"}})
+
+	c.Check(readAppend(c, broker), gc.Equals,
+		`{"rev":12,"op":"put","item":"a/journal","zone":"zone","suffix":"member","slot":0}`+"\n"+
+			`{"rev":12,"op":"delete","item":"b/journal","zone":"zone","suffix":"other","slot":2}`+"\n")
+	broker.AppendRespCh <- buildAppendResponse(broker, 166)
+
+	for op := range as.PendingExcept("") {
+		c.Check(op.Err(), gc.IsNil)
+	}
+}
+
+func (s *AuditSuite) TestAuditFuncBatchesAndDropsWhilePending(c *gc.C) {
+	var broker = teststub.NewBroker(c)
+	defer broker.Cleanup()
+
+	defer func(n int) { auditQueueSize = n }(auditQueueSize)
+	auditQueueSize = 2
+
+	var ctx, cancel = context.WithCancel(context.Background())
+	defer cancel()
+
+	var as = client.NewAppendService(ctx, broker.Client())
+	var audit = NewAuditFunc(ctx, as, "audit/journal")
+
+	var record = func(rev int64) []allocator.AuditRecord {
+		return []allocator.AuditRecord{{Revision: rev, Op: "put", ItemID: "a/journal"}}
+	}
+	audit(record(1))
+	c.Check(readAppend(c, broker), gc.Equals,
+		`{"rev":1,"op":"put","item":"a/journal","zone":"","suffix":"","slot":0}`+"\n")
+
+	// While the append is pending, records are queued until the queue is full,
+	// and are then dropped.
+	audit(record(2))
+	audit(record(3))
+	audit(record(4))
+
+	broker.AppendRespCh <- buildAppendResponse(broker, 60)
+
+	// Queued records are batched into a single following append.
+	c.Check(readAppend(c, broker), gc.Equals,
+		`{"rev":2,"op":"put","item":"a/journal","zone":"","suffix":"","slot":0}`+"\n"+
+			`{"rev":3,"op":"put","item":"a/journal","zone":"","suffix":"","slot":0}`+"\n")
+	broker.AppendRespCh <- buildAppendResponse(broker, 180)
+
+	for op := range as.PendingExcept("") {
+		c.Check(op.Err(), gc.IsNil)
+	}
+}
+
+// readAppend reads an Append RPC of the audit journal from the |broker|,
+// returning its content.
+func readAppend(c *gc.C, broker *teststub.Broker) string {
+	c.Check(<-broker.AppendReqCh, gc.DeepEquals, pb.AppendRequest{Journal: "audit/journal"})
+
+	var content bytes.Buffer
+	for req := <-broker.AppendReqCh; len(req.Content) != 0; req = <-broker.AppendReqCh {
+		content.Write(req.Content)
+	}
+	c.Check(<-broker.ReadLoopErrCh, gc.Equals, io.EOF)
+
+	return content.String()
+}
+
+func buildAppendResponse(broker *teststub.Broker, end pb.Offset) pb.AppendResponse {
+	return pb.AppendResponse{
+		Status: pb.Status_OK,
+		Header: pb.Header{
+			ProcessId: pb.ProcessSpec_ID{Zone: "zone", Suffix: "broker"},
+			Route: pb.Route{
+				Members:   []pb.ProcessSpec_ID{{Zone: "zone", Suffix: "broker"}},
+				Endpoints: []pb.Endpoint{broker.Endpoint()},
+				Primary:   0,
+			},
+			Etcd: pb.Header_Etcd{ClusterId: 1, MemberId: 2, Revision: 3, RaftTerm: 4},
+		},
+		Commit: &pb.Fragment{
+			Journal:          "audit/journal",
+			Begin:            0,
+			End:              end,
+			CompressionCodec: pb.CompressionCodec_NONE,
+		},
+		Registers: new(pb.LabelSet),
+	}
+}
+
+var _ = gc.Suite(&AuditSuite{})
+
+func Test(t *testing.T) { gc.TestingT(t) }
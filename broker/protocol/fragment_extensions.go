@@ -143,6 +143,26 @@ func (m CompressionCodec) Validate() error {
 	return nil
 }
 
+// ValidateLevel returns an error if |level| is not a valid compression level
+// of the CompressionCodec. A zero |level| selects the codec's default level
+// and is always valid. Codecs which don't support levels ignore them.
+func (m CompressionCodec) ValidateLevel(level int32) error {
+	var min, max int32
+
+	switch m {
+	case CompressionCodec_GZIP, CompressionCodec_GZIP_OFFLOAD_DECOMPRESSION:
+		min, max = 1, 9
+	case CompressionCodec_ZSTANDARD:
+		min, max = 1, 22
+	default:
+		return nil
+	}
+	if level != 0 && (level < min || level > max) {
+		return NewValidationError("invalid level (%d; expected %d <= level <= %d)", level, min, max)
+	}
+	return nil
+}
+
 // ToExtension returns the file extension of the CompressionCodec.
 func (m CompressionCodec) ToExtension() string {
 	switch m {
@@ -1,10 +1,17 @@
 package protocol
 
 import (
+	"errors"
 	"net/url"
 	"strings"
 )
 
+// ErrFragmentNeedsRestore is wrapped by errors of FragmentStore operations
+// where the store reports that the Fragment has transitioned to an archival
+// storage class, such as S3 Glacier or the Azure Archive tier. The Fragment
+// must be restored before it's read.
+var ErrFragmentNeedsRestore = errors.New("fragment is archived and must be restored before it's read")
+
 // FragmentStore defines a storage backend base path for Journal Fragments.
 // It is a URL, where the scheme defines the storage backend service. As
 // FragmentStores "root" remote storage locations of fragments, their path
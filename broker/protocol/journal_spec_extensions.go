@@ -124,6 +124,8 @@ func (m *JournalSpec_Fragment) Validate() error {
 			m.Length, minFragmentLen, maxFragmentLen)
 	} else if err := m.CompressionCodec.Validate(); err != nil {
 		return ExtendContext(err, "CompressionCodec")
+	} else if err = m.CompressionCodec.ValidateLevel(m.CompressionLevel); err != nil {
+		return ExtendContext(err, "CompressionLevel")
 	}
 	for i, store := range m.Stores {
 		if err := store.Validate(); err != nil {
@@ -134,9 +136,9 @@ func (m *JournalSpec_Fragment) Validate() error {
 			return NewValidationError("GZIP_OFFLOAD_DECOMPRESSION is incompatible with file:// stores (%s)", store)
 		}
 	}
-	if m.RefreshInterval < minRefreshInterval || m.RefreshInterval > maxRefreshInterval {
+	if m.RefreshInterval < MinRefreshInterval || m.RefreshInterval > maxRefreshInterval {
 		return NewValidationError("invalid RefreshInterval (%s; expected %s <= interval <= %s)",
-			m.RefreshInterval, minRefreshInterval, maxRefreshInterval)
+			m.RefreshInterval, MinRefreshInterval, maxRefreshInterval)
 	}
 
 	if m.FlushInterval != 0 && m.FlushInterval < minFlushInterval {
@@ -144,6 +146,22 @@ func (m *JournalSpec_Fragment) Validate() error {
 			m.FlushInterval, minFlushInterval)
 	}
 
+	if m.AdaptiveInterval < 0 {
+		return NewValidationError("invalid AdaptiveInterval (%s; expected >= 0)", m.AdaptiveInterval)
+	} else if m.AdaptiveInterval == 0 && (m.MinLength != 0 || m.MaxLength != 0) {
+		return NewValidationError("MinLength and MaxLength require an AdaptiveInterval")
+	} else if m.AdaptiveInterval != 0 && (m.MinLength < minFragmentLen || m.MinLength > m.Length) {
+		return NewValidationError("invalid MinLength (%d; expected %d <= MinLength <= Length)",
+			m.MinLength, minFragmentLen)
+	} else if m.AdaptiveInterval != 0 && (m.MaxLength < m.Length || m.MaxLength > maxFragmentLen) {
+		return NewValidationError("invalid MaxLength (%d; expected Length <= MaxLength <= %d)",
+			m.MaxLength, maxFragmentLen)
+	}
+
+	if m.MaxSpoolBytes < 0 {
+		return NewValidationError("invalid MaxSpoolBytes (%d; expected >= 0)", m.MaxSpoolBytes)
+	}
+
 	// Ensure the PathPostfixTemplate parses and evaluates without
 	// error over a zero-valued struct having the proper shape.
 	if tpl, err := template.New("postfix").Parse(m.PathPostfixTemplate); err != nil {
@@ -273,6 +291,21 @@ func UnionJournalSpecs(a, b JournalSpec) JournalSpec {
 	if a.Fragment.PathPostfixTemplate == "" {
 		a.Fragment.PathPostfixTemplate = b.Fragment.PathPostfixTemplate
 	}
+	if a.Fragment.CompressionLevel == 0 {
+		a.Fragment.CompressionLevel = b.Fragment.CompressionLevel
+	}
+	if a.Fragment.AdaptiveInterval == 0 {
+		a.Fragment.AdaptiveInterval = b.Fragment.AdaptiveInterval
+	}
+	if a.Fragment.MinLength == 0 {
+		a.Fragment.MinLength = b.Fragment.MinLength
+	}
+	if a.Fragment.MaxLength == 0 {
+		a.Fragment.MaxLength = b.Fragment.MaxLength
+	}
+	if a.Fragment.MaxSpoolBytes == 0 {
+		a.Fragment.MaxSpoolBytes = b.Fragment.MaxSpoolBytes
+	}
 	if a.Flags == JournalSpec_NOT_SPECIFIED {
 		a.Flags = b.Flags
 	}
@@ -311,6 +344,21 @@ func IntersectJournalSpecs(a, b JournalSpec) JournalSpec {
 	if a.Fragment.PathPostfixTemplate != b.Fragment.PathPostfixTemplate {
 		a.Fragment.PathPostfixTemplate = ""
 	}
+	if a.Fragment.CompressionLevel != b.Fragment.CompressionLevel {
+		a.Fragment.CompressionLevel = 0
+	}
+	if a.Fragment.AdaptiveInterval != b.Fragment.AdaptiveInterval {
+		a.Fragment.AdaptiveInterval = 0
+	}
+	if a.Fragment.MinLength != b.Fragment.MinLength {
+		a.Fragment.MinLength = 0
+	}
+	if a.Fragment.MaxLength != b.Fragment.MaxLength {
+		a.Fragment.MaxLength = 0
+	}
+	if a.Fragment.MaxSpoolBytes != b.Fragment.MaxSpoolBytes {
+		a.Fragment.MaxSpoolBytes = 0
+	}
 	if a.Flags != b.Flags {
 		a.Flags = JournalSpec_NOT_SPECIFIED
 	}
@@ -349,6 +397,21 @@ func SubtractJournalSpecs(a, b JournalSpec) JournalSpec {
 	if a.Fragment.PathPostfixTemplate == b.Fragment.PathPostfixTemplate {
 		a.Fragment.PathPostfixTemplate = ""
 	}
+	if a.Fragment.CompressionLevel == b.Fragment.CompressionLevel {
+		a.Fragment.CompressionLevel = 0
+	}
+	if a.Fragment.AdaptiveInterval == b.Fragment.AdaptiveInterval {
+		a.Fragment.AdaptiveInterval = 0
+	}
+	if a.Fragment.MinLength == b.Fragment.MinLength {
+		a.Fragment.MinLength = 0
+	}
+	if a.Fragment.MaxLength == b.Fragment.MaxLength {
+		a.Fragment.MaxLength = 0
+	}
+	if a.Fragment.MaxSpoolBytes == b.Fragment.MaxSpoolBytes {
+		a.Fragment.MaxSpoolBytes = 0
+	}
 	if a.Flags == b.Flags {
 		a.Flags = JournalSpec_NOT_SPECIFIED
 	}
@@ -358,6 +421,73 @@ func SubtractJournalSpecs(a, b JournalSpec) JournalSpec {
 	return a
 }
 
+// ResolveSpec returns the JournalSpec resolved from a |base| spec, which
+// provides defaults, and an |override| spec which inherits from it. Fields are
+// merged as by UnionJournalSpecs(override, base):
+//
+//   - A field having a non-zero value in |override| takes that value.
+//     Otherwise, it takes the value of |base|.
+//   - Labels are merged by name. Where |override| has values of a label, they
+//     replace all values of the label in |base|.
+//   - Fragment Stores are merged as a whole: an |override| having any Stores
+//     replaces those of |base|.
+//   - Name is that of |override|, or of |base| if |override| has no Name.
+//
+// JournalSpec fields lack presence, so a zero-valued field of |override| is
+// unset and inherits from |base|. Where the zero value of a field is itself
+// meaningful, such as a Retention of zero (which retains Fragments forever),
+// pass the field's name in |zeroed| to explicitly zero it in the resolved spec.
+// Names are as in YAML, eg "fragment.retention" or "max_append_rate". A field
+// which is both zeroed and given a non-zero value by |override| is a conflict
+// and an error, as is an unknown field name.
+//
+// Resolution is deterministic, and the resolved spec is validated. Multiple
+// levels of templates may be composed with UnionJournalSpecs (with the more
+// specific template first) before being resolved with a |base|.
+func ResolveSpec(base, override JournalSpec, zeroed ...string) (JournalSpec, error) {
+	var out = UnionJournalSpecs(override, base)
+	if override.Name == "" {
+		out.Name = base.Name
+	}
+
+	for _, name := range zeroed {
+		var field, ok = zeroableJournalSpecFields[name]
+		if !ok {
+			return JournalSpec{}, NewValidationError("unknown zeroed field (%s)", name)
+		}
+		// Does |override| give the field a non-zero value?
+		var tmp = override
+		if field(&tmp); !tmp.Equal(&override) {
+			return JournalSpec{}, NewValidationError(
+				"field %s of %s is both zeroed and overridden", name, override.Name)
+		}
+		field(&out)
+	}
+
+	if err := out.Validate(); err != nil {
+		return JournalSpec{}, ExtendContext(err, "resolved spec %s", out.Name)
+	}
+	return out, nil
+}
+
+// zeroableJournalSpecFields are fields of a JournalSpec which may be zeroed
+// by ResolveSpec, keyed on their YAML names.
+var zeroableJournalSpecFields = map[string]func(*JournalSpec){
+	"flags":                          func(s *JournalSpec) { s.Flags = JournalSpec_NOT_SPECIFIED },
+	"max_append_rate":                func(s *JournalSpec) { s.MaxAppendRate = 0 },
+	"fragment.stores":                func(s *JournalSpec) { s.Fragment.Stores = nil },
+	"fragment.refresh_interval":      func(s *JournalSpec) { s.Fragment.RefreshInterval = 0 },
+	"fragment.retention":             func(s *JournalSpec) { s.Fragment.Retention = 0 },
+	"fragment.flush_interval":        func(s *JournalSpec) { s.Fragment.FlushInterval = 0 },
+	"fragment.path_postfix_template": func(s *JournalSpec) { s.Fragment.PathPostfixTemplate = "" },
+	"fragment.compression_level":     func(s *JournalSpec) { s.Fragment.CompressionLevel = 0 },
+	"fragment.max_spool_bytes":       func(s *JournalSpec) { s.Fragment.MaxSpoolBytes = 0 },
+	// MinLength and MaxLength are valid only with an AdaptiveInterval.
+	"fragment.adaptive_interval": func(s *JournalSpec) {
+		s.Fragment.AdaptiveInterval, s.Fragment.MinLength, s.Fragment.MaxLength = 0, 0, 0
+	},
+}
+
 // ExtractJournalSpecMetaLabels adds to the LabelSet a singular label "name",
 // with value of the JournalSpec Name, and multi-label "prefix", having a value
 // for each path component prefix of Name.
@@ -397,10 +527,13 @@ func validateJournalLabelConstraints(ls LabelSet) error {
 	return nil
 }
 
+// MinRefreshInterval is the minimum Fragment RefreshInterval of a JournalSpec.
+const MinRefreshInterval = time.Second
+
 const (
-	minJournalNameLen, maxJournalNameLen   = 4, 512
-	maxJournalReplication                  = 5
-	minRefreshInterval, maxRefreshInterval = time.Second, time.Hour * 24
-	minFlushInterval                       = time.Minute
-	minFragmentLen, maxFragmentLen         = 1 << 10, 1 << 34 // 1024 => 17,179,869,184
+	minJournalNameLen, maxJournalNameLen = 4, 512
+	maxJournalReplication                = 5
+	maxRefreshInterval                   = time.Hour * 24
+	minFlushInterval                     = time.Minute
+	minFragmentLen, maxFragmentLen       = 1 << 10, 1 << 34 // 1024 => 17,179,869,184
 )
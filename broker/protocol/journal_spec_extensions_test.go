@@ -151,7 +151,13 @@ func (s *JournalSuite) TestSpecValidationCases(c *gc.C) {
 
 	f.CompressionCodec = 9999
 	c.Check(f.Validate(), gc.ErrorMatches, `CompressionCodec: invalid value \(9999\)`)
+	f.CompressionCodec = CompressionCodec_ZSTANDARD
+
+	f.CompressionLevel = 23
+	c.Check(f.Validate(), gc.ErrorMatches, `CompressionLevel: invalid level \(23; expected 1 <= level <= 22\)`)
 	f.CompressionCodec = CompressionCodec_GZIP_OFFLOAD_DECOMPRESSION
+	c.Check(f.Validate(), gc.ErrorMatches, `CompressionLevel: invalid level \(23; expected 1 <= level <= 9\)`)
+	f.CompressionLevel = 9
 
 	f.Stores[0] = "file:///a/root/"
 	c.Check(f.Validate(), gc.ErrorMatches,
@@ -168,6 +174,22 @@ func (s *JournalSuite) TestSpecValidationCases(c *gc.C) {
 	c.Check(f.Validate(), gc.ErrorMatches, `invalid FlushInterval \(1s; expected >= 1m0s\)`)
 	f.FlushInterval = time.Hour * 2
 
+	f.MinLength = 512
+	c.Check(f.Validate(), gc.ErrorMatches, `MinLength and MaxLength require an AdaptiveInterval`)
+	f.AdaptiveInterval = -time.Second
+	c.Check(f.Validate(), gc.ErrorMatches, `invalid AdaptiveInterval \(-1s; expected >= 0\)`)
+	f.AdaptiveInterval = time.Minute
+	c.Check(f.Validate(), gc.ErrorMatches, `invalid MinLength \(512; expected 1024 <= MinLength <= Length\)`)
+	f.MinLength, f.Length = 1024, 1<<20
+	c.Check(f.Validate(), gc.ErrorMatches, `invalid MaxLength \(0; expected Length <= MaxLength <= \d+\)`)
+	f.MaxLength = 1 << 30
+	c.Check(f.Validate(), gc.IsNil)
+	f.AdaptiveInterval, f.MinLength, f.MaxLength, f.Length = 0, 0, 0, 1024
+
+	f.MaxSpoolBytes = -1
+	c.Check(f.Validate(), gc.ErrorMatches, `invalid MaxSpoolBytes \(-1; expected >= 0\)`)
+	f.MaxSpoolBytes = 1 << 20
+
 	f.PathPostfixTemplate = "{{ bad template"
 	c.Check(f.Validate(), gc.ErrorMatches, `PathPostfixTemplate: template: postfix:1: .*`)
 	f.PathPostfixTemplate = ""
@@ -235,6 +257,11 @@ func (s *JournalSuite) TestSetOperations(c *gc.C) {
 			Retention:           time.Hour,
 			FlushInterval:       time.Hour,
 			PathPostfixTemplate: "{{ .Foo }}",
+			CompressionLevel:    3,
+			AdaptiveInterval:    time.Minute,
+			MinLength:           1024,
+			MaxLength:           1 << 20,
+			MaxSpoolBytes:       1 << 24,
 		},
 		Flags:         JournalSpec_O_RDWR,
 		MaxAppendRate: 1e3,
@@ -256,6 +283,11 @@ func (s *JournalSuite) TestSetOperations(c *gc.C) {
 			Retention:           10 * time.Hour,
 			FlushInterval:       10 * time.Hour,
 			PathPostfixTemplate: "{{ .Bar }}",
+			CompressionLevel:    7,
+			AdaptiveInterval:    time.Hour,
+			MinLength:           2048,
+			MaxLength:           1 << 30,
+			MaxSpoolBytes:       1 << 26,
 		},
 		Flags:         JournalSpec_O_RDONLY,
 		MaxAppendRate: 1e4,
@@ -282,4 +314,90 @@ func (s *JournalSuite) TestSetOperations(c *gc.C) {
 	c.Check(SubtractJournalSpecs(model, other), gc.DeepEquals, model)
 }
 
+func (s *JournalSuite) TestResolveSpec(c *gc.C) {
+	var base = JournalSpec{
+		Replication: 3,
+		LabelSet:    MustLabelSet("aaa", "base", "bbb", "base-1", "bbb", "base-2"),
+		Fragment: JournalSpec_Fragment{
+			Length:           1 << 20,
+			CompressionCodec: CompressionCodec_SNAPPY,
+			Stores:           []FragmentStore{"s3://a-bucket/", "gs://b-bucket/"},
+			RefreshInterval:  time.Minute,
+			Retention:        time.Hour,
+			FlushInterval:    time.Hour,
+		},
+		MaxAppendRate: 1e6,
+	}
+	var override = JournalSpec{
+		Name:     "a/journal",
+		LabelSet: MustLabelSet("bbb", "override", "ccc", "override"),
+		Fragment: JournalSpec_Fragment{
+			Length: 1 << 22,
+			Stores: []FragmentStore{"gs://c-bucket/"},
+		},
+	}
+
+	// Case: fields of |override| take precedence, and others inherit from |base|.
+	var out, err = ResolveSpec(base, override)
+	c.Check(err, gc.IsNil)
+	c.Check(out, gc.DeepEquals, JournalSpec{
+		Name:        "a/journal",
+		Replication: 3,
+		LabelSet:    MustLabelSet("aaa", "base", "bbb", "override", "ccc", "override"),
+		Fragment: JournalSpec_Fragment{
+			Length:           1 << 22,
+			CompressionCodec: CompressionCodec_SNAPPY,
+			Stores:           []FragmentStore{"gs://c-bucket/"},
+			RefreshInterval:  time.Minute,
+			Retention:        time.Hour,
+			FlushInterval:    time.Hour,
+		},
+		MaxAppendRate: 1e6,
+	})
+
+	// Case: fields are explicitly zeroed.
+	out, err = ResolveSpec(base, override, "fragment.retention", "max_append_rate")
+	c.Check(err, gc.IsNil)
+	c.Check(out.Fragment.Retention, gc.Equals, time.Duration(0))
+	c.Check(out.MaxAppendRate, gc.Equals, int64(0))
+	c.Check(out.Fragment.FlushInterval, gc.Equals, time.Hour)
+
+	// Case: zeroing an adaptive interval also zeroes the adaptive length bounds.
+	var adaptive = base
+	adaptive.Fragment.AdaptiveInterval = time.Minute
+	adaptive.Fragment.MinLength, adaptive.Fragment.MaxLength = 1<<20, 1<<24
+
+	out, err = ResolveSpec(adaptive, override)
+	c.Check(err, gc.IsNil)
+	c.Check(out.Fragment.MaxLength, gc.Equals, int64(1<<24))
+
+	out, err = ResolveSpec(adaptive, override, "fragment.adaptive_interval")
+	c.Check(err, gc.IsNil)
+	c.Check(out.Fragment.AdaptiveInterval, gc.Equals, time.Duration(0))
+	c.Check(out.Fragment.MinLength, gc.Equals, int64(0))
+	c.Check(out.Fragment.MaxLength, gc.Equals, int64(0))
+
+	// Case: a field which is both zeroed and overridden is a conflict.
+	_, err = ResolveSpec(base, override, "fragment.stores")
+	c.Check(err, gc.ErrorMatches, `field fragment.stores of a/journal is both zeroed and overridden`)
+
+	// Case: unknown zeroed field.
+	_, err = ResolveSpec(base, override, "fragment.length")
+	c.Check(err, gc.ErrorMatches, `unknown zeroed field \(fragment.length\)`)
+
+	// Case: the resolved spec is validated.
+	override.MaxAppendRate = -1
+	_, err = ResolveSpec(base, override)
+	c.Check(err, gc.ErrorMatches, `resolved spec a/journal: invalid MaxAppendRate \(-1; expected >= 0\)`)
+
+	// Case: |base| is itself composed of multiple levels of templates.
+	override.MaxAppendRate = 0
+	var mid = JournalSpec{Replication: 2, LabelSet: MustLabelSet("aaa", "mid")}
+
+	out, err = ResolveSpec(UnionJournalSpecs(mid, base), override)
+	c.Check(err, gc.IsNil)
+	c.Check(out.Replication, gc.Equals, int32(2))
+	c.Check(out.LabelSet, gc.DeepEquals, MustLabelSet("aaa", "mid", "bbb", "override", "ccc", "override"))
+}
+
 var _ = gc.Suite(&JournalSuite{})
@@ -410,6 +410,9 @@ type JournalSpec_Fragment struct {
 	// size, in that the former reflects uncompressed bytes.
 	Length int64 `protobuf:"varint,1,opt,name=length,proto3" json:"length,omitempty" yaml:",omitempty"`
 	// Codec used to compress Journal Fragments.
+	// Journals of individually compressed messages (see
+	// message.NewCompressedFraming) should typically use NONE, as already
+	// compressed content compresses poorly a second time.
 	CompressionCodec CompressionCodec `protobuf:"varint,2,opt,name=compression_codec,json=compressionCodec,proto3,enum=protocol.CompressionCodec" json:"compression_codec,omitempty" yaml:"compression_codec,omitempty"`
 	// Storage backend base path for this Journal's Fragments. Must be in URL
 	// form, with the choice of backend defined by the scheme. The full path of
@@ -437,6 +440,12 @@ type JournalSpec_Fragment struct {
 	Stores []FragmentStore `protobuf:"bytes,3,rep,name=stores,proto3,casttype=FragmentStore" json:"stores,omitempty" yaml:",omitempty"`
 	// Interval of time between refreshes of remote Fragment listings from
 	// configured fragment_stores.
+	//
+	// Journals written directly to their fragment stores by external tools
+	// may use a short interval, so that newly-arrived Fragments are readable
+	// promptly, while journals written only by brokers may use a long one to
+	// reduce listing load. Brokers add up to 10% of random jitter to the
+	// interval, which must be at least one second.
 	RefreshInterval time.Duration `protobuf:"bytes,4,opt,name=refresh_interval,json=refreshInterval,proto3,stdduration" json:"refresh_interval" yaml:"refresh_interval,omitempty"`
 	// Retention duration for historical Fragments of this Journal within the
 	// Fragment stores. If less than or equal to zero, Fragments are retained
@@ -470,6 +479,33 @@ type JournalSpec_Fragment struct {
 	//
 	// Which will produce a path postfix like "date=2019-11-19/hour=22".
 	PathPostfixTemplate string `protobuf:"bytes,7,opt,name=path_postfix_template,json=pathPostfixTemplate,proto3" json:"path_postfix_template,omitempty" yaml:"path_postfix_template,omitempty"`
+	// Level at which Fragments are compressed by the compression_codec, with
+	// meaning and range specific to the codec. Smaller levels are faster, and
+	// larger levels produce smaller Fragments. If zero, the codec's default
+	// level is used. Levels are ignored by codecs which don't support them
+	// (NONE and SNAPPY). Reads are unaffected by the level.
+	CompressionLevel int32 `protobuf:"varint,8,opt,name=compression_level,json=compressionLevel,proto3" json:"compression_level,omitempty" yaml:"compression_level,omitempty"`
+	// Adaptive interval enables adaptive Fragment lengths, where the target
+	// length of each Fragment is instead the journal's write rate multiplied by
+	// the adaptive interval, bounded by min_length and max_length. Journals
+	// which are written quickly then have larger Fragments (and fewer store
+	// objects), while journals written slowly have smaller Fragments which are
+	// persisted sooner. The write rate is smoothed over the adaptive interval,
+	// so that rapid changes of rate don't cause lengths to oscillate. Before a
+	// rate is observed, |length| is the target length. If zero, Fragment
+	// lengths are not adaptive.
+	AdaptiveInterval time.Duration `protobuf:"bytes,9,opt,name=adaptive_interval,json=adaptiveInterval,proto3,stdduration" json:"adaptive_interval" yaml:"adaptive_interval,omitempty"`
+	// Minimum target length of adaptive Fragments. Required if adaptive_interval
+	// is set, and must not be greater than |length|.
+	MinLength int64 `protobuf:"varint,10,opt,name=min_length,json=minLength,proto3" json:"min_length,omitempty" yaml:"min_length,omitempty"`
+	// Maximum target length of adaptive Fragments. Required if adaptive_interval
+	// is set, and must not be less than |length|.
+	MaxLength int64 `protobuf:"varint,11,opt,name=max_length,json=maxLength,proto3" json:"max_length,omitempty" yaml:"max_length,omitempty"`
+	// Maximum content length of the journal's open Fragment spool, held in
+	// broker memory. A spool which exceeds it is rolled and persisted ahead of its
+	// next append, even if it's under its target length. If zero, there is no
+	// maximum.
+	MaxSpoolBytes int64 `protobuf:"varint,12,opt,name=max_spool_bytes,json=maxSpoolBytes,proto3" json:"max_spool_bytes,omitempty" yaml:"max_spool_bytes,omitempty"`
 }
 
 func (m *JournalSpec_Fragment) Reset()         { *m = JournalSpec_Fragment{} }
@@ -951,6 +987,17 @@ type AppendRequest struct {
 	// indicate the Append should be committed. Absence of this empty chunk
 	// prior to EOF is interpreted by the broker as a rollback of the Append.
 	Content []byte `protobuf:"bytes,4,opt,name=content,proto3" json:"content,omitempty"`
+	// If flush is true, the journal's current Fragment is rolled ahead of this
+	// append, which prompts its persistence to the journal's fragment store.
+	// Content appended prior to this append is thus promptly persisted, rather
+	// than awaiting the Fragment's target length or flush interval.
+	Flush bool `protobuf:"varint,9,opt,name=flush,proto3" json:"flush,omitempty"`
+	// If check_offset is true, |offset| is the expected write head of the
+	// journal and the append is conditional upon it: if the journal's write head
+	// differs, the RPC is failed with WRONG_APPEND_OFFSET. Unlike a non-zero
+	// |offset| alone, a zero |offset| is also checked, and requires that nothing
+	// has yet been written to the journal.
+	CheckOffset bool `protobuf:"varint,10,opt,name=check_offset,json=checkOffset,proto3" json:"check_offset,omitempty"`
 }
 
 func (m *AppendRequest) Reset()         { *m = AppendRequest{} }
@@ -995,7 +1042,8 @@ type AppendResponse struct {
 	// If status is OK, then |commit| is the Fragment which places the
 	// committed Append content within the Journal.
 	Commit *Fragment `protobuf:"bytes,3,opt,name=commit,proto3" json:"commit,omitempty"`
-	// Current registers of the journal.
+	// Current registers of the journal. Set if status is OK, REGISTER_MISMATCH,
+	// or WRONG_APPEND_OFFSET.
 	Registers *LabelSet `protobuf:"bytes,4,opt,name=registers,proto3" json:"registers,omitempty"`
 	// Total number of RPC content chunks processed in this append.
 	TotalChunks int64 `protobuf:"varint,5,opt,name=total_chunks,json=totalChunks,proto3" json:"total_chunks,omitempty"`
@@ -1694,6 +1742,105 @@ func (m *Header_Etcd) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_Header_Etcd proto.InternalMessageInfo
 
+// WatchRequest is the request message of the broker Watch RPC.
+type WatchRequest struct {
+	// Selector optionally refines the set of journals which are watched,
+	// with the same semantics as ListRequest.selector.
+	Selector LabelSelector `protobuf:"bytes,1,opt,name=selector,proto3" json:"selector"`
+	// Revision through which the client is already current, which is the
+	// |revision| of the last WatchResponse it applied. If non-zero, the watch
+	// begins with journals which changed after |resume_revision|. If zero, or if
+	// changes after |resume_revision| are no longer retained by the broker, it
+	// begins with a snapshot.
+	ResumeRevision int64 `protobuf:"varint,2,opt,name=resume_revision,json=resumeRevision,proto3" json:"resume_revision,omitempty"`
+}
+
+func (m *WatchRequest) Reset()         { *m = WatchRequest{} }
+func (m *WatchRequest) String() string { return proto.CompactTextString(m) }
+func (*WatchRequest) ProtoMessage()    {}
+func (*WatchRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_0c0999e5af553218, []int{22}
+}
+func (m *WatchRequest) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *WatchRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_WatchRequest.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *WatchRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_WatchRequest.Merge(m, src)
+}
+func (m *WatchRequest) XXX_Size() int {
+	return m.ProtoSize()
+}
+func (m *WatchRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_WatchRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_WatchRequest proto.InternalMessageInfo
+
+// WatchResponse is the streamed response message of the broker Watch RPC.
+type WatchResponse struct {
+	// Status of the Watch RPC.
+	Status Status `protobuf:"varint,1,opt,name=status,proto3,enum=protocol.Status" json:"status,omitempty"`
+	// Header of the response.
+	Header Header `protobuf:"bytes,2,opt,name=header,proto3" json:"header"`
+	// Revision through which the client is current, having applied this
+	// response. A client which re-connects passes it as |resume_revision|.
+	// Journals of the response may reflect changes after |revision|, which
+	// will be sent again by a resumed watch.
+	Revision int64 `protobuf:"varint,3,opt,name=revision,proto3" json:"revision,omitempty"`
+	// If true, |journals| are all journals which match the selector, and the
+	// client should discard any other journals it knows of.
+	Snapshot bool `protobuf:"varint,4,opt,name=snapshot,proto3" json:"snapshot,omitempty"`
+	// Journals which were created, or whose specification or route changed.
+	Journals []ListResponse_Journal `protobuf:"bytes,5,rep,name=journals,proto3" json:"journals"`
+	// Journals which were deleted, or which no longer match the selector.
+	Deleted []Journal `protobuf:"bytes,6,rep,name=deleted,proto3,casttype=Journal" json:"deleted,omitempty"`
+}
+
+func (m *WatchResponse) Reset()         { *m = WatchResponse{} }
+func (m *WatchResponse) String() string { return proto.CompactTextString(m) }
+func (*WatchResponse) ProtoMessage()    {}
+func (*WatchResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_0c0999e5af553218, []int{23}
+}
+func (m *WatchResponse) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *WatchResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_WatchResponse.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *WatchResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_WatchResponse.Merge(m, src)
+}
+func (m *WatchResponse) XXX_Size() int {
+	return m.ProtoSize()
+}
+func (m *WatchResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_WatchResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_WatchResponse proto.InternalMessageInfo
+
 func init() {
 	proto.RegisterEnum("protocol.Status", Status_name, Status_value)
 	golang_proto.RegisterEnum("protocol.Status", Status_name, Status_value)
@@ -1757,6 +1904,10 @@ func init() {
 	golang_proto.RegisterType((*Header)(nil), "protocol.Header")
 	proto.RegisterType((*Header_Etcd)(nil), "protocol.Header.Etcd")
 	golang_proto.RegisterType((*Header_Etcd)(nil), "protocol.Header.Etcd")
+	proto.RegisterType((*WatchRequest)(nil), "protocol.WatchRequest")
+	golang_proto.RegisterType((*WatchRequest)(nil), "protocol.WatchRequest")
+	proto.RegisterType((*WatchResponse)(nil), "protocol.WatchResponse")
+	golang_proto.RegisterType((*WatchResponse)(nil), "protocol.WatchResponse")
 }
 
 func init() { proto.RegisterFile("broker/protocol/protocol.proto", fileDescriptor_0c0999e5af553218) }
@@ -1765,169 +1916,183 @@ func init() {
 }
 
 var fileDescriptor_0c0999e5af553218 = []byte{
-	// 2577 bytes of a gzipped FileDescriptorProto
+	// 2803 bytes of a gzipped FileDescriptorProto
 	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xb4, 0x59, 0x4d, 0x70, 0xdb, 0xc6,
-	0x15, 0x16, 0x48, 0x90, 0x04, 0x1f, 0x49, 0x09, 0xda, 0xc4, 0xb6, 0xcc, 0xc4, 0xa2, 0xc2, 0xfc,
-	0x8c, 0xec, 0x24, 0x74, 0xa2, 0xb4, 0x49, 0xea, 0x4e, 0xda, 0x80, 0x22, 0x65, 0xd3, 0xa1, 0x49,
-	0xce, 0x92, 0x8a, 0xe3, 0x1c, 0x8a, 0x81, 0x80, 0x15, 0x85, 0x0a, 0x04, 0x50, 0x00, 0x74, 0xa4,
-	0xdc, 0x72, 0x69, 0x33, 0x9d, 0x74, 0xa6, 0xd3, 0x53, 0x4e, 0x9d, 0x5c, 0x7a, 0x6e, 0xcf, 0xed,
-	0x74, 0xa6, 0x47, 0xf7, 0x96, 0x63, 0x67, 0xda, 0xaa, 0xd3, 0xf8, 0xd2, 0xb3, 0x8f, 0x39, 0x75,
-	0xf6, 0x07, 0x24, 0x44, 0x91, 0x96, 0x7d, 0xf0, 0x45, 0x83, 0x7d, 0x7f, 0xfb, 0xf6, 0xbd, 0xb7,
-	0xdf, 0xbe, 0x47, 0xc1, 0xfa, 0x5e, 0xe0, 0x1d, 0x92, 0xe0, 0xba, 0x1f, 0x78, 0x91, 0x67, 0x7a,
-	0xce, 0xe4, 0xa3, 0xc6, 0x3e, 0x90, 0x12, 0xaf, 0xcb, 0xcf, 0x0f, 0xbd, 0xa1, 0xc7, 0x56, 0xd7,
-	0xe9, 0x17, 0xe7, 0x97, 0xd7, 0x87, 0x9e, 0x37, 0x74, 0x08, 0x57, 0xdb, 0x1b, 0xef, 0x5f, 0xb7,
-	0xc6, 0x81, 0x11, 0xd9, 0x9e, 0xcb, 0xf9, 0xd5, 0xf7, 0x20, 0xd3, 0x36, 0xf6, 0x88, 0x83, 0x10,
-	0xc8, 0xae, 0x31, 0x22, 0x6b, 0xd2, 0x86, 0xb4, 0x99, 0xc7, 0xec, 0x1b, 0x3d, 0x0f, 0x99, 0xfb,
-	0x86, 0x33, 0x26, 0x6b, 0x29, 0x46, 0xe4, 0x8b, 0x1b, 0xf2, 0xff, 0xbe, 0xa9, 0x48, 0xd5, 0x01,
-	0x28, 0x4c, 0xb1, 0x4f, 0x22, 0x54, 0x87, 0xac, 0x43, 0xbf, 0xc3, 0x35, 0x69, 0x23, 0xbd, 0x59,
-	0xd8, 0x5a, 0xa9, 0x4d, 0xbc, 0x64, 0x32, 0xf5, 0xcb, 0x0f, 0x4e, 0x2a, 0x4b, 0x8f, 0x4e, 0x2a,
-	0xab, 0xc7, 0xc6, 0xc8, 0xb9, 0x51, 0x7d, 0xc3, 0x1b, 0xd9, 0x11, 0x19, 0xf9, 0xd1, 0x71, 0x15,
-	0x0b, 0x4d, 0x61, 0xf5, 0x0b, 0x09, 0x4a, 0xc2, 0xac, 0x43, 0xcc, 0xc8, 0x0b, 0xd0, 0x16, 0xe4,
-	0x6c, 0xd7, 0x74, 0xc6, 0x16, 0x77, 0xad, 0xb0, 0x85, 0x66, 0x8c, 0xf7, 0x49, 0x54, 0x97, 0xa9,
-	0x7d, 0x1c, 0x0b, 0x52, 0x1d, 0x72, 0xc4, 0x75, 0x52, 0xe7, 0xe9, 0x08, 0xc1, 0x1b, 0xca, 0xd7,
-	0xdf, 0x54, 0x96, 0x98, 0x0f, 0x5f, 0xe5, 0xa1, 0x70, 0xdb, 0x1b, 0x07, 0xae, 0xe1, 0xf4, 0x7d,
-	0x62, 0xa2, 0x1f, 0x24, 0x23, 0x53, 0xdf, 0x98, 0x7b, 0x8c, 0xef, 0x4f, 0x2a, 0x39, 0xa1, 0x23,
-	0x62, 0xf7, 0x1e, 0x14, 0x02, 0xe2, 0x3b, 0xb6, 0xc9, 0xa2, 0xcd, 0xfc, 0xc8, 0xd4, 0x2f, 0xcc,
-	0x8f, 0x41, 0x52, 0x12, 0xf5, 0x26, 0xc1, 0x4c, 0x2f, 0xf4, 0xfd, 0x15, 0xea, 0xfb, 0xb7, 0x27,
-	0x15, 0xe9, 0xd1, 0x49, 0x65, 0x6d, 0xd6, 0xde, 0x1b, 0xb6, 0xeb, 0xd8, 0x2e, 0x99, 0x84, 0x16,
-	0xed, 0x82, 0xb2, 0x1f, 0x18, 0xc3, 0x11, 0x71, 0xa3, 0x35, 0x99, 0xd9, 0x5c, 0x9f, 0xda, 0x4c,
-	0x9c, 0xb4, 0xb6, 0x23, 0xa4, 0x1e, 0x97, 0xaf, 0x89, 0x29, 0xf4, 0x53, 0xc8, 0xec, 0x3b, 0xc6,
-	0x30, 0x5c, 0xcb, 0x6e, 0x48, 0x9b, 0xa5, 0xfa, 0xd5, 0x45, 0x81, 0x51, 0x13, 0x5b, 0xe8, 0x3b,
-	0x8e, 0x31, 0xc4, 0x5c, 0x0f, 0xb5, 0x61, 0x65, 0x64, 0x1c, 0xe9, 0x86, 0xef, 0x13, 0xd7, 0xd2,
-	0x03, 0x23, 0x22, 0x6b, 0xb9, 0x0d, 0x69, 0x33, 0x5d, 0x7f, 0xe5, 0xd1, 0x49, 0x65, 0x83, 0x9b,
-	0x9a, 0x11, 0x48, 0x7a, 0x52, 0x1a, 0x19, 0x47, 0x1a, 0x63, 0x61, 0x23, 0x22, 0xe5, 0xaf, 0x32,
-	0xa0, 0xc4, 0x07, 0x40, 0x6f, 0x42, 0xd6, 0x21, 0xee, 0x30, 0x3a, 0x60, 0x59, 0x4b, 0x2f, 0x0a,
-	0xbc, 0x10, 0x42, 0x1e, 0xac, 0x9a, 0xde, 0xc8, 0x0f, 0x48, 0x18, 0xda, 0x9e, 0xab, 0x9b, 0x9e,
-	0x45, 0x4c, 0x96, 0xb2, 0xe5, 0xad, 0xf2, 0x34, 0x54, 0xdb, 0x53, 0x91, 0x6d, 0x2a, 0x51, 0x7f,
-	0xed, 0xd1, 0x49, 0xa5, 0xca, 0xad, 0x9e, 0x51, 0x4f, 0x6e, 0xa3, 0x9a, 0x33, 0x9a, 0xe8, 0x27,
-	0x90, 0x0d, 0x23, 0x2f, 0x20, 0x34, 0xc9, 0xe9, 0xcd, 0x3c, 0xb3, 0x34, 0x37, 0x78, 0xa5, 0xf8,
-	0x48, 0x7d, 0x2a, 0x8e, 0x85, 0x16, 0x0a, 0x41, 0x0d, 0xc8, 0x7e, 0x40, 0xc2, 0x03, 0xdd, 0x76,
-	0x23, 0x12, 0xdc, 0x37, 0x1c, 0x91, 0xda, 0xcb, 0x35, 0x7e, 0xe3, 0x6b, 0xf1, 0x8d, 0xaf, 0x35,
-	0xc4, 0x8d, 0xaf, 0xbf, 0x29, 0xb2, 0xfa, 0x12, 0xdf, 0x68, 0xd6, 0x40, 0x62, 0xe3, 0xaf, 0xff,
-	0x53, 0x91, 0xf0, 0x8a, 0x10, 0x68, 0x09, 0x3e, 0xfa, 0x18, 0xf2, 0x01, 0x89, 0x88, 0xcb, 0x0a,
-	0x3a, 0x73, 0xde, 0x6e, 0x57, 0x16, 0xd6, 0x10, 0xb3, 0x3e, 0x35, 0x85, 0x46, 0xb0, 0xbc, 0xef,
-	0x8c, 0x93, 0x47, 0xc9, 0x9e, 0x67, 0xfc, 0x75, 0x61, 0xbc, 0xc2, 0x8d, 0x9f, 0x56, 0x9f, 0xdd,
-	0xaa, 0xc4, 0xd8, 0x93, 0x63, 0xfc, 0x0c, 0x2e, 0xf8, 0x46, 0x74, 0xa0, 0xfb, 0x5e, 0x18, 0xed,
-	0xdb, 0x47, 0x3a, 0x15, 0x75, 0xe2, 0xe2, 0xcb, 0xd7, 0xaf, 0x3d, 0x3a, 0xa9, 0xbc, 0xc6, 0xcd,
-	0xce, 0x15, 0x4b, 0x26, 0xf6, 0x39, 0x2a, 0xd1, 0xe3, 0x02, 0x03, 0xc1, 0x17, 0x48, 0xa6, 0x81,
-	0x4c, 0x6b, 0x1d, 0xad, 0x42, 0xa9, 0xd3, 0x1d, 0xe8, 0xfd, 0x5e, 0x73, 0xbb, 0xb5, 0xd3, 0x6a,
-	0x36, 0xd4, 0x25, 0x54, 0x04, 0xa5, 0xab, 0xe3, 0x46, 0xb7, 0xd3, 0xbe, 0xa7, 0x4a, 0x7c, 0x75,
-	0x17, 0xb3, 0x55, 0x0a, 0x01, 0x64, 0x29, 0xef, 0x2e, 0x56, 0x65, 0x61, 0xe8, 0x0f, 0x12, 0x14,
-	0x7a, 0x81, 0x67, 0x92, 0x30, 0x64, 0x70, 0x54, 0x83, 0x94, 0x6d, 0x09, 0x2c, 0x5c, 0x9b, 0x16,
-	0x67, 0x42, 0xa4, 0xd6, 0x6a, 0x08, 0x74, 0x4b, 0xd9, 0x16, 0xda, 0x04, 0x85, 0xb8, 0x96, 0xef,
-	0xd9, 0x6e, 0xc4, 0x71, 0xbc, 0x5e, 0xfc, 0xfe, 0xa4, 0xa2, 0x34, 0x05, 0x0d, 0x4f, 0xb8, 0xe5,
-	0x77, 0x21, 0xd5, 0x6a, 0xd0, 0x87, 0xe0, 0x73, 0xcf, 0x9d, 0x3c, 0x04, 0xf4, 0x1b, 0x5d, 0x84,
-	0x6c, 0x38, 0xde, 0xdf, 0xb7, 0x8f, 0xc4, 0x4b, 0x20, 0x56, 0xdc, 0xc3, 0x1b, 0xf2, 0x97, 0xd4,
-	0xcf, 0x5f, 0x49, 0x00, 0x75, 0xf6, 0x58, 0x31, 0x37, 0x07, 0x50, 0xf4, 0xb9, 0x4b, 0x7a, 0xe8,
-	0x13, 0x53, 0x38, 0x7c, 0x61, 0xae, 0xc3, 0xf5, 0x72, 0x02, 0xcf, 0x96, 0x45, 0xbd, 0xc4, 0x28,
-	0x56, 0xf0, 0x13, 0x87, 0x7f, 0x19, 0x4a, 0x3f, 0xe7, 0x68, 0xa2, 0x3b, 0xf6, 0xc8, 0xe6, 0x27,
-	0x2a, 0xe1, 0xa2, 0x20, 0xb6, 0x29, 0xad, 0xfa, 0xcf, 0x54, 0x02, 0x09, 0x5e, 0x85, 0x9c, 0x60,
-	0x0a, 0x00, 0x2f, 0x24, 0xb1, 0x3a, 0xe6, 0xa1, 0x0d, 0xc8, 0xec, 0x91, 0xa1, 0xcd, 0x81, 0x3a,
-	0x5d, 0x87, 0xef, 0x4f, 0x2a, 0xd9, 0xee, 0xfe, 0x7e, 0x48, 0x22, 0xcc, 0x19, 0xe8, 0x45, 0x48,
-	0x13, 0xd7, 0x62, 0xa0, 0x7c, 0x9a, 0x4f, 0xc9, 0xe8, 0x2a, 0xa4, 0xc3, 0xf1, 0x48, 0xdc, 0xc1,
-	0xd5, 0xe9, 0x29, 0xfb, 0xb7, 0xb4, 0xb7, 0xfb, 0xe3, 0x91, 0xc8, 0x07, 0x95, 0x41, 0x37, 0xe7,
-	0x81, 0x4d, 0xe6, 0x3c, 0xb0, 0x99, 0x03, 0x22, 0xef, 0x42, 0x69, 0xcf, 0x30, 0x0f, 0x6d, 0x77,
-	0xa8, 0x33, 0x58, 0x60, 0xd7, 0x26, 0x5f, 0x5f, 0x3d, 0x0b, 0x1b, 0x45, 0x21, 0xc7, 0x56, 0xe8,
-	0x32, 0x28, 0x23, 0xcf, 0xd2, 0x23, 0x7b, 0x24, 0x00, 0x17, 0xe7, 0x46, 0x9e, 0x35, 0xb0, 0x47,
-	0x04, 0xbd, 0x04, 0xc5, 0x64, 0xd1, 0xaf, 0x29, 0x2c, 0xdd, 0x85, 0x44, 0x99, 0x57, 0x3f, 0x82,
-	0x9c, 0x38, 0x14, 0xed, 0x0f, 0x7c, 0x23, 0x88, 0xde, 0x66, 0x91, 0xcd, 0x62, 0xbe, 0x88, 0xa9,
-	0x5b, 0x2c, 0x94, 0x82, 0xba, 0x15, 0x53, 0xdf, 0x61, 0x01, 0xcc, 0x71, 0xea, 0x3b, 0xd5, 0x3f,
-	0xa5, 0xa0, 0x80, 0x89, 0x61, 0x61, 0xf2, 0x8b, 0x31, 0x09, 0x23, 0xb4, 0x09, 0xd9, 0x03, 0x62,
-	0x58, 0x24, 0x10, 0xf5, 0xa2, 0x4e, 0x03, 0x72, 0x8b, 0xd1, 0xb1, 0xe0, 0x27, 0xf3, 0x9a, 0x7a,
-	0x4c, 0x5e, 0xab, 0x90, 0xf5, 0x58, 0x9a, 0xe6, 0x24, 0x4e, 0x70, 0xa8, 0x6b, 0x7b, 0x8e, 0x67,
-	0x1e, 0xb2, 0xec, 0x29, 0x98, 0x2f, 0xd0, 0x06, 0x14, 0x2d, 0x4f, 0x77, 0xbd, 0x48, 0xf7, 0x03,
-	0xef, 0xe8, 0x98, 0x65, 0x48, 0xc1, 0x60, 0x79, 0x1d, 0x2f, 0xea, 0x51, 0x0a, 0x2d, 0xc6, 0x11,
-	0x89, 0x0c, 0xcb, 0x88, 0x0c, 0xdd, 0x73, 0x9d, 0x63, 0x16, 0x7f, 0x05, 0x17, 0x63, 0x62, 0xd7,
-	0x75, 0x8e, 0xd1, 0x55, 0x00, 0xfa, 0x78, 0x09, 0x27, 0x72, 0x67, 0x9c, 0xc8, 0x13, 0xd7, 0xe2,
-	0x9f, 0xe8, 0x15, 0x58, 0x66, 0xa5, 0xa6, 0x4f, 0xb2, 0xa3, 0xb0, 0xec, 0x14, 0x19, 0xf5, 0x0e,
-	0x4f, 0x51, 0xf5, 0xf7, 0x29, 0x28, 0xf2, 0x90, 0x85, 0xbe, 0xe7, 0x86, 0x84, 0xc6, 0x2c, 0x8c,
-	0x8c, 0x68, 0x1c, 0xb2, 0x98, 0x2d, 0x27, 0x63, 0xd6, 0x67, 0x74, 0x2c, 0xf8, 0x89, 0xe8, 0xa6,
-	0xce, 0x89, 0xee, 0x93, 0x84, 0xed, 0x2a, 0xc0, 0x67, 0x81, 0x1d, 0x11, 0x9d, 0xea, 0xb0, 0xd8,
-	0xcd, 0x9c, 0x8c, 0x71, 0xa9, 0x61, 0x54, 0x4b, 0x74, 0x20, 0x99, 0xd9, 0xae, 0x26, 0x2e, 0xd5,
-	0x44, 0x6b, 0xf1, 0x12, 0x14, 0xe3, 0x6f, 0x7d, 0x1c, 0xf0, 0xf7, 0x20, 0x8f, 0x0b, 0x31, 0x6d,
-	0x37, 0x70, 0xd0, 0x1a, 0xe4, 0x4c, 0xcf, 0xa5, 0x4f, 0x08, 0x0b, 0x6a, 0x11, 0xc7, 0xcb, 0xea,
-	0x97, 0x69, 0x28, 0x89, 0xbe, 0xe0, 0x59, 0x55, 0xd5, 0x6c, 0x6d, 0xa4, 0xcf, 0xd4, 0xc6, 0x34,
-	0x80, 0x99, 0x85, 0x01, 0xfc, 0x10, 0x56, 0xcc, 0x03, 0x62, 0x1e, 0xea, 0x01, 0x19, 0xda, 0x61,
-	0x44, 0x82, 0x50, 0x3c, 0x7c, 0x97, 0xce, 0xb4, 0x7c, 0xbc, 0x19, 0xc6, 0xcb, 0x4c, 0x1e, 0xc7,
-	0xe2, 0xe8, 0xc7, 0xb0, 0x32, 0x76, 0x29, 0x88, 0x4c, 0x2d, 0xe4, 0x16, 0x35, 0x8d, 0x78, 0x99,
-	0x89, 0x4e, 0x95, 0x35, 0x40, 0xe1, 0x78, 0x2f, 0x0a, 0x0c, 0x33, 0x4a, 0xe8, 0x2b, 0x0b, 0xf5,
-	0x57, 0x63, 0xe9, 0xa9, 0x89, 0x44, 0x12, 0xe4, 0x53, 0x49, 0x10, 0x6f, 0xd7, 0xef, 0x52, 0xb0,
-	0x1c, 0xa7, 0xe2, 0xa9, 0xab, 0xb5, 0x76, 0x5e, 0xb5, 0x0a, 0x50, 0x8d, 0x73, 0x77, 0x0d, 0xb2,
-	0xa6, 0x37, 0xa2, 0x8f, 0x42, 0x7a, 0x61, 0x89, 0x09, 0x09, 0xf4, 0x16, 0x6d, 0x65, 0xe2, 0x23,
-	0xcb, 0x0b, 0x8f, 0x3c, 0x15, 0xa2, 0x25, 0x19, 0x79, 0x91, 0xe1, 0xe8, 0xe6, 0xc1, 0xd8, 0x3d,
-	0x0c, 0x79, 0x5a, 0x71, 0x81, 0xd1, 0xb6, 0x19, 0x09, 0xbd, 0x0a, 0xcb, 0x16, 0x71, 0x8c, 0x63,
-	0x62, 0xc5, 0x42, 0x59, 0x26, 0x54, 0x12, 0x54, 0x2e, 0x56, 0xfd, 0x4b, 0x0a, 0x54, 0x2c, 0x1a,
-	0x7e, 0xf2, 0xf4, 0x25, 0x5a, 0x03, 0x3a, 0xf3, 0xf9, 0x5e, 0x68, 0x38, 0x8f, 0x39, 0xe8, 0x44,
-	0xe6, 0xf4, 0x51, 0x73, 0x4f, 0x72, 0xd4, 0x0d, 0x28, 0x18, 0xe6, 0xa1, 0xeb, 0x7d, 0xe6, 0x10,
-	0x6b, 0x48, 0x04, 0xaa, 0x25, 0x49, 0xe8, 0x06, 0x20, 0x8b, 0xf8, 0x01, 0xa1, 0x27, 0xb0, 0xf4,
-	0xc7, 0xdc, 0x98, 0xd5, 0xa9, 0x98, 0x20, 0x2d, 0xae, 0x19, 0x8a, 0xa7, 0xe2, 0x53, 0xb7, 0x88,
-	0x13, 0x19, 0x22, 0xc6, 0x45, 0x41, 0x6c, 0x50, 0x5a, 0xf5, 0xef, 0x12, 0xac, 0x26, 0xa2, 0xf7,
-	0x0c, 0x31, 0x30, 0x09, 0x5a, 0xe9, 0x27, 0x00, 0xad, 0xa7, 0xae, 0xa9, 0xea, 0x00, 0x0a, 0x6d,
-	0x3b, 0x8c, 0xe2, 0x1a, 0xf8, 0x11, 0x28, 0xa1, 0xb8, 0xe9, 0xa2, 0x0a, 0x16, 0x01, 0x81, 0xa8,
-	0xfc, 0x89, 0xf8, 0x6d, 0x59, 0x49, 0xa9, 0xe9, 0xdb, 0xb2, 0x92, 0x56, 0xe5, 0xea, 0x5f, 0x53,
-	0x50, 0xe4, 0x66, 0x9f, 0xf9, 0x95, 0xfb, 0x10, 0x14, 0x91, 0x7c, 0x3e, 0xc8, 0x9c, 0x9a, 0x2c,
-	0x93, 0x3e, 0xc4, 0x63, 0x66, 0xec, 0x78, 0xac, 0x55, 0xfe, 0xb5, 0x04, 0x71, 0xb1, 0xa0, 0xeb,
-	0x20, 0xcf, 0x6f, 0x15, 0x13, 0x03, 0xa4, 0x30, 0xc0, 0x04, 0xe9, 0x9d, 0xa4, 0x4f, 0x65, 0x40,
-	0xee, 0xdb, 0x61, 0x3c, 0x64, 0xa7, 0x71, 0x61, 0xe4, 0x59, 0x58, 0x90, 0xd0, 0xeb, 0x90, 0x09,
-	0xbc, 0x71, 0x44, 0x44, 0x06, 0x13, 0xbf, 0x4c, 0x60, 0x4a, 0x16, 0xe6, 0xb8, 0xcc, 0x6d, 0x59,
-	0x91, 0xd5, 0x4c, 0xf5, 0x5f, 0x12, 0x14, 0x35, 0xdf, 0x77, 0x8e, 0xe3, 0xbc, 0x7c, 0x00, 0x39,
-	0xf3, 0xc0, 0x70, 0x87, 0x24, 0xfe, 0x7d, 0xe3, 0xca, 0xd4, 0x4a, 0x52, 0xb0, 0xb6, 0xcd, 0xa4,
-	0xe2, 0x5f, 0x16, 0x84, 0x4e, 0xf9, 0x2b, 0x09, 0xb2, 0x9c, 0x83, 0x6a, 0xf0, 0x1c, 0x39, 0xf2,
-	0x89, 0x19, 0xe9, 0xa7, 0xfc, 0x66, 0x33, 0x2a, 0x5e, 0xe5, 0xac, 0x3b, 0x09, 0xef, 0xdf, 0x84,
-	0xec, 0xd8, 0x0f, 0x49, 0x10, 0x89, 0x7c, 0xcc, 0x8f, 0x09, 0x16, 0x42, 0xe8, 0x65, 0xc8, 0x5a,
-	0xc4, 0x21, 0xe2, 0xb4, 0x33, 0x57, 0x51, 0xb0, 0xaa, 0x36, 0x7b, 0x1d, 0xa9, 0xd3, 0xcf, 0xba,
-	0x3c, 0xaa, 0xff, 0x4e, 0x81, 0x1a, 0x5f, 0x94, 0xf0, 0x99, 0x3d, 0xc6, 0x67, 0xdb, 0xa6, 0xf4,
-	0xd9, 0xb6, 0x89, 0x3e, 0xd9, 0xb4, 0x0f, 0x9b, 0xc8, 0xb0, 0x7e, 0x05, 0xd3, 0xde, 0x2c, 0x96,
-	0x78, 0x0d, 0x56, 0x5c, 0x72, 0x14, 0xe9, 0xbe, 0x31, 0x24, 0x7a, 0xe4, 0x1d, 0x12, 0x57, 0x00,
-	0x50, 0x89, 0x92, 0x7b, 0xc6, 0x90, 0x0c, 0x28, 0x11, 0x5d, 0x01, 0x60, 0x22, 0x7c, 0x00, 0xa1,
-	0xe8, 0x98, 0xc1, 0x79, 0x4a, 0x61, 0xd3, 0x07, 0xba, 0x09, 0xc5, 0xd0, 0x1e, 0xba, 0x46, 0x34,
-	0x0e, 0xc8, 0x60, 0xd0, 0x16, 0x90, 0xfb, 0x98, 0x59, 0x56, 0x79, 0x70, 0x52, 0x91, 0xd8, 0xa0,
-	0x7a, 0x4a, 0xf1, 0x4c, 0x93, 0xa1, 0xcc, 0x36, 0x19, 0xd5, 0x3f, 0xa7, 0x60, 0x35, 0x11, 0xdf,
-	0x67, 0x7e, 0xdd, 0x5b, 0x90, 0x8f, 0xd1, 0x2e, 0xbe, 0xef, 0xaf, 0x9e, 0x85, 0xc4, 0x89, 0x27,
-	0x35, 0x7d, 0xf2, 0x83, 0x12, 0xb7, 0x33, 0xd5, 0x9e, 0x17, 0x6c, 0x79, 0x4e, 0xb0, 0xcb, 0x9f,
-	0x40, 0x7e, 0x62, 0x05, 0xbd, 0x71, 0x0a, 0x20, 0xe6, 0xa0, 0xf1, 0x29, 0x74, 0xb8, 0x02, 0x40,
-	0xe3, 0x49, 0x2c, 0xd6, 0x42, 0xf2, 0xc1, 0x35, 0xcf, 0x29, 0xbb, 0x81, 0x53, 0xfd, 0x8d, 0x04,
-	0x19, 0x86, 0x01, 0xe8, 0x7d, 0xc8, 0x8d, 0xc8, 0x68, 0x8f, 0xc2, 0x36, 0xbf, 0xdf, 0xe7, 0x8d,
-	0xd5, 0xb1, 0x38, 0x7d, 0xcb, 0xfc, 0xc0, 0x1e, 0x19, 0xc1, 0x31, 0xff, 0x81, 0x0f, 0xc7, 0x4b,
-	0x74, 0x0d, 0xf2, 0xf1, 0x5c, 0x1d, 0xff, 0xc6, 0x73, 0x7a, 0xec, 0x9e, 0xb2, 0x45, 0xaf, 0xf4,
-	0xc7, 0x14, 0x64, 0x79, 0xd4, 0xd1, 0x07, 0x00, 0xf1, 0xec, 0xfc, 0xc4, 0xa3, 0x7e, 0x5e, 0x68,
-	0xb4, 0xac, 0x29, 0xe6, 0xa5, 0xce, 0xc7, 0x3c, 0x0a, 0xba, 0x24, 0x32, 0x2d, 0x81, 0x8f, 0x17,
-	0x66, 0x2b, 0xa0, 0xd6, 0x8c, 0x4c, 0x2b, 0x0e, 0x2b, 0x15, 0x2c, 0x7f, 0x21, 0x81, 0x4c, 0x89,
-	0x34, 0xbe, 0xa6, 0x33, 0xa6, 0x2f, 0x59, 0xec, 0xa5, 0x8c, 0xf3, 0x82, 0xd2, 0xb2, 0xd0, 0x0b,
-	0x90, 0xe7, 0x61, 0xa2, 0xdc, 0x14, 0xe3, 0x2a, 0x9c, 0xd0, 0xb2, 0x50, 0x19, 0x94, 0x09, 0xfa,
-	0xf1, 0xdb, 0x3a, 0x59, 0x53, 0xc5, 0xc0, 0xd8, 0x8f, 0xf4, 0x88, 0x04, 0x7c, 0xa0, 0x96, 0xb1,
-	0x42, 0x09, 0x03, 0x12, 0x8c, 0xe2, 0x5f, 0x1c, 0xe8, 0xdf, 0x6b, 0xdf, 0xa5, 0x20, 0xcb, 0x2b,
-	0x1a, 0x65, 0x21, 0xd5, 0xfd, 0x48, 0x5d, 0x42, 0x17, 0x60, 0xf5, 0x76, 0x77, 0x17, 0x77, 0xb4,
-	0xb6, 0xde, 0xe9, 0x0e, 0xf4, 0x9d, 0xee, 0x6e, 0xa7, 0xa1, 0x4a, 0xe8, 0x0a, 0x5c, 0xee, 0x74,
-	0xf5, 0x98, 0xd3, 0xc3, 0xad, 0x3b, 0x1a, 0xbe, 0xa7, 0xd7, 0x71, 0xf7, 0xa3, 0x26, 0x56, 0x53,
-	0x68, 0x1d, 0xca, 0x54, 0x7a, 0x01, 0x3f, 0x8d, 0x2e, 0x02, 0x4a, 0xf2, 0x05, 0x3d, 0x83, 0x36,
-	0xe0, 0xc5, 0x56, 0xa7, 0xbf, 0xbb, 0xb3, 0xd3, 0xda, 0x6e, 0x35, 0x3b, 0xb3, 0x02, 0x7d, 0x55,
-	0x46, 0x2f, 0xc2, 0x5a, 0x77, 0x67, 0xa7, 0xdf, 0x1c, 0x30, 0x77, 0xee, 0x35, 0x07, 0xba, 0xf6,
-	0xb1, 0xd6, 0x6a, 0x6b, 0xf5, 0x76, 0x53, 0xcd, 0xa2, 0x15, 0x28, 0xdc, 0xc5, 0xdd, 0xce, 0x4d,
-	0x1d, 0x77, 0x77, 0x07, 0x4d, 0x35, 0x47, 0xdd, 0xef, 0xe1, 0x6e, 0xaf, 0xdb, 0xd7, 0xda, 0xfa,
-	0x9d, 0x56, 0xff, 0x8e, 0x36, 0xd8, 0xbe, 0xa5, 0x2a, 0xe8, 0x05, 0xb8, 0xd4, 0x1c, 0x6c, 0x37,
-	0xf4, 0x01, 0xd6, 0x3a, 0x7d, 0x6d, 0x7b, 0xd0, 0xea, 0x76, 0xf4, 0x1d, 0xad, 0xd5, 0x6e, 0x36,
-	0xd4, 0x3c, 0x35, 0x42, 0x6d, 0x6b, 0xed, 0x76, 0xf7, 0x6e, 0xb3, 0xa1, 0x02, 0xba, 0x04, 0xcf,
-	0x71, 0xab, 0x5a, 0xaf, 0xd7, 0xec, 0x34, 0x74, 0xee, 0x80, 0x5a, 0xa0, 0xce, 0xb4, 0x3a, 0x8d,
-	0xe6, 0x27, 0xfa, 0x2d, 0xad, 0xaf, 0xdf, 0xc4, 0x4d, 0x6d, 0xd0, 0xc4, 0x31, 0xb7, 0x48, 0xf7,
-	0xc6, 0xcd, 0x9b, 0xad, 0x3e, 0x25, 0x4e, 0xf6, 0x2e, 0x5d, 0x73, 0x41, 0x9d, 0xfd, 0x29, 0x02,
-	0x15, 0x20, 0xd7, 0xea, 0x7c, 0xac, 0xb5, 0x5b, 0x0d, 0x75, 0x09, 0x29, 0x20, 0x77, 0xba, 0x9d,
-	0xa6, 0x2a, 0xd1, 0xaf, 0x9b, 0x9f, 0xb6, 0x7a, 0x6a, 0x0a, 0x95, 0x20, 0xff, 0x69, 0x7f, 0xa0,
-	0x75, 0x1a, 0x1a, 0x6e, 0xa8, 0x69, 0x04, 0x90, 0xed, 0x77, 0xb4, 0x5e, 0xef, 0x9e, 0x2a, 0xd3,
-	0x58, 0x53, 0x21, 0xba, 0x6f, 0xbb, 0xab, 0x35, 0xf4, 0x46, 0x73, 0xbb, 0x7b, 0xa7, 0x87, 0x9b,
-	0xfd, 0x7e, 0xab, 0xdb, 0x51, 0x33, 0x5b, 0xbf, 0x4c, 0x4f, 0x1b, 0x82, 0x1f, 0x82, 0x4c, 0x9b,
-	0x08, 0x74, 0x61, 0xb6, 0xa9, 0x60, 0x2f, 0x49, 0xf9, 0xe2, 0xfc, 0x5e, 0x03, 0xbd, 0x0f, 0x19,
-	0xf6, 0xc2, 0xa1, 0x8b, 0xf3, 0xdf, 0xe9, 0xf2, 0xa5, 0x33, 0x74, 0xa1, 0xf9, 0x1e, 0xc8, 0x74,
-	0xb4, 0x4e, 0x6e, 0x98, 0xf8, 0x75, 0x22, 0xb9, 0x61, 0x72, 0x02, 0x7f, 0x4b, 0x42, 0x1f, 0x40,
-	0x96, 0xcf, 0x39, 0xe8, 0xb4, 0xed, 0xe9, 0x10, 0x5a, 0x5e, 0x3b, 0xcb, 0xe0, 0xea, 0x9b, 0x12,
-	0xba, 0x05, 0xf9, 0x49, 0x4f, 0x8b, 0xca, 0xc9, 0x5d, 0x4e, 0x8f, 0x09, 0xe5, 0x17, 0xe6, 0xf2,
-	0x62, 0x3b, 0x6f, 0x51, 0x4b, 0x25, 0x1a, 0x8b, 0x09, 0x16, 0x27, 0xad, 0xcd, 0x3e, 0xc5, 0x49,
-	0x6b, 0x67, 0xc0, 0xbb, 0xde, 0x7c, 0xf0, 0xdf, 0xf5, 0xa5, 0x07, 0xdf, 0xad, 0x4b, 0xdf, 0x7e,
-	0xb7, 0x2e, 0xfd, 0xf6, 0xe1, 0xfa, 0xd2, 0x37, 0x0f, 0xd7, 0xa5, 0xbf, 0x3d, 0x5c, 0x97, 0xbe,
-	0x7d, 0xb8, 0xbe, 0xf4, 0x8f, 0x87, 0xeb, 0x4b, 0x9f, 0xbe, 0x3c, 0xf4, 0x6a, 0x43, 0xe3, 0x73,
-	0x12, 0x45, 0xa4, 0x66, 0x91, 0xfb, 0xd7, 0x4d, 0x2f, 0x20, 0xd7, 0x67, 0xfe, 0x5f, 0xb5, 0x97,
-	0x65, 0x5f, 0xef, 0xfc, 0x3f, 0x00, 0x00, 0xff, 0xff, 0x8a, 0xcf, 0x98, 0xbc, 0xc9, 0x1a, 0x00,
-	0x00,
+	0x15, 0x16, 0xf8, 0x0b, 0x3e, 0x92, 0x12, 0xb4, 0x89, 0x6d, 0x9a, 0x8e, 0x45, 0x85, 0x4e, 0x5c,
+	0xd9, 0x49, 0xe8, 0x44, 0x69, 0x93, 0xd4, 0x9d, 0xa4, 0x21, 0x45, 0xca, 0xa6, 0x43, 0x93, 0x9c,
+	0x25, 0x15, 0xc7, 0x39, 0x14, 0x03, 0x01, 0x2b, 0x0a, 0x15, 0x08, 0xa0, 0x00, 0xa8, 0x48, 0xb9,
+	0xe5, 0xd4, 0x4e, 0xa6, 0x9d, 0xe9, 0xf4, 0x94, 0x53, 0x27, 0x97, 0x4e, 0x8e, 0xed, 0xb9, 0x9d,
+	0xce, 0xb4, 0x3d, 0xb9, 0xb7, 0x1c, 0x3b, 0xd3, 0x56, 0x9d, 0xc6, 0x97, 0x9e, 0x7d, 0xf4, 0xa9,
+	0xb3, 0x3f, 0x20, 0x21, 0x92, 0x92, 0x9c, 0x76, 0x74, 0xe3, 0xbe, 0x3f, 0xbc, 0xfd, 0xde, 0xdb,
+	0xf7, 0xde, 0x2e, 0x61, 0x65, 0xdb, 0x73, 0xf6, 0x88, 0x77, 0xcb, 0xf5, 0x9c, 0xc0, 0xd1, 0x1d,
+	0x6b, 0xfc, 0xa3, 0xc2, 0x7e, 0x20, 0x39, 0x5c, 0x17, 0x9f, 0x1f, 0x38, 0x03, 0x87, 0xad, 0x6e,
+	0xd1, 0x5f, 0x9c, 0x5f, 0x5c, 0x19, 0x38, 0xce, 0xc0, 0x22, 0x5c, 0x6d, 0x7b, 0xb4, 0x73, 0xcb,
+	0x18, 0x79, 0x5a, 0x60, 0x3a, 0x36, 0xe7, 0x97, 0xdf, 0x86, 0x64, 0x4b, 0xdb, 0x26, 0x16, 0x42,
+	0x90, 0xb0, 0xb5, 0x21, 0x29, 0x48, 0xab, 0xd2, 0x5a, 0x06, 0xb3, 0xdf, 0xe8, 0x79, 0x48, 0xee,
+	0x6b, 0xd6, 0x88, 0x14, 0x62, 0x8c, 0xc8, 0x17, 0xb7, 0x13, 0xff, 0xf9, 0xb2, 0x24, 0x95, 0xfb,
+	0x20, 0x33, 0xc5, 0x1e, 0x09, 0x50, 0x0d, 0x52, 0x16, 0xfd, 0xed, 0x17, 0xa4, 0xd5, 0xf8, 0x5a,
+	0x76, 0x7d, 0xa9, 0x32, 0xf6, 0x92, 0xc9, 0xd4, 0x2e, 0x3f, 0x3a, 0x2a, 0x2d, 0x3c, 0x39, 0x2a,
+	0x2d, 0x1f, 0x6a, 0x43, 0xeb, 0x76, 0xf9, 0x55, 0x67, 0x68, 0x06, 0x64, 0xe8, 0x06, 0x87, 0x65,
+	0x2c, 0x34, 0x85, 0xd5, 0xcf, 0x24, 0xc8, 0x0b, 0xb3, 0x16, 0xd1, 0x03, 0xc7, 0x43, 0xeb, 0x90,
+	0x36, 0x6d, 0xdd, 0x1a, 0x19, 0xdc, 0xb5, 0xec, 0x3a, 0x9a, 0x32, 0xde, 0x23, 0x41, 0x2d, 0x41,
+	0xed, 0xe3, 0x50, 0x90, 0xea, 0x90, 0x03, 0xae, 0x13, 0x3b, 0x4b, 0x47, 0x08, 0xde, 0x96, 0xbf,
+	0xf8, 0xb2, 0xb4, 0xc0, 0x7c, 0xf8, 0x2a, 0x07, 0xd9, 0x7b, 0xce, 0xc8, 0xb3, 0x35, 0xab, 0xe7,
+	0x12, 0x1d, 0x7d, 0x37, 0x8a, 0x4c, 0x6d, 0x75, 0xee, 0x36, 0x9e, 0x1e, 0x95, 0xd2, 0x42, 0x47,
+	0x60, 0xf7, 0x36, 0x64, 0x3d, 0xe2, 0x5a, 0xa6, 0xce, 0xd0, 0x66, 0x7e, 0x24, 0x6b, 0x17, 0xe6,
+	0x63, 0x10, 0x95, 0x44, 0xdd, 0x31, 0x98, 0xf1, 0x13, 0x7d, 0x7f, 0x89, 0xfa, 0xfe, 0xf5, 0x51,
+	0x49, 0x7a, 0x72, 0x54, 0x2a, 0x4c, 0xdb, 0x7b, 0xd5, 0xb4, 0x2d, 0xd3, 0x26, 0x63, 0x68, 0xd1,
+	0x16, 0xc8, 0x3b, 0x9e, 0x36, 0x18, 0x12, 0x3b, 0x28, 0x24, 0x98, 0xcd, 0x95, 0x89, 0xcd, 0xc8,
+	0x4e, 0x2b, 0x9b, 0x42, 0xea, 0xb4, 0x78, 0x8d, 0x4d, 0xa1, 0x1f, 0x42, 0x72, 0xc7, 0xd2, 0x06,
+	0x7e, 0x21, 0xb5, 0x2a, 0xad, 0xe5, 0x6b, 0x37, 0x4e, 0x02, 0x46, 0x89, 0x7c, 0x42, 0xdd, 0xb4,
+	0xb4, 0x01, 0xe6, 0x7a, 0xa8, 0x05, 0x4b, 0x43, 0xed, 0x40, 0xd5, 0x5c, 0x97, 0xd8, 0x86, 0xea,
+	0x69, 0x01, 0x29, 0xa4, 0x57, 0xa5, 0xb5, 0x78, 0xed, 0xa5, 0x27, 0x47, 0xa5, 0x55, 0x6e, 0x6a,
+	0x4a, 0x20, 0xea, 0x49, 0x7e, 0xa8, 0x1d, 0x54, 0x19, 0x0b, 0x6b, 0x01, 0x29, 0x7e, 0x25, 0x83,
+	0x1c, 0x6e, 0x00, 0xbd, 0x06, 0x29, 0x8b, 0xd8, 0x83, 0x60, 0x97, 0x45, 0x2d, 0x7e, 0x12, 0xf0,
+	0x42, 0x08, 0x39, 0xb0, 0xac, 0x3b, 0x43, 0xd7, 0x23, 0xbe, 0x6f, 0x3a, 0xb6, 0xaa, 0x3b, 0x06,
+	0xd1, 0x59, 0xc8, 0x16, 0xd7, 0x8b, 0x13, 0xa8, 0x36, 0x26, 0x22, 0x1b, 0x54, 0xa2, 0x76, 0xfd,
+	0xc9, 0x51, 0xa9, 0xcc, 0xad, 0xce, 0xa8, 0x47, 0x3f, 0xa3, 0xe8, 0x53, 0x9a, 0xe8, 0x3d, 0x48,
+	0xf9, 0x81, 0xe3, 0x11, 0x1a, 0xe4, 0xf8, 0x5a, 0xa6, 0x76, 0x7d, 0xae, 0x7f, 0x4f, 0x8f, 0x4a,
+	0xf9, 0x70, 0x4b, 0x3d, 0x2a, 0x8e, 0x85, 0x16, 0xf2, 0x41, 0xf1, 0xc8, 0x8e, 0x47, 0xfc, 0x5d,
+	0xd5, 0xb4, 0x03, 0xe2, 0xed, 0x6b, 0x96, 0x08, 0xed, 0xe5, 0x0a, 0x3f, 0xf1, 0x95, 0xf0, 0xc4,
+	0x57, 0xea, 0xe2, 0xc4, 0xd7, 0x5e, 0x13, 0x51, 0x7d, 0x91, 0x7f, 0x68, 0xda, 0x40, 0xe4, 0xc3,
+	0x5f, 0xfc, 0xab, 0x24, 0xe1, 0x25, 0x21, 0xd0, 0x14, 0x7c, 0xf4, 0x21, 0x64, 0x3c, 0x12, 0x10,
+	0x9b, 0x25, 0x74, 0xf2, 0xac, 0xaf, 0x5d, 0x3d, 0x31, 0x87, 0x98, 0xf5, 0x89, 0x29, 0x34, 0x84,
+	0xc5, 0x1d, 0x6b, 0x14, 0xdd, 0x4a, 0xea, 0x2c, 0xe3, 0xaf, 0x08, 0xe3, 0x25, 0x6e, 0xfc, 0xb8,
+	0xfa, 0xf4, 0xa7, 0xf2, 0x8c, 0x3d, 0xde, 0xc6, 0x8f, 0xe0, 0x82, 0xab, 0x05, 0xbb, 0xaa, 0xeb,
+	0xf8, 0xc1, 0x8e, 0x79, 0xa0, 0x52, 0x51, 0x2b, 0x4c, 0xbe, 0x4c, 0xed, 0xe6, 0x93, 0xa3, 0xd2,
+	0x75, 0x6e, 0x76, 0xae, 0x58, 0x34, 0xb0, 0xcf, 0x51, 0x89, 0x2e, 0x17, 0xe8, 0x0b, 0x3e, 0xea,
+	0x1d, 0x4f, 0x26, 0x8b, 0xec, 0x13, 0xab, 0x20, 0xb3, 0xf3, 0x7f, 0x42, 0xc2, 0x30, 0x91, 0x93,
+	0x12, 0xa6, 0x45, 0x99, 0x68, 0x1f, 0x96, 0x35, 0x43, 0x73, 0x03, 0x73, 0x9f, 0x4c, 0x60, 0xca,
+	0x9c, 0x05, 0x53, 0x45, 0xc0, 0x24, 0xbe, 0x39, 0x63, 0x61, 0x1a, 0x29, 0x25, 0x94, 0x18, 0x83,
+	0xf5, 0x1e, 0xc0, 0xd0, 0xb4, 0x55, 0x7e, 0x4e, 0x0a, 0xc0, 0x0e, 0x53, 0xe9, 0xc9, 0x51, 0xe9,
+	0x8a, 0x38, 0x9e, 0x63, 0x5e, 0xd4, 0xfd, 0xcc, 0xd0, 0xb4, 0x5b, 0x8c, 0xca, 0xf4, 0xb5, 0x83,
+	0x50, 0x3f, 0x3b, 0xa3, 0xaf, 0x1d, 0xcc, 0xd5, 0xd7, 0x0e, 0x84, 0xbe, 0xa8, 0x11, 0xbe, 0xeb,
+	0x38, 0x96, 0xba, 0x7d, 0x18, 0x10, 0xbf, 0x90, 0x9b, 0x57, 0x23, 0x22, 0x02, 0xd3, 0x35, 0xa2,
+	0x47, 0x59, 0x35, 0xca, 0x11, 0x4d, 0xa6, 0x0a, 0x09, 0x5a, 0x86, 0xd0, 0x32, 0xe4, 0xdb, 0x9d,
+	0xbe, 0xda, 0xeb, 0x36, 0x36, 0x9a, 0x9b, 0xcd, 0x46, 0x5d, 0x59, 0x40, 0x39, 0x90, 0x3b, 0x2a,
+	0xae, 0x77, 0xda, 0xad, 0x87, 0x8a, 0xc4, 0x57, 0x0f, 0x30, 0x5b, 0xc5, 0x10, 0x40, 0x8a, 0xf2,
+	0x1e, 0x60, 0x25, 0x21, 0x0c, 0xfd, 0x46, 0x82, 0x6c, 0xd7, 0x73, 0x74, 0xe2, 0xfb, 0xac, 0x53,
+	0x54, 0x20, 0x66, 0x1a, 0xa2, 0x4d, 0x15, 0x26, 0x75, 0x23, 0x22, 0x52, 0x69, 0xd6, 0x45, 0xe3,
+	0x89, 0x99, 0x06, 0x5a, 0x03, 0x99, 0xd8, 0x86, 0xeb, 0x98, 0x76, 0xc0, 0x5b, 0x6c, 0x2d, 0xf7,
+	0xf4, 0xa8, 0x24, 0x37, 0x04, 0x0d, 0x8f, 0xb9, 0xc5, 0xb7, 0x20, 0xd6, 0xac, 0xd3, 0x1e, 0xfd,
+	0xa9, 0x63, 0x8f, 0x7b, 0x34, 0xfd, 0x8d, 0x2e, 0x42, 0xca, 0x1f, 0xed, 0xec, 0x98, 0x07, 0xa2,
+	0x49, 0x8b, 0x15, 0xf7, 0xf0, 0x76, 0xe2, 0x67, 0xd4, 0xcf, 0x9f, 0x4a, 0x00, 0x35, 0x36, 0x47,
+	0x30, 0x37, 0xfb, 0x90, 0x73, 0xb9, 0x4b, 0xaa, 0xef, 0x12, 0x5d, 0x38, 0x7c, 0x61, 0xae, 0xc3,
+	0xb5, 0x62, 0xa4, 0xd5, 0x2c, 0x8a, 0xa3, 0x1c, 0x36, 0x98, 0xac, 0x1b, 0xd9, 0xfc, 0x35, 0xc8,
+	0xff, 0x98, 0x17, 0x7a, 0xd5, 0x32, 0x87, 0x26, 0xdf, 0x51, 0x1e, 0xe7, 0x04, 0xb1, 0x45, 0x69,
+	0xe5, 0xbf, 0xc7, 0x22, 0x45, 0xfa, 0x65, 0x48, 0x0b, 0xa6, 0xe8, 0xad, 0xd9, 0x68, 0x1b, 0x0d,
+	0x79, 0x68, 0x15, 0x92, 0xdb, 0x64, 0x60, 0xf2, 0x1e, 0x1a, 0xaf, 0xc1, 0xd3, 0xa3, 0x52, 0xaa,
+	0xb3, 0xb3, 0xe3, 0x93, 0x00, 0x73, 0x06, 0x7a, 0x01, 0xe2, 0xc4, 0x36, 0x0a, 0xf1, 0x19, 0x3e,
+	0x25, 0xa3, 0x1b, 0x10, 0xf7, 0x47, 0x43, 0x51, 0x1e, 0x97, 0x27, 0xbb, 0xec, 0xdd, 0xad, 0xbe,
+	0xd1, 0x1b, 0x0d, 0x45, 0x3c, 0xa8, 0x0c, 0xba, 0x33, 0xaf, 0x0f, 0x24, 0xcf, 0xea, 0x03, 0x73,
+	0xea, 0xfb, 0x5b, 0x90, 0xdf, 0xd6, 0xf4, 0x3d, 0xd3, 0x1e, 0xa8, 0xac, 0x62, 0xb3, 0x8a, 0x96,
+	0xa9, 0x2d, 0xcf, 0x56, 0xf4, 0x9c, 0x90, 0x63, 0x2b, 0x74, 0x19, 0xe4, 0xa1, 0x63, 0xa8, 0x81,
+	0x39, 0x14, 0xbd, 0x10, 0xa7, 0x87, 0x8e, 0xd1, 0x37, 0x87, 0x04, 0xbd, 0x08, 0xb9, 0x68, 0x3d,
+	0x62, 0x15, 0x25, 0x83, 0xb3, 0x91, 0x0a, 0x54, 0xfe, 0x00, 0xd2, 0x62, 0x53, 0x74, 0x74, 0x73,
+	0x35, 0x2f, 0x78, 0x83, 0x21, 0x9b, 0xc2, 0x7c, 0x11, 0x52, 0xd7, 0x0b, 0xb1, 0x09, 0x75, 0x3d,
+	0xa4, 0xbe, 0xc9, 0x00, 0x4c, 0x73, 0xea, 0x9b, 0xe5, 0xdf, 0xc5, 0x20, 0x8b, 0x89, 0x66, 0x60,
+	0xf2, 0x93, 0x11, 0xf1, 0x03, 0xb4, 0x06, 0xa9, 0x5d, 0xa2, 0x19, 0xc4, 0x13, 0xf9, 0xa2, 0x4c,
+	0x00, 0xb9, 0xcb, 0xe8, 0x58, 0xf0, 0xa3, 0x71, 0x8d, 0x9d, 0x12, 0xd7, 0x32, 0xa4, 0x1c, 0x16,
+	0xa6, 0x39, 0x81, 0x13, 0x1c, 0xea, 0xda, 0xb6, 0xe5, 0xe8, 0x7b, 0x2c, 0x7a, 0x32, 0xe6, 0x0b,
+	0xb4, 0x0a, 0x39, 0xc3, 0x51, 0x6d, 0x27, 0x50, 0x5d, 0xcf, 0x39, 0x38, 0x64, 0x11, 0x92, 0x31,
+	0x18, 0x4e, 0xdb, 0x09, 0xba, 0x94, 0x42, 0x93, 0x71, 0x48, 0x02, 0xcd, 0xd0, 0x02, 0x4d, 0x75,
+	0x6c, 0xeb, 0x90, 0xe1, 0x2f, 0xe3, 0x5c, 0x48, 0xec, 0xd8, 0xd6, 0x21, 0xba, 0x01, 0x40, 0xe7,
+	0x0a, 0xe1, 0x44, 0x7a, 0xc6, 0x89, 0x0c, 0xb1, 0x0d, 0xfe, 0x13, 0xbd, 0x04, 0x8b, 0x2c, 0xd5,
+	0xd4, 0x71, 0x74, 0x64, 0x16, 0x9d, 0x1c, 0xa3, 0xde, 0xe7, 0x21, 0x2a, 0xff, 0x3a, 0x06, 0x39,
+	0x0e, 0x99, 0xef, 0x3a, 0xb6, 0x4f, 0x28, 0x66, 0x7e, 0xa0, 0x05, 0x23, 0x9f, 0x61, 0xb6, 0x18,
+	0xc5, 0xac, 0xc7, 0xe8, 0x58, 0xf0, 0x23, 0xe8, 0xc6, 0xce, 0x40, 0xf7, 0x59, 0x60, 0xbb, 0x01,
+	0xf0, 0x89, 0x67, 0x06, 0x44, 0xa5, 0x3a, 0x85, 0xc4, 0x8c, 0x5c, 0x86, 0x71, 0xa9, 0x61, 0x54,
+	0x89, 0x0c, 0x87, 0xc9, 0xe9, 0x81, 0x33, 0x4c, 0xd5, 0xc8, 0xd4, 0xf7, 0x22, 0xe4, 0xc2, 0xdf,
+	0xea, 0xc8, 0xe3, 0xad, 0x3a, 0x83, 0xb3, 0x21, 0x6d, 0xcb, 0xb3, 0x50, 0x01, 0xd2, 0xba, 0x63,
+	0xd3, 0xee, 0xce, 0x40, 0xcd, 0xe1, 0x70, 0x59, 0xfe, 0x73, 0x1c, 0xf2, 0x62, 0x64, 0x3b, 0xaf,
+	0xac, 0x9a, 0xce, 0x8d, 0xf8, 0x4c, 0x6e, 0x4c, 0x00, 0x4c, 0x9e, 0x08, 0xe0, 0xfb, 0xb0, 0xa4,
+	0xef, 0x12, 0x7d, 0x4f, 0xf5, 0xc8, 0xc0, 0xf4, 0x03, 0xe2, 0xf9, 0x62, 0x26, 0xb9, 0x34, 0x33,
+	0x8d, 0xf3, 0x7b, 0x0a, 0x5e, 0x64, 0xf2, 0x38, 0x14, 0x47, 0x3f, 0x80, 0xa5, 0x91, 0x4d, 0x8b,
+	0xc8, 0xc4, 0x42, 0xfa, 0xa4, 0x79, 0x1e, 0x2f, 0x32, 0xd1, 0x89, 0x72, 0x15, 0x90, 0x3f, 0xda,
+	0x0e, 0x3c, 0x4d, 0x0f, 0x22, 0xfa, 0xf2, 0x89, 0xfa, 0xcb, 0xa1, 0xf4, 0xc4, 0x44, 0x24, 0x08,
+	0x89, 0x63, 0x41, 0xa0, 0x67, 0x8a, 0x0d, 0x44, 0x6c, 0x7c, 0x90, 0x31, 0x5f, 0xd0, 0xb8, 0xf2,
+	0x1d, 0x0b, 0x6c, 0x80, 0x31, 0xb3, 0x8c, 0xc6, 0xc1, 0x11, 0x4d, 0xef, 0x57, 0x31, 0x58, 0x0c,
+	0x63, 0xf8, 0xad, 0xd3, 0xbc, 0x72, 0x56, 0x9a, 0x8b, 0x6a, 0x1c, 0x06, 0xfd, 0x26, 0xa4, 0x74,
+	0x67, 0x48, 0xbb, 0x49, 0xfc, 0xc4, 0xdc, 0x14, 0x12, 0xe8, 0x75, 0x3a, 0x9e, 0x86, 0x58, 0x25,
+	0x4e, 0xc4, 0x6a, 0x22, 0x44, 0xf7, 0x1c, 0x38, 0x81, 0x66, 0xa9, 0xfa, 0xee, 0xc8, 0xde, 0xf3,
+	0x79, 0x3e, 0xe0, 0x2c, 0xa3, 0x6d, 0x30, 0x12, 0x7a, 0x19, 0x16, 0x0d, 0x62, 0x69, 0x87, 0xc4,
+	0x08, 0x85, 0x52, 0x4c, 0x28, 0x2f, 0xa8, 0x5c, 0xac, 0xfc, 0x87, 0x18, 0x28, 0x58, 0x5c, 0xe2,
+	0xc8, 0xb7, 0xcf, 0xed, 0x0a, 0xd0, 0x7b, 0xbc, 0xeb, 0xf8, 0x9a, 0x75, 0xca, 0x46, 0xc7, 0x32,
+	0xc7, 0xb7, 0x9a, 0x7e, 0x96, 0xad, 0xae, 0x42, 0x56, 0xd3, 0xf7, 0x6c, 0xe7, 0x13, 0x8b, 0x18,
+	0x03, 0x22, 0xca, 0x61, 0x94, 0x84, 0x6e, 0x03, 0x32, 0x88, 0xeb, 0x11, 0xba, 0x03, 0x43, 0x3d,
+	0xe5, 0xa8, 0x2d, 0x4f, 0xc4, 0x04, 0xe9, 0x94, 0x64, 0xbb, 0x06, 0x79, 0xf1, 0x53, 0x35, 0x88,
+	0x15, 0x68, 0x02, 0xe3, 0x9c, 0x20, 0xd6, 0x29, 0xad, 0xfc, 0x57, 0x09, 0x96, 0x23, 0xe8, 0x9d,
+	0x63, 0xf1, 0x8c, 0x56, 0xbb, 0xf8, 0x33, 0x54, 0xbb, 0x6f, 0x9d, 0x53, 0xe5, 0x3e, 0x64, 0x5b,
+	0xa6, 0x1f, 0x84, 0x39, 0xf0, 0x7d, 0x90, 0x7d, 0x51, 0x22, 0x0a, 0xd2, 0xa9, 0x15, 0x44, 0x64,
+	0xfe, 0x58, 0xfc, 0x5e, 0x42, 0x8e, 0x29, 0xf1, 0x7b, 0x09, 0x39, 0xae, 0x24, 0xca, 0x7f, 0x8c,
+	0x41, 0x8e, 0x9b, 0x3d, 0xf7, 0x23, 0xf7, 0x3e, 0xc8, 0x22, 0xf8, 0xfc, 0x72, 0x7a, 0xec, 0xb5,
+	0x20, 0xea, 0x43, 0xf8, 0x74, 0x10, 0x3a, 0x1e, 0x6a, 0x15, 0x3f, 0x97, 0x20, 0x4c, 0x16, 0x74,
+	0x0b, 0x12, 0xf3, 0x67, 0xcc, 0xc8, 0xa3, 0x80, 0x30, 0xc0, 0x04, 0xe9, 0x99, 0xa4, 0x3d, 0xd6,
+	0x23, 0xfb, 0xa6, 0x1f, 0x3e, 0x9c, 0xc4, 0x71, 0x76, 0xe8, 0x18, 0x58, 0x90, 0xd0, 0x2b, 0x90,
+	0xf4, 0x9c, 0x51, 0x40, 0x44, 0x04, 0x23, 0xaf, 0x4d, 0x98, 0x92, 0x85, 0x39, 0x2e, 0x73, 0x2f,
+	0x21, 0x27, 0x94, 0x64, 0xf9, 0x1f, 0x12, 0xe4, 0xaa, 0xae, 0x6b, 0x1d, 0x86, 0x71, 0x79, 0x17,
+	0xd2, 0xfa, 0xae, 0x66, 0x0f, 0x48, 0xf8, 0x66, 0x75, 0x75, 0x62, 0x25, 0x2a, 0x58, 0xd9, 0x60,
+	0x52, 0xe1, 0x6b, 0x91, 0xd0, 0x29, 0xfe, 0x5c, 0x82, 0x14, 0xe7, 0xa0, 0x0a, 0x3c, 0x47, 0x0e,
+	0x5c, 0xa2, 0x07, 0xea, 0x31, 0xbf, 0xd9, 0xbb, 0x03, 0x5e, 0xe6, 0xac, 0xfb, 0x11, 0xef, 0x5f,
+	0x83, 0xd4, 0xc8, 0xf5, 0x89, 0x17, 0x14, 0x62, 0xa7, 0x60, 0x82, 0x85, 0x10, 0xba, 0x06, 0x29,
+	0x83, 0x58, 0x44, 0xec, 0x76, 0xea, 0x28, 0x0a, 0x56, 0xd9, 0x84, 0xbc, 0x70, 0xfa, 0xbc, 0xd3,
+	0xa3, 0xfc, 0xcf, 0x18, 0x28, 0xe1, 0x41, 0xf1, 0xcf, 0xad, 0x8b, 0xcf, 0xce, 0x5b, 0xf1, 0xd9,
+	0x79, 0x8b, 0xf6, 0x7a, 0x3a, 0xc0, 0x8d, 0x65, 0xd8, 0xa0, 0x83, 0xe9, 0x50, 0x17, 0x4a, 0x5c,
+	0x87, 0x25, 0x9b, 0x1c, 0x04, 0xaa, 0xab, 0x0d, 0x88, 0x1a, 0x38, 0x7b, 0xc4, 0x16, 0x05, 0x28,
+	0x4f, 0xc9, 0x5d, 0x6d, 0x40, 0xfa, 0x94, 0x88, 0xae, 0x02, 0x30, 0x11, 0x7e, 0x73, 0xa1, 0xd5,
+	0x31, 0x89, 0x33, 0x94, 0xc2, 0xae, 0x2d, 0xe8, 0x0e, 0xe4, 0x7c, 0x73, 0x60, 0x6b, 0xc1, 0xc8,
+	0x23, 0xfd, 0x7e, 0xab, 0x90, 0x3e, 0xeb, 0xe2, 0x2d, 0x3f, 0x3a, 0x2a, 0x49, 0xec, 0x4a, 0x7d,
+	0x4c, 0x71, 0x66, 0x3a, 0x91, 0xa7, 0xa7, 0x93, 0xf2, 0xef, 0x63, 0xb0, 0x1c, 0xc1, 0xf7, 0xdc,
+	0x8f, 0x7b, 0x13, 0x32, 0x61, 0xb5, 0x0b, 0xcf, 0xfb, 0xcb, 0xb3, 0x25, 0x71, 0xec, 0x49, 0x45,
+	0x0d, 0x49, 0xc2, 0xce, 0x44, 0x7b, 0x1e, 0xd8, 0x89, 0x39, 0x60, 0x17, 0x3f, 0x82, 0xcc, 0xd8,
+	0x0a, 0x7a, 0xf5, 0x58, 0x81, 0x98, 0x53, 0x8d, 0x8f, 0x55, 0x87, 0xab, 0x00, 0x14, 0x4f, 0x62,
+	0xb0, 0xd9, 0x93, 0xdf, 0x78, 0x33, 0x9c, 0xb2, 0xe5, 0x59, 0xe5, 0x5f, 0x48, 0x90, 0x64, 0x35,
+	0x00, 0xbd, 0x03, 0xe9, 0x21, 0x19, 0x6e, 0x13, 0x2f, 0x3c, 0xdf, 0x67, 0xdd, 0xc7, 0x43, 0x71,
+	0xda, 0xcb, 0x5c, 0xcf, 0x1c, 0x6a, 0xde, 0x21, 0x7f, 0xb4, 0xc5, 0xe1, 0x12, 0xdd, 0x84, 0x4c,
+	0x78, 0x21, 0x0f, 0xdf, 0xed, 0x8e, 0xdf, 0xd7, 0x27, 0x6c, 0x31, 0x2b, 0xfd, 0x36, 0x06, 0x29,
+	0x8e, 0x3a, 0x7a, 0x17, 0x20, 0xbc, 0x74, 0x3f, 0xf3, 0x1b, 0x41, 0x46, 0x68, 0x34, 0x8d, 0x49,
+	0xcd, 0x8b, 0x9d, 0x5d, 0xf3, 0x68, 0xd1, 0x25, 0x81, 0x6e, 0x14, 0xe2, 0xd3, 0x05, 0x86, 0xfb,
+	0x52, 0x69, 0x04, 0xba, 0x11, 0xc2, 0x4a, 0x05, 0x8b, 0x9f, 0x49, 0x90, 0xa0, 0x44, 0x8a, 0xaf,
+	0x6e, 0x8d, 0x68, 0x27, 0x0b, 0xbd, 0x4c, 0xe0, 0x8c, 0xa0, 0x34, 0x0d, 0x74, 0x05, 0x32, 0x1c,
+	0x26, 0xca, 0x8d, 0x31, 0xae, 0xcc, 0x09, 0x4d, 0x03, 0x15, 0x41, 0x1e, 0x57, 0x3f, 0x7e, 0x5a,
+	0xc7, 0x6b, 0xaa, 0xe8, 0x69, 0x3b, 0x81, 0x1a, 0x10, 0x8f, 0xdf, 0xc4, 0x13, 0x58, 0xa6, 0x84,
+	0x3e, 0xf1, 0x86, 0xe1, 0x53, 0x05, 0x43, 0xcc, 0x83, 0xdc, 0x03, 0x2d, 0xd0, 0x77, 0xff, 0xff,
+	0xfe, 0x89, 0xbe, 0x03, 0x4b, 0x1e, 0xf1, 0x47, 0x43, 0x32, 0xdd, 0x4c, 0x16, 0x39, 0x39, 0xac,
+	0xc8, 0xe5, 0xcf, 0x63, 0x90, 0x17, 0x1f, 0x3d, 0xf7, 0xe3, 0x76, 0x1a, 0x48, 0x45, 0x90, 0x7d,
+	0x5b, 0x73, 0xfd, 0x5d, 0x27, 0x10, 0xf7, 0xdd, 0xf1, 0xfa, 0x58, 0x57, 0x4e, 0xfe, 0x2f, 0x5d,
+	0x99, 0x56, 0x5e, 0xde, 0x2d, 0x8c, 0x42, 0x6a, 0x35, 0x3e, 0x53, 0x79, 0x05, 0xef, 0xe6, 0x37,
+	0x31, 0x48, 0xf1, 0x3d, 0xa2, 0x14, 0xc4, 0x3a, 0x1f, 0x28, 0x0b, 0xe8, 0x02, 0x2c, 0xdf, 0xeb,
+	0x6c, 0xe1, 0x76, 0xb5, 0xa5, 0xd2, 0xf7, 0xb2, 0xcd, 0xce, 0x56, 0xbb, 0xae, 0x48, 0xe8, 0x2a,
+	0x5c, 0x6e, 0x77, 0xd4, 0x90, 0xd3, 0xc5, 0xcd, 0xfb, 0x55, 0xfc, 0x50, 0xad, 0xe1, 0xce, 0x07,
+	0x0d, 0xac, 0xc4, 0xd0, 0x0a, 0x14, 0xa9, 0xf4, 0x09, 0xfc, 0x38, 0xba, 0x08, 0x28, 0xca, 0x17,
+	0xf4, 0x24, 0x5a, 0x85, 0x17, 0x9a, 0xed, 0xde, 0xd6, 0xe6, 0x66, 0x73, 0xa3, 0xd9, 0x68, 0x4f,
+	0x0b, 0xf4, 0x94, 0x04, 0x7a, 0x01, 0x0a, 0x9d, 0xcd, 0xcd, 0x5e, 0xa3, 0xcf, 0xdc, 0x79, 0xd8,
+	0xe8, 0xab, 0xd5, 0x0f, 0xab, 0xcd, 0x56, 0xb5, 0xd6, 0x6a, 0x28, 0x29, 0xb4, 0x04, 0x59, 0xfa,
+	0x64, 0x77, 0x47, 0xc5, 0x9d, 0xad, 0x7e, 0x43, 0x49, 0x53, 0xf7, 0xbb, 0xb8, 0xd3, 0xed, 0xf4,
+	0xaa, 0x2d, 0xf5, 0x7e, 0xb3, 0x77, 0xbf, 0xda, 0xdf, 0xb8, 0xab, 0xc8, 0xe8, 0x0a, 0x5c, 0x6a,
+	0xf4, 0x37, 0xea, 0x6a, 0x1f, 0x57, 0xdb, 0xbd, 0xea, 0x46, 0xbf, 0xd9, 0x69, 0xab, 0x9b, 0xd5,
+	0x66, 0xab, 0x51, 0x57, 0x32, 0xd4, 0x08, 0xb5, 0x5d, 0x6d, 0xb5, 0x3a, 0x0f, 0x1a, 0x75, 0x05,
+	0xd0, 0x25, 0x78, 0x8e, 0x5b, 0xad, 0x76, 0xbb, 0x8d, 0x76, 0x5d, 0xe5, 0x0e, 0x28, 0x59, 0xea,
+	0x4c, 0xb3, 0x5d, 0x6f, 0x7c, 0xa4, 0xde, 0xad, 0xf6, 0xd4, 0x3b, 0xb8, 0x51, 0xed, 0x37, 0x70,
+	0xc8, 0xcd, 0xd1, 0x6f, 0xe3, 0xc6, 0x9d, 0x66, 0x8f, 0x12, 0xc7, 0xdf, 0xce, 0xdf, 0xb4, 0x41,
+	0x99, 0x7e, 0x44, 0x42, 0x59, 0x48, 0x37, 0xdb, 0x1f, 0x56, 0x5b, 0x4d, 0xfa, 0x0e, 0x29, 0x43,
+	0xa2, 0xdd, 0x69, 0x37, 0x14, 0x89, 0xfe, 0xba, 0xf3, 0x71, 0xb3, 0xab, 0xc4, 0x50, 0x1e, 0x32,
+	0x1f, 0xf7, 0xfa, 0xd5, 0x76, 0xbd, 0x8a, 0xeb, 0x4a, 0x9c, 0x3e, 0x47, 0xf6, 0xda, 0xd5, 0x6e,
+	0xf7, 0xa1, 0x92, 0xa0, 0x58, 0x53, 0x21, 0xfa, 0xdd, 0x56, 0xa7, 0x5a, 0x57, 0xeb, 0x8d, 0x8d,
+	0xce, 0xfd, 0x2e, 0x6e, 0xf4, 0x7a, 0xcd, 0x4e, 0x5b, 0x49, 0xae, 0xff, 0x25, 0x3e, 0x99, 0xc8,
+	0xbe, 0x07, 0x09, 0x9a, 0x2f, 0xe8, 0xc2, 0x74, 0xfe, 0xb0, 0x03, 0x57, 0xbc, 0x38, 0x3f, 0xad,
+	0xd0, 0x3b, 0x90, 0x64, 0x23, 0x06, 0xba, 0x38, 0x7f, 0x50, 0x2a, 0x5e, 0x9a, 0xa1, 0x0b, 0xcd,
+	0xb7, 0x21, 0x41, 0x1f, 0x45, 0xa2, 0x1f, 0x8c, 0xbc, 0x2b, 0x15, 0x2f, 0x4e, 0x93, 0xb9, 0xda,
+	0xeb, 0x12, 0x7a, 0x17, 0x52, 0xfc, 0xa2, 0x89, 0x8e, 0xdb, 0x9e, 0x3c, 0x1f, 0x14, 0x0b, 0xb3,
+	0x0c, 0xae, 0xbe, 0x26, 0xa1, 0xbb, 0x90, 0x19, 0x5f, 0x2a, 0x50, 0x31, 0xfa, 0x95, 0xe3, 0xf7,
+	0xb4, 0xe2, 0x95, 0xb9, 0xbc, 0xd0, 0xce, 0xeb, 0xd4, 0x52, 0x9e, 0x62, 0x31, 0x6e, 0x86, 0x51,
+	0x6b, 0xd3, 0xb3, 0x50, 0xf1, 0xca, 0x5c, 0x9e, 0xc0, 0xe2, 0x36, 0x24, 0x59, 0xa5, 0x89, 0xa2,
+	0x18, 0xad, 0x77, 0xc5, 0x4b, 0x33, 0xf4, 0x10, 0x8e, 0x5a, 0xe3, 0xd1, 0xbf, 0x57, 0x16, 0x1e,
+	0x7d, 0xb3, 0x22, 0x7d, 0xfd, 0xcd, 0x8a, 0xf4, 0xcb, 0xc7, 0x2b, 0x0b, 0x5f, 0x3e, 0x5e, 0x91,
+	0xfe, 0xf4, 0x78, 0x45, 0xfa, 0xfa, 0xf1, 0xca, 0xc2, 0xdf, 0x1e, 0xaf, 0x2c, 0x7c, 0x7c, 0x6d,
+	0xe0, 0x54, 0x06, 0xda, 0xa7, 0x24, 0x08, 0x48, 0xc5, 0x20, 0xfb, 0xb7, 0x74, 0xc7, 0x23, 0xb7,
+	0xa6, 0xfe, 0x40, 0xde, 0x4e, 0xb1, 0x5f, 0x6f, 0xfe, 0x77, 0x00, 0x44, 0x36, 0x02, 0x2c, 0x5a,
+	0x1e, 0x00, 0x00,
 }
 
 func (this *Label) Equal(that interface{}) bool {
@@ -2097,6 +2262,21 @@ func (this *JournalSpec_Fragment) Equal(that interface{}) bool {
 	if this.PathPostfixTemplate != that1.PathPostfixTemplate {
 		return false
 	}
+	if this.CompressionLevel != that1.CompressionLevel {
+		return false
+	}
+	if this.AdaptiveInterval != that1.AdaptiveInterval {
+		return false
+	}
+	if this.MinLength != that1.MinLength {
+		return false
+	}
+	if this.MaxLength != that1.MaxLength {
+		return false
+	}
+	if this.MaxSpoolBytes != that1.MaxSpoolBytes {
+		return false
+	}
 	return true
 }
 func (this *ProcessSpec_ID) Equal(that interface{}) bool {
@@ -2169,6 +2349,12 @@ func (this *AppendRequest) Equal(that interface{}) bool {
 	if !bytes.Equal(this.Content, that1.Content) {
 		return false
 	}
+	if this.Flush != that1.Flush {
+		return false
+	}
+	if this.CheckOffset != that1.CheckOffset {
+		return false
+	}
 	return true
 }
 func (this *Route) Equal(that interface{}) bool {
@@ -2301,6 +2487,8 @@ type JournalClient interface {
 	Replicate(ctx context.Context, opts ...grpc.CallOption) (Journal_ReplicateClient, error)
 	// List Fragments of a Journal.
 	ListFragments(ctx context.Context, in *FragmentsRequest, opts ...grpc.CallOption) (*FragmentsResponse, error)
+	// Watch journals for changes of their specifications and routes.
+	Watch(ctx context.Context, in *WatchRequest, opts ...grpc.CallOption) (Journal_WatchClient, error)
 }
 
 type journalClient struct {
@@ -2435,6 +2623,38 @@ func (c *journalClient) ListFragments(ctx context.Context, in *FragmentsRequest,
 	return out, nil
 }
 
+func (c *journalClient) Watch(ctx context.Context, in *WatchRequest, opts ...grpc.CallOption) (Journal_WatchClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Journal_serviceDesc.Streams[3], "/protocol.Journal/Watch", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &journalWatchClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Journal_WatchClient interface {
+	Recv() (*WatchResponse, error)
+	grpc.ClientStream
+}
+
+type journalWatchClient struct {
+	grpc.ClientStream
+}
+
+func (x *journalWatchClient) Recv() (*WatchResponse, error) {
+	m := new(WatchResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // JournalServer is the server API for Journal service.
 type JournalServer interface {
 	// List Journals, their JournalSpecs and current Routes.
@@ -2451,6 +2671,8 @@ type JournalServer interface {
 	Replicate(Journal_ReplicateServer) error
 	// List Fragments of a Journal.
 	ListFragments(context.Context, *FragmentsRequest) (*FragmentsResponse, error)
+	// Watch journals for changes of their specifications and routes.
+	Watch(*WatchRequest, Journal_WatchServer) error
 }
 
 // UnimplementedJournalServer can be embedded to have forward compatible implementations.
@@ -2475,6 +2697,9 @@ func (*UnimplementedJournalServer) Replicate(srv Journal_ReplicateServer) error
 func (*UnimplementedJournalServer) ListFragments(ctx context.Context, req *FragmentsRequest) (*FragmentsResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ListFragments not implemented")
 }
+func (*UnimplementedJournalServer) Watch(req *WatchRequest, srv Journal_WatchServer) error {
+	return status.Errorf(codes.Unimplemented, "method Watch not implemented")
+}
 
 func RegisterJournalServer(s *grpc.Server, srv JournalServer) {
 	s.RegisterService(&_Journal_serviceDesc, srv)
@@ -2607,6 +2832,27 @@ func _Journal_ListFragments_Handler(srv interface{}, ctx context.Context, dec fu
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Journal_Watch_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(JournalServer).Watch(m, &journalWatchServer{stream})
+}
+
+type Journal_WatchServer interface {
+	Send(*WatchResponse) error
+	grpc.ServerStream
+}
+
+type journalWatchServer struct {
+	grpc.ServerStream
+}
+
+func (x *journalWatchServer) Send(m *WatchResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 var _Journal_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "protocol.Journal",
 	HandlerType: (*JournalServer)(nil),
@@ -2641,6 +2887,11 @@ var _Journal_serviceDesc = grpc.ServiceDesc{
 			ServerStreams: true,
 			ClientStreams: true,
 		},
+		{
+			StreamName:    "Watch",
+			Handler:       _Journal_Watch_Handler,
+			ServerStreams: true,
+		},
 	},
 	Metadata: "broker/protocol/protocol.proto",
 }
@@ -2847,36 +3098,64 @@ func (m *JournalSpec_Fragment) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
-	if len(m.PathPostfixTemplate) > 0 {
-		i -= len(m.PathPostfixTemplate)
-		copy(dAtA[i:], m.PathPostfixTemplate)
-		i = encodeVarintProtocol(dAtA, i, uint64(len(m.PathPostfixTemplate)))
+	if m.MaxSpoolBytes != 0 {
+		i = encodeVarintProtocol(dAtA, i, uint64(m.MaxSpoolBytes))
 		i--
-		dAtA[i] = 0x3a
+		dAtA[i] = 0x60
+	}
+	if m.MaxLength != 0 {
+		i = encodeVarintProtocol(dAtA, i, uint64(m.MaxLength))
+		i--
+		dAtA[i] = 0x58
 	}
-	n5, err5 := github_com_gogo_protobuf_types.StdDurationMarshalTo(m.FlushInterval, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdDuration(m.FlushInterval):])
+	if m.MinLength != 0 {
+		i = encodeVarintProtocol(dAtA, i, uint64(m.MinLength))
+		i--
+		dAtA[i] = 0x50
+	}
+	n5, err5 := github_com_gogo_protobuf_types.StdDurationMarshalTo(m.AdaptiveInterval, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdDuration(m.AdaptiveInterval):])
 	if err5 != nil {
 		return 0, err5
 	}
 	i -= n5
 	i = encodeVarintProtocol(dAtA, i, uint64(n5))
 	i--
-	dAtA[i] = 0x32
-	n6, err6 := github_com_gogo_protobuf_types.StdDurationMarshalTo(m.Retention, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdDuration(m.Retention):])
+	dAtA[i] = 0x4a
+	if m.CompressionLevel != 0 {
+		i = encodeVarintProtocol(dAtA, i, uint64(m.CompressionLevel))
+		i--
+		dAtA[i] = 0x40
+	}
+	if len(m.PathPostfixTemplate) > 0 {
+		i -= len(m.PathPostfixTemplate)
+		copy(dAtA[i:], m.PathPostfixTemplate)
+		i = encodeVarintProtocol(dAtA, i, uint64(len(m.PathPostfixTemplate)))
+		i--
+		dAtA[i] = 0x3a
+	}
+	n6, err6 := github_com_gogo_protobuf_types.StdDurationMarshalTo(m.FlushInterval, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdDuration(m.FlushInterval):])
 	if err6 != nil {
 		return 0, err6
 	}
 	i -= n6
 	i = encodeVarintProtocol(dAtA, i, uint64(n6))
 	i--
-	dAtA[i] = 0x2a
-	n7, err7 := github_com_gogo_protobuf_types.StdDurationMarshalTo(m.RefreshInterval, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdDuration(m.RefreshInterval):])
+	dAtA[i] = 0x32
+	n7, err7 := github_com_gogo_protobuf_types.StdDurationMarshalTo(m.Retention, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdDuration(m.Retention):])
 	if err7 != nil {
 		return 0, err7
 	}
 	i -= n7
 	i = encodeVarintProtocol(dAtA, i, uint64(n7))
 	i--
+	dAtA[i] = 0x2a
+	n8, err8 := github_com_gogo_protobuf_types.StdDurationMarshalTo(m.RefreshInterval, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdDuration(m.RefreshInterval):])
+	if err8 != nil {
+		return 0, err8
+	}
+	i -= n8
+	i = encodeVarintProtocol(dAtA, i, uint64(n8))
+	i--
 	dAtA[i] = 0x22
 	if len(m.Stores) > 0 {
 		for iNdEx := len(m.Stores) - 1; iNdEx >= 0; iNdEx-- {
@@ -3313,6 +3592,26 @@ func (m *AppendRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if m.CheckOffset {
+		i--
+		if m.CheckOffset {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x50
+	}
+	if m.Flush {
+		i--
+		if m.Flush {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x48
+	}
 	if m.SubtractRegisters != nil {
 		{
 			size, err := m.SubtractRegisters.MarshalToSizedBuffer(dAtA[:i])
@@ -3903,12 +4202,12 @@ func (m *FragmentsRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 		dAtA[i] = 0x40
 	}
 	if m.SignatureTTL != nil {
-		n33, err33 := github_com_gogo_protobuf_types.StdDurationMarshalTo(*m.SignatureTTL, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdDuration(*m.SignatureTTL):])
-		if err33 != nil {
-			return 0, err33
+		n34, err34 := github_com_gogo_protobuf_types.StdDurationMarshalTo(*m.SignatureTTL, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdDuration(*m.SignatureTTL):])
+		if err34 != nil {
+			return 0, err34
 		}
-		i -= n33
-		i = encodeVarintProtocol(dAtA, i, uint64(n33))
+		i -= n34
+		i = encodeVarintProtocol(dAtA, i, uint64(n34))
 		i--
 		dAtA[i] = 0x3a
 	}
@@ -4198,6 +4497,120 @@ func (m *Header_Etcd) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	return len(dAtA) - i, nil
 }
 
+func (m *WatchRequest) Marshal() (dAtA []byte, err error) {
+	size := m.ProtoSize()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *WatchRequest) MarshalTo(dAtA []byte) (int, error) {
+	size := m.ProtoSize()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *WatchRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.ResumeRevision != 0 {
+		i = encodeVarintProtocol(dAtA, i, uint64(m.ResumeRevision))
+		i--
+		dAtA[i] = 0x10
+	}
+	{
+		size, err := m.Selector.MarshalToSizedBuffer(dAtA[:i])
+		if err != nil {
+			return 0, err
+		}
+		i -= size
+		i = encodeVarintProtocol(dAtA, i, uint64(size))
+	}
+	i--
+	dAtA[i] = 0xa
+	return len(dAtA) - i, nil
+}
+
+func (m *WatchResponse) Marshal() (dAtA []byte, err error) {
+	size := m.ProtoSize()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *WatchResponse) MarshalTo(dAtA []byte) (int, error) {
+	size := m.ProtoSize()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *WatchResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Deleted) > 0 {
+		for iNdEx := len(m.Deleted) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.Deleted[iNdEx])
+			copy(dAtA[i:], m.Deleted[iNdEx])
+			i = encodeVarintProtocol(dAtA, i, uint64(len(m.Deleted[iNdEx])))
+			i--
+			dAtA[i] = 0x32
+		}
+	}
+	if len(m.Journals) > 0 {
+		for iNdEx := len(m.Journals) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Journals[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintProtocol(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x2a
+		}
+	}
+	if m.Snapshot {
+		i--
+		if m.Snapshot {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x20
+	}
+	if m.Revision != 0 {
+		i = encodeVarintProtocol(dAtA, i, uint64(m.Revision))
+		i--
+		dAtA[i] = 0x18
+	}
+	{
+		size, err := m.Header.MarshalToSizedBuffer(dAtA[:i])
+		if err != nil {
+			return 0, err
+		}
+		i -= size
+		i = encodeVarintProtocol(dAtA, i, uint64(size))
+	}
+	i--
+	dAtA[i] = 0x12
+	if m.Status != 0 {
+		i = encodeVarintProtocol(dAtA, i, uint64(m.Status))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
 func encodeVarintProtocol(dAtA []byte, offset int, v uint64) int {
 	offset -= sovProtocol(v)
 	base := offset
@@ -4308,6 +4721,20 @@ func (m *JournalSpec_Fragment) ProtoSize() (n int) {
 	if l > 0 {
 		n += 1 + l + sovProtocol(uint64(l))
 	}
+	if m.CompressionLevel != 0 {
+		n += 1 + sovProtocol(uint64(m.CompressionLevel))
+	}
+	l = github_com_gogo_protobuf_types.SizeOfStdDuration(m.AdaptiveInterval)
+	n += 1 + l + sovProtocol(uint64(l))
+	if m.MinLength != 0 {
+		n += 1 + sovProtocol(uint64(m.MinLength))
+	}
+	if m.MaxLength != 0 {
+		n += 1 + sovProtocol(uint64(m.MaxLength))
+	}
+	if m.MaxSpoolBytes != 0 {
+		n += 1 + sovProtocol(uint64(m.MaxSpoolBytes))
+	}
 	return n
 }
 
@@ -4515,6 +4942,12 @@ func (m *AppendRequest) ProtoSize() (n int) {
 		l = m.SubtractRegisters.ProtoSize()
 		n += 1 + l + sovProtocol(uint64(l))
 	}
+	if m.Flush {
+		n += 2
+	}
+	if m.CheckOffset {
+		n += 2
+	}
 	return n
 }
 
@@ -4835,16 +5268,62 @@ func (m *Header_Etcd) ProtoSize() (n int) {
 	return n
 }
 
-func sovProtocol(x uint64) (n int) {
-	return (math_bits.Len64(x|1) + 6) / 7
-}
-func sozProtocol(x uint64) (n int) {
-	return sovProtocol(uint64((x << 1) ^ uint64((int64(x) >> 63))))
-}
-func (m *Label) Unmarshal(dAtA []byte) error {
-	l := len(dAtA)
-	iNdEx := 0
-	for iNdEx < l {
+func (m *WatchRequest) ProtoSize() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = m.Selector.ProtoSize()
+	n += 1 + l + sovProtocol(uint64(l))
+	if m.ResumeRevision != 0 {
+		n += 1 + sovProtocol(uint64(m.ResumeRevision))
+	}
+	return n
+}
+
+func (m *WatchResponse) ProtoSize() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Status != 0 {
+		n += 1 + sovProtocol(uint64(m.Status))
+	}
+	l = m.Header.ProtoSize()
+	n += 1 + l + sovProtocol(uint64(l))
+	if m.Revision != 0 {
+		n += 1 + sovProtocol(uint64(m.Revision))
+	}
+	if m.Snapshot {
+		n += 2
+	}
+	if len(m.Journals) > 0 {
+		for _, e := range m.Journals {
+			l = e.ProtoSize()
+			n += 1 + l + sovProtocol(uint64(l))
+		}
+	}
+	if len(m.Deleted) > 0 {
+		for _, s := range m.Deleted {
+			l = len(s)
+			n += 1 + l + sovProtocol(uint64(l))
+		}
+	}
+	return n
+}
+
+func sovProtocol(x uint64) (n int) {
+	return (math_bits.Len64(x|1) + 6) / 7
+}
+func sozProtocol(x uint64) (n int) {
+	return sovProtocol(uint64((x << 1) ^ uint64((int64(x) >> 63))))
+}
+func (m *Label) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
 		preIndex := iNdEx
 		var wire uint64
 		for shift := uint(0); ; shift += 7 {
@@ -5590,6 +6069,115 @@ func (m *JournalSpec_Fragment) Unmarshal(dAtA []byte) error {
 			}
 			m.PathPostfixTemplate = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
+		case 8:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field CompressionLevel", wireType)
+			}
+			m.CompressionLevel = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowProtocol
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.CompressionLevel |= int32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 9:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field AdaptiveInterval", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowProtocol
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthProtocol
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthProtocol
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if err := github_com_gogo_protobuf_types.StdDurationUnmarshal(&m.AdaptiveInterval, dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 10:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MinLength", wireType)
+			}
+			m.MinLength = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowProtocol
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.MinLength |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 11:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MaxLength", wireType)
+			}
+			m.MaxLength = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowProtocol
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.MaxLength |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 12:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MaxSpoolBytes", wireType)
+			}
+			m.MaxSpoolBytes = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowProtocol
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.MaxSpoolBytes |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
 		default:
 			iNdEx = preIndex
 			skippy, err := skipProtocol(dAtA[iNdEx:])
@@ -7035,6 +7623,46 @@ func (m *AppendRequest) Unmarshal(dAtA []byte) error {
 				return err
 			}
 			iNdEx = postIndex
+		case 9:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Flush", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowProtocol
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Flush = bool(v != 0)
+		case 10:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field CheckOffset", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowProtocol
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.CheckOffset = bool(v != 0)
 		default:
 			iNdEx = preIndex
 			skippy, err := skipProtocol(dAtA[iNdEx:])
@@ -9315,6 +9943,315 @@ func (m *Header_Etcd) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
+func (m *WatchRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowProtocol
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: WatchRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: WatchRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Selector", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowProtocol
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthProtocol
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthProtocol
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if err := m.Selector.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ResumeRevision", wireType)
+			}
+			m.ResumeRevision = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowProtocol
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.ResumeRevision |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipProtocol(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthProtocol
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *WatchResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowProtocol
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: WatchResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: WatchResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Status", wireType)
+			}
+			m.Status = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowProtocol
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Status |= Status(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Header", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowProtocol
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthProtocol
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthProtocol
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if err := m.Header.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Revision", wireType)
+			}
+			m.Revision = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowProtocol
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Revision |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Snapshot", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowProtocol
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Snapshot = bool(v != 0)
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Journals", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowProtocol
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthProtocol
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthProtocol
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Journals = append(m.Journals, ListResponse_Journal{})
+			if err := m.Journals[len(m.Journals)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 6:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Deleted", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowProtocol
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthProtocol
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthProtocol
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Deleted = append(m.Deleted, Journal(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipProtocol(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthProtocol
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
 func skipProtocol(dAtA []byte) (n int, err error) {
 	l := len(dAtA)
 	iNdEx := 0
@@ -140,6 +140,10 @@ func (m *AppendRequest) Validate() error {
 		return NewValidationError("unexpected UnionRegisters")
 	} else if m.SubtractRegisters != nil {
 		return NewValidationError("unexpected SubtractRegisters")
+	} else if m.Flush {
+		return NewValidationError("unexpected Flush")
+	} else if m.CheckOffset {
+		return NewValidationError("unexpected CheckOffset")
 	}
 	return nil
 }
@@ -262,21 +266,7 @@ func (m *ReplicateResponse) Validate() error {
 }
 
 func (m *ListRequest) Validate() error {
-	if err := m.Selector.Validate(); err != nil {
-		return ExtendContext(err, "Selector")
-	}
-	for _, v := range m.Selector.Include.ValuesOf("prefix") {
-		if !strings.HasSuffix(v, "/") {
-			return NewValidationError("Selector.Include.Labels[\"prefix\"]: expected trailing '/' (%+v)", v)
-		}
-	}
-	for _, v := range m.Selector.Exclude.ValuesOf("prefix") {
-		if !strings.HasSuffix(v, "/") {
-			return NewValidationError("Selector.Exclude.Labels[\"prefix\"]: expected trailing '/' (%+v)", v)
-		}
-	}
-
-	return nil
+	return validateJournalSelector(m.Selector)
 }
 
 func (m *ListResponse) Validate() error {
@@ -305,6 +295,55 @@ func (m *ListResponse_Journal) Validate() error {
 	return nil
 }
 
+func (m *WatchRequest) Validate() error {
+	if err := validateJournalSelector(m.Selector); err != nil {
+		return err
+	} else if m.ResumeRevision < 0 {
+		return NewValidationError("invalid ResumeRevision (%d; expected >= 0)", m.ResumeRevision)
+	}
+	return nil
+}
+
+func (m *WatchResponse) Validate() error {
+	if err := m.Status.Validate(); err != nil {
+		return ExtendContext(err, "Status")
+	} else if err = m.Header.Validate(); err != nil {
+		return ExtendContext(err, "Header")
+	} else if m.Revision <= 0 {
+		return NewValidationError("invalid Revision (%d; expected > 0)", m.Revision)
+	}
+	for i, j := range m.Journals {
+		if err := j.Validate(); err != nil {
+			return ExtendContext(err, "Journals[%d]", i)
+		}
+	}
+	for i, j := range m.Deleted {
+		if err := j.Validate(); err != nil {
+			return ExtendContext(err, "Deleted[%d]", i)
+		}
+	}
+	return nil
+}
+
+// validateJournalSelector validates a LabelSelector of journals, which may
+// include "prefix" meta-labels that must end in '/'.
+func validateJournalSelector(sel LabelSelector) error {
+	if err := sel.Validate(); err != nil {
+		return ExtendContext(err, "Selector")
+	}
+	for _, v := range sel.Include.ValuesOf("prefix") {
+		if !strings.HasSuffix(v, "/") {
+			return NewValidationError("Selector.Include.Labels[\"prefix\"]: expected trailing '/' (%+v)", v)
+		}
+	}
+	for _, v := range sel.Exclude.ValuesOf("prefix") {
+		if !strings.HasSuffix(v, "/") {
+			return NewValidationError("Selector.Exclude.Labels[\"prefix\"]: expected trailing '/' (%+v)", v)
+		}
+	}
+	return nil
+}
+
 func (m *ApplyRequest) Validate() error {
 	for i, u := range m.Changes {
 		if err := u.Validate(); err != nil {
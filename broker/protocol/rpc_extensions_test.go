@@ -126,6 +126,8 @@ func (s *RPCSuite) TestAppendRequestValidationCases(c *gc.C) {
 		CheckRegisters:    &LabelSelector{Include: badLabel},
 		UnionRegisters:    &badLabel,
 		SubtractRegisters: &badLabel,
+		Flush:             true,
+		CheckOffset:       true,
 	}
 
 	c.Check(req.Validate(), gc.ErrorMatches, `Header.Etcd: invalid ClusterId .*`)
@@ -161,6 +163,10 @@ func (s *RPCSuite) TestAppendRequestValidationCases(c *gc.C) {
 	req.UnionRegisters = nil
 	c.Check(req.Validate(), gc.ErrorMatches, `unexpected SubtractRegisters`)
 	req.SubtractRegisters = nil
+	c.Check(req.Validate(), gc.ErrorMatches, `unexpected Flush`)
+	req.Flush = false
+	c.Check(req.Validate(), gc.ErrorMatches, `unexpected CheckOffset`)
+	req.CheckOffset = false
 
 	c.Check(req.Validate(), gc.IsNil)
 
@@ -345,6 +351,60 @@ func (s *RPCSuite) TestListResponseValidationCases(c *gc.C) {
 	c.Check(resp.Validate(), gc.IsNil)
 }
 
+func (s *RPCSuite) TestWatchRequestValidationCases(c *gc.C) {
+	var req = WatchRequest{
+		Selector: LabelSelector{
+			Include: LabelSet{Labels: []Label{{Name: "prefix", Value: "no/trailing/slash"}}},
+		},
+		ResumeRevision: -1,
+	}
+	c.Check(req.Validate(), gc.ErrorMatches,
+		`Selector.Include.Labels\["prefix"\]: expected trailing '/' \(no/trailing/slash\)`)
+	req.Selector.Include.Labels[0].Value = "trailing/slash/"
+	c.Check(req.Validate(), gc.ErrorMatches, `invalid ResumeRevision \(-1; expected >= 0\)`)
+	req.ResumeRevision = 0
+
+	c.Check(req.Validate(), gc.IsNil)
+}
+
+func (s *RPCSuite) TestWatchResponseValidationCases(c *gc.C) {
+	var resp = WatchResponse{
+		Status:   9101,
+		Header:   *badHeaderFixture(),
+		Revision: 0,
+		Journals: []ListResponse_Journal{
+			{
+				ModRevision: 0,
+				Spec: JournalSpec{
+					Name:        "a/journal",
+					Replication: 1,
+					Fragment: JournalSpec_Fragment{
+						Length:           1024,
+						CompressionCodec: CompressionCodec_NONE,
+						RefreshInterval:  time.Minute,
+						Retention:        time.Hour,
+					},
+				},
+				Route: Route{Primary: -1},
+			},
+		},
+		Deleted: []Journal{"a/deleted journal"},
+	}
+
+	c.Check(resp.Validate(), gc.ErrorMatches, `Status: invalid status \(9101\)`)
+	resp.Status = Status_OK
+	c.Check(resp.Validate(), gc.ErrorMatches, `Header.Etcd: invalid ClusterId .*`)
+	resp.Header.Etcd.ClusterId = 1234
+	c.Check(resp.Validate(), gc.ErrorMatches, `invalid Revision \(0; expected > 0\)`)
+	resp.Revision = 1
+	c.Check(resp.Validate(), gc.ErrorMatches, `Journals\[0\]: invalid ModRevision \(0; expected > 0\)`)
+	resp.Journals[0].ModRevision = 1
+	c.Check(resp.Validate(), gc.ErrorMatches, `Deleted\[0\]: not a valid token \(.*\)`)
+	resp.Deleted[0] = "a/deleted/journal"
+
+	c.Check(resp.Validate(), gc.IsNil)
+}
+
 func (s *RPCSuite) TestApplyRequestValidationCases(c *gc.C) {
 	var req = ApplyRequest{
 		Changes: []ApplyRequest_Change{
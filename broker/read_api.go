@@ -53,10 +53,10 @@ func (svc *Service) Read(req *pb.ReadRequest, stream pb.Journal_ReadServer) (err
 		return stream.Send(&pb.ReadResponse{Status: pb.Status_NOT_ALLOWED, Header: &resolved.Header})
 	} else if resolved.ProcessId != resolved.localID {
 		req.Header = &resolved.Header // Attach resolved Header to |req|, which we'll forward.
-		return proxyRead(stream, req, svc.jc, svc.stopProxyReadsCh)
+		return proxyRead(stream, req, svc.jc, svc.stopProxyReadsCh, svc.ReadAuthorizer)
 	}
 
-	err = serveRead(stream, req, &resolved.Header, resolved.replica.index)
+	err = serveRead(stream, req, &resolved.Header, resolved.replica.index, svc.ReadAuthorizer)
 
 	// Blocking Read RPCs live indefinitely, until cancelled by the caller or
 	// due to journal reassignment. Interpret local or remote cancellation as
@@ -71,9 +71,11 @@ func (svc *Service) Read(req *pb.ReadRequest, stream pb.Journal_ReadServer) (err
 	return err
 }
 
-// proxyRead forwards a ReadRequest to a resolved peer broker.
-func proxyRead(stream grpc.ServerStream, req *pb.ReadRequest, jc pb.JournalClient, stopCh <-chan struct{}) error {
-	var ctx = pb.WithDispatchRoute(stream.Context(), req.Header.Route, req.Header.ProcessId)
+// proxyRead forwards a ReadRequest to a resolved peer broker, with the
+// claims of the client. If |authorize| is non-nil and denies a Fragment which
+// the peer served, the read ends with a NOT_ALLOWED status.
+func proxyRead(stream grpc.ServerStream, req *pb.ReadRequest, jc pb.JournalClient, stopCh <-chan struct{}, authorize ReadAuthorizeFunc) error {
+	var ctx = pb.WithDispatchRoute(withForwardedClaims(stream.Context()), req.Header.Route, req.Header.ProcessId)
 
 	// We use the |stream| context for this RPC, which means a cancellation from
 	// our client automatically propagates to the proxy |client| stream.
@@ -110,7 +112,20 @@ func proxyRead(stream grpc.ServerStream, req *pb.ReadRequest, jc pb.JournalClien
 				return nil
 			} else if chunk.err != nil {
 				return chunk.err
-			} else if err = stream.SendMsg(&chunk.resp); err != nil {
+			}
+			if authorize != nil && chunk.resp.Content == nil {
+				if denied, err := isReadDenied(stream.Context(), &chunk.resp, authorize); err != nil {
+					return err
+				} else if denied {
+					return stream.SendMsg(&pb.ReadResponse{
+						Status:    pb.Status_NOT_ALLOWED,
+						Header:    chunk.resp.Header,
+						Offset:    chunk.resp.Offset,
+						WriteHead: chunk.resp.WriteHead,
+					})
+				}
+			}
+			if err = stream.SendMsg(&chunk.resp); err != nil {
 				return err
 			}
 		case <-stopCh:
@@ -125,19 +140,33 @@ type proxyChunk struct {
 }
 
 // serveRead evaluates a client's Read RPC against the local replica index.
-func serveRead(stream grpc.ServerStream, req *pb.ReadRequest, hdr *pb.Header, index *fragment.Index) error {
+// If |authorize| is non-nil, unauthorized Fragments are skipped.
+func serveRead(stream grpc.ServerStream, req *pb.ReadRequest, hdr *pb.Header, index *fragment.Index, authorize ReadAuthorizeFunc) error {
 	var buffer = make([]byte, chunkSize)
 	var reader io.ReadCloser
 
-	for i := 0; true; i++ {
+	for {
 		var resp, file, err = index.Query(stream.Context(), req)
 		if err != nil {
 			return err
 		}
 
+		if authorize == nil {
+			// Pass.
+		} else if denied, err := isReadDenied(stream.Context(), resp, authorize); err != nil {
+			return err
+		} else if denied {
+			// Skip to the end of the Fragment. The client observes an offset
+			// jump at the next authorized Fragment which is read.
+			if req.Offset = resp.Fragment.End; req.EndOffset != 0 && req.Offset >= req.EndOffset {
+				return nil
+			}
+			continue
+		}
+
 		// Send the Header with the first response message (only).
-		if i == 0 {
-			resp.Header = hdr
+		if hdr != nil {
+			resp.Header, hdr = hdr, nil
 		}
 		if err = stream.SendMsg(resp); err != nil {
 			return err
@@ -200,7 +229,20 @@ func serveRead(stream grpc.ServerStream, req *pb.ReadRequest, hdr *pb.Header, in
 
 		// Loop to query and read the next Fragment.
 	}
-	return nil
+}
+
+// isReadDenied returns true if the metadata ReadResponse is of a Fragment
+// which the client isn't authorized to read.
+func isReadDenied(ctx context.Context, resp *pb.ReadResponse, authorize ReadAuthorizeFunc) (bool, error) {
+	if resp.Status != pb.Status_OK || resp.Fragment == nil {
+		return false, nil
+	} else if ok, err := authorize(ctx, *resp.Fragment); err != nil {
+		return false, errors.WithMessage(err, "authorizing read")
+	} else if !ok {
+		readDeniedBytesTotal.Add(float64(resp.Fragment.End - resp.Offset))
+		return true, nil
+	}
+	return false, nil
 }
 
 var chunkSize = 1 << 17 // 128K.
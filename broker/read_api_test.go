@@ -340,6 +340,135 @@ func TestReadProxyCases(t *testing.T) {
 	peer.Cleanup()
 }
 
+func TestReadAuthorizationCases(t *testing.T) {
+	var ctx, etcd = pb.WithDispatchDefault(context.Background()), etcdtest.TestClient()
+	defer etcdtest.Cleanup()
+
+	var broker = newTestBroker(t, etcd, pb.ProcessSpec_ID{Zone: "local", Suffix: "broker"})
+	setTestJournal(broker, pb.JournalSpec{Name: "a/journal", Replication: 1}, broker.id)
+
+	// Swap in a Spool which doesn't persist completed Fragments, so that
+	// fixtures may roll it to build multiple local Fragments.
+	var replica = broker.replica("a/journal")
+	<-replica.spoolCh
+	var spool = fragment.NewSpool("a/journal", struct {
+		*fragment.Index
+		noopSpoolCompleter
+	}{replica.index, noopSpoolCompleter{}})
+
+	for _, content := range []string{"allow", "deny", "allowed"} {
+		var roll = spool.Next()
+		roll.Begin, roll.Sum = roll.End, pb.SHA1Sum{}
+		spool.MustApply(&pb.ReplicateRequest{Proposal: &roll, Registers: &spool.Registers})
+		spool.MustApply(&pb.ReplicateRequest{Content: []byte(content)})
+		spool.MustApply(&pb.ReplicateRequest{Proposal: boxFragment(spool.Next())})
+	}
+	// Deny the Fragment [5, 9) of "deny".
+	broker.svc.ReadAuthorizer = func(_ context.Context, f pb.Fragment) (bool, error) {
+		if f.Begin == 5 {
+			return false, nil
+		} else if f.Begin == 9 && f.End > 16 {
+			return false, errors.New("whoops")
+		}
+		return true, nil
+	}
+
+	// Case: reads skip over the unauthorized Fragment, and the client
+	// observes an offset jump.
+	var stream, err = broker.client().Read(ctx, &pb.ReadRequest{Journal: "a/journal"})
+	require.NoError(t, err)
+
+	expectReadResponse(t, stream, pb.ReadResponse{
+		Status:    pb.Status_OK,
+		Header:    broker.header("a/journal"),
+		Offset:    0,
+		WriteHead: 16,
+		Fragment: &pb.Fragment{
+			Journal:          "a/journal",
+			Begin:            0,
+			End:              5,
+			Sum:              pb.SHA1SumOf("allow"),
+			CompressionCodec: pb.CompressionCodec_NONE,
+		},
+	})
+	expectReadResponse(t, stream, pb.ReadResponse{Status: pb.Status_OK, Offset: 0, Content: []byte("allow")})
+	expectReadResponse(t, stream, pb.ReadResponse{
+		Status:    pb.Status_OK,
+		Offset:    9,
+		WriteHead: 16,
+		Fragment: &pb.Fragment{
+			Journal:          "a/journal",
+			Begin:            9,
+			End:              16,
+			Sum:              pb.SHA1SumOf("allowed"),
+			CompressionCodec: pb.CompressionCodec_NONE,
+		},
+	})
+	expectReadResponse(t, stream, pb.ReadResponse{Status: pb.Status_OK, Offset: 9, Content: []byte("allowed")})
+	expectReadResponse(t, stream, pb.ReadResponse{
+		Status:    pb.Status_OFFSET_NOT_YET_AVAILABLE,
+		Offset:    16,
+		WriteHead: 16,
+	})
+	_, err = stream.Recv()
+	require.Equal(t, io.EOF, err)
+
+	// Case: a read which ends within the unauthorized Fragment sends only
+	// its Header.
+	stream, err = broker.client().Read(ctx, &pb.ReadRequest{Journal: "a/journal", Offset: 6, EndOffset: 8})
+	require.NoError(t, err)
+
+	_, err = stream.Recv()
+	require.Equal(t, io.EOF, err)
+
+	// Case: authorization errors fail the RPC.
+	spool.MustApply(&pb.ReplicateRequest{Content: []byte("!")})
+	spool.MustApply(&pb.ReplicateRequest{Proposal: boxFragment(spool.Next())})
+
+	stream, err = broker.client().Read(ctx, &pb.ReadRequest{Journal: "a/journal", Offset: 9})
+	require.NoError(t, err)
+
+	_, err = stream.Recv()
+	require.EqualError(t, err, `rpc error: code = Unknown desc = authorizing read: whoops`)
+
+	replica.spoolCh <- spool // Release.
+	broker.cleanup()
+}
+
+func TestReadProxyAuthorization(t *testing.T) {
+	var ctx, etcd = pb.WithDispatchDefault(context.Background()), etcdtest.TestClient()
+	defer etcdtest.Cleanup()
+
+	var broker = newTestBroker(t, etcd, pb.ProcessSpec_ID{Zone: "local", Suffix: "broker"})
+	var peer = newMockBroker(t, etcd, pb.ProcessSpec_ID{Zone: "peer", Suffix: "broker"})
+	setTestJournal(broker, pb.JournalSpec{Name: "a/journal", Replication: 1}, peer.id)
+
+	broker.svc.ReadAuthorizer = func(_ context.Context, f pb.Fragment) (bool, error) {
+		return f.Begin != 5, nil
+	}
+	var stream, _ = broker.client().Read(ctx, &pb.ReadRequest{Journal: "a/journal"})
+	_ = <-peer.ReadReqCh
+
+	// Peer responds with Fragments [0, 5) and [5, 9), the latter of which
+	// the proxying broker doesn't authorize.
+	for _, f := range []pb.Fragment{{Begin: 0, End: 5}, {Begin: 5, End: 9}} {
+		var f = f
+		peer.ReadRespCh <- pb.ReadResponse{Offset: f.Begin, WriteHead: 9, Fragment: &f}
+		peer.ReadRespCh <- pb.ReadResponse{Offset: f.Begin, Content: []byte("content")}
+	}
+	peer.WriteLoopErrCh <- nil // EOF.
+
+	// Expect the denial is surfaced to the client, ending the RPC.
+	expectReadResponse(t, stream, pb.ReadResponse{Offset: 0, WriteHead: 9, Fragment: &pb.Fragment{Begin: 0, End: 5}})
+	expectReadResponse(t, stream, pb.ReadResponse{Offset: 0, Content: []byte("content")})
+	expectReadResponse(t, stream, pb.ReadResponse{Status: pb.Status_NOT_ALLOWED, Offset: 5, WriteHead: 9})
+	var _, err = stream.Recv()
+	require.Equal(t, io.EOF, err)
+
+	broker.cleanup()
+	peer.Cleanup()
+}
+
 func TestReadRemoteFragmentCases(t *testing.T) {
 	var ctx, etcd = pb.WithDispatchDefault(context.Background()), etcdtest.TestClient()
 	defer etcdtest.Cleanup()
@@ -506,6 +635,10 @@ func boxLabels(nv ...string) *pb.LabelSet {
 	return &l
 }
 
+type noopSpoolCompleter struct{}
+
+func (noopSpoolCompleter) SpoolComplete(fragment.Spool, bool) {}
+
 func expectReadResponse(t require.TestingT, stream pb.Journal_ReadClient, expect pb.ReadResponse) {
 	var resp, err = stream.Recv()
 	require.NoError(t, err)
@@ -3,6 +3,8 @@ package broker
 import (
 	"context"
 	"io"
+	"math"
+	"math/rand"
 	"time"
 
 	"github.com/pkg/errors"
@@ -32,6 +34,12 @@ type replica struct {
 	// by an Append RPC which is in appendFSM stateStreamContent (and there can be
 	// at most one such RPC).
 	appendFlowControl appendFlowControl
+	// storesCh is signaled when the fragment stores of the journal's JournalSpec
+	// change, and prompts an immediate refresh of remote fragments.
+	storesCh chan struct{}
+	// fragmentLength adapts the target Fragment length of the journal. Like
+	// the Spool, it's accessed only by the owner of the replica's pipeline.
+	fragmentLength adaptiveLength
 }
 
 func newReplica(journal pb.Journal) *replica {
@@ -44,6 +52,7 @@ func newReplica(journal pb.Journal) *replica {
 		index:      fragment.NewIndex(ctx),
 		spoolCh:    make(chan fragment.Spool, 1),
 		pipelineCh: make(chan *pipeline, 1),
+		storesCh:   make(chan struct{}, 1),
 	}
 
 	r.spoolCh <- fragment.NewSpool(journal, struct {
@@ -58,15 +67,27 @@ func newReplica(journal pb.Journal) *replica {
 
 // fragmentRefreshDaemon periodically refreshes the local index of replica
 // fragments from configured remote stores, at configured intervals.
+//
+// The index is also refreshed as soon as the fragment stores of the JournalSpec
+// change (eg, during a store migration), so that Fragments of an added store
+// are promptly readable. Reads of Fragments already being served continue from
+// their prior store, and blocked reads resume as the refreshed index covers
+// their offsets. Fragments found only in a removed store are dropped from the
+// index (and logged), and a read of their offsets jumps to the next offset which
+// remains covered (observed by clients as the recoverable ErrOffsetJump).
 func fragmentRefreshDaemon(ks *keyspace.KeySpace, r *replica) {
 	var timer = time.NewTimer(0) // Fires immediately.
 	defer timer.Stop()
 
+	var listed fragment.CoverSet // Fragments of the last successful refresh.
+
 	for {
 		select {
 		case _ = <-r.ctx.Done():
 			return
 		case _ = <-timer.C:
+		case _ = <-r.storesCh:
+			log.WithField("name", r.journal).Info("refreshing remote fragments of changed fragment stores")
 		}
 
 		var spec *pb.JournalSpec
@@ -83,17 +104,64 @@ func fragmentRefreshDaemon(ks *keyspace.KeySpace, r *replica) {
 			return
 		}
 
+		var started = time.Now()
+
 		if set, err := fragment.WalkAllStores(r.ctx, spec.Name, spec.Fragment.Stores); err == nil {
+			if dropped := removedStoreFragments(listed, set, spec.Fragment.Stores); len(dropped) != 0 {
+				log.WithFields(log.Fields{
+					"name":      spec.Name,
+					"fragments": len(dropped),
+					"begin":     dropped[0].Begin,
+					"end":       dropped[len(dropped)-1].End,
+					"stores":    spec.Fragment.Stores,
+				}).Warn("dropping fragments found only in removed fragment stores")
+			}
 			r.index.ReplaceRemote(set)
+			listed = set
+			fragmentRefreshesTotal.WithLabelValues("ok").Inc()
 		} else {
 			log.WithFields(log.Fields{
 				"name":     spec.Name,
 				"err":      err,
 				"interval": spec.Fragment.RefreshInterval,
 			}).Warn("failed to refresh remote fragments (will retry)")
+			fragmentRefreshesTotal.WithLabelValues("failed").Inc()
 		}
-		timer.Reset(spec.Fragment.RefreshInterval)
+		fragmentListingSeconds.Observe(time.Since(started).Seconds())
+
+		timer.Reset(jitterRefreshInterval(spec.Fragment.RefreshInterval))
+	}
+}
+
+// removedStoreFragments returns Fragments of the |prev| listing which are
+// backed by a store not among |stores|, and which cover offsets not also
+// covered by the |next| listing. Such Fragments are no longer readable.
+func removedStoreFragments(prev, next fragment.CoverSet, stores []pb.FragmentStore) fragment.CoverSet {
+	var out fragment.CoverSet
+
+	for _, frag := range fragment.CoverSetDifference(prev, next) {
+		var removed = true
+		for _, store := range stores {
+			if frag.BackingStore == store {
+				removed = false
+			}
+		}
+		if removed {
+			out = append(out, frag)
+		}
+	}
+	return out
+}
+
+// jitterRefreshInterval bounds |interval| to pb.MinRefreshInterval and
+// adds up to a tenth of it as random jitter. Jitter spreads the refreshes of
+// journals sharing an interval, which would otherwise list their stores in
+// lock-step (eg, as they were all assigned upon the broker's start).
+func jitterRefreshInterval(interval time.Duration) time.Duration {
+	if interval < pb.MinRefreshInterval {
+		interval = pb.MinRefreshInterval
 	}
+	return interval + time.Duration(rand.Int63n(int64(interval/10)+1))
 }
 
 // pulseDaemon performs periodic and on-demand invocations of a zero-byte
@@ -166,6 +234,10 @@ func shutDownReplica(r *replica, done func()) {
 	}
 	var sp = <-r.spoolCh
 
+	// The replica pipeline is drained, and its target fragment length is no
+	// longer updated.
+	fragmentTargetLengthGauge.DeleteLabelValues(r.journal.String())
+
 	// Roll the Spool forward to finalize & persist its current Fragment.
 	var fragment = sp.Fragment.Fragment
 	fragment.Begin, fragment.Sum = fragment.End, pb.SHA1Sum{}
@@ -218,6 +290,43 @@ func updateAssignments(ctx context.Context, assignments keyspace.KeyValues, etcd
 	}
 }
 
+// adaptiveLength adapts the target length of a journal's Fragments to its
+// smoothed rate of writes (see JournalSpec_Fragment.AdaptiveInterval).
+type adaptiveLength struct {
+	rate float64   // Smoothed write rate, in bytes per second.
+	end  int64     // Journal write head as-of |at|.
+	at   time.Time // Time of the last observed write head.
+}
+
+// observe the journal write head |end| at |now|, returning the adapted
+// target length of Fragments of |spec|, which must have an AdaptiveInterval.
+func (a *adaptiveLength) observe(spec pb.JournalSpec_Fragment, end int64, now time.Time) int64 {
+	var interval = spec.AdaptiveInterval.Seconds()
+
+	if a.at.IsZero() || end < a.end {
+		// Begin from the rate which is implied by the target Length.
+		a.rate = float64(spec.Length) / interval
+		a.end, a.at = end, now
+	} else if dt := now.Sub(a.at); dt >= adaptiveLengthQuantum {
+		// Update an exponentially-weighted moving average of the rate, which
+		// has a time constant of the interval. A rapid change of rate therefore
+		// moves the target length only gradually, and doesn't cause it to
+		// oscillate, while a sustained change is fully reflected after a few
+		// intervals.
+		var alpha = 1 - math.Exp(-dt.Seconds()/interval)
+		a.rate += alpha * (float64(end-a.end)/dt.Seconds() - a.rate)
+		a.end, a.at = end, now
+	}
+
+	var length = int64(a.rate * interval)
+	if length < spec.MinLength {
+		length = spec.MinLength
+	} else if length > spec.MaxLength {
+		length = spec.MaxLength
+	}
+	return length
+}
+
 // maybeRollFragment returns either the current Fragment, or an empty Fragment
 // which has been "rolled" to an offset at or after the current Spool End,
 // and which reflects the latest |spec|.
@@ -228,6 +337,10 @@ func maybeRollFragment(cur fragment.Spool, rollToOffset int64, spec pb.JournalSp
 		flushFragment = true // Empty fragment is trivially rolled.
 	} else if cl > spec.Length {
 		flushFragment = true // Roll if over the target Fragment length.
+	} else if spec.MaxSpoolBytes != 0 && cl > spec.MaxSpoolBytes {
+		flushFragment = true // Roll if over the journal's maximum Spool length.
+	} else if overSpoolBudget(cl) {
+		flushFragment = true // Roll to release our share of an exceeded budget.
 	} else if cur.Begin == 0 {
 		// We should roll after the journal's very first write. This has the
 		// effect of "dirtying" the remote fragment index, and protects against
@@ -272,7 +385,20 @@ func maybeRollFragment(cur fragment.Spool, rollToOffset int64, spec pb.JournalSp
 	return cur.Fragment.Fragment
 }
 
-var sharedPersister *fragment.Persister
+// overSpoolBudget returns true if the MaxSpoolBytes budget is exceeded, and a
+// Spool having content length |cl| holds at least its fair share of the budget.
+func overSpoolBudget(cl int64) bool {
+	if MaxSpoolBytes == 0 {
+		return false
+	}
+	var bytes, spools = spoolUsage()
+	return bytes > MaxSpoolBytes && spools != 0 && cl >= MaxSpoolBytes/spools
+}
+
+var (
+	sharedPersister *fragment.Persister
+	spoolUsage      = fragment.SpoolUsage
+)
 
 // SetSharedPersister sets the Persister instance used by the `broker` package.
 func SetSharedPersister(p *fragment.Persister) { sharedPersister = p }
@@ -280,4 +406,7 @@ func SetSharedPersister(p *fragment.Persister) { sharedPersister = p }
 var (
 	timeNow             = time.Now
 	healthCheckInterval = time.Minute
+	// Minimum duration between observations of an adaptiveLength, which
+	// bounds the noise of short-lived rates.
+	adaptiveLengthQuantum = time.Second
 )
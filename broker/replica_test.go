@@ -2,6 +2,9 @@ package broker
 
 import (
 	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -28,6 +31,7 @@ func TestReplicaShutdown(t *testing.T) {
 	var fsm = appendFSM{svc: broker.svc, ctx: ctx, req: pb.AppendRequest{Journal: "a/journal"}}
 	require.True(t, fsm.runTo(stateAwaitDesiredReplicas))
 	fsm.returnPipeline()
+	fragmentTargetLengthGauge.WithLabelValues("a/journal").Set(1 << 20)
 
 	// Delete the broker's assignment. shutDownReplica() will be started.
 	setTestJournal(broker, pb.JournalSpec{Name: "a/journal", Replication: 2}, pb.ProcessSpec_ID{}, peer.id)
@@ -42,6 +46,8 @@ func TestReplicaShutdown(t *testing.T) {
 		require.FailNow(t, "selected pipeline")
 	default:
 	}
+	// The journal's gauge was deleted.
+	require.False(t, fragmentTargetLengthGauge.DeleteLabelValues("a/journal"))
 
 	// TODO(johnny): verify the persisted spool as well. Requires hooks into persister.
 
@@ -187,3 +193,230 @@ func TestReplicaNextProposalCases(t *testing.T) {
 		require.Equal(t, proposal, test.out)
 	}
 }
+
+func TestReplicaNextProposalSpoolBudget(t *testing.T) {
+	defer func(b int64) { MaxSpoolBytes = b }(MaxSpoolBytes)
+	defer func(f func() (int64, int64)) { spoolUsage = f }(spoolUsage)
+
+	var spec = pb.JournalSpec_Fragment{Length: 1000, CompressionCodec: pb.CompressionCodec_NONE}
+	var large, small = fragment.NewSpool("a/large", &testSpoolObserver{}), fragment.NewSpool("a/small", &testSpoolObserver{})
+	large.Begin, large.End = 10, 310
+	small.Begin, small.End = 10, 20
+
+	// Three open Spools hold 500 bytes.
+	spoolUsage = func() (int64, int64) { return 500, 3 }
+
+	var isRolled = func(spool fragment.Spool) bool {
+		return maybeRollFragment(spool, 0, spec) != spool.Fragment.Fragment
+	}
+	// Case: no budgets. Spools aren't rolled.
+	require.False(t, isRolled(large))
+	require.False(t, isRolled(small))
+
+	// Case: the Spool of a journal is over the maximum of its JournalSpec.
+	spec.MaxSpoolBytes = 200
+	require.True(t, isRolled(large))
+	require.False(t, isRolled(small))
+	spec.MaxSpoolBytes = 0
+
+	// Case: the budget isn't exceeded.
+	MaxSpoolBytes = 500
+	require.False(t, isRolled(large))
+	require.False(t, isRolled(small))
+
+	// Case: the budget is exceeded. Only the Spool holding at least its fair
+	// share of the budget (450 / 3 = 150 bytes) is rolled.
+	MaxSpoolBytes = 450
+	require.True(t, isRolled(large))
+	require.False(t, isRolled(small))
+}
+
+func TestAdaptiveFragmentLength(t *testing.T) {
+	var spec = pb.JournalSpec_Fragment{
+		Length:           1 << 20,
+		MinLength:        1 << 16,
+		MaxLength:        1 << 24,
+		AdaptiveInterval: time.Minute,
+	}
+	var a adaptiveLength
+	var now = time.Unix(1600000000, 0)
+	var end int64
+
+	// Writes at |rate| bytes per second for |dur|, observing each second.
+	var write = func(rate int64, dur time.Duration) (out []int64) {
+		for i := time.Duration(0); i != dur; i += time.Second {
+			end, now = end+rate, now.Add(time.Second)
+			out = append(out, a.observe(spec, end, now))
+		}
+		return out
+	}
+
+	// Before a rate is observed, the target is the configured Length.
+	require.Equal(t, int64(1<<20), a.observe(spec, end, now))
+	// Observations within the quantum don't update the rate.
+	require.Equal(t, int64(1<<20), a.observe(spec, end+(1<<30), now.Add(time.Millisecond)))
+
+	// A journal written quickly has larger Fragments, up to the maximum.
+	var lengths = write(1<<20, 5*time.Minute)
+	require.Equal(t, int64(1<<24), lengths[len(lengths)-1])
+
+	// A brief pause of writes doesn't change the target.
+	require.Equal(t, []int64{1 << 24, 1 << 24, 1 << 24}, write(0, 3*time.Second))
+
+	// A sustained pause decreases it gradually, down to the minimum.
+	lengths = write(0, 10*time.Minute)
+	for i := 1; i != len(lengths); i++ {
+		require.LessOrEqual(t, lengths[i], lengths[i-1])
+	}
+	require.Equal(t, int64(1<<16), lengths[len(lengths)-1])
+
+	// A rate which rapidly alternates is smoothed, and the target doesn't oscillate.
+	for i := 0; i != 30; i++ {
+		write(100<<10, 10*time.Second)
+		write(0, 10*time.Second)
+	}
+	for i := 0; i != 3; i++ {
+		for _, l := range append(write(100<<10, 10*time.Second), write(0, 10*time.Second)...) {
+			require.InDelta(t, 50<<10*60, l, 0.1*50<<10*60)
+		}
+	}
+
+	// A write head which moves backwards (eg, as another broker became primary
+	// in the interim) restarts from the configured Length.
+	require.Equal(t, int64(1<<20), a.observe(spec, 0, now.Add(time.Second)))
+}
+
+func TestReplicaRefreshesFragmentsOnStoresChange(t *testing.T) {
+	var ctx, etcd = pb.WithDispatchDefault(context.Background()), etcdtest.TestClient()
+	defer etcdtest.Cleanup()
+
+	var tmpDir, err = ioutil.TempDir("", "ReplicaStores")
+	require.NoError(t, err)
+
+	defer func() { require.NoError(t, os.RemoveAll(tmpDir)) }()
+	defer func(s string) { fragment.FileSystemStoreRoot = s }(fragment.FileSystemStoreRoot)
+	fragment.FileSystemStoreRoot = tmpDir
+
+	// The "old" store holds a first Fragment, and the "new" store holds the
+	// first Fragment as well as a second one written after the migration.
+	var first = writeStoreFragmentFixture(t, tmpDir, "old", 0, "first data")
+	_ = writeStoreFragmentFixture(t, tmpDir, "new", 0, "first data")
+	var second = writeStoreFragmentFixture(t, tmpDir, "new", 10, "second data")
+
+	var broker = newTestBroker(t, etcd, pb.ProcessSpec_ID{Zone: "local", Suffix: "broker"})
+	var spec = pb.JournalSpec{Name: "a/journal", Replication: 1, Fragment: pb.JournalSpec_Fragment{
+		Stores:          []pb.FragmentStore{"file:///old/"},
+		RefreshInterval: time.Hour,
+	}}
+	setTestJournal(broker, spec, broker.id)
+
+	var r = broker.replica("a/journal")
+	go fragmentRefreshDaemon(broker.ks, r)
+	<-r.index.FirstRefreshCh()
+
+	// Begin a blocking read, which reads the first Fragment from the old store.
+	stream, err := broker.client().Read(ctx, &pb.ReadRequest{Journal: "a/journal", Block: true})
+	require.NoError(t, err)
+
+	expectReadResponse(t, stream, pb.ReadResponse{
+		Status:      pb.Status_OK,
+		Header:      broker.header("a/journal"),
+		Offset:      0,
+		WriteHead:   10,
+		Fragment:    &first,
+		FragmentUrl: "file:///old/" + first.ContentPath(),
+	})
+	expectReadResponse(t, stream, pb.ReadResponse{
+		Status:  pb.Status_OK,
+		Offset:  0,
+		Content: []byte("first data"),
+	})
+
+	// Change an unrelated field of the JournalSpec. Expect no refresh is signaled.
+	spec.Fragment.Retention = time.Hour * 24
+	setTestJournal(broker, spec, broker.id)
+	require.Len(t, r.storesCh, 0)
+
+	// Migrate to the new store, without awaiting the RefreshInterval.
+	// Expect the blocked read resumes with the second Fragment, without
+	// disruption to its stream.
+	spec.Fragment.Stores = []pb.FragmentStore{"file:///new/"}
+	setTestJournal(broker, spec, broker.id)
+
+	expectReadResponse(t, stream, pb.ReadResponse{
+		Status:      pb.Status_OK,
+		Offset:      10,
+		WriteHead:   21,
+		Fragment:    &second,
+		FragmentUrl: "file:///new/" + second.ContentPath(),
+	})
+	expectReadResponse(t, stream, pb.ReadResponse{
+		Status:  pb.Status_OK,
+		Offset:  10,
+		Content: []byte("second data"),
+	})
+
+	broker.cleanup()
+}
+
+// writeStoreFragmentFixture writes an uncompressed Fragment of |content|
+// at offset |begin| to the file:// |store| rooted at |dir|.
+func writeStoreFragmentFixture(t require.TestingT, dir, store string, begin int64, content string) pb.Fragment {
+	var frag = pb.Fragment{
+		Journal:          "a/journal",
+		Begin:            begin,
+		End:              begin + int64(len(content)),
+		Sum:              pb.SHA1SumOf(content),
+		CompressionCodec: pb.CompressionCodec_NONE,
+		BackingStore:     pb.FragmentStore("file:///" + store + "/"),
+	}
+	var path = filepath.Join(dir, store, frag.ContentPath())
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0700))
+	require.NoError(t, ioutil.WriteFile(path, []byte(content), 0600))
+
+	// Fragments listed from a store have a ModTime.
+	var info, err = os.Stat(path)
+	require.NoError(t, err)
+	frag.ModTime = info.ModTime().Unix()
+
+	return frag
+}
+
+func TestReplicaRefreshIntervalJitter(t *testing.T) {
+	for _, tc := range []struct{ interval, min, max time.Duration }{
+		{time.Minute, time.Minute, time.Minute + 6*time.Second},
+		{time.Hour, time.Hour, time.Hour + 6*time.Minute},
+		// Intervals are bounded to the minimum.
+		{0, time.Second, time.Second + 100*time.Millisecond},
+		{time.Millisecond, time.Second, time.Second + 100*time.Millisecond},
+	} {
+		for i := 0; i != 100; i++ {
+			var d = jitterRefreshInterval(tc.interval)
+			require.True(t, d >= tc.min && d <= tc.max, "%s not in [%s, %s]", d, tc.min, tc.max)
+		}
+	}
+}
+
+func TestReplicaRemovedStoreFragments(t *testing.T) {
+	var frag = func(begin, end int64, store pb.FragmentStore) fragment.Fragment {
+		return fragment.Fragment{Fragment: pb.Fragment{
+			Journal: "a/journal", Begin: begin, End: end, BackingStore: store}}
+	}
+	var stores = []pb.FragmentStore{"file:///new/"}
+
+	var prev = fragment.CoverSet{
+		frag(0, 10, "file:///old/"),  // Also covered by |next|.
+		frag(10, 20, "file:///old/"), // Only in the removed store.
+		frag(20, 30, "file:///new/"), // Deleted from a current store.
+		frag(30, 40, "file:///old/"), // Only in the removed store.
+	}
+	var next = fragment.CoverSet{
+		frag(0, 10, "file:///new/"),
+		frag(40, 50, "file:///new/"),
+	}
+	require.Equal(t, fragment.CoverSet{prev[1], prev[3]},
+		removedStoreFragments(prev, next, stores))
+
+	// A first listing drops nothing.
+	require.Nil(t, removedStoreFragments(nil, next, stores))
+}
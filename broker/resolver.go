@@ -32,6 +32,7 @@ type resolverReplica struct {
 	*replica
 	assignments keyspace.KeyValues
 	signalCh    chan struct{}
+	stores      []pb.FragmentStore // Fragment stores of the current JournalSpec.
 }
 
 func newResolver(state *allocator.State, newReplica func(pb.Journal) *replica) *resolver {
@@ -40,9 +41,7 @@ func newResolver(state *allocator.State, newReplica func(pb.Journal) *replica) *
 		replicas:   make(map[pb.Journal]*resolverReplica),
 		newReplica: newReplica,
 	}
-	state.KS.Mu.Lock()
-	state.KS.Observers = append(state.KS.Observers, r.updateResolutions)
-	state.KS.Mu.Unlock()
+	state.KS.AddObserver("broker.resolver.updateResolutions", 0, r.updateResolutions)
 	return r
 }
 
@@ -212,6 +211,7 @@ func (r *resolver) updateResolutions() {
 	for _, li := range r.state.LocalItems {
 		var item = li.Item.Decoded.(allocator.Item)
 		var name = pb.Journal(item.ID)
+		var stores = item.ItemValue.(*pb.JournalSpec).Fragment.Stores
 
 		var replica, ok = r.replicas[name]
 		if !ok {
@@ -220,6 +220,7 @@ func (r *resolver) updateResolutions() {
 				replica:     r.newReplica(name), // Newly assigned journal.
 				assignments: li.Assignments.Copy(),
 				signalCh:    make(chan struct{}),
+				stores:      stores,
 			}
 
 			var rt pb.Route
@@ -240,6 +241,15 @@ func (r *resolver) updateResolutions() {
 			replica.signalCh = make(chan struct{})
 			replica.assignments = li.Assignments.Copy()
 		}
+		if !equalFragmentStores(stores, replica.stores) {
+			replica.stores = stores
+
+			// Signal the change, without blocking if a prior signal is pending.
+			select {
+			case replica.storesCh <- struct{}{}:
+			default:
+			}
+		}
 	}
 
 	var prev = r.replicas
@@ -280,4 +290,16 @@ func (r *resolver) watch(ctx context.Context, etcd *clientv3.Client) error {
 	return err
 }
 
+func equalFragmentStores(a, b []pb.FragmentStore) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
 var errResolverStopped = errors.New("resolver has stopped serving local replicas")
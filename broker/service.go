@@ -10,6 +10,7 @@ import (
 	"go.gazette.dev/core/task"
 	"golang.org/x/net/trace"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 )
 
@@ -17,6 +18,11 @@ import (
 // drives local journal handling in response to allocator.State, powers
 // journal resolution, and is also an implementation of protocol.JournalServer.
 type Service struct {
+	// ReadAuthorizer is an optional ReadAuthorizeFunc which authorizes the
+	// reads of individual journal Fragments. It must be set before the Service
+	// begins to serve RPCs.
+	ReadAuthorizer ReadAuthorizeFunc
+
 	jc       pb.JournalClient
 	etcd     *clientv3.Client
 	resolver *resolver
@@ -28,6 +34,46 @@ type Service struct {
 	stopProxyReadsCh chan struct{}
 }
 
+// ReadAuthorizeFunc authorizes the read of a journal Fragment by the client of
+// a Read or ListFragments RPC, which may be identified from the RPC Context
+// (eg, by its peer or its incoming metadata). It's evaluated in addition to
+// journal-level authorization, and returns true if the client may read the
+// Fragment, or an error which fails the RPC.
+//
+// Authorization is evaluated per Fragment, which is the unit of granularity
+// of the authorized offset range. A Read RPC skips unauthorized Fragments, and
+// the client observes the skipped range as a gap (client.ErrOffsetJump) at the
+// next authorized Fragment it reads. Because a Fragment is rolled only between
+// appends, and an append of a message is wholly contained in one Fragment,
+// a message is either entirely authorized or entirely skipped: it's never
+// partially read. A ListFragments RPC lists unauthorized Fragments without
+// their signed URLs.
+//
+// Proxied RPCs are authorized by the proxying broker as well as by the serving
+// broker. The proxying broker forwards the incoming gRPC metadata of its client
+// to the serving broker, which authorizes the client's claims rather than those
+// of the proxying broker. Implementations should therefore identify clients by
+// their metadata (eg, a bearer token) rather than by their peer. Should the
+// proxying broker deny a Fragment which the serving broker allowed, the Read
+// RPC is ended with a NOT_ALLOWED status.
+//
+// A ReadAuthorizeFunc is invoked on the read path: once per Fragment served,
+// and again as a blocking read of the journal's current Fragment observes
+// each new commit. Its latency adds directly to the latency of reads, and
+// implementations should evaluate quickly and without blocking, such as by
+// caching decisions of each client and journal.
+type ReadAuthorizeFunc func(ctx context.Context, fragment pb.Fragment) (bool, error)
+
+// withForwardedClaims returns a Context of |ctx| which has the incoming gRPC
+// metadata of the client as its outgoing metadata, for use in RPCs which are
+// proxied to a peer broker on the client's behalf.
+func withForwardedClaims(ctx context.Context) context.Context {
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		ctx = metadata.NewOutgoingContext(ctx, md)
+	}
+	return ctx
+}
+
 // NewService constructs a new broker Service, driven by allocator.State.
 func NewService(state *allocator.State, jc pb.JournalClient, etcd *clientv3.Client) *Service {
 	var svc = &Service{
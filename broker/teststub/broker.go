@@ -32,6 +32,7 @@ type Broker struct {
 	ListFunc          func(context.Context, *pb.ListRequest) (*pb.ListResponse, error)           // List implementation.
 	ApplyFunc         func(context.Context, *pb.ApplyRequest) (*pb.ApplyResponse, error)         // Apply implementation.
 	ListFragmentsFunc func(context.Context, *pb.FragmentsRequest) (*pb.FragmentsResponse, error) // ListFragments implementation.
+	WatchFunc         func(*pb.WatchRequest, pb.Journal_WatchServer) error                       // Watch implementation.
 }
 
 // NewBroker returns a Broker instance served by a local gRPC server.
@@ -194,6 +195,11 @@ func (b *Broker) ListFragments(ctx context.Context, req *pb.FragmentsRequest) (*
 	return b.ListFragmentsFunc(ctx, req)
 }
 
+// Watch implements the JournalServer interface by proxying through WatchFunc.
+func (b *Broker) Watch(req *pb.WatchRequest, srv pb.Journal_WatchServer) error {
+	return b.WatchFunc(req, srv)
+}
+
 func init() { pb.RegisterGRPCDispatcher("local") }
 
 const timeout = time.Minute
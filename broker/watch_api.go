@@ -0,0 +1,195 @@
+package broker
+
+import (
+	"net"
+	"sort"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"go.gazette.dev/core/allocator"
+	pb "go.gazette.dev/core/broker/protocol"
+	pbx "go.gazette.dev/core/broker/protocol/ext"
+	"go.gazette.dev/core/keyspace"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// Watch dispatches the JournalServer.Watch API.
+func (svc *Service) Watch(req *pb.WatchRequest, stream pb.Journal_WatchServer) (err error) {
+	defer instrumentJournalServerRPC("Watch", &err, nil)()
+
+	defer func() {
+		if err != nil {
+			var addr net.Addr
+			if p, ok := peer.FromContext(stream.Context()); ok {
+				addr = p.Addr
+			}
+			log.WithFields(log.Fields{"err": err, "req": req, "client": addr}).
+				Warn("served Watch RPC failed")
+		}
+	}()
+
+	if err = req.Validate(); err != nil {
+		return err
+	}
+	var ctx = stream.Context()
+	var s = svc.resolver.state
+
+	// The client may resume from a revision which we've yet to read.
+	s.KS.Mu.RLock()
+	err = s.KS.WaitForRevision(ctx, req.ResumeRevision)
+	s.KS.Mu.RUnlock()
+
+	if ctx.Err() != nil {
+		return nil // Client went away.
+	} else if err != nil {
+		return err
+	}
+
+	// Resume from the KeySpace's retained history of Changes, or fall back
+	// to a snapshot if the client is too far behind.
+	var feed *keyspace.ChangeFeed
+	var changes []keyspace.Change
+
+	if req.ResumeRevision != 0 {
+		feed, changes, err = s.KS.NewChangeFeedFrom(req.ResumeRevision)
+		if err != nil && !errors.Is(err, keyspace.ErrChangeHistoryUnavailable) {
+			return err
+		}
+	}
+	if feed == nil {
+		feed = s.KS.NewChangeFeed(false)
+	}
+	defer feed.Close()
+
+	var resp = &pb.WatchResponse{
+		Revision: feed.Revision,
+		Snapshot: req.ResumeRevision == 0 || err != nil,
+	}
+
+	for first := true; ; first = false {
+		resp.Status = pb.Status_OK
+		resp.Header = pbx.NewUnroutedHeader(s)
+
+		s.KS.Mu.RLock()
+		if resp.Snapshot {
+			snapshotJournals(s, req.Selector, resp)
+		} else {
+			changedJournals(s, req.Selector, changes, resp)
+		}
+		s.KS.Mu.RUnlock()
+
+		// The first response is always sent, so that the client knows it's
+		// current. Responses of other Changes which don't affect watched
+		// journals are elided.
+		if first || len(resp.Journals) != 0 || len(resp.Deleted) != 0 {
+			if err = stream.Send(resp); err != nil {
+				return err
+			}
+		}
+
+		if changes, err = feed.Next(ctx); err == keyspace.ErrChangeFeedOverflow {
+			// The client didn't keep up with Changes of the KeySpace. End the
+			// stream, and have it re-connect to resync from its last revision.
+			return status.Error(codes.Aborted, "watch fell behind the journal KeySpace (re-connect to resync)")
+		} else if err != nil {
+			return nil // Client went away.
+		}
+		resp = &pb.WatchResponse{Revision: resp.Revision}
+
+		for _, change := range changes {
+			if change.Revision > resp.Revision {
+				resp.Revision = change.Revision
+			}
+		}
+	}
+}
+
+// snapshotJournals adds all journals which match |sel| to |resp|.
+// The KeySpace must be read-locked.
+func snapshotJournals(s *allocator.State, sel pb.LabelSelector, resp *pb.WatchResponse) {
+	for _, kv := range s.Items {
+		if journal, ok := watchedJournal(s, kv.Decoded.(allocator.Item).ID, sel); ok {
+			resp.Journals = append(resp.Journals, journal)
+		}
+	}
+}
+
+// changedJournals adds journals affected by |changes| to |resp|. Journals
+// which match |sel| are added to resp.Journals. Others are added to
+// resp.Deleted if a JournalSpec prior to |changes| matched |sel|, as the
+// client may know of it. The KeySpace must be read-locked.
+func changedJournals(s *allocator.State, sel pb.LabelSelector, changes []keyspace.Change, resp *pb.WatchResponse) {
+	var matched = make(map[string]bool) // Journal => whether a prior spec matched.
+
+	for _, change := range changes {
+		var kv = change.New
+		if kv == nil {
+			kv = change.Old
+		}
+
+		switch d := kv.Decoded.(type) {
+		case allocator.Item:
+			if change.Old != nil && journalMatches(change.Old.Decoded.(allocator.Item).ItemValue.(*pb.JournalSpec), sel) {
+				matched[d.ID] = true
+			} else if _, ok := matched[d.ID]; !ok {
+				matched[d.ID] = false
+			}
+		case allocator.Assignment:
+			if _, ok := matched[d.ItemID]; !ok {
+				matched[d.ItemID] = false
+			}
+		case allocator.Member:
+			// Routes of journals assigned to the member attach its endpoint.
+			for _, a := range s.Assignments {
+				var asn = a.Decoded.(allocator.Assignment)
+				if _, ok := matched[asn.ItemID]; !ok && asn.MemberZone == d.Zone && asn.MemberSuffix == d.Suffix {
+					matched[asn.ItemID] = false
+				}
+			}
+		}
+	}
+
+	var names = make([]string, 0, len(matched))
+	for name := range matched {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if journal, ok := watchedJournal(s, name, sel); ok {
+			resp.Journals = append(resp.Journals, journal)
+		} else if matched[name] {
+			resp.Deleted = append(resp.Deleted, pb.Journal(name))
+		}
+	}
+}
+
+// watchedJournal returns the current ListResponse_Journal of |name|, if it
+// exists and matches |sel|. The KeySpace must be read-locked.
+func watchedJournal(s *allocator.State, name string, sel pb.LabelSelector) (pb.ListResponse_Journal, bool) {
+	var ind, ok = s.KS.KeyValues.Search(allocator.ItemKey(s.KS, name))
+	if !ok {
+		return pb.ListResponse_Journal{}, false
+	}
+	var kv = s.KS.KeyValues[ind]
+	var journal = pb.ListResponse_Journal{
+		Spec:        *kv.Decoded.(allocator.Item).ItemValue.(*pb.JournalSpec),
+		ModRevision: kv.Raw.ModRevision,
+	}
+	if !journalMatches(&journal.Spec, sel) {
+		return pb.ListResponse_Journal{}, false
+	}
+	pbx.Init(&journal.Route, s.KS.KeyValues.Prefixed(allocator.ItemAssignmentsPrefix(s.KS, name)))
+	pbx.AttachEndpoints(&journal.Route, s.KS)
+
+	return journal, true
+}
+
+// journalMatches returns whether the JournalSpec, with its meta-labels,
+// matches the LabelSelector.
+func journalMatches(spec *pb.JournalSpec, sel pb.LabelSelector) bool {
+	var metaLabels = pb.ExtractJournalSpecMetaLabels(spec, pb.LabelSet{})
+	return sel.Matches(pb.UnionLabelSets(metaLabels, spec.LabelSet, pb.LabelSet{}))
+}
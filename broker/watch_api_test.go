@@ -0,0 +1,164 @@
+package broker
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.gazette.dev/core/allocator"
+	pb "go.gazette.dev/core/broker/protocol"
+	"go.gazette.dev/core/etcdtest"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestWatchCases(t *testing.T) {
+	var ctx, etcd = pb.WithDispatchDefault(context.Background()), etcdtest.TestClient()
+	defer etcdtest.Cleanup()
+
+	var fragSpec = pb.JournalSpec_Fragment{
+		Length:           1024,
+		RefreshInterval:  time.Second,
+		CompressionCodec: pb.CompressionCodec_SNAPPY,
+	}
+	var specA = &pb.JournalSpec{
+		Name:        "journal/A",
+		LabelSet:    pb.MustLabelSet("foo", "bar"),
+		Replication: 1,
+		Fragment:    fragSpec,
+	}
+	var specB = &pb.JournalSpec{
+		Name:        "journal/B",
+		Replication: 1,
+		Fragment:    fragSpec,
+	}
+	var specC = &pb.JournalSpec{
+		Name:        "journal/C",
+		LabelSet:    pb.MustLabelSet("foo", "baz"),
+		Replication: 1,
+		Fragment:    fragSpec,
+	}
+	var broker = newTestBroker(t, etcd, pb.ProcessSpec_ID{Zone: "local", Suffix: "broker"})
+
+	var apply = func(changes ...pb.ApplyRequest_Change) int64 {
+		var resp, err = broker.client().Apply(ctx, &pb.ApplyRequest{Changes: changes})
+		require.NoError(t, err)
+		require.Equal(t, pb.Status_OK, resp.Status)
+		return resp.Header.Etcd.Revision
+	}
+	var names = func(journals []pb.ListResponse_Journal) (out []pb.Journal) {
+		for _, j := range journals {
+			out = append(out, j.Spec.Name)
+		}
+		return
+	}
+	var sel = pb.LabelSelector{Include: pb.MustLabelSet("foo", "")}
+	apply(pb.ApplyRequest_Change{Upsert: specA}, pb.ApplyRequest_Change{Upsert: specB})
+
+	// Case: a new Watch begins with a snapshot of matched journals.
+	var watchCtx, cancel = context.WithCancel(ctx)
+	var stream, err = broker.client().Watch(watchCtx, &pb.WatchRequest{Selector: sel})
+	require.NoError(t, err)
+
+	resp, err := stream.Recv()
+	require.NoError(t, err)
+	require.NoError(t, resp.Validate())
+	require.True(t, resp.Snapshot)
+	require.Equal(t, []pb.Journal{"journal/A"}, names(resp.Journals))
+	require.NotZero(t, resp.Journals[0].ModRevision)
+
+	var snapshotRevision = resp.Revision
+
+	// Case: a change of a journal's route is streamed.
+	var rev = mustKeyValues(t, etcd, map[string]string{
+		allocator.AssignmentKey(broker.ks, allocator.Assignment{
+			ItemID:       "journal/A",
+			MemberZone:   "local",
+			MemberSuffix: "broker",
+			Slot:         0,
+		}): ""})
+
+	resp, err = stream.Recv()
+	require.NoError(t, err)
+	require.False(t, resp.Snapshot)
+	require.Equal(t, rev, resp.Revision)
+	require.Equal(t, []pb.Journal{"journal/A"}, names(resp.Journals))
+	require.Equal(t, []pb.ProcessSpec_ID{{Zone: "local", Suffix: "broker"}}, resp.Journals[0].Route.Members)
+	require.Equal(t, []pb.Endpoint{broker.srv.Endpoint()}, resp.Journals[0].Route.Endpoints)
+
+	// Case: changes of unmatched journals are elided. A journal which no
+	// longer matches is deleted, and a created journal which matches is sent.
+	var specAUnmatched = *specA
+	specAUnmatched.LabelSet = pb.LabelSet{}
+	var specBChanged = *specB
+	specBChanged.LabelSet = pb.MustLabelSet("other", "label")
+
+	apply(pb.ApplyRequest_Change{Upsert: &specBChanged, ExpectModRevision: -1})
+	apply(pb.ApplyRequest_Change{Upsert: &specAUnmatched, ExpectModRevision: -1})
+
+	resp, err = stream.Recv()
+	require.NoError(t, err)
+	require.Empty(t, resp.Journals)
+	require.Equal(t, []pb.Journal{"journal/A"}, resp.Deleted)
+
+	rev = apply(pb.ApplyRequest_Change{Upsert: specC})
+
+	resp, err = stream.Recv()
+	require.NoError(t, err)
+	require.Equal(t, rev, resp.Revision)
+	require.Equal(t, []pb.Journal{"journal/C"}, names(resp.Journals))
+	require.Empty(t, resp.Deleted)
+
+	cancel()
+
+	// Case: a Watch resumed from a prior revision begins with the net changes
+	// of matched journals since that revision.
+	stream, err = broker.client().Watch(ctx, &pb.WatchRequest{Selector: sel, ResumeRevision: snapshotRevision})
+	require.NoError(t, err)
+
+	resp, err = stream.Recv()
+	require.NoError(t, err)
+	require.False(t, resp.Snapshot)
+	require.Equal(t, rev, resp.Revision)
+	require.Equal(t, []pb.Journal{"journal/C"}, names(resp.Journals))
+	require.Equal(t, []pb.Journal{"journal/A"}, resp.Deleted)
+
+	// Case: a Watch resumed from a revision which is no longer retained by
+	// the KeySpace's history begins with a snapshot.
+	broker.ks.Mu.Lock()
+	broker.ks.ChangeHistory = 1
+	broker.ks.Mu.Unlock()
+	rev = apply(pb.ApplyRequest_Change{Upsert: specB, ExpectModRevision: -1})
+
+	stream, err = broker.client().Watch(ctx, &pb.WatchRequest{Selector: sel, ResumeRevision: snapshotRevision})
+	require.NoError(t, err)
+
+	resp, err = stream.Recv()
+	require.NoError(t, err)
+	require.True(t, resp.Snapshot)
+	require.Equal(t, rev, resp.Revision)
+	require.Equal(t, []pb.Journal{"journal/C"}, names(resp.Journals))
+	require.Empty(t, resp.Deleted)
+
+	// Case: a Watch which falls behind the ChangeFeedLimit is ended with
+	// a status which tells the client to re-connect and resync.
+	broker.ks.Mu.Lock()
+	broker.ks.ChangeFeedLimit = 1
+	broker.ks.Mu.Unlock()
+
+	apply(pb.ApplyRequest_Change{Upsert: specA, ExpectModRevision: -1},
+		pb.ApplyRequest_Change{Upsert: specB, ExpectModRevision: -1})
+
+	_, err = stream.Recv()
+	require.Equal(t, codes.Aborted, status.Code(err))
+	require.Regexp(t, `watch fell behind the journal KeySpace`, err)
+
+	// Case: Errors on request validation error.
+	stream, err = broker.client().Watch(ctx, &pb.WatchRequest{ResumeRevision: -1})
+	require.NoError(t, err)
+	_, err = stream.Recv()
+	require.Regexp(t, `invalid ResumeRevision \(-1; expected >= 0\)`, err)
+
+	broker.cleanup()
+}
@@ -2,6 +2,8 @@ package gazctlcmd
 
 import (
 	"context"
+	"fmt"
+	"time"
 
 	log "github.com/sirupsen/logrus"
 	"go.gazette.dev/core/broker/client"
@@ -28,6 +30,13 @@ Prune log examines the provided hints to identify Fragments of the log
 which have no intersection with any live files of the DB, and can thus
 be safely deleted.
 
+Fragments which are no longer needed are further retained for the
+recovery_log_retention of the ShardSpec which owns the log, and are
+deleted only if they were persisted before that retention. Fragments
+needed by any hints are never deleted, and a log is skipped entirely
+if its hints reference offsets which are no longer covered by the
+log's fragments, as the hints are then already invalid for recovery.
+
 CAUTION:
 
 When pruning recovery logs which have been forked from other logs,
@@ -49,6 +58,7 @@ func (cmd *cmdShardsPrune) Execute([]string) error {
 	var metrics = shardsPruneMetrics{}
 	var logSegmentSets = make(map[pb.Journal]recoverylog.SegmentSet)
 	var skipRecoveryLogs = make(map[pb.Journal]bool)
+	var logRetention = make(map[pb.Journal]time.Duration)
 
 	for _, shard := range listShards(rsc, cmd.Selector).Shards {
 		metrics.shardsTotal++
@@ -59,6 +69,9 @@ func (cmd *cmdShardsPrune) Execute([]string) error {
 		mbp.Must(err, "failed to fetch hints")
 
 		var recoveryLog = shard.Spec.RecoveryLog()
+		if r := shard.Spec.RecoveryLogRetention; r > logRetention[recoveryLog] {
+			logRetention[recoveryLog] = r
+		}
 
 		for _, curHints := range append(allHints.BackupHints, allHints.PrimaryHints) {
 			var hints = curHints.Hints
@@ -95,13 +108,25 @@ func (cmd *cmdShardsPrune) Execute([]string) error {
 			continue
 		}
 		log.WithField("journal", journal).Debug("checking fragments of journal")
-		for _, f := range fetchFragments(ctx, rjc, journal) {
+		var fragments = fetchFragments(ctx, rjc, journal)
+
+		if err := checkSegmentsCovered(segments, fragments); err != nil {
+			log.WithFields(log.Fields{
+				"journal": journal,
+				"err":     err,
+			}).Error("skipping journal because its hints are not covered by its fragments")
+			metrics.skippedJournals++
+			continue
+		}
+		var horizon = time.Now().Add(-logRetention[journal])
+
+		for _, f := range fragments {
 			var spec = f.Spec
 
 			metrics.fragmentsTotal++
 			metrics.bytesTotal += spec.ContentLength()
 
-			if len(segments.Intersect(journal, spec.Begin, spec.End)) == 0 {
+			if shouldPruneFragment(spec, segments, logRetention[journal], horizon) {
 				log.WithFields(log.Fields{
 					"log":  spec.Journal,
 					"name": spec.ContentName(),
@@ -123,6 +148,50 @@ func (cmd *cmdShardsPrune) Execute([]string) error {
 	return nil
 }
 
+// shouldPruneFragment returns true if the Fragment doesn't intersect any of
+// the hinted |segments|, and, if |retention| is non-zero, it was persisted
+// before |horizon|.
+func shouldPruneFragment(spec pb.Fragment, segments recoverylog.SegmentSet, retention time.Duration, horizon time.Time) bool {
+	if len(segments.Intersect(spec.Journal, spec.Begin, spec.End)) != 0 {
+		return false // Required for playback of hints.
+	} else if retention != 0 && (spec.ModTime == 0 || spec.ModTime >= horizon.Unix()) {
+		return false // Within the retention of the log.
+	}
+	return true
+}
+
+// checkSegmentsCovered returns an error if the offsets of hinted |segments|
+// aren't continuously covered by |fragments|, which are ordered on Begin.
+// A final Segment having a zero LastOffset must be covered through the
+// greatest listed Fragment End. Playback of the hints requires that each
+// hinted offset can be read: a log which fails this check has hints which
+// are already invalid, and must not be pruned further.
+func checkSegmentsCovered(segments recoverylog.SegmentSet, fragments []pb.FragmentsResponse__Fragment) error {
+	var end int64
+	for _, f := range fragments {
+		if f.Spec.End > end {
+			end = f.Spec.End
+		}
+	}
+	for _, segment := range segments {
+		var last = segment.LastOffset
+		if last == 0 {
+			last = end
+		}
+		var offset = segment.FirstOffset
+		for _, f := range fragments {
+			if f.Spec.Begin <= offset && f.Spec.End > offset {
+				offset = f.Spec.End
+			}
+		}
+		if offset < last {
+			return fmt.Errorf("hinted segment [%d, %d) of %s is not covered by fragments (missing offset %d)",
+				segment.FirstOffset, segment.LastOffset, segment.Log, offset)
+		}
+	}
+	return nil
+}
+
 func fetchFragments(ctx context.Context, journalClient pb.RoutedJournalClient, journal pb.Journal) []pb.FragmentsResponse__Fragment {
 	var err error
 	var req = pb.FragmentsRequest{
@@ -3,6 +3,7 @@ package gazctlcmd
 import (
 	"math/rand"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 	pb "go.gazette.dev/core/broker/protocol"
@@ -166,3 +167,55 @@ func TestSegmentFoldingWithManyLogs(t *testing.T) {
 		},
 	}, m)
 }
+
+func TestShouldPruneFragmentWithRetention(t *testing.T) {
+	var segments = recoverylog.SegmentSet{
+		{Author: 0x1, FirstSeqNo: 2, FirstOffset: 200, LastSeqNo: 9, LastOffset: 901, Log: "a/log"},
+		{Author: 0x2, FirstSeqNo: 25, FirstOffset: 2500, LastSeqNo: 27, LastOffset: 0, Log: "a/log"},
+	}
+	var now = time.Unix(10000, 0)
+	var frag = func(begin, end, modTime int64) pb.Fragment {
+		return pb.Fragment{Journal: "a/log", Begin: begin, End: end, ModTime: modTime}
+	}
+
+	// Fragments required by hinted segments are never pruned.
+	require.False(t, shouldPruneFragment(frag(100, 300, 1), segments, 0, now))
+	require.False(t, shouldPruneFragment(frag(3000, 4000, 1), segments, 0, now))
+	// Without retention, other fragments are pruned regardless of age.
+	require.True(t, shouldPruneFragment(frag(0, 200, 9999), segments, 0, now))
+	require.True(t, shouldPruneFragment(frag(1000, 2000, 0), segments, 0, now))
+
+	// With retention, only fragments persisted prior to the horizon are pruned.
+	var horizon = now.Add(-time.Hour)
+	require.True(t, shouldPruneFragment(frag(0, 200, horizon.Unix()-1), segments, time.Hour, horizon))
+	require.False(t, shouldPruneFragment(frag(0, 200, horizon.Unix()), segments, time.Hour, horizon))
+	require.False(t, shouldPruneFragment(frag(0, 200, 0), segments, time.Hour, horizon))
+	require.False(t, shouldPruneFragment(frag(200, 300, 1), segments, time.Hour, horizon))
+}
+
+func TestCheckSegmentsCovered(t *testing.T) {
+	var segments = recoverylog.SegmentSet{
+		{Author: 0x1, FirstSeqNo: 2, FirstOffset: 200, LastSeqNo: 9, LastOffset: 901, Log: "a/log"},
+		{Author: 0x2, FirstSeqNo: 25, FirstOffset: 2500, LastSeqNo: 27, LastOffset: 0, Log: "a/log"},
+	}
+	var frags = func(spans ...int64) (out []pb.FragmentsResponse__Fragment) {
+		for i := 0; i != len(spans); i += 2 {
+			out = append(out, pb.FragmentsResponse__Fragment{
+				Spec: pb.Fragment{Journal: "a/log", Begin: spans[i], End: spans[i+1]},
+			})
+		}
+		return
+	}
+
+	// Hinted segments are covered, and the pruned [0, 100) and [1000, 2000) are not required.
+	require.NoError(t, checkSegmentsCovered(segments, frags(100, 500, 450, 1000, 2000, 3000, 3000, 4000)))
+
+	// A missing fragment within a hinted segment is an error.
+	require.EqualError(t, checkSegmentsCovered(segments, frags(100, 500, 600, 1000, 2000, 4000)),
+		"hinted segment [200, 901) of a/log is not covered by fragments (missing offset 500)")
+
+	// As is a missing fragment following the final segment, which must be
+	// covered through the end of the log.
+	require.EqualError(t, checkSegmentsCovered(segments, frags(100, 1000, 2000, 3000, 3500, 4000)),
+		"hinted segment [2500, 0) of a/log is not covered by fragments (missing offset 3000)")
+}
@@ -0,0 +1,70 @@
+package gazctlcmd
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	pb "go.gazette.dev/core/broker/protocol"
+	"go.gazette.dev/core/consumer"
+	pc "go.gazette.dev/core/consumer/protocol"
+)
+
+type cmdShardsResetCheckpoint struct {
+	ID                   string           `long:"id" required:"true" description:"ID of the shard to reset"`
+	Offsets              map[string]int64 `long:"offset" required:"true" description:"Source journal and offset to read it from, as journal:offset. May be repeated"`
+	AllowStoreDivergence bool             `long:"allow-store-divergence" description:"Acknowledge that the shard's store isn't reset, and will diverge from its reset Checkpoint"`
+}
+
+func init() {
+	CommandRegistry.AddCommand("shards", "reset-checkpoint", "Reset source journal offsets of a shard", `
+Reset the offsets from which a shard reads its source journals.
+
+The shard's assignments are removed, and its next primary resumes reading each
+given source journal from its given offset, rather than from the Checkpoint of
+its store. Source journals which aren't given continue to be read from their
+current Checkpoint. This can be used to replay messages of a source journal
+from an earlier offset, or to skip past messages which can't be processed.
+
+The shard's store is not reset. It continues to reflect messages processed
+through its prior Checkpoint: skipped messages are never applied to it, and
+replayed messages are applied a second time. The application must tolerate this
+divergence, which must be acknowledged with --allow-store-divergence.
+
+Example:
+
+	gazctl shards reset-checkpoint --id my/shard --allow-store-divergence \
+	  --offset my/source/journal:123456 --offset my/other/journal:0
+`, &cmdShardsResetCheckpoint{})
+}
+
+func (cmd *cmdShardsResetCheckpoint) Execute([]string) error {
+	startup(ShardsCfg.BaseConfig)
+
+	var ctx, cancel = context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	var rsc = ShardsCfg.Consumer.MustRoutedShardClient(ctx)
+
+	var req = &pc.ResetCheckpointRequest{
+		Shard:                pc.ShardID(cmd.ID),
+		ReadThrough:          make(pb.Offsets, len(cmd.Offsets)),
+		AllowStoreDivergence: cmd.AllowStoreDivergence,
+	}
+	for journal, offset := range cmd.Offsets {
+		req.ReadThrough[pb.Journal(journal)] = offset
+	}
+	if err := req.Validate(); err != nil {
+		return fmt.Errorf("invalid request: %w", err)
+	}
+
+	if _, err := consumer.ResetShardCheckpoint(ctx, rsc, req); err != nil {
+		return fmt.Errorf("resetting shard checkpoint: %w", err)
+	}
+	log.WithFields(log.Fields{
+		"shard":       req.Shard,
+		"readThrough": req.ReadThrough,
+	}).Info("successfully reset shard checkpoint")
+
+	return nil
+}
@@ -11,12 +11,15 @@ import (
 	log "github.com/sirupsen/logrus"
 	"go.gazette.dev/core/allocator"
 	"go.gazette.dev/core/broker"
+	"go.gazette.dev/core/broker/client"
 	"go.gazette.dev/core/broker/fragment"
 	"go.gazette.dev/core/broker/http_gateway"
+	"go.gazette.dev/core/broker/journalaudit"
 	pb "go.gazette.dev/core/broker/protocol"
 	mbp "go.gazette.dev/core/mainboilerplate"
 	"go.gazette.dev/core/server"
 	"go.gazette.dev/core/task"
+	hv1 "google.golang.org/grpc/health/grpc_health_v1"
 )
 
 const iniFilename = "gazette.ini"
@@ -25,13 +28,19 @@ const iniFilename = "gazette.ini"
 var Config = new(struct {
 	Broker struct {
 		mbp.ServiceConfig
-		Limit          uint32        `long:"limit" env:"LIMIT" default:"1024" description:"Maximum number of Journals the broker will allocate"`
-		FileRoot       string        `long:"file-root" env:"FILE_ROOT" description:"Local path which roots file:// fragment stores (optional)"`
-		MaxAppendRate  uint32        `long:"max-append-rate" env:"MAX_APPEND_RATE" default:"0" description:"Max rate (in bytes-per-sec) that any one journal may be appended to. If zero, there is no max rate"`
-		MaxReplication uint32        `long:"max-replication" env:"MAX_REPLICATION" default:"9" description:"Maximum effective replication of any one journal, which upper-bounds its stated replication."`
-		MinAppendRate  uint32        `long:"min-append-rate" env:"MIN_APPEND_RATE" default:"65536" description:"Min rate (in bytes-per-sec) at which a client may stream Append RPC content. RPCs unable to sustain this rate are aborted"`
-		DisableStores  bool          `long:"disable-stores" env:"DISABLE_STORES" description:"Disable use of any configured journal fragment stores. The broker will neither list or persist remote fragments, and all data is discarded on broker exit."`
-		WatchDelay     time.Duration `long:"watch-delay" env:"WATCH_DELAY" default:"30ms" description:"Delay applied to the application of watched Etcd events. Larger values amortize the processing of fast-changing Etcd keys."`
+		Limit           uint32        `long:"limit" env:"LIMIT" default:"1024" description:"Maximum number of Journals the broker will allocate"`
+		FileRoot        string        `long:"file-root" env:"FILE_ROOT" description:"Local path which roots file:// fragment stores (optional)"`
+		MaxAppendRate   uint32        `long:"max-append-rate" env:"MAX_APPEND_RATE" default:"0" description:"Max rate (in bytes-per-sec) that any one journal may be appended to. If zero, there is no max rate"`
+		MaxReplication  uint32        `long:"max-replication" env:"MAX_REPLICATION" default:"9" description:"Maximum effective replication of any one journal, which upper-bounds its stated replication."`
+		MaxSpoolBytes   uint64        `long:"max-spool-bytes" env:"MAX_SPOOL_BYTES" default:"0" description:"Budget of content bytes held by open fragment spools of all journals. When exceeded, journals holding a fair share or more of the budget are flushed early. If zero, there is no budget"`
+		MinAppendRate   uint32        `long:"min-append-rate" env:"MIN_APPEND_RATE" default:"65536" description:"Min rate (in bytes-per-sec) at which a client may stream Append RPC content. RPCs unable to sustain this rate are aborted"`
+		DisableStores   bool          `long:"disable-stores" env:"DISABLE_STORES" description:"Disable use of any configured journal fragment stores. The broker will neither list or persist remote fragments, and all data is discarded on broker exit."`
+		WatchDelay      time.Duration `long:"watch-delay" env:"WATCH_DELAY" default:"30ms" description:"Delay applied to the application of watched Etcd events. Larger values amortize the processing of fast-changing Etcd keys."`
+		WatchRate       int           `long:"watch-rate" env:"WATCH_RATE" default:"0" description:"Max rate (in events-per-sec) at which watched Etcd events are applied, which smooths the processing of bursts of events. If zero, there is no max rate"`
+		AuditJournal    string        `long:"audit-journal" env:"AUDIT_JOURNAL" description:"Journal to which allocator assignment changes are recorded (optional)"`
+		StatusPath      string        `long:"allocator-status-path" env:"ALLOCATOR_STATUS_PATH" description:"HTTP path at which JSON allocator status is served (optional)"`
+		SlowOpThreshold time.Duration `long:"store-slow-op-threshold" env:"STORE_SLOW_OP_THRESHOLD" default:"1s" description:"Duration at or beyond which a fragment store operation is recorded as slow, and served at /debug/store-slow-ops. If zero, slow operations aren't recorded"`
+		SlowOpWindow    time.Duration `long:"store-slow-op-window" env:"STORE_SLOW_OP_WINDOW" default:"10m" description:"Duration for which slow fragment store operations are retained"`
 	} `group:"Broker" namespace:"broker" env-namespace:"BROKER"`
 
 	Etcd struct {
@@ -69,8 +78,11 @@ func (cmdServe) Execute(args []string) error {
 
 	broker.MinAppendRate = int64(Config.Broker.MinAppendRate)
 	broker.MaxAppendRate = int64(Config.Broker.MaxAppendRate)
+	broker.MaxSpoolBytes = int64(Config.Broker.MaxSpoolBytes)
 	pb.MaxReplication = int32(Config.Broker.MaxReplication)
 	fragment.DisableStores = Config.Broker.DisableStores
+	fragment.SlowOpThreshold = Config.Broker.SlowOpThreshold
+	fragment.SlowOpWindow = Config.Broker.SlowOpWindow
 
 	var (
 		lo   = pb.NewJournalClient(srv.GRPCLoopback)
@@ -89,8 +101,11 @@ func (cmdServe) Execute(args []string) error {
 		signalCh = make(chan os.Signal, 1)
 	)
 	pb.RegisterJournalServer(srv.GRPCServer, service)
+	hv1.RegisterHealthServer(srv.GRPCServer, broker.NewHealthServer(service))
 	srv.HTTPMux.Handle("/", http_gateway.NewGateway(rjc))
+	srv.HTTPMux.HandleFunc("/debug/store-slow-ops", fragment.ServeSlowOps)
 	ks.WatchApplyDelay = Config.Broker.WatchDelay
+	ks.WatchApplyRate = Config.Broker.WatchRate
 
 	log.WithFields(log.Fields{
 		"zone":     spec.Id.Zone,
@@ -98,6 +113,20 @@ func (cmdServe) Execute(args []string) error {
 		"endpoint": spec.Endpoint,
 	}).Info("starting broker")
 
+	// If an audit journal is configured, record allocator decisions to it.
+	var audit allocator.AuditFunc
+	if Config.Broker.AuditJournal != "" {
+		audit = journalaudit.NewAuditFunc(tasks.Context(),
+			client.NewAppendService(tasks.Context(), rjc), pb.Journal(Config.Broker.AuditJournal))
+	}
+
+	// If a status path is configured, serve allocator status at it.
+	var status *allocator.StatusHandler
+	if Config.Broker.StatusPath != "" {
+		status = allocator.NewStatusHandler(allocState)
+		srv.HTTPMux.Handle(Config.Broker.StatusPath, status)
+	}
+
 	mbp.Must(allocator.StartSession(allocator.SessionArgs{
 		Etcd:     etcd,
 		Tasks:    tasks,
@@ -105,6 +134,8 @@ func (cmdServe) Execute(args []string) error {
 		State:    allocState,
 		LeaseTTL: Config.Etcd.LeaseTTL,
 		SignalCh: signalCh,
+		Audit:    audit,
+		Status:   status,
 	}), "failed to start allocator session")
 
 	var persister = fragment.NewPersister(ks)
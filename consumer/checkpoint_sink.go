@@ -0,0 +1,154 @@
+package consumer
+
+import (
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	pc "go.gazette.dev/core/consumer/protocol"
+)
+
+// CheckpointSink is an optional interface of Application which mirrors the
+// Checkpoints of primary Shards to an external system, such as a metrics
+// database or a consumer-group offset store, for visibility of the Shard's
+// progress from outside of Gazette.
+//
+// Under CheckpointSinkStrong, WriteCheckpoint is called with each transaction
+// Checkpoint before it's committed to the Store. A failed write aborts the
+// transaction: the shard fails (or retries, if the error is Retryable and the
+// Service has a TxnRetry policy) and its Checkpoint is never committed. The
+// external Checkpoint is therefore never behind the committed one, but may be
+// ahead by the Checkpoint of a transaction which failed to commit.
+//
+// Under CheckpointSinkEventual, WriteCheckpoint is called with each
+// Checkpoint after it has committed to the Store, and from a goroutine
+// distinct from the transaction loop, so that a slow or failing external
+// system doesn't block transactions. A failed write is periodically retried,
+// and is superseded by the Checkpoint of a later transaction. The external Checkpoint therefore lags the committed one.
+//
+// Under either mode, WriteCheckpoint is also called with the Checkpoint which
+// is restored as the Shard becomes primary, or restarts its transactions,
+// which corrects an external Checkpoint of a transaction that failed to commit.
+// WriteCheckpoint should be idempotent, as a Checkpoint may be written more
+// than once.
+type CheckpointSink interface {
+	// WriteCheckpoint writes the Checkpoint of the Shard to the external system.
+	WriteCheckpoint(Shard, pc.Checkpoint) error
+	// CheckpointSinkMode returns the CheckpointSinkMode of the Shard.
+	CheckpointSinkMode(Shard) CheckpointSinkMode
+}
+
+// CheckpointSinkMode determines whether failed writes of a CheckpointSink
+// abort the consumer transaction, or are retried.
+type CheckpointSinkMode int
+
+const (
+	// CheckpointSinkStrong writes each Checkpoint before it commits,
+	// and aborts the transaction if the write fails.
+	CheckpointSinkStrong CheckpointSinkMode = iota
+	// CheckpointSinkEventual writes each Checkpoint after it commits,
+	// and retries failed writes without blocking transactions.
+	CheckpointSinkEventual
+)
+
+// Interval with which failed writes of a CheckpointSinkEventual
+// CheckpointSink are retried. Variable to facilitate testing.
+var checkpointSinkRetryInterval = 5 * time.Second
+
+// checkpointSinker writes the Checkpoints of a shard to its CheckpointSink.
+type checkpointSinker struct {
+	sink CheckpointSink
+	mode CheckpointSinkMode
+
+	pending  *pc.Checkpoint // Checkpoint yet to be written, if eventual.
+	signalCh chan struct{}  // Signalled upon a new |pending| Checkpoint.
+	mu       sync.Mutex     // Guards |pending|.
+}
+
+// newShardCheckpointSinker returns a checkpointSinker of a CheckpointSink
+// Application, or nil if the Application isn't a CheckpointSink. The
+// checkpointSinker of a CheckpointSinkEventual mode is served by a goroutine
+// until the shard is cancelled.
+func newShardCheckpointSinker(s *shard) *checkpointSinker {
+	var cs, ok = s.svc.App.(CheckpointSink)
+	if !ok {
+		return nil
+	}
+	var cw = &checkpointSinker{
+		sink:     cs,
+		mode:     cs.CheckpointSinkMode(s),
+		signalCh: make(chan struct{}, 1),
+	}
+	if cw.mode == CheckpointSinkEventual {
+		s.wg.Add(1)
+		go cw.serve(s)
+	}
+	return cw
+}
+
+// restored is called with the Checkpoint restored by the shard, which is
+// written (under CheckpointSinkStrong) or queued to be written.
+func (cw *checkpointSinker) restored(s *shard, cp pc.Checkpoint) error {
+	if cw.mode == CheckpointSinkStrong {
+		return cw.sink.WriteCheckpoint(s, cp)
+	}
+	cw.enqueue(cp)
+	return nil
+}
+
+// enqueue a committed Checkpoint to be written, superseding any pending
+// Checkpoint which hasn't yet been written.
+func (cw *checkpointSinker) enqueue(cp pc.Checkpoint) {
+	cw.mu.Lock()
+	cw.pending = &cp
+	cw.mu.Unlock()
+
+	select {
+	case cw.signalCh <- struct{}{}:
+	default: // Already signalled.
+	}
+}
+
+// serve writes pending Checkpoints until the shard is cancelled.
+func (cw *checkpointSinker) serve(s *shard) {
+	defer s.wg.Done()
+
+	var retryCh <-chan time.Time
+	for {
+		select {
+		case <-s.ctx.Done():
+			return
+		case <-cw.signalCh:
+		case <-retryCh:
+		}
+		retryCh = nil
+
+		cw.mu.Lock()
+		var cp = cw.pending
+		cw.pending = nil
+		cw.mu.Unlock()
+
+		if cp == nil {
+			continue
+		}
+		var err = cw.sink.WriteCheckpoint(s, *cp)
+		if err == nil {
+			continue
+		}
+
+		log.WithFields(log.Fields{
+			"err":   err,
+			"shard": s.Spec().Id,
+			"retry": checkpointSinkRetryInterval,
+		}).Warn("failed to write checkpoint to CheckpointSink (will retry)")
+
+		// Retry |cp|, unless it's first superseded.
+		cw.mu.Lock()
+		if cw.pending == nil {
+			cw.pending = cp
+		}
+		cw.mu.Unlock()
+
+		retryCh = time.After(checkpointSinkRetryInterval)
+	}
+}
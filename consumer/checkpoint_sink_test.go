@@ -0,0 +1,126 @@
+package consumer
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	pc "go.gazette.dev/core/consumer/protocol"
+)
+
+func TestStrongCheckpointSinkAbortsTxnOnFailure(t *testing.T) {
+	var tf, cleanup = newTestFixture(t)
+	defer cleanup()
+
+	var app = &checkpointSinkApplication{testApplication: tf.app, mode: CheckpointSinkStrong}
+	tf.service.App = app
+
+	var spec = makeRemoteShard(shardA)
+	tf.allocateShard(spec, localID)
+
+	var res, err = tf.resolver.Resolve(ResolveArgs{Context: context.Background(), ShardID: shardA})
+	require.NoError(t, err)
+	var shard = res.Shard
+	res.Done()
+
+	// The restored Checkpoint is written as the shard becomes primary.
+	require.Len(t, app.written(), 1)
+
+	// Each transaction Checkpoint is written before it commits.
+	runTransaction(tf, shard, map[string]string{"foo": "bar"})
+	var written = app.written()
+	var readThrough, _ = shard.Progress()
+	require.Equal(t, readThrough[sourceA.Name], written[len(written)-1].Sources[sourceA.Name].ReadThrough)
+
+	// A failed write aborts the transaction, which doesn't commit.
+	app.setErr(errors.New("whoops"))
+	_, _ = tf.pub.PublishCommitted(toSourceA, &testMessage{Key: "foo", Value: "baz"})
+
+	require.Equal(t, "runTransactions: txnStartCommit: CheckpointSink.WriteCheckpoint: whoops",
+		expectStatusCode(t, tf.state, pc.ReplicaStatus_FAILED).Errors[0])
+
+	var value string
+	require.NoError(t, tf.app.db.QueryRow(`SELECT value FROM kvstates WHERE key = 'foo'`).Scan(&value))
+	require.Equal(t, "bar", value)
+
+	tf.allocateShard(spec) // Cleanup.
+}
+
+func TestEventualCheckpointSinkRetriesFailures(t *testing.T) {
+	var tf, cleanup = newTestFixture(t)
+	defer cleanup()
+
+	defer func(d time.Duration) { checkpointSinkRetryInterval = d }(checkpointSinkRetryInterval)
+	checkpointSinkRetryInterval = time.Millisecond
+
+	var app = &checkpointSinkApplication{testApplication: tf.app, mode: CheckpointSinkEventual}
+	app.setErr(errors.New("whoops"))
+	tf.service.App = app
+
+	var spec = makeRemoteShard(shardA)
+	tf.allocateShard(spec, localID)
+
+	var res, err = tf.resolver.Resolve(ResolveArgs{Context: context.Background(), ShardID: shardA})
+	require.NoError(t, err)
+	defer res.Done()
+
+	// Transactions commit while the CheckpointSink is failing.
+	runTransaction(tf, res.Shard, map[string]string{"foo": "bar"})
+	runTransaction(tf, res.Shard, map[string]string{"foo": "baz"})
+	require.Empty(t, app.written())
+
+	var value string
+	require.NoError(t, tf.app.db.QueryRow(`SELECT value FROM kvstates WHERE key = 'foo'`).Scan(&value))
+	require.Equal(t, "baz", value)
+	expectStatusCode(t, tf.state, pc.ReplicaStatus_PRIMARY)
+
+	// Once the CheckpointSink recovers, the last committed Checkpoint is written.
+	var readThrough, _ = res.Shard.Progress()
+	app.setErr(nil)
+
+	require.Eventually(t, func() bool {
+		var written = app.written()
+		return len(written) != 0 &&
+			written[len(written)-1].Sources[sourceA.Name].ReadThrough == readThrough[sourceA.Name]
+	}, 5*time.Second, time.Millisecond)
+
+	tf.allocateShard(spec) // Cleanup.
+}
+
+// checkpointSinkApplication is a testApplication which is a CheckpointSink.
+type checkpointSinkApplication struct {
+	*testApplication
+	mode CheckpointSinkMode
+
+	err         error
+	checkpoints []pc.Checkpoint
+	mu          sync.Mutex
+}
+
+func (a *checkpointSinkApplication) WriteCheckpoint(_ Shard, cp pc.Checkpoint) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.err != nil {
+		return a.err
+	}
+	a.checkpoints = append(a.checkpoints, cp)
+	return nil
+}
+
+func (a *checkpointSinkApplication) CheckpointSinkMode(Shard) CheckpointSinkMode { return a.mode }
+
+func (a *checkpointSinkApplication) setErr(err error) {
+	a.mu.Lock()
+	a.err = err
+	a.mu.Unlock()
+}
+
+func (a *checkpointSinkApplication) written() []pc.Checkpoint {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return append([]pc.Checkpoint(nil), a.checkpoints...)
+}
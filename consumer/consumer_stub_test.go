@@ -22,6 +22,8 @@ type shardServerStub struct {
 	ApplyFunc    func(context.Context, *pc.ApplyRequest) (*pc.ApplyResponse, error)
 	GetHintsFunc func(context.Context, *pc.GetHintsRequest) (*pc.GetHintsResponse, error)
 	UnassignFunc func(context.Context, *pc.UnassignRequest) (*pc.UnassignResponse, error)
+
+	ResetCheckpointFunc func(context.Context, *pc.ResetCheckpointRequest) (*pc.ResetCheckpointResponse, error)
 }
 
 // newShardServerStub returns a shardServerStub instance served by a local GRPC server.
@@ -75,3 +77,8 @@ func (s *shardServerStub) GetHints(ctx context.Context, req *pc.GetHintsRequest)
 func (s *shardServerStub) Unassign(ctx context.Context, req *pc.UnassignRequest) (*pc.UnassignResponse, error) {
 	return s.UnassignFunc(ctx, req)
 }
+
+// ResetCheckpoint implements the shardServerStub interface by proxying through ResetCheckpointFunc.
+func (s *shardServerStub) ResetCheckpoint(ctx context.Context, req *pc.ResetCheckpointRequest) (*pc.ResetCheckpointResponse, error) {
+	return s.ResetCheckpointFunc(ctx, req)
+}
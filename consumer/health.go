@@ -0,0 +1,179 @@
+package consumer
+
+import (
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"go.gazette.dev/core/allocator"
+	pc "go.gazette.dev/core/consumer/protocol"
+	"go.gazette.dev/core/keyspace"
+)
+
+// HealthChecker is an optional interface of Application which checks the
+// application-level health of primary Shards. For example, an Application
+// may consider a Shard unhealthy if a downstream database to which it writes
+// is unreachable.
+//
+// CheckHealth is called periodically while the Shard serves as primary, and
+// returns nil if the Shard is healthy or an error describing why it isn't.
+// It's invoked concurrently with the Shard's transactions, and must not
+// access the Shard's Store without synchronizing with them.
+//
+// The health of a primary Shard is advertised by its ReplicaStatus. A Shard
+// which remains unhealthy for Service.ShardHealth.RelinquishAfter relinquishes
+// its primary assignment to a ready STANDBY replica (if there is one),
+// which may have working access to downstream systems.
+//
+// Health checks are subject to hysteresis: once a check fails, a Shard is
+// considered unhealthy until checks have passed for a continuous duration of
+// Service.ShardHealth.RecoverAfter. A Shard which flaps between passing and
+// failing checks therefore remains unhealthy, and will be relinquished.
+type HealthChecker interface {
+	CheckHealth(Shard) error
+}
+
+// HealthReporter is an optional interface of a Shard which reports its health.
+// Shards of this package are HealthReporters.
+type HealthReporter interface {
+	// Health returns nil if the Shard is healthy, or otherwise the error of
+	// its most recent failed health check. Shards are always healthy unless
+	// the Application is a HealthChecker.
+	Health() error
+}
+
+// shardHealth tracks the health of a primary shard, as reported by a HealthChecker.
+type shardHealth struct {
+	err            error     // Most recent failed check, or nil if healthy.
+	unhealthySince time.Time // Start of the current unhealthy period.
+	passingSince   time.Time // Start of passing checks while unhealthy, or zero.
+	mu             sync.Mutex
+}
+
+// observe updates the shardHealth with the result of a check at |now|. It
+// returns true if the check failed and the shard has been unhealthy for
+// at least |relinquishAfter|.
+func (h *shardHealth) observe(err error, now time.Time, recoverAfter, relinquishAfter time.Duration) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if err != nil {
+		if h.err == nil {
+			h.unhealthySince = now
+		}
+		h.err, h.passingSince = err, time.Time{}
+		return now.Sub(h.unhealthySince) >= relinquishAfter
+	}
+
+	if h.err != nil {
+		if h.passingSince.IsZero() {
+			h.passingSince = now
+		}
+		if now.Sub(h.passingSince) >= recoverAfter {
+			h.err = nil // Recovered.
+		}
+	}
+	return false
+}
+
+// get returns the current health error of the shard, or nil if healthy.
+func (h *shardHealth) get() error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.err
+}
+
+// watchHealth periodically checks the health of a primary shard until the
+// shard is cancelled or relinquishes its assignment, due to being unhealthy.
+func watchHealth(s *shard, hc HealthChecker) {
+	var cfg = s.svc.ShardHealth
+	var ticker = time.NewTicker(cfg.Interval)
+	var id = s.Spec().Id.String()
+	var advertised bool // Whether ReplicaStatus advertises the shard as unhealthy.
+
+	defer func() {
+		ticker.Stop()
+		shardHealthyGauge.DeleteLabelValues(id)
+		s.wg.Done()
+	}()
+
+	for {
+		select {
+		case <-s.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		var err = hc.CheckHealth(s)
+		var relinquish = s.health.observe(err, time.Now(), cfg.RecoverAfter, cfg.RelinquishAfter)
+		var unhealthy = s.health.get() != nil
+
+		if unhealthy {
+			shardHealthyGauge.WithLabelValues(id).Set(0)
+		} else {
+			shardHealthyGauge.WithLabelValues(id).Set(1)
+		}
+		if err != nil {
+			log.WithFields(log.Fields{"err": err, "shard": id}).Warn("shard health check failed")
+		}
+		// Advertise transitions of the shard's health in its ReplicaStatus.
+		if unhealthy != advertised {
+			if uErr := updateStatus(s, pc.ReplicaStatus{Code: pc.ReplicaStatus_PRIMARY}); uErr != nil {
+				log.WithFields(log.Fields{"err": uErr, "shard": id}).
+					Warn("failed to advertise shard health (will retry)")
+			} else {
+				advertised = unhealthy
+			}
+		}
+		if !relinquish {
+			continue
+		}
+
+		if err = relinquishPrimary(s); err != nil {
+			log.WithFields(log.Fields{"err": err, "shard": id}).
+				Warn("failed to relinquish unhealthy shard (will retry)")
+			continue
+		}
+		log.WithFields(log.Fields{"shard": id, "health": s.health.get()}).
+			Warn("relinquished primary assignment of unhealthy shard")
+		return
+	}
+}
+
+// relinquishPrimary removes the shard Assignment in a checked transaction,
+// if another replica of the shard is a ready STANDBY. As the Assignment is
+// removed, the shard is cancelled and the allocator promotes the STANDBY to
+// primary. Were there no STANDBY, the allocator could instead select this
+// same consumer as primary once again.
+func relinquishPrimary(s *shard) error {
+	var ks = s.svc.State.KS
+	var asn, standby keyspace.KeyValue
+
+	ks.Mu.RLock()
+	asn = s.resolved.assignment
+	for _, kv := range ks.Prefixed(allocator.ItemAssignmentsPrefix(ks, s.resolved.spec.Id.String())) {
+		var status = kv.Decoded.(allocator.Assignment).AssignmentValue.(*pc.ReplicaStatus)
+		if status.Code == pc.ReplicaStatus_STANDBY {
+			standby = kv
+		}
+	}
+	ks.Mu.RUnlock()
+
+	if standby.Raw.Key == nil {
+		return errors.Errorf("no other replica is a ready STANDBY")
+	}
+	var key = string(asn.Raw.Key)
+
+	var resp, err = s.svc.Etcd.Txn(s.ctx).
+		If(clientv3.Compare(clientv3.ModRevision(key), "=", asn.Raw.ModRevision),
+			clientv3.Compare(clientv3.ModRevision(string(standby.Raw.Key)), "=", standby.Raw.ModRevision)).
+		Then(clientv3.OpDelete(key)).
+		Commit()
+
+	if err == nil && !resp.Succeeded {
+		err = errors.Errorf("transaction failed")
+	}
+	return err
+}
@@ -0,0 +1,105 @@
+package consumer
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.gazette.dev/core/allocator"
+	pc "go.gazette.dev/core/consumer/protocol"
+)
+
+func TestShardHealthHysteresis(t *testing.T) {
+	var h shardHealth
+	var errA, errB = errors.New("A"), errors.New("B")
+
+	var observe = func(err error, at time.Duration) bool {
+		return h.observe(err, faketime(at), 10*time.Second, 30*time.Second)
+	}
+
+	// Passing checks of a healthy shard are a no-op.
+	require.False(t, observe(nil, 0))
+	require.NoError(t, h.get())
+
+	// A failed check makes the shard unhealthy.
+	require.False(t, observe(errA, 5*time.Second))
+	require.Equal(t, errA, h.get())
+
+	// Checks which flap don't restore health, and don't reset the
+	// unhealthy period. The most recent failure is reported.
+	require.False(t, observe(nil, 10*time.Second))
+	require.False(t, observe(errB, 15*time.Second))
+	require.False(t, observe(nil, 20*time.Second))
+	require.False(t, observe(nil, 25*time.Second)) // Passing for 5s.
+	require.Equal(t, errB, h.get())
+
+	// Once unhealthy for |relinquishAfter|, a failed check relinquishes.
+	require.True(t, observe(errA, 35*time.Second))
+	require.Equal(t, errA, h.get())
+
+	// Checks which pass for |recoverAfter| restore health.
+	require.False(t, observe(nil, 40*time.Second))
+	require.False(t, observe(nil, 45*time.Second))
+	require.Equal(t, errA, h.get())
+	require.False(t, observe(nil, 50*time.Second))
+	require.NoError(t, h.get())
+
+	// A subsequent failure begins a new unhealthy period.
+	require.False(t, observe(errB, 55*time.Second))
+	require.False(t, observe(errB, 80*time.Second))
+	require.True(t, observe(errB, 85*time.Second))
+}
+
+func TestShardRelinquishedWhenUnhealthy(t *testing.T) {
+	var tf, cleanup = newTestFixture(t)
+	defer cleanup()
+
+	tf.service.ShardHealth.Interval = time.Millisecond
+	tf.service.ShardHealth.RelinquishAfter = 10 * time.Millisecond
+	tf.app.healthErr = errors.New("downstream is unreachable")
+
+	var spec = makeRemoteShard(shardA)
+	tf.allocateShard(spec, localID, remoteID)
+
+	var res, err = tf.resolver.Resolve(ResolveArgs{Context: context.Background(), ShardID: shardA})
+	require.NoError(t, err)
+	var shard = res.Shard
+	res.Done()
+
+	// Expect the shard advertises that it's unhealthy.
+	tf.ks.Mu.RLock()
+	for {
+		var _, kv = pluckTheAssignment(t, tf.state)
+		var status = kv.Decoded.(allocator.Assignment).AssignmentValue.(*pc.ReplicaStatus)
+
+		if status.Unhealthy != "" {
+			require.Equal(t, &pc.ReplicaStatus{
+				Code:      pc.ReplicaStatus_PRIMARY,
+				Unhealthy: "downstream is unreachable",
+			}, status)
+			break
+		}
+		require.NoError(t, tf.ks.WaitForRevision(context.Background(), tf.ks.Header.Revision+1))
+	}
+	tf.ks.Mu.RUnlock()
+
+	// The shard isn't relinquished while its other replica isn't ready.
+	time.Sleep(2 * tf.service.ShardHealth.RelinquishAfter)
+	require.NoError(t, shard.Context().Err())
+
+	// Once the replica is a STANDBY, the shard is cancelled as its primary
+	// Assignment is removed. The STANDBY remains.
+	tf.setReplicaStatus(spec, remoteID, 1, pc.ReplicaStatus_STANDBY)
+	<-shard.Context().Done()
+	require.Equal(t, tf.app.healthErr, shard.(HealthReporter).Health())
+
+	tf.ks.Mu.RLock()
+	var asns = tf.ks.Prefixed(allocator.ItemAssignmentsPrefix(tf.ks, shardA))
+	require.Len(t, asns, 1)
+	require.Equal(t, remoteID.Suffix, asns[0].Decoded.(allocator.Assignment).MemberSuffix)
+	tf.ks.Mu.RUnlock()
+
+	tf.allocateShard(spec) // Cleanup.
+}
@@ -48,6 +48,26 @@ type Shard interface {
 	// instance will never change, but the instance returned by Assignment may
 	// change over time to reflect updated Etcd states.
 	Assignment() keyspace.KeyValue
+	// Epoch is a fencing token of the Shard's primary assignment, or zero if
+	// the Shard isn't assigned as primary to this process. It's the Etcd
+	// revision at which the primary Assignment was created (its CreateRevision,
+	// which is its ModRevision as of creation). An Assignment's ModRevision
+	// changes as its ReplicaStatus is updated, but its CreateRevision does not,
+	// and Epoch is therefore constant for the duration of a primary assignment.
+	//
+	// Each new primary assignment of the Shard, whether by a hand-off to
+	// another process or the promotion of a standby, creates a new Assignment
+	// key within a later Etcd transaction. Epochs of successive primaries
+	// therefore strictly increase, and are never re-used.
+	//
+	// External systems to which the Shard writes may use Epoch to fence writes
+	// of a stale primary, such as one which hasn't yet observed its hand-off:
+	// the system records the greatest Epoch it has seen of the Shard, and
+	// atomically rejects writes bearing a lesser Epoch. Having written with
+	// Epoch E, the write of a Shard having Epoch E' < E is always rejected.
+	// Writes bearing an equal Epoch are from the current primary, and should
+	// be idempotent with respect to re-delivered messages (see SinkFence).
+	Epoch() int64
 	// JournalClient to be used for raw journal []byte appends made on behalf
 	// of this Shard. Consistent use of this client enables Gazette to ensure
 	// that all writes issued within a consumer transaction have completed prior
@@ -174,6 +194,10 @@ type OpFutures = client.OpFutures
 // If a stall isn't forthcoming (as is frequent at high write rates), it will
 // close upon reaching the ShardSpec's MaxTxnDuration.
 //
+// Shards having a ShardSpec TxnBatchMessages or TxnBatchBytes instead process
+// fixed batches: the transaction closes once it has consumed its batch, or
+// upon reaching the ShardSpec's MaxTxnDuration with a partial batch.
+//
 // Upon transaction close, FinalizeTxn is called. At this point the Application
 // must publish any pending messages and/or begin related journal appends,
 // and must flush any in-memory caches or aggregates into its Store transaction
@@ -302,11 +326,41 @@ type MessageProducer interface {
 	ReadThrough(Shard, Store, ResolveArgs) (pb.Offsets, error)
 }
 
+// SourceResolver is an optional interface of Application which resolves the
+// source journals of a Shard from a listing of journals, rather than using the
+// static Sources of its ShardSpec. This allows a Shard to consume a variable
+// number of partitions, such as where partitions are added over time, without
+// re-defining its ShardSpec. It's ignored if the Application is also a
+// MessageProducer.
+//
+// Journals matching the SourceSelector are periodically listed, and
+// ResolveSources is called with each updated listing. Sources which weren't
+// previously resolved begin to be read without disturbing the reads of
+// existing sources, from the Checkpoint offset of the journal if there is one
+// and otherwise as directed by the ShardSpec StartPolicy (eg, from the source
+// MinOffset). Sources which are no longer resolved continue to be read until
+// the Shard is next re-assigned.
+//
+// Note that Resolver.ShardsWithSource reflects only ShardSpec Sources, and
+// ReadThrough offsets of ResolveArgs are awaited only for journals which
+// have already been resolved as sources.
+type SourceResolver interface {
+	// SourceSelector returns a LabelSelector of candidate source journals.
+	SourceSelector(Shard) pb.LabelSelector
+	// ResolveSources returns the sources of the Shard, given a current
+	// listing of journals which match its SourceSelector.
+	ResolveSources(Shard, *pb.ListResponse) []pc.ShardSpec_Source
+}
+
 var (
 	shardUpDesc = prometheus.NewDesc(
 		"gazette_shard_up",
 		"Indicates the processing status of a shard by this consumer.",
 		[]string{"shard", "status"}, nil)
+	shardsOffloadedDesc = prometheus.NewDesc(
+		"gazette_shards_offloaded",
+		"Number of shards which are offloaded.",
+		nil, nil)
 )
 
 var (
@@ -330,6 +384,30 @@ var (
 		Name: "gazette_shard_phase_seconds_total",
 		Help: "Cumulative number of seconds processing transactions.",
 	}, []string{"shard", "phase", "type"})
+	shardHealthyGauge = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "gazette_shard_healthy",
+		Help: "Application-reported health of a primary shard (1 if healthy, or 0).",
+	}, []string{"shard"})
+	shardTxnConcurrencyActiveGauge = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "gazette_shard_txn_concurrency_active",
+		Help: "Number of shard transactions holding a slot of the Service TxnLimiter.",
+	})
+	shardTxnConcurrencyWaitingGauge = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "gazette_shard_txn_concurrency_waiting",
+		Help: "Number of shard transactions waiting for a slot of the Service TxnLimiter.",
+	})
+	shardTxnConcurrencyLimitGauge = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "gazette_shard_txn_concurrency_limit",
+		Help: "Limit of concurrent shard transactions of the Service TxnLimiter (0 is unlimited).",
+	})
+	shardTxnRetriesTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "gazette_shard_txn_retries_total",
+		Help: "Total number of retries of failed consumer transactions of a primary shard.",
+	}, []string{"shard"})
+	sinkFencedWritesTotal = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "gazette_sink_fenced_writes_total",
+		Help: "Total number of re-delivered messages which SinkFences skipped writing to external sinks.",
+	})
 
 	// DEPRECATED metrics to be removed:
 	txCountTotal = promauto.NewCounter(prometheus.CounterOpts{
@@ -0,0 +1,256 @@
+package consumer
+
+import (
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+	pb "go.gazette.dev/core/broker/protocol"
+	pc "go.gazette.dev/core/consumer/protocol"
+	"go.gazette.dev/core/message"
+)
+
+// MergeOrderer is an optional interface of Application which consumes the
+// messages of multiple source journals in a deterministic, merged order,
+// rather than in the order in which they happen to be read. This is useful
+// for joins and other processing which must observe the messages of its
+// sources interleaved on a common ordering key, typically a timestamp.
+//
+// Each primary Shard buffers read messages in a FIFO queue per source journal.
+// A buffered message is released to ConsumeMessage only once every source of
+// the Shard has a buffered message, or has been stalled (having read no
+// message) for at least the MaxMergeWait. The released message is that of
+// least MergeTime at the head of any source queue, with ties broken by read
+// order. The messages of each source are thus consumed in the order of that
+// journal, and where sources are individually ordered on MergeTime, the merge
+// of sources is as well. A stalled source doesn't block the Shard: when it
+// resumes, its messages may be consumed after messages of other sources
+// having later merge times. Buffered messages are also released, absent
+// further reads, once every source lacking a buffered message has stalled.
+//
+// Buffered messages are written to the Shard's recovery log with each
+// transaction Checkpoint, and are restored alongside it. MergeOrderer requires
+// that the Shard have a recovery log, and that its source journals have a
+// content type which message.FramingByContentType supports. An Application
+// may not be both a MergeOrderer and an EventTimer.
+type MergeOrderer interface {
+	// MergeTime returns the time on which the message is merged. A zero-valued
+	// time.Time opts the message out of buffering: it's consumed immediately.
+	// Acknowledgements are always consumed immediately, and are not passed
+	// to MergeTime.
+	MergeTime(Shard, message.Envelope) time.Time
+	// MaxMergeWait returns the duration after which a source journal having
+	// no buffered messages no longer holds back the release of messages
+	// of other sources.
+	MaxMergeWait(Shard) time.Duration
+}
+
+// mergeBuffer buffers messages of source journals, releasing them in merged order.
+type mergeBuffer struct {
+	mergeTime func(message.Envelope) time.Time
+	maxWait   time.Duration
+	log       bufferLog
+	now       func() time.Time
+
+	sources map[pb.Journal]*mergeSource
+	seq     int64 // Next arrival sequence number.
+}
+
+// mergeSource is a FIFO queue of buffered messages of a source journal.
+type mergeSource struct {
+	buffered []bufferedMessage
+	lastRead time.Time // Time at which the source last read a message.
+}
+
+// newShardMergeBuffer returns a mergeBuffer of the MergeOrderer Application
+// and the sources of the shard, which persists to its recovery log.
+func newShardMergeBuffer(s *shard, mo MergeOrderer) (*mergeBuffer, error) {
+	if s.recovery.recorder == nil {
+		return nil, errors.New("MergeOrderer Application requires a shard recovery log")
+	}
+	var journals []pb.Journal
+	for _, src := range s.Spec().Sources {
+		journals = append(journals, src.Journal)
+	}
+	return newMergeBuffer(
+		func(env message.Envelope) time.Time { return mo.MergeTime(s, env) },
+		mo.MaxMergeWait(s),
+		newShardBufferLog(s, mergeStateName),
+		journals,
+	), nil
+}
+
+func newMergeBuffer(mergeTime func(message.Envelope) time.Time, maxWait time.Duration,
+	log bufferLog, journals []pb.Journal) *mergeBuffer {
+
+	var b = &mergeBuffer{
+		mergeTime: mergeTime,
+		maxWait:   maxWait,
+		log:       log,
+		now:       time.Now,
+		sources:   make(map[pb.Journal]*mergeSource, len(journals)),
+	}
+	for _, journal := range journals {
+		b.sources[journal] = new(mergeSource)
+	}
+	b.resetReads()
+	return b
+}
+
+// consume buffers |env|, and then calls |consumeFn| with each buffered
+// message which is released, in merged order. If |consumeFn| returns an
+// error, its message remains buffered.
+func (b *mergeBuffer) consume(env message.Envelope, consumeFn func(message.Envelope) error) error {
+	var now = b.now()
+	var src = b.source(env.Journal.Name, now)
+	src.lastRead = now
+
+	if message.GetFlags(env.GetUUID()) == message.Flag_ACK_TXN {
+		return consumeFn(env)
+	}
+	var mergeTime = b.mergeTime(env)
+	if mergeTime.IsZero() {
+		return consumeFn(env)
+	}
+
+	src.buffered = append(src.buffered, bufferedMessage{env: env, eventTime: mergeTime, seq: b.seq})
+	b.seq++
+
+	var _, err = b.releaseAt(now, consumeFn)
+	return err
+}
+
+// nextRelease returns the time at which every source having no buffered
+// message will have stalled, and buffered messages are released absent
+// further reads. It's zero if no messages are buffered.
+func (b *mergeBuffer) nextRelease() time.Time {
+	var at time.Time
+	var buffered bool
+
+	for _, src := range b.sources {
+		if len(src.buffered) != 0 {
+			buffered = true
+		} else if stalled := src.lastRead.Add(b.maxWait); stalled.After(at) {
+			at = stalled
+		}
+	}
+	if !buffered {
+		return time.Time{}
+	} else if at.IsZero() {
+		return b.now() // Every source has a buffered message.
+	}
+	return at
+}
+
+// release calls |consumeFn| with each buffered message which is released
+// at the current time, in merged order, and returns the number released.
+func (b *mergeBuffer) release(consumeFn func(message.Envelope) error) (int, error) {
+	return b.releaseAt(b.now(), consumeFn)
+}
+
+func (b *mergeBuffer) releaseAt(now time.Time, consumeFn func(message.Envelope) error) (int, error) {
+	var released int
+	for {
+		var next *mergeSource
+
+		for _, src := range b.sources {
+			if len(src.buffered) == 0 {
+				if now.Sub(src.lastRead) < b.maxWait {
+					return released, nil // Await a message of this source.
+				}
+				continue // Source is stalled.
+			}
+			if next == nil || mergeLess(src.buffered[0], next.buffered[0]) {
+				next = src
+			}
+		}
+		if next == nil {
+			return released, nil
+		}
+
+		if err := consumeFn(next.buffered[0].env); err != nil {
+			return released, err
+		}
+		next.buffered[0] = bufferedMessage{} // Release for GC.
+		next.buffered = next.buffered[1:]
+		released++
+	}
+}
+
+// persist writes the buffered messages to the recovery log in arrival
+// order, to be restored with Checkpoint |cp|.
+func (b *mergeBuffer) persist(cp pc.Checkpoint) error {
+	var buffered []bufferedMessage
+	for _, src := range b.sources {
+		buffered = append(buffered, src.buffered...)
+	}
+	sort.Slice(buffered, func(i, j int) bool { return buffered[i].seq < buffered[j].seq })
+
+	return b.log.persist(cp, time.Time{}, buffered)
+}
+
+// committed notifies that Checkpoint |cp| of a prior persist has committed.
+func (b *mergeBuffer) committed(cp pc.Checkpoint) error { return b.log.committed(cp) }
+
+// restore the buffered messages persisted alongside Checkpoint |cp|.
+// Each source is given the MaxMergeWait to read a message before it's
+// regarded as stalled.
+func (b *mergeBuffer) restore(cp pc.Checkpoint) error {
+	var _, buffered, err = b.log.restore(cp)
+	if err != nil {
+		return err
+	}
+	var now = b.now()
+
+	for _, src := range b.sources {
+		src.buffered = nil
+	}
+	for _, m := range buffered {
+		var src = b.source(m.env.Journal.Name, now)
+		src.buffered = append(src.buffered, m)
+	}
+	b.seq = int64(len(buffered))
+	b.resetReads()
+
+	return nil
+}
+
+// discard buffered messages of source |journals|.
+func (b *mergeBuffer) discard(journals []pb.Journal) {
+	for _, journal := range journals {
+		if src, ok := b.sources[journal]; ok {
+			src.buffered = nil
+		}
+	}
+}
+
+// source returns the mergeSource of |journal|, creating it if required.
+func (b *mergeBuffer) source(journal pb.Journal, now time.Time) *mergeSource {
+	var src, ok = b.sources[journal]
+	if !ok {
+		src = &mergeSource{lastRead: now}
+		b.sources[journal] = src
+	}
+	return src
+}
+
+func (b *mergeBuffer) resetReads() {
+	var now = b.now()
+	for _, src := range b.sources {
+		src.lastRead = now
+	}
+}
+
+// mergeLess orders on ascending merge time, and for the same merge time,
+// ascending arrival sequence.
+func mergeLess(a, b bufferedMessage) bool {
+	if !a.eventTime.Equal(b.eventTime) {
+		return a.eventTime.Before(b.eventTime)
+	}
+	return a.seq < b.seq
+}
+
+// mergeStateName is the name of the persisted mergeBuffer state,
+// which is written to files "merge.json" and "merge.next.json"
+// of the recovery log.
+const mergeStateName = "merge"
@@ -0,0 +1,347 @@
+package consumer
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+	"go.gazette.dev/core/broker/client"
+	pb "go.gazette.dev/core/broker/protocol"
+	pc "go.gazette.dev/core/consumer/protocol"
+	"go.gazette.dev/core/message"
+)
+
+func TestMergeBufferReleasesInMergedOrder(t *testing.T) {
+	var b, _ = newTestMergeBuffer(afero.NewMemMapFs())
+	var consumed []string
+
+	var consumeFn = func(env message.Envelope) error {
+		consumed = append(consumed, env.Message.(*testMessage).Key)
+		return nil
+	}
+	for _, tc := range []struct {
+		env    message.Envelope
+		expect []string
+	}{
+		// Source B has no buffered message. Await it.
+		{mergeEnv(sourceA, "a1", 10, 100), nil},
+		{mergeEnv(sourceA, "a2", 20, 200), nil},
+		// Source B's head is the least merge time.
+		{mergeEnv(sourceB, "b1", 5, 100), []string{"b1"}},
+		// "a1" is released, and then B is awaited again.
+		{mergeEnv(sourceB, "b2", 15, 200), []string{"a1", "b2"}},
+		// Equal merge times are released in read order.
+		{mergeEnv(sourceB, "b3", 20, 300), []string{"a2"}},
+		{mergeEnv(sourceA, "a3", 20, 300), []string{"b3"}},
+	} {
+		consumed = nil
+		require.NoError(t, b.consume(tc.env, consumeFn))
+		require.Equal(t, tc.expect, consumed)
+	}
+
+	// Messages with a zero merge time, and acknowledgements, aren't buffered.
+	consumed = nil
+	require.NoError(t, b.consume(mergeEnv(sourceA, "zero", 0, 400), consumeFn))
+
+	var ack = mergeEnv(sourceB, "ack", 30, 400)
+	ack.Message.SetUUID(message.BuildUUID(message.ProducerID{}, 1, message.Flag_ACK_TXN))
+	require.NoError(t, b.consume(ack, consumeFn))
+	require.Equal(t, []string{"zero", "ack"}, consumed)
+
+	// A failed message remains buffered, and is retried with the next message.
+	consumed = nil
+	var failFn = func(env message.Envelope) error { return errors.New("whoops") }
+	require.EqualError(t, b.consume(mergeEnv(sourceB, "b4", 25, 500), failFn), "whoops")
+	require.NoError(t, b.consume(mergeEnv(sourceA, "a4", 30, 500), consumeFn))
+	require.Equal(t, []string{"a3", "b4"}, consumed)
+	require.Equal(t, []string{"a4"}, mergeKeys(b))
+}
+
+func TestMergeBufferStalledSource(t *testing.T) {
+	var b, clock = newTestMergeBuffer(afero.NewMemMapFs())
+	var consumed []string
+
+	var consumeFn = func(env message.Envelope) error {
+		consumed = append(consumed, env.Message.(*testMessage).Key)
+		return nil
+	}
+	require.NoError(t, b.consume(mergeEnv(sourceA, "a1", 10, 100), consumeFn))
+	*clock = clock.Add(time.Minute - time.Second)
+	require.NoError(t, b.consume(mergeEnv(sourceA, "a2", 20, 200), consumeFn))
+	require.Empty(t, consumed)
+
+	// Source B has now been stalled for the MaxMergeWait, and no longer
+	// holds back the release of source A.
+	*clock = clock.Add(time.Second)
+	require.NoError(t, b.consume(mergeEnv(sourceA, "a3", 30, 300), consumeFn))
+	require.Equal(t, []string{"a1", "a2", "a3"}, consumed)
+
+	// A read of B resumes it, and A is again awaited.
+	consumed = nil
+	require.NoError(t, b.consume(mergeEnv(sourceB, "b1", 15, 100), consumeFn))
+	require.Empty(t, consumed)
+
+	// B's message is consumed, though it's behind messages of A which were
+	// already released. Then B is again awaited.
+	require.NoError(t, b.consume(mergeEnv(sourceA, "a4", 40, 400), consumeFn))
+	require.Equal(t, []string{"b1"}, consumed)
+	require.Equal(t, []string{"a4"}, mergeKeys(b))
+}
+
+func TestMergeBufferReleasesAbsentReads(t *testing.T) {
+	var b, clock = newTestMergeBuffer(afero.NewMemMapFs())
+	var consumed []string
+
+	var consumeFn = func(env message.Envelope) error {
+		consumed = append(consumed, env.Message.(*testMessage).Key)
+		return nil
+	}
+	var stalledAt = clock.Add(time.Minute)
+	require.True(t, b.nextRelease().IsZero())
+
+	// Source A reads no messages. Source B reads a message, and goes quiet.
+	*clock = clock.Add(30 * time.Second)
+	require.NoError(t, b.consume(mergeEnv(sourceB, "b1", 15, 100), consumeFn))
+	require.Empty(t, consumed)
+
+	// The message is released once A has stalled, though no read follows.
+	require.Equal(t, stalledAt, b.nextRelease())
+	var n, err = b.release(consumeFn)
+	require.NoError(t, err)
+	require.Equal(t, 0, n)
+
+	*clock = stalledAt
+	n, err = b.release(consumeFn)
+	require.NoError(t, err)
+	require.Equal(t, 1, n)
+	require.Equal(t, []string{"b1"}, consumed)
+	require.True(t, b.nextRelease().IsZero())
+
+	// A message deferred while every source has a buffered message is
+	// released as soon as it's retried.
+	consumed = nil
+	require.NoError(t, b.consume(mergeEnv(sourceA, "a1", 10, 100), consumeFn))
+	require.Equal(t, ErrDeferToNextTransaction, b.consume(mergeEnv(sourceB, "b2", 20, 200),
+		func(message.Envelope) error { return ErrDeferToNextTransaction }))
+	require.Equal(t, *clock, b.nextRelease())
+
+	n, err = b.release(consumeFn)
+	require.NoError(t, err)
+	require.Equal(t, 1, n)
+	require.Equal(t, []string{"a1"}, consumed)
+	require.Equal(t, []string{"b2"}, mergeKeys(b))
+}
+
+func TestMergeBufferDiscard(t *testing.T) {
+	var b, _ = newTestMergeBuffer(afero.NewMemMapFs())
+	var consumeFn = func(message.Envelope) error { return nil }
+
+	require.NoError(t, b.consume(mergeEnv(sourceA, "a1", 10, 100), consumeFn))
+	require.NoError(t, b.consume(mergeEnv(sourceA, "a2", 20, 200), consumeFn))
+	require.Equal(t, []string{"a1", "a2"}, mergeKeys(b))
+
+	b.discard([]pb.Journal{sourceB.Name})
+	require.Equal(t, []string{"a1", "a2"}, mergeKeys(b))
+	b.discard([]pb.Journal{sourceA.Name, "other/journal"})
+	require.Empty(t, mergeKeys(b))
+}
+
+func TestMergeBufferPersistAndRestore(t *testing.T) {
+	var fs = afero.NewMemMapFs()
+	var b, _ = newTestMergeBuffer(fs)
+	var consumeFn = func(message.Envelope) error { return nil }
+
+	var cp0 = mergeCheckpoint(100, 0)
+	var cp1 = mergeCheckpoint(200, 0)
+	var cp2 = mergeCheckpoint(300, 100)
+
+	// Restoring from an empty directory starts from an empty buffer,
+	// which is persisted alongside the restored Checkpoint.
+	require.NoError(t, b.restore(cp0))
+	require.Empty(t, mergeKeys(b))
+	require.NoError(t, b.restore(cp0))
+
+	require.NoError(t, b.consume(mergeEnv(sourceA, "a1", 10, 100), consumeFn))
+	require.NoError(t, b.consume(mergeEnv(sourceA, "a2", 20, 200), consumeFn))
+	require.NoError(t, b.persist(cp1))
+	require.NoError(t, b.committed(cp1))
+
+	var persistCP2 = func() {
+		require.NoError(t, b.consume(mergeEnv(sourceB, "b1", 15, 100), consumeFn)) // Releases "a1".
+		require.NoError(t, b.consume(mergeEnv(sourceA, "a3", 30, 300), consumeFn)) // Releases "b1".
+		require.NoError(t, b.persist(cp2))
+	}
+	persistCP2()
+
+	// The commit of |cp2| fails, and |cp1| is restored.
+	require.NoError(t, b.restore(cp1))
+	require.Equal(t, []string{"a1", "a2"}, mergeKeys(b))
+
+	// The commit of |cp2| fails a second time. |cp1| remains restorable.
+	persistCP2()
+	require.NoError(t, b.restore(cp1))
+	require.Equal(t, []string{"a1", "a2"}, mergeKeys(b))
+
+	// Restored messages are released in merged order with further reads.
+	var consumed []string
+	require.NoError(t, b.consume(mergeEnv(sourceB, "b1", 15, 100), func(env message.Envelope) error {
+		consumed = append(consumed, env.Message.(*testMessage).Key)
+		return nil
+	}))
+	require.Equal(t, []string{"a1", "b1"}, consumed)
+
+	// We fault after |cp2| commits, but before its commit was observed.
+	require.NoError(t, b.consume(mergeEnv(sourceA, "a3", 30, 300), consumeFn))
+	require.NoError(t, b.persist(cp2))
+
+	var restored, _ = newTestMergeBuffer(fs)
+	require.NoError(t, restored.restore(cp2))
+	require.Equal(t, []string{"a2", "a3"}, mergeKeys(restored))
+
+	// Checkpoints older than the last committed one can no longer be restored.
+	require.EqualError(t, restored.restore(cp1),
+		"no persisted merge state matches the restored checkpoint")
+}
+
+func TestShardMergeReleasesStalledSourceAbsentReads(t *testing.T) {
+	var tf, cleanup = newTestFixture(t)
+	defer cleanup()
+
+	tf.service.App = &mergeOrderApplication{testApplication: tf.app, maxWait: 50 * time.Millisecond}
+	tf.allocateShard(makeShard(shardA), localID)
+	expectStatusCode(t, tf.state, pc.ReplicaStatus_PRIMARY)
+
+	var res, err = tf.resolver.Resolve(ResolveArgs{Context: context.Background(), ShardID: shardA})
+	require.NoError(t, err)
+
+	// Source A reads no messages. Source B reads a message, and goes quiet.
+	var aa, _ = tf.pub.PublishCommitted(toSourceB, &testMessage{Key: "b1", Value: "15"})
+	<-aa.Done()
+
+	// The message is released once A has stalled for the MaxMergeWait,
+	// though no further message is read.
+	var ctx, cancel = context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var it = message.NewReadCommittedIter(
+		client.NewRetryReader(ctx, res.Shard.JournalClient(),
+			pb.ReadRequest{Journal: echoOut.Name, Block: true}),
+		new(testApplication).NewMessage,
+		message.NewSequencer(nil, nil, 16))
+
+	for {
+		var env, err = it.Next()
+		require.NoError(t, err)
+
+		if message.GetFlags(env.GetUUID()) != message.Flag_ACK_TXN {
+			require.Equal(t, "b1", env.Message.(*testMessage).Key)
+			break
+		}
+	}
+	verifyStoreAndEchoOut(t, res.Shard.(*shard), map[string]string{"b1": "15"})
+
+	res.Done()
+	tf.allocateShard(makeShard(shardA)) // Cleanup.
+}
+
+func TestShardMergeOrdererIsExclusiveOfEventTimer(t *testing.T) {
+	var tf, cleanup = newTestFixture(t)
+	defer cleanup()
+
+	tf.service.App = &eventMergeApplication{&eventTimeApplication{testApplication: tf.app}}
+
+	tf.allocateShard(makeShard(shardA), localID)
+	require.Equal(t, "completeRecovery: Application may not be both an EventTimer and a MergeOrderer",
+		expectStatusCode(t, tf.state, pc.ReplicaStatus_FAILED).Errors[0])
+
+	tf.allocateShard(makeShard(shardA)) // Cleanup.
+}
+
+// mergeOrderApplication is a testApplication which is a MergeOrderer. Merge
+// times are the testMessage Value, in seconds.
+type mergeOrderApplication struct {
+	*testApplication
+	maxWait time.Duration // If zero, a minute is used.
+}
+
+func (a *mergeOrderApplication) MergeTime(_ Shard, env message.Envelope) time.Time {
+	if sec, err := strconv.Atoi(env.Message.(*testMessage).Value); err == nil {
+		return time.Unix(int64(sec), 0)
+	}
+	return time.Time{}
+}
+
+func (a *mergeOrderApplication) MaxMergeWait(Shard) time.Duration {
+	if a.maxWait == 0 {
+		return time.Minute
+	}
+	return a.maxWait
+}
+
+// eventMergeApplication is both an EventTimer and a MergeOrderer.
+type eventMergeApplication struct{ *eventTimeApplication }
+
+func (a *eventMergeApplication) MergeTime(s Shard, env message.Envelope) time.Time {
+	return a.EventTime(s, env)
+}
+
+func (a *eventMergeApplication) MaxMergeWait(Shard) time.Duration { return time.Minute }
+
+func newTestMergeBuffer(fs afero.Fs) (*mergeBuffer, *time.Time) {
+	var app = &mergeOrderApplication{testApplication: new(testApplication)}
+	var clock = time.Unix(1600000000, 0)
+
+	var b = newMergeBuffer(
+		func(env message.Envelope) time.Time { return app.MergeTime(nil, env) },
+		app.MaxMergeWait(nil),
+		bufferLog{
+			name:   mergeStateName,
+			newMsg: app.NewMessage,
+			fs:     fs,
+			dir:    "/",
+		},
+		[]pb.Journal{sourceA.Name, sourceB.Name},
+	)
+	b.now = func() time.Time { return clock }
+	b.resetReads()
+
+	return b, &clock
+}
+
+func mergeEnv(spec *pb.JournalSpec, key string, sec int, end pb.Offset) message.Envelope {
+	var value = strconv.Itoa(sec)
+	if sec == 0 {
+		value = ""
+	}
+	return message.Envelope{
+		Journal: spec,
+		Begin:   end - 1,
+		End:     end,
+		Message: &testMessage{Key: key, Value: value},
+	}
+}
+
+func mergeCheckpoint(a, b pb.Offset) pc.Checkpoint {
+	return pc.Checkpoint{
+		Sources: map[pb.Journal]pc.Checkpoint_Source{
+			sourceA.Name: {ReadThrough: a},
+			sourceB.Name: {ReadThrough: b},
+		},
+	}
+}
+
+// mergeKeys returns keys of all buffered messages of the mergeBuffer,
+// ordered on source journal and then arrival.
+func mergeKeys(b *mergeBuffer) []string {
+	var keys []string
+	for _, journal := range []pb.Journal{sourceA.Name, sourceB.Name} {
+		for _, m := range b.sources[journal].buffered {
+			keys = append(keys, m.env.Message.(*testMessage).Key)
+		}
+	}
+	return keys
+}
@@ -0,0 +1,355 @@
+package consumer
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+	pb "go.gazette.dev/core/broker/protocol"
+	pc "go.gazette.dev/core/consumer/protocol"
+	"go.gazette.dev/core/consumer/recoverylog"
+	"go.gazette.dev/core/labels"
+	"go.gazette.dev/core/message"
+)
+
+// messageBuffer buffers messages dequeued by a primary Shard, and releases
+// them for consumption in an order of its choosing. Buffered messages are
+// persisted alongside each transaction Checkpoint, and restored with it.
+// It's implemented by watermarkBuffer (of an EventTimer Application) and
+// mergeBuffer (of a MergeOrderer Application).
+type messageBuffer interface {
+	// consume buffers |env|, and then calls |consumeFn| with each buffered
+	// message which is released. If |consumeFn| returns an error, its message
+	// remains buffered.
+	consume(env message.Envelope, consumeFn func(message.Envelope) error) error
+	// nextRelease returns the time at which buffered messages are next
+	// released absent further reads, or a zero time if they're not.
+	nextRelease() time.Time
+	// release calls |consumeFn| with each buffered message which is released
+	// at the current time, and returns the number released. If |consumeFn|
+	// returns an error, its message remains buffered.
+	release(consumeFn func(message.Envelope) error) (int, error)
+	// persist the buffered messages, to be restored with Checkpoint |cp|.
+	// persist must be called before the Checkpoint is committed to the Store.
+	persist(cp pc.Checkpoint) error
+	// committed notifies that Checkpoint |cp|, of a prior persist, has
+	// committed to the Store.
+	committed(cp pc.Checkpoint) error
+	// restore the buffered messages persisted alongside Checkpoint |cp|.
+	restore(cp pc.Checkpoint) error
+	// discard buffered messages of source |journals|, such as when
+	// their offsets are reset.
+	discard(journals []pb.Journal)
+}
+
+// newShardMessageBuffer returns the messageBuffer of the Shard's Application,
+// or nil if the Application doesn't buffer messages.
+func newShardMessageBuffer(s *shard) (messageBuffer, error) {
+	var et, isET = s.svc.App.(EventTimer)
+	var mo, isMO = s.svc.App.(MergeOrderer)
+
+	switch {
+	case isET && isMO:
+		return nil, errors.New("Application may not be both an EventTimer and a MergeOrderer")
+	case isET:
+		return newShardWatermarkBuffer(s, et)
+	case isMO:
+		return newShardMergeBuffer(s, mo)
+	default:
+		return nil, nil
+	}
+}
+
+// bufferReleaseTimer fires at the time a messageBuffer next releases
+// buffered messages absent further reads.
+type bufferReleaseTimer struct {
+	at    time.Time // Time at which |timer| fires.
+	timer *time.Timer
+}
+
+// arm the timer to fire at |at|, or stop it if |at| is zero.
+func (t *bufferReleaseTimer) arm(at time.Time) {
+	if at.Equal(t.at) {
+		return
+	}
+	t.stop()
+
+	if t.at = at; !at.IsZero() {
+		t.timer = time.NewTimer(time.Until(at))
+	}
+}
+
+// stop the timer.
+func (t *bufferReleaseTimer) stop() {
+	if t.timer != nil {
+		t.timer.Stop()
+	}
+	t.at, t.timer = time.Time{}, nil
+}
+
+// C returns the channel of the timer, or nil if it's stopped.
+func (t *bufferReleaseTimer) C() <-chan time.Time {
+	if t.timer == nil {
+		return nil
+	}
+	return t.timer.C
+}
+
+// bufferLog persists buffered messages to files of a directory, which is
+// typically that of the Shard's recovery log. State is written to a "next"
+// file, which is moved to "current" only once its Checkpoint is known to
+// have committed. This ensures that state matching the last committed
+// Checkpoint is always recoverable, however many commits of following
+// Checkpoints fail.
+type bufferLog struct {
+	name   string // Name of the persisted state, eg "watermark".
+	newMsg message.NewMessageFunc
+	fs     afero.Fs
+	dir    string
+
+	pending *pc.Checkpoint // Checkpoint of the "next" state, which has yet to commit.
+}
+
+// newShardBufferLog returns a bufferLog of the |name| state, which persists
+// to the recovery log of the shard.
+func newShardBufferLog(s *shard, name string) bufferLog {
+	return bufferLog{
+		name:   name,
+		newMsg: s.svc.App.NewMessage,
+		fs:     recoverylog.RecordedAferoFS{Recorder: s.recovery.recorder, Fs: afero.NewOsFs()},
+		dir:    s.recovery.recorder.Dir(),
+	}
+}
+
+// bufferState is the persisted state of a bufferLog.
+type bufferState struct {
+	// Checkpoint alongside which the state was persisted.
+	Checkpoint []byte
+	// Largest event time read (of a watermarkBuffer).
+	MaxEventTime time.Time
+	// Marshalled JournalSpecs of buffered messages.
+	Journals map[pb.Journal][]byte
+	// Buffered messages.
+	Messages []bufferStateMessage
+}
+
+type bufferStateMessage struct {
+	Journal    pb.Journal
+	Begin, End pb.Offset
+	EventTime  time.Time
+	Content    []byte // Message, as marshalled by the Framing of its Journal.
+}
+
+// persist |buffered| messages, in order, and |maxEventTime| to be restored
+// with Checkpoint |cp|.
+func (l *bufferLog) persist(cp pc.Checkpoint, maxEventTime time.Time, buffered []bufferedMessage) error {
+	var state = bufferState{
+		MaxEventTime: maxEventTime,
+		Journals:     make(map[pb.Journal][]byte),
+	}
+	var err error
+	if state.Checkpoint, err = cp.Marshal(); err != nil {
+		return errors.WithMessage(err, "marshal(checkpoint)")
+	}
+
+	var buf bytes.Buffer
+	var bw = bufio.NewWriter(&buf)
+
+	for _, m := range buffered {
+		var spec = m.env.Journal
+
+		if _, ok := state.Journals[spec.Name]; !ok {
+			if state.Journals[spec.Name], err = spec.Marshal(); err != nil {
+				return errors.WithMessage(err, "marshal(journal spec)")
+			}
+		}
+		framing, err := message.FramingByContentType(spec.LabelSet.ValueOf(labels.ContentType))
+		if err != nil {
+			return errors.WithMessage(err, "determining framing")
+		}
+
+		buf.Reset()
+		bw.Reset(&buf)
+
+		if err = framing.Marshal(m.env.Message, bw); err == nil {
+			err = bw.Flush()
+		}
+		if err != nil {
+			return errors.WithMessagef(err, "marshal(message %s:%d)", spec.Name, m.env.Begin)
+		}
+
+		state.Messages = append(state.Messages, bufferStateMessage{
+			Journal:   spec.Name,
+			Begin:     m.env.Begin,
+			End:       m.env.End,
+			EventTime: m.eventTime,
+			Content:   append([]byte(nil), buf.Bytes()...),
+		})
+	}
+
+	// Overwrite the "next" state of a prior Checkpoint which failed to commit.
+	l.pending = nil
+
+	f, err := l.fs.OpenFile(l.path(".next"), os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return errors.WithMessagef(err, "creating %s file", l.name)
+	} else if err = json.NewEncoder(f).Encode(&state); err != nil {
+		return errors.WithMessagef(err, "encode(%s)", l.name)
+	} else if err = f.Close(); err != nil {
+		return errors.WithMessagef(err, "closing %s file", l.name)
+	}
+	l.pending = &cp
+
+	return nil
+}
+
+// committed moves the "next" state to "current" if it was persisted with
+// Checkpoint |cp|, which has committed.
+func (l *bufferLog) committed(cp pc.Checkpoint) error {
+	if l.pending == nil || !checkpointsEqual(*l.pending, cp) {
+		return nil
+	} else if err := l.fs.Rename(l.path(".next"), l.path("")); err != nil {
+		return errors.WithMessage(err, "renaming next => current")
+	}
+	l.pending = nil
+
+	return nil
+}
+
+// restore the buffered messages, in persisted order, and the max event time
+// persisted alongside Checkpoint |cp|, which has committed. If no state was
+// ever persisted, restore returns (and persists) an empty buffer.
+func (l *bufferLog) restore(cp pc.Checkpoint) (time.Time, []bufferedMessage, error) {
+	var found bool
+	l.pending = nil
+
+	for _, suffix := range []string{"", ".next"} {
+		var name = l.path(suffix)
+		var state bufferState
+
+		if f, err := l.fs.Open(name); os.IsNotExist(err) {
+			continue
+		} else if err != nil {
+			return time.Time{}, nil, errors.WithMessagef(err, "opening %s file", l.name)
+		} else if err = json.NewDecoder(f).Decode(&state); err != nil {
+			// A "next" file may be partially written, if we faulted
+			// while writing it.
+			_ = f.Close()
+			continue
+		} else if err = f.Close(); err != nil {
+			return time.Time{}, nil, errors.WithMessagef(err, "closing %s file", l.name)
+		}
+		found = true
+
+		var stateCP pc.Checkpoint
+		if err := stateCP.Unmarshal(state.Checkpoint); err != nil {
+			return time.Time{}, nil, errors.WithMessage(err, "unmarshal(checkpoint)")
+		} else if !checkpointsEqual(stateCP, cp) {
+			continue
+		}
+		var buffered, err = l.decode(state)
+		if err != nil {
+			return time.Time{}, nil, err
+		}
+		// A "next" state matching |cp| was committed, but we faulted
+		// before it was moved to "current".
+		if suffix != "" {
+			if err = l.fs.Rename(name, l.path("")); err != nil {
+				return time.Time{}, nil, errors.WithMessage(err, "renaming next => current")
+			}
+		}
+		return state.MaxEventTime, buffered, nil
+	}
+
+	if found {
+		return time.Time{}, nil, errors.Errorf("no persisted %s state matches the restored checkpoint", l.name)
+	}
+	// Persist an empty buffer with |cp| now, so that state matching the
+	// restored Checkpoint is recoverable should the next commit fail.
+	if err := l.persist(cp, time.Time{}, nil); err != nil {
+		return time.Time{}, nil, err
+	}
+	return time.Time{}, nil, l.committed(cp)
+}
+
+// decode the buffered messages of |state|, which are assigned
+// sequence numbers in persisted order.
+func (l *bufferLog) decode(state bufferState) ([]bufferedMessage, error) {
+	var specs = make(map[pb.Journal]*pb.JournalSpec, len(state.Journals))
+	for name, raw := range state.Journals {
+		var spec = new(pb.JournalSpec)
+		if err := spec.Unmarshal(raw); err != nil {
+			return nil, errors.WithMessage(err, "unmarshal(journal spec)")
+		}
+		specs[name] = spec
+	}
+
+	var out []bufferedMessage
+	for _, m := range state.Messages {
+		var spec, ok = specs[m.Journal]
+		if !ok {
+			return nil, errors.Errorf("journal %s of buffered message not found", m.Journal)
+		}
+		framing, err := message.FramingByContentType(spec.LabelSet.ValueOf(labels.ContentType))
+		if err != nil {
+			return nil, errors.WithMessage(err, "determining framing")
+		}
+		msg, err := l.newMsg(spec)
+		if err != nil {
+			return nil, errors.WithMessage(err, "newMessage")
+		}
+		var br = bufio.NewReader(bytes.NewReader(m.Content))
+		if err = framing.NewUnmarshalFunc(br)(msg); err != nil {
+			return nil, errors.WithMessagef(err, "unmarshal(message %s:%d)", m.Journal, m.Begin)
+		}
+
+		out = append(out, bufferedMessage{
+			env: message.Envelope{
+				Journal: spec,
+				Begin:   m.Begin,
+				End:     m.End,
+				Message: msg,
+			},
+			eventTime: m.EventTime,
+			seq:       int64(len(out)),
+		})
+	}
+	return out, nil
+}
+
+// path returns the path of the state file having |suffix|.
+func (l *bufferLog) path(suffix string) string {
+	return filepath.Join(l.dir, l.name+suffix+".json")
+}
+
+// checkpointsEqual returns true if Checkpoints |a| and |b| have equal
+// read-through offsets and producer states. Acknowledgement intents
+// are not compared, as they follow from producer states.
+func checkpointsEqual(a, b pc.Checkpoint) bool {
+	var ao, bo = pc.FlattenReadThrough(a), pc.FlattenReadThrough(b)
+	var ap, bp = pc.FlattenProducerStates(a), pc.FlattenProducerStates(b)
+
+	if len(ao) != len(bo) || len(ap) != len(bp) {
+		return false
+	}
+	for j, o := range ao {
+		if bo[j] != o {
+			return false
+		}
+	}
+	var states = make(map[message.JournalProducer]message.ProducerState, len(ap))
+	for _, s := range ap {
+		states[s.JournalProducer] = s
+	}
+	for _, s := range bp {
+		if states[s.JournalProducer] != s {
+			return false
+		}
+	}
+	return true
+}
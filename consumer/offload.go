@@ -0,0 +1,271 @@
+package consumer
+
+import (
+	"context"
+	"hash/fnv"
+	"time"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"go.gazette.dev/core/allocator"
+	"go.gazette.dev/core/broker/client"
+	pb "go.gazette.dev/core/broker/protocol"
+	pc "go.gazette.dev/core/consumer/protocol"
+	"go.gazette.dev/core/keyspace"
+)
+
+// watchIdle offloads a primary shard once it's made no progress for the
+// ShardSpec's OffloadAfterIdle, and returns. It also returns if the shard is
+// cancelled, or if OffloadAfterIdle is changed to zero.
+func watchIdle(s *shard) {
+	defer s.wg.Done()
+
+	for {
+		var idle = s.Spec().OffloadAfterIdle
+		if idle == 0 {
+			return
+		}
+		s.progress.Lock()
+		var progressCh = s.progress.signalCh
+		s.progress.Unlock()
+
+		select {
+		case <-s.ctx.Done():
+			return
+		case <-progressCh:
+			continue // The shard isn't idle.
+		case <-time.After(idle):
+		}
+
+		var err = offloadShard(s)
+		if err == nil {
+			log.WithField("shard", s.FQN()).Info("offloaded idle shard")
+			return
+		} else if s.ctx.Err() != nil {
+			return
+		}
+		log.WithFields(log.Fields{"err": err, "shard": s.FQN()}).
+			Warn("failed to offload idle shard (will retry)")
+	}
+}
+
+// offloadShard stores recorded FSMHints of the shard, awaits the persistence
+// of its recovery log, and then sets the ShardSpec Offloaded to the shard's
+// read-through offsets in a checked transaction. As the allocator observes
+// the Offloaded ShardSpec, it removes the shard's Assignments.
+func offloadShard(s *shard) error {
+	var readThrough, _ = s.Progress()
+	if len(readThrough) == 0 {
+		// Without source offsets, we couldn't tell when to re-hydrate.
+		return errors.New("shard has no read-through offsets of source journals")
+	}
+
+	if s.recovery.log != "" {
+		var hints, err = s.recovery.recorder.BuildHints()
+		if err == nil {
+			err = storeRecordedHints(s, hints)
+		}
+		if err != nil {
+			return errors.WithMessage(err, "storing recorded hints")
+		}
+		if err = awaitLogPersisted(s); err != nil {
+			return errors.WithMessage(err, "awaiting recovery log persistence")
+		}
+	}
+
+	var ks = s.svc.State.KS
+	ks.Mu.RLock()
+	var item, ok = lookupShardSpec(ks, s.Spec().Id)
+	ks.Mu.RUnlock()
+
+	if !ok {
+		return errors.New("ShardSpec not found")
+	}
+	var spec = *item.Decoded.(allocator.Item).ItemValue.(*pc.ShardSpec)
+	var asn = s.Assignment()
+
+	spec.Offloaded = &pc.Checkpoint{Sources: make(map[pb.Journal]pc.Checkpoint_Source)}
+	for journal, offset := range readThrough {
+		spec.Offloaded.Sources[journal] = pc.Checkpoint_Source{ReadThrough: offset}
+	}
+	// Sources which haven't been read through their MinOffset are offloaded
+	// at their MinOffset, as prior content wouldn't be read anyway.
+	for _, src := range spec.Sources {
+		if cp, ok := spec.Offloaded.Sources[src.Journal]; ok && cp.ReadThrough < src.MinOffset {
+			spec.Offloaded.Sources[src.Journal] = pc.Checkpoint_Source{ReadThrough: src.MinOffset}
+		}
+	}
+
+	var resp, err = s.svc.Etcd.Txn(s.ctx).
+		// Verify the ShardSpec is unchanged, and that we're still primary.
+		If(clientv3.Compare(clientv3.ModRevision(string(item.Raw.Key)), "=", item.Raw.ModRevision),
+			clientv3.Compare(clientv3.CreateRevision(string(asn.Raw.Key)), "=", asn.Raw.CreateRevision)).
+		Then(clientv3.OpPut(string(item.Raw.Key), spec.MarshalString())).
+		Commit()
+
+	if err == nil && !resp.Succeeded {
+		err = errors.Errorf("transaction failed")
+	}
+	return err
+}
+
+// awaitLogPersisted flushes the shard's recovery log, and awaits the
+// persistence of its current content to the log's fragment stores. It's
+// a no-op if the recovery log has no fragment stores.
+func awaitLogPersisted(s *shard) error {
+	var spec, err = client.GetJournal(s.ctx, s.ajc, s.recovery.log)
+	if err != nil {
+		return err
+	} else if len(spec.Fragment.Stores) == 0 {
+		return nil
+	}
+
+	head, err := client.GetHead(s.ctx, s.ajc, s.recovery.log)
+	if err != nil || head == 0 {
+		return err
+	}
+	return client.AwaitPersisted(s.ctx, s.ajc,
+		pb.Fragment{Journal: s.recovery.log, Begin: head - 1, End: head}, true)
+}
+
+// watchOffloads re-hydrates offloaded shards of which the Service's Member
+// is the offload owner (see offloadOwner), as soon as one of their source
+// journals is written beyond its offloaded offset. It returns when |ctx|
+// is cancelled.
+func watchOffloads(ctx context.Context, svc *Service) error {
+	type watch struct {
+		revision int64
+		cancel   context.CancelFunc
+	}
+	var ks = svc.State.KS
+	var watches = make(map[string]watch) // Keyed on ShardSpec key.
+
+	defer func() {
+		for _, w := range watches {
+			w.cancel()
+		}
+	}()
+
+	ks.Mu.RLock()
+	defer ks.Mu.RUnlock()
+
+	for {
+		var offloaded = make(map[string]struct{})
+
+		for _, kv := range svc.State.Items {
+			var spec = kv.Decoded.(allocator.Item).ItemValue.(*pc.ShardSpec)
+			var key = string(kv.Raw.Key)
+
+			if spec.Offloaded == nil || offloadOwner(svc.State.Members, spec.Id) != svc.State.LocalKey {
+				continue
+			}
+			offloaded[key] = struct{}{}
+
+			if w, ok := watches[key]; ok && w.revision == kv.Raw.ModRevision {
+				continue
+			} else if ok {
+				w.cancel()
+			}
+			var wctx, cancel = context.WithCancel(ctx)
+			watches[key] = watch{revision: kv.Raw.ModRevision, cancel: cancel}
+
+			go rehydrateOnWrite(wctx, svc, kv)
+		}
+		for key, w := range watches {
+			if _, ok := offloaded[key]; !ok {
+				w.cancel()
+				delete(watches, key)
+			}
+		}
+
+		if err := ks.WaitForRevision(ctx, ks.Header.Revision+1); err == context.Canceled {
+			return nil
+		} else if err != nil {
+			return err
+		}
+	}
+}
+
+// rehydrateOnWrite awaits a write to any source journal of the offloaded
+// ShardSpec |kv| beyond its offloaded offset, and then clears the ShardSpec
+// Offloaded in a checked transaction.
+func rehydrateOnWrite(ctx context.Context, svc *Service, kv keyspace.KeyValue) {
+	var spec = *kv.Decoded.(allocator.Item).ItemValue.(*pc.ShardSpec)
+	var writeCh = make(chan error, len(spec.Offloaded.Sources))
+
+	for journal, source := range spec.Offloaded.Sources {
+		go func(journal pb.Journal, offset pb.Offset) {
+			var _, err = client.AwaitOffset(ctx, svc.Journals, journal, offset+1)
+			if err != nil {
+				err = errors.WithMessagef(err, "awaiting write of %s", journal)
+			}
+			writeCh <- err
+		}(journal, source.ReadThrough)
+	}
+
+	var written bool
+	for remaining := len(spec.Offloaded.Sources); remaining != 0 && !written; remaining-- {
+		if err := <-writeCh; err == nil {
+			written = true
+		} else if ctx.Err() == nil {
+			log.WithFields(log.Fields{"err": err, "shard": spec.Id}).
+				Warn("failed to await source journal of offloaded shard")
+		}
+	}
+	if !written {
+		return
+	}
+	spec.Offloaded = nil
+
+	for attempt := 0; true; attempt++ {
+		var resp, err = svc.Etcd.Txn(ctx).
+			If(clientv3.Compare(clientv3.ModRevision(string(kv.Raw.Key)), "=", kv.Raw.ModRevision)).
+			Then(clientv3.OpPut(string(kv.Raw.Key), spec.MarshalString())).
+			Commit()
+
+		if err == nil {
+			if resp.Succeeded {
+				log.WithField("shard", spec.Id).Info("re-hydrated offloaded shard")
+			}
+			return // If the ShardSpec changed, watchOffloads will re-evaluate it.
+		}
+		log.WithFields(log.Fields{"err": err, "shard": spec.Id, "attempt": attempt}).
+			Warn("failed to re-hydrate offloaded shard (will retry)")
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff(attempt)):
+		}
+	}
+}
+
+// offloadOwner returns the key of the Member which re-hydrates the offloaded
+// shard |id|. Owners are chosen by rendezvous hashing of shard IDs and Member
+// keys, so that each offloaded shard is watched by a single Member, and few
+// shards change owners as Members come and go.
+func offloadOwner(members keyspace.KeyValues, id pc.ShardID) string {
+	var owner string
+	var max uint64
+
+	for _, kv := range members {
+		var h = fnv.New64a()
+		_, _ = h.Write(kv.Raw.Key)
+		_, _ = h.Write([]byte(id))
+
+		if sum := h.Sum64(); owner == "" || sum > max {
+			owner, max = string(kv.Raw.Key), sum
+		}
+	}
+	return owner
+}
+
+// lookupShardSpec returns the KeyValue of the ShardSpec |id|.
+// The KeySpace must be read-locked.
+func lookupShardSpec(ks *keyspace.KeySpace, id pc.ShardID) (keyspace.KeyValue, bool) {
+	if ind, ok := ks.KeyValues.Search(allocator.ItemKey(ks, id.String())); ok {
+		return ks.KeyValues[ind], true
+	}
+	return keyspace.KeyValue{}, false
+}
@@ -0,0 +1,111 @@
+package consumer
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+	"go.gazette.dev/core/allocator"
+	"go.gazette.dev/core/broker/client"
+	pb "go.gazette.dev/core/broker/protocol"
+	pc "go.gazette.dev/core/consumer/protocol"
+)
+
+func TestIdleShardIsOffloadedAndRehydrated(t *testing.T) {
+	var tf, cleanup = newTestFixture(t)
+	defer cleanup()
+
+	var spec = makeShard(shardA)
+	spec.OffloadAfterIdle = 10 * time.Millisecond
+	tf.allocateShard(spec, localID)
+
+	var res, err = tf.resolver.Resolve(ResolveArgs{Context: context.Background(), ShardID: shardA})
+	require.NoError(t, err)
+	var shard = res.Shard
+	res.Done()
+
+	// Expect the idle shard offloads at its read-through offsets,
+	// which are bounded from below by source MinOffsets.
+	var offloaded = awaitShardSpec(t, tf, func(spec *pc.ShardSpec) bool { return spec.Offloaded != nil })
+	require.Equal(t, &pc.Checkpoint{Sources: map[pb.Journal]pc.Checkpoint_Source{
+		sourceA.Name: {ReadThrough: int64(len(sourceAWriteFixture))},
+		sourceB.Name: {ReadThrough: 0},
+	}}, offloaded.Offloaded)
+
+	require.NoError(t, testutil.CollectAndCompare(tf.resolver, strings.NewReader(`
+		# HELP gazette_shards_offloaded Number of shards which are offloaded.
+		# TYPE gazette_shards_offloaded gauge
+		gazette_shards_offloaded 1
+	`), "gazette_shards_offloaded"))
+
+	// Mimic the allocator, which removes assignments of the offloaded shard.
+	tf.allocateShard(offloaded)
+	<-shard.Context().Done()
+
+	// A write to a source lands before re-hydration is watched for,
+	// as it would if it raced the shard's offload. It isn't missed.
+	var a = client.NewAppender(context.Background(), tf.broker.Client(), pb.AppendRequest{Journal: sourceB.Name})
+	_, _ = a.Write([]byte("{}\n"))
+	require.NoError(t, a.Close())
+
+	tf.ks.Mu.RLock()
+	require.Equal(t, tf.state.LocalKey, offloadOwner(tf.state.Members, shardA))
+	tf.ks.Mu.RUnlock()
+
+	var ctx, cancel = context.WithCancel(context.Background())
+	var doneCh = make(chan error)
+	go func() { doneCh <- watchOffloads(ctx, tf.service) }()
+
+	// Expect the shard is re-hydrated.
+	var rehydrated = awaitShardSpec(t, tf, func(spec *pc.ShardSpec) bool { return spec.Offloaded == nil })
+	require.Equal(t, spec.OffloadAfterIdle, rehydrated.OffloadAfterIdle)
+
+	cancel()
+	require.NoError(t, <-doneCh)
+}
+
+func TestOffloadOwnerIsStableAcrossMembers(t *testing.T) {
+	var tf, cleanup = newTestFixture(t)
+	defer cleanup()
+
+	tf.allocateShard(makeShard(shardA)) // Creates local & remote Members.
+
+	tf.ks.Mu.RLock()
+	defer tf.ks.Mu.RUnlock()
+
+	var members = tf.state.Members
+	require.Len(t, members, 2)
+
+	for _, id := range []pc.ShardID{shardA, shardB, shardC} {
+		var owner = offloadOwner(members, id)
+		require.Contains(t, []string{string(members[0].Raw.Key), string(members[1].Raw.Key)}, owner)
+		require.Equal(t, owner, offloadOwner(members, id))
+
+		// Removing a non-owner doesn't change the owner.
+		for i := range members {
+			if string(members[i].Raw.Key) != owner {
+				require.Equal(t, owner, offloadOwner(append(members[:i:i], members[i+1:]...), id))
+			}
+		}
+	}
+	require.Equal(t, "", offloadOwner(nil, shardA))
+}
+
+// awaitShardSpec waits for the fixture's ShardSpec of shardA to pass |fn|.
+func awaitShardSpec(t *testing.T, tf *testFixture, fn func(*pc.ShardSpec) bool) *pc.ShardSpec {
+	tf.ks.Mu.RLock()
+	defer tf.ks.Mu.RUnlock()
+
+	for {
+		var item, ok = allocator.LookupItem(tf.ks, shardA)
+		require.True(t, ok)
+
+		if spec := item.ItemValue.(*pc.ShardSpec); fn(spec) {
+			return spec
+		}
+		require.NoError(t, tf.ks.WaitForRevision(tf.tasks.Context(), tf.ks.Header.Revision+1))
+	}
+}
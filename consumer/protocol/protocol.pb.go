@@ -4,6 +4,7 @@
 package protocol
 
 import (
+	bytes "bytes"
 	context "context"
 	encoding_binary "encoding/binary"
 	fmt "fmt"
@@ -90,6 +91,41 @@ func (Status) EnumDescriptor() ([]byte, []int) {
 	return fileDescriptor_6491fb50a1cefedd, []int{0}
 }
 
+// StartPolicy determines the offsets from which a shard first reads its
+// source journals.
+type ShardSpec_StartPolicy int32
+
+const (
+	// Sources are read from their MinOffset (eg, from the beginning of
+	// the journal).
+	ShardSpec_START_AT_MIN_OFFSET ShardSpec_StartPolicy = 0
+	// Sources are read from their write heads, as of the shard's first
+	// assignment. Content written prior is skipped.
+	ShardSpec_START_AT_HEAD ShardSpec_StartPolicy = 1
+	// Sources are read from offsets written at approximately |start_time|.
+	ShardSpec_START_AT_TIME ShardSpec_StartPolicy = 2
+)
+
+var ShardSpec_StartPolicy_name = map[int32]string{
+	0: "START_AT_MIN_OFFSET",
+	1: "START_AT_HEAD",
+	2: "START_AT_TIME",
+}
+
+var ShardSpec_StartPolicy_value = map[string]int32{
+	"START_AT_MIN_OFFSET": 0,
+	"START_AT_HEAD":       1,
+	"START_AT_TIME":       2,
+}
+
+func (x ShardSpec_StartPolicy) String() string {
+	return proto.EnumName(ShardSpec_StartPolicy_name, int32(x))
+}
+
+func (ShardSpec_StartPolicy) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_6491fb50a1cefedd, []int{0, 0}
+}
+
 type ReplicaStatus_Code int32
 
 const (
@@ -249,6 +285,69 @@ type ShardSpec struct {
 	// MessageProducer implementations.
 	// If zero, a reasonable default (currently 8192) is used.
 	ReadChannelSize uint32 `protobuf:"varint,13,opt,name=read_channel_size,json=readChannelSize,proto3" json:"read_channel_size,omitempty" yaml:"read_channel_size,omitempty"`
+	// Policy by which the shard begins to read its source journals, upon its
+	// first assignment. The policy applies only to a shard which hasn't yet
+	// recorded a Checkpoint of read source offsets. A recovered shard always
+	// reads from its Checkpoint offsets, regardless of policy. In all cases,
+	// reads begin no earlier than the Source |min_offset|.
+	StartPolicy ShardSpec_StartPolicy `protobuf:"varint,14,opt,name=start_policy,json=startPolicy,proto3,enum=consumer.ShardSpec_StartPolicy" json:"start_policy,omitempty" yaml:"start_policy,omitempty"`
+	// Time, in Unix seconds, from which sources are read under the START_AT_TIME
+	// policy. It's resolved to offsets at assignment, from the modification times
+	// of persisted journal Fragments.
+	StartTime int64 `protobuf:"varint,15,opt,name=start_time,json=startTime,proto3" json:"start_time,omitempty" yaml:"start_time,omitempty"`
+	// Retention of recovery log fragments which are no longer required for
+	// playback of the shard's store, as determined from its primary and backup
+	// FSMHints. When pruning the recovery log, such fragments are discarded only
+	// if they were persisted longer than |recovery_log_retention| ago. Fragments
+	// which remain required by any FSMHints are never discarded, regardless of
+	// retention. If zero, all fragments which are no longer required are
+	// discarded.
+	//
+	// Shards having large stores which regularly compact may use a small retention
+	// to bound the size of their logs, while shards having small stores may use a
+	// large retention to preserve a full history of their logs.
+	RecoveryLogRetention time.Duration `protobuf:"bytes,16,opt,name=recovery_log_retention,json=recoveryLogRetention,proto3,stdduration" json:"recovery_log_retention" yaml:"recovery_log_retention,omitempty"`
+	// Fixed number of messages processed by each shard transaction. If non-zero,
+	// the shard processes in fixed batches rather than adaptively: a transaction
+	// consumes messages until it has consumed |txn_batch_messages| (or
+	// |txn_batch_bytes|), regardless of |min_txn_duration| or whether further
+	// messages are ready, and then commits. Given the same input, the shard's
+	// transactions (and its recovery log) are then the same on every run, which
+	// is useful for reproducible processing and golden tests. A partial batch at
+	// the end of available input is committed once |max_txn_duration| elapses,
+	// which should be long enough to process a full batch. Consumed messages
+	// include acknowledgements of source journal transactions.
+	TxnBatchMessages uint32 `protobuf:"varint,17,opt,name=txn_batch_messages,json=txnBatchMessages,proto3" json:"txn_batch_messages,omitempty" yaml:"txn_batch_messages,omitempty"`
+	// Fixed number of message bytes processed by each shard transaction. If
+	// non-zero, the shard processes in fixed batches (see |txn_batch_messages|),
+	// and a transaction commits once its consumed messages total at least
+	// |txn_batch_bytes|. If both are set, a transaction commits upon reaching
+	// either.
+	TxnBatchBytes int64 `protobuf:"varint,18,opt,name=txn_batch_bytes,json=txnBatchBytes,proto3" json:"txn_batch_bytes,omitempty" yaml:"txn_batch_bytes,omitempty"`
+	// Duration after which a primary shard which hasn't processed any messages
+	// is offloaded. If non-zero, a shard which is idle for |offload_after_idle|
+	// stores its recovery log hints, awaits the persistence of its recovery log
+	// to its fragment stores (if any), and then sets |offloaded| to the read-through
+	// offsets of its source journals, which releases all of its assignments.
+	// An offloaded shard is re-hydrated (|offloaded| is cleared and the shard is
+	// again assigned) as soon as any of its source journals is written beyond
+	// its offloaded offset. Messages written while the shard is being offloaded
+	// aren't lost, as they lie beyond the offloaded offsets and are read upon
+	// re-hydration.
+	OffloadAfterIdle time.Duration `protobuf:"bytes,19,opt,name=offload_after_idle,json=offloadAfterIdle,proto3,stdduration" json:"offload_after_idle" yaml:"offload_after_idle,omitempty"`
+	// If set, the shard is offloaded (see |offload_after_idle|) and has no
+	// assignments. Its Sources hold the offsets through which each source
+	// journal was read at the time of offload. |offloaded| is set and cleared by
+	// consumers, and should generally not be set by users, though clearing it
+	// re-hydrates the shard.
+	Offloaded *Checkpoint `protobuf:"bytes,20,opt,name=offloaded,proto3" json:"offloaded,omitempty" yaml:"offloaded,omitempty"`
+	// If set, the shard's next primary resumes reading each source journal of
+	// |reset_checkpoint| from its read_through offset, rather than from the
+	// Checkpoint restored from its store. The primary commits the reset
+	// Checkpoint to its store, and then clears |reset_checkpoint|.
+	// |reset_checkpoint| is set by the ResetCheckpoint RPC, and should generally
+	// not be set by users (see ResetCheckpointRequest).
+	ResetCheckpoint *Checkpoint `protobuf:"bytes,21,opt,name=reset_checkpoint,json=resetCheckpoint,proto3" json:"reset_checkpoint,omitempty" yaml:"reset_checkpoint,omitempty"`
 }
 
 func (m *ShardSpec) Reset()         { *m = ShardSpec{} }
@@ -395,6 +494,10 @@ type ReplicaStatus struct {
 	Code ReplicaStatus_Code `protobuf:"varint,1,opt,name=code,proto3,enum=consumer.ReplicaStatus_Code" json:"code,omitempty"`
 	// Errors encountered during replica processing. Set iff |code| is FAILED.
 	Errors []string `protobuf:"bytes,2,rep,name=errors,proto3" json:"errors,omitempty"`
+	// Most recent failed health check of an unhealthy PRIMARY replica, or empty
+	// if the replica is healthy. Set only if |code| is PRIMARY.
+	// See consumer.HealthChecker.
+	Unhealthy string `protobuf:"bytes,3,opt,name=unhealthy,proto3" json:"unhealthy,omitempty"`
 }
 
 func (m *ReplicaStatus) Reset()         { *m = ReplicaStatus{} }
@@ -865,6 +968,9 @@ type StatRequest struct {
 	// transaction before Stat returns, blocking if required. Offsets of journals
 	// not read by this shard are ignored.
 	ReadThrough map[go_gazette_dev_core_broker_protocol.Journal]go_gazette_dev_core_broker_protocol.Offset `protobuf:"bytes,3,rep,name=read_through,json=readThrough,proto3,castkey=go.gazette.dev/core/broker/protocol.Journal,castvalue=go.gazette.dev/core/broker/protocol.Offset" json:"read_through,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"varint,2,opt,name=value,proto3"`
+	// Include the Checkpoint of the most recent completed consumer
+	// transaction in the response.
+	IncludeCheckpoint bool `protobuf:"varint,4,opt,name=include_checkpoint,json=includeCheckpoint,proto3" json:"include_checkpoint,omitempty"`
 	// Optional extension of the StatRequest.
 	Extension []byte `protobuf:"bytes,100,opt,name=extension,proto3" json:"extension,omitempty"`
 }
@@ -922,6 +1028,10 @@ type StatResponse struct {
 	// through multiple intermediate consumers and arbitrary transformations
 	// before arriving at the materialized view which is ultimately queried.
 	PublishAt map[go_gazette_dev_core_broker_protocol.Journal]go_gazette_dev_core_broker_protocol.Offset `protobuf:"bytes,4,rep,name=publish_at,json=publishAt,proto3,castkey=go.gazette.dev/core/broker/protocol.Journal,castvalue=go.gazette.dev/core/broker/protocol.Offset" json:"publish_at,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"varint,2,opt,name=value,proto3"`
+	// Checkpoint of the most recent completed consumer transaction,
+	// or of the shard's recovery if it's yet to complete a transaction.
+	// Set only if StatRequest.include_checkpoint.
+	Checkpoint *Checkpoint `protobuf:"bytes,5,opt,name=checkpoint,proto3" json:"checkpoint,omitempty"`
 	// Optional extension of the StatResponse.
 	Extension []byte `protobuf:"bytes,100,opt,name=extension,proto3" json:"extension,omitempty"`
 }
@@ -1168,9 +1278,102 @@ func (m *UnassignResponse) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_UnassignResponse proto.InternalMessageInfo
 
+// ResetCheckpointRequest is the request message of the ResetCheckpoint RPC.
+type ResetCheckpointRequest struct {
+	// Shard to reset.
+	Shard ShardID `protobuf:"bytes,1,opt,name=shard,proto3,casttype=ShardID" json:"shard,omitempty"`
+	// Source journals of the shard, and offsets from which they're to be read.
+	// Source journals not included continue to be read from their current
+	// Checkpoint.
+	ReadThrough map[go_gazette_dev_core_broker_protocol.Journal]go_gazette_dev_core_broker_protocol.Offset `protobuf:"bytes,2,rep,name=read_through,json=readThrough,proto3,castkey=go.gazette.dev/core/broker/protocol.Journal,castvalue=go.gazette.dev/core/broker/protocol.Offset" json:"read_through,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"varint,2,opt,name=value,proto3"`
+	// Resetting a Checkpoint doesn't reset the shard's store, which continues
+	// to reflect messages processed through its prior Checkpoint. Messages
+	// before the prior Checkpoint are processed again, and applied to the store
+	// a second time, while messages which are skipped are never applied. The
+	// application must tolerate this divergence of its store, and a reset fails
+	// unless |allow_store_divergence| acknowledges it.
+	AllowStoreDivergence bool `protobuf:"varint,3,opt,name=allow_store_divergence,json=allowStoreDivergence,proto3" json:"allow_store_divergence,omitempty"`
+}
+
+func (m *ResetCheckpointRequest) Reset()         { *m = ResetCheckpointRequest{} }
+func (m *ResetCheckpointRequest) String() string { return proto.CompactTextString(m) }
+func (*ResetCheckpointRequest) ProtoMessage()    {}
+func (*ResetCheckpointRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_6491fb50a1cefedd, []int{14}
+}
+func (m *ResetCheckpointRequest) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *ResetCheckpointRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_ResetCheckpointRequest.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *ResetCheckpointRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ResetCheckpointRequest.Merge(m, src)
+}
+func (m *ResetCheckpointRequest) XXX_Size() int {
+	return m.ProtoSize()
+}
+func (m *ResetCheckpointRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ResetCheckpointRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ResetCheckpointRequest proto.InternalMessageInfo
+
+// ResetCheckpointResponse is the response message of the ResetCheckpoint RPC.
+type ResetCheckpointResponse struct {
+	// Status of the ResetCheckpoint RPC.
+	Status Status `protobuf:"varint,1,opt,name=status,proto3,enum=consumer.Status" json:"status,omitempty"`
+	// Header of the response.
+	Header protocol.Header `protobuf:"bytes,2,opt,name=header,proto3" json:"header"`
+}
+
+func (m *ResetCheckpointResponse) Reset()         { *m = ResetCheckpointResponse{} }
+func (m *ResetCheckpointResponse) String() string { return proto.CompactTextString(m) }
+func (*ResetCheckpointResponse) ProtoMessage()    {}
+func (*ResetCheckpointResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_6491fb50a1cefedd, []int{15}
+}
+func (m *ResetCheckpointResponse) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *ResetCheckpointResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_ResetCheckpointResponse.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *ResetCheckpointResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ResetCheckpointResponse.Merge(m, src)
+}
+func (m *ResetCheckpointResponse) XXX_Size() int {
+	return m.ProtoSize()
+}
+func (m *ResetCheckpointResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ResetCheckpointResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ResetCheckpointResponse proto.InternalMessageInfo
+
 func init() {
 	proto.RegisterEnum("consumer.Status", Status_name, Status_value)
 	golang_proto.RegisterEnum("consumer.Status", Status_name, Status_value)
+	proto.RegisterEnum("consumer.ShardSpec_StartPolicy", ShardSpec_StartPolicy_name, ShardSpec_StartPolicy_value)
+	golang_proto.RegisterEnum("consumer.ShardSpec_StartPolicy", ShardSpec_StartPolicy_name, ShardSpec_StartPolicy_value)
 	proto.RegisterEnum("consumer.ReplicaStatus_Code", ReplicaStatus_Code_name, ReplicaStatus_Code_value)
 	golang_proto.RegisterEnum("consumer.ReplicaStatus_Code", ReplicaStatus_Code_name, ReplicaStatus_Code_value)
 	proto.RegisterType((*ShardSpec)(nil), "consumer.ShardSpec")
@@ -1225,6 +1428,12 @@ func init() {
 	golang_proto.RegisterType((*UnassignRequest)(nil), "consumer.UnassignRequest")
 	proto.RegisterType((*UnassignResponse)(nil), "consumer.UnassignResponse")
 	golang_proto.RegisterType((*UnassignResponse)(nil), "consumer.UnassignResponse")
+	proto.RegisterType((*ResetCheckpointRequest)(nil), "consumer.ResetCheckpointRequest")
+	golang_proto.RegisterType((*ResetCheckpointRequest)(nil), "consumer.ResetCheckpointRequest")
+	proto.RegisterMapType((map[go_gazette_dev_core_broker_protocol.Journal]go_gazette_dev_core_broker_protocol.Offset)(nil), "consumer.ResetCheckpointRequest.ReadThroughEntry")
+	golang_proto.RegisterMapType((map[go_gazette_dev_core_broker_protocol.Journal]go_gazette_dev_core_broker_protocol.Offset)(nil), "consumer.ResetCheckpointRequest.ReadThroughEntry")
+	proto.RegisterType((*ResetCheckpointResponse)(nil), "consumer.ResetCheckpointResponse")
+	golang_proto.RegisterType((*ResetCheckpointResponse)(nil), "consumer.ResetCheckpointResponse")
 }
 
 func init() { proto.RegisterFile("consumer/protocol/protocol.proto", fileDescriptor_6491fb50a1cefedd) }
@@ -1233,130 +1442,157 @@ func init() {
 }
 
 var fileDescriptor_6491fb50a1cefedd = []byte{
-	// 1964 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xc4, 0x58, 0x4d, 0x6c, 0x1b, 0xc7,
-	0x15, 0xd6, 0xf2, 0x4f, 0xe4, 0x23, 0x25, 0x51, 0x63, 0x3b, 0xa6, 0x69, 0x87, 0x94, 0x18, 0xcb,
-	0x65, 0xfe, 0x56, 0xa9, 0x82, 0x00, 0xa9, 0x91, 0x04, 0x25, 0x45, 0xc9, 0x56, 0xa3, 0xbf, 0x2e,
-	0x15, 0xa4, 0x09, 0x50, 0x2c, 0x96, 0xbb, 0x23, 0x6a, 0xab, 0xe5, 0xce, 0x76, 0x77, 0xa8, 0x8a,
-	0x3e, 0x1a, 0x28, 0x0a, 0xa4, 0x97, 0xdc, 0xda, 0x63, 0xd0, 0x5e, 0x5a, 0xa0, 0xd7, 0xf6, 0x56,
-	0xa0, 0xb7, 0xfa, 0xe8, 0x53, 0xd1, 0x13, 0x8d, 0x46, 0x97, 0x1e, 0x0b, 0xf5, 0x52, 0xf8, 0x54,
-	0xcc, 0xcf, 0x72, 0x97, 0x34, 0xa5, 0x54, 0x05, 0xdc, 0x5e, 0x84, 0xd1, 0x7b, 0xdf, 0xfb, 0xde,
-	0xcf, 0xbc, 0x79, 0x33, 0x4b, 0x58, 0x32, 0x89, 0x1b, 0xf4, 0x7b, 0xd8, 0x5f, 0xf5, 0x7c, 0x42,
-	0x89, 0x49, 0x9c, 0xd1, 0x42, 0xe5, 0x0b, 0x94, 0x0d, 0x11, 0xe5, 0x4a, 0xc7, 0x27, 0xc7, 0x17,
-	0x23, 0xcb, 0xf7, 0x46, 0x5c, 0x3e, 0x36, 0xc9, 0x09, 0xf6, 0x07, 0x0e, 0xe9, 0xf2, 0xb5, 0x6f,
-	0x61, 0x4b, 0x27, 0x9e, 0xc4, 0x5d, 0xef, 0x92, 0x2e, 0xe1, 0xcb, 0x55, 0xb6, 0x92, 0xd2, 0x4a,
-	0x97, 0x90, 0xae, 0x83, 0x05, 0x69, 0xa7, 0x7f, 0xb8, 0x6a, 0xf5, 0x7d, 0x83, 0xda, 0xc4, 0x15,
-	0xfa, 0xda, 0x3f, 0x73, 0x90, 0x6b, 0x1f, 0x19, 0xbe, 0xd5, 0xf6, 0xb0, 0x89, 0xde, 0x81, 0x84,
-	0x6d, 0x95, 0x94, 0x25, 0xa5, 0x9e, 0x6b, 0x2e, 0x9d, 0x0f, 0xab, 0x8b, 0x03, 0xa3, 0xe7, 0xdc,
-	0xaf, 0xbd, 0x45, 0x7a, 0x36, 0xc5, 0x3d, 0x8f, 0x0e, 0x6a, 0xcf, 0x87, 0xd5, 0x59, 0x8e, 0xdf,
-	0x6a, 0x69, 0x09, 0xdb, 0x42, 0x7b, 0x30, 0x1b, 0x90, 0xbe, 0x6f, 0xe2, 0xa0, 0x94, 0x58, 0x4a,
-	0xd6, 0xf3, 0x6b, 0x65, 0x35, 0x8c, 0x57, 0x1d, 0xf1, 0xaa, 0x6d, 0x0e, 0x69, 0xde, 0x7a, 0x32,
-	0xac, 0xce, 0x4c, 0xa5, 0xd5, 0x42, 0x16, 0xf4, 0x03, 0xb8, 0x16, 0xe6, 0xa9, 0x3b, 0xa4, 0xab,
-	0x7b, 0x3e, 0x3e, 0xb4, 0x4f, 0x4b, 0x49, 0x1e, 0x53, 0xfd, 0x7c, 0x58, 0xbd, 0x2b, 0x8c, 0xa7,
-	0x80, 0xe2, 0x7c, 0x8b, 0xa1, 0x7e, 0x9b, 0x74, 0xf7, 0xb9, 0x16, 0x35, 0x20, 0x7f, 0x64, 0xbb,
-	0x34, 0x64, 0x4c, 0x8d, 0xb2, 0xbc, 0x23, 0x18, 0x63, 0xca, 0x38, 0x13, 0x30, 0xb9, 0xa4, 0x68,
-	0x41, 0x81, 0xa3, 0x3a, 0x86, 0x79, 0xdc, 0xf7, 0x82, 0x52, 0x7a, 0x49, 0xa9, 0xa7, 0x9b, 0xcb,
-	0xe7, 0xc3, 0xea, 0xab, 0x31, 0x0e, 0xa9, 0x8d, 0x93, 0x70, 0xcf, 0x4d, 0x21, 0x47, 0x3e, 0x14,
-	0x7b, 0xc6, 0xa9, 0x4e, 0x4f, 0x5d, 0x3d, 0xdc, 0x8d, 0x52, 0x66, 0x49, 0xa9, 0xe7, 0xd7, 0x6e,
-	0xa9, 0x62, 0xbb, 0xd4, 0x70, 0xbb, 0xd4, 0x96, 0x04, 0x34, 0xdf, 0x96, 0xb5, 0x5b, 0x16, 0x8e,
-	0x26, 0x09, 0x62, 0xce, 0x7e, 0xf9, 0xac, 0xaa, 0x68, 0xf3, 0x3d, 0xe3, 0xf4, 0xe0, 0xd4, 0x0d,
-	0xcd, 0xb9, 0x4f, 0xdb, 0x1d, 0xf7, 0x39, 0x7b, 0x55, 0x9f, 0x13, 0x04, 0x2f, 0xfa, 0xb4, 0xdd,
-	0xb8, 0xcf, 0x55, 0x98, 0xb5, 0xec, 0xc0, 0xe8, 0x38, 0xb8, 0x94, 0x5d, 0x52, 0xea, 0xd9, 0xe6,
-	0x8d, 0x0b, 0xf6, 0x5e, 0xa2, 0x78, 0x79, 0x09, 0xd5, 0x03, 0x6a, 0xb8, 0x56, 0x67, 0x10, 0x94,
-	0x72, 0x4b, 0x4a, 0x7d, 0x6e, 0xac, 0xbc, 0x31, 0xed, 0x78, 0x79, 0x09, 0x6d, 0x4b, 0x39, 0xda,
-	0x87, 0x8c, 0x63, 0x74, 0xb0, 0x13, 0x94, 0x80, 0x27, 0x88, 0xd4, 0xd1, 0x89, 0xda, 0x66, 0xf2,
-	0x36, 0xa6, 0xcd, 0xbb, 0x2c, 0xb3, 0xa7, 0xc3, 0xaa, 0x72, 0x3e, 0xac, 0x96, 0x26, 0x23, 0x7a,
-	0xcb, 0x76, 0x1d, 0xdb, 0xc5, 0x35, 0x4d, 0xf2, 0xa0, 0xcf, 0xe1, 0xba, 0x0c, 0x51, 0xff, 0x89,
-	0x61, 0x53, 0xfd, 0x90, 0xf8, 0xba, 0x61, 0x1e, 0x97, 0xf2, 0x3c, 0xab, 0xd7, 0xcf, 0x87, 0xd5,
-	0x15, 0xc1, 0x31, 0x0d, 0x35, 0xd6, 0x95, 0x12, 0xf0, 0xa9, 0x61, 0xd3, 0x4d, 0xe2, 0x37, 0xcc,
-	0x63, 0xb4, 0x07, 0x45, 0xdf, 0x76, 0xbb, 0x7a, 0xa7, 0x7f, 0x78, 0x88, 0x7d, 0x3d, 0xb0, 0x1f,
-	0xe1, 0x52, 0x81, 0xe7, 0xbd, 0x12, 0x55, 0x7e, 0x12, 0x11, 0xe7, 0x9c, 0x67, 0xca, 0x26, 0xd7,
-	0xb5, 0xed, 0x47, 0x18, 0x69, 0xb0, 0xe8, 0x63, 0xc3, 0xd2, 0xcd, 0x23, 0xc3, 0x75, 0xb1, 0x23,
-	0x18, 0xe7, 0x38, 0xe3, 0xbd, 0xf3, 0x61, 0xb5, 0x16, 0x1e, 0x9f, 0x09, 0x48, 0x9c, 0x72, 0x81,
-	0x69, 0xd7, 0x85, 0x92, 0x71, 0x96, 0xff, 0xac, 0x40, 0x46, 0x9c, 0x61, 0xb4, 0x05, 0xb3, 0x3f,
-	0x22, 0x7d, 0xdf, 0x35, 0x1c, 0x39, 0x27, 0x56, 0x9f, 0x0f, 0xab, 0x6f, 0x76, 0x89, 0xda, 0x35,
-	0x1e, 0x61, 0x4a, 0xb1, 0x6a, 0xe1, 0x93, 0x55, 0x93, 0xf8, 0x78, 0x75, 0x62, 0xae, 0xa9, 0xdf,
-	0x13, 0x66, 0x5a, 0x68, 0x8f, 0x1c, 0x00, 0xd6, 0x52, 0xe4, 0xf0, 0x30, 0xc0, 0x94, 0x9f, 0xf0,
-	0x64, 0x73, 0xe7, 0x7c, 0x58, 0xbd, 0x1d, 0xb5, 0x9b, 0xd0, 0x8d, 0xcf, 0x9f, 0x37, 0xfe, 0x13,
-	0x67, 0x7b, 0xdc, 0x50, 0xcb, 0xf5, 0x6c, 0x57, 0x2c, 0xef, 0xa7, 0xfe, 0xfe, 0x55, 0x55, 0x11,
-	0x7f, 0x6b, 0x3f, 0x55, 0xa0, 0xb0, 0x2e, 0xc7, 0x14, 0x1f, 0x7c, 0x07, 0x50, 0xf0, 0x7c, 0x62,
-	0xe2, 0x20, 0xd0, 0x03, 0x0f, 0x9b, 0x3c, 0xb5, 0xfc, 0xda, 0x8d, 0xa8, 0x73, 0xf6, 0x85, 0x96,
-	0x81, 0x9b, 0xe5, 0x58, 0xf3, 0xcc, 0xcb, 0xe6, 0x09, 0x5b, 0x26, 0xef, 0x45, 0x40, 0x54, 0x85,
-	0x7c, 0xc0, 0x66, 0xa0, 0xee, 0xd8, 0x3d, 0x9b, 0x96, 0x12, 0x6c, 0x13, 0x34, 0xe0, 0xa2, 0x6d,
-	0x26, 0xa9, 0xfd, 0x4a, 0x81, 0x39, 0x0d, 0x7b, 0x8e, 0x6d, 0x1a, 0x6d, 0x6a, 0xd0, 0x7e, 0x80,
-	0xde, 0x81, 0x94, 0x49, 0x2c, 0xcc, 0x03, 0x98, 0x5f, 0xbb, 0x13, 0x0d, 0xd3, 0x31, 0x98, 0xba,
-	0x4e, 0x2c, 0xac, 0x71, 0x24, 0x7a, 0x05, 0x32, 0xd8, 0xf7, 0x89, 0x2f, 0x06, 0x70, 0x4e, 0x93,
-	0xff, 0xd5, 0x1e, 0x40, 0x8a, 0xa1, 0x50, 0x16, 0x52, 0x5b, 0xad, 0xed, 0x8d, 0xe2, 0x0c, 0x2a,
-	0x40, 0xb6, 0xd9, 0x58, 0xff, 0x78, 0x73, 0x6b, 0x7b, 0xbb, 0x68, 0xa1, 0x02, 0xcc, 0xb6, 0x0f,
-	0x1a, 0xbb, 0xad, 0xe6, 0x67, 0xc5, 0x27, 0x0a, 0xfb, 0x6f, 0x5f, 0xdb, 0xda, 0x69, 0x68, 0x9f,
-	0x15, 0x7f, 0x97, 0x40, 0x79, 0xc8, 0x6c, 0x36, 0xb6, 0xb6, 0x37, 0x5a, 0xc5, 0x2f, 0x93, 0xb5,
-	0x3f, 0x64, 0x00, 0xd6, 0x8f, 0xb0, 0x79, 0xec, 0x11, 0xdb, 0xa5, 0xc8, 0x8b, 0x26, 0xbe, 0xc2,
-	0x27, 0xfe, 0x72, 0x14, 0x64, 0x04, 0x93, 0x23, 0x3f, 0xd8, 0x70, 0xa9, 0x3f, 0x68, 0xbe, 0xcb,
-	0x2a, 0xf6, 0xf8, 0xd9, 0x15, 0xfb, 0x24, 0xbc, 0x12, 0x4e, 0x20, 0x6f, 0x98, 0xc7, 0xba, 0xed,
-	0x52, 0xec, 0xd2, 0xf0, 0x9e, 0xb9, 0x3b, 0xd5, 0x6b, 0xc3, 0x3c, 0xde, 0x12, 0x30, 0xe1, 0x78,
-	0xf5, 0xaa, 0x4e, 0xc1, 0x18, 0x31, 0x94, 0x7f, 0x9e, 0x18, 0x75, 0xfd, 0xf7, 0xa1, 0xc0, 0x4f,
-	0x0c, 0x3d, 0xf2, 0x49, 0xbf, 0x7b, 0xc4, 0xb7, 0x27, 0xd9, 0x54, 0xaf, 0xd8, 0x8d, 0x79, 0xc6,
-	0x71, 0x20, 0x28, 0xd0, 0x0e, 0xe4, 0x3c, 0x9f, 0x58, 0x7d, 0x13, 0xfb, 0x61, 0x4e, 0xaf, 0x5f,
-	0x52, 0x49, 0xd6, 0x81, 0x1c, 0x2c, 0x12, 0x4b, 0xb1, 0x8a, 0x6a, 0x11, 0x43, 0x59, 0x87, 0xb9,
-	0x31, 0x04, 0x9a, 0x1f, 0xdd, 0xe5, 0x05, 0x7e, 0x53, 0x7f, 0x04, 0xe9, 0x80, 0x1a, 0x14, 0xf3,
-	0x36, 0xcc, 0xaf, 0xd5, 0xa6, 0xfa, 0x0a, 0x29, 0x58, 0x9b, 0x61, 0xe9, 0x44, 0x98, 0x95, 0x7f,
-	0xa1, 0x44, 0x1e, 0xb8, 0x1a, 0x7d, 0x17, 0xb2, 0x8e, 0x11, 0x50, 0x3e, 0x0a, 0x99, 0x9f, 0x4c,
-	0x73, 0xe5, 0xf9, 0xb0, 0xba, 0x3c, 0xad, 0x20, 0x3d, 0x1c, 0x04, 0x46, 0x17, 0xab, 0xeb, 0x0e,
-	0x31, 0x8f, 0xb5, 0x59, 0x66, 0xc6, 0x86, 0x5f, 0x0b, 0xd2, 0x1d, 0xdc, 0xb5, 0x5d, 0x1e, 0xd3,
-	0xd5, 0xeb, 0x29, 0x8c, 0xcb, 0x9f, 0x42, 0x21, 0xde, 0x6d, 0xa8, 0x08, 0xc9, 0x63, 0x3c, 0x10,
-	0xe3, 0x49, 0x63, 0x4b, 0xf4, 0x6d, 0x48, 0x9f, 0x18, 0x4e, 0x3f, 0xcc, 0xfd, 0xf6, 0x25, 0x75,
-	0xd6, 0x04, 0xf2, 0x7e, 0xe2, 0x7d, 0xa5, 0xfc, 0x21, 0x2c, 0x4c, 0x34, 0xd4, 0x14, 0xee, 0xeb,
-	0x71, 0xee, 0x42, 0xcc, 0xbc, 0x76, 0x08, 0xf9, 0x6d, 0x3b, 0xa0, 0x1a, 0xfe, 0x71, 0x1f, 0x07,
-	0x14, 0x7d, 0x07, 0xb2, 0x01, 0x76, 0xb0, 0x49, 0x89, 0x2f, 0xe7, 0xcb, 0xcd, 0x17, 0x6e, 0x26,
-	0xa1, 0x96, 0x85, 0x1f, 0xc1, 0xd1, 0x1d, 0xc8, 0xe1, 0x53, 0x8a, 0xdd, 0x80, 0x5d, 0xdb, 0x16,
-	0xf7, 0x13, 0x09, 0x6a, 0x8f, 0x93, 0x50, 0x10, 0x8e, 0x02, 0x8f, 0xb8, 0x01, 0x46, 0x75, 0xc8,
-	0x04, 0x7c, 0x4e, 0xc8, 0x31, 0x52, 0x8c, 0xbd, 0xc9, 0xb8, 0x5c, 0x93, 0x7a, 0xa4, 0x42, 0xe6,
-	0x08, 0x1b, 0x16, 0xf6, 0x65, 0x65, 0x8a, 0x51, 0x44, 0x0f, 0xb9, 0x5c, 0x86, 0x22, 0x51, 0xe8,
-	0x3e, 0x64, 0xf8, 0xf8, 0x0a, 0x4a, 0x49, 0xde, 0xb1, 0xb1, 0x01, 0x15, 0x8f, 0x40, 0x3c, 0xfd,
-	0x42, 0x5b, 0x61, 0x71, 0x79, 0x12, 0xe5, 0x3f, 0x2a, 0x90, 0xe6, 0x56, 0xe8, 0x6d, 0x48, 0xc5,
-	0x66, 0xf0, 0xb5, 0x29, 0xef, 0x49, 0x49, 0xcc, 0x61, 0x68, 0x19, 0x0a, 0x3d, 0x62, 0xe9, 0x3e,
-	0x3e, 0xb1, 0x39, 0x33, 0x6f, 0x25, 0x2d, 0xdf, 0x23, 0x96, 0x26, 0x45, 0xe8, 0x4d, 0x48, 0xfb,
-	0xa4, 0x4f, 0x31, 0xbf, 0x63, 0xf2, 0x6b, 0x0b, 0x51, 0x92, 0x1a, 0x13, 0x87, 0x7d, 0xce, 0x31,
-	0xe8, 0xbd, 0x51, 0xf1, 0x52, 0x3c, 0xc5, 0x9b, 0x17, 0xcc, 0xe0, 0x51, 0x76, 0xfc, 0xbf, 0xda,
-	0xbf, 0x14, 0x28, 0x34, 0x3c, 0xcf, 0x19, 0x84, 0xdb, 0xfd, 0x21, 0xcc, 0xb2, 0xfb, 0xb5, 0x3b,
-	0x9a, 0x93, 0xaf, 0x46, 0x44, 0x71, 0xa0, 0xba, 0xce, 0x51, 0x92, 0x2e, 0xb4, 0xf9, 0x86, 0x6a,
-	0x7d, 0xa1, 0x40, 0x46, 0xd8, 0x21, 0x15, 0xae, 0xe1, 0x53, 0x0f, 0x9b, 0x54, 0x1f, 0x2b, 0x03,
-	0x9f, 0x50, 0xda, 0xa2, 0x50, 0xed, 0x8c, 0x15, 0x23, 0xd3, 0xf7, 0x02, 0xec, 0x53, 0xb9, 0xe5,
-	0xd3, 0x0a, 0xac, 0x49, 0x08, 0x7a, 0x0d, 0x32, 0x16, 0x76, 0xb0, 0x2c, 0x5d, 0xae, 0x99, 0x8f,
-	0xbf, 0xff, 0xa5, 0xaa, 0xf6, 0x33, 0x05, 0xe6, 0x64, 0x46, 0x2f, 0xbd, 0x01, 0x2f, 0x3f, 0x09,
-	0x67, 0x09, 0xc8, 0x33, 0x07, 0xe1, 0x1e, 0xd4, 0x47, 0xec, 0xca, 0x74, 0xf6, 0x11, 0xef, 0x32,
-	0xa4, 0x79, 0x9b, 0xf2, 0x30, 0x26, 0xf2, 0x14, 0x1a, 0xf4, 0x1b, 0x65, 0xe2, 0x12, 0x10, 0x47,
-	0xe0, 0xde, 0x78, 0x6e, 0xe1, 0xae, 0x6a, 0xd1, 0xa8, 0x17, 0x13, 0xfb, 0x87, 0x57, 0xbc, 0x8a,
-	0xbe, 0x78, 0xf6, 0xdf, 0xdf, 0x2d, 0x97, 0x37, 0xcf, 0x47, 0x50, 0x9c, 0x8c, 0xee, 0x9b, 0xe6,
-	0x5a, 0x32, 0x3e, 0xd7, 0xfe, 0x92, 0x82, 0x82, 0x48, 0xf5, 0xa5, 0x6f, 0xf7, 0x6f, 0xa7, 0xd7,
-	0xfc, 0x5b, 0x93, 0x35, 0x97, 0x63, 0xe7, 0xff, 0x5a, 0xf4, 0x5f, 0x2b, 0x00, 0x5e, 0xbf, 0xe3,
-	0xd8, 0xc1, 0x91, 0x6e, 0x50, 0x39, 0x3d, 0x56, 0x2e, 0x88, 0x74, 0x5f, 0x00, 0x1b, 0xf4, 0x7f,
-	0x12, 0x67, 0xce, 0x0b, 0xdd, 0xbd, 0xdc, 0xd6, 0x28, 0x7f, 0x00, 0xf3, 0xe3, 0x99, 0x5d, 0xa9,
-	0xb1, 0x34, 0x58, 0x78, 0x80, 0xe9, 0x43, 0xdb, 0xa5, 0x41, 0x78, 0x82, 0x47, 0xe7, 0x52, 0xb9,
-	0xf0, 0x5c, 0x5e, 0x3e, 0x12, 0xfe, 0x91, 0x80, 0x62, 0x44, 0xfa, 0xd2, 0x1b, 0xb6, 0x0d, 0x73,
-	0x9e, 0x6f, 0xf7, 0x0c, 0x7f, 0xa0, 0xb3, 0x4f, 0xfe, 0x40, 0x5e, 0x39, 0xf5, 0xc8, 0xc1, 0x64,
-	0x30, 0x6a, 0xb8, 0xe0, 0x52, 0x49, 0x57, 0x90, 0x24, 0x5c, 0xc6, 0x5e, 0x9f, 0xe2, 0x37, 0x05,
-	0xc9, 0x29, 0x5a, 0xeb, 0xaa, 0x9c, 0x79, 0xc1, 0x21, 0x28, 0x2f, 0x6f, 0x83, 0x0f, 0xd8, 0x67,
-	0x49, 0x8c, 0x81, 0xdd, 0xa0, 0xc2, 0x75, 0xf8, 0x61, 0x14, 0xfb, 0x2d, 0x4a, 0xdd, 0x6c, 0xef,
-	0x08, 0xef, 0x02, 0x53, 0xf3, 0x60, 0xe1, 0x13, 0xd7, 0x08, 0x02, 0xbb, 0xeb, 0x86, 0xdb, 0xf8,
-	0xda, 0xe8, 0xdd, 0xc0, 0xee, 0xc2, 0xc9, 0x7b, 0x44, 0x3e, 0x10, 0xaa, 0x90, 0x27, 0xae, 0x33,
-	0xd0, 0x0f, 0x0d, 0xdb, 0xc1, 0x62, 0x12, 0x67, 0x35, 0x60, 0xa2, 0x4d, 0x2e, 0x41, 0x37, 0x61,
-	0xd6, 0xf2, 0x07, 0xba, 0xdf, 0x77, 0x79, 0x59, 0xb3, 0x5a, 0xc6, 0xf2, 0x07, 0x5a, 0xdf, 0xad,
-	0x19, 0x50, 0x8c, 0x3c, 0x5e, 0x79, 0x8f, 0xa3, 0xe0, 0x12, 0x17, 0x06, 0xf7, 0xc6, 0x63, 0xf6,
-	0x09, 0x2c, 0xf0, 0x19, 0x48, 0xec, 0x7d, 0x5c, 0x9c, 0x41, 0xd7, 0x60, 0xa1, 0xfd, 0xb0, 0xa1,
-	0xb5, 0xf4, 0xdd, 0xbd, 0x03, 0x7d, 0x73, 0xef, 0x93, 0xdd, 0x56, 0x51, 0x41, 0xd7, 0xa1, 0xb8,
-	0xbb, 0xa7, 0x0b, 0x79, 0xf8, 0x45, 0x95, 0x40, 0x37, 0x60, 0x91, 0x81, 0xc6, 0xc5, 0x49, 0x74,
-	0x1b, 0x6e, 0x6e, 0x1c, 0xac, 0xb7, 0xf4, 0x03, 0xad, 0xb1, 0xdb, 0x6e, 0xac, 0x1f, 0x6c, 0xed,
-	0xed, 0xea, 0xf2, 0xc3, 0x2b, 0x85, 0x16, 0x61, 0x4e, 0xe0, 0xdb, 0x07, 0x7b, 0xfb, 0xfb, 0x1b,
-	0xad, 0x62, 0x7a, 0xed, 0xf7, 0x89, 0xf0, 0x91, 0xf4, 0x1e, 0xa4, 0x58, 0x34, 0xe8, 0xc6, 0xd4,
-	0xdb, 0xa7, 0xfc, 0xca, 0xf4, 0xb1, 0xc3, 0xcc, 0xd8, 0x3b, 0x2d, 0x6e, 0x16, 0x7b, 0xa2, 0xc6,
-	0xcd, 0xc6, 0x1e, 0x94, 0xef, 0x43, 0x9a, 0x5f, 0xf0, 0xe8, 0x95, 0xe9, 0x6f, 0x98, 0xf2, 0xcd,
-	0x17, 0xe4, 0xd2, 0xb2, 0x01, 0xd9, 0xb0, 0x39, 0xd1, 0xad, 0x69, 0x0d, 0x2b, 0xec, 0xcb, 0x17,
-	0xf7, 0x32, 0xa3, 0x08, 0x37, 0x37, 0x4e, 0x31, 0xd1, 0x62, 0x71, 0x8a, 0xc9, 0x5e, 0x68, 0x3e,
-	0x78, 0xf2, 0xb7, 0xca, 0xcc, 0x93, 0xaf, 0x2b, 0xca, 0xd3, 0xaf, 0x2b, 0xca, 0x97, 0x67, 0x95,
-	0x99, 0xaf, 0xce, 0x2a, 0xca, 0x9f, 0xce, 0x2a, 0xca, 0xd3, 0xb3, 0xca, 0xcc, 0x5f, 0xcf, 0x2a,
-	0x33, 0x9f, 0xaf, 0x4c, 0x9b, 0xa6, 0x2f, 0xfc, 0x8a, 0xdb, 0xc9, 0xf0, 0xd5, 0xbb, 0xff, 0x0e,
-	0x00, 0x00, 0xff, 0xff, 0x2d, 0x38, 0x62, 0xfa, 0xe1, 0x15, 0x00, 0x00,
+	// 2398 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xc4, 0x59, 0x4d, 0x6c, 0xe3, 0xd6,
+	0x11, 0x36, 0x25, 0x4b, 0xb6, 0x47, 0xb2, 0x4d, 0x3f, 0x7b, 0x6d, 0x45, 0xd9, 0x58, 0xb2, 0xf6,
+	0x4f, 0xf9, 0x59, 0x39, 0x71, 0x12, 0x20, 0x5d, 0x24, 0x8b, 0x4a, 0x96, 0xbd, 0xeb, 0xc6, 0x7f,
+	0xa5, 0xb4, 0x4d, 0x13, 0xa0, 0x20, 0x28, 0xf2, 0x49, 0x66, 0x4d, 0x91, 0x2c, 0xf9, 0xe4, 0x58,
+	0x7b, 0x5c, 0xb4, 0x28, 0x10, 0xb4, 0x40, 0xd0, 0x4b, 0x7b, 0x29, 0x10, 0xa0, 0x97, 0x16, 0xe8,
+	0xad, 0xe7, 0x02, 0xb9, 0x75, 0xd1, 0x43, 0xb1, 0xc7, 0x9e, 0xb4, 0x68, 0xf6, 0xd0, 0x1e, 0x0b,
+	0x9f, 0x8a, 0x3d, 0x15, 0xef, 0x87, 0x22, 0x25, 0xcb, 0x76, 0x9d, 0x76, 0x9b, 0x8b, 0x41, 0xcd,
+	0x7c, 0xf3, 0xcd, 0x7b, 0xf3, 0x66, 0xe6, 0x0d, 0x69, 0xc8, 0xeb, 0x8e, 0xed, 0x77, 0xda, 0xd8,
+	0x5b, 0x75, 0x3d, 0x87, 0x38, 0xba, 0x63, 0xf5, 0x1f, 0x4a, 0xec, 0x01, 0x4d, 0x06, 0x88, 0xec,
+	0x72, 0xc3, 0x73, 0x0e, 0xcf, 0x46, 0x66, 0x6f, 0xf6, 0xb9, 0x3c, 0xac, 0x3b, 0x47, 0xd8, 0xeb,
+	0x5a, 0x4e, 0x8b, 0x3d, 0x7b, 0x06, 0x36, 0x54, 0xc7, 0x15, 0xb8, 0x85, 0x96, 0xd3, 0x72, 0xd8,
+	0xe3, 0x2a, 0x7d, 0x12, 0xd2, 0xe5, 0x96, 0xe3, 0xb4, 0x2c, 0xcc, 0x49, 0x1b, 0x9d, 0xe6, 0xaa,
+	0xd1, 0xf1, 0x34, 0x62, 0x3a, 0x36, 0xd7, 0x17, 0xfe, 0x2c, 0xc3, 0x54, 0xed, 0x40, 0xf3, 0x8c,
+	0x9a, 0x8b, 0x75, 0xf4, 0x26, 0xc4, 0x4c, 0x23, 0x23, 0xe5, 0xa5, 0xe2, 0x54, 0x25, 0x7f, 0xd2,
+	0xcb, 0xcd, 0x75, 0xb5, 0xb6, 0x75, 0xa7, 0xf0, 0x86, 0xd3, 0x36, 0x09, 0x6e, 0xbb, 0xa4, 0x5b,
+	0x78, 0xde, 0xcb, 0x4d, 0x30, 0xfc, 0x56, 0x55, 0x89, 0x99, 0x06, 0xda, 0x83, 0x09, 0xdf, 0xe9,
+	0x78, 0x3a, 0xf6, 0x33, 0xb1, 0x7c, 0xbc, 0x98, 0x5a, 0xcb, 0x96, 0x82, 0xf5, 0x96, 0xfa, 0xbc,
+	0xa5, 0x1a, 0x83, 0x54, 0x5e, 0x7a, 0xdc, 0xcb, 0x8d, 0x8d, 0xa4, 0x55, 0x02, 0x16, 0xf4, 0x7d,
+	0x98, 0x0f, 0xf6, 0xa9, 0x5a, 0x4e, 0x4b, 0x75, 0x3d, 0xdc, 0x34, 0x8f, 0x33, 0x71, 0xb6, 0xa6,
+	0xe2, 0x49, 0x2f, 0x77, 0x9d, 0x1b, 0x8f, 0x00, 0x45, 0xf9, 0xe6, 0x02, 0xfd, 0xb6, 0xd3, 0xda,
+	0x67, 0x5a, 0x54, 0x86, 0xd4, 0x81, 0x69, 0x93, 0x80, 0x71, 0xbc, 0xbf, 0xcb, 0xab, 0x9c, 0x31,
+	0xa2, 0x8c, 0x32, 0x01, 0x95, 0x0b, 0x8a, 0x2a, 0xa4, 0x19, 0xaa, 0xa1, 0xe9, 0x87, 0x1d, 0xd7,
+	0xcf, 0x24, 0xf2, 0x52, 0x31, 0x51, 0x59, 0x39, 0xe9, 0xe5, 0x5e, 0x89, 0x70, 0x08, 0x6d, 0x94,
+	0x84, 0x79, 0xae, 0x70, 0x39, 0xf2, 0x40, 0x6e, 0x6b, 0xc7, 0x2a, 0x39, 0xb6, 0xd5, 0xe0, 0x34,
+	0x32, 0xc9, 0xbc, 0x54, 0x4c, 0xad, 0xbd, 0x54, 0xe2, 0xc7, 0x55, 0x0a, 0x8e, 0xab, 0x54, 0x15,
+	0x80, 0xca, 0x6d, 0x11, 0xbb, 0x15, 0xee, 0x68, 0x98, 0x20, 0xe2, 0xec, 0x57, 0x4f, 0x73, 0x92,
+	0x32, 0xd3, 0xd6, 0x8e, 0xeb, 0xc7, 0x76, 0x60, 0xce, 0x7c, 0x9a, 0xf6, 0xa0, 0xcf, 0x89, 0xcb,
+	0xfa, 0x34, 0xed, 0x0b, 0x7c, 0x9a, 0x76, 0xd4, 0xe7, 0x2a, 0x4c, 0x18, 0xa6, 0xaf, 0x35, 0x2c,
+	0x9c, 0x99, 0xcc, 0x4b, 0xc5, 0xc9, 0xca, 0x95, 0x33, 0xce, 0x5e, 0xa0, 0x58, 0x78, 0x1d, 0xa2,
+	0xfa, 0x44, 0xb3, 0x8d, 0x46, 0xd7, 0xcf, 0x4c, 0xe5, 0xa5, 0xe2, 0xf4, 0x40, 0x78, 0x23, 0xda,
+	0xc1, 0xf0, 0x3a, 0xa4, 0x26, 0xe4, 0x68, 0x1f, 0x92, 0x96, 0xd6, 0xc0, 0x96, 0x9f, 0x01, 0xb6,
+	0x41, 0x54, 0xea, 0x57, 0xd4, 0x36, 0x95, 0xd7, 0x30, 0xa9, 0x5c, 0xa7, 0x3b, 0x7b, 0xd2, 0xcb,
+	0x49, 0x27, 0xbd, 0x5c, 0x66, 0x78, 0x45, 0x6f, 0x98, 0xb6, 0x65, 0xda, 0xb8, 0xa0, 0x08, 0x1e,
+	0xf4, 0x09, 0x2c, 0x88, 0x25, 0xaa, 0x9f, 0x6a, 0x26, 0x51, 0x9b, 0x8e, 0xa7, 0x6a, 0xfa, 0x61,
+	0x26, 0xc5, 0x76, 0xf5, 0xea, 0x49, 0x2f, 0x77, 0x83, 0x73, 0x8c, 0x42, 0x0d, 0x64, 0xa5, 0x00,
+	0x7c, 0xa4, 0x99, 0x64, 0xd3, 0xf1, 0xca, 0xfa, 0x21, 0xda, 0x03, 0xd9, 0x33, 0xed, 0x96, 0xda,
+	0xe8, 0x34, 0x9b, 0xd8, 0x53, 0x7d, 0xf3, 0x21, 0xce, 0xa4, 0xd9, 0xbe, 0x6f, 0x84, 0x91, 0x1f,
+	0x46, 0x44, 0x39, 0x67, 0xa8, 0xb2, 0xc2, 0x74, 0x35, 0xf3, 0x21, 0x46, 0x0a, 0xcc, 0x79, 0x58,
+	0x33, 0x54, 0xfd, 0x40, 0xb3, 0x6d, 0x6c, 0x71, 0xc6, 0x69, 0xc6, 0x78, 0xf3, 0xa4, 0x97, 0x2b,
+	0x04, 0xe5, 0x33, 0x04, 0x89, 0x52, 0xce, 0x52, 0xed, 0x3a, 0x57, 0x32, 0x4e, 0x0c, 0x69, 0x9f,
+	0x68, 0x1e, 0x51, 0x5d, 0xc7, 0x32, 0xf5, 0x6e, 0x66, 0x26, 0x2f, 0x15, 0x67, 0xd6, 0x72, 0x23,
+	0x4b, 0x9d, 0xe2, 0xf6, 0x19, 0x2c, 0x7a, 0x72, 0x51, 0xf3, 0x81, 0x93, 0xf3, 0x43, 0x3c, 0xba,
+	0x0b, 0xc0, 0x71, 0xc4, 0x6c, 0xe3, 0xcc, 0x6c, 0x5e, 0x2a, 0xc6, 0x2b, 0xb9, 0x93, 0x5e, 0xee,
+	0xe5, 0x28, 0x07, 0xd5, 0x45, 0x19, 0xa6, 0x98, 0xb8, 0x6e, 0xb6, 0x31, 0xfa, 0xb1, 0x04, 0x8b,
+	0x03, 0x7d, 0xc1, 0xc3, 0x04, 0xdb, 0x2c, 0xd7, 0xe5, 0x8b, 0x72, 0xfd, 0x6d, 0x91, 0xeb, 0xb7,
+	0x46, 0xb4, 0x97, 0x3e, 0xcd, 0x70, 0xc6, 0x2f, 0x44, 0xba, 0x8c, 0x12, 0x80, 0xd0, 0x03, 0x40,
+	0xb4, 0x4c, 0x1a, 0x1a, 0xd1, 0x0f, 0xd4, 0x36, 0xf6, 0x7d, 0xad, 0x85, 0xfd, 0xcc, 0x1c, 0x3b,
+	0x82, 0x5b, 0x27, 0xbd, 0xdc, 0x35, 0xee, 0xe2, 0x34, 0x26, 0xba, 0x2d, 0x99, 0x1c, 0xdb, 0x15,
+	0xaa, 0xdd, 0x11, 0x4a, 0xb4, 0x0d, 0xb3, 0xa1, 0x49, 0xa3, 0x4b, 0xb0, 0x9f, 0x41, 0x2c, 0x44,
+	0xd7, 0x4f, 0x7a, 0xb9, 0xfc, 0x30, 0x27, 0x03, 0x44, 0x09, 0xa7, 0x03, 0xc2, 0x0a, 0xd5, 0xa0,
+	0x63, 0x40, 0x4e, 0xb3, 0x69, 0x39, 0x9a, 0xa1, 0x6a, 0x4d, 0x82, 0x3d, 0xd5, 0x34, 0x2c, 0x9c,
+	0x99, 0xbf, 0x28, 0x4c, 0xab, 0x22, 0x4c, 0x62, 0x0f, 0xa7, 0x29, 0x86, 0x43, 0x24, 0x0b, 0x48,
+	0x99, 0x22, 0xb6, 0x0c, 0x8b, 0x26, 0xe8, 0x94, 0x90, 0x61, 0x23, 0xb3, 0xc0, 0x1c, 0x2e, 0x84,
+	0x99, 0xb4, 0x7e, 0x80, 0xf5, 0x43, 0xd7, 0xa1, 0xed, 0x72, 0xf9, 0xa4, 0x97, 0xcb, 0x0e, 0xf8,
+	0xc1, 0xc6, 0xc0, 0xc9, 0xf7, 0xa5, 0xa8, 0x09, 0xb2, 0x87, 0x7d, 0x4c, 0x54, 0xbd, 0x6f, 0x9e,
+	0xb9, 0x72, 0x0e, 0x75, 0xb4, 0xb6, 0x86, 0xec, 0x86, 0x0a, 0xc1, 0xc7, 0x24, 0xb4, 0xcb, 0xfe,
+	0x49, 0x82, 0x24, 0xbf, 0xcc, 0xd0, 0x16, 0x4c, 0xfc, 0xd0, 0xe9, 0x78, 0xb6, 0x66, 0x89, 0x0b,
+	0x73, 0xf5, 0x79, 0x2f, 0xf7, 0x7a, 0xcb, 0x29, 0xb5, 0xb4, 0x87, 0x98, 0x10, 0x5c, 0x32, 0xf0,
+	0xd1, 0xaa, 0xee, 0x78, 0x78, 0x75, 0xe8, 0x82, 0x2f, 0x7d, 0x87, 0x9b, 0x29, 0x81, 0x3d, 0xb2,
+	0x00, 0x68, 0x6f, 0x75, 0x9a, 0x4d, 0x1f, 0x13, 0x76, 0xd5, 0xc5, 0x2b, 0x3b, 0x61, 0xde, 0x87,
+	0xba, 0xc1, 0x8b, 0xf8, 0xb5, 0xff, 0xc4, 0xd9, 0x1e, 0x33, 0x54, 0xa6, 0xda, 0xa6, 0xcd, 0x1f,
+	0xef, 0x8c, 0xff, 0xe3, 0x8b, 0x9c, 0x54, 0xd8, 0x86, 0x54, 0xa4, 0x54, 0xd1, 0x12, 0xcc, 0xd7,
+	0xea, 0x65, 0xa5, 0xae, 0x96, 0xeb, 0xea, 0xce, 0xd6, 0xae, 0xba, 0xb7, 0xb9, 0x59, 0xdb, 0xa8,
+	0xcb, 0x63, 0x68, 0x0e, 0xa6, 0xfb, 0x8a, 0xfb, 0x1b, 0xe5, 0xaa, 0x2c, 0x0d, 0x88, 0xea, 0x5b,
+	0x3b, 0x1b, 0x72, 0x4c, 0x70, 0xfe, 0x44, 0x82, 0xf4, 0xba, 0x88, 0x36, 0x9b, 0x27, 0xea, 0x90,
+	0x76, 0x3d, 0x47, 0xc7, 0xbe, 0xaf, 0xfa, 0x2e, 0xd6, 0x59, 0xa0, 0x52, 0x6b, 0x57, 0xc2, 0x86,
+	0xbc, 0xcf, 0xb5, 0x14, 0x5c, 0xc9, 0x46, 0x7a, 0xf2, 0x8c, 0xe8, 0xc9, 0x41, 0x27, 0x4e, 0xb9,
+	0x21, 0x10, 0xe5, 0x20, 0xe5, 0xd3, 0x7e, 0xa3, 0x5a, 0x66, 0xdb, 0x24, 0x99, 0x18, 0x2d, 0x2c,
+	0x05, 0x98, 0x68, 0x9b, 0x4a, 0x0a, 0x5f, 0x4a, 0x30, 0xad, 0x60, 0xd7, 0x32, 0x75, 0xad, 0x46,
+	0x34, 0xd2, 0xf1, 0xd1, 0x9b, 0x30, 0xae, 0x3b, 0x06, 0x66, 0x0b, 0x98, 0x59, 0xbb, 0x1a, 0xe6,
+	0xc4, 0x00, 0xac, 0xb4, 0xee, 0x18, 0x58, 0x61, 0x48, 0xb4, 0x08, 0x49, 0xec, 0x79, 0x8e, 0xc7,
+	0xe7, 0x9a, 0x29, 0x45, 0xfc, 0x42, 0x57, 0x61, 0xaa, 0x63, 0x1f, 0x60, 0xcd, 0x22, 0x07, 0x5d,
+	0x3e, 0x95, 0x28, 0xa1, 0xa0, 0x70, 0x0f, 0xc6, 0x29, 0x07, 0x9a, 0x84, 0xf1, 0xad, 0xea, 0xf6,
+	0x86, 0x3c, 0x86, 0xd2, 0x30, 0x59, 0x29, 0xaf, 0x7f, 0xb8, 0xb9, 0xb5, 0xbd, 0x2d, 0x1b, 0x28,
+	0x0d, 0x13, 0xb5, 0x7a, 0x79, 0xb7, 0x5a, 0xf9, 0x58, 0x7e, 0x2c, 0xd1, 0x5f, 0xfb, 0xca, 0xd6,
+	0x4e, 0x59, 0xf9, 0x58, 0xfe, 0x7d, 0x0c, 0xa5, 0x20, 0xb9, 0x59, 0xde, 0xda, 0xde, 0xa8, 0xca,
+	0x9f, 0xc7, 0x0b, 0x7f, 0x49, 0x02, 0x84, 0x79, 0x87, 0xdc, 0x70, 0xcc, 0x92, 0xd8, 0x98, 0xb5,
+	0x32, 0x2a, 0xad, 0xc5, 0x9c, 0xe5, 0x6f, 0xd8, 0xc4, 0xeb, 0xf2, 0x8e, 0xf6, 0xe8, 0xe9, 0x25,
+	0x73, 0x32, 0x98, 0xc3, 0x8e, 0x20, 0xa5, 0xe9, 0x87, 0xaa, 0x69, 0xd3, 0xae, 0x16, 0x0c, 0x77,
+	0xd7, 0x47, 0x7a, 0x2d, 0xeb, 0x87, 0x5b, 0x1c, 0xc6, 0x1d, 0xaf, 0x5e, 0xd6, 0x29, 0x68, 0x7d,
+	0x86, 0xec, 0x2f, 0x63, 0xfd, 0x0a, 0xfb, 0x2e, 0xa4, 0xd9, 0x35, 0x45, 0x0e, 0x3c, 0xa7, 0xd3,
+	0x3a, 0x60, 0x87, 0x17, 0xaf, 0x94, 0x2e, 0x99, 0xf9, 0x29, 0xca, 0x51, 0xe7, 0x14, 0x68, 0x07,
+	0xa6, 0x5c, 0xcf, 0x31, 0x3a, 0x3a, 0xf6, 0x82, 0x3d, 0xbd, 0x7a, 0x4e, 0x24, 0x4b, 0xfb, 0x02,
+	0xcc, 0x37, 0x36, 0x4e, 0x23, 0xaa, 0x84, 0x0c, 0x59, 0x0c, 0xd3, 0x03, 0x08, 0x34, 0xd3, 0x1f,
+	0xa0, 0xd3, 0x6c, 0x3c, 0xbe, 0x0b, 0x09, 0x9f, 0x68, 0x04, 0xb3, 0x24, 0x4d, 0xad, 0x15, 0x46,
+	0xfa, 0x0a, 0x28, 0x68, 0x12, 0x62, 0xe1, 0x84, 0x9b, 0xf1, 0xba, 0xe2, 0x7f, 0xb3, 0xbf, 0x96,
+	0x60, 0x7a, 0x00, 0x8a, 0xbe, 0x0d, 0x93, 0x96, 0xe6, 0x13, 0x36, 0x8b, 0x50, 0x9f, 0xc9, 0xca,
+	0x8d, 0xe7, 0xbd, 0xdc, 0xca, 0xa8, 0xe0, 0x88, 0x2b, 0xa6, 0xb4, 0x6e, 0x39, 0xfa, 0xa1, 0x32,
+	0x41, 0xcd, 0xe8, 0xf4, 0x51, 0x85, 0x44, 0x03, 0xb7, 0x4c, 0x3b, 0x13, 0xfb, 0x5a, 0xb1, 0xe5,
+	0xc6, 0x62, 0x7d, 0x1f, 0x41, 0x3a, 0x9a, 0x7f, 0x48, 0x86, 0xf8, 0x21, 0xee, 0xf2, 0xe6, 0xa8,
+	0xd0, 0x47, 0xf4, 0x16, 0x24, 0x8e, 0x34, 0xab, 0x13, 0x44, 0xe3, 0xe5, 0x73, 0x22, 0xaf, 0x70,
+	0xe4, 0x9d, 0xd8, 0x7b, 0x52, 0xf6, 0x03, 0x98, 0x1d, 0x4a, 0xb1, 0x11, 0xdc, 0x0b, 0x51, 0xee,
+	0x74, 0xc4, 0x5c, 0xf4, 0xa6, 0x26, 0xa4, 0xb6, 0x4d, 0x9f, 0x28, 0xf8, 0x47, 0x1d, 0xec, 0x13,
+	0xf4, 0x2d, 0x98, 0xf4, 0xb1, 0x85, 0x75, 0xe2, 0x78, 0xa2, 0x2b, 0x2d, 0x9d, 0x1a, 0x13, 0xb9,
+	0x5a, 0x1c, 0x48, 0x1f, 0x4e, 0x3b, 0x00, 0x3e, 0x26, 0xd8, 0xf6, 0xe9, 0x5c, 0x61, 0x30, 0x6f,
+	0xa1, 0xa0, 0xf0, 0x28, 0x0e, 0x69, 0xee, 0xc8, 0x77, 0x1d, 0xdb, 0xc7, 0xa8, 0x08, 0x49, 0x9f,
+	0x75, 0x17, 0xd1, 0x7c, 0xe4, 0xc8, 0xd4, 0xc4, 0xe4, 0x8a, 0xd0, 0xa3, 0x12, 0x24, 0x0f, 0xb0,
+	0x66, 0x60, 0x4f, 0xc4, 0x47, 0x0e, 0x57, 0x74, 0x9f, 0xc9, 0xc5, 0x52, 0x04, 0x0a, 0xdd, 0x81,
+	0x24, 0x6b, 0x7a, 0x7e, 0x26, 0xce, 0x32, 0x39, 0xd2, 0xd6, 0xa2, 0x2b, 0xe0, 0xc3, 0x59, 0x60,
+	0xcb, 0x2d, 0xce, 0xdf, 0x44, 0xf6, 0x8f, 0x12, 0x24, 0x98, 0x15, 0xba, 0x0d, 0xe3, 0x91, 0xce,
+	0x3d, 0x3f, 0x62, 0xe2, 0x13, 0xc4, 0x0c, 0x86, 0x56, 0x20, 0xdd, 0x76, 0x0c, 0xd5, 0xc3, 0x47,
+	0x26, 0x63, 0x66, 0x69, 0xa5, 0xa4, 0xda, 0x8e, 0xa1, 0x08, 0x11, 0x7a, 0x1d, 0x12, 0x9e, 0xd3,
+	0x21, 0x98, 0x35, 0xcf, 0xd4, 0xda, 0x6c, 0xb8, 0x49, 0x85, 0x8a, 0x83, 0xfc, 0x67, 0x18, 0xf4,
+	0x6e, 0x3f, 0x78, 0xe3, 0x6c, 0x8b, 0x4b, 0x67, 0x74, 0xee, 0xfe, 0xee, 0xd8, 0xaf, 0xc2, 0xbf,
+	0x24, 0x48, 0x97, 0x5d, 0xd7, 0xea, 0x06, 0xc7, 0xfd, 0x01, 0x4c, 0xd0, 0x61, 0xb7, 0xd5, 0xef,
+	0x9f, 0xaf, 0x84, 0x44, 0x51, 0x60, 0x69, 0x9d, 0xa1, 0x04, 0x5d, 0x60, 0x73, 0x41, 0xb4, 0x3e,
+	0x93, 0x20, 0xc9, 0xed, 0x50, 0x09, 0xe6, 0xf1, 0xb1, 0x8b, 0x75, 0xa2, 0x0e, 0x84, 0x81, 0x75,
+	0x2e, 0x65, 0x8e, 0xab, 0x76, 0x06, 0x82, 0x91, 0xec, 0xb8, 0x3e, 0xf6, 0x48, 0x26, 0x76, 0x66,
+	0x80, 0x15, 0x01, 0x41, 0xd7, 0x20, 0x69, 0x60, 0x0b, 0x8b, 0xd0, 0x4d, 0x55, 0x52, 0xd1, 0x97,
+	0x71, 0xa1, 0x2a, 0xfc, 0x54, 0x82, 0x69, 0xb1, 0xa3, 0x17, 0x9e, 0x80, 0xe7, 0x57, 0xc2, 0x2f,
+	0xe2, 0x6c, 0xc4, 0xe8, 0x97, 0x5c, 0xb1, 0xcf, 0x2e, 0x8d, 0x66, 0xef, 0xf3, 0xae, 0x40, 0x82,
+	0xa5, 0x69, 0x26, 0x76, 0x7a, 0x9f, 0x5c, 0x83, 0x7e, 0x2b, 0x0d, 0x5d, 0x0e, 0xbc, 0x04, 0x6e,
+	0x0e, 0xee, 0x2d, 0x38, 0x55, 0x25, 0xbc, 0x02, 0x78, 0x27, 0xff, 0xc1, 0x25, 0xaf, 0xa8, 0xcf,
+	0x9e, 0x7e, 0xfd, 0x3b, 0xe7, 0xdc, 0x28, 0xa1, 0xdb, 0x80, 0x4c, 0x5b, 0xb7, 0x3a, 0x06, 0x8e,
+	0xce, 0xae, 0xf4, 0xe3, 0xc4, 0xa4, 0x32, 0x27, 0x34, 0x91, 0x01, 0xf4, 0x2e, 0xc8, 0xc3, 0x9b,
+	0xb9, 0xa8, 0x19, 0xc6, 0x23, 0xcd, 0xb0, 0xf0, 0xb3, 0x04, 0xa4, 0x79, 0x64, 0x5e, 0x78, 0x76,
+	0xfc, 0x6e, 0xf4, 0x11, 0xdd, 0x1a, 0x3e, 0x22, 0xd1, 0xa5, 0xbe, 0xd1, 0x33, 0xfa, 0x8d, 0x04,
+	0xe0, 0x76, 0x1a, 0x96, 0xe9, 0x1f, 0xa8, 0x1a, 0x11, 0xcd, 0xe6, 0xc6, 0x19, 0x2b, 0xdd, 0xe7,
+	0xc0, 0x32, 0xf9, 0xbf, 0xac, 0x73, 0xca, 0x0d, 0xdc, 0x5d, 0x90, 0x49, 0xef, 0x00, 0x44, 0x32,
+	0x28, 0x71, 0xf6, 0xdb, 0x8f, 0x02, 0xfa, 0xff, 0x2c, 0xa1, 0xb2, 0xef, 0xc3, 0xcc, 0x60, 0x3c,
+	0x2e, 0x95, 0x8e, 0x0a, 0xcc, 0xde, 0xc3, 0xe4, 0xbe, 0x69, 0x13, 0x3f, 0x68, 0x13, 0xfd, 0xe2,
+	0x97, 0xce, 0x2c, 0xfe, 0xf3, 0xfb, 0xce, 0x3f, 0x63, 0x20, 0x87, 0xa4, 0x2f, 0x3c, 0xcd, 0x6b,
+	0x30, 0xed, 0x7a, 0x66, 0x5b, 0xf3, 0xba, 0x2a, 0xfd, 0xc8, 0xe7, 0x8b, 0x7b, 0xad, 0x18, 0x3a,
+	0x18, 0x5e, 0x4c, 0x29, 0x78, 0x60, 0x52, 0x41, 0x97, 0x16, 0x24, 0x4c, 0x46, 0x47, 0x5f, 0xfe,
+	0x15, 0x51, 0x70, 0xf2, 0x84, 0xbc, 0x2c, 0x67, 0x8a, 0x73, 0x70, 0xca, 0xf3, 0xef, 0xb0, 0xf7,
+	0x61, 0x7a, 0x80, 0x81, 0x5e, 0xd3, 0xdc, 0x75, 0xf0, 0xce, 0x16, 0xf9, 0xfa, 0x5c, 0xda, 0xac,
+	0xed, 0x70, 0xef, 0x1c, 0x53, 0x70, 0x61, 0xf6, 0x81, 0xad, 0xf9, 0xbe, 0xd9, 0xb2, 0x83, 0x63,
+	0xbc, 0xd6, 0x1f, 0x4e, 0xe8, 0x85, 0x3b, 0x7c, 0x59, 0x71, 0x15, 0x7d, 0x93, 0x73, 0x6c, 0xab,
+	0xab, 0x36, 0x35, 0xd3, 0xc2, 0xbc, 0xdd, 0x4f, 0x2a, 0x40, 0x45, 0x9b, 0x4c, 0x82, 0x96, 0x60,
+	0xc2, 0xf0, 0xba, 0xaa, 0xd7, 0xb1, 0x59, 0x58, 0x27, 0x95, 0xa4, 0xe1, 0x75, 0x95, 0x8e, 0x5d,
+	0xd0, 0x40, 0x0e, 0x3d, 0x5e, 0xfa, 0x8c, 0xc3, 0xc5, 0xc5, 0xce, 0x5c, 0x5c, 0xe1, 0xef, 0x31,
+	0x58, 0x54, 0x06, 0xdf, 0xff, 0x2f, 0x91, 0xa3, 0x7f, 0x18, 0xee, 0x7e, 0xfc, 0x6d, 0xe3, 0xad,
+	0xe8, 0x00, 0x33, 0x8a, 0xfb, 0x9b, 0xed, 0x83, 0xef, 0xc0, 0xa2, 0x66, 0x59, 0xce, 0xa7, 0xaa,
+	0x4f, 0x1c, 0x0f, 0xab, 0x86, 0x79, 0x84, 0xbd, 0x16, 0xb6, 0x75, 0x2c, 0xc2, 0xbf, 0xc0, 0xb4,
+	0x35, 0xaa, 0xac, 0xf6, 0x75, 0xff, 0xf5, 0xa5, 0xe4, 0xc3, 0xd2, 0xa9, 0x60, 0xbc, 0xe8, 0xba,
+	0x7d, 0xed, 0x11, 0xfd, 0x94, 0xc3, 0x4d, 0x93, 0x10, 0xdb, 0xfb, 0x50, 0x1e, 0x43, 0xf3, 0x30,
+	0x5b, 0xbb, 0x5f, 0x56, 0xaa, 0xea, 0xee, 0x5e, 0x5d, 0xdd, 0xdc, 0x7b, 0xb0, 0x4b, 0xbf, 0x76,
+	0x2c, 0x80, 0xbc, 0xbb, 0xa7, 0x72, 0x79, 0xf0, 0xb6, 0x1e, 0x43, 0x57, 0x60, 0x8e, 0x82, 0x06,
+	0xc5, 0x71, 0xf4, 0x32, 0x2c, 0x6d, 0xd4, 0xd7, 0xab, 0x6a, 0x5d, 0x29, 0xef, 0xd6, 0xca, 0xeb,
+	0xf5, 0xad, 0xbd, 0x5d, 0x55, 0xbc, 0xd4, 0x8f, 0xb3, 0xef, 0x26, 0x0c, 0x5f, 0xab, 0xef, 0xed,
+	0xef, 0x6f, 0x54, 0xe5, 0xc4, 0xda, 0xcf, 0xe3, 0xc1, 0xa0, 0xfd, 0x2e, 0x8c, 0xd3, 0xd5, 0xa0,
+	0x2b, 0x23, 0x27, 0x98, 0xec, 0xe2, 0xe8, 0xbb, 0x88, 0x9a, 0xd1, 0x59, 0x3f, 0x6a, 0x16, 0x79,
+	0xcd, 0xc9, 0x2e, 0x0e, 0x8b, 0x85, 0xd9, 0x7b, 0x90, 0x60, 0x43, 0x22, 0x5a, 0x1c, 0x3d, 0x07,
+	0x67, 0x97, 0x4e, 0xc9, 0x85, 0x65, 0x19, 0x26, 0x83, 0xde, 0x83, 0x5e, 0x1a, 0xd5, 0x8f, 0xb8,
+	0x7d, 0xf6, 0xec, 0x56, 0x45, 0x29, 0x82, 0xda, 0x8d, 0x52, 0x0c, 0x75, 0x90, 0x6c, 0x76, 0x94,
+	0x4a, 0x50, 0x7c, 0x0f, 0x66, 0x87, 0x32, 0x06, 0xe5, 0x2f, 0xaa, 0xac, 0xec, 0xca, 0x39, 0x08,
+	0xce, 0x5b, 0xb9, 0xf7, 0xf8, 0x6f, 0xcb, 0x63, 0x8f, 0xbf, 0x5a, 0x96, 0x9e, 0x7c, 0xb5, 0x2c,
+	0x7d, 0xfe, 0x6c, 0x79, 0xec, 0x8b, 0x67, 0xcb, 0xd2, 0x97, 0xcf, 0x96, 0xa5, 0x27, 0xcf, 0x96,
+	0xc7, 0xfe, 0xfa, 0x6c, 0x79, 0xec, 0x93, 0x1b, 0xa3, 0x4a, 0xeb, 0xd4, 0xbf, 0xfb, 0x1a, 0x49,
+	0xf6, 0xf4, 0xf6, 0xbf, 0x07, 0x00, 0x48, 0xbe, 0x95, 0x74, 0x0a, 0x1c, 0x00, 0x00,
 }
 
 func (this *ShardSpec) Equal(that interface{}) bool {
@@ -1422,6 +1658,30 @@ func (this *ShardSpec) Equal(that interface{}) bool {
 	if this.ReadChannelSize != that1.ReadChannelSize {
 		return false
 	}
+	if this.StartPolicy != that1.StartPolicy {
+		return false
+	}
+	if this.StartTime != that1.StartTime {
+		return false
+	}
+	if this.RecoveryLogRetention != that1.RecoveryLogRetention {
+		return false
+	}
+	if this.TxnBatchMessages != that1.TxnBatchMessages {
+		return false
+	}
+	if this.TxnBatchBytes != that1.TxnBatchBytes {
+		return false
+	}
+	if this.OffloadAfterIdle != that1.OffloadAfterIdle {
+		return false
+	}
+	if !this.Offloaded.Equal(that1.Offloaded) {
+		return false
+	}
+	if !this.ResetCheckpoint.Equal(that1.ResetCheckpoint) {
+		return false
+	}
 	return true
 }
 func (this *ShardSpec_Source) Equal(that interface{}) bool {
@@ -1451,6 +1711,131 @@ func (this *ShardSpec_Source) Equal(that interface{}) bool {
 	}
 	return true
 }
+func (this *Checkpoint) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*Checkpoint)
+	if !ok {
+		that2, ok := that.(Checkpoint)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if len(this.Sources) != len(that1.Sources) {
+		return false
+	}
+	for i := range this.Sources {
+		a := this.Sources[i]
+		b := that1.Sources[i]
+		if !(&a).Equal(&b) {
+			return false
+		}
+	}
+	if len(this.AckIntents) != len(that1.AckIntents) {
+		return false
+	}
+	for i := range this.AckIntents {
+		if !bytes.Equal(this.AckIntents[i], that1.AckIntents[i]) {
+			return false
+		}
+	}
+	return true
+}
+func (this *Checkpoint_Source) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*Checkpoint_Source)
+	if !ok {
+		that2, ok := that.(Checkpoint_Source)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if this.ReadThrough != that1.ReadThrough {
+		return false
+	}
+	if len(this.Producers) != len(that1.Producers) {
+		return false
+	}
+	for i := range this.Producers {
+		if !this.Producers[i].Equal(&that1.Producers[i]) {
+			return false
+		}
+	}
+	return true
+}
+func (this *Checkpoint_Source_ProducerEntry) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*Checkpoint_Source_ProducerEntry)
+	if !ok {
+		that2, ok := that.(Checkpoint_Source_ProducerEntry)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if !bytes.Equal(this.Id, that1.Id) {
+		return false
+	}
+	if !this.State.Equal(&that1.State) {
+		return false
+	}
+	return true
+}
+func (this *Checkpoint_ProducerState) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*Checkpoint_ProducerState)
+	if !ok {
+		that2, ok := that.(Checkpoint_ProducerState)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if this.LastAck != that1.LastAck {
+		return false
+	}
+	if this.Begin != that1.Begin {
+		return false
+	}
+	return true
+}
 
 // Reference imports to suppress errors if they are not otherwise used.
 var _ context.Context
@@ -1474,6 +1859,9 @@ type ShardClient interface {
 	GetHints(ctx context.Context, in *GetHintsRequest, opts ...grpc.CallOption) (*GetHintsResponse, error)
 	// Unassign a Shard.
 	Unassign(ctx context.Context, in *UnassignRequest, opts ...grpc.CallOption) (*UnassignResponse, error)
+	// ResetCheckpoint of a Shard, which resumes reading its source journals
+	// from the given offsets upon its next assignment.
+	ResetCheckpoint(ctx context.Context, in *ResetCheckpointRequest, opts ...grpc.CallOption) (*ResetCheckpointResponse, error)
 }
 
 type shardClient struct {
@@ -1529,6 +1917,15 @@ func (c *shardClient) Unassign(ctx context.Context, in *UnassignRequest, opts ..
 	return out, nil
 }
 
+func (c *shardClient) ResetCheckpoint(ctx context.Context, in *ResetCheckpointRequest, opts ...grpc.CallOption) (*ResetCheckpointResponse, error) {
+	out := new(ResetCheckpointResponse)
+	err := c.cc.Invoke(ctx, "/consumer.Shard/ResetCheckpoint", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // ShardServer is the server API for Shard service.
 type ShardServer interface {
 	// Stat returns detailed status of a given Shard.
@@ -1541,6 +1938,9 @@ type ShardServer interface {
 	GetHints(context.Context, *GetHintsRequest) (*GetHintsResponse, error)
 	// Unassign a Shard.
 	Unassign(context.Context, *UnassignRequest) (*UnassignResponse, error)
+	// ResetCheckpoint of a Shard, which resumes reading its source journals
+	// from the given offsets upon its next assignment.
+	ResetCheckpoint(context.Context, *ResetCheckpointRequest) (*ResetCheckpointResponse, error)
 }
 
 // UnimplementedShardServer can be embedded to have forward compatible implementations.
@@ -1562,6 +1962,9 @@ func (*UnimplementedShardServer) GetHints(ctx context.Context, req *GetHintsRequ
 func (*UnimplementedShardServer) Unassign(ctx context.Context, req *UnassignRequest) (*UnassignResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Unassign not implemented")
 }
+func (*UnimplementedShardServer) ResetCheckpoint(ctx context.Context, req *ResetCheckpointRequest) (*ResetCheckpointResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ResetCheckpoint not implemented")
+}
 
 func RegisterShardServer(s *grpc.Server, srv ShardServer) {
 	s.RegisterService(&_Shard_serviceDesc, srv)
@@ -1657,6 +2060,24 @@ func _Shard_Unassign_Handler(srv interface{}, ctx context.Context, dec func(inte
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Shard_ResetCheckpoint_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResetCheckpointRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ShardServer).ResetCheckpoint(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/consumer.Shard/ResetCheckpoint",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ShardServer).ResetCheckpoint(ctx, req.(*ResetCheckpointRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _Shard_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "consumer.Shard",
 	HandlerType: (*ShardServer)(nil),
@@ -1681,6 +2102,10 @@ var _Shard_serviceDesc = grpc.ServiceDesc{
 			MethodName: "Unassign",
 			Handler:    _Shard_Unassign_Handler,
 		},
+		{
+			MethodName: "ResetCheckpoint",
+			Handler:    _Shard_ResetCheckpoint_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "consumer/protocol/protocol.proto",
@@ -1706,6 +2131,78 @@ func (m *ShardSpec) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if m.ResetCheckpoint != nil {
+		{
+			size, err := m.ResetCheckpoint.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintProtocol(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x1
+		i--
+		dAtA[i] = 0xaa
+	}
+	if m.Offloaded != nil {
+		{
+			size, err := m.Offloaded.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintProtocol(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x1
+		i--
+		dAtA[i] = 0xa2
+	}
+	n3, err3 := github_com_gogo_protobuf_types.StdDurationMarshalTo(m.OffloadAfterIdle, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdDuration(m.OffloadAfterIdle):])
+	if err3 != nil {
+		return 0, err3
+	}
+	i -= n3
+	i = encodeVarintProtocol(dAtA, i, uint64(n3))
+	i--
+	dAtA[i] = 0x1
+	i--
+	dAtA[i] = 0x9a
+	if m.TxnBatchBytes != 0 {
+		i = encodeVarintProtocol(dAtA, i, uint64(m.TxnBatchBytes))
+		i--
+		dAtA[i] = 0x1
+		i--
+		dAtA[i] = 0x90
+	}
+	if m.TxnBatchMessages != 0 {
+		i = encodeVarintProtocol(dAtA, i, uint64(m.TxnBatchMessages))
+		i--
+		dAtA[i] = 0x1
+		i--
+		dAtA[i] = 0x88
+	}
+	n4, err4 := github_com_gogo_protobuf_types.StdDurationMarshalTo(m.RecoveryLogRetention, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdDuration(m.RecoveryLogRetention):])
+	if err4 != nil {
+		return 0, err4
+	}
+	i -= n4
+	i = encodeVarintProtocol(dAtA, i, uint64(n4))
+	i--
+	dAtA[i] = 0x1
+	i--
+	dAtA[i] = 0x82
+	if m.StartTime != 0 {
+		i = encodeVarintProtocol(dAtA, i, uint64(m.StartTime))
+		i--
+		dAtA[i] = 0x78
+	}
+	if m.StartPolicy != 0 {
+		i = encodeVarintProtocol(dAtA, i, uint64(m.StartPolicy))
+		i--
+		dAtA[i] = 0x70
+	}
 	if m.ReadChannelSize != 0 {
 		i = encodeVarintProtocol(dAtA, i, uint64(m.ReadChannelSize))
 		i--
@@ -1751,20 +2248,20 @@ func (m *ShardSpec) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 		i--
 		dAtA[i] = 0x40
 	}
-	n2, err2 := github_com_gogo_protobuf_types.StdDurationMarshalTo(m.MinTxnDuration, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdDuration(m.MinTxnDuration):])
-	if err2 != nil {
-		return 0, err2
+	n6, err6 := github_com_gogo_protobuf_types.StdDurationMarshalTo(m.MinTxnDuration, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdDuration(m.MinTxnDuration):])
+	if err6 != nil {
+		return 0, err6
 	}
-	i -= n2
-	i = encodeVarintProtocol(dAtA, i, uint64(n2))
+	i -= n6
+	i = encodeVarintProtocol(dAtA, i, uint64(n6))
 	i--
 	dAtA[i] = 0x3a
-	n3, err3 := github_com_gogo_protobuf_types.StdDurationMarshalTo(m.MaxTxnDuration, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdDuration(m.MaxTxnDuration):])
-	if err3 != nil {
-		return 0, err3
+	n7, err7 := github_com_gogo_protobuf_types.StdDurationMarshalTo(m.MaxTxnDuration, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdDuration(m.MaxTxnDuration):])
+	if err7 != nil {
+		return 0, err7
 	}
-	i -= n3
-	i = encodeVarintProtocol(dAtA, i, uint64(n3))
+	i -= n7
+	i = encodeVarintProtocol(dAtA, i, uint64(n7))
 	i--
 	dAtA[i] = 0x32
 	if m.HintBackups != 0 {
@@ -1903,6 +2400,13 @@ func (m *ReplicaStatus) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if len(m.Unhealthy) > 0 {
+		i -= len(m.Unhealthy)
+		copy(dAtA[i:], m.Unhealthy)
+		i = encodeVarintProtocol(dAtA, i, uint64(len(m.Unhealthy)))
+		i--
+		dAtA[i] = 0x1a
+	}
 	if len(m.Errors) > 0 {
 		for iNdEx := len(m.Errors) - 1; iNdEx >= 0; iNdEx-- {
 			i -= len(m.Errors[iNdEx])
@@ -2438,6 +2942,16 @@ func (m *StatRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 		i--
 		dAtA[i] = 0xa2
 	}
+	if m.IncludeCheckpoint {
+		i--
+		if m.IncludeCheckpoint {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x20
+	}
 	if len(m.ReadThrough) > 0 {
 		for k := range m.ReadThrough {
 			v := m.ReadThrough[k]
@@ -2506,13 +3020,25 @@ func (m *StatResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 		i--
 		dAtA[i] = 0xa2
 	}
-	if len(m.PublishAt) > 0 {
-		for k := range m.PublishAt {
-			v := m.PublishAt[k]
-			baseI := i
-			i = encodeVarintProtocol(dAtA, i, uint64(v))
-			i--
-			dAtA[i] = 0x10
+	if m.Checkpoint != nil {
+		{
+			size, err := m.Checkpoint.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintProtocol(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x2a
+	}
+	if len(m.PublishAt) > 0 {
+		for k := range m.PublishAt {
+			v := m.PublishAt[k]
+			baseI := i
+			i = encodeVarintProtocol(dAtA, i, uint64(v))
+			i--
+			dAtA[i] = 0x10
 			i -= len(k)
 			copy(dAtA[i:], k)
 			i = encodeVarintProtocol(dAtA, i, uint64(len(k)))
@@ -2792,6 +3318,101 @@ func (m *UnassignResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	return len(dAtA) - i, nil
 }
 
+func (m *ResetCheckpointRequest) Marshal() (dAtA []byte, err error) {
+	size := m.ProtoSize()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *ResetCheckpointRequest) MarshalTo(dAtA []byte) (int, error) {
+	size := m.ProtoSize()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *ResetCheckpointRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.AllowStoreDivergence {
+		i--
+		if m.AllowStoreDivergence {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x18
+	}
+	if len(m.ReadThrough) > 0 {
+		for k := range m.ReadThrough {
+			v := m.ReadThrough[k]
+			baseI := i
+			i = encodeVarintProtocol(dAtA, i, uint64(v))
+			i--
+			dAtA[i] = 0x10
+			i -= len(k)
+			copy(dAtA[i:], k)
+			i = encodeVarintProtocol(dAtA, i, uint64(len(k)))
+			i--
+			dAtA[i] = 0xa
+			i = encodeVarintProtocol(dAtA, i, uint64(baseI-i))
+			i--
+			dAtA[i] = 0x12
+		}
+	}
+	if len(m.Shard) > 0 {
+		i -= len(m.Shard)
+		copy(dAtA[i:], m.Shard)
+		i = encodeVarintProtocol(dAtA, i, uint64(len(m.Shard)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *ResetCheckpointResponse) Marshal() (dAtA []byte, err error) {
+	size := m.ProtoSize()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *ResetCheckpointResponse) MarshalTo(dAtA []byte) (int, error) {
+	size := m.ProtoSize()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *ResetCheckpointResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	{
+		size, err := m.Header.MarshalToSizedBuffer(dAtA[:i])
+		if err != nil {
+			return 0, err
+		}
+		i -= size
+		i = encodeVarintProtocol(dAtA, i, uint64(size))
+	}
+	i--
+	dAtA[i] = 0x12
+	if m.Status != 0 {
+		i = encodeVarintProtocol(dAtA, i, uint64(m.Status))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
 func encodeVarintProtocol(dAtA []byte, offset int, v uint64) int {
 	offset -= sovProtocol(v)
 	base := offset
@@ -2851,6 +3472,30 @@ func (m *ShardSpec) ProtoSize() (n int) {
 	if m.ReadChannelSize != 0 {
 		n += 1 + sovProtocol(uint64(m.ReadChannelSize))
 	}
+	if m.StartPolicy != 0 {
+		n += 1 + sovProtocol(uint64(m.StartPolicy))
+	}
+	if m.StartTime != 0 {
+		n += 1 + sovProtocol(uint64(m.StartTime))
+	}
+	l = github_com_gogo_protobuf_types.SizeOfStdDuration(m.RecoveryLogRetention)
+	n += 2 + l + sovProtocol(uint64(l))
+	if m.TxnBatchMessages != 0 {
+		n += 2 + sovProtocol(uint64(m.TxnBatchMessages))
+	}
+	if m.TxnBatchBytes != 0 {
+		n += 2 + sovProtocol(uint64(m.TxnBatchBytes))
+	}
+	l = github_com_gogo_protobuf_types.SizeOfStdDuration(m.OffloadAfterIdle)
+	n += 2 + l + sovProtocol(uint64(l))
+	if m.Offloaded != nil {
+		l = m.Offloaded.ProtoSize()
+		n += 2 + l + sovProtocol(uint64(l))
+	}
+	if m.ResetCheckpoint != nil {
+		l = m.ResetCheckpoint.ProtoSize()
+		n += 2 + l + sovProtocol(uint64(l))
+	}
 	return n
 }
 
@@ -2899,6 +3544,10 @@ func (m *ReplicaStatus) ProtoSize() (n int) {
 			n += 1 + l + sovProtocol(uint64(l))
 		}
 	}
+	l = len(m.Unhealthy)
+	if l > 0 {
+		n += 1 + l + sovProtocol(uint64(l))
+	}
 	return n
 }
 
@@ -3120,6 +3769,9 @@ func (m *StatRequest) ProtoSize() (n int) {
 			n += mapEntrySize + 1 + sovProtocol(uint64(mapEntrySize))
 		}
 	}
+	if m.IncludeCheckpoint {
+		n += 2
+	}
 	l = len(m.Extension)
 	if l > 0 {
 		n += 2 + l + sovProtocol(uint64(l))
@@ -3154,6 +3806,10 @@ func (m *StatResponse) ProtoSize() (n int) {
 			n += mapEntrySize + 1 + sovProtocol(uint64(mapEntrySize))
 		}
 	}
+	if m.Checkpoint != nil {
+		l = m.Checkpoint.ProtoSize()
+		n += 1 + l + sovProtocol(uint64(l))
+	}
 	l = len(m.Extension)
 	if l > 0 {
 		n += 2 + l + sovProtocol(uint64(l))
@@ -3256,6 +3912,44 @@ func (m *UnassignResponse) ProtoSize() (n int) {
 	return n
 }
 
+func (m *ResetCheckpointRequest) ProtoSize() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Shard)
+	if l > 0 {
+		n += 1 + l + sovProtocol(uint64(l))
+	}
+	if len(m.ReadThrough) > 0 {
+		for k, v := range m.ReadThrough {
+			_ = k
+			_ = v
+			mapEntrySize := 1 + len(k) + sovProtocol(uint64(len(k))) + 1 + sovProtocol(uint64(v))
+			n += mapEntrySize + 1 + sovProtocol(uint64(mapEntrySize))
+		}
+	}
+	if m.AllowStoreDivergence {
+		n += 2
+	}
+	return n
+}
+
+func (m *ResetCheckpointResponse) ProtoSize() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Status != 0 {
+		n += 1 + sovProtocol(uint64(m.Status))
+	}
+	l = m.Header.ProtoSize()
+	n += 1 + l + sovProtocol(uint64(l))
+	return n
+}
+
 func sovProtocol(x uint64) (n int) {
 	return (math_bits.Len64(x|1) + 6) / 7
 }
@@ -3636,61 +4330,49 @@ func (m *ShardSpec) Unmarshal(dAtA []byte) error {
 					break
 				}
 			}
-		default:
-			iNdEx = preIndex
-			skippy, err := skipProtocol(dAtA[iNdEx:])
-			if err != nil {
-				return err
-			}
-			if (skippy < 0) || (iNdEx+skippy) < 0 {
-				return ErrInvalidLengthProtocol
-			}
-			if (iNdEx + skippy) > l {
-				return io.ErrUnexpectedEOF
+		case 14:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field StartPolicy", wireType)
 			}
-			iNdEx += skippy
-		}
-	}
-
-	if iNdEx > l {
-		return io.ErrUnexpectedEOF
-	}
-	return nil
-}
-func (m *ShardSpec_Source) Unmarshal(dAtA []byte) error {
-	l := len(dAtA)
-	iNdEx := 0
-	for iNdEx < l {
-		preIndex := iNdEx
-		var wire uint64
-		for shift := uint(0); ; shift += 7 {
-			if shift >= 64 {
-				return ErrIntOverflowProtocol
+			m.StartPolicy = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowProtocol
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.StartPolicy |= ShardSpec_StartPolicy(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
 			}
-			if iNdEx >= l {
-				return io.ErrUnexpectedEOF
+		case 15:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field StartTime", wireType)
 			}
-			b := dAtA[iNdEx]
-			iNdEx++
-			wire |= uint64(b&0x7F) << shift
-			if b < 0x80 {
-				break
+			m.StartTime = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowProtocol
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.StartTime |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
 			}
-		}
-		fieldNum := int32(wire >> 3)
-		wireType := int(wire & 0x7)
-		if wireType == 4 {
-			return fmt.Errorf("proto: Source: wiretype end group for non-group")
-		}
-		if fieldNum <= 0 {
-			return fmt.Errorf("proto: Source: illegal tag %d (wire type %d)", fieldNum, wire)
-		}
-		switch fieldNum {
-		case 1:
+		case 16:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Journal", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field RecoveryLogRetention", wireType)
 			}
-			var stringLen uint64
+			var msglen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowProtocol
@@ -3700,29 +4382,30 @@ func (m *ShardSpec_Source) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				stringLen |= uint64(b&0x7F) << shift
+				msglen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
+			if msglen < 0 {
 				return ErrInvalidLengthProtocol
 			}
-			postIndex := iNdEx + intStringLen
+			postIndex := iNdEx + msglen
 			if postIndex < 0 {
 				return ErrInvalidLengthProtocol
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Journal = go_gazette_dev_core_broker_protocol.Journal(dAtA[iNdEx:postIndex])
+			if err := github_com_gogo_protobuf_types.StdDurationUnmarshal(&m.RecoveryLogRetention, dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
 			iNdEx = postIndex
-		case 3:
+		case 17:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field MinOffset", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field TxnBatchMessages", wireType)
 			}
-			m.MinOffset = 0
+			m.TxnBatchMessages = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowProtocol
@@ -3732,24 +4415,249 @@ func (m *ShardSpec_Source) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.MinOffset |= go_gazette_dev_core_broker_protocol.Offset(b&0x7F) << shift
+				m.TxnBatchMessages |= uint32(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-		default:
-			iNdEx = preIndex
-			skippy, err := skipProtocol(dAtA[iNdEx:])
-			if err != nil {
-				return err
-			}
-			if (skippy < 0) || (iNdEx+skippy) < 0 {
-				return ErrInvalidLengthProtocol
-			}
-			if (iNdEx + skippy) > l {
-				return io.ErrUnexpectedEOF
+		case 18:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TxnBatchBytes", wireType)
 			}
-			iNdEx += skippy
+			m.TxnBatchBytes = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowProtocol
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.TxnBatchBytes |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 19:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field OffloadAfterIdle", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowProtocol
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthProtocol
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthProtocol
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if err := github_com_gogo_protobuf_types.StdDurationUnmarshal(&m.OffloadAfterIdle, dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 20:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Offloaded", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowProtocol
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthProtocol
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthProtocol
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Offloaded == nil {
+				m.Offloaded = &Checkpoint{}
+			}
+			if err := m.Offloaded.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 21:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ResetCheckpoint", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowProtocol
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthProtocol
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthProtocol
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.ResetCheckpoint == nil {
+				m.ResetCheckpoint = &Checkpoint{}
+			}
+			if err := m.ResetCheckpoint.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipProtocol(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthProtocol
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *ShardSpec_Source) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowProtocol
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Source: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Source: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Journal", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowProtocol
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthProtocol
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthProtocol
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Journal = go_gazette_dev_core_broker_protocol.Journal(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MinOffset", wireType)
+			}
+			m.MinOffset = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowProtocol
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.MinOffset |= go_gazette_dev_core_broker_protocol.Offset(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipProtocol(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthProtocol
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
 		}
 	}
 
@@ -3940,6 +4848,38 @@ func (m *ReplicaStatus) Unmarshal(dAtA []byte) error {
 			}
 			m.Errors = append(m.Errors, string(dAtA[iNdEx:postIndex]))
 			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Unhealthy", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowProtocol
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthProtocol
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthProtocol
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Unhealthy = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipProtocol(dAtA[iNdEx:])
@@ -5624,11 +6564,31 @@ func (m *StatRequest) Unmarshal(dAtA []byte) error {
 			}
 			m.ReadThrough[go_gazette_dev_core_broker_protocol.Journal(mapkey)] = ((go_gazette_dev_core_broker_protocol.Offset)(mapvalue))
 			iNdEx = postIndex
-		case 100:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Extension", wireType)
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field IncludeCheckpoint", wireType)
 			}
-			var byteLen int
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowProtocol
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.IncludeCheckpoint = bool(v != 0)
+		case 100:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Extension", wireType)
+			}
+			var byteLen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowProtocol
@@ -5986,6 +6946,42 @@ func (m *StatResponse) Unmarshal(dAtA []byte) error {
 			}
 			m.PublishAt[go_gazette_dev_core_broker_protocol.Journal(mapkey)] = ((go_gazette_dev_core_broker_protocol.Offset)(mapvalue))
 			iNdEx = postIndex
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Checkpoint", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowProtocol
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthProtocol
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthProtocol
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Checkpoint == nil {
+				m.Checkpoint = &Checkpoint{}
+			}
+			if err := m.Checkpoint.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
 		case 100:
 			if wireType != 2 {
 				return fmt.Errorf("proto: wrong wireType = %d for field Extension", wireType)
@@ -6669,6 +7665,323 @@ func (m *UnassignResponse) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
+func (m *ResetCheckpointRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowProtocol
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ResetCheckpointRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ResetCheckpointRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Shard", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowProtocol
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthProtocol
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthProtocol
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Shard = ShardID(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ReadThrough", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowProtocol
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthProtocol
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthProtocol
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.ReadThrough == nil {
+				m.ReadThrough = make(map[go_gazette_dev_core_broker_protocol.Journal]go_gazette_dev_core_broker_protocol.Offset)
+			}
+			var mapkey go_gazette_dev_core_broker_protocol.Journal
+			var mapvalue int64
+			for iNdEx < postIndex {
+				entryPreIndex := iNdEx
+				var wire uint64
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowProtocol
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					wire |= uint64(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				fieldNum := int32(wire >> 3)
+				if fieldNum == 1 {
+					var stringLenmapkey uint64
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowProtocol
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						stringLenmapkey |= uint64(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					intStringLenmapkey := int(stringLenmapkey)
+					if intStringLenmapkey < 0 {
+						return ErrInvalidLengthProtocol
+					}
+					postStringIndexmapkey := iNdEx + intStringLenmapkey
+					if postStringIndexmapkey < 0 {
+						return ErrInvalidLengthProtocol
+					}
+					if postStringIndexmapkey > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapkey = go_gazette_dev_core_broker_protocol.Journal(dAtA[iNdEx:postStringIndexmapkey])
+					iNdEx = postStringIndexmapkey
+				} else if fieldNum == 2 {
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowProtocol
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						mapvalue |= int64(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+				} else {
+					iNdEx = entryPreIndex
+					skippy, err := skipProtocol(dAtA[iNdEx:])
+					if err != nil {
+						return err
+					}
+					if (skippy < 0) || (iNdEx+skippy) < 0 {
+						return ErrInvalidLengthProtocol
+					}
+					if (iNdEx + skippy) > postIndex {
+						return io.ErrUnexpectedEOF
+					}
+					iNdEx += skippy
+				}
+			}
+			m.ReadThrough[go_gazette_dev_core_broker_protocol.Journal(mapkey)] = ((go_gazette_dev_core_broker_protocol.Offset)(mapvalue))
+			iNdEx = postIndex
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field AllowStoreDivergence", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowProtocol
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.AllowStoreDivergence = bool(v != 0)
+		default:
+			iNdEx = preIndex
+			skippy, err := skipProtocol(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthProtocol
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *ResetCheckpointResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowProtocol
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ResetCheckpointResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ResetCheckpointResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Status", wireType)
+			}
+			m.Status = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowProtocol
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Status |= Status(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Header", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowProtocol
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthProtocol
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthProtocol
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if err := m.Header.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipProtocol(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthProtocol
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
 func skipProtocol(dAtA []byte) (n int, err error) {
 	l := len(dAtA)
 	iNdEx := 0
@@ -171,3 +171,27 @@ func (m *UnassignResponse) Validate() error {
 	}
 	return nil
 }
+
+// Validate returns an error if the ResetCheckpointRequest is not well-formed.
+func (m *ResetCheckpointRequest) Validate() error {
+	if err := m.Shard.Validate(); err != nil {
+		return pb.ExtendContext(err, "Shard")
+	} else if len(m.ReadThrough) == 0 {
+		return pb.NewValidationError("expected at least one ReadThrough journal")
+	} else if err = pb.Offsets(m.ReadThrough).Validate(); err != nil {
+		return pb.ExtendContext(err, "ReadThrough")
+	} else if !m.AllowStoreDivergence {
+		return pb.NewValidationError("expected AllowStoreDivergence (a reset Checkpoint diverges from the shard's store)")
+	}
+	return nil
+}
+
+// Validate returns an error if the ResetCheckpointResponse is not well-formed.
+func (m *ResetCheckpointResponse) Validate() error {
+	if err := m.Status.Validate(); err != nil {
+		return pb.ExtendContext(err, "Status")
+	} else if err = m.Header.Validate(); err != nil {
+		return pb.ExtendContext(err, "Header")
+	}
+	return nil
+}
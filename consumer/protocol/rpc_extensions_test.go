@@ -170,6 +170,36 @@ func (s *RPCSuite) TestUnassignResponseValidationCases(c *gc.C) {
 	c.Check(resp.Validate(), gc.IsNil)
 }
 
+func (s *RPCSuite) TestResetCheckpointRequestValidationCases(c *gc.C) {
+	var req = ResetCheckpointRequest{
+		Shard: "",
+	}
+	c.Check(req.Validate(), gc.ErrorMatches, `Shard: invalid length .*`)
+	req.Shard = "a-shard"
+	c.Check(req.Validate(), gc.ErrorMatches, `expected at least one ReadThrough journal`)
+	req.ReadThrough = pb.Offsets{"a/journal": -1}
+	c.Check(req.Validate(), gc.ErrorMatches, `ReadThrough.Offsets\[a/journal\]: invalid offset \(-1; expected >= 0\)`)
+	req.ReadThrough["a/journal"] = 1234
+	c.Check(req.Validate(), gc.ErrorMatches, `expected AllowStoreDivergence .*`)
+	req.AllowStoreDivergence = true
+
+	c.Check(req.Validate(), gc.IsNil)
+}
+
+func (s *RPCSuite) TestResetCheckpointResponseValidationCases(c *gc.C) {
+	var resp = ResetCheckpointResponse{
+		Status: 9101,
+		Header: *badHeaderFixture(),
+	}
+
+	c.Check(resp.Validate(), gc.ErrorMatches, `Status: invalid status \(9101\)`)
+	resp.Status = Status_OK
+	c.Check(resp.Validate(), gc.ErrorMatches, `Header.Etcd: invalid ClusterId .*`)
+	resp.Header.Etcd.ClusterId = 1234
+
+	c.Check(resp.Validate(), gc.IsNil)
+}
+
 func badHeaderFixture() *pb.Header {
 	return &pb.Header{
 		ProcessId: pb.ProcessSpec_ID{Zone: "zone", Suffix: "name"},
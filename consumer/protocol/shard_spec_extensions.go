@@ -62,6 +62,18 @@ func (m *ShardSpec) Validate() error {
 		return pb.ExtendContext(err, "LabelSet")
 	} else if len(m.LabelSet.ValuesOf("id")) != 0 {
 		return pb.NewValidationError(`Labels cannot include label "id"`)
+	} else if err = m.StartPolicy.Validate(); err != nil {
+		return pb.ExtendContext(err, "StartPolicy")
+	} else if m.StartPolicy == ShardSpec_START_AT_TIME && m.StartTime <= 0 {
+		return pb.NewValidationError("invalid StartTime (%d; expected > 0 with START_AT_TIME)", m.StartTime)
+	} else if m.StartPolicy != ShardSpec_START_AT_TIME && m.StartTime != 0 {
+		return pb.NewValidationError("invalid non-zero StartTime (%d) without START_AT_TIME", m.StartTime)
+	} else if m.RecoveryLogRetention < 0 {
+		return pb.NewValidationError("invalid RecoveryLogRetention (%d; expected >= 0)", m.RecoveryLogRetention)
+	} else if m.TxnBatchBytes < 0 {
+		return pb.NewValidationError("invalid TxnBatchBytes (%d; expected >= 0)", m.TxnBatchBytes)
+	} else if m.OffloadAfterIdle < 0 {
+		return pb.NewValidationError("invalid OffloadAfterIdle (%d; expected >= 0)", m.OffloadAfterIdle)
 	}
 
 	for i := range m.Sources {
@@ -73,7 +85,18 @@ func (m *ShardSpec) Validate() error {
 		}
 	}
 
-	// HotStandbys, Disable, and DisableWaitForAck require no extra validation.
+	if m.ResetCheckpoint != nil {
+		for journal, src := range m.ResetCheckpoint.Sources {
+			if err := journal.Validate(); err != nil {
+				return pb.ExtendContext(err, "ResetCheckpoint.Sources[%s]", journal)
+			} else if src.ReadThrough < 0 {
+				return pb.NewValidationError("invalid ResetCheckpoint.Sources[%s].ReadThrough (%d; expected >= 0)",
+					journal, src.ReadThrough)
+			}
+		}
+	}
+
+	// HotStandbys, Disable, DisableWaitForAck, and Offloaded require no extra validation.
 
 	return nil
 }
@@ -88,6 +111,14 @@ func (m *ShardSpec_Source) Validate() error {
 	return nil
 }
 
+// Validate returns an error if the ShardSpec_StartPolicy is not a known value.
+func (m ShardSpec_StartPolicy) Validate() error {
+	if _, ok := ShardSpec_StartPolicy_name[int32(m)]; !ok {
+		return pb.NewValidationError("invalid value (%s)", m)
+	}
+	return nil
+}
+
 // MarshalString returns the marshaled encoding of the ShardSpec as a string.
 func (m *ShardSpec) MarshalString() string {
 	var d, err = m.Marshal()
@@ -98,8 +129,9 @@ func (m *ShardSpec) MarshalString() string {
 }
 
 // DesiredReplication is the desired number of shard replicas. allocator.ItemValue implementation.
+// A disabled or offloaded shard desires no replicas.
 func (m *ShardSpec) DesiredReplication() int {
-	if m.Disable {
+	if m.Disable || m.Offloaded != nil {
 		return 0
 	}
 	if MaxHotStandbys < m.HotStandbys {
@@ -168,6 +200,30 @@ func UnionShardSpecs(a, b ShardSpec) ShardSpec {
 	if a.ReadChannelSize == 0 {
 		a.ReadChannelSize = b.ReadChannelSize
 	}
+	if a.StartPolicy == ShardSpec_START_AT_MIN_OFFSET {
+		a.StartPolicy = b.StartPolicy
+	}
+	if a.StartTime == 0 {
+		a.StartTime = b.StartTime
+	}
+	if a.RecoveryLogRetention == 0 {
+		a.RecoveryLogRetention = b.RecoveryLogRetention
+	}
+	if a.TxnBatchMessages == 0 {
+		a.TxnBatchMessages = b.TxnBatchMessages
+	}
+	if a.TxnBatchBytes == 0 {
+		a.TxnBatchBytes = b.TxnBatchBytes
+	}
+	if a.OffloadAfterIdle == 0 {
+		a.OffloadAfterIdle = b.OffloadAfterIdle
+	}
+	if a.Offloaded == nil {
+		a.Offloaded = b.Offloaded
+	}
+	if a.ResetCheckpoint == nil {
+		a.ResetCheckpoint = b.ResetCheckpoint
+	}
 	return a
 }
 
@@ -209,6 +265,30 @@ func IntersectShardSpecs(a, b ShardSpec) ShardSpec {
 	if a.ReadChannelSize != b.ReadChannelSize {
 		a.ReadChannelSize = 0
 	}
+	if a.StartPolicy != b.StartPolicy {
+		a.StartPolicy = ShardSpec_START_AT_MIN_OFFSET
+	}
+	if a.StartTime != b.StartTime {
+		a.StartTime = 0
+	}
+	if a.RecoveryLogRetention != b.RecoveryLogRetention {
+		a.RecoveryLogRetention = 0
+	}
+	if a.TxnBatchMessages != b.TxnBatchMessages {
+		a.TxnBatchMessages = 0
+	}
+	if a.TxnBatchBytes != b.TxnBatchBytes {
+		a.TxnBatchBytes = 0
+	}
+	if a.OffloadAfterIdle != b.OffloadAfterIdle {
+		a.OffloadAfterIdle = 0
+	}
+	if !a.Offloaded.Equal(b.Offloaded) {
+		a.Offloaded = nil
+	}
+	if !a.ResetCheckpoint.Equal(b.ResetCheckpoint) {
+		a.ResetCheckpoint = nil
+	}
 	return a
 }
 
@@ -250,6 +330,30 @@ func SubtractShardSpecs(a, b ShardSpec) ShardSpec {
 	if a.ReadChannelSize == b.ReadChannelSize {
 		a.ReadChannelSize = 0
 	}
+	if a.StartPolicy == b.StartPolicy {
+		a.StartPolicy = ShardSpec_START_AT_MIN_OFFSET
+	}
+	if a.StartTime == b.StartTime {
+		a.StartTime = 0
+	}
+	if a.RecoveryLogRetention == b.RecoveryLogRetention {
+		a.RecoveryLogRetention = 0
+	}
+	if a.TxnBatchMessages == b.TxnBatchMessages {
+		a.TxnBatchMessages = 0
+	}
+	if a.TxnBatchBytes == b.TxnBatchBytes {
+		a.TxnBatchBytes = 0
+	}
+	if a.OffloadAfterIdle == b.OffloadAfterIdle {
+		a.OffloadAfterIdle = 0
+	}
+	if a.Offloaded.Equal(b.Offloaded) {
+		a.Offloaded = nil
+	}
+	if a.ResetCheckpoint.Equal(b.ResetCheckpoint) {
+		a.ResetCheckpoint = nil
+	}
 	return a
 }
 
@@ -291,6 +395,12 @@ func (m *ReplicaStatus) Reduce(other *ReplicaStatus) {
 	for _, e := range other.Errors {
 		m.Errors = append(m.Errors, e)
 	}
+	// Unhealthy is retained only while the reduced status is PRIMARY.
+	if m.Code != ReplicaStatus_PRIMARY {
+		m.Unhealthy = ""
+	} else if m.Unhealthy == "" {
+		m.Unhealthy = other.Unhealthy
+	}
 }
 
 // Validate returns an error if the ReplicaStatus is not well-formed.
@@ -306,6 +416,9 @@ func (m *ReplicaStatus) Validate() error {
 	} else if m.Code != ReplicaStatus_FAILED {
 		return pb.NewValidationError("expected Code FAILED with non-empty Errors")
 	}
+	if m.Unhealthy != "" && m.Code != ReplicaStatus_PRIMARY {
+		return pb.NewValidationError("expected Code PRIMARY with non-empty Unhealthy")
+	}
 
 	return nil
 }
@@ -73,6 +73,22 @@ func (s *SpecSuite) TestShardSpecValidationCases(c *gc.C) {
 	spec.LabelSet = pb.MustLabelSet("id", "") // Label is rejected even if empty.
 	c.Check(spec.Validate(), gc.ErrorMatches, `Labels cannot include label "id"`)
 	spec.LabelSet = pb.MustLabelSet(labels.Instance, "an-instance", labels.ManagedBy, "a-tool")
+	spec.StartPolicy = 12
+	c.Check(spec.Validate(), gc.ErrorMatches, `StartPolicy: invalid value \(12\)`)
+	spec.StartPolicy = ShardSpec_START_AT_TIME
+	c.Check(spec.Validate(), gc.ErrorMatches, `invalid StartTime \(0; expected > 0 with START_AT_TIME\)`)
+	spec.StartPolicy, spec.StartTime = ShardSpec_START_AT_HEAD, 1234
+	c.Check(spec.Validate(), gc.ErrorMatches, `invalid non-zero StartTime \(1234\) without START_AT_TIME`)
+	spec.StartPolicy = ShardSpec_START_AT_TIME
+	spec.RecoveryLogRetention = -time.Second
+	c.Check(spec.Validate(), gc.ErrorMatches, `invalid RecoveryLogRetention \(-1000000000; expected >= 0\)`)
+	spec.RecoveryLogRetention = time.Hour
+	spec.TxnBatchBytes = -1
+	c.Check(spec.Validate(), gc.ErrorMatches, `invalid TxnBatchBytes \(-1; expected >= 0\)`)
+	spec.TxnBatchBytes = 0
+	spec.OffloadAfterIdle = -time.Second
+	c.Check(spec.Validate(), gc.ErrorMatches, `invalid OffloadAfterIdle \(-1000000000; expected >= 0\)`)
+	spec.OffloadAfterIdle = time.Hour
 
 	c.Check(spec.Validate(), gc.ErrorMatches, `Sources\[0\].Journal: not a valid token \(journal 2\)`)
 	spec.Sources[0].Journal = "journal/2"
@@ -81,6 +97,14 @@ func (s *SpecSuite) TestShardSpecValidationCases(c *gc.C) {
 	c.Check(spec.Validate(), gc.ErrorMatches, `Sources.Journal not in unique, sorted order \(index 1; journal/1 <= journal/2\)`)
 	spec.Sources[0], spec.Sources[1] = spec.Sources[1], spec.Sources[0]
 
+	spec.ResetCheckpoint = &Checkpoint{
+		Sources: map[pb.Journal]Checkpoint_Source{"journal 1": {ReadThrough: 1}},
+	}
+	c.Check(spec.Validate(), gc.ErrorMatches, `ResetCheckpoint.Sources\[journal 1\]: not a valid token \(journal 1\)`)
+	spec.ResetCheckpoint.Sources = map[pb.Journal]Checkpoint_Source{"journal/1": {ReadThrough: -1}}
+	c.Check(spec.Validate(), gc.ErrorMatches, `invalid ResetCheckpoint.Sources\[journal/1\].ReadThrough \(-1; expected >= 0\)`)
+	spec.ResetCheckpoint.Sources = map[pb.Journal]Checkpoint_Source{"journal/1": {ReadThrough: 2048}}
+
 	c.Check(spec.Validate(), gc.IsNil)
 }
 
@@ -101,7 +125,9 @@ func (s *SpecSuite) TestShardSpecRoutines(c *gc.C) {
 
 	spec.Disable = true
 	c.Check(spec.DesiredReplication(), gc.Equals, 0)
-	spec.Disable, spec.HotStandbys = false, 0
+	spec.Disable, spec.Offloaded = false, new(Checkpoint)
+	c.Check(spec.DesiredReplication(), gc.Equals, 0)
+	spec.Offloaded, spec.HotStandbys = nil, 0
 	c.Check(spec.DesiredReplication(), gc.Equals, 1)
 
 	c.Check(ExtractShardSpecMetaLabels(&spec, pb.MustLabelSet("label", "buffer")),
@@ -136,6 +162,19 @@ func (s *SpecSuite) TestSetOperations(c *gc.C) {
 		DisableWaitForAck: true,
 		RingBufferSize:    123,
 		ReadChannelSize:   456,
+		StartPolicy:       ShardSpec_START_AT_TIME,
+		StartTime:         1234,
+
+		RecoveryLogRetention: time.Hour,
+		TxnBatchMessages:     100,
+		TxnBatchBytes:        1 << 20,
+		OffloadAfterIdle:     time.Minute,
+		Offloaded: &Checkpoint{
+			Sources: map[pb.Journal]Checkpoint_Source{"a/source": {ReadThrough: 1234}},
+		},
+		ResetCheckpoint: &Checkpoint{
+			Sources: map[pb.Journal]Checkpoint_Source{"a/source": {ReadThrough: 12}},
+		},
 	}
 	var other = ShardSpec{
 		Sources: []ShardSpec_Source{
@@ -158,6 +197,19 @@ func (s *SpecSuite) TestSetOperations(c *gc.C) {
 		DisableWaitForAck: false,
 		RingBufferSize:    456,
 		ReadChannelSize:   789,
+		StartPolicy:       ShardSpec_START_AT_HEAD,
+		StartTime:         5678,
+
+		RecoveryLogRetention: 24 * time.Hour,
+		TxnBatchMessages:     200,
+		TxnBatchBytes:        1 << 21,
+		OffloadAfterIdle:     time.Hour,
+		Offloaded: &Checkpoint{
+			Sources: map[pb.Journal]Checkpoint_Source{"other/source": {ReadThrough: 5678}},
+		},
+		ResetCheckpoint: &Checkpoint{
+			Sources: map[pb.Journal]Checkpoint_Source{"other/source": {ReadThrough: 56}},
+		},
 	}
 
 	c.Check(UnionShardSpecs(ShardSpec{}, model), gc.DeepEquals, model)
@@ -214,6 +266,11 @@ func (s *SpecSuite) TestReplicaStatusValidationCases(c *gc.C) {
 
 	status.Errors = []string{"error!"}
 	c.Check(status.Validate(), gc.IsNil)
+
+	status.Unhealthy = "unhealthy!"
+	c.Check(status.Validate(), gc.ErrorMatches, `expected Code PRIMARY with non-empty Unhealthy`)
+	status.Errors, status.Code = nil, ReplicaStatus_PRIMARY
+	c.Check(status.Validate(), gc.IsNil)
 }
 
 func (s *SpecSuite) TestReplicaStatusReduction(c *gc.C) {
@@ -225,6 +282,10 @@ func (s *SpecSuite) TestReplicaStatusReduction(c *gc.C) {
 	status.Reduce(&ReplicaStatus{Code: ReplicaStatus_BACKFILL})
 	c.Check(status, gc.DeepEquals, &ReplicaStatus{Code: ReplicaStatus_STANDBY})
 
+	// Unhealthy is reduced from a PRIMARY, and is dropped if a replica FAILED.
+	status.Reduce(&ReplicaStatus{Code: ReplicaStatus_PRIMARY, Unhealthy: "unhealthy!"})
+	c.Check(status, gc.DeepEquals, &ReplicaStatus{Code: ReplicaStatus_PRIMARY, Unhealthy: "unhealthy!"})
+
 	// Multiple errors are accumulated.
 	status.Reduce(&ReplicaStatus{Code: ReplicaStatus_FAILED, Errors: []string{"err-1"}})
 	status.Reduce(&ReplicaStatus{Code: ReplicaStatus_FAILED, Errors: []string{"err-2"}})
@@ -12,6 +12,7 @@ import (
 	log "github.com/sirupsen/logrus"
 	"go.etcd.io/etcd/api/v3/v3rpc/rpctypes"
 	clientv3 "go.etcd.io/etcd/client/v3"
+	"go.gazette.dev/core/allocator"
 	"go.gazette.dev/core/broker/client"
 	pb "go.gazette.dev/core/broker/protocol"
 	pc "go.gazette.dev/core/consumer/protocol"
@@ -283,6 +284,25 @@ func completeRecovery(s *shard) (_ pc.Checkpoint, err error) {
 		return cp, errors.WithMessage(err, "store.RestoreCheckpoint")
 	}
 
+	// If the Application orders messages by event time or merges its sources,
+	// restore messages which were buffered as of the Checkpoint.
+	if s.buffer, err = newShardMessageBuffer(s); err != nil {
+		return cp, err
+	} else if s.buffer != nil {
+		if err = s.buffer.restore(cp); err != nil {
+			return cp, errors.WithMessage(err, "buffer.restore")
+		}
+	}
+
+	// If the ShardSpec resets source offsets, apply them to the restored
+	// Checkpoint and commit it before processing begins.
+	var reset = s.Spec().ResetCheckpoint
+	if reset != nil {
+		if cp, err = applyResetCheckpoint(s, cp, reset); err != nil {
+			return cp, errors.WithMessage(err, "applying ResetCheckpoint")
+		}
+	}
+
 	// Store |recoveredHints| as a backup. We do this _after_ restoring the
 	// checkpoint as a sanity check, so that any integrity issues encountered
 	// during checkpoint recovery are surfaced before we over-write backup hints.
@@ -314,14 +334,110 @@ func completeRecovery(s *shard) (_ pc.Checkpoint, err error) {
 		}
 	}
 
+	// Now that the reset Checkpoint is committed, clear it from the ShardSpec
+	// so that it's not applied again by a future primary.
+	for attempt := 0; reset != nil; attempt++ {
+		if err = clearResetCheckpoint(s, reset); err == nil {
+			break
+		}
+		log.WithFields(log.Fields{
+			"attempt": attempt,
+			"err":     err,
+			"shard":   s.FQN(),
+		}).Warn("failed to clear ResetCheckpoint (will retry)")
+
+		select {
+		case <-s.ctx.Done():
+			return cp, s.ctx.Err()
+		case <-time.After(backoff(attempt)):
+			// Pass.
+		}
+	}
+
 	// Update read progress to reflect the restored checkpoint. Note that when we
 	// close |storeReadyCh| in just a moment, concurrent Stat RPCs may begin
 	// accessing |progress| (but not before).
 	for j, src := range cp.Sources {
 		s.progress.readThrough[j] = src.ReadThrough
 	}
+	s.progress.checkpoint = cp
 
 	close(s.storeReadyCh) // Unblocks Resolve().
 
 	return cp, nil
 }
+
+// applyResetCheckpoint returns the restored Checkpoint |cp| updated with the
+// source offsets of |reset|, after committing it to the shard's Store.
+// Producer states of reset sources are discarded, as they no longer reflect
+// the messages which have been read. Other sources and AckIntents are retained.
+// So are buffered messages of other sources, which are persisted alongside
+// the committed Checkpoint.
+func applyResetCheckpoint(s *shard, cp pc.Checkpoint, reset *pc.Checkpoint) (pc.Checkpoint, error) {
+	var sources = make(map[pb.Journal]pc.Checkpoint_Source, len(cp.Sources))
+	var journals []pb.Journal
+
+	for journal, src := range cp.Sources {
+		sources[journal] = src
+	}
+	for journal, src := range reset.Sources {
+		sources[journal] = pc.Checkpoint_Source{ReadThrough: src.ReadThrough}
+		journals = append(journals, journal)
+	}
+	cp.Sources = sources
+
+	if s.buffer != nil {
+		s.buffer.discard(journals)
+
+		if err := s.buffer.persist(cp); err != nil {
+			return cp, errors.WithMessage(err, "buffer.persist")
+		}
+	}
+	if err := s.store.StartCommit(s, cp, nil).Err(); err != nil {
+		return cp, errors.WithMessage(err, "store.StartCommit")
+	}
+	if s.buffer != nil {
+		if err := s.buffer.committed(cp); err != nil {
+			return cp, errors.WithMessage(err, "buffer.committed")
+		}
+	}
+	log.WithFields(log.Fields{
+		"shard": s.FQN(),
+		"reset": reset.Sources,
+	}).Info("applied ResetCheckpoint of shard")
+
+	return cp, nil
+}
+
+// clearResetCheckpoint clears the ResetCheckpoint of the ShardSpec in a
+// checked transaction which verifies we're still primary. It's a no-op if
+// the ShardSpec's ResetCheckpoint no longer equals |reset|.
+func clearResetCheckpoint(s *shard, reset *pc.Checkpoint) error {
+	var ks = s.svc.State.KS
+	ks.Mu.RLock()
+	var item, ok = lookupShardSpec(ks, s.Spec().Id)
+	ks.Mu.RUnlock()
+
+	if !ok {
+		return errors.New("ShardSpec not found")
+	}
+	var spec = *item.Decoded.(allocator.Item).ItemValue.(*pc.ShardSpec)
+	var asn = s.Assignment()
+
+	if !spec.ResetCheckpoint.Equal(reset) {
+		return nil // Changed since we applied it.
+	}
+	spec.ResetCheckpoint = nil
+
+	var resp, err = s.svc.Etcd.Txn(s.ctx).
+		// Verify the ShardSpec is unchanged, and that we're still primary.
+		If(clientv3.Compare(clientv3.ModRevision(string(item.Raw.Key)), "=", item.Raw.ModRevision),
+			clientv3.Compare(clientv3.CreateRevision(string(asn.Raw.Key)), "=", asn.Raw.CreateRevision)).
+		Then(clientv3.OpPut(string(item.Raw.Key), spec.MarshalString())).
+		Commit()
+
+	if err == nil && !resp.Succeeded {
+		err = errors.New("transaction failed")
+	}
+	return err
+}
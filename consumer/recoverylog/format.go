@@ -0,0 +1,182 @@
+package recoverylog
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"sync"
+	"sync/atomic"
+
+	"github.com/pkg/errors"
+	"go.gazette.dev/core/message"
+)
+
+// FormatID identifies the RecordFormat of a frame of the recovery log. Each
+// frame begins with an 8-byte header, being its 4-byte FormatID followed by
+// the 4-byte little-endian length of the encoded frame which follows.
+//
+// The FormatID of ProtoFormat is message.FixedFrameWord, and a recovery log
+// written only with ProtoFormat is a message.FixedFraming of RecordedOps.
+// Logs written prior to the introduction of RecordFormats are thus logs of
+// ProtoFormat.
+type FormatID [4]byte
+
+// String returns the FormatID as a hex string.
+func (id FormatID) String() string { return fmt.Sprintf("%x", id[:]) }
+
+// RecordFormat encodes and decodes recovery log frames. A frame encodes a
+// RecordedOp together with the content of a recorded write, allowing
+// a RecordFormat to compress or encrypt all data of the recovery log.
+//
+// ProtoFormat is the exception: for compatibility with logs written prior to
+// the introduction of RecordFormats, its frames encode only the RecordedOp,
+// and the content of a recorded write follows its frame as-is.
+//
+// Formats are registered with RegisterFormat, and a Recorder encodes with
+// a single RecordFormat at a time (see Recorder.SetFormat). Players decode
+// each frame using the registered RecordFormat of its FormatID, and logs may
+// therefore transition between formats at any frame boundary, such as after
+// an upgrade. A Player must have registered every RecordFormat of the log.
+type RecordFormat interface {
+	// AppendFrame appends the encoding of |op| and its written |content|
+	// (which is empty unless |op| is a Write) to |b|, and returns the result.
+	AppendFrame(b []byte, op *RecordedOp, content []byte) ([]byte, error)
+	// DecodeFrame decodes the encoding |b| into |op|, and returns its written
+	// content. |b| may be retained only until DecodeFrame returns, but the
+	// returned content may reference |b|.
+	DecodeFrame(b []byte, op *RecordedOp) (content []byte, err error)
+}
+
+var (
+	// ProtoFormatID is the FormatID of ProtoFormat, the default RecordFormat.
+	ProtoFormatID = FormatID(message.FixedFrameWord)
+	// ProtoFormat is the RecordFormat of RecordedOps in their protobuf encoding.
+	ProtoFormat RecordFormat = protoFormat{}
+)
+
+// RegisterFormat registers the RecordFormat of FormatID |id|. It panics if
+// |id| is already registered. Formats are typically registered from an init
+// function, as a Player can't decode a frame of an unregistered FormatID.
+func RegisterFormat(id FormatID, format RecordFormat) {
+	formats.mu.Lock()
+	defer formats.mu.Unlock()
+
+	var prev = formats.m.Load().(map[FormatID]RecordFormat)
+	if _, ok := prev[id]; ok {
+		panic(fmt.Sprintf("recovery log format %s is already registered", id))
+	}
+	// Copy-on-write, so that lookups of each frame's format needn't lock.
+	var next = make(map[FormatID]RecordFormat, len(prev)+1)
+	for k, v := range prev {
+		next[k] = v
+	}
+	next[id] = format
+	formats.m.Store(next)
+}
+
+// LookupFormat returns the registered RecordFormat of |id|, or nil if
+// there is none.
+func LookupFormat(id FormatID) RecordFormat {
+	return formats.m.Load().(map[FormatID]RecordFormat)[id]
+}
+
+// frameHeaderLength is the length of the header of each recovery log frame.
+const frameHeaderLength = 8
+
+// appendFrame appends a frame of |op| and its written |content|, encoded with
+// |format| of FormatID |id|. If |format| is ProtoFormat, |content| is not
+// appended and must instead be written following the frame.
+func appendFrame(b []byte, id FormatID, format RecordFormat, op *RecordedOp, content []byte) ([]byte, error) {
+	var offset = len(b)
+	b = append(b, id[:]...)
+	b = append(b, 0, 0, 0, 0) // Length placeholder.
+
+	var err error
+	if b, err = format.AppendFrame(b, op, content); err != nil {
+		return nil, err
+	}
+	binary.LittleEndian.PutUint32(b[offset+4:], uint32(len(b)-offset-frameHeaderLength))
+	return b, nil
+}
+
+// unpackFrame returns the next frame of the Reader, including its header, and
+// the RecordFormat of the frame. If the frame header doesn't begin with a
+// registered FormatID (indicating a de-sync), unpackFrame discards through to
+// the next registered FormatID, returning the interleaved but de-synchronized
+// content along with message.ErrDesyncDetected.
+func unpackFrame(r *bufio.Reader) (RecordFormat, []byte, error) {
+	var registered = formats.m.Load().(map[FormatID]RecordFormat)
+	var b, err = r.Peek(frameHeaderLength)
+
+	if err != nil {
+		// If we read at least one byte, then an EOF is unexpected (it should
+		// occur only on frame boundaries).
+		if len(b) != 0 && err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		if err != io.EOF {
+			err = errors.Wrap(err, "Peek(frameHeaderLength)")
+		}
+		return nil, nil, err
+	}
+
+	var format = registered[FormatID{b[0], b[1], b[2], b[3]}]
+	if format == nil {
+		// Scan forward within the buffered region to the next registered FormatID.
+		b, _ = r.Peek(r.Buffered())
+
+		var i, j = 1, 1 + len(b) - len(FormatID{})
+		for ; i != j; i++ {
+			if registered[FormatID{b[i], b[i+1], b[i+2], b[i+3]}] != nil {
+				break
+			}
+		}
+		_, _ = r.Discard(i)
+		return nil, b[:i], message.ErrDesyncDetected
+	}
+
+	var size = frameHeaderLength + int(binary.LittleEndian.Uint32(b[4:]))
+
+	// Fast path: return the buffered frame without copying. It's
+	// invalidated by the next Reader operation.
+	if b, err = r.Peek(size); err == nil {
+		_, _ = r.Discard(size)
+		return format, b, nil
+	}
+
+	// Slow path: allocate and read the full frame.
+	b = make([]byte, size)
+	if _, err = io.ReadFull(r, b); err == nil {
+		return format, b, nil
+	} else if err == io.EOF {
+		err = io.ErrUnexpectedEOF // Always unexpected (having read a header).
+	}
+	return nil, b, errors.Wrapf(err, "reading frame (size %d)", size)
+}
+
+// protoFormat encodes only the RecordedOp. Written content follows its frame.
+type protoFormat struct{}
+
+func (protoFormat) AppendFrame(b []byte, op *RecordedOp, _ []byte) ([]byte, error) {
+	var offset = len(b)
+	b = append(b, make([]byte, op.ProtoSize())...)
+
+	if _, err := op.MarshalTo(b[offset:]); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+func (protoFormat) DecodeFrame(b []byte, op *RecordedOp) ([]byte, error) {
+	return nil, op.Unmarshal(b)
+}
+
+var formats struct {
+	m  atomic.Value // map[FormatID]RecordFormat, which is never mutated.
+	mu sync.Mutex   // Serializes RegisterFormat.
+}
+
+func init() {
+	formats.m.Store(map[FormatID]RecordFormat{ProtoFormatID: ProtoFormat})
+}
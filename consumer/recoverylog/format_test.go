@@ -0,0 +1,167 @@
+package recoverylog
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"regexp"
+
+	"go.gazette.dev/core/broker/client"
+	pb "go.gazette.dev/core/broker/protocol"
+	"go.gazette.dev/core/message"
+	gc "gopkg.in/check.v1"
+)
+
+type FormatSuite struct{}
+
+func (s *FormatSuite) TestFrameRoundTripAcrossFormats(c *gc.C) {
+	var frame = func(id FormatID, op RecordedOp, content []byte) []byte {
+		var b, err = appendFrame(nil, id, LookupFormat(id), &op, content)
+		c.Assert(err, gc.IsNil)
+		return b
+	}
+	// The ProtoFormat is exactly the fixed protobuf framing of RecordedOps,
+	// and doesn't include written content.
+	var op = newWriteOp(123, 0, 6)
+	var b, _ = message.EncodeFixedProtoFrame(&op, nil)
+	c.Check(frame(ProtoFormatID, op, []byte("hello!")), gc.DeepEquals, b)
+
+	// Other formats encode written content within the frame.
+	c.Check(bytes.Contains(frame(jsonFormatID, op, []byte("hello!")), []byte("hello!")), gc.Equals, false)
+
+	// Case: a stream transitions between formats, with an interleaved,
+	// de-synchronized write.
+	var parts = [][]byte{
+		frame(ProtoFormatID, newCreateOp("/a/path"), nil),
+		frame(jsonFormatID, newWriteOp(123, 0, 6), []byte("hello!")),
+		[]byte("... invalid data ..."),
+		frame(jsonFormatID, newCreateOp("/other/path"), nil),
+		frame(jsonFormatID, newWriteOp(123, 6, 0), nil),
+		frame(jsonFormatID, newWriteOp(123, 6, 3), []byte("!")),
+		frame(ProtoFormatID, newLinkOp(123, "/fin"), nil),
+	}
+	var expect = []struct {
+		err     error
+		op      RecordedOp
+		content []byte
+	}{
+		{op: newCreateOp("/a/path")},
+		{op: newWriteOp(123, 0, 6), content: []byte("hello!")},
+		{err: message.ErrDesyncDetected},
+		{op: newCreateOp("/other/path")},
+		{op: newWriteOp(123, 6, 0), content: []byte{}},
+		{err: errors.New("decoded frame content length (1) doesn't match Write.Length (3)")},
+		{op: newLinkOp(123, "/fin")},
+	}
+	var br = bufio.NewReader(bytes.NewReader(bytes.Join(parts, nil)))
+
+	var offset int64
+	for i, exp := range expect {
+		var op, frame, content, err = decodeOperation(br, aRecoveryLog, offset)
+		c.Check(frame, gc.DeepEquals, parts[i])
+
+		if exp.err == nil || err == nil {
+			c.Check(err, gc.Equals, exp.err)
+		} else {
+			c.Check(err, gc.ErrorMatches, regexp.QuoteMeta(exp.err.Error()))
+		}
+		c.Check(content, gc.DeepEquals, exp.content)
+
+		if exp.err == nil {
+			c.Check(op.Create, gc.DeepEquals, exp.op.Create)
+			c.Check(op.Write, gc.DeepEquals, exp.op.Write)
+			c.Check(op.Link, gc.DeepEquals, exp.op.Link)
+		}
+		offset += int64(len(parts[i]))
+	}
+}
+
+func (s *FormatSuite) TestRegistration(c *gc.C) {
+	c.Check(LookupFormat(ProtoFormatID), gc.Equals, ProtoFormat)
+	c.Check(LookupFormat(jsonFormatID), gc.Equals, jsonFormat{})
+	c.Check(LookupFormat(FormatID{1, 2, 3, 4}), gc.IsNil)
+
+	c.Check(func() { RegisterFormat(ProtoFormatID, jsonFormat{}) }, gc.PanicMatches,
+		"recovery log format 66339336 is already registered")
+}
+
+func (s *FormatSuite) TestPlayWithFormatTransition(c *gc.C) {
+	var broker, cleanup = newBrokerAndLog(c)
+	defer cleanup()
+
+	var ctx = context.Background()
+	var rjc = pb.NewRoutedJournalClient(broker.Client(), pb.NoopDispatchRouter{})
+	var ajc = client.NewAppendService(ctx, rjc)
+
+	var dir, err = ioutil.TempDir("", "format-suite")
+	c.Assert(err, gc.IsNil)
+	defer os.RemoveAll(dir)
+
+	fsm, err := NewFSM(FSMHints{Log: aRecoveryLog})
+	c.Assert(err, gc.IsNil)
+
+	// Record with the default ProtoFormat.
+	var rec = NewRecorder(aRecoveryLog, fsm, anAuthor, "/strip", ajc)
+	var f = FileRecorder{Recorder: rec, Fnode: rec.RecordCreate("/strip/foo")}
+	f.RecordWrite([]byte("hello"))
+	var hints, _ = rec.BuildHints()
+
+	// Transition formats mid-stream, as might happen after an upgrade.
+	c.Check(rec.SetFormat(FormatID{1, 2, 3, 4}), gc.ErrorMatches,
+		"recovery log format 01020304 is not registered")
+	c.Check(rec.SetFormat(jsonFormatID), gc.IsNil)
+
+	f.RecordWrite([]byte(" world"))
+	(&FileRecorder{Recorder: rec, Fnode: rec.RecordCreate("/strip/bar")}).
+		RecordWrite([]byte("bing"))
+
+	// And transition back again.
+	c.Check(rec.SetFormat(ProtoFormatID), gc.IsNil)
+	f.RecordWrite([]byte("!"))
+	<-rec.Barrier(nil).Done()
+
+	// Expect playback recovers content of all formats.
+	var player = NewPlayer()
+	go func() {
+		c.Check(player.Play(context.Background(), hints, dir, ajc), gc.IsNil)
+	}()
+	player.FinishAtWriteHead()
+	<-player.Done()
+
+	c.Assert(player.Resolved.FSM, gc.NotNil)
+	c.Check(player.Resolved.FSM.NextSeqNo, gc.Equals, fsm.NextSeqNo)
+	c.Check(player.Resolved.FSM.NextChecksum, gc.Equals, fsm.NextChecksum)
+
+	expectFileContent(c, dir+"/foo", "hello world!")
+	expectFileContent(c, dir+"/bar", "bing")
+}
+
+// jsonFormat is a RecordFormat of RecordedOps and their content in a JSON
+// encoding, which transforms written content (to base64).
+type jsonFormat struct{}
+
+type jsonFrame struct {
+	Op      *RecordedOp
+	Content []byte
+}
+
+func (jsonFormat) AppendFrame(b []byte, op *RecordedOp, content []byte) ([]byte, error) {
+	var j, err = json.Marshal(jsonFrame{Op: op, Content: content})
+	return append(b, j...), err
+}
+
+func (jsonFormat) DecodeFrame(b []byte, op *RecordedOp) ([]byte, error) {
+	var frame = jsonFrame{Op: op}
+	var err = json.Unmarshal(b, &frame)
+	return frame.Content, err
+}
+
+var jsonFormatID = FormatID{0xa1, 'j', 's', 'n'}
+
+func init() { RegisterFormat(jsonFormatID, jsonFormat{}) }
+
+var _ = gc.Suite(&FormatSuite{})
@@ -128,7 +128,7 @@ func newPlayerReader(ctx context.Context, name pb.Journal, ajc client.AsyncJourn
 	// happen in the background to prime for reading the next operation.
 	go func(br *bufio.Reader, reqCh <-chan struct{}, respCh chan<- error) {
 		for range reqCh {
-			var _, err = br.Peek(message.FixedFrameHeaderLength)
+			var _, err = br.Peek(frameHeaderLength)
 			respCh <- err
 		}
 		close(respCh)
@@ -524,18 +524,28 @@ func cleanupOnAbort(dir string, files fnodeFileMap) {
 	}
 }
 
-// decodeOperation unpacks, unmarshals, and sets offsets of a RecordedOp from Reader |br| at |offset|.
-func decodeOperation(br *bufio.Reader, readLog pb.Journal, offset int64) (op RecordedOp, frame []byte, err error) {
-	if frame, err = message.UnpackFixedFrame(br); err == nil {
-		err = op.Unmarshal(frame[message.FixedFrameHeaderLength:])
+// decodeOperation unpacks, decodes, and sets offsets of a RecordedOp from Reader |br| at |offset|.
+// It also returns the written content of the frame, which is nil if the frame is
+// of ProtoFormat and its content instead follows the frame in |br|.
+func decodeOperation(br *bufio.Reader, readLog pb.Journal, offset int64) (op RecordedOp, frame, content []byte, err error) {
+	var format RecordFormat
+	if format, frame, err = unpackFrame(br); err == nil {
+		content, err = format.DecodeFrame(frame[frameHeaderLength:], &op)
 	}
 	// First and last offsets are meta-fields never populated by Recorder, and known only upon playback.
 	op.FirstOffset = offset
 	op.LastOffset = offset + int64(len(frame))
 	op.Log = readLog
 
-	if op.Write != nil {
+	if err != nil || op.Write == nil {
+		// Pass.
+	} else if format == ProtoFormat {
 		op.LastOffset += op.Write.Length
+	} else if int64(len(content)) != op.Write.Length {
+		content, err = nil, errors.Errorf("decoded frame content length (%d) doesn't match Write.Length (%d)",
+			len(content), op.Write.Length)
+	} else if content == nil {
+		content = []byte{} // Distinguish from content which follows the frame.
 	}
 	return
 }
@@ -544,7 +554,7 @@ func decodeOperation(br *bufio.Reader, readLog pb.Journal, offset int64) (op Rec
 // whether a state transition was applied, and logs unexpected FSM errors.
 // Common and expected FSM errors are squelched.
 func applyOperation(op RecordedOp, frame []byte, fsm *FSM) bool {
-	if err := fsm.Apply(&op, frame[message.FixedFrameHeaderLength:]); err == nil {
+	if err := fsm.Apply(&op, frame[frameHeaderLength:]); err == nil {
 		return true
 	} else if err == ErrFnodeNotTracked {
 		// Fnode is hinted as being deleted later in the log. This occurs regularly
@@ -568,14 +578,15 @@ func applyOperation(op RecordedOp, frame []byte, fsm *FSM) bool {
 }
 
 // reenactOperation replays local file actions represented by RecordedOp |op|, which has been applied to |fsm|.
-func reenactOperation(op RecordedOp, fsm *FSM, br *bufio.Reader, dir string, files fnodeFileMap) error {
+// Written |content| is nil if it instead follows the operation's frame in |br|.
+func reenactOperation(op RecordedOp, content []byte, fsm *FSM, br *bufio.Reader, dir string, files fnodeFileMap) error {
 	if op.Create != nil {
 		return create(dir, Fnode(op.SeqNo), files)
 	} else if op.Unlink != nil {
 		return unlink(dir, op.Unlink.Fnode, fsm, files)
 	} else if op.Write != nil {
 		recoveredBytesTotal.Add(float64(op.Write.Length))
-		return write(op.Write, br, files)
+		return write(op.Write, content, br, files)
 	}
 	// op.Link and op.Property have no local reenactment, beyond application to the FSM.
 	return nil
@@ -586,11 +597,11 @@ func reenactOperation(op RecordedOp, fsm *FSM, br *bufio.Reader, dir string, fil
 func playOperation(br *bufio.Reader, readLog pb.Journal, offset int64, fsm *FSM,
 	dir string, files fnodeFileMap) (op RecordedOp, applied bool, err error) {
 
-	// Unpack the next frame and its unmarshaled RecordedOp.
-	var frame []byte
-	if op, frame, err = decodeOperation(br, readLog, offset); err != nil {
+	// Unpack the next frame and its decoded RecordedOp.
+	var frame, content []byte
+	if op, frame, content, err = decodeOperation(br, readLog, offset); err != nil {
 		if err == message.ErrDesyncDetected {
-			// ErrDesyncDetected is returned by UnpackFixedFrame.
+			// ErrDesyncDetected is returned by unpackFrame.
 			// This is pretty bad. It means a *significant* data corruption occurred,
 			// such as a loss of Etcd consistency *plus* an operational recovery error
 			// that resulted in a double-write of a journal offset. Or, a bug in
@@ -612,10 +623,10 @@ func playOperation(br *bufio.Reader, readLog pb.Journal, offset int64, fsm *FSM,
 	// Attempt to transition the FSM by the operation, and if it applies,
 	// reenact the local filesystem action.
 	if applied = applyOperation(op, frame, fsm); applied {
-		if err = reenactOperation(op, fsm, br, dir, files); err != nil {
+		if err = reenactOperation(op, content, fsm, br, dir, files); err != nil {
 			err = extendErr(err, "reenactOperation(%s)", op.String())
 		}
-	} else if op.Write != nil {
+	} else if op.Write != nil && content == nil {
 		// We must discard the indicated length for bytestream consistency.
 		if err = copyFixed(ioutil.Discard, br, op.Write.Length); err != nil {
 			err = extendErr(err, "copyFixed(%d)", op.Write.Length)
@@ -656,13 +667,17 @@ func unlink(dir string, fnode Fnode, fsm *FSM, files fnodeFileMap) error {
 	return nil
 }
 
-func write(op *RecordedOp_Write, br *bufio.Reader, files fnodeFileMap) error {
+func write(op *RecordedOp_Write, content []byte, br *bufio.Reader, files fnodeFileMap) error {
 	var file = files[Fnode(op.Fnode)]
 
 	// Seek to the indicated offset.
 	if _, err := file.Seek(op.Offset, 0); err != nil {
 		return err
 	}
+	if content != nil {
+		var _, err = file.Write(content)
+		return err
+	}
 	return copyFixed(file, br, op.Length)
 }
 
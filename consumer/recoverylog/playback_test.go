@@ -324,8 +324,9 @@ func (s *PlaybackSuite) TestOperationDecode(c *gc.C) {
 
 	var offset int
 	for i, exp := range expect {
-		var op, frame, err = decodeOperation(br, aRecoveryLog, int64(offset))
+		var op, frame, content, err = decodeOperation(br, aRecoveryLog, int64(offset))
 		c.Check(frame, gc.DeepEquals, parts[i])
+		c.Check(content, gc.IsNil) // Content of ProtoFormat follows its frame.
 		c.Check(err, gc.Equals, exp.err)
 
 		var wlen int64
@@ -2,6 +2,8 @@ package recoverylog
 
 import (
 	"bufio"
+	"fmt"
+	"io"
 	"io/ioutil"
 	"os"
 	"path"
@@ -10,7 +12,6 @@ import (
 	log "github.com/sirupsen/logrus"
 	"go.gazette.dev/core/broker/client"
 	pb "go.gazette.dev/core/broker/protocol"
-	"go.gazette.dev/core/message"
 )
 
 // propertyFiles is well-known paths which should be treated as properties,
@@ -78,6 +79,9 @@ type Recorder struct {
 	writeHead int64
 	// Scratch buffer for framing RecordedOps.
 	buf []byte
+	// FormatID and RecordFormat with which RecordedOps are framed.
+	formatID FormatID
+	format   RecordFormat
 }
 
 // NewRecorder builds and returns a new *Recorder.
@@ -94,6 +98,8 @@ func NewRecorder(journal pb.Journal, fsm *FSM, author Author, dir string, ajc cl
 		dir:            dir,
 		client:         ajc,
 		checkRegisters: nil,
+		formatID:       ProtoFormatID,
+		format:         ProtoFormat,
 	}
 
 	// Issue a write barrier to determine the current write head, which will
@@ -164,8 +170,7 @@ func (r *Recorder) RecordOpenFile(path string, flags int) Fnode {
 // RecordWriteAt records |data| written at |offset| to the file identified by |fnode|.
 func (r *Recorder) RecordWriteAt(fnode Fnode, data []byte, offset int64) {
 	var txn = r.lockAndBeginTxn(nil)
-	r.process(newWriteOp(fnode, offset, int64(len(data))), txn.Writer())
-	_, _ = txn.Writer().Write(data)
+	r.processWrite(newWriteOp(fnode, offset, int64(len(data))), data, txn.Writer())
 	r.unlockAndReleaseTxn(txn)
 }
 
@@ -237,6 +242,55 @@ func (r *Recorder) RecordRename(src, target string) {
 	r.unlockAndReleaseTxn(txn)
 }
 
+// RecordExistingFiles records the creation of each regular file under the
+// Recorder's directory, with its current content. It seeds a recovery log with
+// files which were written outside of the Recorder (for example, from an
+// imported archive), and which must not already be known to the Recorder.
+// Hard links aren't detected: each link is recorded as a distinct file.
+func (r *Recorder) RecordExistingFiles() error {
+	var buf = make([]byte, 1<<20)
+
+	return filepath.Walk(r.dir, func(fpath string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		} else if info.IsDir() {
+			return nil
+		} else if !info.Mode().IsRegular() {
+			return fmt.Errorf("%s is not a regular file", fpath)
+		}
+
+		if _, isProperty := propertyFiles[r.normalizePath(fpath)]; isProperty {
+			var content, err = ioutil.ReadFile(fpath)
+			if err != nil {
+				return err
+			}
+			var txn = r.lockAndBeginTxn(nil)
+			r.process(newPropertyOp(r.normalizePath(fpath), string(content)), txn.Writer())
+			r.unlockAndReleaseTxn(txn)
+			return nil
+		}
+
+		f, err := os.Open(fpath)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		var fr = FileRecorder{Recorder: r, Fnode: r.RecordCreate(fpath)}
+		for {
+			var n, err = f.Read(buf)
+			if n != 0 {
+				fr.RecordWrite(buf[:n])
+			}
+			if err == io.EOF {
+				return nil
+			} else if err != nil {
+				return err
+			}
+		}
+	})
+}
+
 // BuildHints returns FSMHints which may be played back to fully reconstruct the
 // local filesystem state observed by this Recorder. It may block while pending
 // operations sync to the log.
@@ -263,6 +317,22 @@ func (r *Recorder) Barrier(waitFor client.OpFutures) *client.AsyncAppend {
 	return txn
 }
 
+// SetFormat sets the registered RecordFormat of FormatID |id| with which the
+// Recorder frames further operations. The recovery log transitions to the
+// format with the next recorded operation. Players of the log must have
+// registered the format.
+func (r *Recorder) SetFormat(id FormatID) error {
+	var format = LookupFormat(id)
+	if format == nil {
+		return fmt.Errorf("recovery log format %s is not registered", id)
+	}
+	var txn = r.lockAndBeginTxn(nil)
+	r.formatID, r.format = id, format
+	r.unlockAndReleaseTxn(txn)
+
+	return nil
+}
+
 // Dir returns the directory in which this Recorder is recording.
 func (r *Recorder) Dir() string {
 	return r.dir
@@ -322,25 +392,33 @@ func (r *Recorder) unlockAndReleaseTxn(txn *client.AsyncAppend) {
 	}
 }
 
-func (r *Recorder) process(op RecordedOp, bw *bufio.Writer) {
+func (r *Recorder) process(op RecordedOp, bw *bufio.Writer) { r.processWrite(op, nil, bw) }
+
+// processWrite frames and writes |op| and its written |content| to |bw|,
+// and applies |op| to the Recorder FSM.
+func (r *Recorder) processWrite(op RecordedOp, content []byte, bw *bufio.Writer) {
 	op.Author = r.author
 	op.SeqNo = r.fsm.NextSeqNo
 	op.Checksum = r.fsm.NextChecksum
 
 	var err error
-	r.buf, err = message.EncodeFixedProtoFrame(&op, r.buf[:0])
+	r.buf, err = appendFrame(r.buf[:0], r.formatID, r.format, &op, content)
 	if err != nil {
-		log.WithFields(log.Fields{"op": op, "err": err}).Panic("fixed-framing encode failed")
+		log.WithFields(log.Fields{"op": op, "err": err}).Panic("framing encode failed")
 	}
 	_, _ = bw.Write(r.buf)
 
+	if r.format == ProtoFormat {
+		_, _ = bw.Write(content) // Content follows the frame as-is.
+	}
+
 	// Use writeHead as a lower-bound for FirstOffset. As a meta-field, it's not
 	// stored in the written frame, but is used by FSM in the production of hints.
 	// LastOffset is left as zero (unbounded).
 	op.FirstOffset = r.writeHead
 	op.Log = r.log
 
-	if err = r.fsm.Apply(&op, r.buf[message.FixedFrameHeaderLength:]); err != nil {
+	if err = r.fsm.Apply(&op, r.buf[frameHeaderLength:]); err != nil {
 		log.WithFields(log.Fields{"op": op, "err": err}).Panic("recorder FSM error")
 	}
 }
@@ -320,6 +320,40 @@ func (s *RecorderSuite) TestPropertyUpdate(c *gc.C) {
 	})
 }
 
+func (s *RecorderSuite) TestRecordExistingFiles(c *gc.C) {
+	var ajc, _, br, cleanup = newBrokerLogAndReader(c)
+	defer cleanup()
+
+	var dir, err = ioutil.TempDir("", "recorder-existing-files")
+	c.Assert(err, gc.IsNil)
+	defer os.RemoveAll(dir)
+
+	c.Assert(os.MkdirAll(dir+"/sub", 0777), gc.IsNil)
+	c.Assert(ioutil.WriteFile(dir+"/IDENTITY", []byte("value"), 0666), gc.IsNil)
+	c.Assert(ioutil.WriteFile(dir+"/sub/file", []byte("content"), 0666), gc.IsNil)
+
+	var fsm, _ = NewFSM(FSMHints{Log: aRecoveryLog})
+	var rec = NewRecorder(aRecoveryLog, fsm, anAuthor, dir, ajc)
+	c.Check(rec.RecordExistingFiles(), gc.IsNil)
+
+	// Expect a property update, and then creation and write of the file.
+	var op = s.parseOp(c, br)
+	c.Check(op.Property.Path, gc.Equals, "/IDENTITY")
+	c.Check(op.Property.Content, gc.Equals, "value")
+
+	op = s.parseOp(c, br)
+	c.Check(op.Create.Path, gc.Equals, "/sub/file")
+
+	op = s.parseOp(c, br)
+	c.Check(op.Write, gc.DeepEquals, &RecordedOp_Write{Fnode: 2, Offset: 0, Length: 7})
+	c.Check(s.readLen(c, op.Write.Length, br), gc.Equals, "content")
+
+	// Files which aren't regular are an error. The link is walked first,
+	// so that no further operations are recorded.
+	c.Assert(os.Symlink(dir+"/sub/file", dir+"/0-link"), gc.IsNil)
+	c.Check(rec.RecordExistingFiles(), gc.ErrorMatches, ".*/0-link is not a regular file")
+}
+
 func (s *RecorderSuite) TestMixedNewFileWriteAndDelete(c *gc.C) {
 	var ajc, r, br, cleanup = newBrokerLogAndReader(c)
 	defer cleanup()
@@ -41,9 +41,8 @@ func NewResolver(state *allocator.State, newShard func(keyspace.KeyValue) *shard
 		newShard: newShard,
 		shards:   make(map[pc.ShardID]*shard),
 	}
-	state.KS.Mu.Lock()
-	state.KS.Observers = append(state.KS.Observers, r.updateLocalShards, r.updateJournalsIndex)
-	state.KS.Mu.Unlock()
+	state.KS.AddObserver("consumer.Resolver.updateLocalShards", 0, r.updateLocalShards)
+	state.KS.AddObserver("consumer.Resolver.updateJournalsIndex", 0, r.updateJournalsIndex)
 	return r
 }
 
@@ -217,6 +216,17 @@ func (r *Resolver) Resolve(args ResolveArgs) (res Resolution, err error) {
 				err = fmt.Errorf("MessageProducer.ReadThrough: %w", err)
 				return
 			}
+		} else if _, ok := shard.svc.App.(SourceResolver); ok && len(args.ReadThrough) != 0 {
+			readThrough = make(pb.Offsets, len(args.ReadThrough))
+
+			// Filter ReadThrough to journals which are resolved shard sources.
+			shard.progress.Lock()
+			for journal, offset := range args.ReadThrough {
+				if _, ok := shard.progress.readThrough[journal]; ok && offset != 0 {
+					readThrough[journal] = offset
+				}
+			}
+			shard.progress.Unlock()
 		} else if l := len(args.ReadThrough); l != 0 {
 			readThrough = make(pb.Offsets, l)
 
@@ -421,6 +431,17 @@ func (r *Resolver) Collect(ch chan<- prometheus.Metric) {
 			shardID.String(),
 			status.Code.String())
 	}
+
+	var offloaded int
+	for _, kv := range r.state.Items {
+		if kv.Decoded.(allocator.Item).ItemValue.(*pc.ShardSpec).Offloaded != nil {
+			offloaded++
+		}
+	}
+	ch <- prometheus.MustNewConstMetric(
+		shardsOffloadedDesc,
+		prometheus.GaugeValue,
+		float64(offloaded))
 }
 
 // ErrResolverStopped is returned by Resolver if a ShardID resolves to a local
@@ -355,6 +355,11 @@ func TestResolverCollect(t *testing.T) {
 		require.Equal(t, "shard-A", *dtom.Label[0].Value)
 		require.Equal(t, "status", *dtom.Label[1].Name)
 		require.Equal(t, "IDLE", *dtom.Label[1].Value)
+
+		m = <-ch
+		dtom = &dto.Metric{}
+		m.Write(dtom)
+		require.Equal(t, 0.0, *dtom.Gauge.Value) // gazette_shards_offloaded.
 	}()
 	tf.resolver.Collect(ch)
 	close(ch)
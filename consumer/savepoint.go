@@ -0,0 +1,71 @@
+package consumer
+
+import "github.com/pkg/errors"
+
+// SavepointStore is an optional interface of a Store which supports savepoints
+// within a consumer transaction. An Application may mark a savepoint, make
+// further speculative updates of the Store, and then roll back to the
+// savepoint: updates made since the savepoint are discarded, while earlier
+// updates of the transaction are retained and committed as usual. See
+// Speculate, which is a convenient means of using savepoints.
+//
+// Savepoints are scoped to the current consumer transaction. They may be
+// nested, and a name may be re-used, in which case it refers to the most
+// recent savepoint of that name. All savepoints are released upon
+// StartCommit or RestoreCheckpoint.
+//
+// Implementations must ensure that only the committed state of a transaction
+// is durable: updates which were rolled back must never be observed by a
+// Store recovered from the shard's recovery log or external system. Stores
+// which write to their recovery log only upon StartCommit (eg, JSONFileStore)
+// meet this requirement trivially, as do Stores of external transactional
+// systems (eg, SQLStore). Stores which record speculative writes as they're
+// made must roll back by recording the reversal of those writes, such that
+// replay of the recovery log produces the committed state.
+//
+// Savepoints apply only to the Store. Messages published since a savepoint
+// aren't rolled back, nor are the offsets of consumed messages: the
+// transaction's Checkpoint reflects all messages consumed, including those
+// whose updates were rolled back.
+type SavepointStore interface {
+	Store
+	// Savepoint marks a savepoint |name| of the current transaction.
+	Savepoint(_ Shard, name string) error
+	// RollbackToSavepoint discards updates of the transaction made since
+	// savepoint |name|, and releases savepoints marked after it. Savepoint
+	// |name| itself remains, and may be rolled back to again.
+	RollbackToSavepoint(_ Shard, name string) error
+	// ReleaseSavepoint releases savepoint |name| and those marked after it,
+	// retaining their updates within the transaction.
+	ReleaseSavepoint(_ Shard, name string) error
+}
+
+// Speculate invokes |fn| within savepoint |name| of the |store|, which must
+// be a SavepointStore. If |fn| returns false, the Store is rolled back to the
+// savepoint, discarding updates made by |fn|. Otherwise its updates are kept.
+// In either case, the savepoint is then released.
+//
+// An error returned by |fn| is returned by Speculate without a rollback.
+// As with other errors of an Application, it's expected to fail the consumer
+// transaction.
+func Speculate(shard Shard, store Store, name string, fn func() (keep bool, err error)) error {
+	var sps, ok = store.(SavepointStore)
+	if !ok {
+		return errors.Errorf("store %T doesn't support savepoints", store)
+	} else if err := sps.Savepoint(shard, name); err != nil {
+		return errors.WithMessagef(err, "marking savepoint %s", name)
+	}
+
+	var keep, err = fn()
+	if err != nil {
+		return err
+	} else if !keep {
+		if err = sps.RollbackToSavepoint(shard, name); err != nil {
+			return errors.WithMessagef(err, "rolling back to savepoint %s", name)
+		}
+	}
+	if err = sps.ReleaseSavepoint(shard, name); err != nil {
+		return errors.WithMessagef(err, "releasing savepoint %s", name)
+	}
+	return nil
+}
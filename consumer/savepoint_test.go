@@ -0,0 +1,75 @@
+package consumer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestJSONFileStoreSpeculation(t *testing.T) {
+	var state = map[string]string{"a": "1"}
+	var store = &JSONFileStore{State: &state}
+
+	// Case: updates of a kept speculation are retained.
+	require.NoError(t, Speculate(nil, store, "one", func() (bool, error) {
+		state["b"] = "2"
+		return true, nil
+	}))
+	require.Equal(t, map[string]string{"a": "1", "b": "2"}, state)
+
+	// Case: updates of a discarded speculation are rolled back,
+	// including those of a nested, kept speculation.
+	require.NoError(t, Speculate(nil, store, "two", func() (bool, error) {
+		delete(state, "a")
+		require.NoError(t, Speculate(nil, store, "three", func() (bool, error) {
+			state["c"] = "3"
+			return true, nil
+		}))
+		return false, nil
+	}))
+	require.Equal(t, map[string]string{"a": "1", "b": "2"}, state)
+	require.Empty(t, store.savepoints)
+
+	// Case: rolling back to an unknown savepoint fails.
+	require.EqualError(t, store.RollbackToSavepoint(nil, "unknown"),
+		"savepoint unknown not found")
+}
+
+func TestSQLStoreSpeculation(t *testing.T) {
+	var tf, shard, cleanup = newTestFixtureWithIdleShard(t)
+	defer cleanup()
+
+	var store = NewSQLStore(tf.app.db)
+	var txn, err = store.Transaction(shard.ctx, nil)
+	require.NoError(t, err)
+	defer txn.Rollback()
+
+	var insert = func(key string) func() (bool, error) {
+		return func() (bool, error) {
+			var _, err = txn.Exec(`INSERT INTO kvstates (key, value) VALUES (?, 'v')`, key)
+			return key != "discarded", err
+		}
+	}
+	require.NoError(t, Speculate(shard, store, "sp", insert("kept")))
+	require.NoError(t, Speculate(shard, store, "sp", insert("discarded")))
+
+	var keys []string
+	rows, err := txn.Query(`SELECT key FROM kvstates ORDER BY key`)
+	require.NoError(t, err)
+	for rows.Next() {
+		var key string
+		require.NoError(t, rows.Scan(&key))
+		keys = append(keys, key)
+	}
+	require.NoError(t, rows.Err())
+	require.Equal(t, []string{"kept"}, keys)
+
+	// Case: savepoint names must be valid SQL identifiers.
+	require.EqualError(t, store.Savepoint(shard, "bad; DROP TABLE kvstates"),
+		`invalid savepoint name "bad; DROP TABLE kvstates" (expected a SQL identifier)`)
+}
+
+func TestSpeculateRequiresSavepointStore(t *testing.T) {
+	require.EqualError(t, Speculate(nil, nil, "sp", nil),
+		"store <nil> doesn't support savepoints")
+}
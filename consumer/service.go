@@ -47,12 +47,34 @@ type Service struct {
 	// ShardAPI holds function delegates which power the ShardServer API.
 	// They're exposed to allow consumer applications to wrap or alter their behavior.
 	ShardAPI struct {
-		Stat     func(context.Context, *Service, *pc.StatRequest) (*pc.StatResponse, error)
-		List     func(context.Context, *Service, *pc.ListRequest) (*pc.ListResponse, error)
-		Apply    func(context.Context, *Service, *pc.ApplyRequest) (*pc.ApplyResponse, error)
-		GetHints func(context.Context, *Service, *pc.GetHintsRequest) (*pc.GetHintsResponse, error)
-		Unassign func(context.Context, *Service, *pc.UnassignRequest) (*pc.UnassignResponse, error)
+		Stat            func(context.Context, *Service, *pc.StatRequest) (*pc.StatResponse, error)
+		List            func(context.Context, *Service, *pc.ListRequest) (*pc.ListResponse, error)
+		Apply           func(context.Context, *Service, *pc.ApplyRequest) (*pc.ApplyResponse, error)
+		GetHints        func(context.Context, *Service, *pc.GetHintsRequest) (*pc.GetHintsResponse, error)
+		Unassign        func(context.Context, *Service, *pc.UnassignRequest) (*pc.UnassignResponse, error)
+		ResetCheckpoint func(context.Context, *Service, *pc.ResetCheckpointRequest) (*pc.ResetCheckpointResponse, error)
 	}
+	// ShardHealth configures the checking of primary shard health, if the
+	// Application is a HealthChecker. See HealthChecker.
+	ShardHealth struct {
+		// Interval between health checks of a shard.
+		Interval time.Duration
+		// RecoverAfter is the duration for which checks of an unhealthy shard
+		// must continuously pass, before it's again considered healthy.
+		RecoverAfter time.Duration
+		// RelinquishAfter is the duration for which a shard must be unhealthy
+		// before it relinquishes its primary assignment.
+		RelinquishAfter time.Duration
+	}
+	// TxnLimiter bounds the number of local shards which concurrently process
+	// transactions. It's unlimited by default, and its limit may be changed
+	// at any time. See TxnLimiter.
+	TxnLimiter *TxnLimiter
+	// TxnRetry is an optional policy for retrying the consumer transactions
+	// of primary shards which fail with a retryable error. If nil, if the
+	// error isn't retryable, or if the shard's Store isn't a RollbackStore,
+	// the shard fails. See TxnRetryPolicy.
+	TxnRetry TxnRetryPolicy
 
 	// stoppingCh is closed when the Service is in the process of shutting down.
 	stoppingCh chan struct{}
@@ -76,6 +98,13 @@ func NewService(app Application, state *allocator.State, rjc pb.RoutedJournalCli
 	svc.ShardAPI.Apply = ShardApply
 	svc.ShardAPI.GetHints = ShardGetHints
 	svc.ShardAPI.Unassign = ShardUnassign
+	svc.ShardAPI.ResetCheckpoint = ShardResetCheckpoint
+
+	svc.ShardHealth.Interval = 10 * time.Second
+	svc.ShardHealth.RecoverAfter = time.Minute
+	svc.ShardHealth.RelinquishAfter = 2 * time.Minute
+
+	svc.TxnLimiter = NewTxnLimiter(0)
 	return svc
 }
 
@@ -92,6 +121,10 @@ func (svc *Service) QueueTasks(tasks *task.Group, server *server.Server) {
 	tasks.Queue("service.Watch", func() error {
 		return svc.Resolver.watch(watchCtx, svc.Etcd)
 	})
+	// Re-hydrate offloaded shards owned by this consumer upon source writes.
+	tasks.Queue("service.WatchOffloads", func() error {
+		return watchOffloads(watchCtx, svc)
+	})
 
 	// server.GracefulStop stops the server on task.Group cancellation,
 	// after which the service.Watch is also cancelled.
@@ -161,5 +194,10 @@ func (svc *Service) Unassign(ctx context.Context, req *pc.UnassignRequest) (*pc.
 	return svc.ShardAPI.Unassign(ctx, svc, req)
 }
 
+// ResetCheckpoint calls its ShardAPI delegate.
+func (svc *Service) ResetCheckpoint(ctx context.Context, req *pc.ResetCheckpointRequest) (*pc.ResetCheckpointResponse, error) {
+	return svc.ShardAPI.ResetCheckpoint(ctx, svc, req)
+}
+
 // Service implements the ShardServer interface.
 var _ pc.ShardServer = (*Service)(nil)
@@ -37,6 +37,10 @@ const (
 	messageSequencerPruneHorizon = time.Hour * 24
 )
 
+// Interval with which candidate source journals of a SourceResolver
+// Application are listed. Variable to facilitate testing.
+var sourceListInterval = time.Minute
+
 type shard struct {
 	svc          *Service                  // Service which owns the shard.
 	ctx          context.Context           // Context tied to shard Assignment lifetime.
@@ -49,6 +53,17 @@ type shard struct {
 	clock        message.Clock             // Clock which sequences messages from this shard.
 	wg           sync.WaitGroup            // Synchronizes over references to the shard.
 	primary      *client.AsyncOperation    // Status of servePrimary.
+	health       shardHealth               // Application-reported health of the primary.
+	buffer       messageBuffer             // Message buffer of an EventTimer or MergeOrderer Application.
+	release      bufferReleaseTimer        // Fires when |buffer| releases messages absent reads.
+	ckptSink     *checkpointSinker         // Sinker of a CheckpointSink Application.
+	readCtx      context.Context           // Context of reads of the current transaction loop.
+
+	// txnRetry tracks consecutive retries of failed transactions.
+	txnRetry struct {
+		attempt      int       // Number of consecutive retries.
+		failingSince time.Time // Time of the first consecutive failure.
+	}
 
 	// recovery of the shard from its log (if applicable).
 	recovery struct {
@@ -68,6 +83,7 @@ type shard struct {
 	progress struct {
 		readThrough pb.Offsets    // Offsets read through.
 		publishAt   pb.Offsets    // ACKs started to each journal.
+		checkpoint  pc.Checkpoint // Checkpoint of the last completed transaction.
 		signalCh    chan struct{} // Signalled on update to progress.
 		sync.Mutex                // Guards |progress|.
 	}
@@ -85,6 +101,7 @@ func newShard(svc *Service, item keyspace.KeyValue) *shard {
 		svc:          svc,
 		ctx:          ctx,
 		cancel:       cancel,
+		readCtx:      ctx,
 		ajc:          client.NewAppendService(ctx, svc.Journals),
 		storeReadyCh: make(chan struct{}),
 		primary:      client.NewAsyncOperation(),
@@ -122,6 +139,9 @@ func (s *shard) FQN() string                              { return s.resolved.fq
 func (s *shard) JournalClient() client.AsyncJournalClient { return s.ajc }
 func (s *shard) RecoveredHints() *pc.GetHintsResponse     { return s.recovery.hints }
 func (s *shard) PrimaryLoop() client.OpFuture             { return s.primary }
+func (s *shard) Health() error                            { return s.health.get() }
+
+var _ HealthReporter = (*shard)(nil) // shard is-a HealthReporter.
 
 func (s *shard) Spec() *pc.ShardSpec {
 	s.resolved.RLock()
@@ -136,6 +156,16 @@ func (s *shard) Assignment() keyspace.KeyValue {
 	return s.resolved.assignment
 }
 
+// Epoch of the Shard's primary Assignment, or zero if it's not primary.
+func (s *shard) Epoch() int64 {
+	var asn = s.Assignment()
+
+	if asn.Raw.CreateRevision == 0 || asn.Decoded.(allocator.Assignment).Slot != 0 {
+		return 0
+	}
+	return asn.Raw.CreateRevision
+}
+
 // Progress of this Shard in processing consumer transactions.
 func (s *shard) Progress() (readThrough, publishAt pb.Offsets) {
 	s.progress.Lock()
@@ -221,6 +251,7 @@ func servePrimary(s *shard) (err error) {
 	// Defer a trap which logs and updates Etcd status based on exit error.
 	defer func() {
 		s.primary.Resolve(err)
+		shardTxnRetriesTotal.DeleteLabelValues(s.Spec().Id.String())
 
 		if err != nil && s.ctx.Err() == nil {
 			log.WithFields(log.Fields{"err": err, "shard": s.FQN()}).Error("servePrimary failed")
@@ -244,8 +275,26 @@ func servePrimary(s *shard) (err error) {
 	if cp, err = completeRecovery(s); err != nil {
 		return errors.WithMessage(err, "completeRecovery")
 	}
+	// If the Application mirrors Checkpoints, write the restored Checkpoint.
+	if s.ckptSink = newShardCheckpointSinker(s); s.ckptSink != nil {
+		if err = s.ckptSink.restored(s, cp); err != nil {
+			return errors.WithMessage(err, "CheckpointSink.WriteCheckpoint")
+		}
+	}
+	warnTxnRetryStore(s)
 	updateStatusWithRetry(s, pc.ReplicaStatus{Code: pc.ReplicaStatus_PRIMARY})
 
+	// If the Application checks shard health, begin to watch it.
+	if hc, ok := s.svc.App.(HealthChecker); ok {
+		s.wg.Add(1)
+		go watchHealth(s, hc)
+	}
+	// If the shard may be offloaded, begin to watch for its idleness.
+	if s.Spec().OffloadAfterIdle != 0 {
+		s.wg.Add(1)
+		go watchIdle(s)
+	}
+
 	// If the shard store records to a log, arrange to periodically write FSMHints.
 	var hintsCh <-chan time.Time
 	if s.recovery.log != "" {
@@ -263,8 +312,15 @@ func servePrimary(s *shard) (err error) {
 		}
 		var msgCh = make(chan EnvelopeOrError, chanSize)
 
+		// Reads of this loop are cancelled as it exits, so that a retried
+		// loop doesn't race reads of the one it replaces.
+		var readCancel context.CancelFunc
+		s.readCtx, readCancel = context.WithCancel(s.ctx)
+
 		if mp, ok := s.svc.App.(MessageProducer); ok {
 			mp.StartReadingMessages(s, s.store, cp, msgCh)
+		} else if sr, ok := s.svc.App.(SourceResolver); ok {
+			startReadingResolvedSources(s, sr, cp, msgCh)
 		} else {
 			startReadingMessages(s, cp, msgCh)
 		}
@@ -279,7 +335,12 @@ func servePrimary(s *shard) (err error) {
 			int(ringSize),
 		)
 
-		if err = runTransactions(s, cp, msgCh, hintsCh); err != nil {
+		err = runTransactions(s, cp, msgCh, hintsCh)
+		readCancel()
+
+		if err == nil {
+			// |msgCh| closed, and processing restarts.
+		} else if err = retryTxns(s, err); err != nil {
 			return errors.WithMessage(err, "runTransactions")
 		}
 
@@ -291,6 +352,16 @@ func servePrimary(s *shard) (err error) {
 		if err != nil {
 			return errors.WithMessage(err, "restart store.RestoreCheckpoint")
 		}
+		if s.buffer != nil {
+			if err = s.buffer.restore(cp); err != nil {
+				return errors.WithMessage(err, "restart buffer.restore")
+			}
+		}
+		if s.ckptSink != nil {
+			if err = s.ckptSink.restored(s, cp); err != nil {
+				return errors.WithMessage(err, "restart CheckpointSink.WriteCheckpoint")
+			}
+		}
 	}
 }
 
@@ -312,6 +383,13 @@ func updateStatus(s *shard, status pc.ReplicaStatus) error {
 	var asn = s.Assignment()
 	status.Reduce(asn.Decoded.(allocator.Assignment).AssignmentValue.(*pc.ReplicaStatus))
 
+	// Health is advertised by a PRIMARY, and always reflects its current health.
+	if err := s.health.get(); err != nil && status.Code == pc.ReplicaStatus_PRIMARY {
+		status.Unhealthy = err.Error()
+	} else {
+		status.Unhealthy = ""
+	}
+
 	var key = string(asn.Raw.Key)
 	var val = status.MarshalString()
 
@@ -366,44 +444,126 @@ type EnvelopeOrError struct {
 // startReadingMessages from source journals into the provided channel.
 func startReadingMessages(s *shard, cp pc.Checkpoint, ch chan<- EnvelopeOrError) {
 	for _, src := range s.Spec().Sources {
+		startReadingSource(s.readCtx, s, src, cp, ch)
+	}
+}
+
+// startReadingResolvedSources lists journals of the SourceResolver's
+// SourceSelector, and begins reading from its resolved sources into the
+// provided channel. As the listing is updated, sources which are newly
+// resolved also begin to be read.
+func startReadingResolvedSources(s *shard, sr SourceResolver, cp pc.Checkpoint, ch chan<- EnvelopeOrError) {
+	var ctx = s.readCtx
+	var list, err = client.NewPolledList(ctx, s.ajc, sourceListInterval,
+		pb.ListRequest{Selector: sr.SourceSelector(s)})
+	if err != nil {
+		ch <- EnvelopeOrError{Error: errors.WithMessage(err, "listing source journals")}
+		return
+	}
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		var reading = make(map[pb.Journal]struct{})
+
+		for {
+			select {
+			case <-list.UpdateCh():
+			case <-ctx.Done():
+				return
+			}
+
+			for _, src := range sr.ResolveSources(s, list.List()) {
+				if _, ok := reading[src.Journal]; ok {
+					continue
+				}
+				reading[src.Journal] = struct{}{}
+
+				// Track progress of the source, which may be awaited by Resolve.
+				s.progress.Lock()
+				if _, ok := s.progress.readThrough[src.Journal]; !ok {
+					s.progress.readThrough[src.Journal] = 0
+				}
+				s.progress.Unlock()
 
-		// Lower-bound checkpoint offset to the ShardSpec.Source.MinOffset.
-		var offset = cp.Sources[src.Journal].ReadThrough
+				log.WithFields(log.Fields{
+					"shard":   s.Spec().Id,
+					"journal": src.Journal,
+				}).Info("reading from resolved shard source")
 
-		if offset < src.MinOffset {
-			offset = src.MinOffset
+				startReadingSource(ctx, s, src, cp, ch)
+			}
 		}
+	}()
+}
 
-		var it = message.NewReadUncommittedIter(
-			client.NewRetryReader(s.ctx, s.ajc, pb.ReadRequest{
-				Journal:    src.Journal,
-				Offset:     offset,
-				Block:      true,
-				DoNotProxy: !s.ajc.IsNoopRouter(),
-			}), s.svc.App.NewMessage)
+// resolveStartOffset returns the offset from which the source journal is read.
+// A shard which has recorded a Checkpoint of read source offsets reads from its
+// Checkpoint. Otherwise, this is the shard's first assignment and the offset is
+// resolved from the ShardSpec StartPolicy. Either way, the offset is
+// lower-bounded by the ShardSpec.Source.MinOffset.
+func resolveStartOffset(ctx context.Context, s *shard, src pc.ShardSpec_Source, cp pc.Checkpoint) (pb.Offset, error) {
+	var spec = s.Spec()
+	var offset = cp.Sources[src.Journal].ReadThrough
+	var err error
+
+	if len(cp.Sources) != 0 {
+		// Recovered shards always read from their Checkpoint.
+	} else if spec.StartPolicy == pc.ShardSpec_START_AT_HEAD {
+		offset, err = client.GetHead(ctx, s.ajc, src.Journal)
+	} else if spec.StartPolicy == pc.ShardSpec_START_AT_TIME {
+		var snapshot client.Snapshot
+		snapshot, err = client.NewSnapshotAt(ctx, s.ajc,
+			[]pb.Journal{src.Journal}, time.Unix(spec.StartTime, 0))
+		offset = snapshot.Offsets[src.Journal]
+	}
 
-		s.wg.Add(1)
-		go func(it message.Iterator) {
-			defer s.wg.Done()
+	if err != nil {
+		return 0, err
+	} else if offset < src.MinOffset {
+		offset = src.MinOffset
+	}
+	return offset, nil
+}
 
-			var v EnvelopeOrError
-			for v.Error == nil {
-				v.Envelope, v.Error = it.Next()
+// startReadingSource begins reading from the source journal into the
+// provided channel, until the Context is cancelled.
+func startReadingSource(ctx context.Context, s *shard, src pc.ShardSpec_Source, cp pc.Checkpoint, ch chan<- EnvelopeOrError) {
+	var offset, err = resolveStartOffset(ctx, s, src, cp)
+	if err != nil {
+		ch <- EnvelopeOrError{Error: errors.WithMessagef(err, "resolving start offset of %s", src.Journal)}
+		return
+	}
 
-				// Attempt to place |v| even if context is cancelled,
-				// but don't hang if we're cancelled and buffer is full.
+	var it = message.NewReadUncommittedIter(
+		client.NewRetryReader(ctx, s.ajc, pb.ReadRequest{
+			Journal:    src.Journal,
+			Offset:     offset,
+			Block:      true,
+			DoNotProxy: !s.ajc.IsNoopRouter(),
+		}), s.svc.App.NewMessage)
+
+	s.wg.Add(1)
+	go func(it message.Iterator) {
+		defer s.wg.Done()
+
+		var v EnvelopeOrError
+		for v.Error == nil {
+			v.Envelope, v.Error = it.Next()
+
+			// Attempt to place |v| even if context is cancelled,
+			// but don't hang if we're cancelled and buffer is full.
+			select {
+			case ch <- v:
+			default:
 				select {
 				case ch <- v:
-				default:
-					select {
-					case ch <- v:
-					case <-s.ctx.Done():
-						return
-					}
+				case <-ctx.Done():
+					return
 				}
 			}
-		}(it)
-	}
+		}
+	}(it)
 }
 
 func backoff(attempt int) time.Duration {
@@ -42,6 +42,14 @@ func ShardStat(ctx context.Context, srv *Service, req *pc.StatRequest) (*pc.Stat
 	defer res.Done()
 
 	resp.ReadThrough, resp.PublishAt = res.Shard.Progress()
+
+	if req.IncludeCheckpoint {
+		var s = res.Shard.(*shard)
+		s.progress.Lock()
+		var cp = s.progress.checkpoint
+		s.progress.Unlock()
+		resp.Checkpoint = &cp
+	}
 	return resp, err
 }
 
@@ -242,6 +250,70 @@ func ShardUnassign(ctx context.Context, srv *Service, req *pc.UnassignRequest) (
 	return resp, err
 }
 
+// ShardResetCheckpoint is the default implementation of the ShardServer.ResetCheckpoint API.
+func ShardResetCheckpoint(ctx context.Context, srv *Service, req *pc.ResetCheckpointRequest) (*pc.ResetCheckpointResponse, error) {
+	var state = srv.Resolver.state
+
+	var resp = &pc.ResetCheckpointResponse{
+		Status: pc.Status_OK,
+		Header: pbx.NewUnroutedHeader(state),
+	}
+	if err := req.Validate(); err != nil {
+		return resp, err
+	}
+
+	state.KS.Mu.RLock()
+	var item, ok = lookupShardSpec(state.KS, req.Shard)
+	var assignments = state.Assignments.Prefixed(allocator.ItemAssignmentsPrefix(state.KS, req.Shard.String()))
+	state.KS.Mu.RUnlock()
+
+	if !ok {
+		resp.Status = pc.Status_SHARD_NOT_FOUND
+		return resp, nil
+	}
+	var spec = *item.Decoded.(allocator.Item).ItemValue.(*pc.ShardSpec)
+
+	spec.ResetCheckpoint = &pc.Checkpoint{Sources: make(map[pb.Journal]pc.Checkpoint_Source)}
+	for journal, offset := range req.ReadThrough {
+		var found bool
+		for _, src := range spec.Sources {
+			found = found || src.Journal == journal
+		}
+		if !found {
+			return resp, fmt.Errorf("journal %s is not a source of shard %s", journal, req.Shard)
+		}
+		spec.ResetCheckpoint.Sources[journal] = pc.Checkpoint_Source{ReadThrough: offset}
+	}
+
+	// Verify the ShardSpec and its Assignments are unchanged. Update the
+	// ShardSpec, and remove its Assignments so that a new primary is assigned
+	// which recovers and applies the ResetCheckpoint.
+	var cmp = []clientv3.Cmp{
+		clientv3.Compare(clientv3.ModRevision(string(item.Raw.Key)), "=", item.Raw.ModRevision),
+	}
+	var ops = []clientv3.Op{
+		clientv3.OpPut(string(item.Raw.Key), spec.MarshalString()),
+	}
+	for _, kv := range assignments {
+		cmp = append(cmp, clientv3.Compare(clientv3.ModRevision(string(kv.Raw.Key)), "=", kv.Raw.ModRevision))
+		ops = append(ops, clientv3.OpDelete(string(kv.Raw.Key)))
+	}
+
+	etcdResp, err := srv.Etcd.Txn(ctx).If(cmp...).Then(ops...).Commit()
+	if err != nil {
+		return resp, fmt.Errorf("executing etcd transaction: %w", err)
+	} else if !etcdResp.Succeeded {
+		resp.Status = pc.Status_ETCD_TRANSACTION_FAILED
+	} else {
+		// Delay responding until we have read our own Etcd write.
+		state.KS.Mu.RLock()
+		err = state.KS.WaitForRevision(ctx, etcdResp.Header.Revision)
+		state.KS.Mu.RUnlock()
+		resp.Header = pbx.NewUnroutedHeader(state)
+	}
+	return resp, err
+}
+
 // ListShards is a convenience for invoking the List RPC, which maps a validation or !OK status to an error.
 func ListShards(ctx context.Context, sc pc.ShardClient, req *pc.ListRequest) (*pc.ListResponse, error) {
 	if r, err := sc.List(pb.WithDispatchDefault(ctx), req, grpc.WaitForReady(true)); err != nil {
@@ -269,6 +341,20 @@ func StatShard(ctx context.Context, rc pc.RoutedShardClient, req *pc.StatRequest
 	}
 }
 
+// ResetShardCheckpoint is a convenience for invoking the ResetCheckpoint RPC,
+// which maps a validation or !OK status to an error.
+func ResetShardCheckpoint(ctx context.Context, sc pc.ShardClient, req *pc.ResetCheckpointRequest) (*pc.ResetCheckpointResponse, error) {
+	if r, err := sc.ResetCheckpoint(pb.WithDispatchDefault(ctx), req, grpc.WaitForReady(true)); err != nil {
+		return r, err
+	} else if err = r.Validate(); err != nil {
+		return r, err
+	} else if r.Status != pc.Status_OK {
+		return r, errors.New(r.Status.String())
+	} else {
+		return r, nil
+	}
+}
+
 // VerifyReferencedJournals ensures the referential integrity of journals
 // (sources and recovery logs, and their content types) referenced by Shards
 // of the ApplyRequest. It returns a descriptive error if any invalid
@@ -377,6 +377,93 @@ func TestAPIHintsCases(t *testing.T) {
 	tf.allocateShard(spec) // Cleanup.
 }
 
+func TestAPIResetCheckpointCases(t *testing.T) {
+	var tf, cleanup = newTestFixture(t)
+	defer cleanup()
+
+	tf.allocateShard(makeShard(shardA), localID)
+	expectStatusCode(t, tf.state, pc.ReplicaStatus_PRIMARY)
+
+	var aa, _ = tf.pub.PublishCommitted(toSourceB, &testMessage{Key: "one", Value: "1"})
+	<-aa.Done()
+
+	// Case: Stat includes the Checkpoint of the shard's last transaction.
+	stat, err := tf.service.Stat(context.Background(), &pc.StatRequest{
+		Shard:             shardA,
+		ReadThrough:       pb.Offsets{sourceB.Name: aa.Response().Commit.End},
+		IncludeCheckpoint: true,
+	})
+	require.NoError(t, err)
+	require.Equal(t, aa.Response().Commit.End, stat.Checkpoint.Sources[sourceB.Name].ReadThrough)
+	require.Len(t, stat.Checkpoint.Sources[sourceB.Name].Producers, 1)
+
+	var req = &pc.ResetCheckpointRequest{
+		Shard:                shardA,
+		ReadThrough:          pb.Offsets{sourceB.Name: aa.Response().Commit.End},
+		AllowStoreDivergence: true,
+	}
+
+	// Case: Reset of a non-existent Shard.
+	resp, err := tf.service.ResetCheckpoint(context.Background(), &pc.ResetCheckpointRequest{
+		Shard:                "missing-shard",
+		ReadThrough:          req.ReadThrough,
+		AllowStoreDivergence: true,
+	})
+	require.NoError(t, err)
+	require.Equal(t, pc.Status_SHARD_NOT_FOUND, resp.Status)
+
+	// Case: Reset of a journal which isn't a shard source.
+	_, err = tf.service.ResetCheckpoint(context.Background(), &pc.ResetCheckpointRequest{
+		Shard:                shardA,
+		ReadThrough:          pb.Offsets{echoOut.Name: 0},
+		AllowStoreDivergence: true,
+	})
+	require.EqualError(t, err, "journal "+echoOut.Name.String()+" is not a source of shard "+shardA)
+
+	// Case: Reset of an assigned shard removes its assignments.
+	resp, err = tf.service.ResetCheckpoint(context.Background(), req)
+	require.NoError(t, err)
+	require.Equal(t, pc.Status_OK, resp.Status)
+
+	list, err := tf.service.List(context.Background(), &pc.ListRequest{
+		Selector: pb.LabelSelector{Include: pb.MustLabelSet("id", shardA)},
+	})
+	require.NoError(t, err)
+	require.Len(t, list.Shards[0].Status, 0)
+
+	// A message is written while the shard is unassigned.
+	aa, _ = tf.pub.PublishCommitted(toSourceB, &testMessage{Key: "two", Value: "2"})
+	<-aa.Done()
+
+	// Case: A reset replaces a prior, unapplied reset.
+	req.ReadThrough[sourceB.Name] = aa.Response().Commit.End
+	resp, err = tf.service.ResetCheckpoint(context.Background(), req)
+	require.NoError(t, err)
+	require.Equal(t, pc.Status_OK, resp.Status)
+
+	var spec = awaitShardSpec(t, tf, func(spec *pc.ShardSpec) bool { return spec.ResetCheckpoint != nil })
+	require.Equal(t, &pc.Checkpoint{Sources: map[pb.Journal]pc.Checkpoint_Source{
+		sourceB.Name: {ReadThrough: aa.Response().Commit.End},
+	}}, spec.ResetCheckpoint)
+
+	// The next primary applies and then clears the ResetCheckpoint,
+	// and skips the message written prior to its reset offset.
+	tf.allocateShard(spec, localID)
+	expectStatusCode(t, tf.state, pc.ReplicaStatus_PRIMARY)
+	awaitShardSpec(t, tf, func(spec *pc.ShardSpec) bool { return spec.ResetCheckpoint == nil })
+
+	stat, err = tf.service.Stat(context.Background(), &pc.StatRequest{
+		Shard:             shardA,
+		IncludeCheckpoint: true,
+	})
+	require.NoError(t, err)
+	require.Equal(t, aa.Response().Commit.End, stat.ReadThrough[sourceB.Name])
+	require.Equal(t, pc.Checkpoint_Source{ReadThrough: aa.Response().Commit.End},
+		stat.Checkpoint.Sources[sourceB.Name])
+
+	tf.allocateShard(makeShard(shardA)) // Cleanup.
+}
+
 func TestVerifyReferencedJournalsCases(t *testing.T) {
 	var tf, cleanup = newTestFixture(t)
 	var ctx, jc = context.Background(), tf.broker.Client()
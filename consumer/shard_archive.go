@@ -0,0 +1,367 @@
+package consumer
+
+import (
+	"archive/tar"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"go.gazette.dev/core/broker/client"
+	"go.gazette.dev/core/broker/codecs"
+	pb "go.gazette.dev/core/broker/protocol"
+	pc "go.gazette.dev/core/consumer/protocol"
+	"go.gazette.dev/core/consumer/recoverylog"
+)
+
+// ShardArchiveManifest describes the content of a shard archive. It's the
+// first entry of each archive written by ExportShard.
+type ShardArchiveManifest struct {
+	// Version of the archive format.
+	Version int `json:"version"`
+	// Shard which was exported.
+	Shard pc.ShardID `json:"shard"`
+	// RecoveryLog from which the Shard's Store was exported.
+	RecoveryLog pb.Journal `json:"recoveryLog"`
+	// StoreSchema identifies the type and schema version of the exported Store.
+	StoreSchema string `json:"storeSchema"`
+	// Checkpoint of the exported Store, if known.
+	Checkpoint *pc.Checkpoint `json:"checkpoint,omitempty"`
+	// ExportedAt is the time of the export.
+	ExportedAt time.Time `json:"exportedAt"`
+}
+
+// ExportShardArgs are arguments of ExportShard.
+type ExportShardArgs struct {
+	// Spec of the shard to export.
+	Spec *pc.ShardSpec
+	// Etcd client of the shard's consumer cluster, from which hints are read.
+	Etcd *clientv3.Client
+	// Journals client of the shard's broker cluster.
+	Journals pb.RoutedJournalClient
+	// StoreSchema is an application-defined identifier of the type and schema
+	// version of the shard's Store, such as "sqlite/my-app/v3". It's recorded
+	// in the archive, and an import must present the same StoreSchema.
+	StoreSchema string
+	// Checkpoint optionally returns the Checkpoint of a Store recovered into
+	// |dir|. If provided, the Checkpoint is recorded in the archive manifest
+	// and imports verify that its source journals exist and have been written
+	// through its offsets.
+	Checkpoint func(dir string) (pc.Checkpoint, error)
+}
+
+// ExportShard exports the state of a shard to a portable archive written to
+// |w|. The shard's Store is recovered from its recovery log into a temporary
+// directory, using its most recent hints, and its files are archived along
+// with a ShardArchiveManifest. The shard may be actively processing during an
+// export, in which case the archive reflects its state as of its last stored
+// hints. ExportShard doesn't Close |w|.
+//
+// An archive may be written wherever the application likes. To persist it
+// to a fragment store, append it to a journal of the store:
+//
+//	var app = client.NewAppender(ctx, rjc, pb.AppendRequest{Journal: "archives/my-shard"})
+//	if _, err = consumer.ExportShard(ctx, args, app); err != nil {
+//		app.Abort()
+//	} else {
+//		err = app.Close() // app.Response.Commit locates the archive.
+//	}
+func ExportShard(ctx context.Context, args ExportShardArgs, w io.Writer) (ShardArchiveManifest, error) {
+	var dir, err = ioutil.TempDir("", "shard-export-")
+	if err != nil {
+		return ShardArchiveManifest{}, err
+	}
+	defer os.RemoveAll(dir)
+
+	if err = playLatestHints(ctx, args.Spec, args.Etcd, args.Journals, dir); err != nil {
+		return ShardArchiveManifest{}, err
+	}
+
+	var manifest = ShardArchiveManifest{
+		Version:     shardArchiveVersion,
+		Shard:       args.Spec.Id,
+		RecoveryLog: args.Spec.RecoveryLog(),
+		StoreSchema: args.StoreSchema,
+		ExportedAt:  time.Now().UTC(),
+	}
+	if args.Checkpoint != nil {
+		var cp, err = args.Checkpoint(dir)
+		if err != nil {
+			return ShardArchiveManifest{}, errors.WithMessage(err, "reading checkpoint")
+		}
+		manifest.Checkpoint = &cp
+	}
+
+	if err = writeShardArchive(w, manifest, dir); err != nil {
+		return ShardArchiveManifest{}, errors.WithMessage(err, "writing archive")
+	}
+	return manifest, nil
+}
+
+// ImportShardArgs are arguments of ImportShard.
+type ImportShardArgs struct {
+	// Spec of the shard into which the archive is imported.
+	Spec *pc.ShardSpec
+	// Etcd client of the shard's consumer cluster, to which hints are written.
+	Etcd *clientv3.Client
+	// Journals client of the shard's broker cluster.
+	Journals pb.RoutedJournalClient
+	// StoreSchema expected of the archive. An archive of a different
+	// StoreSchema is refused.
+	StoreSchema string
+}
+
+// ImportShard imports an archive written by ExportShard into a shard of
+// another consumer cluster, such that the shard resumes from the exported
+// state when it's next assigned. Files of the archive are recorded into the
+// shard's recovery log, and hints of the recorded log are stored for the
+// shard. The Store then restores its Checkpoint from its imported files.
+//
+// ImportShard refuses an archive of an unknown format version or of a
+// different StoreSchema, or having a Checkpoint which reads from journals
+// which don't exist or haven't been written through its offsets. The shard
+// must be disabled (ShardSpec.Disable) for the duration of the import, so
+// that it's not concurrently processed, and it mustn't already have stored
+// hints: an import never overwrites existing state of a shard.
+func ImportShard(ctx context.Context, args ImportShardArgs, r io.Reader) (ShardArchiveManifest, error) {
+	if !args.Spec.Disable {
+		return ShardArchiveManifest{}, errors.Errorf("shard %s must be disabled during import", args.Spec.Id)
+	}
+
+	dir, err := ioutil.TempDir("", "shard-import-")
+	if err != nil {
+		return ShardArchiveManifest{}, err
+	}
+	defer os.RemoveAll(dir)
+
+	manifest, err := readShardArchive(r, dir)
+	if err != nil {
+		return ShardArchiveManifest{}, errors.WithMessage(err, "reading archive")
+	} else if manifest.StoreSchema != args.StoreSchema {
+		return ShardArchiveManifest{}, errors.Errorf(
+			"archive StoreSchema %q is incompatible with expected StoreSchema %q",
+			manifest.StoreSchema, args.StoreSchema)
+	}
+
+	if manifest.Checkpoint != nil {
+		for journal, src := range manifest.Checkpoint.Sources {
+			var head, err = client.GetHead(ctx, args.Journals, journal)
+			if err != nil {
+				return ShardArchiveManifest{}, errors.WithMessagef(err, "checkpoint source %s", journal)
+			} else if head < src.ReadThrough {
+				return ShardArchiveManifest{}, errors.Errorf(
+					"checkpoint source %s has write head %d, which is less than checkpoint offset %d",
+					journal, head, src.ReadThrough)
+			}
+		}
+	}
+
+	// Verify the shard has no existing hints, and therefore no existing state.
+	fetched, err := fetchHints(ctx, args.Spec, args.Etcd)
+	if err != nil {
+		return ShardArchiveManifest{}, err
+	}
+	for _, h := range fetched.hints {
+		if h != nil {
+			return ShardArchiveManifest{}, errors.Errorf("shard %s has existing hints", args.Spec.Id)
+		}
+	}
+
+	// Record archived files into the shard's recovery log.
+	var recoveryLog = args.Spec.RecoveryLog()
+	fsm, err := recoverylog.NewFSM(recoverylog.FSMHints{Log: recoveryLog})
+	if err != nil {
+		return ShardArchiveManifest{}, err
+	}
+	var rec = recoverylog.NewRecorder(recoveryLog, fsm, recoverylog.NewRandomAuthor(), dir,
+		client.NewAppendService(ctx, args.Journals))
+
+	if err = rec.RecordExistingFiles(); err != nil {
+		return ShardArchiveManifest{}, errors.WithMessage(err, "recording files")
+	}
+	hints, err := rec.BuildHints()
+	if err != nil {
+		return ShardArchiveManifest{}, errors.WithMessage(err, "building hints")
+	}
+	val, err := json.Marshal(hints)
+	if err != nil {
+		return ShardArchiveManifest{}, errors.WithMessage(err, "json.Marshal(hints)")
+	}
+
+	// Store hints only if the shard still has none.
+	var cmps []clientv3.Cmp
+	for _, key := range append([]string{args.Spec.HintPrimaryKey()}, args.Spec.HintBackupKeys()...) {
+		cmps = append(cmps, clientv3.Compare(clientv3.CreateRevision(key), "=", 0))
+	}
+	resp, err := args.Etcd.Txn(ctx).
+		If(cmps...).
+		Then(clientv3.OpPut(args.Spec.HintPrimaryKey(), string(val))).
+		Commit()
+
+	if err != nil {
+		return ShardArchiveManifest{}, errors.WithMessage(err, "storing hints")
+	} else if !resp.Succeeded {
+		return ShardArchiveManifest{}, errors.Errorf("shard %s hints were concurrently stored", args.Spec.Id)
+	}
+	return manifest, nil
+}
+
+// playLatestHints plays the recovery log of the shard into |dir| through its
+// current write head, using the most recent stored hints of the shard.
+func playLatestHints(ctx context.Context, spec *pc.ShardSpec, etcd *clientv3.Client,
+	journals pb.RoutedJournalClient, dir string) error {
+
+	var fetched, err = fetchHints(ctx, spec, etcd)
+	if err != nil {
+		return err
+	}
+	var hints *recoverylog.FSMHints
+	for _, h := range fetched.hints {
+		if h != nil {
+			hints = h
+			break
+		}
+	}
+	if hints == nil {
+		return errors.Errorf("shard %s has no stored hints", spec.Id)
+	}
+
+	var player = recoverylog.NewPlayer()
+	player.FinishAtWriteHead()
+
+	if err = player.Play(ctx, *hints, dir, client.NewAppendService(ctx, journals)); err != nil {
+		return errors.WithMessage(err, "playing recovery log")
+	}
+	return nil
+}
+
+// writeShardArchive writes a gzip-compressed tar of the |manifest| and all
+// files under |dir| to |w|.
+func writeShardArchive(w io.Writer, manifest ShardArchiveManifest, dir string) error {
+	var comp, err = codecs.NewCodecWriter(w, pb.CompressionCodec_GZIP)
+	if err != nil {
+		return err
+	}
+	var tw = tar.NewWriter(comp)
+
+	b, err := json.Marshal(manifest)
+	if err != nil {
+		return err
+	} else if err = tw.WriteHeader(&tar.Header{
+		Name:     shardArchiveManifestName,
+		Mode:     0644,
+		Size:     int64(len(b)),
+		Typeflag: tar.TypeReg,
+	}); err != nil {
+		return err
+	} else if _, err = tw.Write(b); err != nil {
+		return err
+	}
+
+	err = filepath.Walk(dir, func(fpath string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		} else if !info.Mode().IsRegular() {
+			return fmt.Errorf("%s is not a regular file", fpath)
+		}
+		rel, err := filepath.Rel(dir, fpath)
+		if err != nil {
+			return err
+		}
+
+		if err = tw.WriteHeader(&tar.Header{
+			Name:     shardArchiveFilesPrefix + filepath.ToSlash(rel),
+			Mode:     int64(info.Mode().Perm()),
+			Size:     info.Size(),
+			ModTime:  info.ModTime(),
+			Typeflag: tar.TypeReg,
+		}); err != nil {
+			return err
+		}
+		f, err := os.Open(fpath)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		_, err = io.Copy(tw, f)
+		return err
+	})
+
+	if err != nil {
+		return err
+	} else if err = tw.Close(); err != nil {
+		return err
+	}
+	return comp.Close()
+}
+
+// readShardArchive reads a gzip-compressed tar archive from |r|, writing its
+// files into |dir| and returning its validated manifest.
+func readShardArchive(r io.Reader, dir string) (ShardArchiveManifest, error) {
+	var manifest ShardArchiveManifest
+
+	var decomp, err = codecs.NewCodecReader(r, pb.CompressionCodec_GZIP)
+	if err != nil {
+		return manifest, err
+	}
+	defer decomp.Close()
+
+	var tr = tar.NewReader(decomp)
+
+	if hdr, err := tr.Next(); err != nil {
+		return manifest, err
+	} else if hdr.Name != shardArchiveManifestName {
+		return manifest, errors.Errorf("expected manifest as first archive entry (not %q)", hdr.Name)
+	} else if err = json.NewDecoder(tr).Decode(&manifest); err != nil {
+		return manifest, errors.WithMessage(err, "decoding manifest")
+	} else if manifest.Version != shardArchiveVersion {
+		return manifest, errors.Errorf("unsupported archive version %d (expected %d)",
+			manifest.Version, shardArchiveVersion)
+	}
+
+	for {
+		var hdr, err = tr.Next()
+		if err == io.EOF {
+			return manifest, nil
+		} else if err != nil {
+			return manifest, err
+		}
+
+		var name = path.Clean(strings.TrimPrefix(hdr.Name, shardArchiveFilesPrefix))
+		if hdr.Typeflag != tar.TypeReg {
+			return manifest, errors.Errorf("archive entry %q is not a regular file", hdr.Name)
+		} else if !strings.HasPrefix(hdr.Name, shardArchiveFilesPrefix) ||
+			path.IsAbs(name) || name == ".." || strings.HasPrefix(name, "../") {
+			return manifest, errors.Errorf("invalid archive entry name %q", hdr.Name)
+		}
+
+		var fpath = filepath.Join(dir, filepath.FromSlash(name))
+		if err = os.MkdirAll(filepath.Dir(fpath), 0777); err != nil {
+			return manifest, err
+		}
+		f, err := os.OpenFile(fpath, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0666)
+		if err != nil {
+			return manifest, err
+		} else if _, err = io.Copy(f, tr); err != nil {
+			_ = f.Close()
+			return manifest, err
+		} else if err = f.Close(); err != nil {
+			return manifest, err
+		}
+	}
+}
+
+const (
+	shardArchiveVersion      = 1
+	shardArchiveManifestName = "MANIFEST.json"
+	shardArchiveFilesPrefix  = "files/"
+)
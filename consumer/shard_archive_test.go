@@ -0,0 +1,160 @@
+package consumer
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	pb "go.gazette.dev/core/broker/protocol"
+	pc "go.gazette.dev/core/consumer/protocol"
+	"go.gazette.dev/core/consumer/recoverylog"
+)
+
+func TestShardExportAndImport(t *testing.T) {
+	var tf, cleanup = newTestFixture(t)
+	defer cleanup()
+
+	var ctx = context.Background()
+	var specA, specB = makeShard(shardA), makeShard(shardB)
+
+	// Build a fixture of shard A, having a JSONFileStore of a recorded
+	// checkpoint, and stored hints of its recovery log.
+	var dir, err = ioutil.TempDir("", "shard-archive-test")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	fsm, err := recoverylog.NewFSM(recoverylog.FSMHints{Log: specA.RecoveryLog()})
+	require.NoError(t, err)
+	var rec = recoverylog.NewRecorder(specA.RecoveryLog(), fsm, recoverylog.NewRandomAuthor(), dir, tf.ajc)
+
+	store, err := NewJSONFileStore(rec, &map[string]string{"key": "value"})
+	require.NoError(t, err)
+
+	var cp = pc.Checkpoint{Sources: map[pb.Journal]pc.Checkpoint_Source{
+		sourceA.Name: {ReadThrough: int64(len(sourceAWriteFixture))},
+	}}
+	require.NoError(t, store.StartCommit(nil, cp, nil).Err())
+
+	hints, err := rec.BuildHints()
+	require.NoError(t, err)
+	hintsJSON, err := json.Marshal(hints)
+	require.NoError(t, err)
+	_, err = tf.etcd.Put(ctx, specA.HintPrimaryKey(), string(hintsJSON))
+	require.NoError(t, err)
+
+	// Returns the Checkpoint and state of a JSONFileStore recovered into |dir|.
+	var readStore = func(dir string) (pc.Checkpoint, map[string]string, error) {
+		var f, err = os.Open(filepath.Join(dir, "state.json"))
+		if err != nil {
+			return pc.Checkpoint{}, nil, err
+		}
+		defer f.Close()
+
+		var dec = json.NewDecoder(f)
+		var offsets pb.Offsets
+		var state map[string]string
+		var cp pc.Checkpoint
+
+		if err = dec.Decode(&offsets); err == nil {
+			if err = dec.Decode(&state); err == nil {
+				err = dec.Decode(&cp)
+			}
+		}
+		return cp, state, err
+	}
+
+	// Export shard A.
+	var archive bytes.Buffer
+	manifest, err := ExportShard(ctx, ExportShardArgs{
+		Spec:        specA,
+		Etcd:        tf.etcd,
+		Journals:    tf.broker.Client(),
+		StoreSchema: "json/v1",
+		Checkpoint: func(dir string) (pc.Checkpoint, error) {
+			var cp, _, err = readStore(dir)
+			return cp, err
+		},
+	}, &archive)
+	require.NoError(t, err)
+	require.Equal(t, shardArchiveVersion, manifest.Version)
+	require.Equal(t, specA.Id, manifest.Shard)
+	require.Equal(t, specA.RecoveryLog(), manifest.RecoveryLog)
+	require.Equal(t, &cp, manifest.Checkpoint)
+
+	var importArgs = ImportShardArgs{
+		Spec:        specB,
+		Etcd:        tf.etcd,
+		Journals:    tf.broker.Client(),
+		StoreSchema: "json/v1",
+	}
+
+	// Case: shard must be disabled.
+	_, err = ImportShard(ctx, importArgs, bytes.NewReader(archive.Bytes()))
+	require.EqualError(t, err, "shard shard-B must be disabled during import")
+	specB.Disable = true
+
+	// Case: StoreSchema must match.
+	importArgs.StoreSchema = "json/v2"
+	_, err = ImportShard(ctx, importArgs, bytes.NewReader(archive.Bytes()))
+	require.EqualError(t, err, `archive StoreSchema "json/v1" is incompatible with expected StoreSchema "json/v2"`)
+	importArgs.StoreSchema = "json/v1"
+
+	// Case: archive is corrupt.
+	_, err = ImportShard(ctx, importArgs, bytes.NewReader(archive.Bytes()[:archive.Len()/2]))
+	require.Error(t, err)
+
+	// Case: success. Import into shard B.
+	imported, err := ImportShard(ctx, importArgs, bytes.NewReader(archive.Bytes()))
+	require.NoError(t, err)
+	require.Equal(t, manifest.ExportedAt.Unix(), imported.ExportedAt.Unix())
+	require.Equal(t, manifest.Checkpoint, imported.Checkpoint)
+
+	// Expect shard B's hints recover the exported Store from its own recovery log.
+	fetched, err := fetchHints(ctx, specB, tf.etcd)
+	require.NoError(t, err)
+	require.NotNil(t, fetched.hints[0])
+	require.Equal(t, specB.RecoveryLog(), fetched.hints[0].Log)
+
+	var playDir = filepath.Join(dir, "played")
+	var player = recoverylog.NewPlayer()
+	player.FinishAtWriteHead()
+	require.NoError(t, player.Play(ctx, *fetched.hints[0], playDir, tf.ajc))
+
+	recoveredCP, state, err := readStore(playDir)
+	require.NoError(t, err)
+	require.Equal(t, cp, recoveredCP)
+	require.Equal(t, map[string]string{"key": "value"}, state)
+
+	// Case: an import never overwrites existing hints of a shard.
+	_, err = ImportShard(ctx, importArgs, bytes.NewReader(archive.Bytes()))
+	require.EqualError(t, err, "shard shard-B has existing hints")
+
+	// Case: source journals must be written through checkpoint offsets.
+	archive.Reset()
+	var specC = makeShard(shardC)
+	specC.Disable = true
+
+	_, err = ExportShard(ctx, ExportShardArgs{
+		Spec:     specA,
+		Etcd:     tf.etcd,
+		Journals: tf.broker.Client(),
+		Checkpoint: func(string) (pc.Checkpoint, error) {
+			return pc.Checkpoint{Sources: map[pb.Journal]pc.Checkpoint_Source{
+				sourceA.Name: {ReadThrough: 1 << 20},
+			}}, nil
+		},
+	}, &archive)
+	require.NoError(t, err)
+
+	_, err = ImportShard(ctx, ImportShardArgs{Spec: specC, Etcd: tf.etcd, Journals: tf.broker.Client()}, &archive)
+	require.EqualError(t, err, "checkpoint source source/A has write head 19, which is less than checkpoint offset 1048576")
+
+	// Case: a shard without hints cannot be exported.
+	_, err = ExportShard(ctx, ExportShardArgs{Spec: specC, Etcd: tf.etcd, Journals: tf.broker.Client()}, &archive)
+	require.EqualError(t, err, "shard shard-C has no stored hints")
+}
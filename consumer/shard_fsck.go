@@ -0,0 +1,286 @@
+package consumer
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io/ioutil"
+	"os"
+
+	"github.com/pkg/errors"
+	clientv3 "go.etcd.io/etcd/client/v3"
+	pb "go.gazette.dev/core/broker/protocol"
+	pc "go.gazette.dev/core/consumer/protocol"
+)
+
+// FsckShardArgs are arguments of FsckShard.
+type FsckShardArgs struct {
+	// Spec of the shard to check.
+	Spec *pc.ShardSpec
+	// Etcd client of the shard's consumer cluster, from which hints are read.
+	Etcd *clientv3.Client
+	// Journals client of the shard's broker cluster.
+	Journals pb.RoutedJournalClient
+	// ScanRecovered scans the records of a Store recovered into |dir|,
+	// calling |fn| with each key and value in ascending key order. It returns
+	// the Checkpoint of the recovered Store.
+	ScanRecovered func(dir string, fn func(key, value []byte) error) (pc.Checkpoint, error)
+	// ScanLive scans the records of the shard's live Store, calling |fn| with
+	// each key and value in ascending key order. It returns the Checkpoint of
+	// the scanned records. The live Store must not commit transactions while
+	// it's scanned: ScanLive must either scan a consistent snapshot of the
+	// Store (such as within a read transaction of the Store), or the shard
+	// must be quiesced for the duration of FsckShard.
+	ScanLive func(fn func(key, value []byte) error) (pc.Checkpoint, error)
+}
+
+// FsckReport is the result of FsckShard.
+type FsckReport struct {
+	Shard pc.ShardID
+	// RecoveryLog from which the recovered Store was played.
+	RecoveryLog pb.Journal
+	// Checkpoint of both the recovered and the live Store.
+	Checkpoint pc.Checkpoint
+	// Records of the recovered and the live Store.
+	RecoveredRecords, LiveRecords int
+	// Hex-encoded SHA-256 digests of the records of each Store.
+	RecoveredDigest, LiveDigest string
+	// Mismatches is the number of keys whose records differ between Stores,
+	// or which are present in only one of them.
+	Mismatches int
+	// First is the first divergent record in key order, or nil if the Stores
+	// are consistent.
+	First *FsckDivergence
+}
+
+// FsckDivergence is a record which differs between the recovered and live Stores.
+type FsckDivergence struct {
+	Key []byte
+	// Recovered and Live values of the Key, or nil if the Store lacks the Key.
+	Recovered, Live []byte
+	// Reason describes the divergence.
+	Reason string
+}
+
+// Consistent returns true if the recovered and live Stores are identical.
+func (r FsckReport) Consistent() bool {
+	return r.Mismatches == 0 && r.RecoveredDigest == r.LiveDigest
+}
+
+// ErrFsckCheckpointMismatch is returned by FsckShard if the Checkpoints of
+// the recovered and live Stores differ. This happens if the live Store
+// committed transactions during the check, or was scanned before its latest
+// transaction was recorded, and the Stores aren't comparable. The check may
+// be retried.
+var ErrFsckCheckpointMismatch = errors.New("recovered and live store checkpoints differ")
+
+// FsckShard verifies that a shard's live Store matches the content which its
+// recovery log says it should have, catching silent divergence of the Store
+// and its log. The recovery log is played into a scratch directory through
+// its current write head using the most recent hints of the shard, as with
+// ExportShard, and records of the recovered and live Stores are compared.
+//
+// The live Store must be quiesced or scanned at a consistent snapshot (see
+// FsckShardArgs.ScanLive). Records are compared only if the recovered and live
+// Stores have equal Checkpoints, and otherwise FsckShard fails with
+// ErrFsckCheckpointMismatch. The returned FsckReport has digests of each
+// Store, and locates the first divergent record. It's not an error for the
+// Stores to diverge: callers must examine FsckReport.Consistent.
+func FsckShard(ctx context.Context, args FsckShardArgs) (FsckReport, error) {
+	var report = FsckReport{
+		Shard:       args.Spec.Id,
+		RecoveryLog: args.Spec.RecoveryLog(),
+	}
+	var dir, err = ioutil.TempDir("", "shard-fsck-")
+	if err != nil {
+		return report, err
+	}
+	defer os.RemoveAll(dir)
+
+	if err = playLatestHints(ctx, args.Spec, args.Etcd, args.Journals, dir); err != nil {
+		return report, err
+	}
+
+	// Scan the recovered Store in the background, comparing each of its
+	// records with those of the live Store as it's scanned.
+	var recoveredCh = make(chan fsckRecord, 64)
+	var doneCh = make(chan struct{})
+	var recoveredCP pc.Checkpoint
+	var recoveredErr error
+
+	go func() {
+		defer close(recoveredCh)
+
+		var digest = newFsckDigest()
+		var last []byte
+
+		recoveredCP, recoveredErr = args.ScanRecovered(dir, func(key, value []byte) error {
+			if digest.n != 0 && bytes.Compare(last, key) >= 0 {
+				return fmt.Errorf("keys are not in ascending order (%q then %q)", last, key)
+			}
+			last = append(last[:0], key...)
+			digest.add(key, value)
+
+			var rec = fsckRecord{
+				key:   append([]byte{}, key...),
+				value: append([]byte{}, value...),
+			}
+			select {
+			case recoveredCh <- rec:
+				return nil
+			case <-doneCh:
+				return errors.New("fsck was aborted")
+			}
+		})
+		report.RecoveredRecords, report.RecoveredDigest = digest.n, digest.sum()
+	}()
+
+	var cmp = fsckComparator{report: &report, recoveredCh: recoveredCh}
+	var liveDigest = newFsckDigest()
+
+	liveCP, err := args.ScanLive(func(key, value []byte) error {
+		liveDigest.add(key, value)
+		return cmp.live(key, value)
+	})
+	if err == nil {
+		cmp.finish()
+	}
+	close(doneCh)
+	for range recoveredCh {
+		// Drain, so that the scan of the recovered Store finishes.
+	}
+	report.LiveRecords, report.LiveDigest = liveDigest.n, liveDigest.sum()
+
+	if err != nil {
+		return report, errors.WithMessage(err, "scanning live store")
+	} else if recoveredErr != nil {
+		return report, errors.WithMessage(recoveredErr, "scanning recovered store")
+	} else if !equalReadThrough(recoveredCP, liveCP) {
+		return report, errors.WithMessagef(ErrFsckCheckpointMismatch,
+			"recovered checkpoint reads through %v, and live checkpoint reads through %v",
+			pc.FlattenReadThrough(recoveredCP), pc.FlattenReadThrough(liveCP))
+	}
+	report.Checkpoint = liveCP
+
+	return report, nil
+}
+
+type fsckRecord struct{ key, value []byte }
+
+// fsckComparator merges ordered records of the recovered and live Stores,
+// tracking their mismatches within a FsckReport.
+type fsckComparator struct {
+	report      *FsckReport
+	recoveredCh <-chan fsckRecord
+	next        *fsckRecord // Next recovered record, if already read.
+	last        []byte      // Last live key.
+	n           int         // Number of live records.
+}
+
+// live compares a record of the live Store with recovered records.
+func (c *fsckComparator) live(key, value []byte) error {
+	if c.n++; c.n != 1 && bytes.Compare(c.last, key) >= 0 {
+		return fmt.Errorf("keys are not in ascending order (%q then %q)", c.last, key)
+	}
+	c.last = append(c.last[:0], key...)
+
+	for {
+		var rec, ok = c.peek()
+		if !ok {
+			c.mismatch(key, nil, value, "key is present only in the live store")
+			return nil
+		}
+
+		switch bytes.Compare(rec.key, key) {
+		case -1:
+			c.mismatch(rec.key, rec.value, nil, "key is present only in the recovered store")
+			c.next = nil
+			continue
+		case 1:
+			c.mismatch(key, nil, value, "key is present only in the live store")
+		default:
+			if !bytes.Equal(rec.value, value) {
+				c.mismatch(key, rec.value, value, "values differ")
+			}
+			c.next = nil
+		}
+		return nil
+	}
+}
+
+// finish consumes remaining recovered records.
+func (c *fsckComparator) finish() {
+	for {
+		var rec, ok = c.peek()
+		if !ok {
+			return
+		}
+		c.mismatch(rec.key, rec.value, nil, "key is present only in the recovered store")
+		c.next = nil
+	}
+}
+
+// peek returns the next recovered record, or false if none remain.
+func (c *fsckComparator) peek() (fsckRecord, bool) {
+	if c.next == nil {
+		if rec, ok := <-c.recoveredCh; ok {
+			c.next = &rec
+		} else {
+			return fsckRecord{}, false
+		}
+	}
+	return *c.next, true
+}
+
+// mismatch records a divergent record, where |live| is retained only by the
+// live scan and must be copied. A nil |recovered| or |live| is absent.
+func (c *fsckComparator) mismatch(key, recovered, live []byte, reason string) {
+	if c.report.Mismatches++; c.report.First != nil {
+		return
+	}
+	c.report.First = &FsckDivergence{
+		Key:       append([]byte{}, key...),
+		Recovered: recovered,
+		Reason:    reason,
+	}
+	if live != nil {
+		c.report.First.Live = append([]byte{}, live...)
+	}
+}
+
+// fsckDigest is a digest of an ordered sequence of records.
+type fsckDigest struct {
+	h hash.Hash
+	n int
+}
+
+func newFsckDigest() *fsckDigest { return &fsckDigest{h: sha256.New()} }
+
+func (d *fsckDigest) add(key, value []byte) {
+	var b [binary.MaxVarintLen64]byte
+	for _, p := range [][]byte{key, value} {
+		_, _ = d.h.Write(b[:binary.PutUvarint(b[:], uint64(len(p)))])
+		_, _ = d.h.Write(p)
+	}
+	d.n++
+}
+
+func (d *fsckDigest) sum() string { return hex.EncodeToString(d.h.Sum(nil)) }
+
+// equalReadThrough returns true if the Checkpoints read through equal offsets.
+func equalReadThrough(a, b pc.Checkpoint) bool {
+	var ra, rb = pc.FlattenReadThrough(a), pc.FlattenReadThrough(b)
+	if len(ra) != len(rb) {
+		return false
+	}
+	for journal, offset := range ra {
+		if o, ok := rb[journal]; !ok || o != offset {
+			return false
+		}
+	}
+	return true
+}
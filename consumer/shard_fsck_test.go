@@ -0,0 +1,157 @@
+package consumer
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	pb "go.gazette.dev/core/broker/protocol"
+	pc "go.gazette.dev/core/consumer/protocol"
+	"go.gazette.dev/core/consumer/recoverylog"
+)
+
+func TestShardFsck(t *testing.T) {
+	var tf, cleanup = newTestFixture(t)
+	defer cleanup()
+
+	var ctx = context.Background()
+	var spec = makeShard(shardA)
+
+	// Build a fixture of a live JSONFileStore of a recorded checkpoint,
+	// and stored hints of its recovery log.
+	var dir, err = ioutil.TempDir("", "shard-fsck-test")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	fsm, err := recoverylog.NewFSM(recoverylog.FSMHints{Log: spec.RecoveryLog()})
+	require.NoError(t, err)
+	var rec = recoverylog.NewRecorder(spec.RecoveryLog(), fsm, recoverylog.NewRandomAuthor(), dir, tf.ajc)
+
+	var live = map[string]string{"a": "1", "b": "2", "c": "3"}
+	store, err := NewJSONFileStore(rec, &live)
+	require.NoError(t, err)
+
+	var cp = pc.Checkpoint{Sources: map[pb.Journal]pc.Checkpoint_Source{
+		sourceA.Name: {ReadThrough: int64(len(sourceAWriteFixture))},
+	}}
+	require.NoError(t, store.StartCommit(nil, cp, nil).Err())
+
+	hints, err := rec.BuildHints()
+	require.NoError(t, err)
+	hintsJSON, err := json.Marshal(hints)
+	require.NoError(t, err)
+	_, err = tf.etcd.Put(ctx, spec.HintPrimaryKey(), string(hintsJSON))
+	require.NoError(t, err)
+
+	var args = FsckShardArgs{
+		Spec:     spec,
+		Etcd:     tf.etcd,
+		Journals: tf.broker.Client(),
+		ScanRecovered: func(dir string, fn func(key, value []byte) error) (pc.Checkpoint, error) {
+			var f, err = os.Open(filepath.Join(dir, "state.json"))
+			if err != nil {
+				return pc.Checkpoint{}, err
+			}
+			defer f.Close()
+
+			var dec = json.NewDecoder(f)
+			var offsets pb.Offsets
+			var state map[string]string
+			var cp pc.Checkpoint
+
+			if err = dec.Decode(&offsets); err == nil {
+				if err = dec.Decode(&state); err == nil {
+					err = dec.Decode(&cp)
+				}
+			}
+			if err == nil {
+				err = scanStringMap(state, fn)
+			}
+			return cp, err
+		},
+		ScanLive: func(fn func(key, value []byte) error) (pc.Checkpoint, error) {
+			return store.checkpoint, scanStringMap(live, fn)
+		},
+	}
+
+	// Case: stores are consistent.
+	report, err := FsckShard(ctx, args)
+	require.NoError(t, err)
+	require.True(t, report.Consistent())
+	require.Equal(t, 3, report.RecoveredRecords)
+	require.Equal(t, 3, report.LiveRecords)
+	require.Equal(t, report.RecoveredDigest, report.LiveDigest)
+	require.Equal(t, cp, report.Checkpoint)
+	require.Nil(t, report.First)
+
+	// Case: the live store silently diverged from its recovery log.
+	live["b"] = "two"
+	delete(live, "c")
+	live["d"] = "4"
+
+	report, err = FsckShard(ctx, args)
+	require.NoError(t, err)
+	require.False(t, report.Consistent())
+	require.NotEqual(t, report.RecoveredDigest, report.LiveDigest)
+	require.Equal(t, 3, report.Mismatches)
+	require.Equal(t, &FsckDivergence{
+		Key:       []byte("b"),
+		Recovered: []byte("2"),
+		Live:      []byte("two"),
+		Reason:    "values differ",
+	}, report.First)
+
+	// Case: the live store has only a subset of recovered records.
+	live = map[string]string{"a": "1", "b": "2"}
+
+	report, err = FsckShard(ctx, args)
+	require.NoError(t, err)
+	require.Equal(t, 1, report.Mismatches)
+	require.Equal(t, &FsckDivergence{
+		Key:       []byte("c"),
+		Recovered: []byte("3"),
+		Reason:    "key is present only in the recovered store",
+	}, report.First)
+
+	// Case: the live store committed a transaction not yet recorded.
+	store.checkpoint = pc.Checkpoint{Sources: map[pb.Journal]pc.Checkpoint_Source{
+		sourceA.Name: {ReadThrough: int64(len(sourceAWriteFixture)) + 10},
+	}}
+	_, err = FsckShard(ctx, args)
+	require.True(t, errors.Is(err, ErrFsckCheckpointMismatch))
+	require.Regexp(t, "recovered checkpoint reads through .*, and live checkpoint reads through .*", err)
+
+	// Case: a scan fails.
+	args.ScanLive = func(fn func(key, value []byte) error) (pc.Checkpoint, error) {
+		return cp, errors.New("whoops")
+	}
+	_, err = FsckShard(ctx, args)
+	require.EqualError(t, err, "scanning live store: whoops")
+
+	// Case: a shard without hints cannot be checked.
+	args.Spec = makeShard(shardB)
+	_, err = FsckShard(ctx, args)
+	require.EqualError(t, err, "shard shard-B has no stored hints")
+}
+
+// scanStringMap calls |fn| with each key and value of |m|, in key order.
+func scanStringMap(m map[string]string, fn func(key, value []byte) error) error {
+	var keys []string
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		if err := fn([]byte(k), []byte(m[k])); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,149 @@
+package consumer
+
+import (
+	"context"
+
+	"go.gazette.dev/core/allocator"
+	pb "go.gazette.dev/core/broker/protocol"
+	pbx "go.gazette.dev/core/broker/protocol/ext"
+	pc "go.gazette.dev/core/consumer/protocol"
+)
+
+// ShardRoute is the current route of a shard: the consumer members to which
+// it's assigned, their endpoints, and the ReplicaStatus of each. It parallels
+// the Route of a broker journal, and is used by clients which route reads of
+// shard-derived state to its primary, or to its standbys.
+type ShardRoute struct {
+	Shard pc.ShardID
+	// Etcd Revision of the ShardRoute.
+	Revision int64
+	// Route of the shard, including endpoints. Route.Primary indexes the
+	// member assigned as primary, or is -1 if the shard has no primary.
+	Route pb.Route
+	// Status of each member. Cardinality and ordering matches |Route|.
+	Status []pc.ReplicaStatus
+}
+
+// Primary returns the member which is assigned as primary of the shard and
+// has completed its recovery to become PRIMARY, and its endpoint. It returns
+// false if the shard has no such member, as is the case mid-handoff:
+// an assigned primary may still be recovering, and a demoted primary may not
+// yet have released the shard. Callers should retry after a subsequent
+// ShardRoute rather than route to a member which isn't its ready primary.
+func (r ShardRoute) Primary() (pb.ProcessSpec_ID, pb.Endpoint, bool) {
+	if r.Route.Primary == -1 || r.InHandoff() {
+		return pb.ProcessSpec_ID{}, "", false
+	}
+	return r.Route.Members[r.Route.Primary], r.endpoint(int(r.Route.Primary)), true
+}
+
+// Standbys returns the members which are live-tailing the shard's recovery
+// log as STANDBY (and not as its assigned primary), and their endpoints.
+func (r ShardRoute) Standbys() ([]pb.ProcessSpec_ID, []pb.Endpoint) {
+	var ids []pb.ProcessSpec_ID
+	var endpoints []pb.Endpoint
+
+	for i, status := range r.Status {
+		if i != int(r.Route.Primary) && status.Code == pc.ReplicaStatus_STANDBY {
+			ids = append(ids, r.Route.Members[i])
+			endpoints = append(endpoints, r.endpoint(i))
+		}
+	}
+	return ids, endpoints
+}
+
+// endpoint returns the Endpoint of the member at index |i|, or empty if the
+// Route has no Endpoints.
+func (r ShardRoute) endpoint(i int) pb.Endpoint {
+	if i < len(r.Route.Endpoints) {
+		return r.Route.Endpoints[i]
+	}
+	return ""
+}
+
+// InHandoff returns true if primary responsibility of the shard is
+// transitioning between members: its assigned primary is not yet PRIMARY,
+// or a member which is not its assigned primary still reports PRIMARY.
+func (r ShardRoute) InHandoff() bool {
+	for i, status := range r.Status {
+		if i == int(r.Route.Primary) && status.Code != pc.ReplicaStatus_PRIMARY {
+			return true
+		} else if i != int(r.Route.Primary) && status.Code == pc.ReplicaStatus_PRIMARY {
+			return true
+		}
+	}
+	return false
+}
+
+// Equal returns true if the ShardRoutes have equal Routes and replica status Codes.
+func (r ShardRoute) Equal(other ShardRoute) bool {
+	if r.Shard != other.Shard || !r.Route.Equal(&other.Route) || len(r.Status) != len(other.Status) {
+		return false
+	}
+	for i := range r.Status {
+		if r.Status[i].Code != other.Status[i].Code {
+			return false
+		}
+	}
+	return true
+}
+
+// NewListedShardRoute returns the ShardRoute of a ListResponse_Shard, having
+// the ListResponse Header. Clients which are not themselves consumer members
+// may use the List RPC to fetch ShardRoutes.
+func NewListedShardRoute(hdr pb.Header, shard pc.ListResponse_Shard) ShardRoute {
+	return ShardRoute{
+		Shard:    shard.Spec.Id,
+		Revision: hdr.Etcd.Revision,
+		Route:    shard.Route.Copy(),
+		Status:   append([]pc.ReplicaStatus(nil), shard.Status...),
+	}
+}
+
+// ShardRoute returns the current ShardRoute of the shard, or false if the
+// shard doesn't exist. ShardRoute read-locks the KeySpace.
+func (r *Resolver) ShardRoute(id pc.ShardID) (ShardRoute, bool) {
+	var ks = r.state.KS
+
+	ks.Mu.RLock()
+	defer ks.Mu.RUnlock()
+
+	return r.shardRoute(id)
+}
+
+// WatchShardRoute blocks until the ShardRoute of |prior|'s shard is no longer
+// Equal to |prior|, and then returns it. A shard which doesn't exist has a
+// ShardRoute without members, which WatchShardRoute returns upon the shard's
+// deletion. WatchShardRoute read-locks the KeySpace.
+func (r *Resolver) WatchShardRoute(ctx context.Context, prior ShardRoute) (ShardRoute, error) {
+	var ks = r.state.KS
+
+	ks.Mu.RLock()
+	defer ks.Mu.RUnlock()
+
+	for {
+		var next, _ = r.shardRoute(prior.Shard)
+		if !next.Equal(prior) {
+			return next, nil
+		} else if err := ks.WaitForRevision(ctx, ks.Header.Revision+1); err != nil {
+			return ShardRoute{}, err
+		}
+	}
+}
+
+// shardRoute returns the ShardRoute of the shard. The KeySpace must be read-locked.
+func (r *Resolver) shardRoute(id pc.ShardID) (ShardRoute, bool) {
+	var ks = r.state.KS
+	var _, ok = allocator.LookupItem(ks, id.String())
+	var out = ShardRoute{Shard: id, Revision: ks.Header.Revision}
+
+	var assignments = ks.KeyValues.Prefixed(allocator.ItemAssignmentsPrefix(ks, id.String()))
+	pbx.Init(&out.Route, assignments)
+	pbx.AttachEndpoints(&out.Route, ks)
+
+	for _, asn := range assignments {
+		out.Status = append(out.Status,
+			*asn.Decoded.(allocator.Assignment).AssignmentValue.(*pc.ReplicaStatus))
+	}
+	return out, ok
+}
@@ -0,0 +1,92 @@
+package consumer
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	pb "go.gazette.dev/core/broker/protocol"
+	pc "go.gazette.dev/core/consumer/protocol"
+)
+
+func TestShardRouteCases(t *testing.T) {
+	var tf, cleanup = newTestFixture(t)
+	defer cleanup()
+	defer disableShardTransitions()()
+
+	// Case: shard doesn't exist.
+	var route, ok = tf.resolver.ShardRoute(shardA)
+	require.False(t, ok)
+	require.Equal(t, pb.Route{Primary: -1}, route.Route)
+
+	// Case: shard has a ready primary, and a standby.
+	var spec = makeShard(shardA)
+	tf.allocateShard(spec, remoteID, localID)
+	tf.setReplicaStatus(spec, remoteID, 0, pc.ReplicaStatus_PRIMARY)
+	tf.setReplicaStatus(spec, localID, 1, pc.ReplicaStatus_STANDBY)
+
+	route, ok = tf.resolver.ShardRoute(shardA)
+	require.True(t, ok)
+	require.Equal(t, pb.Route{
+		Members:   []pb.ProcessSpec_ID{localID, remoteID},
+		Primary:   1,
+		Endpoints: []pb.Endpoint{"http://local/endpoint", "http://remote/endpoint"},
+	}, route.Route)
+	require.False(t, route.InHandoff())
+
+	var id, ep, ready = route.Primary()
+	require.True(t, ready)
+	require.Equal(t, remoteID, id)
+	require.Equal(t, pb.Endpoint("http://remote/endpoint"), ep)
+
+	var ids, eps = route.Standbys()
+	require.Equal(t, []pb.ProcessSpec_ID{localID}, ids)
+	require.Equal(t, []pb.Endpoint{"http://local/endpoint"}, eps)
+
+	// Expect a ShardRoute of the List RPC is equal.
+	var resp, err = tf.service.List(context.Background(), &pc.ListRequest{
+		Selector: pb.LabelSelector{Include: pb.MustLabelSet("id", shardA)},
+	})
+	require.NoError(t, err)
+	require.Len(t, resp.Shards, 1)
+	require.True(t, route.Equal(NewListedShardRoute(resp.Header, resp.Shards[0])))
+
+	// Case: mid-handoff, the new primary is recovering,
+	// and the demoted primary hasn't yet released the shard.
+	tf.allocateShard(spec, localID, remoteID)
+	tf.setReplicaStatus(spec, localID, 0, pc.ReplicaStatus_BACKFILL)
+	tf.setReplicaStatus(spec, remoteID, 1, pc.ReplicaStatus_PRIMARY)
+
+	var prior = route
+	route, err = tf.resolver.WatchShardRoute(context.Background(), prior)
+	require.NoError(t, err)
+	require.Equal(t, int32(0), route.Route.Primary)
+	require.True(t, route.InHandoff())
+
+	_, _, ready = route.Primary()
+	require.False(t, ready)
+	ids, _ = route.Standbys()
+	require.Empty(t, ids)
+
+	// Expect WatchShardRoute blocks until the handoff completes.
+	go func() {
+		tf.setReplicaStatus(spec, remoteID, 1, pc.ReplicaStatus_STANDBY)
+		tf.setReplicaStatus(spec, localID, 0, pc.ReplicaStatus_PRIMARY)
+	}()
+	for route.InHandoff() {
+		route, err = tf.resolver.WatchShardRoute(context.Background(), route)
+		require.NoError(t, err)
+	}
+	id, ep, ready = route.Primary()
+	require.True(t, ready)
+	require.Equal(t, localID, id)
+	require.Equal(t, pb.Endpoint("http://local/endpoint"), ep)
+
+	// Case: WatchShardRoute returns a context error.
+	var ctx, cancel = context.WithCancel(context.Background())
+	cancel()
+	_, err = tf.resolver.WatchShardRoute(ctx, route)
+	require.Equal(t, context.Canceled, err)
+
+	tf.allocateShard(spec) // Cleanup.
+}
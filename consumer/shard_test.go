@@ -4,9 +4,11 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 	pb "go.gazette.dev/core/broker/protocol"
+	"go.gazette.dev/core/brokertest"
 	pc "go.gazette.dev/core/consumer/protocol"
 	"go.gazette.dev/core/labels"
 	"go.gazette.dev/core/message"
@@ -42,6 +44,118 @@ func TestReadMessages(t *testing.T) {
 	require.Regexp(t, `framing.Unmarshal\(offset \d+\): context canceled`, (<-ch).Error)
 }
 
+func TestReadMessagesFromResolvedSources(t *testing.T) {
+	var tf, shard, cleanup = newTestFixtureWithIdleShard(t)
+
+	defer func(d time.Duration) { sourceListInterval = d }(sourceListInterval)
+	sourceListInterval = 10 * time.Millisecond
+
+	// Write a fixture to sourceA that's skipped by the checkpoint.
+	var aa, _ = tf.pub.PublishCommitted(toSourceA, &testMessage{Key: "skipped"})
+	<-aa.Done()
+
+	var cp = pc.Checkpoint{
+		Sources: map[pb.Journal]pc.Checkpoint_Source{
+			sourceA.Name: {ReadThrough: aa.Response().Commit.End},
+		},
+	}
+
+	var ch = make(chan EnvelopeOrError, 12)
+	startReadingResolvedSources(shard, testSourceResolver{}, cp, ch)
+
+	_, _ = tf.pub.PublishCommitted(toSourceA, &testMessage{Key: "one"})
+	require.Equal(t, "one", (<-ch).Envelope.Message.(*testMessage).Key)
+	_, _ = tf.pub.PublishCommitted(toSourceB, &testMessage{Key: "two"})
+	require.Equal(t, "two", (<-ch).Envelope.Message.(*testMessage).Key)
+
+	// Create a new source journal, which is read from its beginning once listed.
+	var sourceC = brokertest.Journal(pb.JournalSpec{
+		Name:     "source/C",
+		LabelSet: pb.MustLabelSet(labels.ContentType, labels.ContentType_JSONLines),
+	})
+	brokertest.CreateJournals(t, tf.broker, sourceC)
+
+	var toSourceC = func(message.Mappable) (pb.Journal, string, error) {
+		return sourceC.Name, labels.ContentType_JSONLines, nil
+	}
+	_, _ = tf.pub.PublishCommitted(toSourceC, &testMessage{Key: "three"})
+
+	var env = (<-ch).Envelope
+	require.Equal(t, sourceC.Name, env.Journal.Name)
+	require.Equal(t, pb.Offset(0), env.Begin)
+	require.Equal(t, "three", env.Message.(*testMessage).Key)
+
+	shard.progress.Lock()
+	require.Contains(t, shard.progress.readThrough, sourceC.Name)
+	shard.progress.Unlock()
+
+	// Reads of existing sources are undisturbed.
+	_, _ = tf.pub.PublishCommitted(toSourceA, &testMessage{Key: "four"})
+	require.Equal(t, "four", (<-ch).Envelope.Message.(*testMessage).Key)
+
+	cleanup()
+	require.Regexp(t, `framing.Unmarshal\(offset \d+\): context canceled`, (<-ch).Error)
+}
+
+func TestReadMessagesWithStartPolicy(t *testing.T) {
+	var tf, shard, cleanup = newTestFixtureWithIdleShard(t)
+	defer cleanup()
+
+	// Write a fixture to sourceA which precedes its write head.
+	var aa, _ = tf.pub.PublishCommitted(toSourceA, &testMessage{Key: "before"})
+	<-aa.Done()
+	var head = aa.Response().Commit.End
+
+	// A recovered Checkpoint, which has no offset for sourceA.
+	var recovered = pc.Checkpoint{
+		Sources: map[pb.Journal]pc.Checkpoint_Source{sourceB.Name: {ReadThrough: 0}},
+	}
+	// The fixture's sourceA has a MinOffset which skips over invalid content.
+	var src = shard.Spec().Sources[0]
+	require.Equal(t, sourceA.Name, src.Journal)
+	var min = src.MinOffset
+
+	for _, tc := range []struct {
+		policy    pc.ShardSpec_StartPolicy
+		startTime int64
+		cp        pc.Checkpoint
+		expect    pb.Offset
+	}{
+		{pc.ShardSpec_START_AT_MIN_OFFSET, 0, pc.Checkpoint{}, min},
+		{pc.ShardSpec_START_AT_HEAD, 0, pc.Checkpoint{}, head},
+		// The policy doesn't apply to a recovered shard.
+		{pc.ShardSpec_START_AT_HEAD, 0, recovered, min},
+		// Fragments of the test broker are never persisted, and have no
+		// modification times. The journal is read from its MinOffset.
+		{pc.ShardSpec_START_AT_TIME, time.Now().Unix(), pc.Checkpoint{}, min},
+		{pc.ShardSpec_START_AT_TIME, time.Now().Unix(), recovered, min},
+	} {
+		shard.Spec().StartPolicy, shard.Spec().StartTime = tc.policy, tc.startTime
+
+		var offset, err = resolveStartOffset(shard.ctx, shard, src, tc.cp)
+		require.NoError(t, err)
+		require.Equal(t, tc.expect, offset, tc.policy.String())
+	}
+
+	// Resolved offsets are lower-bounded by the source MinOffset.
+	var bounded = src
+	bounded.MinOffset = head + 1
+	shard.Spec().StartPolicy, shard.Spec().StartTime = pc.ShardSpec_START_AT_HEAD, 0
+
+	var offset, err = resolveStartOffset(shard.ctx, shard, bounded, pc.Checkpoint{})
+	require.NoError(t, err)
+	require.Equal(t, head+1, offset)
+
+	// Expect reads of a newly assigned shard begin from the write head.
+	var ch = make(chan EnvelopeOrError, 12)
+	startReadingMessages(shard, pc.Checkpoint{}, ch)
+
+	_, _ = tf.pub.PublishCommitted(toSourceA, &testMessage{Key: "after"})
+	var env = (<-ch).Envelope
+	require.Equal(t, head, env.Begin)
+	require.Equal(t, "after", env.Message.(*testMessage).Key)
+}
+
 func TestReadMessagesFailsWithUnknownJournal(t *testing.T) {
 	var _, shard, cleanup = newTestFixtureWithIdleShard(t)
 	defer cleanup()
@@ -130,6 +244,49 @@ func TestShardTransitionsWithRecovery(t *testing.T) {
 	}
 }
 
+func TestShardEpochIncreasesAcrossAssignments(t *testing.T) {
+	var tf, cleanup = newTestFixture(t)
+	defer cleanup()
+
+	var spec = makeRemoteShard(shardA)
+	var localShard = func() *shard {
+		tf.ks.Mu.RLock()
+		defer tf.ks.Mu.RUnlock()
+		return tf.resolver.shards[shardA]
+	}
+
+	// A standby has no Epoch.
+	tf.allocateShard(spec, remoteID, localID)
+	expectStatusCode(t, tf.state, pc.ReplicaStatus_STANDBY)
+	var standby = localShard()
+	require.Equal(t, int64(0), standby.Epoch())
+
+	// The standby is promoted to primary.
+	tf.allocateShard(spec, localID)
+	expectStatusCode(t, tf.state, pc.ReplicaStatus_PRIMARY)
+	require.True(t, standby == localShard())
+
+	var epoch = standby.Epoch()
+	var asn = standby.Assignment()
+	require.Equal(t, asn.Raw.CreateRevision, epoch)
+	// Status updates of the Assignment don't change its Epoch.
+	require.Greater(t, asn.Raw.ModRevision, epoch)
+
+	// Hand off to a remote primary, and then back to a new local primary.
+	// The cancelled, stale primary retains its (now lesser) Epoch.
+	tf.allocateShard(spec, remoteID)
+	<-standby.Context().Done()
+	require.Equal(t, epoch, standby.Epoch())
+	tf.allocateShard(spec, localID)
+	expectStatusCode(t, tf.state, pc.ReplicaStatus_PRIMARY)
+
+	var primary = localShard()
+	require.False(t, primary == standby)
+	require.Greater(t, primary.Epoch(), epoch)
+
+	tf.allocateShard(spec) // Cleanup.
+}
+
 func TestShardRecoveryLogDoesntExist(t *testing.T) {
 	var tf, cleanup = newTestFixture(t)
 	defer cleanup()
@@ -222,3 +379,19 @@ func TestShardStoreStartCommitFails(t *testing.T) {
 
 	tf.allocateShard(spec) // Cleanup.
 }
+
+// testSourceResolver is a SourceResolver which reads from all journals
+// having prefix "source/".
+type testSourceResolver struct{}
+
+func (testSourceResolver) SourceSelector(Shard) pb.LabelSelector {
+	return pb.LabelSelector{Include: pb.MustLabelSet("prefix", "source/")}
+}
+
+func (testSourceResolver) ResolveSources(_ Shard, list *pb.ListResponse) []pc.ShardSpec_Source {
+	var out []pc.ShardSpec_Source
+	for _, j := range list.Journals {
+		out = append(out, pc.ShardSpec_Source{Journal: j.Spec.Name})
+	}
+	return out
+}
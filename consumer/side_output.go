@@ -0,0 +1,111 @@
+package consumer
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"go.gazette.dev/core/broker/client"
+	"go.gazette.dev/core/message"
+)
+
+// SideOutputs stages messages to output journals within a consumer
+// transaction, such that they commit atomically with the transaction's
+// Checkpoint. An Application stages messages with Stage as it consumes
+// messages of the transaction, and calls FinalizeTxn from its own
+// Application.FinalizeTxn:
+//
+//	func (app *MyApp) ConsumeMessage(shard Shard, store Store, env message.Envelope, pub *message.Publisher) error {
+//	    return app.outputs.Stage(pub, app.mapping, deriveEvent(env))
+//	}
+//
+//	func (app *MyApp) FinalizeTxn(shard Shard, store Store, pub *message.Publisher) error {
+//	    return app.outputs.FinalizeTxn(shard)
+//	}
+//
+// Staged messages are published uncommitted, and are acknowledged only after
+// the transaction's Checkpoint (which records their pending acknowledgements)
+// has committed to the Store. If the transaction fails, its staged messages
+// are never acknowledged and are discarded by read-committed readers of the
+// output journals: neither the Checkpoint nor the outputs are applied. Should
+// the shard fail after its Checkpoint commits but before all acknowledgements
+// are written, the process which next recovers the shard writes them.
+//
+// Output journals which are unavailable at commit time, such as because
+// they don't exist or have no ready brokers, would otherwise stall the
+// transaction indefinitely. FinalizeTxn instead waits up to Timeout for staged
+// messages to be written, and then aborts the transaction with an error
+// wrapping ErrSideOutputUnavailable. The shard fails without committing
+// its Checkpoint, and the transaction is retried in its entirety by the
+// process which next recovers the shard (eg, upon its re-assignment): its
+// messages are re-consumed and its outputs re-staged, while outputs of the
+// aborted attempt are never acknowledged.
+//
+// SideOutputs is used from the shard's transaction loop, and isn't safe for
+// concurrent use.
+type SideOutputs struct {
+	// Timeout is the maximum duration FinalizeTxn will wait for staged messages
+	// to be written to their output journals. If zero, DefaultSideOutputTimeout
+	// is used.
+	Timeout time.Duration
+
+	pending OpFutures // Appends of the current transaction.
+}
+
+// Stage publishes the Message uncommitted to the output journal of its
+// |mapping|, as part of the current consumer transaction. |pub| is the
+// Publisher passed to Application.ConsumeMessage or FinalizeTxn.
+func (o *SideOutputs) Stage(pub *message.Publisher, mapping message.MappingFunc, msg message.Message) error {
+	var aa, err = pub.PublishUncommitted(mapping, msg)
+	if err != nil {
+		return err
+	}
+	if o.pending == nil {
+		o.pending = make(OpFutures)
+	}
+	o.pending[aa] = struct{}{}
+	return nil
+}
+
+// FinalizeTxn waits for messages staged by the current transaction to be
+// written to their output journals, and must be called from the shard's
+// Application.FinalizeTxn. It returns an error wrapping
+// ErrSideOutputUnavailable if messages aren't written within the Timeout,
+// or the error of a failed write. Either aborts the transaction.
+func (o *SideOutputs) FinalizeTxn(shard Shard) error {
+	var pending = o.pending
+	o.pending = nil
+
+	var timeout = o.Timeout
+	if timeout == 0 {
+		timeout = DefaultSideOutputTimeout
+	}
+	var timer = time.NewTimer(timeout)
+	defer timer.Stop()
+
+	for op := range pending {
+		select {
+		case <-op.Done():
+			if err := op.Err(); err != nil {
+				return fmt.Errorf("writing side output: %w", err)
+			}
+		case <-timer.C:
+			var journal = "(unknown)"
+			if aa, ok := op.(*client.AsyncAppend); ok {
+				journal = aa.Request().Journal.String()
+			}
+			return fmt.Errorf("%w: journal %s wasn't written within %s", ErrSideOutputUnavailable, journal, timeout)
+		case <-shard.Context().Done():
+			return shard.Context().Err()
+		}
+	}
+	return nil
+}
+
+var (
+	// DefaultSideOutputTimeout is the SideOutputs Timeout used if none is set.
+	DefaultSideOutputTimeout = time.Minute
+	// ErrSideOutputUnavailable is wrapped by the error of a SideOutputs
+	// transaction which couldn't write its staged messages in time.
+	ErrSideOutputUnavailable = errors.New("side output journal is unavailable")
+)
@@ -0,0 +1,44 @@
+package consumer
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	pb "go.gazette.dev/core/broker/protocol"
+	"go.gazette.dev/core/labels"
+	"go.gazette.dev/core/message"
+)
+
+func TestSideOutputsStageAndFinalize(t *testing.T) {
+	var tf, shard, cleanup = newTestFixtureWithIdleShard(t)
+	defer cleanup()
+
+	var clock message.Clock
+	var pub = message.NewPublisher(tf.ajc, &clock)
+	var outputs = SideOutputs{Timeout: 100 * time.Millisecond}
+
+	// Case: staged messages are written, and pend acknowledgement.
+	require.NoError(t, outputs.Stage(pub, toEchoOut, &testMessage{Key: "a", Value: "1"}))
+	require.NoError(t, outputs.Stage(pub, toEchoOut, &testMessage{Key: "b", Value: "2"}))
+	require.NoError(t, outputs.FinalizeTxn(shard))
+
+	var intents, err = pub.BuildAckIntents()
+	require.NoError(t, err)
+	require.Len(t, intents, 1)
+	require.Equal(t, echoOut.Name, intents[0].Journal)
+
+	// Case: an empty transaction trivially finalizes.
+	require.NoError(t, outputs.FinalizeTxn(shard))
+
+	// Case: the output journal is unavailable. The transaction is aborted.
+	require.NoError(t, outputs.Stage(pub, func(message.Mappable) (pb.Journal, string, error) {
+		return "does/not/exist", labels.ContentType_JSONLines, nil
+	}, &testMessage{Key: "c", Value: "3"}))
+
+	err = outputs.FinalizeTxn(shard)
+	require.True(t, errors.Is(err, ErrSideOutputUnavailable))
+	require.EqualError(t, err,
+		"side output journal is unavailable: journal does/not/exist wasn't written within 100ms")
+}
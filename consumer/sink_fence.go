@@ -0,0 +1,150 @@
+package consumer
+
+import (
+	"encoding/json"
+	"sort"
+	"time"
+
+	pb "go.gazette.dev/core/broker/protocol"
+	"go.gazette.dev/core/message"
+)
+
+// SinkFence provides ordered, at-least-once writes of consumed messages to an
+// external sink, such as a database or API which cannot participate in the
+// consumer transaction, and fences writes so that re-delivered messages may be
+// applied idempotently.
+//
+// Each message written to the sink has a Fence, which is its source journal
+// and offset, as well as its producer and Clock. A SinkFence tracks the Fence
+// of the last message of each source journal and producer which was written,
+// and is persisted by the application within its Store, where it commits
+// together with the transaction's Checkpoint. Write skips messages which are
+// already fenced, and the application calls Write from ConsumeMessage:
+//
+//	func (app *MyApp) ConsumeMessage(shard Shard, store Store, env message.Envelope, pub *message.Publisher) error {
+//	    var state = store.(*JSONFileStore).State.(*myState)
+//	    return state.Fence.Write(env, func(fence Fence) error {
+//	        return app.sink.Upsert(fence, env.Message)
+//	    })
+//	}
+//
+// Fences are tracked by producer Clock rather than by journal offset because,
+// where messages are read-committed, the offsets of delivered messages are not
+// monotonic: a message is delivered only once it's acknowledged, and messages
+// of concurrent producers are acknowledged in an order independent of their
+// offsets. Each producer's Clocks are strictly increasing, however.
+//
+// Writes of a transaction which fails after they're applied to the sink, but
+// before its Checkpoint commits (eg, because the process crashes), are
+// re-delivered when the transaction is retried by the shard's recovering
+// process: the SinkFence recovered from its Store doesn't reflect them. This
+// re-delivery window is bounded by the messages consumed since the last
+// committed transaction, and the sink must apply writes idempotently to
+// tolerate it. Typically the sink records the Fence of each journal and
+// producer alongside its written data, and conditions writes on the recorded
+// Clock being less than that of the write's Fence. Messages which are
+// re-delivered outside of this window, such as by a source journal being
+// re-read from an earlier offset, are skipped by Write itself.
+//
+// Messages lacking a producer Clock, such as those of non-transactional
+// framings which don't carry UUIDs, cannot be fenced and are always written.
+type SinkFence struct {
+	fences map[message.JournalProducer]Fence
+}
+
+// Fence identifies a message written to an external sink.
+type Fence struct {
+	// Source journal and [Begin, End) offsets of the message.
+	Journal pb.Journal `json:"journal"`
+	Begin   pb.Offset  `json:"begin"`
+	End     pb.Offset  `json:"end"`
+	// Producer and Clock of the message.
+	Producer message.ProducerID `json:"producer"`
+	Clock    message.Clock      `json:"clock"`
+}
+
+// Write the message of the Envelope by invoking |fn| with its Fence, unless
+// the SinkFence has already recorded the write of the message (or a later
+// message of its producer), in which case Write returns nil without calling
+// |fn|. If |fn| succeeds, the message's Fence is recorded. If |fn| fails, its
+// error is returned and the consumer transaction should fail.
+func (f *SinkFence) Write(env message.Envelope, fn func(Fence) error) error {
+	var uuid = env.Message.GetUUID()
+	var fence = Fence{
+		Journal:  env.Journal.Name,
+		Begin:    env.Begin,
+		End:      env.End,
+		Producer: message.GetProducerID(uuid),
+		Clock:    message.GetClock(uuid),
+	}
+	var key = message.JournalProducer{Journal: fence.Journal, Producer: fence.Producer}
+
+	if fence.Clock == 0 {
+		return fn(fence) // Cannot be fenced.
+	} else if last, ok := f.fences[key]; ok && fence.Clock <= last.Clock {
+		sinkFencedWritesTotal.Inc()
+		return nil
+	} else if err := fn(fence); err != nil {
+		return err
+	}
+
+	if f.fences == nil {
+		f.fences = make(map[message.JournalProducer]Fence)
+	}
+	f.fences[key] = fence
+	return nil
+}
+
+// Last returns the Fence of the last written message of the journal and
+// producer, and whether there is one.
+func (f *SinkFence) Last(journal pb.Journal, producer message.ProducerID) (Fence, bool) {
+	var fence, ok = f.fences[message.JournalProducer{Journal: journal, Producer: producer}]
+	return fence, ok
+}
+
+// Prune Fences of producers which haven't written a message since |before|.
+// Producers are identified with a process instance, and as instances come and
+// go their Fences accumulate. Pruning bounds the size of the SinkFence, at the
+// cost that a re-delivery of a pruned producer's messages is written to the
+// sink (which must then ignore it). |before| should be well outside of any
+// expected re-delivery window. Prune returns the number of pruned Fences.
+func (f *SinkFence) Prune(before time.Time) int {
+	var pruned int
+	for key, fence := range f.fences {
+		if fence.Clock.AsTime().Before(before) {
+			delete(f.fences, key)
+			pruned++
+		}
+	}
+	return pruned
+}
+
+// MarshalJSON encodes the SinkFence as an array of Fences, ordered on
+// journal and producer.
+func (f SinkFence) MarshalJSON() ([]byte, error) {
+	var fences = make([]Fence, 0, len(f.fences))
+	for _, fence := range f.fences {
+		fences = append(fences, fence)
+	}
+	sort.Slice(fences, func(i, j int) bool {
+		if fences[i].Journal != fences[j].Journal {
+			return fences[i].Journal < fences[j].Journal
+		}
+		return string(fences[i].Producer[:]) < string(fences[j].Producer[:])
+	})
+	return json.Marshal(fences)
+}
+
+// UnmarshalJSON decodes a SinkFence encoded by MarshalJSON.
+func (f *SinkFence) UnmarshalJSON(b []byte) error {
+	var fences []Fence
+	if err := json.Unmarshal(b, &fences); err != nil {
+		return err
+	}
+	f.fences = make(map[message.JournalProducer]Fence, len(fences))
+
+	for _, fence := range fences {
+		f.fences[message.JournalProducer{Journal: fence.Journal, Producer: fence.Producer}] = fence
+	}
+	return nil
+}
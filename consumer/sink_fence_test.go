@@ -0,0 +1,119 @@
+package consumer
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	pb "go.gazette.dev/core/broker/protocol"
+	"go.gazette.dev/core/message"
+)
+
+func TestSinkFenceAcrossCrashes(t *testing.T) {
+	var spec = &pb.JournalSpec{Name: "source/A"}
+	var p1, p2 = message.ProducerID{1}, message.ProducerID{2}
+	var now = message.NewClock(time.Now())
+
+	var env = func(producer message.ProducerID, clock message.Clock, begin pb.Offset, value string) message.Envelope {
+		return message.Envelope{
+			Journal: spec,
+			Begin:   begin,
+			End:     begin + 10,
+			Message: &testMessage{
+				UUID:  message.BuildUUID(producer, now+clock, message.Flag_OUTSIDE_TXN),
+				Value: value,
+			},
+		}
+	}
+	// Sink fixture which applies writes idempotently, by conditioning each
+	// on the Clock of its Fence.
+	var sinkFences = make(map[message.JournalProducer]message.Clock)
+	var sinkValues []string
+	var calls int
+
+	var upsert = func(env message.Envelope) func(Fence) error {
+		return func(fence Fence) error {
+			calls++
+			var key = message.JournalProducer{Journal: fence.Journal, Producer: fence.Producer}
+			if fence.Clock == 0 || fence.Clock > sinkFences[key] {
+				sinkFences[key] = fence.Clock
+				sinkValues = append(sinkValues, env.Message.(*testMessage).Value)
+			}
+			return nil
+		}
+	}
+	// Models a Store which persists the SinkFence with each commit.
+	var committed []byte
+	var commit = func(f *SinkFence) {
+		var err error
+		committed, err = json.Marshal(f)
+		require.NoError(t, err)
+	}
+	var restore = func() *SinkFence {
+		var f = new(SinkFence)
+		require.NoError(t, json.Unmarshal(committed, f))
+		return f
+	}
+
+	var fence = new(SinkFence)
+	commit(fence)
+
+	// Transaction 1 writes messages and commits. Delivered offsets needn't be
+	// monotonic: |p2|'s message is delivered after |p1|'s, but precedes it.
+	var txn1 = []message.Envelope{env(p1, 1, 100, "one"), env(p2, 1, 50, "two")}
+	for _, e := range txn1 {
+		require.NoError(t, fence.Write(e, upsert(e)))
+	}
+	commit(fence)
+
+	// Transaction 2 writes messages, but crashes before committing.
+	var txn2 = []message.Envelope{env(p1, 2, 200, "three"), env(p2, 2, 210, "four")}
+	for _, e := range txn2 {
+		require.NoError(t, fence.Write(e, upsert(e)))
+	}
+	require.Equal(t, 4, calls)
+
+	// Recovery restores the committed SinkFence. Transaction 2 is retried,
+	// and its messages are re-delivered to the sink (which ignores them).
+	fence = restore()
+
+	for _, e := range txn2 {
+		require.NoError(t, fence.Write(e, upsert(e)))
+	}
+	require.Equal(t, 6, calls)
+
+	// Messages fenced by the committed SinkFence aren't re-delivered.
+	for _, e := range txn1 {
+		require.NoError(t, fence.Write(e, upsert(e)))
+	}
+	require.Equal(t, 6, calls)
+	commit(fence)
+
+	var last, ok = fence.Last("source/A", p2)
+	require.True(t, ok)
+	require.Equal(t, Fence{Journal: "source/A", Begin: 210, End: 220, Producer: p2, Clock: now + 2}, last)
+
+	// Case: a failed write isn't fenced, and fails the transaction.
+	var e = env(p1, 3, 300, "five")
+	require.EqualError(t, fence.Write(e, func(Fence) error { return errors.New("whoops") }), "whoops")
+	require.NoError(t, fence.Write(e, upsert(e)))
+
+	// Case: messages without a producer Clock can't be fenced, and are
+	// always written.
+	e = message.Envelope{Journal: spec, Begin: 400, End: 410, Message: &testMessage{Value: "six"}}
+	require.NoError(t, fence.Write(e, upsert(e)))
+	require.NoError(t, fence.Write(e, upsert(e)))
+
+	require.Equal(t, []string{"one", "two", "three", "four", "five", "six", "six"}, sinkValues)
+
+	// Prune Fences of producers which haven't written recently.
+	require.NoError(t, fence.Write(env(p2, 1<<30, 500, "seven"), upsert(env(p2, 1<<30, 500, "seven"))))
+	require.Equal(t, 1, fence.Prune((now + 1<<29).AsTime()))
+
+	_, ok = fence.Last("source/A", p1)
+	require.False(t, ok)
+	_, ok = fence.Last("source/A", p2)
+	require.True(t, ok)
+}
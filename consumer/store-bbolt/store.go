@@ -0,0 +1,271 @@
+// Package store_bbolt implements the consumer.Store interface via an embedded
+// bbolt (BoltDB) database. It's a pure-Go Store suited to shards having small
+// state -- up to tens of megabytes -- which would like ordered, bucketed
+// key/value storage without the CGO dependencies of store-rocksdb or
+// store-sqlite.
+//
+// Database Representation
+//
+// bbolt keeps a single database file which it reads through a memory map and
+// updates randomly, in place. Such a file is poorly suited to being recorded
+// as a recoverylog.FNode: its recovery horizon would be unbounded, and its
+// writes aren't observable as they're made through the map. Store instead
+// keeps two files within the recorder directory:
+//
+//  - "live.db" is the working database, which is opened by bbolt, and is not
+//    recorded.
+//  - "state.db" is a recorded snapshot of "live.db", which is re-written with
+//    each committed consumer transaction.
+//
+// Open copies a recovered "state.db" to "live.db" before opening it. StartCommit
+// commits the bbolt transaction of the consumer transaction, together with its
+// Checkpoint, and then writes a consistent snapshot of the database to a
+// recorded "next.db", which is atomically renamed to "state.db". As with
+// JSONFileStore, a recovered "next.db" is the remnant of a failed transaction,
+// and is over-written. The recovery horizon is thus bounded to the most recent
+// snapshot, but each commit writes the full database to the recovery log, and
+// Store is a poor choice for large databases or high transaction rates.
+//
+// Fencing
+//
+// A shard's database is never shared across processes: each process opens its
+// own "live.db", copied from the "state.db" it recovered from the shard's
+// recovery log. A recovered "live.db" is never trusted. It's exclusively locked
+// (flock) by its bbolt instance, and Open fails rather than blocking should
+// another instance of the process already hold it. A process which has lost
+// its shard assignment and continues to commit transactions is fenced by the
+// Recorder, whose appends to the recovery log fail once a new primary has
+// injected its hand-off, and its snapshots are never recovered.
+package store_bbolt
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/afero"
+	"go.etcd.io/bbolt"
+	"go.gazette.dev/core/broker/client"
+	"go.gazette.dev/core/consumer"
+	pc "go.gazette.dev/core/consumer/protocol"
+	"go.gazette.dev/core/consumer/recoverylog"
+)
+
+// Store implements the consumer.Store interface.
+type Store struct {
+	DB *bbolt.DB
+	// Options of the bbolt database, which may be tweaked prior to Open.
+	Options *bbolt.Options
+
+	// Cache is a convenient mechanism for consumers to associate shard-specific,
+	// in-memory state with a Store, typically for performance reasons.
+	// Examples might include:
+	//
+	// - Records we expect to reduce / aggregate over multiple times in a consumer
+	//   transaction, and want to write to the DB only once per transaction (ie,
+	//   as part of a consumer Flush).
+	// - An LRU of "hot" records we expect to reference again soon.
+	//
+	// The representation of Cache is up to the consumer; it is not directly used
+	// by Store.
+	Cache interface{}
+
+	fs       afero.Fs
+	recorder *recoverylog.Recorder
+	txn      *bbolt.Tx
+}
+
+var _ consumer.RollbackStore = &Store{} // Store is-a consumer.RollbackStore.
+
+// NewStore builds a Store which is prepared to open its database, but has not
+// yet done so. The caller may wish to further tweak Options, and should then
+// call Open to open the database.
+func NewStore(recorder *recoverylog.Recorder) *Store {
+	return &Store{
+		Options: &bbolt.Options{
+			// Fail, rather than block indefinitely, if the database is locked.
+			Timeout: time.Second,
+			// Durability is provided by the recovery log, not the local file.
+			NoSync:         true,
+			NoFreelistSync: true,
+			FreelistType:   bbolt.FreelistMapType,
+		},
+		fs:       recoverylog.RecordedAferoFS{Recorder: recorder, Fs: afero.NewOsFs()},
+		recorder: recorder,
+	}
+}
+
+// Open the database, initializing it from the recovered snapshot of the
+// recorder directory (if there is one). After Open, further updates to
+// Options are ignored.
+func (s *Store) Open() (err error) {
+	// Re-build the working database from the recovered snapshot. A working
+	// database left behind in the directory is discarded.
+	if err = os.Remove(s.livePath()); err != nil && !os.IsNotExist(err) {
+		return errors.WithMessage(err, "removing stale database")
+	} else if err = copyFile(s.currentPath(), s.livePath()); err != nil && !os.IsNotExist(err) {
+		return errors.WithMessage(err, "restoring database snapshot")
+	}
+
+	if s.DB, err = bbolt.Open(s.livePath(), 0600, s.Options); err != nil {
+		return errors.WithMessage(err, "opening database")
+	}
+	return nil
+}
+
+// Transaction returns the read-write *bbolt.Tx of the current consumer
+// transaction, beginning one if required. The transaction is committed by
+// StartCommit, and consumer applications must not Commit or Rollback it.
+// As bbolt permits only one read-write transaction at a time, applications
+// must not use DB.Update while a consumer transaction is underway.
+func (s *Store) Transaction() (*bbolt.Tx, error) {
+	if s.txn != nil {
+		return s.txn, nil
+	}
+	var txn, err = s.DB.Begin(true)
+	if err != nil {
+		return nil, errors.WithMessage(err, "beginning transaction")
+	}
+	s.txn = txn
+	return s.txn, nil
+}
+
+// RollbackTxn implements consumer.RollbackStore. A current, uncommitted
+// Transaction is rolled back.
+func (s *Store) RollbackTxn() error {
+	if s.txn == nil {
+		return nil
+	}
+	var err = s.txn.Rollback()
+	s.txn = nil
+
+	if err == bbolt.ErrTxClosed {
+		err = nil // Already committed or rolled back.
+	}
+	return err
+}
+
+// RestoreCheckpoint implements consumer.Store.
+func (s *Store) RestoreCheckpoint(_ consumer.Shard) (cp pc.Checkpoint, err error) {
+	err = s.DB.View(func(txn *bbolt.Tx) error {
+		var bucket = txn.Bucket(checkpointBucket)
+		if bucket == nil {
+			return nil
+		} else if b := bucket.Get(checkpointKey); b == nil {
+			return nil
+		} else if err := cp.Unmarshal(b); err != nil {
+			return errors.WithMessage(err, "unmarshal checkpoint")
+		}
+		return nil
+	})
+	return
+}
+
+// StartCommit implements consumer.Store.
+func (s *Store) StartCommit(_ consumer.Shard, cp pc.Checkpoint, waitFor client.OpFutures) client.OpFuture {
+	_ = s.recorder.Barrier(waitFor)
+
+	var txn, err = s.Transaction()
+	if err != nil {
+		return client.FinishedOperation(err)
+	}
+	s.txn = nil
+
+	// Marshal checkpoint alongside other transaction content.
+	if err = putCheckpoint(txn, cp); err != nil {
+		_ = txn.Rollback()
+		return client.FinishedOperation(err)
+	} else if err = txn.Commit(); err != nil {
+		return client.FinishedOperation(errors.WithMessage(err, "committing transaction"))
+	}
+
+	// Write a consistent snapshot of the database to a temporary file, and
+	// then atomically move it to a well-known location. As with JSONFileStore,
+	// we use O_TRUNC and not O_EXCL as we may have recovered a "next.db" of a
+	// failed transaction, which is over-written.
+	f, err := s.fs.OpenFile(s.nextPath(), os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return client.FinishedOperation(errors.WithMessage(err, "creating snapshot file"))
+	}
+
+	if err = s.DB.View(func(txn *bbolt.Tx) error {
+		var _, err = txn.WriteTo(f)
+		return err
+	}); err != nil {
+		err = errors.WithMessage(err, "writing snapshot")
+	} else if err = f.Close(); err != nil {
+		err = errors.WithMessage(err, "closing snapshot file")
+	} else if err = s.fs.Rename(s.nextPath(), s.currentPath()); err != nil {
+		err = errors.WithMessage(err, "renaming next => current")
+	}
+
+	if err != nil {
+		return client.FinishedOperation(err)
+	}
+	return s.recorder.Barrier(nil)
+}
+
+// Destroy implements consumer.Store.
+func (s *Store) Destroy() {
+	if s.txn != nil {
+		_ = s.txn.Rollback()
+		s.txn = nil
+	}
+	if s.DB != nil {
+		if err := s.DB.Close(); err != nil {
+			log.WithField("err", err).Error("failed to close bbolt database")
+		}
+		s.DB = nil
+	}
+
+	if err := os.RemoveAll(s.recorder.Dir()); err != nil {
+		log.WithFields(log.Fields{
+			"dir": s.recorder.Dir(),
+			"err": err,
+		}).Error("failed to remove bbolt directory")
+	}
+}
+
+func (s *Store) currentPath() string { return filepath.Join(s.recorder.Dir(), "state.db") }
+func (s *Store) nextPath() string    { return filepath.Join(s.recorder.Dir(), "next.db") }
+func (s *Store) livePath() string    { return filepath.Join(s.recorder.Dir(), "live.db") }
+
+func putCheckpoint(txn *bbolt.Tx, cp pc.Checkpoint) error {
+	var b, err = cp.Marshal()
+	if err != nil {
+		return errors.WithMessage(err, "marshal checkpoint")
+	}
+	bucket, err := txn.CreateBucketIfNotExists(checkpointBucket)
+	if err != nil {
+		return errors.WithMessage(err, "creating checkpoint bucket")
+	}
+	return bucket.Put(checkpointKey, b)
+}
+
+// copyFile copies |src| to a new file |dst|, without recording it.
+func copyFile(src, dst string) error {
+	var in, err = os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err = io.Copy(out, in); err != nil {
+		_ = out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+var (
+	// checkpointBucket is reserved by Store for its consumer Checkpoint.
+	checkpointBucket = []byte("__gazette")
+	checkpointKey    = []byte("checkpoint")
+)
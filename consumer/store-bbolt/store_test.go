@@ -0,0 +1,151 @@
+package store_bbolt
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.etcd.io/bbolt"
+	"go.gazette.dev/core/broker/client"
+	pb "go.gazette.dev/core/broker/protocol"
+	"go.gazette.dev/core/brokertest"
+	pc "go.gazette.dev/core/consumer/protocol"
+	"go.gazette.dev/core/consumer/recoverylog"
+	"go.gazette.dev/core/etcdtest"
+)
+
+func TestStoreWriteRecoverAndFence(t *testing.T) {
+	var rjc, cleanup = newBrokerAndLog(t)
+	defer cleanup()
+
+	// Primaries A and B use distinct AppendServices, as would separate processes.
+	var ctx, cancel = context.WithCancel(context.Background())
+	defer cancel()
+	var ajcA, ajcB = client.NewAppendService(ctx, rjc), client.NewAppendService(ctx, rjc)
+
+	var fsm, err = recoverylog.NewFSM(recoverylog.FSMHints{Log: aRecoveryLog})
+	require.NoError(t, err)
+
+	var dirA = tempDir(t)
+	var recA = recoverylog.NewRecorder(aRecoveryLog, fsm, recoverylog.NewRandomAuthor(), dirA, ajcA)
+	var storeA = NewStore(recA)
+	require.NoError(t, storeA.Open())
+
+	// Case: a new database has an empty Checkpoint.
+	cp, err := storeA.RestoreCheckpoint(nil)
+	require.NoError(t, err)
+	require.Equal(t, pc.Checkpoint{}, cp)
+
+	var put = func(s *Store, key, value string) {
+		var txn, err = s.Transaction()
+		require.NoError(t, err)
+		bucket, err := txn.CreateBucketIfNotExists([]byte("data"))
+		require.NoError(t, err)
+		require.NoError(t, bucket.Put([]byte(key), []byte(value)))
+	}
+	var expect = func(s *Store, expect map[string]string) {
+		var actual = make(map[string]string)
+		require.NoError(t, s.DB.View(func(txn *bbolt.Tx) error {
+			return txn.Bucket([]byte("data")).ForEach(func(k, v []byte) error {
+				actual[string(k)] = string(v)
+				return nil
+			})
+		}))
+		require.Equal(t, expect, actual)
+	}
+	var checkpoint = func(journal pb.Journal, offset pb.Offset) pc.Checkpoint {
+		return pc.Checkpoint{Sources: map[pb.Journal]pc.Checkpoint_Source{
+			journal: {ReadThrough: offset},
+		}}
+	}
+
+	// Write and commit two transactions. The Transaction is stable until commit.
+	put(storeA, "foo", "bar")
+	put(storeA, "baz", "bing")
+	require.NoError(t, storeA.StartCommit(nil, checkpoint("journal/A", 1234), nil).Err())
+
+	put(storeA, "foo", "fib")
+	require.NoError(t, storeA.StartCommit(nil, checkpoint("journal/B", 5678), nil).Err())
+	expect(storeA, map[string]string{"foo": "fib", "baz": "bing"})
+
+	// A third transaction is begun, but never commits.
+	put(storeA, "baz", "uncommitted")
+
+	// Recover the database into a new directory, and hand off the log.
+	hints, err := recA.BuildHints()
+	require.NoError(t, err)
+
+	var dirB = tempDir(t)
+	var authorB = recoverylog.NewRandomAuthor()
+	var player = recoverylog.NewPlayer()
+
+	go func() { require.NoError(t, player.Play(context.Background(), hints, dirB, ajcB)) }()
+	player.InjectHandoff(authorB)
+	<-player.Done()
+	require.NotNil(t, player.Resolved.FSM)
+
+	var recB = recoverylog.NewRecorder(aRecoveryLog, player.Resolved.FSM, authorB, dirB, ajcB)
+	var storeB = NewStore(recB)
+	require.NoError(t, storeB.Open())
+
+	// Expect the recovered database and Checkpoint reflect committed transactions.
+	cp, err = storeB.RestoreCheckpoint(nil)
+	require.NoError(t, err)
+	require.Equal(t, checkpoint("journal/B", 5678), cp)
+	expect(storeB, map[string]string{"foo": "fib", "baz": "bing"})
+
+	// Case: the prior primary is fenced. Its commit never completes, as its
+	// appends to the recovery log fail their register checks.
+	var zombie = storeA.StartCommit(nil, checkpoint("journal/C", 9999), nil)
+	select {
+	case <-zombie.Done():
+		t.Fatal("expected zombie commit to not complete")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	// While the new primary can.
+	put(storeB, "baz", "bang")
+	require.NoError(t, storeB.StartCommit(nil, checkpoint("journal/C", 9999), nil).Err())
+	expect(storeB, map[string]string{"foo": "fib", "baz": "bang"})
+
+	// Case: the database is locked by its open Store.
+	_, err = bbolt.Open(storeB.livePath(), 0600, &bbolt.Options{Timeout: 10 * time.Millisecond})
+	require.Equal(t, bbolt.ErrTimeout, err)
+
+	for _, s := range []*Store{storeA, storeB} {
+		s.Destroy()
+
+		// Assert the store directory was removed.
+		_, err = os.Stat(s.recorder.Dir())
+		require.True(t, os.IsNotExist(err))
+	}
+}
+
+func tempDir(t *testing.T) string {
+	var dir, err = ioutil.TempDir("", "store-bbolt-test")
+	require.NoError(t, err)
+	return dir
+}
+
+func newBrokerAndLog(t require.TestingT) (pb.RoutedJournalClient, func()) {
+	var etcd = etcdtest.TestClient()
+	var broker = brokertest.NewBroker(t, etcd, "local", "broker")
+
+	brokertest.CreateJournals(t, broker,
+		brokertest.Journal(pb.JournalSpec{Name: aRecoveryLog}))
+
+	var rjc = pb.NewRoutedJournalClient(broker.Client(), pb.NoopDispatchRouter{})
+
+	return rjc, func() {
+		broker.Tasks.Cancel()
+		require.NoError(t, broker.Tasks.Wait())
+		etcdtest.Cleanup()
+	}
+}
+
+const aRecoveryLog pb.Journal = "test/store-bbolt/recovery-log"
+
+func TestMain(m *testing.M) { etcdtest.TestMainWithEtcd(m) }
@@ -0,0 +1,156 @@
+package consumer
+
+import (
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.gazette.dev/core/broker/client"
+	pc "go.gazette.dev/core/consumer/protocol"
+)
+
+// BatchSink is an external sink, such as a data warehouse or bulk-load API,
+// to which a BatchingStore writes batches of records. Each batch is written
+// together with the Checkpoint through which it was accumulated, and the
+// sink must write the two atomically: either both are durable, or neither is.
+type BatchSink interface {
+	// RestoreCheckpoint returns the Checkpoint of the last batch written by
+	// WriteBatch for the Shard, or a zero-valued Checkpoint if none has been.
+	// It also fences the sink such that WriteBatch calls of any prior
+	// BatchSink instance of the Shard (such as one of a previous process which
+	// is believed to have failed) will fail.
+	RestoreCheckpoint(Shard) (pc.Checkpoint, error)
+	// WriteBatch atomically writes |records| and the Checkpoint of the Shard.
+	// It must fail if the sink has been fenced by a later RestoreCheckpoint.
+	WriteBatch(shard Shard, records []interface{}, cp pc.Checkpoint) error
+}
+
+// BatchingStore is a Store which accumulates records across consumer
+// transactions, and writes them to a BatchSink in larger batches. It's
+// appropriate for sinks having a high overhead per write, for which writing
+// the output of each transaction is too fine-grained.
+//
+// Applications Add records from their ConsumeMessage or FinalizeTxn
+// implementations. As each transaction commits, the BatchingStore flushes
+// its accumulated records to the BatchSink if they total at least MaxSize,
+// if MaxDelay has elapsed since its last flush, or if the transaction
+// published messages (see below). A flush writes the records
+// with the transaction's Checkpoint, which is the only Checkpoint persisted
+// by the BatchingStore: transactions which commit without a flush advance
+// the Shard's consumed offsets in memory only.
+//
+// Exactly-once semantics follow. If the process crashes after records are
+// accumulated but before they're flushed, the recovered Shard restores the
+// Checkpoint of the last flush and re-reads its source journals from its
+// offsets, re-accumulating the lost records. If a prior process is still
+// running, its flushes fail once the recovering process restores its
+// Checkpoint from the fenced BatchSink.
+//
+// Acknowledgements of messages published by a transaction are written once
+// its commit resolves, and must not be written unless its Checkpoint (which
+// records those acknowledgements) is durable. A transaction which published
+// messages therefore always flushes, regardless of MaxSize and MaxDelay, and
+// applications which publish with every transaction gain no batching.
+// MaxDelay is evaluated only as transactions commit, and a Shard having no
+// further messages to consume will not flush until it consumes another.
+type BatchingStore struct {
+	// Sink to which batches are written.
+	Sink BatchSink
+	// MaxSize of accumulated records, as sized by Add, which triggers a flush.
+	// If zero, records are flushed with every transaction.
+	MaxSize int64
+	// MaxDelay since the last flush which triggers a flush. If zero, flushes
+	// are triggered only by MaxSize.
+	MaxDelay time.Duration
+
+	mu          sync.Mutex
+	pending     []interface{}
+	pendingSize int64
+	lastFlush   time.Time
+	now         func() time.Time
+}
+
+// NewBatchingStore returns a BatchingStore of the BatchSink.
+// MaxSize and MaxDelay should be set by the caller.
+func NewBatchingStore(sink BatchSink) *BatchingStore {
+	return &BatchingStore{
+		Sink: sink,
+		now:  time.Now,
+	}
+}
+
+// Add a record of the given |size| to the current batch. Records are flushed
+// to the BatchSink in the order they're added. Add must be called only from
+// within a consumer transaction, before FinalizeTxn returns.
+func (s *BatchingStore) Add(record interface{}, size int) {
+	s.mu.Lock()
+	s.pending = append(s.pending, record)
+	s.pendingSize += int64(size)
+	s.mu.Unlock()
+}
+
+// Pending returns the number and total size of accumulated records which
+// are yet to be flushed.
+func (s *BatchingStore) Pending() (count int, size int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.pending), s.pendingSize
+}
+
+// RestoreCheckpoint discards any accumulated records, and restores the
+// Checkpoint of the last flush from the BatchSink.
+func (s *BatchingStore) RestoreCheckpoint(shard Shard) (pc.Checkpoint, error) {
+	s.mu.Lock()
+	s.pending, s.pendingSize = nil, 0
+	s.lastFlush = s.now()
+	s.mu.Unlock()
+
+	var cp, err = s.Sink.RestoreCheckpoint(shard)
+	if err != nil {
+		return pc.Checkpoint{}, errors.WithMessage(err, "restoring checkpoint from BatchSink")
+	}
+	return cp, nil
+}
+
+// StartCommit flushes accumulated records with the Checkpoint if a flush is
+// triggered, once all |waitFor| operations have completed. Otherwise, the
+// returned OpFuture resolves once |waitFor| operations complete, without
+// persisting the Checkpoint. A Checkpoint having AckIntents always triggers
+// a flush.
+func (s *BatchingStore) StartCommit(shard Shard, cp pc.Checkpoint, waitFor OpFutures) OpFuture {
+	s.mu.Lock()
+	var now = s.now()
+	var flush = s.pendingSize >= s.MaxSize ||
+		(s.MaxDelay != 0 && now.Sub(s.lastFlush) >= s.MaxDelay) ||
+		len(cp.AckIntents) != 0
+
+	var records []interface{}
+	if flush {
+		records, s.pending, s.pendingSize = s.pending, nil, 0
+		s.lastFlush = now
+	}
+	s.mu.Unlock()
+
+	var result = client.NewAsyncOperation()
+
+	go func() {
+		for op := range waitFor {
+			if op.Err() != nil {
+				result.Resolve(errors.WithMessage(op.Err(), "dependency failed"))
+				return
+			}
+		}
+		if !flush {
+			result.Resolve(nil)
+		} else if err := s.Sink.WriteBatch(shard, records, cp); err != nil {
+			result.Resolve(errors.WithMessage(err, "writing batch"))
+		} else {
+			result.Resolve(nil)
+		}
+	}()
+	return result
+}
+
+// Destroy is a no-op. Accumulated records which weren't flushed are discarded,
+// and are re-accumulated by the Shard's next assigned process.
+func (s *BatchingStore) Destroy() {}
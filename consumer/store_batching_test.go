@@ -0,0 +1,170 @@
+package consumer
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.gazette.dev/core/broker/client"
+	pb "go.gazette.dev/core/broker/protocol"
+	pc "go.gazette.dev/core/consumer/protocol"
+)
+
+func TestBatchingStoreFlushTriggers(t *testing.T) {
+	var _, shard, cleanup = newTestFixtureWithIdleShard(t)
+	defer cleanup()
+
+	var sink = new(fencedBatchSink)
+	var store, clock = newTestBatchingStore(sink)
+	store.MaxSize, store.MaxDelay = 10, time.Minute
+
+	var cp, err = store.RestoreCheckpoint(shard)
+	require.NoError(t, err)
+	require.Equal(t, pc.Checkpoint{}, cp)
+
+	// Below MaxSize and MaxDelay: the commit doesn't flush.
+	store.Add("one", 4)
+	store.Add("two", 4)
+	require.NoError(t, store.StartCommit(shard, batchingCheckpoint(100), nil).Err())
+	require.Empty(t, sink.batches)
+
+	var count, size = store.Pending()
+	require.Equal(t, 2, count)
+	require.Equal(t, int64(8), size)
+
+	// Reaching MaxSize flushes all records with the Checkpoint.
+	store.Add("three", 4)
+	require.NoError(t, store.StartCommit(shard, batchingCheckpoint(200), nil).Err())
+	require.Equal(t, []batch{{[]interface{}{"one", "two", "three"}, batchingCheckpoint(200)}}, sink.batches)
+
+	count, size = store.Pending()
+	require.Equal(t, 0, count)
+	require.Equal(t, int64(0), size)
+
+	// Elapsed MaxDelay also flushes.
+	store.Add("four", 1)
+	require.NoError(t, store.StartCommit(shard, batchingCheckpoint(300), nil).Err())
+	require.Len(t, sink.batches, 1)
+
+	*clock = clock.Add(time.Minute)
+	require.NoError(t, store.StartCommit(shard, batchingCheckpoint(400), nil).Err())
+	require.Equal(t, batch{[]interface{}{"four"}, batchingCheckpoint(400)}, sink.batches[1])
+
+	// A Checkpoint having AckIntents flushes, as its acknowledgements are
+	// written once the commit resolves.
+	store.Add("ack", 1)
+	var withAcks = batchingCheckpoint(450)
+	withAcks.AckIntents = map[pb.Journal][]byte{"a/journal": []byte("ack")}
+	require.NoError(t, store.StartCommit(shard, withAcks, nil).Err())
+	require.Equal(t, batch{[]interface{}{"ack"}, withAcks}, sink.batches[2])
+
+	// A failed dependency fails the commit, without writing a batch.
+	store.Add("five", 100)
+	var dep = OpFutures{client.FinishedOperation(errors.New("op failed")): {}}
+	require.EqualError(t, store.StartCommit(shard, batchingCheckpoint(500), dep).Err(),
+		"dependency failed: op failed")
+	require.Len(t, sink.batches, 3)
+}
+
+func TestBatchingStoreCrashRecovery(t *testing.T) {
+	var _, shard, cleanup = newTestFixtureWithIdleShard(t)
+	defer cleanup()
+
+	var sink = new(fencedBatchSink)
+	var store, _ = newTestBatchingStore(sink)
+	store.MaxSize = 10
+
+	var _, err = store.RestoreCheckpoint(shard)
+	require.NoError(t, err)
+
+	// Flush a first batch.
+	store.Add("one", 10)
+	require.NoError(t, store.StartCommit(shard, batchingCheckpoint(100), nil).Err())
+
+	// Accumulate a record which is committed, but not flushed, before a "crash".
+	store.Add("two", 5)
+	require.NoError(t, store.StartCommit(shard, batchingCheckpoint(200), nil).Err())
+
+	// A recovering store restores the Checkpoint of the last flush,
+	// from which the shard would re-read and re-accumulate records.
+	var recovered, _ = newTestBatchingStore(sink)
+	recovered.MaxSize = 10
+
+	cp, err := recovered.RestoreCheckpoint(shard)
+	require.NoError(t, err)
+	require.Equal(t, batchingCheckpoint(100), cp)
+
+	// The prior store is fenced, and fails to flush.
+	store.Add("three", 5)
+	require.EqualError(t, store.StartCommit(shard, batchingCheckpoint(300), nil).Err(),
+		"writing batch: sink is fenced")
+
+	// The recovered store re-accumulates and flushes the lost records once.
+	recovered.Add("two", 5)
+	require.NoError(t, recovered.StartCommit(shard, batchingCheckpoint(200), nil).Err())
+	recovered.Add("three", 5)
+	require.NoError(t, recovered.StartCommit(shard, batchingCheckpoint(300), nil).Err())
+
+	require.Equal(t, []batch{
+		{[]interface{}{"one"}, batchingCheckpoint(100)},
+		{[]interface{}{"two", "three"}, batchingCheckpoint(300)},
+	}, sink.batches)
+
+	// A restore discards records which weren't flushed.
+	recovered.Add("four", 1)
+	cp, err = recovered.RestoreCheckpoint(shard)
+	require.NoError(t, err)
+	require.Equal(t, batchingCheckpoint(300), cp)
+
+	var count, _ = recovered.Pending()
+	require.Equal(t, 0, count)
+}
+
+type batch struct {
+	records []interface{}
+	cp      pc.Checkpoint
+}
+
+// fencedBatchSink is a BatchSink which fences writes of prior instances
+// by tracking the number of restores.
+type fencedBatchSink struct {
+	batches []batch
+	fence   int
+}
+
+type fencedBatchSinkHandle struct {
+	*fencedBatchSink
+	fence int
+}
+
+func (s *fencedBatchSinkHandle) RestoreCheckpoint(Shard) (pc.Checkpoint, error) {
+	s.fencedBatchSink.fence++
+	s.fence = s.fencedBatchSink.fence
+
+	if len(s.batches) == 0 {
+		return pc.Checkpoint{}, nil
+	}
+	return s.batches[len(s.batches)-1].cp, nil
+}
+
+func (s *fencedBatchSinkHandle) WriteBatch(_ Shard, records []interface{}, cp pc.Checkpoint) error {
+	if s.fence != s.fencedBatchSink.fence {
+		return errors.New("sink is fenced")
+	}
+	s.batches = append(s.batches, batch{records, cp})
+	return nil
+}
+
+func newTestBatchingStore(sink *fencedBatchSink) (*BatchingStore, *time.Time) {
+	var store = NewBatchingStore(&fencedBatchSinkHandle{fencedBatchSink: sink})
+	var clock = time.Unix(1600000000, 0)
+	store.now = func() time.Time { return clock }
+	return store, &clock
+}
+
+func batchingCheckpoint(offset pb.Offset) pc.Checkpoint {
+	return pc.Checkpoint{Sources: map[pb.Journal]pc.Checkpoint_Source{
+		"a/journal": {ReadThrough: offset},
+	}}
+}
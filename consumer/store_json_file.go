@@ -5,6 +5,7 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"reflect"
 
 	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
@@ -25,9 +26,16 @@ type JSONFileStore struct {
 	checkpoint pc.Checkpoint
 	fs         afero.Fs
 	recorder   *recoverylog.Recorder
+	savepoints []jsonSavepoint // Savepoints of the current transaction.
 }
 
-var _ Store = &JSONFileStore{} // JSONFileStore is-a Store.
+// jsonSavepoint is a savepoint of a JSONFileStore, and its encoded State.
+type jsonSavepoint struct {
+	name  string
+	state []byte
+}
+
+var _ SavepointStore = &JSONFileStore{} // JSONFileStore is-a SavepointStore.
 
 // NewJSONFileStore returns a new JSONFileStore. |state| is the runtime instance
 // of the Store's state, which is decoded into, encoded from, and retained
@@ -72,12 +80,16 @@ func NewJSONFileStore(rec *recoverylog.Recorder, state interface{}) (*JSONFileSt
 }
 
 // RestoreCheckpoint returns the checkpoint encoded in the recovered JSON state file.
-func (s *JSONFileStore) RestoreCheckpoint(Shard) (pc.Checkpoint, error) { return s.checkpoint, nil }
+func (s *JSONFileStore) RestoreCheckpoint(Shard) (pc.Checkpoint, error) {
+	s.savepoints = nil
+	return s.checkpoint, nil
+}
 
 // StartCommit marshals the in-memory state and Checkpoint into a recorded JSON state file.
 func (s *JSONFileStore) StartCommit(_ Shard, cp pc.Checkpoint, waitFor OpFutures) OpFuture {
 	_ = s.recorder.Barrier(waitFor)
 	s.checkpoint = cp
+	s.savepoints = nil
 
 	// Commit by writing the complete state to a temporary file, and then
 	// atomically moving it to a well-known location. This ensures that we'll
@@ -112,6 +124,58 @@ func (s *JSONFileStore) StartCommit(_ Shard, cp pc.Checkpoint, waitFor OpFutures
 	return s.recorder.Barrier(nil)
 }
 
+// Savepoint implements SavepointStore, by retaining an in-memory encoding of
+// the current State. State is written to the recovery log only by StartCommit,
+// so rolled-back updates are never recorded.
+func (s *JSONFileStore) Savepoint(_ Shard, name string) error {
+	var b, err = json.Marshal(s.State)
+	if err != nil {
+		return errors.WithMessage(err, "encode(state)")
+	}
+	s.savepoints = append(s.savepoints, jsonSavepoint{name: name, state: b})
+	return nil
+}
+
+// RollbackToSavepoint implements SavepointStore, by decoding the State of the
+// savepoint into a zero-valued State. State must be a pointer.
+func (s *JSONFileStore) RollbackToSavepoint(_ Shard, name string) error {
+	var ind, err = s.savepointIndex(name)
+	if err != nil {
+		return err
+	}
+	var v = reflect.ValueOf(s.State)
+	if v.Kind() != reflect.Ptr || v.IsNil() {
+		return errors.Errorf("JSONFileStore.State must be a non-nil pointer (not %T)", s.State)
+	}
+	v.Elem().Set(reflect.Zero(v.Elem().Type()))
+
+	if err = json.Unmarshal(s.savepoints[ind].state, s.State); err != nil {
+		return errors.WithMessage(err, "decode(state)")
+	}
+	s.savepoints = s.savepoints[:ind+1]
+	return nil
+}
+
+// ReleaseSavepoint implements SavepointStore.
+func (s *JSONFileStore) ReleaseSavepoint(_ Shard, name string) error {
+	var ind, err = s.savepointIndex(name)
+	if err != nil {
+		return err
+	}
+	s.savepoints = s.savepoints[:ind]
+	return nil
+}
+
+// savepointIndex returns the index of the most recent savepoint |name|.
+func (s *JSONFileStore) savepointIndex(name string) (int, error) {
+	for i := len(s.savepoints) - 1; i >= 0; i-- {
+		if s.savepoints[i].name == name {
+			return i, nil
+		}
+	}
+	return 0, errors.Errorf("savepoint %s not found", name)
+}
+
 // Destroy the JSONFileStore directory and state file.
 func (s *JSONFileStore) Destroy() {
 	if err := os.RemoveAll(s.recorder.Dir()); err != nil {
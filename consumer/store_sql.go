@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"regexp"
 
 	"github.com/pkg/errors"
 	"go.gazette.dev/core/broker/client"
@@ -43,6 +44,9 @@ type SQLStore struct {
 	txn   *sql.Tx // Current consumer transaction.
 }
 
+var _ SavepointStore = &SQLStore{} // SQLStore is-a SavepointStore.
+var _ RollbackStore = &SQLStore{}  // SQLStore is-a RollbackStore.
+
 // NewSQLStore returns a new SQLStore using the *DB.
 func NewSQLStore(db *sql.DB) *SQLStore {
 	return &SQLStore{
@@ -62,6 +66,20 @@ func (s *SQLStore) Transaction(ctx context.Context, txOpts *sql.TxOptions) (_ *s
 	return s.txn, err
 }
 
+// RollbackTxn rolls back a current SQL transaction, if one has begun.
+func (s *SQLStore) RollbackTxn() error {
+	if s.txn == nil {
+		return nil
+	}
+	var err = s.txn.Rollback()
+	s.txn = nil
+
+	if err == sql.ErrTxDone {
+		err = nil // Already committed or rolled back.
+	}
+	return err
+}
+
 // RestoreCheckpoint issues a SQL transaction which SELECTS the most recent
 // Checkpoint of this shard FQN and also increments its "fence" column.
 func (s *SQLStore) RestoreCheckpoint(shard Shard) (cp pc.Checkpoint, _ error) {
@@ -144,3 +162,35 @@ func (s *SQLStore) Destroy() {
 		_ = s.txn.Rollback()
 	}
 }
+
+// Savepoint implements SavepointStore, by issuing a SQL SAVEPOINT within
+// the current transaction. |name| must be a SQL identifier.
+func (s *SQLStore) Savepoint(shard Shard, name string) error {
+	return s.execSavepoint(shard, "SAVEPOINT %s;", name)
+}
+
+// RollbackToSavepoint implements SavepointStore, by issuing a SQL
+// ROLLBACK TO SAVEPOINT within the current transaction.
+func (s *SQLStore) RollbackToSavepoint(shard Shard, name string) error {
+	return s.execSavepoint(shard, "ROLLBACK TO SAVEPOINT %s;", name)
+}
+
+// ReleaseSavepoint implements SavepointStore, by issuing a SQL
+// RELEASE SAVEPOINT within the current transaction.
+func (s *SQLStore) ReleaseSavepoint(shard Shard, name string) error {
+	return s.execSavepoint(shard, "RELEASE SAVEPOINT %s;", name)
+}
+
+func (s *SQLStore) execSavepoint(shard Shard, format, name string) error {
+	if !sqlIdentifierRe.MatchString(name) {
+		return errors.Errorf("invalid savepoint name %q (expected a SQL identifier)", name)
+	}
+	var txn, err = s.Transaction(shard.Context(), nil)
+	if err == nil {
+		_, err = txn.Exec(fmt.Sprintf(format, name))
+	}
+	return err
+}
+
+// sqlIdentifierRe matches an unquoted SQL identifier.
+var sqlIdentifierRe = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
@@ -83,6 +83,7 @@ type testApplication struct {
 	finalizeErr          error         // Error returned by Application.FinalizeTxn().
 	startCommitErr       error         // Error returned by Store.StartCommit().
 	restoreCheckpointErr error         // Error returned by Store.RestoreCheckpoint().
+	healthErr            error         // Error returned by Application.CheckHealth().
 	finishedCh           chan OpFuture // Signaled on FinishedTxn().
 	db                   *sql.DB       // "Remote" sqlite database.
 }
@@ -154,6 +155,8 @@ func (a *testApplication) ConsumeMessage(shard Shard, store Store, env message.E
 	return nil
 }
 
+func (a *testApplication) CheckHealth(Shard) error { return a.healthErr }
+
 func (a *testApplication) FinalizeTxn(Shard, Store, *message.Publisher) error { return a.finalizeErr }
 
 func (a *testApplication) FinishedTxn(_ Shard, _ Store, op OpFuture) {
@@ -43,6 +43,12 @@ func runTransactions(s *shard, cp pc.Checkpoint, readCh <-chan EnvelopeOrError,
 	)
 	<-realTimer.C // Timer starts as idle.
 
+	// Restored messages of a buffer may be released absent further reads.
+	if s.buffer != nil {
+		s.release.arm(s.buffer.nextRelease())
+		defer s.release.stop()
+	}
+
 	// Begin by acknowledging (or re-acknowledging) messages published as part
 	// of the most-recent recovered transaction checkpoint. This is a relaxed
 	// form of txnAcknowledge(), as we allow recovered intents to name journals
@@ -81,6 +87,10 @@ func runTransactions(s *shard, cp pc.Checkpoint, readCh <-chan EnvelopeOrError,
 
 		txnInit(s, &txn, &prev, readCh, txnTimer)
 		if err := txnRun(s, &txn, &prev); err != nil {
+			if mayRetryTxns(s, err) {
+				// The Store must be quiescent before its Checkpoint is restored.
+				awaitTxnCommits(s, &prev, &txn)
+			}
 			return err
 		} else if txn.consumedCount == 0 {
 			return nil // |readCh| has closed and drained.
@@ -97,6 +107,9 @@ type transaction struct {
 	readCh         <-chan EnvelopeOrError // Message source. Nil'd upon reaching |maxDur|.
 	consumedCount  int                    // Number of acknowledged Messages consumed.
 	consumedBytes  int64                  // Number of acknowledged Message bytes consumed.
+	batchCount     int                    // Fixed number of Messages of the transaction, or zero.
+	batchBytes     int64                  // Fixed number of Message bytes of the transaction, or zero.
+	limited        bool                   // Holds a slot of the Service TxnLimiter?
 	checkpoint     pc.Checkpoint          // Checkpoint upon the commit of this transaction.
 	commitBarrier  OpFuture               // Barrier at which this transaction commits.
 	acks           OpFutures              // ACKs of published messages, queued on |commitBarrier|.
@@ -124,9 +137,25 @@ func txnInit(s *shard, txn, prev *transaction, readCh <-chan EnvelopeOrError, ti
 		waitForAck:        !spec.DisableWaitForAck,
 		barrierCh:         prev.commitBarrier.Done(),
 		prevPrepareDoneAt: prev.prepareDoneAt,
+		batchCount:        int(spec.TxnBatchMessages),
+		batchBytes:        spec.TxnBatchBytes,
+	}
+	if txnFixedBatch(txn) {
+		txn.minDur = -1 // Fixed batches have no minimum duration.
 	}
 }
 
+// txnFixedBatch returns true if |txn| processes a fixed batch of messages.
+func txnFixedBatch(txn *transaction) bool {
+	return txn.batchCount != 0 || txn.batchBytes != 0
+}
+
+// txnBatchFull returns true if |txn| has consumed its fixed batch of messages.
+func txnBatchFull(txn *transaction) bool {
+	return (txn.batchCount != 0 && txn.consumedCount >= txn.batchCount) ||
+		(txn.batchBytes != 0 && txn.consumedBytes >= txn.batchBytes)
+}
+
 // txnRun runs a single consumer transaction |txn| until it starts to commit.
 func txnRun(s *shard, txn, prev *transaction) error {
 	trace.Log(s.ctx, "txnRun", s.resolved.fqn)
@@ -135,6 +164,7 @@ func txnRun(s *shard, txn, prev *transaction) error {
 	for !done && err == nil {
 		done, err = txnStep(s, txn, prev)
 	}
+	txnReleaseLimit(s, txn) // Release if we failed while holding a slot.
 
 	if bf, ok := s.svc.App.(BeginFinisher); ok && !txn.beganAt.IsZero() {
 		if err != nil {
@@ -156,6 +186,11 @@ func txnBlocks(s *shard, txn *transaction) bool {
 	if txn.readCh == nil && txn.consumedCount == 0 {
 		return false
 	}
+	// A fixed batch blocks until it's consumed, or its maximum duration
+	// elapses. Either stops the reading of further messages.
+	if txnFixedBatch(txn) {
+		return txn.readCh != nil
+	}
 	// Block if we haven't consumed messages yet.
 	return txn.consumedCount == 0 ||
 		// Or if the minimum batching duration hasn't elapsed.
@@ -185,6 +220,12 @@ func txnStep(s *shard, txn, prev *transaction) (bool, error) {
 		return false, nil // We consumed one message.
 	}
 
+	// Buffered messages may be released only while we're still reading.
+	var releaseCh = s.release.C()
+	if txn.readCh == nil {
+		releaseCh = nil
+	}
+
 	if txnBlocks(s, txn) {
 		select {
 		case env, ok := <-txn.readCh:
@@ -193,6 +234,8 @@ func txnStep(s *shard, txn, prev *transaction) (bool, error) {
 			return false, txnTick(s, txn, tick)
 		case <-txn.barrierCh:
 			return false, txnBarrierResolved(s, txn, prev)
+		case <-releaseCh:
+			return false, txnRelease(s, txn)
 		}
 	} else {
 		select {
@@ -200,6 +243,8 @@ func txnStep(s *shard, txn, prev *transaction) (bool, error) {
 			return false, txnRead(s, txn, prev, env, ok)
 		case tick := <-txn.timer.C:
 			return false, txnTick(s, txn, tick)
+		case <-releaseCh:
+			return false, txnRelease(s, txn)
 		default:
 			// Start to commit.
 		}
@@ -213,6 +258,8 @@ func txnStep(s *shard, txn, prev *transaction) (bool, error) {
 		return false, fmt.Errorf("txnAcknowledge: %w", err)
 	}
 
+	txnReleaseLimit(s, txn) // The transaction is no longer processing.
+
 	// If the timer is still running, stop and drain it.
 	if txn.maxDur != -1 && !txn.timer.Stop() {
 		<-txn.timer.C
@@ -220,6 +267,15 @@ func txnStep(s *shard, txn, prev *transaction) (bool, error) {
 	return true, nil
 }
 
+// txnReleaseLimit releases the slot of the Service TxnLimiter held by
+// |txn|, if any.
+func txnReleaseLimit(s *shard, txn *transaction) {
+	if txn.limited {
+		s.svc.TxnLimiter.release()
+		txn.limited = false
+	}
+}
+
 func txnRead(s *shard, txn, prev *transaction, env EnvelopeOrError, ok bool) error {
 	if !ok {
 		txn.readCh = nil // Channel is closed, don't select it again.
@@ -310,28 +366,63 @@ func txnRead(s *shard, txn, prev *transaction, env EnvelopeOrError, ok bool) err
 	}
 }
 
-func txnConsume(s *shard, txn *transaction) error {
-	// Does this message begin the transaction?
-	if txn.consumedCount == 0 {
-		trace.Log(s.ctx, "BeginTxn", s.resolved.fqn)
+// txnBegin begins the transaction |txn|, which has yet to consume a message.
+func txnBegin(s *shard, txn *transaction) error {
+	trace.Log(s.ctx, "BeginTxn", s.resolved.fqn)
 
-		if ba, ok := s.svc.App.(BeginFinisher); ok {
-			// BeginTxn may block arbitrarily, for example by obtaining a
-			// semaphore to constrain maximum concurrency.
-			if err := ba.BeginTxn(s, s.store); err != nil {
-				return fmt.Errorf("app.BeginTxn: %w", err)
-			}
+	// Wait for a slot of the process-wide TxnLimiter. The slot is held
+	// until the transaction starts to commit.
+	if !txn.limited {
+		if err := s.svc.TxnLimiter.acquire(s.ctx); err != nil {
+			return fmt.Errorf("TxnLimiter: %w", err)
+		}
+		txn.limited = true
+	}
+	if ba, ok := s.svc.App.(BeginFinisher); ok {
+		// BeginTxn may block arbitrarily, for example by obtaining a
+		// semaphore to constrain maximum concurrency.
+		if err := ba.BeginTxn(s, s.store); err != nil {
+			return fmt.Errorf("app.BeginTxn: %w", err)
 		}
-		txn.beganAt = txn.timer.Now()
+	}
+	txn.beganAt = txn.timer.Now()
+
+	if txn.minDur == -1 {
+		txn.timer.Reset(txn.maxDur) // Fixed batch.
+	} else {
 		txn.timer.Reset(txn.minDur)
+	}
+
+	var delta = atomic.LoadInt64((*int64)(&s.svc.PublishClockDelta))
+	s.clock.Update(txn.beganAt.Add(time.Duration(delta)))
+
+	return nil
+}
 
-		var delta = atomic.LoadInt64((*int64)(&s.svc.PublishClockDelta))
-		s.clock.Update(txn.beganAt.Add(time.Duration(delta)))
+func txnConsume(s *shard, txn *transaction) error {
+	// Does this message begin the transaction?
+	if txn.consumedCount == 0 {
+		if err := txnBegin(s, txn); err != nil {
+			return err
+		}
 	}
 
-	var err = s.svc.App.ConsumeMessage(s, s.store, *s.sequencer.Dequeued, s.publisher)
+	var err error
+	if s.buffer != nil {
+		err = s.buffer.consume(*s.sequencer.Dequeued, func(env message.Envelope) error {
+			return s.svc.App.ConsumeMessage(s, s.store, env, s.publisher)
+		})
+		s.release.arm(s.buffer.nextRelease())
+	} else {
+		err = s.svc.App.ConsumeMessage(s, s.store, *s.sequencer.Dequeued, s.publisher)
+	}
 
-	if err == ErrDeferToNextTransaction && txn.consumedCount == 0 {
+	if err == ErrDeferToNextTransaction && s.buffer != nil {
+		// The dequeued message was buffered, but the Application deferred a
+		// released message (which remains buffered). Stop reading further
+		// messages, and resume releasing with the next transaction.
+		txn.readCh = nil
+	} else if err == ErrDeferToNextTransaction && txn.consumedCount == 0 {
 		return fmt.Errorf("consumer transaction is empty, but application deferred the first message")
 	} else if err == ErrDeferToNextTransaction {
 		txn.readCh = nil // Stop reading further messages.
@@ -343,6 +434,10 @@ func txnConsume(s *shard, txn *transaction) error {
 	txn.consumedCount++
 	txn.consumedBytes += (s.sequencer.Dequeued.End - s.sequencer.Dequeued.Begin)
 
+	if txnBatchFull(txn) {
+		txn.readCh = nil // Stop reading further messages.
+	}
+
 	if err := s.sequencer.Step(); err == io.EOF {
 		// sequencer.Dequeued is now nil, and a further call to txnStep
 		// will queue additional ready read-uncommitted messages.
@@ -352,6 +447,44 @@ func txnConsume(s *shard, txn *transaction) error {
 	return nil // sequencer.Dequeued is the next message to consume.
 }
 
+// txnRelease consumes buffered messages which are released absent further
+// reads, beginning the transaction if it hasn't already. Released messages
+// are counted as consumed by the transaction, which must commit the effects
+// of their consumption.
+func txnRelease(s *shard, txn *transaction) error {
+	s.release.stop()
+
+	// Messages read since the timer was armed may have changed when
+	// buffered messages are released.
+	if at := s.buffer.nextRelease(); at.IsZero() || time.Now().Before(at) {
+		s.release.arm(at)
+		return nil
+	}
+	if txn.consumedCount == 0 {
+		if err := txnBegin(s, txn); err != nil {
+			return err
+		}
+	}
+
+	var n, err = s.buffer.release(func(env message.Envelope) error {
+		return s.svc.App.ConsumeMessage(s, s.store, env, s.publisher)
+	})
+	txn.consumedCount += n
+
+	if err == ErrDeferToNextTransaction && txn.consumedCount == 0 {
+		return fmt.Errorf("consumer transaction is empty, but application deferred the first message")
+	} else if err == ErrDeferToNextTransaction {
+		txn.readCh = nil // Stop reading further messages.
+	} else if err != nil {
+		return fmt.Errorf("app.ConsumeMessage: %w", err)
+	} else if txnBatchFull(txn) {
+		txn.readCh = nil // Stop reading further messages.
+	}
+
+	s.release.arm(s.buffer.nextRelease())
+	return nil
+}
+
 func txnTick(s *shard, txn *transaction, tick time.Time) error {
 	if tick.Before(txn.beganAt.Add(txn.minDur)) {
 		panic("unexpected tick")
@@ -392,6 +525,15 @@ func txnBarrierResolved(s *shard, txn, prev *transaction) error {
 			return fmt.Errorf("store.StartCommit: %w", prev.commitBarrier.Err())
 		}
 		prev.committedAt = now
+
+		if s.buffer != nil {
+			if err := s.buffer.committed(prev.checkpoint); err != nil {
+				return fmt.Errorf("buffer.committed: %w", err)
+			}
+		}
+		if s.ckptSink != nil && s.ckptSink.mode == CheckpointSinkEventual {
+			s.ckptSink.enqueue(prev.checkpoint)
+		}
 	}
 
 	// Find the next ACK append that hasn't finished.
@@ -416,6 +558,10 @@ func txnBarrierResolved(s *shard, txn, prev *transaction) error {
 	// All barriers have finished. |prev| transaction is complete.
 	trace.Log(s.ctx, "txnBarrier(done)", s.resolved.fqn)
 	prev.ackedAt = now
+
+	if prev.consumedCount != 0 {
+		s.txnRetry.attempt = 0 // Transactions are no longer failing.
+	}
 	recordMetrics(s, prev)
 
 	// Signal shard progress from results of |prev| transaction.
@@ -428,6 +574,7 @@ func txnBarrierResolved(s *shard, txn, prev *transaction) error {
 				publishAt[ack.Request().Journal] = ack.Response().Commit.End
 			}
 		}
+		s.progress.checkpoint = prev.checkpoint
 	})
 
 	return nil
@@ -451,6 +598,20 @@ func txnStartCommit(s *shard, txn *transaction) (pc.Checkpoint, error) {
 	}
 	txn.checkpoint = pc.BuildCheckpoint(bca)
 
+	// Buffered messages must be persisted before the Checkpoint which
+	// steps past them may commit.
+	if s.buffer != nil {
+		if err = s.buffer.persist(txn.checkpoint); err != nil {
+			return pc.Checkpoint{}, fmt.Errorf("buffer.persist: %w", err)
+		}
+	}
+	// A strong CheckpointSink must write the Checkpoint before it may commit.
+	if s.ckptSink != nil && s.ckptSink.mode == CheckpointSinkStrong {
+		if err = s.ckptSink.sink.WriteCheckpoint(s, txn.checkpoint); err != nil {
+			return pc.Checkpoint{}, fmt.Errorf("CheckpointSink.WriteCheckpoint: %w", err)
+		}
+	}
+
 	// Collect pending journal writes before we start to commit. We'll require
 	// that the Store wait on all |waitFor| operations before it commits, to
 	// ensure that writes driven by messages of the transaction have completed
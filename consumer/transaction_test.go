@@ -2,8 +2,11 @@ package consumer
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"math"
+	"strconv"
 	"testing"
 	"time"
 
@@ -64,6 +67,8 @@ func TestTxnPriorSyncsThenMinDurElapses(t *testing.T) {
 	require.False(t, mustTxnStep(t, shard, &txn, &prior))
 	require.Equal(t, minDur, timer.reset)                                         // Was Reset to |minDur|.
 	require.Equal(t, message.NewClock(txn.beganAt.Add(time.Hour))+1, shard.clock) // Shard clock was updated.
+	require.True(t, txn.limited)                                                  // Holds a TxnLimiter slot.
+	require.Equal(t, 1, tf.service.TxnLimiter.Active())
 
 	// Expect it continues to block.
 	require.True(t, txnBlocks(shard, &txn))
@@ -90,6 +95,8 @@ func TestTxnPriorSyncsThenMinDurElapses(t *testing.T) {
 	require.Equal(t, time.Duration(-1), txn.minDur)
 	require.Equal(t, maxDur, txn.maxDur) // Did not reach maxDur.
 	require.NotNil(t, txn.readCh)        // Did not reach maxDur.
+	require.False(t, txn.limited)        // Slot was released upon starting to commit.
+	require.Equal(t, 0, tf.service.TxnLimiter.Active())
 	require.Equal(t, 3, txn.consumedCount)
 	require.Len(t, txn.acks, 1)
 
@@ -644,6 +651,95 @@ func TestAppDeferWithinDequeueSequence(t *testing.T) {
 		txn.checkpoint.Sources[sourceA.Name].ReadThrough)
 }
 
+func TestTxnFixedBatchesAreReproducible(t *testing.T) {
+	type committed struct {
+		count       int
+		state       string
+		readThrough pb.Offset
+	}
+	// Process input messages with a fixture shard of |spec|, and return the
+	// store state and read-through offset committed by each transaction.
+	var run = func(spec *pc.ShardSpec, readChSize int) []committed {
+		var tf, shard, cleanup = newTestFixtureWithIdleShard(t)
+		defer cleanup()
+		tf.allocateShard(spec, localID)
+
+		const inputs = 7
+		for i := 0; i != inputs; i++ {
+			var _, err = tf.pub.PublishCommitted(toSourceA,
+				&testMessage{Key: fmt.Sprintf("key-%d", i%3), Value: strconv.Itoa(i)})
+			require.NoError(t, err)
+		}
+
+		var (
+			cp    = playAndComplete(t, shard)
+			msgCh = make(chan EnvelopeOrError, readChSize)
+			timer = newTestTimer()
+			prior = transaction{
+				commitBarrier: client.FinishedOperation(nil),
+				acks:          make(OpFutures),
+				prepareDoneAt: timer.timepoint,
+			}
+			txn   = transaction{}
+			store = shard.store.(*JSONFileStore)
+			out   []committed
+		)
+		startReadingMessages(shard, cp, msgCh)
+
+		for consumed := 0; consumed != inputs; {
+			txnInit(shard, &txn, &prior, msgCh, timer.txnTimer)
+
+			for done := false; !done; {
+				// A final, partial batch commits upon its maximum duration.
+				if consumed+txn.consumedCount == inputs && txn.readCh != nil && txn.maxDur != -1 {
+					timer.timepoint = txn.beganAt.Add(txn.maxDur)
+					timer.signal()
+				}
+				done = mustTxnStep(t, shard, &txn, &prior)
+			}
+			var state, err = json.Marshal(store.State)
+			require.NoError(t, err)
+
+			out = append(out, committed{
+				count:       txn.consumedCount,
+				state:       string(state),
+				readThrough: txn.checkpoint.Sources[sourceA.Name].ReadThrough,
+			})
+			consumed += txn.consumedCount
+			prior, txn = txn, prior
+		}
+		// Await the final commit and its ACKs.
+		<-prior.commitBarrier.Done()
+		for op := range prior.acks {
+			<-op.Done()
+		}
+		return out
+	}
+
+	var spec = makeShard(shardA)
+	spec.MaxTxnDuration = time.Minute
+	spec.TxnBatchMessages = 3
+
+	// Expect fixed batches, with a final partial batch.
+	var expect = run(spec, 1)
+	require.Len(t, expect, 3)
+	require.Equal(t, committed{count: 3, state: `{"key-0":"0","key-1":"1","key-2":"2"}`,
+		readThrough: expect[0].readThrough}, expect[0])
+	require.Equal(t, committed{count: 3, state: `{"key-0":"3","key-1":"4","key-2":"5"}`,
+		readThrough: expect[1].readThrough}, expect[1])
+	require.Equal(t, committed{count: 1, state: `{"key-0":"6","key-1":"4","key-2":"5"}`,
+		readThrough: expect[2].readThrough}, expect[2])
+
+	// Transactions are the same, though messages are read at differing rates.
+	require.Equal(t, expect, run(spec, 64))
+
+	// Batches may also be sized in bytes. Each message is larger than one byte.
+	spec.TxnBatchMessages, spec.TxnBatchBytes = 0, 1
+	expect = run(spec, 1)
+	require.Len(t, expect, 7)
+	require.Equal(t, expect, run(spec, 64))
+}
+
 func TestRunTxnsACKsRecoveredCheckpoint(t *testing.T) {
 	var tf, shard, cleanup = newTestFixtureWithIdleShard(t)
 	defer cleanup()
@@ -0,0 +1,141 @@
+package consumer
+
+import (
+	"context"
+	"sync"
+)
+
+// TxnLimiter bounds the number of shards of a process which are concurrently
+// processing consumer transactions. A shard acquires a slot of the TxnLimiter
+// as it begins a transaction, and releases it once the transaction starts to
+// commit. For example, a Service with many shards which are catching up after
+// recovery may use a TxnLimiter to throttle their processing, and avoid
+// overwhelming a downstream database shared by the shards.
+//
+// Slots are granted in the order they were requested: a waiting shard is
+// never passed over by a shard which requested a slot after it, and a shard
+// which releases its slot and then immediately requests another must wait
+// behind other shards which were already waiting. No shard is starved.
+//
+// The limit may be changed at any time. A raised limit immediately grants
+// slots to waiting shards, while a lowered limit takes effect as shards
+// release their current slots. A limit of zero is unlimited.
+type TxnLimiter struct {
+	limit   int
+	active  int
+	waiting []chan struct{}
+	mu      sync.Mutex
+}
+
+// NewTxnLimiter returns a TxnLimiter of the given limit.
+func NewTxnLimiter(limit int) *TxnLimiter {
+	var l = new(TxnLimiter)
+	l.SetLimit(limit)
+	return l
+}
+
+// SetLimit sets the maximum number of concurrent transactions.
+// If zero, the number is unlimited.
+func (l *TxnLimiter) SetLimit(limit int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.limit = limit
+	shardTxnConcurrencyLimitGauge.Set(float64(limit))
+	l.grant()
+}
+
+// Limit returns the current limit.
+func (l *TxnLimiter) Limit() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.limit
+}
+
+// Active returns the number of transactions currently holding a slot.
+func (l *TxnLimiter) Active() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.active
+}
+
+// Waiting returns the number of transactions currently waiting for a slot.
+func (l *TxnLimiter) Waiting() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return len(l.waiting)
+}
+
+// acquire a slot, blocking until one is granted or the Context is done.
+func (l *TxnLimiter) acquire(ctx context.Context) error {
+	l.mu.Lock()
+
+	if len(l.waiting) == 0 && l.available() {
+		l.active++
+		shardTxnConcurrencyActiveGauge.Inc()
+		l.mu.Unlock()
+		return nil
+	}
+	var ch = make(chan struct{})
+	l.waiting = append(l.waiting, ch)
+	shardTxnConcurrencyWaitingGauge.Inc()
+	l.mu.Unlock()
+
+	select {
+	case <-ch:
+		return nil
+	case <-ctx.Done():
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	select {
+	case <-ch:
+		// We were granted a slot while also being cancelled. Release it.
+		l.releaseLocked()
+	default:
+		for i := range l.waiting {
+			if l.waiting[i] == ch {
+				l.waiting = append(l.waiting[:i], l.waiting[i+1:]...)
+				shardTxnConcurrencyWaitingGauge.Dec()
+				break
+			}
+		}
+	}
+	return ctx.Err()
+}
+
+// release a previously acquired slot.
+func (l *TxnLimiter) release() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.releaseLocked()
+}
+
+// releaseLocked releases a slot. l.mu must be held.
+func (l *TxnLimiter) releaseLocked() {
+	if l.active == 0 {
+		panic("TxnLimiter released without a prior acquire")
+	}
+	l.active--
+	shardTxnConcurrencyActiveGauge.Dec()
+	l.grant()
+}
+
+// grant available slots to waiting transactions, in order. l.mu must be held.
+func (l *TxnLimiter) grant() {
+	for len(l.waiting) != 0 && l.available() {
+		close(l.waiting[0])
+		l.waiting = l.waiting[1:]
+		l.active++
+
+		shardTxnConcurrencyWaitingGauge.Dec()
+		shardTxnConcurrencyActiveGauge.Inc()
+	}
+}
+
+// available returns true if a slot is available. l.mu must be held.
+func (l *TxnLimiter) available() bool {
+	return l.limit <= 0 || l.active < l.limit
+}
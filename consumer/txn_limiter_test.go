@@ -0,0 +1,120 @@
+package consumer
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTxnLimiterGrantsInOrder(t *testing.T) {
+	var l = NewTxnLimiter(2)
+	var ctx = context.Background()
+
+	require.NoError(t, l.acquire(ctx))
+	require.NoError(t, l.acquire(ctx))
+	require.Equal(t, 2, l.Active())
+
+	// Start waiters, in order.
+	var granted = make(chan int, 3)
+	for i := 0; i != 3; i++ {
+		go func(i int) {
+			require.NoError(t, l.acquire(ctx))
+			granted <- i
+		}(i)
+		for l.Waiting() != i+1 {
+		} // Wait for |i| to queue.
+	}
+
+	// A released slot is granted to the first waiter. A re-acquiring
+	// holder queues behind waiters which were already waiting.
+	l.release()
+	require.Equal(t, 0, <-granted)
+
+	var reacquired = make(chan struct{})
+	go func() {
+		require.NoError(t, l.acquire(ctx))
+		close(reacquired)
+	}()
+	for l.Waiting() != 3 {
+	}
+
+	l.release()
+	require.Equal(t, 1, <-granted)
+	l.release()
+	require.Equal(t, 2, <-granted)
+	l.release()
+	<-reacquired
+
+	require.Equal(t, 2, l.Active())
+	require.Equal(t, 0, l.Waiting())
+}
+
+func TestTxnLimiterSetLimitAtRuntime(t *testing.T) {
+	var l = NewTxnLimiter(1)
+	var ctx = context.Background()
+
+	require.NoError(t, l.acquire(ctx))
+
+	var granted = make(chan struct{}, 2)
+	for i := 0; i != 2; i++ {
+		go func() {
+			require.NoError(t, l.acquire(ctx))
+			granted <- struct{}{}
+		}()
+	}
+	for l.Waiting() != 2 {
+	}
+
+	// Raising the limit grants waiting slots.
+	l.SetLimit(2)
+	<-granted
+	require.Equal(t, 2, l.Active())
+	require.Equal(t, 1, l.Waiting())
+
+	// A zero limit is unlimited.
+	l.SetLimit(0)
+	<-granted
+	require.Equal(t, 3, l.Active())
+	require.NoError(t, l.acquire(ctx))
+	require.Equal(t, 4, l.Active())
+
+	// Lowering the limit takes effect as slots are released.
+	l.SetLimit(1)
+	require.Equal(t, 1, l.Limit())
+
+	go func() {
+		require.NoError(t, l.acquire(ctx))
+		granted <- struct{}{}
+	}()
+	for l.Waiting() != 1 {
+	}
+
+	for i := 0; i != 3; i++ {
+		l.release()
+		require.Equal(t, 1, l.Waiting())
+	}
+	l.release()
+	<-granted
+	require.Equal(t, 1, l.Active())
+}
+
+func TestTxnLimiterCancellation(t *testing.T) {
+	var l = NewTxnLimiter(1)
+	require.NoError(t, l.acquire(context.Background()))
+
+	var ctx, cancel = context.WithCancel(context.Background())
+	var errCh = make(chan error)
+	go func() { errCh <- l.acquire(ctx) }()
+
+	for l.Waiting() != 1 {
+	}
+	cancel()
+	require.Equal(t, context.Canceled, <-errCh)
+	require.Equal(t, 0, l.Waiting())
+	require.Equal(t, 1, l.Active())
+
+	// The cancelled waiter is not granted the released slot.
+	l.release()
+	require.Equal(t, 0, l.Active())
+}
@@ -0,0 +1,218 @@
+package consumer
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// TxnRetryPolicy is a policy for the retry of consumer transactions of a
+// primary Shard, which have failed with a retryable error (see IsRetryable).
+//
+// A Shard retries by rolling back the current transaction of its Store,
+// restoring its Checkpoint, and restarting the reading and processing of
+// messages from it, as would happen if a MessageProducer closed its channel.
+// Only Shards having a RollbackStore may retry: Shards of other Stores fail
+// upon any transaction error, as they would without a policy. MessageProducer
+// Applications are restarted with a new channel, and the prior channel is
+// no longer read.
+type TxnRetryPolicy interface {
+	// RetryTxn is called with the retryable error of a failed transaction of
+	// the Shard, the number of consecutive retries which preceded it, and the
+	// duration for which the Shard has been failing (since the first failed
+	// transaction of |attempt| zero). It returns the delay after which the
+	// Shard retries, or false if the Shard should instead relinquish its
+	// primary assignment, allowing the allocator to select another primary.
+	RetryTxn(_ Shard, _ error, attempt int, failingFor time.Duration) (time.Duration, bool)
+}
+
+// RollbackStore is a Store which is able to discard all state of its current,
+// uncommitted transaction, such that a following RestoreCheckpoint returns
+// the Store to its last committed Checkpoint. SQLStore and the bbolt Store
+// are RollbackStores. Stores which can't roll back (such as JSONFileStore,
+// which mutates its State in memory) must not be retried.
+type RollbackStore interface {
+	Store
+	// RollbackTxn discards the current, uncommitted transaction of the Store.
+	// It's called only after all started commits of the Store have resolved.
+	RollbackTxn() error
+}
+
+// BackoffRetryPolicy is a TxnRetryPolicy which retries with exponential
+// backoff, until an optional maximum number of attempts or deadline.
+type BackoffRetryPolicy struct {
+	// Initial delay of the first retry. If zero, 100ms is used.
+	Initial time.Duration
+	// Max delay of a retry. If zero, 30s is used.
+	Max time.Duration
+	// Multiplier of the delay of each successive retry. If less than one, 2 is used.
+	Multiplier float64
+	// MaxAttempts of consecutive retries, after which the Shard relinquishes
+	// its assignment. If zero, attempts are unlimited.
+	MaxAttempts int
+	// Deadline is the duration a Shard may continuously fail, after which it
+	// relinquishes its assignment. If zero, there's no deadline.
+	Deadline time.Duration
+}
+
+// RetryTxn implements TxnRetryPolicy.
+func (p BackoffRetryPolicy) RetryTxn(_ Shard, _ error, attempt int, failingFor time.Duration) (time.Duration, bool) {
+	if p.MaxAttempts != 0 && attempt >= p.MaxAttempts {
+		return 0, false
+	} else if p.Deadline != 0 && failingFor >= p.Deadline {
+		return 0, false
+	}
+
+	var delay, max, mult = p.Initial, p.Max, p.Multiplier
+	if delay == 0 {
+		delay = 100 * time.Millisecond
+	}
+	if max == 0 {
+		max = 30 * time.Second
+	}
+	if mult < 1 {
+		mult = 2
+	}
+	for ; attempt != 0 && delay < max; attempt-- {
+		delay = time.Duration(float64(delay) * mult)
+	}
+	if delay > max {
+		delay = max
+	}
+	// Don't wait beyond the deadline only to then relinquish.
+	if p.Deadline != 0 && failingFor+delay > p.Deadline {
+		delay = p.Deadline - failingFor
+	}
+	return delay, true
+}
+
+// Retryable wraps |err| to mark it as a transient error (for example, a
+// failure to reach a downstream database) which may succeed if retried.
+// Applications and Stores use Retryable to mark errors as safe to retry
+// under the Service.TxnRetry policy. A nil |err| returns nil.
+func Retryable(err error) error {
+	if err == nil {
+		return nil
+	}
+	return retryableError{err}
+}
+
+// IsRetryable returns true if |err| or an error it wraps was marked by
+// Retryable, or has a Temporary method (as do many errors of package net)
+// which returns true. Other errors, such as of data corruption or of fenced
+// Stores, are fatal to the Shard and are never retried.
+func IsRetryable(err error) bool {
+	var re retryableError
+	var te interface{ Temporary() bool }
+
+	if errors.As(err, &re) {
+		return true
+	} else if errors.As(err, &te) {
+		return te.Temporary()
+	}
+	return false
+}
+
+// relinquishTimeout bounds the time for which a shard which relinquished its
+// assignment awaits its cancellation, before instead failing.
+var relinquishTimeout = time.Minute
+
+type retryableError struct{ err error }
+
+func (e retryableError) Error() string { return e.err.Error() }
+func (e retryableError) Unwrap() error { return e.err }
+
+// mayRetryTxns returns true if the shard's transactions, having failed
+// with |err|, may be retried.
+func mayRetryTxns(s *shard, err error) bool {
+	var _, ok = s.store.(RollbackStore)
+	return ok && s.svc.TxnRetry != nil && IsRetryable(err)
+}
+
+// warnTxnRetryStore logs, once per Store type, if the Service has a
+// TxnRetryPolicy but the shard's Store isn't a RollbackStore and can't retry.
+func warnTxnRetryStore(s *shard) {
+	var _, ok = s.store.(RollbackStore)
+	if s.svc.TxnRetry == nil || ok {
+		return
+	}
+	var storeType = fmt.Sprintf("%T", s.store)
+
+	if _, warned := txnRetryWarnedStores.LoadOrStore(storeType, struct{}{}); !warned {
+		log.WithFields(log.Fields{"store": storeType, "shard": s.Spec().Id}).
+			Warn("Service.TxnRetry is set, but Store isn't a RollbackStore and its transactions won't be retried")
+	}
+}
+
+// txnRetryWarnedStores are Store types of which warnTxnRetryStore has logged.
+var txnRetryWarnedStores sync.Map
+
+// retryTxns determines whether the primary shard retries its transactions,
+// after they failed with |err|. If so, it rolls back the shard Store,
+// awaits the retry delay, and returns nil. Otherwise it returns an error with
+// which the primary fails. If the TxnRetryPolicy declines to retry, the
+// shard's assignment is relinquished and retryTxns awaits the shard's
+// cancellation (for at most relinquishTimeout) before returning |err|.
+func retryTxns(s *shard, err error) error {
+	if !mayRetryTxns(s, err) {
+		return err
+	}
+	var policy = s.svc.TxnRetry
+	var id = s.Spec().Id.String()
+	var now = time.Now()
+
+	if s.txnRetry.attempt == 0 {
+		s.txnRetry.failingSince = now
+	}
+	var delay, ok = policy.RetryTxn(s, err, s.txnRetry.attempt, now.Sub(s.txnRetry.failingSince))
+
+	if !ok {
+		if rErr := relinquishPrimary(s); rErr != nil {
+			log.WithFields(log.Fields{"err": rErr, "shard": id}).
+				Warn("failed to relinquish shard after failed transaction retries")
+			return err
+		}
+		log.WithFields(log.Fields{"err": err, "shard": id, "attempts": s.txnRetry.attempt}).
+			Warn("relinquished primary assignment of shard after failed transaction retries")
+
+		select {
+		case <-s.ctx.Done():
+		case <-time.After(relinquishTimeout):
+		}
+		return err
+	}
+
+	if rErr := s.store.(RollbackStore).RollbackTxn(); rErr != nil {
+		return fmt.Errorf("rolling back Store after %q: %w", err, rErr)
+	}
+	s.txnRetry.attempt++
+	shardTxnRetriesTotal.WithLabelValues(id).Inc()
+
+	log.WithFields(log.Fields{"err": err, "shard": id, "attempt": s.txnRetry.attempt, "delay": delay}).
+		Warn("consumer transaction failed (will retry)")
+
+	select {
+	case <-s.ctx.Done():
+		return err
+	case <-time.After(delay):
+		return nil
+	}
+}
+
+// awaitTxnCommits blocks until the commits of |txns| which have started
+// have resolved, or until the shard is cancelled.
+func awaitTxnCommits(s *shard, txns ...*transaction) {
+	for _, txn := range txns {
+		if txn.commitBarrier == nil {
+			continue // Not started to commit.
+		}
+		select {
+		case <-txn.commitBarrier.Done():
+		case <-s.ctx.Done():
+			return
+		}
+	}
+}
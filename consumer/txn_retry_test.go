@@ -0,0 +1,185 @@
+package consumer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+	"go.gazette.dev/core/allocator"
+	pc "go.gazette.dev/core/consumer/protocol"
+)
+
+func TestBackoffRetryPolicy(t *testing.T) {
+	var p = BackoffRetryPolicy{Initial: time.Second, Max: 5 * time.Second, Multiplier: 2}
+
+	for attempt, expect := range []time.Duration{
+		time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second, 5 * time.Second,
+	} {
+		var delay, ok = p.RetryTxn(nil, nil, attempt, 0)
+		require.True(t, ok)
+		require.Equal(t, expect, delay)
+	}
+
+	// Case: zero-valued fields have defaults.
+	var delay, ok = BackoffRetryPolicy{}.RetryTxn(nil, nil, 1, time.Hour)
+	require.True(t, ok)
+	require.Equal(t, 200*time.Millisecond, delay)
+
+	// Case: MaxAttempts are exhausted.
+	p.MaxAttempts = 3
+	_, ok = p.RetryTxn(nil, nil, 2, 0)
+	require.True(t, ok)
+	_, ok = p.RetryTxn(nil, nil, 3, 0)
+	require.False(t, ok)
+
+	// Case: the delay is bounded by the Deadline, after which retries stop.
+	p = BackoffRetryPolicy{Initial: time.Second, Deadline: 10 * time.Second}
+	delay, ok = p.RetryTxn(nil, nil, 5, 9*time.Second)
+	require.True(t, ok)
+	require.Equal(t, time.Second, delay)
+	_, ok = p.RetryTxn(nil, nil, 6, 10*time.Second)
+	require.False(t, ok)
+}
+
+func TestIsRetryable(t *testing.T) {
+	var base = errors.New("downstream is unreachable")
+
+	require.False(t, IsRetryable(base))
+	require.False(t, IsRetryable(nil))
+	require.Nil(t, Retryable(nil))
+
+	require.True(t, IsRetryable(Retryable(base)))
+	require.True(t, IsRetryable(fmt.Errorf("app.ConsumeMessage: %w", Retryable(base))))
+	require.EqualError(t, Retryable(base), base.Error())
+	require.True(t, errors.Is(Retryable(base), base))
+
+	// Errors having a Temporary method are retryable if it returns true.
+	require.True(t, IsRetryable(&net.DNSError{IsTemporary: true}))
+	require.False(t, IsRetryable(&net.DNSError{IsTemporary: false}))
+}
+
+func TestShardRetriesRetryableTxnErrors(t *testing.T) {
+	var tf, cleanup = newTestFixture(t)
+	defer cleanup()
+
+	// The first two transactions fail, and the third succeeds.
+	tf.app.consumeErr = Retryable(errors.New("downstream is unreachable"))
+	var attempts []int
+	var errs []string
+
+	tf.service.TxnRetry = retryFunc(func(_ Shard, err error, attempt int, _ time.Duration) (time.Duration, bool) {
+		attempts, errs = append(attempts, attempt), append(errs, err.Error())
+
+		if attempt == 1 {
+			tf.app.consumeErr = nil
+		}
+		return time.Millisecond, true
+	})
+
+	var spec = makeRemoteShard(shardA)
+	tf.allocateShard(spec, localID)
+
+	var res, err = tf.resolver.Resolve(ResolveArgs{Context: context.Background(), ShardID: shardA})
+	require.NoError(t, err)
+	defer res.Done()
+
+	runTransaction(tf, res.Shard, map[string]string{"foo": "bar"})
+	require.Equal(t, []int{0, 1}, attempts)
+	require.Equal(t, []string{
+		"app.ConsumeMessage: downstream is unreachable",
+		"app.ConsumeMessage: downstream is unreachable",
+	}, errs)
+	require.Equal(t, 2.0, testutil.ToFloat64(shardTxnRetriesTotal.WithLabelValues(shardA)))
+
+	// The retried transaction committed, and the shard remains PRIMARY.
+	var value string
+	require.NoError(t, tf.app.db.QueryRow(`SELECT value FROM kvstates WHERE key = 'foo'`).Scan(&value))
+	require.Equal(t, "bar", value)
+	expectStatusCode(t, tf.state, pc.ReplicaStatus_PRIMARY)
+
+	tf.allocateShard(spec) // Cleanup.
+}
+
+func TestShardRelinquishedAfterTxnRetries(t *testing.T) {
+	var tf, cleanup = newTestFixture(t)
+	defer cleanup()
+
+	tf.app.consumeErr = Retryable(errors.New("downstream is unreachable"))
+	tf.service.TxnRetry = BackoffRetryPolicy{Initial: time.Millisecond, MaxAttempts: 2}
+
+	var spec = makeRemoteShard(shardA)
+	tf.allocateShard(spec, localID, remoteID)
+	tf.setReplicaStatus(spec, remoteID, 1, pc.ReplicaStatus_STANDBY)
+
+	var res, err = tf.resolver.Resolve(ResolveArgs{Context: context.Background(), ShardID: shardA})
+	require.NoError(t, err)
+	var shard = res.Shard
+	res.Done()
+
+	_, _ = tf.pub.PublishCommitted(toSourceA, &testMessage{})
+
+	// Expect the shard is cancelled, as its primary Assignment is removed
+	// in favor of the STANDBY.
+	<-shard.Context().Done()
+
+	tf.ks.Mu.RLock()
+	require.Len(t, tf.ks.Prefixed(allocator.ItemAssignmentsPrefix(tf.ks, shardA)), 1)
+	tf.ks.Mu.RUnlock()
+
+	tf.allocateShard(spec) // Cleanup.
+}
+
+func TestShardFailsWithFatalTxnError(t *testing.T) {
+	var tf, cleanup = newTestFixture(t)
+	defer cleanup()
+
+	tf.app.consumeErr = errors.New("corrupted state")
+	tf.service.TxnRetry = retryFunc(func(Shard, error, int, time.Duration) (time.Duration, bool) {
+		panic("not called")
+	})
+
+	var spec = makeRemoteShard(shardA)
+	tf.allocateShard(spec, localID)
+
+	_, _ = tf.pub.PublishCommitted(toSourceA, &testMessage{})
+
+	require.Equal(t, "runTransactions: app.ConsumeMessage: corrupted state",
+		expectStatusCode(t, tf.state, pc.ReplicaStatus_FAILED).Errors[0])
+
+	tf.allocateShard(spec) // Cleanup.
+}
+
+func TestShardWithTxnRetryOfNonRollbackStoreIsNotRetried(t *testing.T) {
+	var tf, cleanup = newTestFixture(t)
+	defer cleanup()
+
+	tf.app.consumeErr = Retryable(errors.New("downstream is unreachable"))
+	tf.service.TxnRetry = retryFunc(func(Shard, error, int, time.Duration) (time.Duration, bool) {
+		panic("not called")
+	})
+
+	// Shards having recovery logs use a JSONFileStore, which can't roll back.
+	// The shard is nonetheless a PRIMARY.
+	var spec = makeShard(shardA)
+	tf.allocateShard(spec, localID)
+	expectStatusCode(t, tf.state, pc.ReplicaStatus_PRIMARY)
+
+	// A retryable transaction error isn't retried, and fails the shard.
+	_, _ = tf.pub.PublishCommitted(toSourceA, &testMessage{})
+
+	require.Equal(t, "runTransactions: app.ConsumeMessage: downstream is unreachable",
+		expectStatusCode(t, tf.state, pc.ReplicaStatus_FAILED).Errors[0])
+
+	tf.allocateShard(spec) // Cleanup.
+}
+
+type retryFunc func(Shard, error, int, time.Duration) (time.Duration, bool)
+
+func (fn retryFunc) RetryTxn(s Shard, err error, attempt int, failingFor time.Duration) (time.Duration, bool) {
+	return fn(s, err, attempt, failingFor)
+}
@@ -0,0 +1,184 @@
+package consumer
+
+import (
+	"container/heap"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+	pb "go.gazette.dev/core/broker/protocol"
+	pc "go.gazette.dev/core/consumer/protocol"
+	"go.gazette.dev/core/message"
+)
+
+// EventTimer is an optional interface of Application which consumes messages
+// in order of their event time, rather than in the order in which they were
+// read from source journals. This is useful for windowed aggregations, which
+// must tolerate messages which arrive out of order.
+//
+// Each primary Shard tracks a watermark: the largest event time of any
+// message it has read, less the Shard's AllowedLateness. Read messages are
+// buffered until the watermark reaches their event time, and are then passed
+// to ConsumeMessage in event-time order. A message which is read with an
+// event time already behind the watermark is "late", and is passed to
+// ConsumeMessage immediately. Note the watermark advances only as messages
+// are read: buffered messages are held until a later message advances it.
+//
+// Buffered messages are written to the Shard's recovery log with each
+// transaction Checkpoint, and are restored alongside it. Messages which have
+// been read but not yet consumed are thus not lost if the Shard faults.
+// EventTimer requires that the Shard have a recovery log, and that its
+// source journals have a content type which message.FramingByContentType
+// supports.
+type EventTimer interface {
+	// EventTime returns the event time of the message. A zero-valued time.Time
+	// opts the message out of buffering: it's consumed immediately.
+	// Acknowledgements are always consumed immediately, and are not passed
+	// to EventTime.
+	EventTime(Shard, message.Envelope) time.Time
+	// AllowedLateness returns the duration by which the Shard's watermark
+	// trails the largest event time it has read.
+	AllowedLateness(Shard) time.Duration
+}
+
+// watermarkBuffer buffers messages until an event-time watermark passes them.
+type watermarkBuffer struct {
+	eventTime func(message.Envelope) time.Time
+	lateness  time.Duration
+	log       bufferLog
+
+	maxEventTime time.Time        // Largest event time read.
+	buffered     bufferedMessages // Messages awaiting the watermark.
+	seq          int64            // Next arrival sequence number.
+}
+
+// bufferedMessage is a message awaiting release by a messageBuffer.
+type bufferedMessage struct {
+	env       message.Envelope
+	eventTime time.Time
+	seq       int64 // Orders messages of equal event time by arrival.
+}
+
+// newShardWatermarkBuffer returns a watermarkBuffer of the EventTimer
+// Application, which persists to the recovery log of the shard.
+func newShardWatermarkBuffer(s *shard, et EventTimer) (*watermarkBuffer, error) {
+	if s.recovery.recorder == nil {
+		return nil, errors.New("EventTimer Application requires a shard recovery log")
+	}
+	return &watermarkBuffer{
+		eventTime: func(env message.Envelope) time.Time { return et.EventTime(s, env) },
+		lateness:  et.AllowedLateness(s),
+		log:       newShardBufferLog(s, watermarkStateName),
+	}, nil
+}
+
+// consume buffers |env|, and then calls |consumeFn| with each buffered
+// message which the watermark has passed, in event-time order. If
+// |consumeFn| returns an error, its message remains buffered.
+func (b *watermarkBuffer) consume(env message.Envelope, consumeFn func(message.Envelope) error) error {
+	if message.GetFlags(env.GetUUID()) == message.Flag_ACK_TXN {
+		return consumeFn(env)
+	}
+	var eventTime = b.eventTime(env)
+	if eventTime.IsZero() {
+		return consumeFn(env)
+	}
+
+	heap.Push(&b.buffered, bufferedMessage{env: env, eventTime: eventTime, seq: b.seq})
+	b.seq++
+
+	if eventTime.After(b.maxEventTime) {
+		b.maxEventTime = eventTime
+	}
+	var watermark = b.maxEventTime.Add(-b.lateness)
+
+	for len(b.buffered) != 0 && !b.buffered[0].eventTime.After(watermark) {
+		if err := consumeFn(b.buffered[0].env); err != nil {
+			return err
+		}
+		heap.Pop(&b.buffered)
+	}
+	return nil
+}
+
+// nextRelease returns a zero time, as buffered messages are released only
+// as the watermark advances with further reads.
+func (b *watermarkBuffer) nextRelease() time.Time { return time.Time{} }
+
+// release is a no-op, as buffered messages are released only as the
+// watermark advances with further reads.
+func (b *watermarkBuffer) release(func(message.Envelope) error) (int, error) { return 0, nil }
+
+// persist writes the buffered messages to the recovery log in event-time
+// order, to be restored with Checkpoint |cp|.
+func (b *watermarkBuffer) persist(cp pc.Checkpoint) error {
+	var sorted = append(bufferedMessages(nil), b.buffered...)
+	sort.Sort(sorted)
+
+	return b.log.persist(cp, b.maxEventTime, sorted)
+}
+
+// committed notifies that Checkpoint |cp| of a prior persist has committed.
+func (b *watermarkBuffer) committed(cp pc.Checkpoint) error { return b.log.committed(cp) }
+
+// restore the buffered messages persisted alongside Checkpoint |cp|.
+func (b *watermarkBuffer) restore(cp pc.Checkpoint) error {
+	var maxEventTime, buffered, err = b.log.restore(cp)
+	if err != nil {
+		return err
+	}
+	// Messages are persisted in order, and so remain a valid heap.
+	b.maxEventTime = maxEventTime
+	b.buffered = buffered
+	b.seq = int64(len(buffered))
+
+	return nil
+}
+
+// discard buffered messages of source |journals|.
+func (b *watermarkBuffer) discard(journals []pb.Journal) {
+	var discarded = make(map[pb.Journal]struct{}, len(journals))
+	for _, journal := range journals {
+		discarded[journal] = struct{}{}
+	}
+
+	var kept = b.buffered[:0]
+	for _, m := range b.buffered {
+		if _, ok := discarded[m.env.Journal.Name]; !ok {
+			kept = append(kept, m)
+		}
+	}
+	for i := len(kept); i != len(b.buffered); i++ {
+		b.buffered[i] = bufferedMessage{} // Release for GC.
+	}
+	b.buffered = kept
+	heap.Init(&b.buffered)
+}
+
+// bufferedMessages implements heap.Interface over bufferedMessage.
+type bufferedMessages []bufferedMessage
+
+func (h bufferedMessages) Len() int            { return len(h) }
+func (h bufferedMessages) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *bufferedMessages) Push(x interface{}) { *h = append(*h, x.(bufferedMessage)) }
+
+// Less orders on ascending event time, and for the same event time,
+// ascending arrival sequence.
+func (h bufferedMessages) Less(i, j int) bool {
+	if !h[i].eventTime.Equal(h[j].eventTime) {
+		return h[i].eventTime.Before(h[j].eventTime)
+	}
+	return h[i].seq < h[j].seq
+}
+
+func (h *bufferedMessages) Pop() interface{} {
+	var old, n = *h, len(*h)
+	var x = old[n-1]
+	*h = old[0 : n-1]
+	return x
+}
+
+// watermarkStateName is the name of the persisted watermarkBuffer state,
+// which is written to files "watermark.json" and "watermark.next.json"
+// of the recovery log.
+const watermarkStateName = "watermark"
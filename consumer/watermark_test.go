@@ -0,0 +1,318 @@
+package consumer
+
+import (
+	"container/heap"
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+	pb "go.gazette.dev/core/broker/protocol"
+	pc "go.gazette.dev/core/consumer/protocol"
+	"go.gazette.dev/core/message"
+)
+
+func TestWatermarkBufferReleasesInEventTimeOrder(t *testing.T) {
+	var b = newTestWatermarkBuffer(afero.NewMemMapFs())
+	var consumed []string
+
+	var consumeFn = func(env message.Envelope) error {
+		consumed = append(consumed, env.Message.(*testMessage).Key)
+		return nil
+	}
+	// Messages arrive out of order. The watermark trails the largest
+	// event time by three seconds.
+	for _, env := range []message.Envelope{
+		watermarkEnv("a", 10, 100),
+		watermarkEnv("b", 8, 200),
+		watermarkEnv("c", 12, 300), // Watermark 9: releases "b".
+		watermarkEnv("d", 6, 400),  // Late: released immediately.
+		watermarkEnv("e", 11, 500),
+		watermarkEnv("f", 10, 600),
+		watermarkEnv("g", 15, 700), // Watermark 12: releases "a", "f", "e", "c".
+	} {
+		require.NoError(t, b.consume(env, consumeFn))
+	}
+	require.Equal(t, []string{"b", "d", "a", "f", "e", "c"}, consumed)
+
+	// Messages with a zero event time, and acknowledgements, aren't buffered.
+	consumed = nil
+	require.NoError(t, b.consume(watermarkEnv("zero", 0, 800), consumeFn))
+
+	var ack = watermarkEnv("ack", 20, 900)
+	ack.Message.SetUUID(message.BuildUUID(message.ProducerID{}, 1, message.Flag_ACK_TXN))
+	require.NoError(t, b.consume(ack, consumeFn))
+	require.Equal(t, []string{"zero", "ack"}, consumed)
+
+	// A failed message remains buffered, and is retried with the next message.
+	consumed = nil
+	var failFn = func(env message.Envelope) error { return errors.New("whoops") }
+	require.EqualError(t, b.consume(watermarkEnv("h", 19, 1000), failFn), "whoops")
+	require.NoError(t, b.consume(watermarkEnv("i", 17, 1100), consumeFn))
+	require.Equal(t, []string{"g"}, consumed)
+	require.Equal(t, []string{"i", "h"}, drainKeys(b))
+}
+
+func TestWatermarkBufferPersistAndRestore(t *testing.T) {
+	var fs = afero.NewMemMapFs()
+	var b = newTestWatermarkBuffer(fs)
+	var consumeFn = func(message.Envelope) error { return nil }
+
+	var cp0 = watermarkCheckpoint(100)
+	var cp1 = watermarkCheckpoint(200)
+	var cp2 = watermarkCheckpoint(400)
+
+	// Restoring from an empty directory starts from an empty buffer,
+	// which is persisted alongside the restored Checkpoint.
+	require.NoError(t, b.restore(cp0))
+	require.Len(t, b.buffered, 0)
+	require.NoError(t, b.restore(cp0))
+
+	require.NoError(t, b.consume(watermarkEnv("a", 10, 100), consumeFn))
+	require.NoError(t, b.consume(watermarkEnv("b", 8, 200), consumeFn))
+	require.NoError(t, b.persist(cp1))
+	require.NoError(t, b.committed(cp1))
+
+	var persistCP2 = func() {
+		require.NoError(t, b.consume(watermarkEnv("c", 12, 300), consumeFn)) // Releases "b".
+		require.NoError(t, b.consume(watermarkEnv("d", 11, 400), consumeFn))
+		require.NoError(t, b.persist(cp2))
+	}
+	persistCP2()
+
+	// The commit of |cp2| fails, and |cp1| is restored.
+	require.NoError(t, b.restore(cp1))
+	require.Equal(t, []string{"b", "a"}, drainKeys(b))
+	require.Equal(t, int64(10), b.maxEventTime.Unix())
+
+	// The commit of |cp2| fails a second time. |cp1| remains restorable.
+	require.NoError(t, b.restore(cp1))
+	persistCP2()
+	require.NoError(t, b.restore(cp1))
+	require.Equal(t, []string{"b", "a"}, drainKeys(b))
+
+	// We fault after |cp2| commits, but before its commit was observed.
+	require.NoError(t, b.restore(cp1))
+	persistCP2()
+
+	var restored = newTestWatermarkBuffer(fs)
+	require.NoError(t, restored.restore(cp2))
+	require.Equal(t, []string{"a", "d", "c"}, drainKeys(restored))
+	require.Equal(t, int64(12), restored.maxEventTime.Unix())
+
+	// Restored messages are released as the watermark advances.
+	require.NoError(t, restored.restore(cp2))
+	var consumed []message.Envelope
+	require.NoError(t, restored.consume(watermarkEnv("e", 14, 500), func(env message.Envelope) error {
+		consumed = append(consumed, env)
+		return nil
+	}))
+	require.Len(t, consumed, 2)
+	require.Equal(t, "a", consumed[0].Message.(*testMessage).Key)
+	require.Equal(t, message.Envelope{
+		Journal: sourceA,
+		Begin:   399,
+		End:     400,
+		Message: &testMessage{Key: "d", Value: "11"},
+	}, consumed[1])
+
+	// Checkpoints older than the last committed one can no longer be restored.
+	require.EqualError(t, restored.restore(cp1),
+		"no persisted watermark state matches the restored checkpoint")
+}
+
+func TestShardWatermarkSurvivesRecovery(t *testing.T) {
+	var tf, cleanup = newTestFixture(t)
+	defer cleanup()
+
+	tf.service.App = &eventTimeApplication{testApplication: tf.app}
+	var spec = makeShard(shardA)
+
+	tf.allocateShard(spec, localID)
+	expectStatusCode(t, tf.state, pc.ReplicaStatus_PRIMARY)
+
+	var res, err = tf.resolver.Resolve(ResolveArgs{Context: context.Background(), ShardID: shardA})
+	require.NoError(t, err)
+
+	// Watermark is 9s after "c", and only "b" is released.
+	runOrderedTransaction(tf, res.Shard, []testMessage{
+		{Key: "a", Value: "10"},
+		{Key: "b", Value: "5"},
+		{Key: "c", Value: "12"},
+	})
+	verifyStoreAndEchoOut(t, res.Shard.(*shard), map[string]string{"b": "5"})
+
+	// De-assign the shard, and then re-assign it to recover from its log.
+	res.Done()
+	tf.allocateShard(spec)
+	tf.allocateShard(spec, localID)
+	expectStatusCode(t, tf.state, pc.ReplicaStatus_PRIMARY)
+
+	res, err = tf.resolver.Resolve(ResolveArgs{Context: context.Background(), ShardID: shardA})
+	require.NoError(t, err)
+
+	// Watermark is 17s after "d". Recovered messages "a" and "c" are released.
+	runOrderedTransaction(tf, res.Shard, []testMessage{{Key: "d", Value: "20"}})
+	verifyStoreAndEchoOut(t, res.Shard.(*shard),
+		map[string]string{"a": "10", "b": "5", "c": "12"})
+
+	res.Done()
+	tf.allocateShard(spec) // Cleanup.
+}
+
+func TestShardWatermarkResetCheckpoint(t *testing.T) {
+	var tf, cleanup = newTestFixture(t)
+	defer cleanup()
+
+	tf.service.App = &eventTimeApplication{testApplication: tf.app}
+	tf.allocateShard(makeShard(shardA), localID)
+	expectStatusCode(t, tf.state, pc.ReplicaStatus_PRIMARY)
+
+	var res, err = tf.resolver.Resolve(ResolveArgs{Context: context.Background(), ShardID: shardA})
+	require.NoError(t, err)
+
+	// resetAndResolve resets |offsets| of the shard, which is then recovered
+	// by a new primary that applies the ResetCheckpoint.
+	var resetAndResolve = func(offsets pb.Offsets) {
+		res.Done()
+
+		var resp, err = tf.service.ResetCheckpoint(context.Background(), &pc.ResetCheckpointRequest{
+			Shard:                shardA,
+			ReadThrough:          offsets,
+			AllowStoreDivergence: true,
+		})
+		require.NoError(t, err)
+		require.Equal(t, pc.Status_OK, resp.Status)
+
+		var spec = awaitShardSpec(t, tf, func(spec *pc.ShardSpec) bool { return spec.ResetCheckpoint != nil })
+		tf.allocateShard(spec, localID)
+		expectStatusCode(t, tf.state, pc.ReplicaStatus_PRIMARY)
+		awaitShardSpec(t, tf, func(spec *pc.ShardSpec) bool { return spec.ResetCheckpoint == nil })
+
+		res, err = tf.resolver.Resolve(ResolveArgs{Context: context.Background(), ShardID: shardA})
+		require.NoError(t, err)
+	}
+	var readThroughA = func() pb.Offset {
+		var stat, err = tf.service.Stat(context.Background(), &pc.StatRequest{Shard: shardA})
+		require.NoError(t, err)
+		return stat.ReadThrough[sourceA.Name]
+	}
+
+	// Watermark is 9s after "c", and only "b" is released.
+	runOrderedTransaction(tf, res.Shard, []testMessage{
+		{Key: "a", Value: "10"},
+		{Key: "b", Value: "5"},
+		{Key: "c", Value: "12"},
+	})
+	verifyStoreAndEchoOut(t, res.Shard.(*shard), map[string]string{"b": "5"})
+
+	// Case: a reset of another source retains buffered messages of sourceA,
+	// which are released as the watermark advances past them.
+	resetAndResolve(pb.Offsets{sourceB.Name: 0})
+
+	runOrderedTransaction(tf, res.Shard, []testMessage{{Key: "d", Value: "20"}})
+	verifyStoreAndEchoOut(t, res.Shard.(*shard),
+		map[string]string{"a": "10", "b": "5", "c": "12"})
+
+	// Case: a reset of sourceA discards its buffered message "d", which
+	// is not released as the watermark advances past it.
+	resetAndResolve(pb.Offsets{sourceA.Name: readThroughA()})
+
+	runOrderedTransaction(tf, res.Shard, []testMessage{{Key: "e", Value: "30"}})
+	verifyStoreAndEchoOut(t, res.Shard.(*shard),
+		map[string]string{"a": "10", "b": "5", "c": "12"})
+
+	res.Done()
+	tf.allocateShard(makeShard(shardA)) // Cleanup.
+}
+
+func TestShardWatermarkRequiresRecoveryLog(t *testing.T) {
+	var tf, cleanup = newTestFixture(t)
+	defer cleanup()
+
+	tf.service.App = &eventTimeApplication{testApplication: tf.app}
+
+	tf.allocateShard(makeRemoteShard(shardA), localID)
+	require.Equal(t, "completeRecovery: EventTimer Application requires a shard recovery log",
+		expectStatusCode(t, tf.state, pc.ReplicaStatus_FAILED).Errors[0])
+
+	tf.allocateShard(makeRemoteShard(shardA)) // Cleanup.
+}
+
+// eventTimeApplication is a testApplication which is an EventTimer. Event
+// times are the testMessage Value, in seconds.
+type eventTimeApplication struct{ *testApplication }
+
+func (a *eventTimeApplication) EventTime(_ Shard, env message.Envelope) time.Time {
+	if sec, err := strconv.Atoi(env.Message.(*testMessage).Value); err == nil {
+		return time.Unix(int64(sec), 0)
+	}
+	return time.Time{}
+}
+
+func (a *eventTimeApplication) AllowedLateness(Shard) time.Duration { return 3 * time.Second }
+
+func newTestWatermarkBuffer(fs afero.Fs) *watermarkBuffer {
+	return &watermarkBuffer{
+		eventTime: func(env message.Envelope) time.Time { return new(eventTimeApplication).EventTime(nil, env) },
+		lateness:  3 * time.Second,
+		log: bufferLog{
+			name:   watermarkStateName,
+			newMsg: new(testApplication).NewMessage,
+			fs:     fs,
+			dir:    "/",
+		},
+	}
+}
+
+func watermarkEnv(key string, sec int, end pb.Offset) message.Envelope {
+	var value = strconv.Itoa(sec)
+	if sec == 0 {
+		value = ""
+	}
+	return message.Envelope{
+		Journal: sourceA,
+		Begin:   end - 1,
+		End:     end,
+		Message: &testMessage{Key: key, Value: value},
+	}
+}
+
+func watermarkCheckpoint(offset pb.Offset) pc.Checkpoint {
+	return pc.Checkpoint{
+		Sources: map[pb.Journal]pc.Checkpoint_Source{
+			sourceA.Name: {ReadThrough: offset},
+		},
+	}
+}
+
+// drainKeys pops all messages of the watermarkBuffer, returning their keys.
+func drainKeys(b *watermarkBuffer) []string {
+	var keys []string
+	for len(b.buffered) != 0 {
+		keys = append(keys, heap.Pop(&b.buffered).(bufferedMessage).env.Message.(*testMessage).Key)
+	}
+	return keys
+}
+
+// runOrderedTransaction publishes |msgs| in order within a transaction,
+// and waits for the Shard to read through its acknowledgements.
+func runOrderedTransaction(tf *testFixture, s Shard, msgs []testMessage) {
+	for i := range msgs {
+		var _, err = tf.pub.PublishUncommitted(toSourceA, &msgs[i])
+		require.NoError(tf.t, err)
+	}
+	var offsets = make(pb.Offsets)
+	for _, aa := range tf.writeTxnPubACKs() {
+		require.NoError(tf.t, aa.Err())
+		offsets[aa.Request().Journal] = aa.Response().Commit.End
+	}
+	var _, err = ShardStat(tf.tasks.Context(), tf.service, &pc.StatRequest{
+		Shard:       s.Spec().Id,
+		ReadThrough: offsets,
+	})
+	require.NoError(tf.t, err)
+}
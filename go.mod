@@ -35,8 +35,12 @@ require (
 	github.com/soheilhy/cmux v0.1.5
 	github.com/spf13/afero v1.6.0
 	github.com/stretchr/testify v1.8.1
+	go.etcd.io/bbolt v1.3.7
 	go.etcd.io/etcd/api/v3 v3.5.0
 	go.etcd.io/etcd/client/v3 v3.5.0
+	go.opentelemetry.io/otel v1.0.1
+	go.opentelemetry.io/otel/sdk v1.0.1
+	go.opentelemetry.io/otel/trace v1.0.1
 	golang.org/x/net v0.14.0
 	golang.org/x/oauth2 v0.11.0
 	golang.org/x/sync v0.3.0
@@ -0,0 +1,77 @@
+package keyspace
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"go.gazette.dev/core/etcdtest"
+	gc "gopkg.in/check.v1"
+)
+
+func BenchmarkLoad(b *testing.B) {
+	b.Run("full", func(b *testing.B) {
+		benchmarkLoad(b, nil)
+	})
+	b.Run("sub-prefixes", func(b *testing.B) {
+		benchmarkLoad(b, []string{"/items/", "/members/"})
+	})
+}
+
+type BenchmarkHealthSuite struct{}
+
+// TestBenchmarkHealth runs benchmarks with a small N to ensure they don't bit rot.
+func (s *BenchmarkHealthSuite) TestBenchmarkHealth(c *gc.C) {
+	var fakeB = testing.B{N: 1}
+
+	benchmarkLoad(&fakeB, nil)
+	benchmarkLoad(&fakeB, []string{"/items/", "/members/"})
+}
+
+var _ = gc.Suite(&BenchmarkHealthSuite{})
+
+// benchmarkLoad loads a KeySpace resembling that of an allocator, where
+// assignments far outnumber items and members, and reports the memory
+// allocated by each Load.
+func benchmarkLoad(b *testing.B, subPrefixes []string) {
+	var client = etcdtest.TestClient()
+	defer etcdtest.Cleanup()
+
+	var ctx = context.Background()
+	const (
+		NMembers     = 10
+		NItems       = 1000
+		NAssignments = NItems * 3
+	)
+
+	var fill = func(n int, key func(i int) string) {
+		var ops []clientv3.Op
+		for i := 0; i != n; i++ {
+			if ops = append(ops, clientv3.OpPut(key(i), "1")); len(ops) == 100 || i == n-1 {
+				if _, err := client.Txn(ctx).Then(ops...).Commit(); err != nil {
+					b.Fatal(err)
+				}
+				ops = ops[:0]
+			}
+		}
+	}
+	fill(NMembers, func(i int) string { return fmt.Sprintf("/root/members/member-%03d", i) })
+	fill(NItems, func(i int) string { return fmt.Sprintf("/root/items/item-%05d", i) })
+	fill(NAssignments, func(i int) string {
+		return fmt.Sprintf("/root/assign/item-%05d#member-%03d#%d", i/3, i%NMembers, i%3)
+	})
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i != b.N; i++ {
+		var ks = NewKeySpace("/root", testDecoder)
+		ks.SubPrefixes = subPrefixes
+
+		if err := ks.Load(ctx, client, 0); err != nil {
+			b.Fatal(err)
+		}
+		b.ReportMetric(float64(len(ks.KeyValues)), "keys/op")
+	}
+}
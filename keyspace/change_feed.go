@@ -0,0 +1,369 @@
+package keyspace
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"go.etcd.io/etcd/api/v3/etcdserverpb"
+	"go.etcd.io/etcd/api/v3/mvccpb"
+	"go.etcd.io/etcd/api/v3/v3rpc/rpctypes"
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+// Change is a put or deletion of a key of the KeySpace.
+type Change struct {
+	// Type of the Change: mvccpb.PUT or mvccpb.DELETE.
+	Type mvccpb.Event_EventType
+	// Key which changed.
+	Key string
+	// Revision of the Change. For a put, it's the ModRevision of the New value.
+	Revision int64
+	// Old value of the key, or nil if the key didn't exist.
+	Old *KeyValue
+	// New value of the key, or nil if the key was deleted.
+	New *KeyValue
+}
+
+// ChangeFeed is a stream of the Changes of a KeySpace, in revision order.
+// Changes are those of the KeySpace itself, rather than of Etcd: a value which
+// fails to decode and is skipped (see DecodeErrorSkip) isn't a Change, and
+// Changes of a KeySpace having SubPrefixes are only of keys under them.
+// Changes are queued until read by Next, and a ChangeFeed which is no longer
+// read must be closed. Should more than KeySpace.ChangeFeedLimit Changes be
+// queued, the ChangeFeed is overflowed and its queued Changes are discarded.
+type ChangeFeed struct {
+	// Revision of the KeySpace at which the ChangeFeed began. Changes of the
+	// ChangeFeed are of revisions after Revision, or if the ChangeFeed began
+	// with a snapshot, also puts of current keys at or before Revision.
+	Revision int64
+
+	ks       *KeySpace
+	mu       sync.Mutex
+	changes  []Change      // Queued Changes, guarded by |mu|.
+	unread   int           // Published Changes not yet read, guarded by |mu|.
+	overflow bool          // Did |unread| exceed the ChangeFeedLimit? Guarded by |mu|.
+	readyCh  chan struct{} // Signalled when |changes| become non-empty.
+}
+
+// ErrChangeFeedOverflow is returned by ChangeFeed.Next if more than the
+// KeySpace's ChangeFeedLimit Changes were queued to the ChangeFeed.
+var ErrChangeFeedOverflow = errors.New("ChangeFeed overflowed its queue of unread Changes")
+
+// ErrChangeHistoryUnavailable is returned by NewChangeFeedFrom if Changes of
+// the requested revision aren't retained by the KeySpace.
+var ErrChangeHistoryUnavailable = errors.New("Changes of the revision aren't retained")
+
+// NewChangeFeed returns a ChangeFeed of the KeySpace. If |snapshot|, the feed
+// begins with a put of each current key, ordered on its ModRevision, and
+// ChangeFeed readers may reconstruct the KeySpace from its Changes alone.
+// Otherwise, the feed begins with Changes after the current revision.
+//
+// Changes are published to a ChangeFeed under the same write lock of the
+// KeySpace which applies them, and a ChangeFeed therefore misses no Changes
+// between its beginning and the KeySpace's subsequent Load or Watch. A Load
+// publishes the difference of the loaded and prior key/values, which for a
+// new KeySpace is a put of each loaded key.
+//
+// NewChangeFeed locks KeySpace.Mu, which must not be held by the caller.
+func (ks *KeySpace) NewChangeFeed(snapshot bool) *ChangeFeed {
+	ks.Mu.Lock()
+	defer ks.Mu.Unlock()
+
+	var f = ks.newChangeFeed()
+	if snapshot {
+		// The snapshot is queued without regard to the ChangeFeedLimit.
+		f.changes = diffKeyValues(nil, ks.KeyValues, ks.Header.Revision)
+		f.readyCh <- struct{}{}
+	}
+	return f
+}
+
+// NewChangeFeedFrom returns a ChangeFeed of the KeySpace which resumes from
+// |revision|, through which the caller is already current. It also returns
+// the retained Changes of revisions after |revision| and through the
+// ChangeFeed's Revision, which the ChangeFeed itself doesn't include. If the
+// KeySpace hasn't retained all such Changes (see ChangeHistory), or if
+// |revision| is after the current revision, ErrChangeHistoryUnavailable is
+// returned.
+//
+// NewChangeFeedFrom locks KeySpace.Mu, which must not be held by the caller.
+func (ks *KeySpace) NewChangeFeedFrom(revision int64) (*ChangeFeed, []Change, error) {
+	ks.Mu.Lock()
+	defer ks.Mu.Unlock()
+
+	if revision < ks.historyAt || revision > ks.Header.Revision {
+		return nil, nil, fmt.Errorf("%w (revision %d; retained revisions are %d through %d)",
+			ErrChangeHistoryUnavailable, revision, ks.historyAt, ks.Header.Revision)
+	}
+	var ind = sort.Search(len(ks.history), func(i int) bool {
+		return ks.history[i].Revision > revision
+	})
+	var changes = append([]Change(nil), ks.history[ind:]...)
+
+	return ks.newChangeFeed(), changes, nil
+}
+
+// newChangeFeed returns a new ChangeFeed which is added to the KeySpace.
+// KeySpace.Mu must be write-locked.
+func (ks *KeySpace) newChangeFeed() *ChangeFeed {
+	var f = &ChangeFeed{
+		Revision: ks.Header.Revision,
+		ks:       ks,
+		readyCh:  make(chan struct{}, 1),
+	}
+	ks.feeds = append(ks.feeds, f)
+	return f
+}
+
+// Next returns the next Changes of the ChangeFeed in revision order, blocking
+// until at least one Change is available or the Context is done. If the
+// ChangeFeed overflowed, Next returns ErrChangeFeedOverflow and the ChangeFeed
+// receives no further Changes.
+func (f *ChangeFeed) Next(ctx context.Context) ([]Change, error) {
+	for {
+		f.mu.Lock()
+		var out, overflow = f.changes, f.overflow
+		f.changes, f.unread = nil, 0
+		f.mu.Unlock()
+
+		if overflow {
+			return nil, ErrChangeFeedOverflow
+		} else if len(out) != 0 {
+			return out, nil
+		}
+		select {
+		case <-f.readyCh:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// Close the ChangeFeed, which receives no further Changes.
+// Close locks KeySpace.Mu, which must not be held by the caller.
+func (f *ChangeFeed) Close() {
+	f.ks.Mu.Lock()
+	defer f.ks.Mu.Unlock()
+
+	for i, o := range f.ks.feeds {
+		if o == f {
+			f.ks.feeds = append(f.ks.feeds[:i], f.ks.feeds[i+1:]...)
+			break
+		}
+	}
+}
+
+// publish queues |changes| to the ChangeFeed, or overflows the ChangeFeed
+// if its unread Changes would exceed |limit| (where zero is unbounded).
+func (f *ChangeFeed) publish(changes []Change, limit int) {
+	if len(changes) == 0 {
+		return
+	}
+	f.mu.Lock()
+	if f.overflow {
+		// Pass.
+	} else if f.unread += len(changes); limit != 0 && f.unread > limit {
+		f.changes, f.overflow = nil, true
+	} else {
+		f.changes = append(f.changes, changes...)
+	}
+	f.mu.Unlock()
+
+	select {
+	case f.readyCh <- struct{}{}:
+	default: // Already signalled.
+	}
+}
+
+// publishChanges publishes |changes| to each ChangeFeed of the KeySpace.
+// KeySpace.Mu must be write-locked.
+func (ks *KeySpace) publishChanges(changes []Change) {
+	for _, f := range ks.feeds {
+		f.publish(changes, ks.ChangeFeedLimit)
+	}
+}
+
+// retainChanges adds |changes| to the retained history of the KeySpace,
+// discarding the Changes of its oldest revisions in excess of ChangeHistory.
+// KeySpace.Mu must be write-locked.
+func (ks *KeySpace) retainChanges(changes []Change) {
+	ks.history = append(ks.history, changes...)
+
+	var drop int
+	for len(ks.history)-drop > ks.ChangeHistory {
+		// Drop all Changes of the oldest retained revision.
+		var rev = ks.history[drop].Revision
+		for drop != len(ks.history) && ks.history[drop].Revision == rev {
+			drop++
+		}
+		ks.historyAt = rev
+	}
+	if drop != 0 {
+		ks.history = append(ks.history[:0], ks.history[drop:]...)
+	}
+}
+
+// Delta returns the Changes which transform the KeySpace at revision |from|
+// into the KeySpace at revision |to|, ordered on revision, for diagnosing
+// what changed in a window of time. Like the Changes of a ChangeFeed, they're
+// of the KeySpace rather than of Etcd, and are the net difference of the two
+// revisions: a key which was put more than once between them has a single
+// Change, and a key which was created and then deleted has none.
+//
+// If the KeySpace retains the Changes of all revisions after |from| through
+// |to| (see ChangeHistory), the Delta is built from them. Otherwise
+// key/values of each revision are read from Etcd, except that the KeySpace's
+// own KeyValues are used if |to| is its current revision. Delta then fails if
+// a revision has been compacted by Etcd, with an error which wraps
+// rpctypes.ErrCompacted. Delta doesn't modify the KeySpace.
+//
+// Delta read-locks KeySpace.Mu, which must not be held by the caller.
+func (ks *KeySpace) Delta(ctx context.Context, client clientv3.KV, from, to int64) ([]Change, error) {
+	if from <= 0 || to < from {
+		return nil, fmt.Errorf("invalid revisions (from %d, to %d; expected 0 < from <= to)", from, to)
+	}
+
+	ks.Mu.RLock()
+	if from >= ks.historyAt && to <= ks.Header.Revision {
+		var changes = netChanges(ks.history, from, to)
+		ks.Mu.RUnlock()
+		return changes, nil
+	}
+	ks.Mu.RUnlock()
+
+	var load = func(rev int64) (KeyValues, error) {
+		ks.Mu.RLock()
+		if ks.Header.Revision == rev {
+			var kvs = append(KeyValues(nil), ks.KeyValues...)
+			ks.Mu.RUnlock()
+			return kvs, nil
+		}
+		ks.Mu.RUnlock()
+
+		var kvs, err = ks.loadKeyValues(ctx, client, rev, new(etcdserverpb.ResponseHeader), nil)
+		if err == rpctypes.ErrCompacted {
+			return nil, fmt.Errorf("revision %d has been compacted: %w", rev, err)
+		} else if err == rpctypes.ErrFutureRev {
+			return nil, fmt.Errorf("revision %d is after the current Etcd revision: %w", rev, err)
+		}
+		return kvs, err
+	}
+
+	var fromKVs, toKVs KeyValues
+	var err error
+
+	if fromKVs, err = load(from); err != nil {
+		return nil, err
+	} else if toKVs, err = load(to); err != nil {
+		return nil, err
+	}
+	return diffKeyValues(fromKVs, toKVs, to), nil
+}
+
+// netChanges returns the net Changes of |history| having revisions after
+// |from| and through |to|, which are equivalent to the diffKeyValues of the
+// KeyValues at each revision.
+func netChanges(history []Change, from, to int64) []Change {
+	var begin = sort.Search(len(history), func(i int) bool { return history[i].Revision > from })
+	var end = sort.Search(len(history), func(i int) bool { return history[i].Revision > to })
+
+	// Fold Changes of each key into one, from its first Old to its last New.
+	var index = make(map[string]int)
+	var out []Change
+
+	for _, c := range history[begin:end] {
+		if i, ok := index[c.Key]; ok {
+			out[i].New = c.New
+		} else {
+			index[c.Key] = len(out)
+			out = append(out, Change{Key: c.Key, Old: c.Old, New: c.New})
+		}
+	}
+
+	var n int
+	for _, c := range out {
+		if c.Old == nil && c.New == nil {
+			continue // Created and then deleted.
+		} else if c.New == nil {
+			c.Type, c.Revision = mvccpb.DELETE, to
+		} else {
+			c.Type, c.Revision = mvccpb.PUT, c.New.Raw.ModRevision
+		}
+		out[n] = c
+		n++
+	}
+	out = out[:n]
+
+	sort.Slice(out, func(i, j int) bool { return out[i].Key < out[j].Key })
+	sortChanges(out)
+	return out
+}
+
+// tailChange returns the Change of the tail of KeyValues |next| which was
+// made by applying an event of |key| at |revision|, where |prior| is the
+// value of the key before the event (if any), and |length| is the length of
+// |next| before the event. It returns false if the event made no Change,
+// as happens if the event's value failed to decode and was skipped.
+func tailChange(next KeyValues, key []byte, revision int64, prior *KeyValue, length int) (Change, bool) {
+	if len(next) < length {
+		return Change{Type: mvccpb.DELETE, Key: string(key), Revision: revision, Old: prior}, true
+	}
+	if len(next) == 0 {
+		return Change{}, false
+	}
+	var kv = next[len(next)-1]
+
+	if !bytes.Equal(kv.Raw.Key, key) || kv.Raw.ModRevision != revision {
+		return Change{}, false
+	}
+	return Change{Type: mvccpb.PUT, Key: string(key), Revision: revision, Old: prior, New: &kv}, true
+}
+
+// diffKeyValues returns the Changes which transform KeyValues |from| into
+// |to|, ordered on revision. Keys present only in |to|, or having a different
+// ModRevision, are puts. Keys present only in |from| are deleted at |revision|.
+func diffKeyValues(from, to KeyValues, revision int64) []Change {
+	var out []Change
+
+	for len(from) != 0 || len(to) != 0 {
+		var cmp int
+		if len(from) == 0 {
+			cmp = 1
+		} else if len(to) == 0 {
+			cmp = -1
+		} else {
+			cmp = bytes.Compare(from[0].Raw.Key, to[0].Raw.Key)
+		}
+
+		switch {
+		case cmp < 0:
+			var old = from[0]
+			out = append(out, Change{Type: mvccpb.DELETE, Key: string(old.Raw.Key), Revision: revision, Old: &old})
+			from = from[1:]
+		case cmp > 0:
+			var kv = to[0]
+			out = append(out, Change{Type: mvccpb.PUT, Key: string(kv.Raw.Key), Revision: kv.Raw.ModRevision, New: &kv})
+			to = to[1:]
+		default:
+			if from[0].Raw.ModRevision != to[0].Raw.ModRevision {
+				var old, kv = from[0], to[0]
+				out = append(out, Change{Type: mvccpb.PUT, Key: string(kv.Raw.Key), Revision: kv.Raw.ModRevision, Old: &old, New: &kv})
+			}
+			from, to = from[1:], to[1:]
+		}
+	}
+	sortChanges(out)
+	return out
+}
+
+// sortChanges orders |changes| on revision, preserving the key order of
+// Changes having the same revision.
+func sortChanges(changes []Change) {
+	sort.SliceStable(changes, func(i, j int) bool {
+		return changes[i].Revision < changes[j].Revision
+	})
+}
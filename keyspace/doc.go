@@ -11,9 +11,25 @@
 // KeySpace.Observers, then any reader which properly synchronizes over
 // KeySpace.Mu is guaranteed to see values of `foo` which reflect the current
 // KeySpace state. Formally, readers are assured atomicity of a combined
-// update to the KeySpace and the derived value.
+// update to the KeySpace and the derived value. Observers registered with
+// KeySpace.AddObserver are named and prioritized, which orders their calls.
 //
 // KeySpace scales efficiently to Watches over 100's of thousands of keys by
 // amortizing updates with a short Nagle-like delay, while providing fast range
 // and point queries powered by its packed, sorted ordering.
 package keyspace
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var keySpaceDecodeErrorsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "gazette_keyspace_decode_errors_total",
+	Help: "Cumulative number of key/values which failed to decode, by KeySpace root.",
+}, []string{"root"})
+
+var keySpaceWatchBacklog = promauto.NewGaugeVec(prometheus.GaugeOpts{
+	Name: "gazette_keyspace_watch_backlog_events",
+	Help: "Number of watch events queued for application to the KeySpace, by KeySpace root.",
+}, []string{"root"})
@@ -4,17 +4,20 @@ import (
 	"bytes"
 	"container/heap"
 	"context"
+	"errors"
 	"fmt"
 	"path"
+	"runtime/debug"
 	"sort"
+	"strings"
 	"sync"
 	"time"
 
 	log "github.com/sirupsen/logrus"
 	"go.etcd.io/etcd/api/v3/etcdserverpb"
+	"go.etcd.io/etcd/api/v3/mvccpb"
 	"go.etcd.io/etcd/api/v3/v3rpc/rpctypes"
 	clientv3 "go.etcd.io/etcd/client/v3"
-	"go.etcd.io/etcd/client/v3/mirror"
 )
 
 // A KeySpace is a local mirror of a decoded portion of the Etcd key/value space,
@@ -29,7 +32,8 @@ type KeySpace struct {
 	// change the KeySpace, such as progress notifications. This property means, for
 	// all watched KeySpaces having a common Root, their revisions are always directly
 	// comparable and will be equal if (and only if) they reflect the same keys and
-	// values.
+	// values. If SubPrefixes are used, this holds only for KeySpaces having the
+	// same SubPrefixes.
 	Header etcdserverpb.ResponseHeader
 	// KeyValues is a complete and decoded mirror of the (prefixed) Etcd key/value space.
 	KeyValues
@@ -40,20 +44,85 @@ type KeySpace struct {
 	// deriving themselves from the KeySpace: any mutations performed by Observers
 	// will appear synchronously with changes to the KeySpace itself, from the
 	// perspective of a client appropriately utilizing a read-lock.
+	//
+	// Observers are called with priority zero: after named Observers of negative
+	// priority, and before named Observers of zero or positive priority
+	// (see AddObserver). Panics of Observers are recovered and logged.
 	Observers []func()
 	// WatchApplyDelay is the duration for which KeySpace should allow Etcd
 	// WatchResponses to queue before applying all responses to the KeySpace.
 	// This Nagle-like mechanism amortizes the cost of applying many
 	// WatchResponses arriving in close succession. Default is 30ms.
 	WatchApplyDelay time.Duration
+	// WatchApplyRate optionally limits the rate, in events per second, at
+	// which Watch applies events to the KeySpace. Where WatchApplyDelay
+	// coalesces events arriving in close succession, WatchApplyRate smooths
+	// a burst of events into a series of smaller updates spread over time,
+	// trading latency of the burst's application for more even CPU use and
+	// shorter holds of the KeySpace write lock by Observers. WatchResponses
+	// are applied whole, and at least one is applied with each update.
+	// If zero, events are applied as quickly as they arrive.
+	WatchApplyRate int
+	// WatchMaxBacklog bounds the number of queued events under WatchApplyRate.
+	// Should the backlog exceed it, all queued events are applied without
+	// delay so that the KeySpace cannot fall behind Etcd indefinitely. If zero,
+	// the backlog is bounded to one second of events (WatchApplyRate).
+	WatchMaxBacklog int
+	// SubPrefixes optionally restricts the KeySpace to keys under Root having one
+	// of the given prefixes, relative to Root (eg, "/members/"). Load and Watch
+	// then fetch only matching keys, and other keys under Root are never retrieved
+	// from Etcd. This reduces memory and Etcd transfer where a process requires
+	// only a portion of a large key space. SubPrefixes must be ordered and
+	// non-overlapping, and must be set before Load and Watch are called.
+	//
+	// A KeySpace having SubPrefixes is an incomplete mirror of Root, and it
+	// must not be used by components which expect a complete mirror (such as
+	// the Allocator, which would treat excluded keys as deleted).
+	SubPrefixes []string
+	// DecodeErrorHandler is optionally called with the key, raw value, and
+	// error of each key/value which fails to decode, and returns the
+	// DecodeErrorAction to take. If nil, DecodeErrorSkip is taken. It's called
+	// from Load and Watch, and must not lock the KeySpace Mutex.
+	DecodeErrorHandler func(key, value []byte, err error) DecodeErrorAction
+	// ChangeFeedLimit bounds the number of Changes queued by a ChangeFeed
+	// which isn't read. A ChangeFeed which exceeds it is overflowed, and its
+	// Next returns ErrChangeFeedOverflow. If zero, queues are unbounded.
+	// Default is 16,384.
+	ChangeFeedLimit int
+	// ChangeHistory is the number of recent Changes which are retained by the
+	// KeySpace, from which NewChangeFeedFrom may resume. Changes of a revision
+	// are retained or discarded together. If zero, no Changes are retained.
+	// Default is 1,024.
+	ChangeHistory int
+
 	// Mu guards Header, KeyValues, and Observers. It must be locked before any are accessed.
 	Mu sync.RWMutex
 
-	decode   KeyValueDecoder // Client-provided KeySpace decoder.
-	next     KeyValues       // Reusable buffer for next, amortized KeyValues update.
-	updateCh chan struct{}   // Signals waiting goroutines of an update.
+	named     []namedObserver // Named Observers, ordered on ascending priority.
+	decode    KeyValueDecoder // Client-provided KeySpace decoder.
+	next      KeyValues       // Reusable buffer for next, amortized KeyValues update.
+	updateCh  chan struct{}   // Signals waiting goroutines of an update.
+	feeds     []*ChangeFeed   // ChangeFeeds of the KeySpace.
+	history   []Change        // Retained recent Changes.
+	historyAt int64           // Revision after which all Changes are in |history|.
 }
 
+// DecodeErrorAction is an action taken by a KeySpace upon a key/value which
+// fails to decode.
+type DecodeErrorAction int
+
+const (
+	// DecodeErrorSkip logs the error and skips the key/value. A key having a
+	// prior decoded value retains it, and a new key is omitted from the KeySpace.
+	DecodeErrorSkip DecodeErrorAction = iota
+	// DecodeErrorRemove logs the error and removes the key from the KeySpace.
+	// A key having a prior decoded value is removed as if it were deleted, and
+	// a new key is omitted. KeyValues never have a nil Decoded value.
+	DecodeErrorRemove
+	// DecodeErrorHalt fails the Load or Watch with the decode error.
+	DecodeErrorHalt
+)
+
 // NewKeySpace returns a KeySpace with the configured key |prefix| and |decoder|.
 // |prefix| must be a "Clean" path, as defined by path.Clean, or NewKeySpace panics.
 // This check limits the space of possible prefixes somewhat, but guards against
@@ -65,15 +134,46 @@ func NewKeySpace(prefix string, decoder KeyValueDecoder) *KeySpace {
 	var ks = &KeySpace{
 		Root:            prefix,
 		WatchApplyDelay: 30 * time.Millisecond,
+		ChangeFeedLimit: 1 << 14,
+		ChangeHistory:   1024,
 		decode:          decoder,
 		updateCh:        make(chan struct{}),
 	}
 	return ks
 }
 
+// AddObserver registers a named Observer of the KeySpace, which is called upon
+// each mutation of the KeySpace under the same terms as Observers. Observers
+// are called in ascending order of |priority|, and Observers of equal priority
+// are called in the order they were added. Unnamed Observers of the Observers
+// field have priority zero, and are ordered before named Observers of zero
+// priority.
+//
+// Should an Observer panic, the panic is recovered and logged with the
+// Observer's |name|, and remaining Observers are called as usual. Note an
+// Observer which panics may leave its derived state partially updated.
+//
+// AddObserver locks KeySpace.Mu, which must not be held by the caller.
+func (ks *KeySpace) AddObserver(name string, priority int, fn func()) {
+	ks.Mu.Lock()
+	defer ks.Mu.Unlock()
+
+	var ind = sort.Search(len(ks.named), func(i int) bool {
+		return ks.named[i].priority > priority
+	})
+	ks.named = append(ks.named, namedObserver{})
+	copy(ks.named[ind+1:], ks.named[ind:])
+	ks.named[ind] = namedObserver{name: name, priority: priority, fn: fn}
+}
+
 // Load loads a snapshot of the prefixed KeySpace at revision |rev|,
-// or if |rev| is zero, at the current revision.
-func (ks *KeySpace) Load(ctx context.Context, client *clientv3.Client, rev int64) error {
+// or if |rev| is zero, at the current revision. The KV is typically a
+// *clientv3.Client, but may be any implementation (such as a decorator which
+// adds instrumentation, or a fake).
+func (ks *KeySpace) Load(ctx context.Context, client clientv3.KV, rev int64) error {
+	if err := validateSubPrefixes(ks.SubPrefixes); err != nil {
+		return err
+	}
 	if rev == 0 {
 		// Resolve a current Revision. Note |rev| of zero is also interpreted by
 		// SyncBase as "use a recent revision", which we would use instead except
@@ -87,33 +187,17 @@ func (ks *KeySpace) Load(ctx context.Context, client *clientv3.Client, rev int64
 	defer ks.Mu.Unlock()
 	ks.Mu.Lock()
 
+	// If ChangeFeeds must be published the difference of this Load,
+	// retain the prior KeyValues rather than re-using its buffer.
+	var prior KeyValues
+	if len(ks.feeds) != 0 {
+		prior = append(prior, ks.KeyValues...)
+	}
 	ks.Header, ks.KeyValues = etcdserverpb.ResponseHeader{}, ks.KeyValues[:0]
-	var respCh, errCh = mirror.NewSyncer(client, ks.Root, rev).SyncBase(ctx)
 
-	// Read messages across |respCh| and |errCh| until both are closed.
-	for respCh != nil || errCh != nil {
-		select {
-		case resp, ok := <-respCh:
-			if !ok {
-				respCh = nil // Finished draining |respCh|.
-			} else if err := checkHeader(&ks.Header, *resp.Header); err != nil {
-				return err
-			} else {
-				ks.Header = *resp.Header
-				for _, kv := range resp.Kvs {
-					if ks.KeyValues, err = appendKeyValue(ks.KeyValues, ks.decode, kv); err != nil {
-						log.WithFields(log.Fields{"key": string(kv.Key), "err": err}).
-							Error("key/value decode failed while loading")
-					}
-				}
-			}
-		case err, ok := <-errCh:
-			if !ok {
-				errCh = nil // Finished draining |errCh|.
-			} else {
-				return err
-			}
-		}
+	var err error
+	if ks.KeyValues, err = ks.loadKeyValues(ctx, client, rev, &ks.Header, ks.KeyValues); err != nil {
+		return err
 	}
 	// Etcd defines `ResponseHeader.Revision` to be the store revision when the
 	// request was applied (and importantly, not of the revision of the request).
@@ -121,12 +205,69 @@ func (ks *KeySpace) Load(ctx context.Context, client *clientv3.Client, rev int64
 	// maintain our Header as the effective Revision of the KeySpace.
 	ks.Header.Revision = rev
 
+	if len(ks.feeds) != 0 {
+		ks.publishChanges(diffKeyValues(prior, ks.KeyValues, rev))
+	}
+	// Changes between the prior and loaded revisions are unknown.
+	ks.history, ks.historyAt = ks.history[:0], rev
+
 	ks.onUpdate()
 	return nil
 }
 
+// loadKeyValues appends the decoded key/values of the KeySpace's prefixes at
+// |rev| to |kvs|, and updates |header| with each response header.
+func (ks *KeySpace) loadKeyValues(ctx context.Context, client clientv3.KV, rev int64,
+	header *etcdserverpb.ResponseHeader, kvs KeyValues) (KeyValues, error) {
+
+	// Prefixes are ordered and non-overlapping, so each successive prefix
+	// sync appends keys in order.
+	for _, prefix := range ks.watchPrefixes() {
+		var key, opts = prefix, []clientv3.OpOption{clientv3.WithLimit(loadBatchLimit), clientv3.WithRev(rev)}
+
+		if prefix == "" {
+			// Load the entire key-value space, from the smallest key onwards.
+			key, opts = "\x00", append(opts, clientv3.WithFromKey())
+		} else {
+			opts = append(opts, clientv3.WithRange(clientv3.GetPrefixRangeEnd(prefix)))
+		}
+
+		// Read pages of the prefix until none remain.
+		for {
+			var resp, err = client.Get(ctx, key, opts...)
+			if err != nil {
+				return kvs, err
+			} else if err = checkHeader(header, *resp.Header); err != nil {
+				return kvs, err
+			}
+			*header = *resp.Header
+
+			for _, kv := range resp.Kvs {
+				if kvs, err = appendKeyValue(kvs, ks.handleDecode, kv); err != nil {
+					if halt, ok := err.(decodeHaltError); ok {
+						return kvs, halt.err
+					} else if errors.Is(err, ErrUnknownKey) {
+						continue
+					}
+					log.WithFields(log.Fields{"key": string(kv.Key), "err": err}).
+						Error("key/value decode failed while loading")
+				}
+			}
+			if !resp.More {
+				break
+			}
+			// Continue from the key which immediately follows the last one read.
+			key = string(append(resp.Kvs[len(resp.Kvs)-1].Key, 0))
+		}
+	}
+	return kvs, nil
+}
+
 // Watch a loaded KeySpace and apply updates as they are received.
 func (ks *KeySpace) Watch(ctx context.Context, client clientv3.Watcher) error {
+	if err := validateSubPrefixes(ks.SubPrefixes); err != nil {
+		return err
+	}
 	var watchCh clientv3.WatchChan
 
 	// WatchResponses can often arrive in quick succession and contain many key
@@ -146,6 +287,11 @@ func (ks *KeySpace) Watch(ctx context.Context, client clientv3.Watcher) error {
 	var resumeRevision = ks.Header.Revision + 1
 	ks.Mu.RUnlock()
 
+	// Number of events of queued |responses|.
+	var backlog int
+	var backlogGauge = keySpaceWatchBacklog.WithLabelValues(ks.Root)
+	defer backlogGauge.Set(0)
+
 	for attempt := 0; true; attempt++ {
 		// Start (or restart) a new long-lived Watch. Note this is very similar to
 		// mirror.Syncer: A key difference (and the reason that API is not used) is
@@ -164,7 +310,14 @@ func (ks *KeySpace) Watch(ctx context.Context, client clientv3.Watcher) error {
 		// keep our lease alive. Specifically this can lead to our being allocator
 		// leader but being unable to observe updates.
 
-		if watchCh == nil {
+		//
+		// If SubPrefixes are used, a Watch of each prefix is merged into a
+		// single WatchChan which preserves this revision ordering.
+
+		if watchCh == nil && len(ks.SubPrefixes) != 0 {
+			watchCh = watchMerged(clientv3.WithRequireLeader(ctx), client,
+				ks.watchPrefixes(), resumeRevision)
+		} else if watchCh == nil {
 			watchCh = client.Watch(clientv3.WithRequireLeader(ctx), ks.Root,
 				clientv3.WithPrefix(),
 				clientv3.WithProgressNotify(),
@@ -211,6 +364,8 @@ func (ks *KeySpace) Watch(ctx context.Context, client clientv3.Watcher) error {
 					applyTimer.Reset(ks.WatchApplyDelay)
 				}
 				responses = append(responses, resp)
+				backlog += len(resp.Events)
+				backlogGauge.Set(float64(backlog))
 				attempt = 0 // Restart sequence.
 			} else if resp.IsProgressNotify() {
 				log.WithFields(log.Fields{
@@ -229,12 +384,23 @@ func (ks *KeySpace) Watch(ctx context.Context, client clientv3.Watcher) error {
 				}).Warn("ignoring unexpected empty watch response")
 			}
 		case <-applyTimer.C:
-			// Apply all previously buffered WatchResponses. |applyTimer| is now
-			// idle and will remain so until the next response is received.
-			if err := ks.Apply(responses...); err != nil {
+			// Apply previously buffered WatchResponses: all of them, or if
+			// throttled, a leading portion which is paced by WatchApplyRate.
+			var n, events, delay = ks.throttledBatch(responses, backlog)
+
+			if err := ks.Apply(responses[:n]...); err != nil {
 				return err
 			}
-			responses = responses[:0]
+			responses = append(responses[:0], responses[n:]...)
+			backlog -= events
+			backlogGauge.Set(float64(backlog))
+
+			// If responses remain, pace the next application. Otherwise
+			// |applyTimer| is now idle, and will remain so until the next
+			// response is received.
+			if len(responses) != 0 {
+				applyTimer.Reset(delay)
+			}
 		}
 	}
 	panic("not reached")
@@ -250,6 +416,29 @@ func (ks *KeySpace) Update() <-chan struct{} {
 	return ks.updateCh
 }
 
+// throttledBatch returns the number of leading |responses| to apply next,
+// their number of events, and the delay before the application of any further
+// responses under WatchApplyRate. |backlog| is the total events of |responses|.
+func (ks *KeySpace) throttledBatch(responses []clientv3.WatchResponse, backlog int) (n, events int, delay time.Duration) {
+	var maxBacklog = ks.WatchMaxBacklog
+	if maxBacklog == 0 {
+		maxBacklog = ks.WatchApplyRate
+	}
+	if ks.WatchApplyRate <= 0 || backlog > maxBacklog {
+		return len(responses), backlog, 0
+	}
+
+	// Apply events which the rate permits over an interval of WatchApplyDelay
+	// (but at least one response), and then delay for their pacing.
+	var budget = int(float64(ks.WatchApplyRate) * ks.WatchApplyDelay.Seconds())
+	for n == 0 || (n != len(responses) && events+len(responses[n].Events) <= budget) {
+		events += len(responses[n].Events)
+		n++
+	}
+	delay = time.Duration(float64(events) / float64(ks.WatchApplyRate) * float64(time.Second))
+	return n, events, delay
+}
+
 // WaitForRevision blocks until the KeySpace Revision is at least |revision|,
 // or until the context is done. A read lock of the KeySpace Mutex must be
 // held at invocation, and will be re-acquired before WaitForRevision returns.
@@ -322,6 +511,9 @@ func (ks *KeySpace) Apply(responses ...clientv3.WatchResponse) error {
 	// key space. Unmodified runs of keys are copied from |current|, with
 	// Watch Events applied as they are encountered and in (Key, ModRevision) order.
 	var current, next = ks.KeyValues, ks.next
+	// Changes of applied Events are collected even if there are no ChangeFeeds,
+	// as a ChangeFeed may begin before the critical section below.
+	var changes []Change
 
 	for responseHeap.Len() != 0 {
 		if wr = heap.Pop(&responseHeap).(clientv3.WatchResponse); len(wr.Events) == 0 {
@@ -338,11 +530,29 @@ func (ks *KeySpace) Apply(responses ...clientv3.WatchResponse) error {
 		}
 		next, current = append(next, current[:ind]...), current[ind:]
 
+		// Retain the prior value of this key, if any, for its Change.
+		var prior *KeyValue
+		if l := len(next); l != 0 && bytes.Equal(next[l-1].Raw.Key, wr.Events[0].Kv.Key) {
+			var kv = next[l-1]
+			prior = &kv
+		}
+		var length = len(next)
+
 		// Patch the tail of |next|, inserting, modifying, or deleting at the last element.
 		var err error
-		if next, err = updateKeyValuesTail(next, ks.decode, *wr.Events[0]); err != nil {
-			log.WithFields(log.Fields{"err": err, "event": wr.Events[0].Kv.String()}).
-				Error("inconsistent watched key/value event")
+		if next, err = updateKeyValuesTail(next, ks.handleDecode, *wr.Events[0]); err != nil {
+			if halt, ok := err.(decodeHaltError); ok {
+				return halt.err
+			} else if _, ok = err.(decodeRemoveError); ok && len(next) == length && prior != nil {
+				next = next[:length-1] // Remove the prior value of the key.
+			}
+			if !ks.isUnknownKey(err, wr.Events[0].Kv) {
+				log.WithFields(log.Fields{"err": err, "event": wr.Events[0].Kv.String()}).
+					Error("inconsistent watched key/value event")
+			}
+		}
+		if change, ok := tailChange(next, wr.Events[0].Kv.Key, wr.Events[0].Kv.ModRevision, prior, length); ok {
+			changes = append(changes, change)
 		}
 
 		// Pop wr.Events[0], and re-order the next Event in the heap.
@@ -350,25 +560,116 @@ func (ks *KeySpace) Apply(responses ...clientv3.WatchResponse) error {
 		heap.Push(&responseHeap, wr)
 	}
 	next = append(next, current...) // Append any left-over elements in |current|.
+	sortChanges(changes)
 
-	// Critical section: update header, swap out rebuilt KeyValues, and notify observers.
+	// Critical section: update header, swap out rebuilt KeyValues, publish
+	// Changes, and notify observers.
 	ks.Mu.Lock()
 	ks.Header = nextHeader
 	ks.KeyValues, ks.next = next, ks.KeyValues[:0]
+	ks.publishChanges(changes)
+	ks.retainChanges(changes)
 	ks.onUpdate()
 	ks.Mu.Unlock()
 
 	return nil
 }
 
+// handleDecode decodes the KeyValue, and upon a decode error applies the
+// DecodeErrorAction of the DecodeErrorHandler. It's a KeyValueDecoder.
+func (ks *KeySpace) handleDecode(raw *mvccpb.KeyValue) (interface{}, error) {
+	var decoded, err = ks.decode(raw)
+	if err == nil || errors.Is(err, ErrUnknownKey) {
+		return decoded, err
+	}
+	keySpaceDecodeErrorsTotal.WithLabelValues(ks.Root).Inc()
+
+	var action = DecodeErrorSkip
+	if ks.DecodeErrorHandler != nil {
+		action = ks.DecodeErrorHandler(raw.Key, raw.Value, err)
+	}
+	switch action {
+	case DecodeErrorRemove:
+		return nil, decodeRemoveError{err: err}
+	case DecodeErrorHalt:
+		return nil, decodeHaltError{err: fmt.Errorf("decoding key %q: %w", raw.Key, err)}
+	default:
+		return nil, err
+	}
+}
+
+// isUnknownKey returns true if |err| of an update of |kv| is due to |kv| being
+// an unknown key (see ErrUnknownKey), which was never added to the KeySpace.
+func (ks *KeySpace) isUnknownKey(err error, kv *mvccpb.KeyValue) bool {
+	if err == errUnknownDeletion {
+		_, err = ks.decode(kv)
+	}
+	return errors.Is(err, ErrUnknownKey)
+}
+
+// decodeHaltError is a decode error for which DecodeErrorHalt was taken.
+type decodeHaltError struct{ err error }
+
+func (e decodeHaltError) Error() string { return e.err.Error() }
+
+// decodeRemoveError is a decode error for which DecodeErrorRemove was taken.
+type decodeRemoveError struct{ err error }
+
+func (e decodeRemoveError) Error() string { return e.err.Error() }
+
+// watchPrefixes returns the Etcd key prefixes which are loaded and watched.
+func (ks *KeySpace) watchPrefixes() []string {
+	if len(ks.SubPrefixes) == 0 {
+		return []string{ks.Root}
+	}
+	var out = make([]string, len(ks.SubPrefixes))
+	for i, p := range ks.SubPrefixes {
+		out[i] = strings.TrimSuffix(ks.Root, "/") + p
+	}
+	return out
+}
+
+// onUpdate calls Observers of the KeySpace, and signals its update.
 func (ks *KeySpace) onUpdate() {
-	for _, obv := range ks.Observers {
-		obv()
+	var i int
+	for ; i != len(ks.named) && ks.named[i].priority < 0; i++ {
+		callObserver(ks.named[i].fn, ks.named[i].name, 0)
+	}
+	for j, obv := range ks.Observers {
+		callObserver(obv, "", j)
+	}
+	for ; i != len(ks.named); i++ {
+		callObserver(ks.named[i].fn, ks.named[i].name, 0)
 	}
 	close(ks.updateCh)
 	ks.updateCh = make(chan struct{})
 }
 
+// namedObserver is an Observer registered via AddObserver.
+type namedObserver struct {
+	name     string
+	priority int
+	fn       func()
+}
+
+// callObserver calls the Observer |fn|, which is |name|d or is otherwise
+// Observers[|index|], recovering and logging a panic.
+func callObserver(fn func(), name string, index int) {
+	defer func() {
+		if r := recover(); r != nil {
+			if name == "" {
+				name = fmt.Sprintf("Observers[%d]", index)
+			}
+			log.WithFields(log.Fields{
+				"observer": name,
+				"panic":    r,
+				"stack":    string(debug.Stack()),
+			}).Error("KeySpace observer panicked")
+		}
+	}()
+	fn()
+}
+
 // checkHeader returns an error if the ClusterIds are not the same, or if the Revision of `update` is less than the
 // Revision of `h`.
 func checkHeader(h *etcdserverpb.ResponseHeader, update etcdserverpb.ResponseHeader) error {
@@ -430,3 +731,6 @@ func backoff(attempt int) time.Duration {
 		return 5 * time.Second
 	}
 }
+
+// loadBatchLimit is the maximum number of keys read by each Get of a Load.
+const loadBatchLimit = 1000
@@ -1,11 +1,20 @@
 package keyspace
 
 import (
+	"bytes"
 	"context"
+	"errors"
+	"fmt"
+	"strconv"
 	"testing"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	log "github.com/sirupsen/logrus"
+	logtest "github.com/sirupsen/logrus/hooks/test"
 	epb "go.etcd.io/etcd/api/v3/etcdserverpb"
+	"go.etcd.io/etcd/api/v3/mvccpb"
+	"go.etcd.io/etcd/api/v3/v3rpc/rpctypes"
 	clientv3 "go.etcd.io/etcd/client/v3"
 	"go.gazette.dev/core/etcdtest"
 	gc "gopkg.in/check.v1"
@@ -62,6 +71,420 @@ func (s *KeySpaceSuite) TestLoadAndWatch(c *gc.C) {
 		map[string]int{"/two": 2, "/three": 4, "/foo": 5, "/raced": 999})
 }
 
+func (s *KeySpaceSuite) TestLoadAndWatchSubPrefixes(c *gc.C) {
+	var client = etcdtest.TestClient()
+	var ctx, cancel = context.WithCancel(context.Background())
+
+	defer etcdtest.Cleanup()
+
+	for k, v := range map[string]string{
+		"/root/aaa/one":   "1",
+		"/root/bbb/two":   "2",
+		"/root/ccc/three": "3",
+		"/root/cccc/four": "4",
+		"/other/aaa/five": "5",
+	} {
+		var _, err = client.Put(ctx, k, v)
+		c.Assert(err, gc.IsNil)
+	}
+
+	var ks = NewKeySpace("/root", testDecoder)
+	ks.SubPrefixes = []string{"/aaa/", "/ccc/"}
+
+	// Expect that each Observer call reflects either both or neither of
+	// keys which are put in a single transaction.
+	var expectObserverCallCh = make(chan struct{}, 1)
+	ks.Observers = append(ks.Observers, func() {
+		var _, a = ks.Search("/root/aaa/txn")
+		var _, c2 = ks.Search("/root/ccc/txn")
+		c.Check(a, gc.Equals, c2)
+		expectObserverCallCh <- struct{}{}
+	})
+
+	c.Check(ks.Load(ctx, client, 0), gc.IsNil)
+	verifyDecodedKeyValues(c, ks.KeyValues,
+		map[string]int{"/root/aaa/one": 1, "/root/ccc/three": 3})
+	<-expectObserverCallCh
+
+	go func() {
+		for _, ops := range [][]clientv3.Op{
+			{clientv3.OpPut("/root/ccc/six", "6")},
+			{
+				clientv3.OpPut("/root/aaa/txn", "7"),
+				clientv3.OpPut("/root/bbb/txn", "8"),
+				clientv3.OpPut("/root/ccc/txn", "9"),
+			},
+			{clientv3.OpDelete("/root/aaa/one")},
+		} {
+			var _, err = client.Txn(ctx).Then(ops...).Commit()
+			c.Check(err, gc.IsNil)
+
+			<-expectObserverCallCh
+		}
+		// Updates of excluded keys aren't observed. Expect only the final put.
+		for _, op := range []clientv3.Op{
+			clientv3.OpPut("/root/bbb/two", "22"),
+			clientv3.OpPut("/root/cccc/four", "44"),
+			clientv3.OpPut("/root/ccc/three", "33"),
+		} {
+			var _, err = client.Do(ctx, op)
+			c.Check(err, gc.IsNil)
+		}
+		<-expectObserverCallCh
+		cancel()
+	}()
+
+	c.Check(ks.Watch(ctx, client), gc.Equals, context.Canceled)
+
+	verifyDecodedKeyValues(c, ks.KeyValues, map[string]int{
+		"/root/aaa/txn":   7,
+		"/root/ccc/six":   6,
+		"/root/ccc/three": 33,
+		"/root/ccc/txn":   9,
+	})
+
+	// SubPrefixes must be ordered and non-overlapping.
+	ks.SubPrefixes = []string{"/ccc/", "/aaa/"}
+	c.Check(ks.Load(ctx, client, 0), gc.ErrorMatches, `SubPrefixes are not ordered \("/aaa/" <= "/ccc/"\)`)
+	ks.SubPrefixes = []string{"/aaa", "/aaa/bbb"}
+	c.Check(ks.Watch(ctx, client), gc.ErrorMatches, `SubPrefixes overlap \("/aaa/bbb" is prefixed by "/aaa"\)`)
+}
+
+func (s *KeySpaceSuite) TestChangeFeed(c *gc.C) {
+	var client = etcdtest.TestClient()
+	var ctx, cancel = context.WithCancel(context.Background())
+
+	defer etcdtest.Cleanup()
+
+	_, err := client.Put(ctx, "/one", "1")
+	c.Assert(err, gc.IsNil)
+	_, err = client.Put(ctx, "/two", "2")
+	c.Assert(err, gc.IsNil)
+	_, err = client.Put(ctx, "/bad", "invalid value is not a change")
+	c.Assert(err, gc.IsNil)
+
+	var ks = NewKeySpace("/", testDecoder)
+
+	// A feed of a new KeySpace observes the puts of its Load.
+	var loaded = ks.NewChangeFeed(false)
+	c.Check(loaded.Revision, gc.Equals, int64(0))
+	c.Check(ks.Load(ctx, client, 0), gc.IsNil)
+
+	// Feeds which begin after Load either snapshot the KeySpace, or don't.
+	var snapshot, current = ks.NewChangeFeed(true), ks.NewChangeFeed(false)
+	defer loaded.Close()
+	defer snapshot.Close()
+	defer current.Close()
+
+	c.Check(current.Revision, gc.Equals, ks.Header.Revision)
+
+	var expectLoad = []string{"PUT /one <nil> 1", "PUT /two <nil> 2"}
+	c.Check(readChanges(c, loaded, 2), gc.DeepEquals, expectLoad)
+	c.Check(readChanges(c, snapshot, 2), gc.DeepEquals, expectLoad)
+
+	go func() {
+		for _, op := range []clientv3.Op{
+			clientv3.OpPut("/three", "3"),
+			clientv3.OpPut("/one", "11"),
+			clientv3.OpPut("/bad", "still invalid"),
+			clientv3.OpDelete("/two"),
+			clientv3.OpTxn(nil, []clientv3.Op{
+				clientv3.OpPut("/zzz", "4"),
+				clientv3.OpPut("/aaa", "5"),
+			}, nil),
+		} {
+			var _, err = client.Do(ctx, op)
+			c.Check(err, gc.IsNil)
+		}
+	}()
+	var watchErr = make(chan error)
+	go func() { watchErr <- ks.Watch(ctx, client) }()
+
+	// Each feed observes the same Changes of the Watch, in revision order
+	// and then key order, despite arbitrary batching of watch responses.
+	var expectWatch = []string{
+		"PUT /three <nil> 3",
+		"PUT /one 1 11",
+		"DELETE /two 2 <nil>",
+		"PUT /aaa <nil> 5",
+		"PUT /zzz <nil> 4",
+	}
+	for _, f := range []*ChangeFeed{loaded, snapshot, current} {
+		c.Check(readChanges(c, f, len(expectWatch)), gc.DeepEquals, expectWatch)
+	}
+	cancel()
+	c.Check(<-watchErr, gc.Equals, context.Canceled)
+
+	// A subsequent Load publishes its difference with the prior KeySpace.
+	_, err = client.Delete(context.Background(), "/three")
+	c.Assert(err, gc.IsNil)
+	_, err = client.Put(context.Background(), "/aaa", "55")
+	c.Assert(err, gc.IsNil)
+
+	c.Check(ks.Load(context.Background(), client, 0), gc.IsNil)
+	c.Check(readChanges(c, current, 2), gc.DeepEquals, []string{
+		"PUT /aaa 5 55",
+		"DELETE /three 3 <nil>",
+	})
+
+	// A closed feed receives no further Changes.
+	current.Close()
+	_, err = client.Put(context.Background(), "/one", "111")
+	c.Assert(err, gc.IsNil)
+	c.Check(ks.Load(context.Background(), client, 0), gc.IsNil)
+
+	var timeoutCtx, timeoutCancel = context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer timeoutCancel()
+
+	_, err = current.Next(timeoutCtx)
+	c.Check(err, gc.Equals, context.DeadlineExceeded)
+}
+
+func (s *KeySpaceSuite) TestChangeFeedHistoryAndOverflow(c *gc.C) {
+	var client = etcdtest.TestClient()
+	var ctx, cancel = context.WithCancel(context.Background())
+
+	defer etcdtest.Cleanup()
+
+	_, err := client.Put(ctx, "/one", "1")
+	c.Assert(err, gc.IsNil)
+
+	var ks = NewKeySpace("/", testDecoder)
+	ks.ChangeHistory, ks.ChangeFeedLimit = 2, 2
+	c.Check(ks.Load(ctx, client, 0), gc.IsNil)
+	var loaded = ks.Header.Revision
+
+	// A feed may resume from the loaded revision, but not before or after it.
+	feed, changes, err := ks.NewChangeFeedFrom(loaded)
+	c.Check(err, gc.IsNil)
+	c.Check(changes, gc.HasLen, 0)
+	feed.Close()
+
+	_, _, err = ks.NewChangeFeedFrom(loaded - 1)
+	c.Check(errors.Is(err, ErrChangeHistoryUnavailable), gc.Equals, true)
+	_, _, err = ks.NewChangeFeedFrom(loaded + 1)
+	c.Check(errors.Is(err, ErrChangeHistoryUnavailable), gc.Equals, true)
+
+	var unread = ks.NewChangeFeed(false)
+	defer unread.Close()
+
+	for _, op := range []clientv3.Op{
+		clientv3.OpPut("/two", "2"),
+		clientv3.OpPut("/one", "11"),
+		clientv3.OpTxn(nil, []clientv3.Op{
+			clientv3.OpPut("/three", "3"),
+			clientv3.OpPut("/four", "4"),
+		}, nil),
+	} {
+		var _, err = client.Do(ctx, op)
+		c.Check(err, gc.IsNil)
+	}
+	var watchErr = make(chan error)
+	go func() { watchErr <- ks.Watch(ctx, client) }()
+
+	ks.Mu.RLock()
+	c.Check(ks.WaitForRevision(ctx, loaded+3), gc.IsNil)
+	ks.Mu.RUnlock()
+
+	// Only Changes of the last revision are retained, as retaining those of
+	// the prior revision also would exceed the ChangeHistory.
+	feed, changes, err = ks.NewChangeFeedFrom(loaded + 2)
+	c.Check(err, gc.IsNil)
+	c.Check(changes, gc.HasLen, 2)
+	c.Check(changes[0].Key, gc.Equals, "/four")
+	c.Check(changes[1].Key, gc.Equals, "/three")
+	c.Check(feed.Revision, gc.Equals, loaded+3)
+	feed.Close()
+
+	_, _, err = ks.NewChangeFeedFrom(loaded + 1)
+	c.Check(err, gc.ErrorMatches, `Changes of the revision aren't retained \(revision \d+; retained revisions are \d+ through \d+\)`)
+
+	// The feed which wasn't read overflowed its ChangeFeedLimit.
+	_, err = unread.Next(ctx)
+	c.Check(err, gc.Equals, ErrChangeFeedOverflow)
+
+	cancel()
+	c.Check(<-watchErr, gc.Equals, context.Canceled)
+}
+
+func (s *KeySpaceSuite) TestDelta(c *gc.C) {
+	var client = etcdtest.TestClient()
+	var ctx = context.Background()
+
+	defer etcdtest.Cleanup()
+
+	var do = func(ops ...clientv3.Op) int64 {
+		var resp, err = client.Txn(ctx).Then(ops...).Commit()
+		c.Assert(err, gc.IsNil)
+		return resp.Header.Revision
+	}
+	var format = func(changes []Change) []string {
+		var out []string
+		for _, ch := range changes {
+			var old, new interface{}
+			if ch.Old != nil {
+				old = ch.Old.Decoded
+			}
+			if ch.New != nil {
+				new = ch.New.Decoded
+			}
+			out = append(out, fmt.Sprintf("%s %s %v %v", ch.Type, ch.Key, old, new))
+		}
+		return out
+	}
+
+	var rev1 = do(clientv3.OpPut("/one", "1"), clientv3.OpPut("/two", "2"), clientv3.OpPut("/three", "3"))
+	do(clientv3.OpPut("/one", "11"))
+	do(clientv3.OpPut("/one", "111"), clientv3.OpDelete("/two"))
+	do(clientv3.OpPut("/four", "4"), clientv3.OpPut("/bad", "invalid value is not a change"))
+	do(clientv3.OpPut("/five", "5"))
+	var rev2 = do(clientv3.OpDelete("/five"))
+
+	var ks = NewKeySpace("/", testDecoder)
+	c.Assert(ks.Load(ctx, client, rev2), gc.IsNil)
+
+	// Expect the net Changes between revisions, ordered on revision.
+	var changes, err = ks.Delta(ctx, client, rev1, rev2)
+	c.Check(err, gc.IsNil)
+	c.Check(format(changes), gc.DeepEquals, []string{
+		"PUT /one 1 111",
+		"PUT /four <nil> 4",
+		"DELETE /two 2 <nil>",
+	})
+	c.Check(changes[2].Revision, gc.Equals, rev2)
+
+	// Revisions need not be that of the KeySpace.
+	changes, err = ks.Delta(ctx, client, rev1, rev1+1)
+	c.Check(err, gc.IsNil)
+	c.Check(format(changes), gc.DeepEquals, []string{"PUT /one 1 11"})
+
+	changes, err = ks.Delta(ctx, client, rev2, rev2)
+	c.Check(err, gc.IsNil)
+	c.Check(changes, gc.HasLen, 0)
+
+	// The KeySpace is unmodified.
+	c.Check(ks.Header.Revision, gc.Equals, rev2)
+	c.Check(ks.KeyValues, gc.HasLen, 3)
+
+	// Case: invalid revisions.
+	_, err = ks.Delta(ctx, client, rev2, rev1)
+	c.Check(err, gc.ErrorMatches, `invalid revisions \(from \d+, to \d+; expected 0 < from <= to\)`)
+	_, err = ks.Delta(ctx, client, rev1, rev2+100)
+	c.Check(err, gc.ErrorMatches, `revision \d+ is after the current Etcd revision: .*`)
+
+	// Case: a compacted revision.
+	_, err = client.Compact(ctx, rev1+1)
+	c.Assert(err, gc.IsNil)
+
+	_, err = ks.Delta(ctx, client, rev1, rev2)
+	c.Check(err, gc.ErrorMatches, `revision \d+ has been compacted: .*`)
+	c.Check(errors.Is(err, rpctypes.ErrCompacted), gc.Equals, true)
+}
+
+func (s *KeySpaceSuite) TestDeltaFromHistory(c *gc.C) {
+	var client = etcdtest.TestClient()
+	var ctx, cancel = context.WithCancel(context.Background())
+
+	defer etcdtest.Cleanup()
+
+	var do = func(ops ...clientv3.Op) int64 {
+		var resp, err = client.Txn(ctx).Then(ops...).Commit()
+		c.Assert(err, gc.IsNil)
+		return resp.Header.Revision
+	}
+	var rev1 = do(clientv3.OpPut("/one", "1"), clientv3.OpPut("/two", "2"), clientv3.OpPut("/three", "3"))
+
+	var ks = NewKeySpace("/", testDecoder)
+	c.Assert(ks.Load(ctx, client, rev1), gc.IsNil)
+
+	do(clientv3.OpPut("/one", "11"))
+	do(clientv3.OpPut("/one", "111"), clientv3.OpDelete("/two"))
+	do(clientv3.OpPut("/four", "4"), clientv3.OpPut("/bad", "invalid value is not a change"))
+	do(clientv3.OpPut("/five", "5"))
+	var rev2 = do(clientv3.OpDelete("/five"))
+
+	var watchErr = make(chan error)
+	go func() { watchErr <- ks.Watch(ctx, client) }()
+
+	ks.Mu.RLock()
+	c.Check(ks.WaitForRevision(ctx, rev2), gc.IsNil)
+	ks.Mu.RUnlock()
+
+	// Expect Deltas from retained history equal those read from Etcd,
+	// by a KeySpace which retains no history.
+	var other = NewKeySpace("/", testDecoder)
+	other.ChangeHistory = 0
+	c.Assert(other.Load(ctx, client, rev2), gc.IsNil)
+
+	var expect = make(map[[2]int64][]Change)
+	for _, r := range [][2]int64{{rev1, rev2}, {rev1, rev1 + 1}, {rev1 + 1, rev2 - 1}, {rev2, rev2}} {
+		var changes, err = other.Delta(ctx, client, r[0], r[1])
+		c.Assert(err, gc.IsNil)
+		expect[r] = changes
+	}
+	c.Check(expect[[2]int64{rev1, rev2}], gc.HasLen, 3)
+
+	// Compact Etcd, such that it can no longer serve the Deltas.
+	_, err := client.Compact(ctx, rev2)
+	c.Assert(err, gc.IsNil)
+
+	for r, changes := range expect {
+		var actual, err = ks.Delta(ctx, client, r[0], r[1])
+		c.Check(err, gc.IsNil)
+		c.Check(actual, gc.DeepEquals, changes)
+	}
+	// Revisions before the retained history are read from Etcd.
+	_, err = ks.Delta(ctx, client, rev1-1, rev2)
+	c.Check(errors.Is(err, rpctypes.ErrCompacted), gc.Equals, true)
+
+	cancel()
+	c.Check(<-watchErr, gc.Equals, context.Canceled)
+}
+
+func (s *KeySpaceSuite) TestWatchMerger(c *gc.C) {
+	var m = newWatchMerger(2, 10)
+	var hdr = func(rev int64) epb.ResponseHeader { return epb.ResponseHeader{ClusterId: 9999, Revision: rev} }
+
+	// Events of Watch 0 are held until Watch 1 is known to be complete.
+	var _, ok = m.add(0, clientv3.WatchResponse{
+		Header: hdr(12),
+		Events: []*clientv3.Event{
+			putEvent("/a/1", "1", 10, 10, 1),
+			putEvent("/a/2", "2", 12, 12, 1),
+		},
+	})
+	c.Check(ok, gc.Equals, false)
+
+	// Watch 1 has an Event at revision 11.
+	out, ok := m.add(1, clientv3.WatchResponse{
+		Header: hdr(12),
+		Events: []*clientv3.Event{putEvent("/b/1", "3", 11, 11, 1)},
+	})
+	c.Check(ok, gc.Equals, true)
+	c.Check(out.Header.Revision, gc.Equals, int64(11))
+	c.Check(out.Events, gc.DeepEquals, []*clientv3.Event{
+		putEvent("/a/1", "1", 10, 10, 1),
+		putEvent("/b/1", "3", 11, 11, 1),
+	})
+
+	// A progress notification of Watch 1 releases the Event of revision 12.
+	out, ok = m.add(1, clientv3.WatchResponse{Header: hdr(14)})
+	c.Check(ok, gc.Equals, true)
+	c.Check(out.Header.Revision, gc.Equals, int64(12))
+	c.Check(out.Events, gc.DeepEquals, []*clientv3.Event{putEvent("/a/2", "2", 12, 12, 1)})
+
+	// Progress without Events is itself a progress notification.
+	out, ok = m.add(0, clientv3.WatchResponse{Header: hdr(15)})
+	c.Check(ok, gc.Equals, true)
+	c.Check(out.IsProgressNotify(), gc.Equals, true)
+	c.Check(out.Header.Revision, gc.Equals, int64(14))
+
+	// Regressions are ignored.
+	_, ok = m.add(1, clientv3.WatchResponse{Header: hdr(13)})
+	c.Check(ok, gc.Equals, false)
+	c.Check(m.pending, gc.HasLen, 0)
+}
+
 func (s *KeySpaceSuite) TestHeaderChecking(c *gc.C) {
 	var h = epb.ResponseHeader{
 		ClusterId: 8675309,
@@ -199,6 +622,254 @@ func (s *KeySpaceSuite) TestWatchResponseApply(c *gc.C) {
 		})
 }
 
+func (s *KeySpaceSuite) TestDecodeErrorHandler(c *gc.C) {
+	var ks = NewKeySpace("/", testDecoder)
+	ks.Header = epb.ResponseHeader{ClusterId: 9999, Revision: 9}
+
+	var calls []string
+	ks.DecodeErrorHandler = func(key, value []byte, err error) DecodeErrorAction {
+		calls = append(calls, string(key)+"="+string(value))
+		c.Check(err, gc.ErrorMatches, `strconv.ParseInt: .*`)
+
+		if bytes.HasPrefix(key, []byte("/remove")) {
+			return DecodeErrorRemove
+		} else if bytes.HasPrefix(key, []byte("/halt")) {
+			return DecodeErrorHalt
+		}
+		return DecodeErrorSkip
+	}
+	var errorsBefore = testutil.ToFloat64(keySpaceDecodeErrorsTotal.WithLabelValues("/"))
+
+	c.Check(ks.Apply(clientv3.WatchResponse{
+		Header: epb.ResponseHeader{ClusterId: 9999, Revision: 10},
+		Events: []*clientv3.Event{
+			putEvent("/remove/existing", "1", 10, 10, 1),
+			putEvent("/remove/key", "bad", 10, 10, 1),
+			putEvent("/skip/existing", "1", 10, 10, 1),
+			putEvent("/skip/key", "bad", 10, 10, 1),
+		},
+	}), gc.IsNil)
+	verifyDecodedKeyValues(c, ks.KeyValues,
+		map[string]int{"/remove/existing": 1, "/skip/existing": 1})
+
+	var feed = ks.NewChangeFeed(false)
+	defer feed.Close()
+
+	c.Check(ks.Apply(clientv3.WatchResponse{
+		Header: epb.ResponseHeader{ClusterId: 9999, Revision: 11},
+		Events: []*clientv3.Event{
+			putEvent("/remove/existing", "bad", 10, 11, 2),
+			putEvent("/skip/existing", "bad", 10, 11, 2),
+		},
+	}), gc.IsNil)
+
+	// Expect new keys are omitted, and a skipped existing key retains its
+	// prior value. DecodeErrorRemove removes an existing key, as a deletion.
+	verifyDecodedKeyValues(c, ks.KeyValues, map[string]int{"/skip/existing": 1})
+
+	var changes, err = feed.Next(context.Background())
+	c.Check(err, gc.IsNil)
+	c.Check(changes, gc.HasLen, 1)
+	c.Check(changes[0].Type, gc.Equals, mvccpb.DELETE)
+	c.Check(changes[0].Key, gc.Equals, "/remove/existing")
+
+	// Keys which are later corrected decode normally.
+	c.Check(ks.Apply(clientv3.WatchResponse{
+		Header: epb.ResponseHeader{ClusterId: 9999, Revision: 12},
+		Events: []*clientv3.Event{
+			putEvent("/remove/existing", "2", 10, 12, 3),
+			putEvent("/remove/key", "3", 10, 12, 2),
+			putEvent("/skip/existing", "4", 10, 12, 3),
+			putEvent("/skip/key", "5", 10, 12, 2),
+		},
+	}), gc.IsNil)
+	verifyDecodedKeyValues(c, ks.KeyValues, map[string]int{
+		"/remove/existing": 2, "/remove/key": 3, "/skip/existing": 4, "/skip/key": 5})
+
+	// DecodeErrorHalt fails the Apply, and the KeySpace isn't updated.
+	c.Check(ks.Apply(clientv3.WatchResponse{
+		Header: epb.ResponseHeader{ClusterId: 9999, Revision: 13},
+		Events: []*clientv3.Event{
+			putEvent("/halt/key", "bad", 13, 13, 1),
+			putEvent("/skip/key", "6", 10, 13, 3),
+		},
+	}), gc.ErrorMatches, `decoding key "/halt/key": strconv.ParseInt: .*`)
+
+	c.Check(ks.Header.Revision, gc.Equals, int64(12))
+	verifyDecodedKeyValues(c, ks.KeyValues, map[string]int{
+		"/remove/existing": 2, "/remove/key": 3, "/skip/existing": 4, "/skip/key": 5})
+
+	c.Check(calls, gc.DeepEquals, []string{
+		"/remove/key=bad",
+		"/skip/key=bad",
+		"/remove/existing=bad",
+		"/skip/existing=bad",
+		"/halt/key=bad",
+	})
+	c.Check(testutil.ToFloat64(keySpaceDecodeErrorsTotal.WithLabelValues("/"))-errorsBefore, gc.Equals, 5.0)
+
+	// DecodeErrorHalt also fails a Load.
+	var client = etcdtest.TestClient()
+	defer etcdtest.Cleanup()
+
+	resp, err := client.Put(context.Background(), "/halt/key", "bad")
+	c.Assert(err, gc.IsNil)
+
+	ks = NewKeySpace("/", testDecoder)
+	ks.DecodeErrorHandler = func([]byte, []byte, error) DecodeErrorAction { return DecodeErrorHalt }
+	c.Check(ks.Load(context.Background(), client, resp.Header.Revision),
+		gc.ErrorMatches, `decoding key "/halt/key": strconv.ParseInt: .*`)
+}
+
+func (s *KeySpaceSuite) TestUnknownKeysAreIgnored(c *gc.C) {
+	var ks = NewKeySpace("/", func(raw *mvccpb.KeyValue) (interface{}, error) {
+		if bytes.HasPrefix(raw.Key, []byte("/unknown/")) {
+			return nil, fmt.Errorf("unexpected key prefix: %w", ErrUnknownKey)
+		}
+		return testDecoder(raw)
+	})
+	ks.Header = epb.ResponseHeader{ClusterId: 9999, Revision: 9}
+	ks.DecodeErrorHandler = func([]byte, []byte, error) DecodeErrorAction {
+		c.Error("not called")
+		return DecodeErrorHalt
+	}
+	var errorsBefore = testutil.ToFloat64(keySpaceDecodeErrorsTotal.WithLabelValues("/"))
+
+	c.Check(ks.Apply(clientv3.WatchResponse{
+		Header: epb.ResponseHeader{ClusterId: 9999, Revision: 10},
+		Events: []*clientv3.Event{
+			putEvent("/known/key", "1", 10, 10, 1),
+			putEvent("/unknown/key", "bad", 10, 10, 1),
+		},
+	}), gc.IsNil)
+	c.Check(ks.Apply(clientv3.WatchResponse{
+		Header: epb.ResponseHeader{ClusterId: 9999, Revision: 11},
+		Events: []*clientv3.Event{delEvent("/unknown/key", 11)},
+	}), gc.IsNil)
+
+	c.Check(ks.isUnknownKey(errUnknownDeletion, delEvent("/unknown/key", 11).Kv), gc.Equals, true)
+	c.Check(ks.isUnknownKey(errUnknownDeletion, delEvent("/known/other", 11).Kv), gc.Equals, false)
+
+	verifyDecodedKeyValues(c, ks.KeyValues, map[string]int{"/known/key": 1})
+	c.Check(testutil.ToFloat64(keySpaceDecodeErrorsTotal.WithLabelValues("/"))-errorsBefore, gc.Equals, 0.0)
+}
+
+func (s *KeySpaceSuite) TestWatchThrottling(c *gc.C) {
+	var resp = func(events int) clientv3.WatchResponse {
+		return clientv3.WatchResponse{Events: make([]*clientv3.Event, events)}
+	}
+	var responses = []clientv3.WatchResponse{resp(2), resp(3), resp(4), resp(1)}
+	var ks = NewKeySpace("/", testDecoder)
+
+	// Without a WatchApplyRate, all responses are applied.
+	var n, events, delay = ks.throttledBatch(responses, 10)
+	c.Check([]interface{}{n, events, delay}, gc.DeepEquals, []interface{}{4, 10, time.Duration(0)})
+
+	// With a rate, responses which fit within the rate over WatchApplyDelay
+	// (100 * 30ms = 3 events) are applied and paced.
+	ks.WatchApplyRate, ks.WatchMaxBacklog = 100, 10
+	n, events, delay = ks.throttledBatch(responses, 10)
+	c.Check([]interface{}{n, events, delay}, gc.DeepEquals, []interface{}{1, 2, 20 * time.Millisecond})
+
+	// At least one response is applied, even if it exceeds the rate.
+	n, events, delay = ks.throttledBatch(responses[2:], 5)
+	c.Check([]interface{}{n, events, delay}, gc.DeepEquals, []interface{}{1, 4, 40 * time.Millisecond})
+
+	// A backlog beyond WatchMaxBacklog is applied at full speed.
+	n, events, delay = ks.throttledBatch(append(responses, resp(1)), 11)
+	c.Check([]interface{}{n, events, delay}, gc.DeepEquals, []interface{}{5, 11, time.Duration(0)})
+
+	// WatchMaxBacklog defaults to one second of events.
+	ks.WatchMaxBacklog = 0
+	n, _, _ = ks.throttledBatch(responses, 101)
+	c.Check(n, gc.Equals, 4)
+
+	// Expect a throttled Watch applies all events, and drains its backlog.
+	var client = etcdtest.TestClient()
+	defer etcdtest.Cleanup()
+
+	var ctx, cancel = context.WithCancel(context.Background())
+	ks.WatchApplyRate, ks.WatchApplyDelay = 200, 10*time.Millisecond
+	c.Check(ks.Load(ctx, client, 0), gc.IsNil)
+
+	var done = make(chan error)
+	go func() { done <- ks.Watch(ctx, client) }()
+
+	var rev int64
+	for i := 0; i != 20; i++ {
+		var r, err = client.Put(ctx, fmt.Sprintf("/key-%02d", i), strconv.Itoa(i))
+		c.Assert(err, gc.IsNil)
+		rev = r.Header.Revision
+	}
+	ks.Mu.RLock()
+	c.Check(ks.WaitForRevision(ctx, rev), gc.IsNil)
+	c.Check(ks.KeyValues, gc.HasLen, 20)
+	ks.Mu.RUnlock()
+
+	cancel()
+	c.Check(<-done, gc.Equals, context.Canceled)
+	c.Check(testutil.ToFloat64(keySpaceWatchBacklog.WithLabelValues("/")), gc.Equals, 0.0)
+}
+
+func (s *KeySpaceSuite) TestObserverOrderingAndPanics(c *gc.C) {
+	var ks = NewKeySpace("/", testDecoder)
+	ks.Header = epb.ResponseHeader{ClusterId: 9999, Revision: 9}
+
+	var calls []string
+	var observe = func(name string) func() {
+		return func() { calls = append(calls, name) }
+	}
+	ks.AddObserver("five", 5, observe("five"))
+	ks.AddObserver("zero-a", 0, observe("zero-a"))
+	ks.AddObserver("panics", 0, func() { panic("whoops") })
+	ks.AddObserver("minus-one", -1, observe("minus-one"))
+	ks.AddObserver("zero-b", 0, observe("zero-b"))
+	ks.Observers = append(ks.Observers, func() { panic("unnamed whoops") }, observe("unnamed"))
+
+	// Capture logged panics of Observers.
+	var hook = new(logtest.Hook)
+	defer log.StandardLogger().ReplaceHooks(log.StandardLogger().ReplaceHooks(log.LevelHooks{}))
+	log.AddHook(hook)
+
+	var loggedPanics = func() (out []string) {
+		for _, e := range hook.AllEntries() {
+			if e.Message == "KeySpace observer panicked" {
+				out = append(out, fmt.Sprintf("%s: %v", e.Data["observer"], e.Data["panic"]))
+			}
+		}
+		hook.Reset()
+		return
+	}
+
+	c.Check(ks.Apply(clientv3.WatchResponse{
+		Header: epb.ResponseHeader{ClusterId: 9999, Revision: 10},
+		Events: []*clientv3.Event{putEvent("/some/key", "99", 10, 10, 1)},
+	}), gc.IsNil)
+
+	// Expect panics were logged with the name of the Observer, or if unnamed,
+	// with its index of KeySpace.Observers.
+	c.Check(loggedPanics(), gc.DeepEquals, []string{"Observers[0]: unnamed whoops", "panics: whoops"})
+
+	// Expect Observers were called in priority and registration order, and
+	// the panicking Observer didn't prevent the calls of Observers after it.
+	c.Check(calls, gc.DeepEquals, []string{"minus-one", "unnamed", "zero-a", "zero-b", "five"})
+	verifyDecodedKeyValues(c, ks.KeyValues, map[string]int{"/some/key": 99})
+
+	// Expect that updates continue to be observed.
+	calls = nil
+	c.Check(ks.Apply(clientv3.WatchResponse{
+		Header: epb.ResponseHeader{ClusterId: 9999, Revision: 11},
+		Events: []*clientv3.Event{putEvent("/some/key", "100", 10, 11, 2)},
+	}), gc.IsNil)
+	c.Check(calls, gc.DeepEquals, []string{"minus-one", "unnamed", "zero-a", "zero-b", "five"})
+	c.Check(loggedPanics(), gc.DeepEquals, []string{"Observers[0]: unnamed whoops", "panics: whoops"})
+
+	// The KeySpace didn't fail, and may be waited upon as usual.
+	ks.Mu.RLock()
+	c.Check(ks.WaitForRevision(context.Background(), 11), gc.IsNil)
+	ks.Mu.RUnlock()
+}
+
 func (s *KeySpaceSuite) TestWaitForRevision(c *gc.C) {
 	var ks = NewKeySpace("/", testDecoder)
 
@@ -246,6 +917,34 @@ func (s *KeySpaceSuite) TestWaitForRevision(c *gc.C) {
 
 var _ = gc.Suite(&KeySpaceSuite{})
 
+// readChanges reads |n| Changes of the ChangeFeed, verifies they're ordered
+// on revision, and returns a summary of each.
+func readChanges(c *gc.C, f *ChangeFeed, n int) []string {
+	var out []string
+	var last int64
+
+	for len(out) < n {
+		var changes, err = f.Next(context.Background())
+		c.Assert(err, gc.IsNil)
+
+		for _, ch := range changes {
+			c.Check(ch.Revision >= last, gc.Equals, true)
+			last = ch.Revision
+
+			var old, new interface{}
+			if ch.Old != nil {
+				old = ch.Old.Decoded
+			}
+			if ch.New != nil {
+				new = ch.New.Decoded
+				c.Check(ch.New.Raw.ModRevision, gc.Equals, ch.Revision)
+			}
+			out = append(out, fmt.Sprintf("%s %s %v %v", ch.Type, ch.Key, old, new))
+		}
+	}
+	return out
+}
+
 func Test(t *testing.T) { gc.TestingT(t) }
 
 func TestMain(m *testing.M) { etcdtest.TestMainWithEtcd(m) }
@@ -2,6 +2,7 @@ package keyspace
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"sort"
 
@@ -18,14 +19,25 @@ type KeyValue struct {
 
 // A KeyValueDecoder decodes raw KeyValue instances into a user-defined
 // representation. KeyValueDecoder returns an error if the KeyValue cannot be
-// decoded. KeySpace will log decoding errors and not incorporate them into
-// the current KeyValues, but will also treat them as recoverable and in all
+// decoded. By default, KeySpace will log decoding errors and not incorporate
+// them into the current KeyValues (see KeySpace.DecodeErrorHandler), but will
+// also treat them as recoverable and in all
 // cases seek to bring the KeyValues representation to consistency with Etcd.
 // In practice, this means bad values written to Etcd which fail to decode
 // may be later corrected with valid representations: KeySpace will ignore
 // the bad update and then reflect the corrected one once available.
 type KeyValueDecoder func(raw *mvccpb.KeyValue) (interface{}, error)
 
+// ErrUnknownKey may be returned (or wrapped) by a KeyValueDecoder for a key it
+// doesn't recognize, such as a key of a newer release which shares the KeySpace.
+// The key is omitted from KeyValues as with DecodeErrorSkip, but isn't logged,
+// counted as a decode error, or passed to the DecodeErrorHandler.
+var ErrUnknownKey = errors.New("unknown key")
+
+// errUnknownDeletion is returned by updateKeyValuesTail upon the deletion of
+// a key which isn't in KeyValues.
+var errUnknownDeletion = errors.New("unexpected deletion of unknown key")
+
 // KeyValues is a collection of KeyValue naturally ordered on keys.
 type KeyValues []KeyValue
 
@@ -118,7 +130,7 @@ func updateKeyValuesTail(kv KeyValues, decode KeyValueDecoder, event clientv3.Ev
 			// This case can happen if a key with a bad value (which failed to decode,
 			// and was not applied) is subsequently deleted. Ignoring the deletion
 			// brings KeyValues back to consistency.
-			return kv, fmt.Errorf("unexpected deletion of unknown key")
+			return kv, errUnknownDeletion
 		} else if event.Type != clientv3.EventTypePut {
 			// DELETE & PUT are the only defined types in Etcd's `mvccpb` package kv.proto.
 			panic(event.Type)
@@ -0,0 +1,179 @@
+package keyspace
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+// validateSubPrefixes returns an error if |prefixes| are not ordered,
+// non-empty, and non-overlapping.
+func validateSubPrefixes(prefixes []string) error {
+	for i, p := range prefixes {
+		if p == "" {
+			return fmt.Errorf("SubPrefixes[%d] is empty", i)
+		} else if i == 0 {
+			continue
+		} else if prev := prefixes[i-1]; p <= prev {
+			return fmt.Errorf("SubPrefixes are not ordered (%q <= %q)", p, prev)
+		} else if strings.HasPrefix(p, prev) {
+			return fmt.Errorf("SubPrefixes overlap (%q is prefixed by %q)", p, prev)
+		}
+	}
+	return nil
+}
+
+// watchMerged starts a Watch of each of |prefixes| from |revision|, and returns
+// a WatchChan which merges their responses. Each Watch delivers its Events in
+// revision order, but Watches progress independently of one another. The
+// merged WatchChan instead delivers Events only through the revision at which
+// all Watches are known to be complete, so that its responses have the same
+// revision ordering as a single Watch, and never reflect a partial Etcd
+// transaction. Progress notifications are requested of lagging Watches as
+// needed, and are delivered when the merged revision advances without Events.
+//
+// The merged WatchChan is closed upon the first error response of any Watch,
+// which is delivered, or if any Watch is closed. |ctx| must include any
+// metadata of the Watches (eg, clientv3.WithRequireLeader), as progress
+// notifications are requested of the Watch stream associated with |ctx|.
+func watchMerged(ctx context.Context, client clientv3.Watcher, prefixes []string, revision int64) clientv3.WatchChan {
+	var ctx2, cancel = context.WithCancel(ctx)
+	var taggedCh = make(chan taggedWatchResponse)
+
+	for i, prefix := range prefixes {
+		var watchCh = client.Watch(ctx2, prefix,
+			clientv3.WithPrefix(),
+			clientv3.WithProgressNotify(),
+			clientv3.WithRev(revision),
+		)
+		go func(index int, watchCh clientv3.WatchChan) {
+			for {
+				var resp, ok = <-watchCh
+
+				select {
+				case taggedCh <- taggedWatchResponse{index: index, resp: resp, ok: ok}:
+				case <-ctx2.Done():
+					return
+				}
+				if !ok {
+					return
+				}
+			}
+		}(i, watchCh)
+	}
+
+	var outCh = make(chan clientv3.WatchResponse)
+	go func() {
+		defer close(outCh)
+		defer cancel()
+
+		var merger = newWatchMerger(len(prefixes), revision)
+		for {
+			var tagged taggedWatchResponse
+			select {
+			case tagged = <-taggedCh:
+			case <-ctx2.Done():
+				return
+			}
+
+			var out, ok = tagged.resp, tagged.ok
+
+			if !ok {
+				return // Watch was closed.
+			} else if out.Err() != nil {
+				// Pass through to the caller.
+			} else if out, ok = merger.add(tagged.index, tagged.resp); len(merger.pending) != 0 {
+				// Request progress of Watches which may be idle. Etcd sends progress
+				// notifications only once all Watches of the stream are caught up.
+				_ = client.RequestProgress(ctx2)
+			}
+
+			if ok {
+				select {
+				case outCh <- out:
+				case <-ctx2.Done():
+					return
+				}
+			}
+			if out.Err() != nil {
+				return
+			}
+		}
+	}()
+
+	return outCh
+}
+
+// watchMerger merges WatchResponses of multiple Watches.
+type watchMerger struct {
+	// Revision through which each Watch is known to be complete.
+	complete []int64
+	// Revision through which merged responses have been returned.
+	merged int64
+	// Events which have been added but not yet returned.
+	pending []*clientv3.Event
+}
+
+func newWatchMerger(watches int, revision int64) *watchMerger {
+	var m = &watchMerger{
+		complete: make([]int64, watches),
+		merged:   revision - 1,
+	}
+	for i := range m.complete {
+		m.complete[i] = m.merged
+	}
+	return m
+}
+
+// add the WatchResponse of Watch |index|. If the revision through which all
+// Watches are complete has advanced, add returns a merged WatchResponse having
+// that Header.Revision and all pending Events through it, or if there are no
+// such Events, a progress notification.
+func (m *watchMerger) add(index int, resp clientv3.WatchResponse) (clientv3.WatchResponse, bool) {
+	if n := len(resp.Events); n != 0 {
+		m.pending = append(m.pending, resp.Events...)
+		// Etcd doesn't split the Events of a revision across responses.
+		m.complete[index] = resp.Events[n-1].Kv.ModRevision
+	} else if resp.IsProgressNotify() && resp.Header.Revision > m.complete[index] {
+		m.complete[index] = resp.Header.Revision
+	}
+
+	var through = m.complete[0]
+	for _, c := range m.complete[1:] {
+		if c < through {
+			through = c
+		}
+	}
+	if through <= m.merged {
+		return clientv3.WatchResponse{}, false
+	}
+	m.merged = through
+
+	// Order on ModRevision, while preserving the key order of a revision's
+	// Events within each Watch.
+	sort.SliceStable(m.pending, func(i, j int) bool {
+		return m.pending[i].Kv.ModRevision < m.pending[j].Kv.ModRevision
+	})
+	var n = sort.Search(len(m.pending), func(i int) bool {
+		return m.pending[i].Kv.ModRevision > through
+	})
+
+	var out = clientv3.WatchResponse{Header: resp.Header}
+	out.Header.Revision = through
+
+	if n != 0 {
+		out.Events = append([]*clientv3.Event(nil), m.pending[:n]...)
+		m.pending = append(m.pending[:0], m.pending[n:]...)
+	}
+	return out, true
+}
+
+// taggedWatchResponse is a WatchResponse of an indexed Watch.
+type taggedWatchResponse struct {
+	index int
+	resp  clientv3.WatchResponse
+	ok    bool // False if the Watch was closed.
+}
@@ -0,0 +1,84 @@
+package keyspace
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/gogo/protobuf/proto"
+)
+
+// ValueCodec is a symmetric encoding of values stored under a KeySpace.
+// KeyValueDecoders may use a ValueCodec to decode raw Etcd values, while
+// tooling which writes values into Etcd uses the same ValueCodec to encode
+// them, ensuring that readers and writers agree on a serialization.
+type ValueCodec interface {
+	// Encode the value into its stored representation.
+	Encode(v interface{}) ([]byte, error)
+	// Decode the stored representation into |v|, which must be a pointer.
+	Decode(b []byte, v interface{}) error
+}
+
+// ProtoMagic is the leading byte of values encoded by ProtoCodec. It's never
+// the first byte of a valid JSON document, nor of a non-empty protobuf message
+// (as zero is not a valid field number), which allows DetectingCodec to
+// distinguish ProtoCodec values from those written by JSONCodec.
+const ProtoMagic byte = 0x00
+
+// JSONCodec is a ValueCodec which encodes values as JSON.
+type JSONCodec struct{}
+
+// Encode the value as JSON.
+func (JSONCodec) Encode(v interface{}) ([]byte, error) { return json.Marshal(v) }
+
+// Decode JSON into |v|.
+func (JSONCodec) Decode(b []byte, v interface{}) error { return json.Unmarshal(b, v) }
+
+// ProtoCodec is a ValueCodec which encodes values as binary protobuf messages,
+// prefixed with ProtoMagic. Values must implement proto.Message.
+type ProtoCodec struct{}
+
+// Encode the value, which must be a proto.Message, with a ProtoMagic prefix.
+func (ProtoCodec) Encode(v interface{}) ([]byte, error) {
+	var msg, ok = v.(proto.Message)
+	if !ok {
+		return nil, fmt.Errorf("value %T is not a proto.Message", v)
+	}
+	var b, err = proto.Marshal(msg)
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte{ProtoMagic}, b...), nil
+}
+
+// Decode a ProtoMagic-prefixed protobuf message into |v|,
+// which must be a proto.Message.
+func (ProtoCodec) Decode(b []byte, v interface{}) error {
+	var msg, ok = v.(proto.Message)
+	if !ok {
+		return fmt.Errorf("value %T is not a proto.Message", v)
+	} else if len(b) == 0 || b[0] != ProtoMagic {
+		return fmt.Errorf("value is missing ProtoMagic prefix")
+	}
+	return proto.Unmarshal(b[1:], msg)
+}
+
+// DetectingCodec encodes values using its Encoder, and decodes values of
+// either ProtoCodec or JSONCodec format by inspecting their leading byte.
+// It's intended for migrations of a KeySpace from JSON to protobuf values,
+// where a mix of both formats may be present at any given time.
+type DetectingCodec struct {
+	// Encoder of written values, which is typically ProtoCodec
+	// (or JSONCodec, to roll back a migration).
+	Encoder ValueCodec
+}
+
+// Encode the value using the DetectingCodec's Encoder.
+func (c DetectingCodec) Encode(v interface{}) ([]byte, error) { return c.Encoder.Encode(v) }
+
+// Decode the value after detecting whether it's a ProtoCodec or JSONCodec encoding.
+func (c DetectingCodec) Decode(b []byte, v interface{}) error {
+	if len(b) != 0 && b[0] == ProtoMagic {
+		return ProtoCodec{}.Decode(b, v)
+	}
+	return JSONCodec{}.Decode(b, v)
+}
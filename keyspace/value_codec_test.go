@@ -0,0 +1,61 @@
+package keyspace
+
+import (
+	pb "go.gazette.dev/core/broker/protocol"
+	gc "gopkg.in/check.v1"
+)
+
+type ValueCodecSuite struct{}
+
+func (s *ValueCodecSuite) TestRoundTrips(c *gc.C) {
+	var fixture = pb.BrokerSpec{
+		ProcessSpec: pb.ProcessSpec{
+			Id:       pb.ProcessSpec_ID{Zone: "a-zone", Suffix: "a-suffix"},
+			Endpoint: "http://a/endpoint",
+		},
+		JournalLimit: 123,
+	}
+	for _, codec := range []ValueCodec{
+		JSONCodec{},
+		ProtoCodec{},
+		DetectingCodec{Encoder: JSONCodec{}},
+		DetectingCodec{Encoder: ProtoCodec{}},
+	} {
+		var b, err = codec.Encode(&fixture)
+		c.Assert(err, gc.IsNil)
+
+		var out pb.BrokerSpec
+		c.Check(codec.Decode(b, &out), gc.IsNil)
+		c.Check(out, gc.DeepEquals, fixture)
+	}
+}
+
+func (s *ValueCodecSuite) TestMixedFormatDecoding(c *gc.C) {
+	var fixture = pb.BrokerSpec{JournalLimit: 456}
+
+	var jsonValue, err = JSONCodec{}.Encode(&fixture)
+	c.Assert(err, gc.IsNil)
+	protoValue, err := ProtoCodec{}.Encode(&fixture)
+	c.Assert(err, gc.IsNil)
+	c.Check(protoValue[0], gc.Equals, ProtoMagic)
+
+	// A DetectingCodec decodes values of either format.
+	var codec = DetectingCodec{Encoder: ProtoCodec{}}
+	for _, b := range [][]byte{jsonValue, protoValue} {
+		var out pb.BrokerSpec
+		c.Check(codec.Decode(b, &out), gc.IsNil)
+		c.Check(out, gc.DeepEquals, fixture)
+	}
+
+	// ProtoCodec requires its magic prefix.
+	var out pb.BrokerSpec
+	c.Check(ProtoCodec{}.Decode(jsonValue, &out), gc.ErrorMatches,
+		"value is missing ProtoMagic prefix")
+	// JSONCodec doesn't understand protobuf values.
+	c.Check(JSONCodec{}.Decode(protoValue, &out), gc.NotNil)
+	// ProtoCodec requires a proto.Message.
+	_, err = ProtoCodec{}.Encode(struct{}{})
+	c.Check(err, gc.ErrorMatches, `value struct {} is not a proto.Message`)
+}
+
+var _ = gc.Suite(&ValueCodecSuite{})
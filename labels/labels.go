@@ -53,11 +53,22 @@ const (
 	// AWS, Azure, or GCP regions like "us-central1", "us-east-1", etc. Only one
 	// Region label is allowed. Compare to failure-domain.beta.kubernetes.io/region.
 	Region = "app.gazette.dev/region"
+	// FragmentTagPrefix prefixes labels of a journal which are applied as
+	// object tags (or metadata, where the store doesn't support tags) of its
+	// persisted fragments, named by the label with the prefix removed. Eg, a
+	// label "app.gazette.dev/fragment-tag/lifecycle" of value "cold" tags
+	// fragments with "lifecycle=cold", which the store's lifecycle rules may
+	// match to transition fragments to colder storage classes as they age.
+	FragmentTagPrefix = "app.gazette.dev/fragment-tag/"
+	// AppendToken is a journal register which holds the token of the most
+	// recent conditional append of the journal (see client.AppendIfHead).
+	AppendToken = "app.gazette.dev/append-token"
 )
 
 // SingleValueLabels identifies label names which must only have one label value
 // within a specification.
 var SingleValueLabels = map[string]struct{}{
+	AppendToken:    {},
 	ContentType:    {},
 	Instance:       {},
 	ManagedBy:      {},
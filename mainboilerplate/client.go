@@ -9,33 +9,85 @@ import (
 	"go.gazette.dev/core/broker/client"
 	pb "go.gazette.dev/core/broker/protocol"
 	pc "go.gazette.dev/core/consumer/protocol"
+	"go.gazette.dev/core/server"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/keepalive"
 )
 
 // AddressConfig of a remote service.
 type AddressConfig struct {
 	Address pb.Endpoint `long:"address" env:"ADDRESS" default:"http://localhost:8080" description:"Service address endpoint"`
+
+	Keepalive struct {
+		Time    time.Duration `long:"keepalive.time" env:"KEEPALIVE_TIME" default:"0s" description:"Interval of keepalive pings of a connection which has seen no activity. If zero, keepalive pings are not sent"`
+		Timeout time.Duration `long:"keepalive.timeout" env:"KEEPALIVE_TIMEOUT" default:"20s" description:"Time to wait for a keepalive ping acknowledgement before the connection is closed"`
+	}
+	RPCTimeout time.Duration `long:"rpc-timeout" env:"RPC_TIMEOUT" default:"0s" description:"Deadline of unary RPCs which don't otherwise have one. If zero, there is no deadline"`
+}
+
+// Validate returns an error if the AddressConfig is invalid. Keepalive pings
+// may be no more frequent than server.MinKeepaliveTime, as servers close
+// connections of clients which ping more often.
+func (c *AddressConfig) Validate() error {
+	if t := c.Keepalive.Time; t != 0 && t < server.MinKeepaliveTime {
+		return fmt.Errorf("keepalive.time (%s) must be zero or at least %s, or servers will close connections for excessive pings",
+			t, server.MinKeepaliveTime)
+	} else if t != 0 && c.Keepalive.Timeout < time.Second {
+		return fmt.Errorf("keepalive.timeout (%s) must be at least 1s", c.Keepalive.Timeout)
+	} else if c.RPCTimeout < 0 {
+		return fmt.Errorf("rpc-timeout (%s) must be zero or positive", c.RPCTimeout)
+	}
+	return nil
 }
 
 // MustDial dials the server address using a protocol.Dispatcher balancer, and panics on error.
+//
+// If Keepalive.Time is set, connections which have seen no activity are pinged,
+// and are closed if a ping isn't acknowledged within Keepalive.Timeout. Streams
+// of a closed connection fail, allowing a long-lived stream (such as that of a
+// client.RetryReader) to promptly retry upon a new connection, rather than
+// waiting indefinitely upon a connection which was silently dropped.
+// RPCTimeout applies only to unary RPCs, as streams may be long-lived.
 func (c *AddressConfig) MustDial(ctx context.Context) *grpc.ClientConn {
-	var cc, err = grpc.DialContext(ctx,
-		c.Address.GRPCAddr(),
+	Must(c.Validate(), "invalid client configuration", "endpoint", c.Address)
+
+	var opts = []grpc.DialOption{
 		grpc.WithTransportCredentials(insecure.NewCredentials()),
 		grpc.WithDefaultServiceConfig(fmt.Sprintf(`{"loadBalancingConfig": [{"%s":{}}]}`, pb.DispatcherGRPCBalancerName)),
 		// Use a tighter bound for the maximum back-off delay (default is 120s).
 		// TODO(johnny): Make this configurable?
 		grpc.WithBackoffMaxDelay(time.Second*5),
 		// Instrument client for gRPC metric collection.
-		grpc.WithUnaryInterceptor(grpc_prometheus.UnaryClientInterceptor),
+		grpc.WithChainUnaryInterceptor(grpc_prometheus.UnaryClientInterceptor, c.unaryTimeoutInterceptor),
 		grpc.WithStreamInterceptor(grpc_prometheus.StreamClientInterceptor),
-	)
+	}
+	if c.Keepalive.Time != 0 {
+		opts = append(opts, grpc.WithKeepaliveParams(keepalive.ClientParameters{
+			Time:                c.Keepalive.Time,
+			Timeout:             c.Keepalive.Timeout,
+			PermitWithoutStream: true,
+		}))
+	}
+	var cc, err = grpc.DialContext(ctx, c.Address.GRPCAddr(), opts...)
 	Must(err, "failed to dial remote service", "endpoint", c.Address)
 
 	return cc
 }
 
+// unaryTimeoutInterceptor applies RPCTimeout to a unary RPC
+// whose Context doesn't already have a deadline.
+func (c *AddressConfig) unaryTimeoutInterceptor(ctx context.Context, method string, req, reply interface{},
+	cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+
+	if _, ok := ctx.Deadline(); !ok && c.RPCTimeout != 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.RPCTimeout)
+		defer cancel()
+	}
+	return invoker(ctx, method, req, reply, cc, opts...)
+}
+
 // MustJournalClient dials and returns a new JournalClient.
 func (c *AddressConfig) MustJournalClient(ctx context.Context) pb.JournalClient {
 	return pb.NewJournalClient(c.MustDial(ctx))
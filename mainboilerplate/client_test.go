@@ -0,0 +1,70 @@
+package mainboilerplate
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.gazette.dev/core/server"
+	"google.golang.org/grpc"
+)
+
+func TestAddressConfigValidation(t *testing.T) {
+	var cfg AddressConfig
+	cfg.Keepalive.Timeout = 20 * time.Second
+	require.NoError(t, cfg.Validate()) // Keepalive and RPCTimeout are disabled.
+
+	cfg.Keepalive.Time = server.MinKeepaliveTime
+	cfg.RPCTimeout = time.Minute
+	require.NoError(t, cfg.Validate())
+
+	cfg.Keepalive.Time = server.MinKeepaliveTime - time.Second
+	require.EqualError(t, cfg.Validate(), "keepalive.time (9s) must be zero or at least 10s, "+
+		"or servers will close connections for excessive pings")
+	cfg.Keepalive.Time = server.MinKeepaliveTime
+
+	cfg.Keepalive.Timeout = 500 * time.Millisecond
+	require.EqualError(t, cfg.Validate(), "keepalive.timeout (500ms) must be at least 1s")
+
+	// Keepalive.Timeout isn't validated if keepalive is disabled.
+	cfg.Keepalive.Time = 0
+	require.NoError(t, cfg.Validate())
+	cfg.Keepalive.Time, cfg.Keepalive.Timeout = server.MinKeepaliveTime, time.Second
+
+	cfg.RPCTimeout = -time.Second
+	require.EqualError(t, cfg.Validate(), "rpc-timeout (-1s) must be zero or positive")
+}
+
+func TestUnaryTimeoutInterceptor(t *testing.T) {
+	var cfg = AddressConfig{RPCTimeout: time.Minute}
+
+	var deadline time.Time
+	var hasDeadline bool
+	var invoker = func(ctx context.Context, method string, req, reply interface{},
+		cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		deadline, hasDeadline = ctx.Deadline()
+		return nil
+	}
+
+	// Case: the caller's context has no deadline, and RPCTimeout is applied.
+	var before = time.Now()
+	require.NoError(t, cfg.unaryTimeoutInterceptor(context.Background(), "method", nil, nil, nil, invoker))
+	require.True(t, hasDeadline)
+	require.False(t, deadline.Before(before.Add(time.Minute)))
+	require.False(t, deadline.After(time.Now().Add(time.Minute)))
+
+	// Case: the caller's deadline is retained, even if it's later than RPCTimeout.
+	var ctx, cancel = context.WithTimeout(context.Background(), time.Hour)
+	defer cancel()
+	var expect, _ = ctx.Deadline()
+
+	require.NoError(t, cfg.unaryTimeoutInterceptor(ctx, "method", nil, nil, nil, invoker))
+	require.True(t, hasDeadline)
+	require.Equal(t, expect, deadline)
+
+	// Case: RPCTimeout is zero, and no deadline is applied.
+	cfg.RPCTimeout = 0
+	require.NoError(t, cfg.unaryTimeoutInterceptor(context.Background(), "method", nil, nil, nil, invoker))
+	require.False(t, hasDeadline)
+}
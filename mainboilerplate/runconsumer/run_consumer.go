@@ -79,6 +79,10 @@ type BaseConfig struct {
 		Limit          uint32        `long:"limit" env:"LIMIT" default:"32" description:"Maximum number of Shards this consumer process will allocate"`
 		MaxHotStandbys uint32        `long:"max-hot-standbys" env:"MAX_HOT_STANDBYS" default:"3" description:"Maximum effective hot standbys of any one shard, which upper-bounds its stated hot-standbys."`
 		WatchDelay     time.Duration `long:"watch-delay" env:"WATCH_DELAY" default:"30ms" description:"Delay applied to the application of watched Etcd events. Larger values amortize the processing of fast-changing Etcd keys."`
+		WatchRate      int           `long:"watch-rate" env:"WATCH_RATE" default:"0" description:"Max rate (in events-per-sec) at which watched Etcd events are applied, which smooths the processing of bursts of events. If zero, there is no max rate"`
+		TxnLimit       uint32        `long:"txn-limit" env:"TXN_LIMIT" default:"0" description:"Maximum number of Shards of this consumer process which concurrently process transactions. Zero is unlimited."`
+		TxnRetry       time.Duration `long:"txn-retry" env:"TXN_RETRY" default:"0" description:"Duration for which a primary Shard retries transactions which fail with retryable errors, with backoff, before relinquishing its assignment. If zero, failed transactions aren't retried. Requires that the application's Stores are consumer.RollbackStores, and primary Shards of other Stores fail."`
+		StatusPath     string        `long:"allocator-status-path" env:"ALLOCATOR_STATUS_PATH" description:"HTTP path at which JSON allocator status is served (optional)"`
 	} `group:"Consumer" namespace:"consumer" env-namespace:"CONSUMER"`
 
 	Broker struct {
@@ -155,6 +159,11 @@ func (sc Cmd) Execute(args []string) error {
 	)
 	pc.RegisterShardServer(srv.GRPCServer, service)
 	ks.WatchApplyDelay = bc.Consumer.WatchDelay
+	ks.WatchApplyRate = bc.Consumer.WatchRate
+	service.TxnLimiter.SetLimit(int(bc.Consumer.TxnLimit))
+	if bc.Consumer.TxnRetry != 0 {
+		service.TxnRetry = consumer.BackoffRetryPolicy{Deadline: bc.Consumer.TxnRetry}
+	}
 
 	// Register Resolver as a prometheus.Collector for tracking shard status
 	prometheus.MustRegister(service.Resolver)
@@ -174,6 +183,12 @@ func (sc Cmd) Execute(args []string) error {
 		Tasks:   tasks,
 	}), "failed to init application")
 
+	var status *allocator.StatusHandler
+	if bc.Consumer.StatusPath != "" {
+		status = allocator.NewStatusHandler(state)
+		srv.HTTPMux.Handle(bc.Consumer.StatusPath, status)
+	}
+
 	mbp.Must(allocator.StartSession(allocator.SessionArgs{
 		Etcd:     etcd,
 		LeaseTTL: bc.Etcd.LeaseTTL,
@@ -181,6 +196,7 @@ func (sc Cmd) Execute(args []string) error {
 		Spec:     spec,
 		State:    state,
 		Tasks:    tasks,
+		Status:   status,
 	}), "failed to start allocator session")
 
 	srv.QueueTasks(tasks)
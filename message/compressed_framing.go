@@ -0,0 +1,152 @@
+package message
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"sync"
+
+	"go.gazette.dev/core/broker/codecs"
+	pb "go.gazette.dev/core/broker/protocol"
+)
+
+// CompressedFrameHeaderLength is the number of leading header bytes of a
+// compressed frame, consisting of a fixed frame header (see
+// FixedFrameHeaderLength) followed by a 1-byte CompressionCodec of the frame
+// body. The fixed frame length covers the codec byte and the frame body.
+const CompressedFrameHeaderLength = FixedFrameHeaderLength + 1
+
+// NewCompressedFraming returns a Framing of |contentType| which wraps the
+// |inner| Framing, compressing individual messages with |codec|. Each message
+// is marshalled by |inner| into the body of a compressed frame. Bodies of at
+// least |threshold| bytes are compressed, unless compression doesn't make
+// them smaller, and other bodies are framed as-is. The codec of each body is
+// recorded in its frame header, and readers decompress each message
+// transparently regardless of the codec or threshold with which it was
+// written: a journal may freely mix compressed and uncompressed messages,
+// and the codec or threshold of its Framing may be changed over time.
+//
+// Compressed messages compress poorly a second time, and journals of a
+// compressed Framing should typically disable Fragment compression
+// (a JournalSpec Fragment.CompressionCodec of NONE) to avoid wasting
+// broker CPU on re-compressing their content.
+//
+// The returned Framing must be registered (see RegisterFraming) for use with
+// journals labeled with |contentType|. NewCompressedFraming panics if |codec|
+// is not a valid codec for messages: NONE, GZIP, SNAPPY, or ZSTANDARD.
+func NewCompressedFraming(contentType string, inner Framing, codec pb.CompressionCodec, threshold int) Framing {
+	if err := codec.Validate(); err != nil || codec == pb.CompressionCodec_GZIP_OFFLOAD_DECOMPRESSION {
+		panic(fmt.Sprintf("invalid message compression codec (%s)", codec))
+	}
+	return &compressedFraming{
+		contentType: contentType,
+		inner:       inner,
+		codec:       codec,
+		threshold:   threshold,
+	}
+}
+
+type compressedFraming struct {
+	contentType string
+	inner       Framing
+	codec       pb.CompressionCodec
+	threshold   int
+}
+
+func (f *compressedFraming) ContentType() string { return f.contentType }
+
+func (f *compressedFraming) Marshal(msg Frameable, bw *bufio.Writer) error {
+	var b = compressBufferPool.Get().(*compressBuffers)
+	defer compressBufferPool.Put(b)
+
+	b.body.Reset()
+	b.bw.Reset(&b.body)
+
+	if err := f.inner.Marshal(msg, b.bw); err != nil {
+		return err
+	} else if err = b.bw.Flush(); err != nil {
+		return err
+	}
+	var codec, body = pb.CompressionCodec_NONE, b.body.Bytes()
+
+	if f.codec != pb.CompressionCodec_NONE && len(body) >= f.threshold {
+		b.compressed.Reset()
+
+		if cw, err := codecs.NewCodecWriter(&b.compressed, f.codec); err != nil {
+			return err
+		} else if _, err = cw.Write(body); err != nil {
+			return fmt.Errorf("compressing message: %w", err)
+		} else if err = cw.Close(); err != nil {
+			return fmt.Errorf("compressing message: %w", err)
+		} else if b.compressed.Len() < len(body) {
+			codec, body = f.codec, b.compressed.Bytes()
+		}
+	}
+
+	var header [CompressedFrameHeaderLength]byte
+	copy(header[:4], FixedFrameWord[:])
+	binary.LittleEndian.PutUint32(header[4:8], uint32(len(body)+1))
+	header[8] = byte(codec)
+
+	_, _ = bw.Write(header[:])
+	_, _ = bw.Write(body)
+	return nil
+}
+
+func (f *compressedFraming) NewUnmarshalFunc(r *bufio.Reader) UnmarshalFunc {
+	// |inner| unmarshals from a Reader of the current frame body, which is
+	// reset with each frame.
+	var body = new(frameBodyReader)
+	var br = bufio.NewReader(body)
+	var inner = f.inner.NewUnmarshalFunc(br)
+
+	return func(msg Frameable) error {
+		var b, err = UnpackFixedFrame(r)
+		if err != nil {
+			return err
+		} else if len(b) < CompressedFrameHeaderLength {
+			return fmt.Errorf("compressed frame is too short (%d bytes)", len(b))
+		}
+		var codec = pb.CompressionCodec(b[8])
+		body.r = bytes.NewReader(b[CompressedFrameHeaderLength:])
+
+		if codec != pb.CompressionCodec_NONE {
+			if err = codec.Validate(); err != nil || codec == pb.CompressionCodec_GZIP_OFFLOAD_DECOMPRESSION {
+				return fmt.Errorf("invalid message compression codec (%s)", codec)
+			}
+			var dec, err = codecs.NewCodecReader(body.r, codec)
+			if err != nil {
+				return fmt.Errorf("decompressing message: %w", err)
+			}
+			defer dec.Close()
+			body.r = dec
+		}
+		br.Reset(body)
+
+		if err = inner(msg); err == io.EOF {
+			err = io.ErrUnexpectedEOF // Frame body is always expected to hold a message.
+		}
+		return err
+	}
+}
+
+// frameBodyReader is an io.Reader of the body of the current frame.
+type frameBodyReader struct{ r io.Reader }
+
+func (r *frameBodyReader) Read(p []byte) (int, error) { return r.r.Read(p) }
+
+// compressBuffers are buffers used by compressedFraming.Marshal.
+type compressBuffers struct {
+	body       bytes.Buffer
+	bw         *bufio.Writer
+	compressed bytes.Buffer
+}
+
+// compressBufferPool pools compressBuffers.
+var compressBufferPool = sync.Pool{New: func() interface{} {
+	var b = new(compressBuffers)
+	b.bw = bufio.NewWriter(&b.body)
+	return b
+}}
@@ -0,0 +1,139 @@
+package message
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	pb "go.gazette.dev/core/broker/protocol"
+	"go.gazette.dev/core/labels"
+)
+
+func TestCompressedFramingRoundTrip(t *testing.T) {
+	var fixed, _ = FramingByContentType(labels.ContentType_ProtoFixed)
+	var json, _ = FramingByContentType(labels.ContentType_JSONLines)
+
+	var large = strings.Repeat("a large and compressible message ", 100)
+
+	for _, tc := range []struct {
+		inner Framing
+		codec pb.CompressionCodec
+	}{
+		{fixed, pb.CompressionCodec_GZIP},
+		{fixed, pb.CompressionCodec_SNAPPY},
+		{fixed, pb.CompressionCodec_NONE},
+		{json, pb.CompressionCodec_GZIP},
+		{json, pb.CompressionCodec_SNAPPY},
+	} {
+		var f = NewCompressedFraming("test/compressed", tc.inner, tc.codec, 64)
+		require.Equal(t, "test/compressed", f.ContentType())
+
+		var buf bytes.Buffer
+		var bw = bufio.NewWriter(&buf)
+		var msgs = []string{"small", large, "also small", large + "!", ""}
+
+		// Marshal messages, and expect only large ones are compressed.
+		var codecs []pb.CompressionCodec
+		for _, s := range msgs {
+			var offset = buf.Len() + bw.Buffered()
+
+			if tc.inner == json {
+				require.NoError(t, f.Marshal(&TestStruct{AField: s}, bw))
+			} else {
+				require.NoError(t, f.Marshal(&frameablestring{s}, bw))
+			}
+			require.NoError(t, bw.Flush())
+			codecs = append(codecs, pb.CompressionCodec(buf.Bytes()[offset+8]))
+		}
+
+		var none = pb.CompressionCodec_NONE
+		if tc.codec == none {
+			require.Equal(t, []pb.CompressionCodec{none, none, none, none, none}, codecs)
+		} else {
+			require.Equal(t, []pb.CompressionCodec{none, tc.codec, none, tc.codec, none}, codecs)
+			require.True(t, buf.Len() < 2*len(large))
+		}
+		buf.WriteString("extra")
+
+		// Expect messages are transparently decompressed.
+		var br = testReader(buf.Bytes())
+		var unmarshal = f.NewUnmarshalFunc(br)
+
+		for _, s := range msgs {
+			if tc.inner == json {
+				var msg TestStruct
+				require.NoError(t, unmarshal(&msg))
+				require.Equal(t, s, msg.AField)
+			} else {
+				var msg frameablestring
+				require.NoError(t, unmarshal(&msg))
+				require.Equal(t, s, msg.s)
+			}
+		}
+		var extra, _ = ioutil.ReadAll(br) // Expect precise frames were consumed.
+		require.Equal(t, "extra", string(extra))
+	}
+}
+
+func TestCompressedFramingMixedCodecs(t *testing.T) {
+	var fixed, _ = FramingByContentType(labels.ContentType_ProtoFixed)
+
+	// Messages written under varied codecs and thresholds are read by any
+	// compressed Framing, regardless of its own codec.
+	var buf bytes.Buffer
+	var bw = bufio.NewWriter(&buf)
+	var large = strings.Repeat("compressible ", 50)
+
+	for _, f := range []Framing{
+		NewCompressedFraming("test/compressed", fixed, pb.CompressionCodec_GZIP, 0),
+		NewCompressedFraming("test/compressed", fixed, pb.CompressionCodec_SNAPPY, 16),
+		NewCompressedFraming("test/compressed", fixed, pb.CompressionCodec_NONE, 0),
+	} {
+		require.NoError(t, f.Marshal(&frameablestring{large}, bw))
+		require.NoError(t, f.Marshal(&frameablestring{"tiny"}, bw))
+	}
+	require.NoError(t, bw.Flush())
+
+	var unmarshal = NewCompressedFraming("test/compressed", fixed, pb.CompressionCodec_NONE, 0).
+		NewUnmarshalFunc(testReader(buf.Bytes()))
+
+	for i := 0; i != 3; i++ {
+		var msg frameablestring
+		require.NoError(t, unmarshal(&msg))
+		require.Equal(t, large, msg.s)
+		require.NoError(t, unmarshal(&msg))
+		require.Equal(t, "tiny", msg.s)
+	}
+	require.Equal(t, io.EOF, unmarshal(new(frameablestring)))
+}
+
+func TestCompressedFramingErrors(t *testing.T) {
+	var fixed, _ = FramingByContentType(labels.ContentType_ProtoFixed)
+	var f = NewCompressedFraming("test/compressed", fixed, pb.CompressionCodec_GZIP, 0)
+
+	// Case: inner Framing fails to marshal.
+	require.EqualError(t, f.Marshal(&frameableerror{"test message"}, nil), "error!")
+
+	// Case: frame has an invalid codec.
+	var fixture = []byte{0x66, 0x33, 0x93, 0x36, 0x04, 0x0, 0x0, 0x0, 0x7f, 'f', 'o', 'o'}
+	require.EqualError(t, f.NewUnmarshalFunc(testReader(fixture))(new(frameablestring)),
+		"invalid message compression codec (127)")
+
+	// Case: frame body is corrupt.
+	fixture = []byte{0x66, 0x33, 0x93, 0x36, 0x04, 0x0, 0x0, 0x0, byte(pb.CompressionCodec_GZIP), 'f', 'o', 'o'}
+	require.Error(t, f.NewUnmarshalFunc(testReader(fixture))(new(frameablestring)))
+
+	// Case: frame is missing its codec.
+	fixture = []byte{0x66, 0x33, 0x93, 0x36, 0x0, 0x0, 0x0, 0x0}
+	require.EqualError(t, f.NewUnmarshalFunc(testReader(fixture))(new(frameablestring)),
+		"compressed frame is too short (8 bytes)")
+
+	// Case: codec is invalid for messages.
+	require.Panics(t, func() {
+		NewCompressedFraming("test/compressed", fixed, pb.CompressionCodec_GZIP_OFFLOAD_DECOMPRESSION, 0)
+	})
+}
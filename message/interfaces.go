@@ -92,6 +92,9 @@
 //      of [4]byte{0x66, 0x33, 0x93, 0x36}, followed by a 4-byte little endian unsigned
 //      length, followed by a marshalled protobuf message.
 //
+// NewCompressedFraming wraps a Framing to compress individual large messages,
+// for registration under an application-chosen content-type.
+//
 // See the "labels" package for definitions of well-known label names and values
 // such as content-types.
 package message
@@ -100,6 +103,7 @@ import (
 	"bufio"
 	"fmt"
 	"io"
+	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
@@ -149,6 +153,11 @@ type Envelope struct {
 	Journal    *pb.JournalSpec // JournalSpec of the Message.
 	Begin, End pb.Offset       // [Begin, End) byte offset of the Message within the Journal.
 	Message                    // Wrapped message.
+
+	// Timestamp of the Message, and its source. These are set only by
+	// iterators having a TimestampFunc, and are otherwise zero-valued.
+	Timestamp       time.Time
+	TimestampSource TimestampSource
 }
 
 // JournalProducer composes an Journal and ProducerID.
@@ -255,4 +264,8 @@ var (
 		Name: "gazette_sequencer_replay",
 		Help: "Cumulative number of messages re-read from source journal due to insufficient Sequencer ring-buffer size.",
 	}, []string{"journal"})
+	skippedMessagesTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "gazette_skipped_messages_total",
+		Help: "Cumulative number of malformed messages which were skipped by readers.",
+	}, []string{"journal"})
 )
@@ -0,0 +1,46 @@
+//go:build go1.23
+
+package message
+
+import (
+	"context"
+	"io"
+	"iter"
+
+	"go.gazette.dev/core/broker/client"
+	pb "go.gazette.dev/core/broker/protocol"
+)
+
+// Messages returns a sequence of read-uncommitted message Envelopes of the
+// ReadRequest, for use with range-over-func:
+//
+//	for env, err := range message.Messages(ctx, rjc, req, newMsg) {
+//	    if err != nil {
+//	        return err
+//	    }
+//	    // Process |env|.
+//	}
+//
+// Each iteration of the sequence begins a RetryReader of the ReadRequest, and
+// messages are read as by ReadUncommittedIter. The journal must have an
+// appropriate labels.ContentType label, which is used to determine the message
+// Framing. The sequence ends without error upon an io.EOF (as when reading
+// through a ReadRequest.EndOffset, or of a non-blocking ReadRequest), and ends
+// after yielding any other error. The RetryReader is cancelled when the
+// sequence ends, including if the caller breaks from its loop.
+func Messages(ctx context.Context, rjc pb.RoutedJournalClient, req pb.ReadRequest, newMsg NewMessageFunc) iter.Seq2[Envelope, error] {
+	return func(yield func(Envelope, error) bool) {
+		var rr = client.NewRetryReader(ctx, rjc, req)
+		defer rr.Cancel()
+
+		var it = NewReadUncommittedIter(rr, newMsg)
+		for {
+			var env, err = it.Next()
+			if err == io.EOF {
+				return
+			} else if !yield(env, err) || err != nil {
+				return
+			}
+		}
+	}
+}
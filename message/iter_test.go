@@ -0,0 +1,86 @@
+//go:build go1.23
+
+package message
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.gazette.dev/core/broker/client"
+	pb "go.gazette.dev/core/broker/protocol"
+	"go.gazette.dev/core/brokertest"
+	"go.gazette.dev/core/etcdtest"
+	"go.gazette.dev/core/labels"
+	"google.golang.org/grpc"
+)
+
+func TestMessagesSequence(t *testing.T) {
+	var etcd = etcdtest.TestClient()
+	defer etcdtest.Cleanup()
+
+	var (
+		framing, _ = FramingByContentType(labels.ContentType_JSONLines)
+		spec       = newTestMsgSpec("a/journal")
+		bk         = brokertest.NewBroker(t, etcd, "local", "broker")
+		ajc        = client.NewAppendService(context.Background(), bk.Client())
+		rjc        = &readRecordingClient{RoutedJournalClient: bk.Client()}
+		ctx        = context.Background()
+	)
+	brokertest.CreateJournals(t, bk, spec)
+
+	var aa = ajc.StartAppend(pb.AppendRequest{Journal: spec.Name}, nil)
+	for _, str := range []string{"one", "two", "three"} {
+		aa.Require(framing.Marshal(&testMsg{Str: str}, aa.Writer()))
+	}
+	require.NoError(t, aa.Release())
+	<-aa.Done()
+
+	// Expect all messages are ranged over, ending at the EndOffset.
+	var strs []string
+	for env, err := range Messages(ctx, rjc, pb.ReadRequest{
+		Journal:   spec.Name,
+		EndOffset: aa.Response().Commit.End,
+	}, newTestMsg) {
+		require.NoError(t, err)
+		strs = append(strs, env.Message.(*testMsg).Str)
+	}
+	require.Equal(t, []string{"one", "two", "three"}, strs)
+
+	// Break from a blocking read. Expect its Read RPC is cancelled.
+	strs, rjc.ctxs = nil, nil
+	for env, err := range Messages(ctx, rjc, pb.ReadRequest{
+		Journal: spec.Name,
+		Block:   true,
+	}, newTestMsg) {
+		require.NoError(t, err)
+		if strs = append(strs, env.Message.(*testMsg).Str); len(strs) == 2 {
+			break
+		}
+	}
+	require.Equal(t, []string{"one", "two"}, strs)
+	require.Len(t, rjc.ctxs, 1)
+	<-rjc.ctxs[0].Done()
+
+	// Expect an error is yielded, after which the sequence ends.
+	var errs []error
+	for _, err := range Messages(ctx, rjc, pb.ReadRequest{Journal: "does/not/exist"}, newTestMsg) {
+		errs = append(errs, err)
+	}
+	require.Len(t, errs, 1)
+	require.EqualError(t, errs[0], "fetching journal spec: named journal does not exist (does/not/exist)")
+
+	bk.Tasks.Cancel()
+	require.NoError(t, bk.Tasks.Wait())
+}
+
+// readRecordingClient records the Context of each Read RPC.
+type readRecordingClient struct {
+	pb.RoutedJournalClient
+	ctxs []context.Context
+}
+
+func (c *readRecordingClient) Read(ctx context.Context, req *pb.ReadRequest, opts ...grpc.CallOption) (pb.Journal_ReadClient, error) {
+	c.ctxs = append(c.ctxs, ctx)
+	return c.RoutedJournalClient.Read(ctx, req, opts...)
+}
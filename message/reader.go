@@ -30,22 +30,58 @@ func (ifn IteratorFunc) Next() (Envelope, error) { return ifn() }
 // read from the RetryReader. The reader's journal must have an appropriate
 // labels.ContentType label, which is used to determine the message Framing.
 func NewReadUncommittedIter(rr *client.RetryReader, newMsg NewMessageFunc) *ReadUncommittedIter {
-	return &ReadUncommittedIter{
+	var it = &ReadUncommittedIter{
 		rr:     rr,
-		br:     bufio.NewReader(rr),
+		lr:     lastErrReader{Reader: rr},
 		newMsg: newMsg,
 	}
+	it.br = bufio.NewReader(&it.lr)
+	return it
 }
 
 // ReadUncommittedIter is an Iterator over read-uncommitted messages.
 type ReadUncommittedIter struct {
 	rr        *client.RetryReader
+	lr        lastErrReader
 	br        *bufio.Reader
 	newMsg    NewMessageFunc
 	spec      *pb.JournalSpec
 	unmarshal UnmarshalFunc
+	timestamp TimestampFunc
+	skip      SkipFunc
 }
 
+// SkippedMessage is an undecodable message which was skipped.
+type SkippedMessage struct {
+	Journal pb.Journal
+	// Begin and End offsets of the skipped content.
+	Begin, End pb.Offset
+	// Err encountered while decoding the message.
+	Err error
+}
+
+// SkipFunc is notified of each SkippedMessage.
+type SkipFunc func(SkippedMessage)
+
+// SetTimestampFunc sets a TimestampFunc which the ReadUncommittedIter uses to
+// extract the Timestamp of each returned Envelope. Where a message has no
+// parseable timestamp, its Envelope's Timestamp is instead the time at which
+// it was read, and its TimestampSource is TimestampIngested.
+func (it *ReadUncommittedIter) SetTimestampFunc(fn TimestampFunc) { it.timestamp = fn }
+
+// SetSkipFunc sets a SkipFunc of the ReadUncommittedIter, and enables the
+// skipping of malformed messages. By default, a message which can't be decoded
+// is an error of Next. With a SkipFunc, Next instead reads past the content of
+// the message and continues with the next one, notifying the SkipFunc of the
+// skipped content and its decoding error. This trades completeness for
+// liveness: messages are read at-most-once, and a skipped message is lost.
+//
+// Only content which is genuinely malformed is skipped. A frame which is
+// incomplete because reading stopped within it (eg, an EndOffset or a
+// non-blocking read which ends mid-frame), or a decoding error which was
+// caused by an error of the underlying RetryReader, is still an error of Next.
+func (it *ReadUncommittedIter) SetSkipFunc(fn SkipFunc) { it.skip = fn }
+
 // Next reads and returns the next Envelope or error.
 func (it *ReadUncommittedIter) Next() (Envelope, error) {
 	if it.spec != nil {
@@ -62,12 +98,16 @@ func (it *ReadUncommittedIter) Next() (Envelope, error) {
 
 		switch err = it.unmarshal(msg); errors.Cause(err) {
 		case nil:
-			return Envelope{
+			var env = Envelope{
 				Journal: it.spec,
 				Begin:   begin,
 				End:     it.rr.AdjustedOffset(it.br),
 				Message: msg,
-			}, nil
+			}
+			if it.timestamp != nil {
+				stampEnvelope(&env, it.timestamp)
+			}
+			return env, nil
 
 		case io.EOF:
 			return Envelope{}, err // Don't wrap io.EOF.
@@ -94,6 +134,14 @@ func (it *ReadUncommittedIter) Next() (Envelope, error) {
 			continue
 
 		default:
+			var end = it.rr.AdjustedOffset(it.br)
+
+			if it.skip != nil && end > begin && it.lr.err == nil && errors.Cause(err) != io.ErrUnexpectedEOF {
+				// Content of the message was read without error, and it's malformed.
+				skippedMessagesTotal.WithLabelValues(it.spec.Name.String()).Inc()
+				it.skip(SkippedMessage{Journal: it.spec.Name, Begin: begin, End: end, Err: err})
+				continue
+			}
 			return Envelope{}, errors.WithMessagef(err, "framing.Unmarshal(offset %d)", begin)
 		}
 	}
@@ -117,6 +165,19 @@ func (it *ReadUncommittedIter) init() error {
 	return nil
 }
 
+// lastErrReader is an io.Reader which retains the error of its last Read.
+// It's nil if the last Read succeeded.
+type lastErrReader struct {
+	io.Reader
+	err error
+}
+
+func (r *lastErrReader) Read(p []byte) (n int, err error) {
+	n, err = r.Reader.Read(p)
+	r.err = err
+	return
+}
+
 // ReadCommittedIter is an Iterator over read-committed messages. It's little
 // more than the composition of a provided Sequencer with an underlying
 // ReadUncommittedIter.
@@ -136,6 +197,14 @@ func NewReadCommittedIter(rr *client.RetryReader, newMsg NewMessageFunc, seq *Se
 	return &ReadCommittedIter{rui: *NewReadUncommittedIter(rr, newMsg), seq: seq}
 }
 
+// SetTimestampFunc sets a TimestampFunc of the ReadCommittedIter, which is
+// applied as by ReadUncommittedIter.SetTimestampFunc (including to replays).
+func (it *ReadCommittedIter) SetTimestampFunc(fn TimestampFunc) { it.rui.timestamp = fn }
+
+// SetSkipFunc sets a SkipFunc of the ReadCommittedIter, which skips malformed
+// messages as by ReadUncommittedIter.SetSkipFunc (including of replays).
+func (it *ReadCommittedIter) SetSkipFunc(fn SkipFunc) { it.rui.skip = fn }
+
 // Next returns the next read-committed message Envelope in the sequence.
 // It returns EOF if none remain, or any other encountered error.
 func (it *ReadCommittedIter) Next() (Envelope, error) {
@@ -156,7 +225,10 @@ func (it *ReadCommittedIter) Next() (Envelope, error) {
 			req.Journal, req.Offset, req.EndOffset = it.seq.ReplayRange()
 
 			var rr = client.NewRetryReader(it.rui.rr.Context, it.rui.rr.Client, req)
-			it.seq.StartReplay(NewReadUncommittedIter(rr, it.rui.newMsg))
+			var replay = NewReadUncommittedIter(rr, it.rui.newMsg)
+			replay.timestamp = it.rui.timestamp
+			replay.skip = it.rui.skip
+			it.seq.StartReplay(replay)
 		}
 	}
 }
@@ -5,6 +5,7 @@ import (
 	"io"
 	"testing"
 
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/require"
 	"go.gazette.dev/core/broker/client"
 	pb "go.gazette.dev/core/broker/protocol"
@@ -66,6 +67,16 @@ func TestReadIterators(t *testing.T) {
 	verify([]testMsg{allMessages[1], allMessages[3], allMessages[4]}, NewReadCommittedIter(
 		client.NewRetryReader(context.Background(), bk.Client(), req), newTestMsg, seq))
 
+	// Expect a TimestampFunc stamps each read Envelope.
+	var it = NewReadCommittedIter(
+		client.NewRetryReader(context.Background(), bk.Client(), req), newTestMsg, NewSequencer(nil, nil, 0))
+	it.SetTimestampFunc(UUIDTimestamp)
+
+	env, err := it.Next()
+	require.NoError(t, err)
+	require.Equal(t, GetClock(allMessages[1].UUID).AsTime(), env.Timestamp)
+	require.Equal(t, TimestampExtracted, env.TimestampSource)
+
 	bk.Tasks.Cancel()
 	require.NoError(t, bk.Tasks.Wait())
 }
@@ -210,3 +221,71 @@ func TestReadUncommittedIterReadErrorCases(t *testing.T) {
 	_, err = r.Next()
 	require.EqualError(t, err, "framing.Unmarshal(offset 1000): unexpected EOF")
 }
+
+func TestReadUncommittedIterSkipsMalformedMessages(t *testing.T) {
+	var broker = teststub.NewBroker(t)
+	defer broker.Cleanup()
+
+	var ctx = context.Background()
+	var spec = &pb.JournalSpec{Name: "a/journal"}
+	var framing, _ = FramingByContentType(labels.ContentType_JSONLines)
+
+	go func() {
+		_ = <-broker.ReadReqCh // Read request.
+
+		broker.ReadRespCh <- pb.ReadResponse{
+			Status:    pb.Status_OK,
+			Offset:    1000,
+			WriteHead: 9999,
+			Fragment: &pb.Fragment{
+				Journal:          "a/journal",
+				Begin:            1000,
+				End:              2000,
+				CompressionCodec: pb.CompressionCodec_NONE,
+			},
+		}
+		broker.ReadRespCh <- pb.ReadResponse{
+			Offset: 1000,
+			Content: []byte(`{"Str":"abc"}` + "\n" + // 14 bytes.
+				`{not json}` + "\n" + // 11 bytes.
+				`{"Str":"def"}` + "\n" + // 14 bytes.
+				`{"Str"`), // 6 bytes, without a newline.
+		}
+		broker.WriteLoopErrCh <- nil // EOF.
+	}()
+
+	var rr = client.NewRetryReader(ctx, broker.Client(), pb.ReadRequest{
+		Journal:   "a/journal",
+		Offset:    1000,
+		EndOffset: 1000 + 45,
+	})
+	var r = NewReadUncommittedIter(rr, newTestMsg)
+	r.spec, r.unmarshal = spec, framing.NewUnmarshalFunc(r.br) // Set fixtures without running init().
+
+	var skipped []SkippedMessage
+	r.SetSkipFunc(func(s SkippedMessage) { skipped = append(skipped, s) })
+	var counter = skippedMessagesTotal.WithLabelValues("a/journal")
+	var before = testutil.ToFloat64(counter)
+
+	var env, err = r.Next()
+	require.NoError(t, err)
+	require.Equal(t, &testMsg{Str: "abc"}, env.Message)
+
+	// Expect the malformed message is skipped.
+	env, err = r.Next()
+	require.NoError(t, err)
+	require.Equal(t, &testMsg{Str: "def"}, env.Message)
+	require.Equal(t, pb.Offset(1000+25), env.Begin)
+
+	require.Len(t, skipped, 1)
+	require.Equal(t, pb.Journal("a/journal"), skipped[0].Journal)
+	require.Equal(t, pb.Offset(1000+14), skipped[0].Begin)
+	require.Equal(t, pb.Offset(1000+25), skipped[0].End)
+	require.EqualError(t, skipped[0].Err, "invalid character 'n' looking for beginning of object key string")
+	require.Equal(t, before+1, testutil.ToFloat64(counter))
+
+	// Expect a partial frame at the EndOffset is an error, and isn't skipped.
+	_, err = r.Next()
+	require.EqualError(t, err, "framing.Unmarshal(offset 1039): unexpected EOF")
+	require.Len(t, skipped, 1)
+}
@@ -0,0 +1,83 @@
+package message
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.gazette.dev/core/broker/client"
+	pb "go.gazette.dev/core/broker/protocol"
+)
+
+// TimeOffset is the offset of the first message of a journal at or after
+// a time, as returned by OffsetAt.
+type TimeOffset struct {
+	// Offset of the first message having a timestamp at or after the time,
+	// or the write head of the journal if there is none.
+	Offset pb.Offset
+	// Approximate is true if Offset is instead the Fragment boundary located
+	// by client.FragmentOffsetAt, because messages of the journal don't have
+	// timestamps, or is the offset at which a scan of messages before the time
+	// was stopped (see offsetAtMaxScan). Messages at or after an approximate
+	// Offset may have been written before the time, but no message written at
+	// or after the time is before it.
+	Approximate bool
+}
+
+// offsetAtMaxScan bounds the bytes of messages read by OffsetAt, as measured
+// from the located Fragment's beginning.
+var offsetAtMaxScan int64 = 1 << 30
+
+// OffsetAt resolves the offset of the first message of the journal having a
+// timestamp at or after |t|, such as for beginning a read or replay of the
+// journal at a point in time. It locates the Fragment which may first hold
+// messages written at or after |t| using client.FragmentOffsetAt, and then
+// reads messages from the Fragment's beginning, through the journal write
+// head, until it finds a message with a timestamp of at least |t|.
+// Acknowledgement messages are passed over. Where a message isn't found within
+// offsetAtMaxScan bytes, the offset following the last read message is
+// returned as an Approximate offset.
+//
+// If a read message has no timestamp, as determined by the TimestampFunc,
+// then OffsetAt returns the Fragment's beginning as an Approximate offset.
+// TimestampFuncs which extract a message's event time, rather than the time
+// it was written, are appropriate only where the two are closely related:
+// the search of Fragments is by write time, and messages of earlier
+// Fragments which have later event times are not considered.
+func OffsetAt(ctx context.Context, rjc pb.RoutedJournalClient, journal pb.Journal, t time.Time,
+	newMsg NewMessageFunc, fn TimestampFunc) (TimeOffset, error) {
+
+	var begin, err = client.FragmentOffsetAt(ctx, rjc, journal, t)
+	if err != nil {
+		return TimeOffset{}, errors.WithMessage(err, "locating fragment")
+	}
+
+	var rr = client.NewRetryReader(ctx, rjc, pb.ReadRequest{
+		Journal: journal,
+		Offset:  begin,
+	})
+	rr.Reader.ReadToHead = true
+	defer rr.Cancel()
+
+	var it = NewReadUncommittedIter(rr, newMsg)
+	for {
+		var env, err = it.Next()
+
+		if err == io.EOF {
+			return TimeOffset{Offset: rr.Offset()}, nil // Read through the write head.
+		} else if err != nil {
+			return TimeOffset{}, err
+		} else if GetFlags(env.GetUUID()) == Flag_ACK_TXN {
+			continue
+		}
+
+		if ts, err := fn(env); err != nil || ts.IsZero() {
+			return TimeOffset{Offset: begin, Approximate: true}, nil
+		} else if !ts.Before(t) {
+			return TimeOffset{Offset: env.Begin}, nil
+		} else if env.End-begin >= offsetAtMaxScan {
+			return TimeOffset{Offset: env.End, Approximate: true}, nil
+		}
+	}
+}
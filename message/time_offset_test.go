@@ -0,0 +1,89 @@
+package message
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.gazette.dev/core/broker/client"
+	pb "go.gazette.dev/core/broker/protocol"
+	"go.gazette.dev/core/brokertest"
+	"go.gazette.dev/core/etcdtest"
+	"go.gazette.dev/core/labels"
+)
+
+func TestOffsetAt(t *testing.T) {
+	var etcd = etcdtest.TestClient()
+	defer etcdtest.Cleanup()
+
+	var (
+		framing, _ = FramingByContentType(labels.ContentType_JSONLines)
+		spec       = newTestMsgSpec("a/journal")
+		bk         = brokertest.NewBroker(t, etcd, "local", "broker")
+		ajc        = client.NewAppendService(context.Background(), bk.Client())
+		ctx        = context.Background()
+		producer   = NewProducerID()
+		t0         = time.Unix(1600000000, 0)
+	)
+	brokertest.CreateJournals(t, bk, spec)
+
+	var write = func(msg *testMsg) (begin, end pb.Offset) {
+		var aa = ajc.StartAppend(pb.AppendRequest{Journal: spec.Name}, nil)
+		aa.Require(framing.Marshal(msg, aa.Writer()))
+		require.NoError(t, aa.Release())
+		require.NoError(t, aa.Err())
+		return aa.Response().Commit.Begin, aa.Response().Commit.End
+	}
+	var at = func(d time.Duration, flags Flags) *testMsg {
+		return &testMsg{UUID: BuildUUID(producer, NewClock(t0.Add(d)), flags), Str: d.String()}
+	}
+
+	write(at(0, Flag_CONTINUE_TXN))
+	var second, third = write(at(time.Minute, Flag_CONTINUE_TXN))
+	write(at(2*time.Minute, Flag_ACK_TXN))
+	var fourth, head = write(at(3*time.Minute, Flag_OUTSIDE_TXN))
+
+	for _, tc := range []struct {
+		t      time.Time
+		expect TimeOffset
+	}{
+		{t0.Add(-time.Hour), TimeOffset{Offset: 0}},
+		{t0.Add(30 * time.Second), TimeOffset{Offset: second}},
+		{t0.Add(time.Minute), TimeOffset{Offset: second}},
+		// Expect the acknowledgement is passed over.
+		{t0.Add(90 * time.Second), TimeOffset{Offset: fourth}},
+		// No message is at or after the time. Expect the write head.
+		{t0.Add(time.Hour), TimeOffset{Offset: head}},
+	} {
+		var out, err = OffsetAt(ctx, bk.Client(), spec.Name, tc.t, newTestMsg, UUIDTimestamp)
+		require.NoError(t, err)
+		require.Equal(t, tc.expect, out)
+	}
+
+	// Case: the scan of messages is bounded. Expect the offset through which
+	// messages were read is returned as an approximation.
+	var maxScan = offsetAtMaxScan
+	offsetAtMaxScan = second + 1
+
+	var out, err = OffsetAt(ctx, bk.Client(), spec.Name, t0.Add(time.Hour), newTestMsg, UUIDTimestamp)
+	require.NoError(t, err)
+	require.Equal(t, TimeOffset{Offset: third, Approximate: true}, out)
+
+	offsetAtMaxScan = maxScan
+
+	// Append a message without a UUID, which has no timestamp.
+	// Expect the Fragment boundary is returned as an approximation.
+	write(&testMsg{Str: "no timestamp"})
+
+	out, err = OffsetAt(ctx, bk.Client(), spec.Name, t0.Add(time.Hour), newTestMsg, UUIDTimestamp)
+	require.NoError(t, err)
+	require.Equal(t, TimeOffset{Offset: 0, Approximate: true}, out)
+
+	// Case: the journal doesn't exist.
+	_, err = OffsetAt(ctx, bk.Client(), "does/not/exist", t0, newTestMsg, UUIDTimestamp)
+	require.EqualError(t, err, "locating fragment: "+client.ErrJournalNotFound.Error())
+
+	bk.Tasks.Cancel()
+	require.NoError(t, bk.Tasks.Wait())
+}
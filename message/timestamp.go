@@ -0,0 +1,237 @@
+package message
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gogo/protobuf/types"
+)
+
+// TimestampFunc extracts the timestamp of a message, such as its event time.
+// It returns an error if the message has no parseable timestamp.
+type TimestampFunc func(Envelope) (time.Time, error)
+
+// TimestampSource describes the source of an Envelope's Timestamp.
+type TimestampSource int
+
+const (
+	// TimestampUnset is the TimestampSource of an Envelope read without a
+	// TimestampFunc, which has a zero-valued Timestamp.
+	TimestampUnset TimestampSource = iota
+	// TimestampExtracted is the TimestampSource of an Envelope whose Timestamp
+	// was extracted from its message by a TimestampFunc.
+	TimestampExtracted
+	// TimestampIngested is the TimestampSource of an Envelope whose message had
+	// no parseable timestamp. Its Timestamp is instead the time at which the
+	// message was read.
+	TimestampIngested
+)
+
+func (s TimestampSource) String() string {
+	switch s {
+	case TimestampUnset:
+		return "unset"
+	case TimestampExtracted:
+		return "extracted"
+	case TimestampIngested:
+		return "ingested"
+	default:
+		return fmt.Sprintf("TimestampSource(%d)", int(s))
+	}
+}
+
+// UUIDTimestamp is a TimestampFunc which returns the publishing time encoded
+// in the Clock of the message UUID header. It errors if the message has no UUID.
+func UUIDTimestamp(env Envelope) (time.Time, error) {
+	var clock = GetClock(env.GetUUID())
+	if clock == 0 {
+		return time.Time{}, fmt.Errorf("message has no UUID")
+	}
+	return clock.AsTime(), nil
+}
+
+// JSONTimestamp returns a TimestampFunc which extracts a timestamp from a
+// message at the dot-separated JSON property |path| (eg, "meta.eventTime"),
+// where properties are named as they would be encoded by "encoding/json".
+// A time.Time value is returned as-is, a string value is parsed as an RFC 3339
+// timestamp, and a numeric value is taken as (possibly fractional) seconds
+// since the Unix epoch.
+//
+// The message isn't encoded. Instead the path is walked directly, through
+// struct fields (which are resolved once per message type) and through maps
+// having string keys.
+func JSONTimestamp(path string) TimestampFunc {
+	var parts = strings.Split(path, ".")
+	var fields sync.Map // jsonFieldKey => []int, or nil if not found.
+
+	var fieldIndex = func(t reflect.Type, name string) []int {
+		var key = jsonFieldKey{t: t, name: name}
+		if ind, ok := fields.Load(key); ok {
+			return ind.([]int)
+		}
+		var ind = jsonFieldIndex(t, name)
+		fields.Store(key, ind)
+		return ind
+	}
+
+	return func(env Envelope) (time.Time, error) {
+		var v = reflect.ValueOf(env.Message)
+
+		for _, part := range parts {
+			for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+				v = v.Elem()
+			}
+			switch v.Kind() {
+			case reflect.Struct:
+				if ind := fieldIndex(v.Type(), part); ind != nil {
+					v = v.FieldByIndex(ind)
+				} else {
+					return time.Time{}, fmt.Errorf("JSON path %q not found", path)
+				}
+			case reflect.Map:
+				if v.Type().Key().Kind() != reflect.String {
+					return time.Time{}, fmt.Errorf("JSON path %q not found", path)
+				} else if v = v.MapIndex(reflect.ValueOf(part).Convert(v.Type().Key())); !v.IsValid() {
+					return time.Time{}, fmt.Errorf("JSON path %q not found", path)
+				}
+			default:
+				return time.Time{}, fmt.Errorf("JSON path %q not found", path)
+			}
+		}
+		for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+			if v.IsNil() {
+				return time.Time{}, fmt.Errorf("JSON path %q not found", path)
+			}
+			v = v.Elem()
+		}
+
+		switch v.Kind() {
+		case reflect.String:
+			return time.Parse(time.RFC3339Nano, v.String())
+		case reflect.Float32, reflect.Float64:
+			var sec, frac = math.Modf(v.Float())
+			return time.Unix(int64(sec), int64(frac*1e9)).UTC(), nil
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			return time.Unix(v.Int(), 0).UTC(), nil
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			return time.Unix(int64(v.Uint()), 0).UTC(), nil
+		}
+		if v.CanInterface() {
+			if ts, ok := v.Interface().(time.Time); ok {
+				return ts, nil
+			}
+		}
+		return time.Time{}, fmt.Errorf("JSON path %q has unexpected value %v", path, v)
+	}
+}
+
+type jsonFieldKey struct {
+	t    reflect.Type
+	name string
+}
+
+// jsonFieldIndex returns the index of the field of struct type |t| which is
+// encoded by "encoding/json" as property |name|, or nil if there is none.
+// Fields of embedded structs are promoted, unless shadowed.
+func jsonFieldIndex(t reflect.Type, name string) []int {
+	var embedded []int
+
+	for i := 0; i != t.NumField(); i++ {
+		var f = t.Field(i)
+		var tag = strings.Split(f.Tag.Get("json"), ",")[0]
+
+		if tag == "-" || (f.PkgPath != "" && !f.Anonymous) {
+			continue // Ignored, or unexported.
+		} else if tag == name || (tag == "" && !f.Anonymous && f.Name == name) {
+			return []int{i}
+		} else if tag == "" && f.Anonymous && f.Type.Kind() == reflect.Struct {
+			embedded = append(embedded, i)
+		}
+	}
+	for _, i := range embedded {
+		if ind := jsonFieldIndex(t.Field(i).Type, name); ind != nil {
+			return append([]int{i}, ind...)
+		}
+	}
+	return nil
+}
+
+// ProtoTimestamp returns a TimestampFunc which extracts a timestamp from a
+// field of a message generated by the gogo/protobuf compiler. |field| is the
+// field name as defined by its .proto file. The field may be a
+// google.protobuf.Timestamp (with or without the gogoproto.stdtime option),
+// or an integer of seconds since the Unix epoch. The struct field is
+// resolved once per message type.
+func ProtoTimestamp(field string) TimestampFunc {
+	var tag = "name=" + field
+	var fields sync.Map // reflect.Type => int, or -1 if not found.
+
+	var fieldIndex = func(t reflect.Type) int {
+		if ind, ok := fields.Load(t); ok {
+			return ind.(int)
+		}
+		var ind = -1
+		for i := 0; i != t.NumField() && ind == -1; i++ {
+			for _, p := range strings.Split(t.Field(i).Tag.Get("protobuf"), ",") {
+				if p == tag {
+					ind = i
+				}
+			}
+		}
+		fields.Store(t, ind)
+		return ind
+	}
+
+	return func(env Envelope) (time.Time, error) {
+		var v = reflect.Indirect(reflect.ValueOf(env.Message))
+		if v.Kind() != reflect.Struct {
+			return time.Time{}, fmt.Errorf("message %T is not a struct", env.Message)
+		}
+		var ind = fieldIndex(v.Type())
+		if ind == -1 {
+			return time.Time{}, fmt.Errorf("message %T has no field %s", env.Message, field)
+		}
+
+		switch f := v.Field(ind).Interface().(type) {
+		case time.Time:
+			if f.IsZero() {
+				return time.Time{}, fmt.Errorf("field %s is not set", field)
+			}
+			return f, nil
+		case *time.Time:
+			if f == nil {
+				return time.Time{}, fmt.Errorf("field %s is not set", field)
+			}
+			return *f, nil
+		case types.Timestamp:
+			return types.TimestampFromProto(&f)
+		case *types.Timestamp:
+			if f == nil {
+				return time.Time{}, fmt.Errorf("field %s is not set", field)
+			}
+			return types.TimestampFromProto(f)
+		case int64:
+			return time.Unix(f, 0).UTC(), nil
+		case uint64:
+			return time.Unix(int64(f), 0).UTC(), nil
+		default:
+			return time.Time{}, fmt.Errorf("field %s has unexpected type %T", field, f)
+		}
+	}
+}
+
+// stampEnvelope sets the Timestamp of the Envelope using |fn|, falling back
+// to the current time if the message has no parseable timestamp.
+func stampEnvelope(env *Envelope, fn TimestampFunc) {
+	if ts, err := fn(*env); err == nil && !ts.IsZero() {
+		env.Timestamp, env.TimestampSource = ts, TimestampExtracted
+	} else {
+		env.Timestamp, env.TimestampSource = timeNow(), TimestampIngested
+	}
+}
+
+var timeNow = time.Now
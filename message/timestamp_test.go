@@ -0,0 +1,129 @@
+package message
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gogo/protobuf/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUUIDTimestamp(t *testing.T) {
+	var ts = time.Unix(1600000000, 123400).UTC()
+	var env = Envelope{Message: &testMsg{UUID: BuildUUID(NewProducerID(), NewClock(ts), Flag_OUTSIDE_TXN)}}
+
+	var out, err = UUIDTimestamp(env)
+	require.NoError(t, err)
+	require.Equal(t, ts, out.UTC())
+
+	// Case: message has no UUID.
+	_, err = UUIDTimestamp(Envelope{Message: &testMsg{}})
+	require.EqualError(t, err, "message has no UUID")
+}
+
+func TestJSONTimestamp(t *testing.T) {
+	type doc struct {
+		testMsg
+		Meta map[string]interface{}
+	}
+	var env = func(v interface{}) Envelope {
+		return Envelope{Message: &doc{Meta: map[string]interface{}{"ts": v}}}
+	}
+	var fn = JSONTimestamp("Meta.ts")
+
+	var out, err = fn(env("2020-09-13T12:26:40.5Z"))
+	require.NoError(t, err)
+	require.Equal(t, time.Unix(1600000000, 5e8).UTC(), out)
+
+	out, err = fn(env(1600000000.25))
+	require.NoError(t, err)
+	require.Equal(t, time.Unix(1600000000, 25e7).UTC(), out)
+
+	// Error cases.
+	_, err = fn(env("yesterday"))
+	require.Error(t, err)
+	_, err = fn(env(true))
+	require.EqualError(t, err, `JSON path "Meta.ts" has unexpected value true`)
+	_, err = JSONTimestamp("Meta.other")(env(1))
+	require.EqualError(t, err, `JSON path "Meta.other" not found`)
+	_, err = JSONTimestamp("Str.ts")(env(1))
+	require.EqualError(t, err, `JSON path "Str.ts" not found`)
+
+	// Struct fields are walked by their JSON property names.
+	type inner struct {
+		At    time.Time `json:"at"`
+		Epoch int64     `json:"epoch,omitempty"`
+		Skip  int64     `json:"-"`
+	}
+	type tagged struct {
+		testMsg
+		Inner *inner `json:"inner"`
+	}
+	var ts = time.Unix(1600000000, 500).UTC()
+	var tenv = Envelope{Message: &tagged{Inner: &inner{At: ts, Epoch: 1600000000, Skip: 1}}}
+
+	out, err = JSONTimestamp("inner.at")(tenv)
+	require.NoError(t, err)
+	require.Equal(t, ts, out)
+	out, err = JSONTimestamp("inner.epoch")(tenv)
+	require.NoError(t, err)
+	require.Equal(t, time.Unix(1600000000, 0).UTC(), out)
+
+	_, err = JSONTimestamp("Inner.at")(tenv)
+	require.EqualError(t, err, `JSON path "Inner.at" not found`)
+	_, err = JSONTimestamp("inner.Skip")(tenv)
+	require.EqualError(t, err, `JSON path "inner.Skip" not found`)
+	_, err = JSONTimestamp("inner.at")(Envelope{Message: &tagged{}})
+	require.EqualError(t, err, `JSON path "inner.at" not found`)
+}
+
+func TestProtoTimestamp(t *testing.T) {
+	type protoMsg struct {
+		testMsg
+		Std   time.Time        `protobuf:"bytes,1,opt,name=std,proto3,stdtime"`
+		Ptr   *types.Timestamp `protobuf:"bytes,2,opt,name=ptr,proto3"`
+		Epoch int64            `protobuf:"varint,3,opt,name=epoch,proto3"`
+		Str   string           `protobuf:"bytes,4,opt,name=str,proto3"`
+	}
+	var ts = time.Unix(1600000000, 500).UTC()
+	var pts, _ = types.TimestampProto(ts)
+	var env = Envelope{Message: &protoMsg{Std: ts, Ptr: pts, Epoch: 1600000000, Str: "foo"}}
+
+	for _, field := range []string{"std", "ptr"} {
+		var out, err = ProtoTimestamp(field)(env)
+		require.NoError(t, err)
+		require.Equal(t, ts, out.UTC())
+	}
+	var out, err = ProtoTimestamp("epoch")(env)
+	require.NoError(t, err)
+	require.Equal(t, time.Unix(1600000000, 0).UTC(), out)
+
+	// Error cases.
+	_, err = ProtoTimestamp("str")(env)
+	require.EqualError(t, err, "field str has unexpected type string")
+	_, err = ProtoTimestamp("missing")(env)
+	require.EqualError(t, err, "message *message.protoMsg has no field missing")
+	_, err = ProtoTimestamp("ptr")(Envelope{Message: &protoMsg{}})
+	require.EqualError(t, err, "field ptr is not set")
+	_, err = ProtoTimestamp("std")(Envelope{Message: &protoMsg{}})
+	require.EqualError(t, err, "field std is not set")
+}
+
+func TestStampEnvelopeFallsBackToIngestionTime(t *testing.T) {
+	defer func(fn func() time.Time) { timeNow = fn }(timeNow)
+	var now = time.Unix(1700000000, 0)
+	timeNow = func() time.Time { return now }
+
+	var ts = time.Unix(1600000000, 0)
+	var env = Envelope{Message: &testMsg{UUID: BuildUUID(NewProducerID(), NewClock(ts), Flag_OUTSIDE_TXN)}}
+
+	stampEnvelope(&env, UUIDTimestamp)
+	require.Equal(t, ts, env.Timestamp.Local())
+	require.Equal(t, TimestampExtracted, env.TimestampSource)
+
+	env = Envelope{Message: &testMsg{}}
+	stampEnvelope(&env, UUIDTimestamp)
+	require.Equal(t, now, env.Timestamp)
+	require.Equal(t, TimestampIngested, env.TimestampSource)
+	require.Equal(t, "ingested", env.TimestampSource.String())
+}
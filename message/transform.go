@@ -0,0 +1,189 @@
+package message
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+
+	"go.gazette.dev/core/broker/client"
+	pb "go.gazette.dev/core/broker/protocol"
+)
+
+// TransformArgs are arguments of AppendTransformed.
+type TransformArgs struct {
+	// Journal to which transformed records are appended.
+	Journal pb.Journal
+	// From is the Framing of records read from the io.Reader.
+	From Framing
+	// To is the Framing with which records are encoded for appending.
+	To Framing
+	// New returns a Frameable into which each record is decoded under the
+	// From Framing, and which is then encoded under the To Framing. It must
+	// be suitable for use with both Framings. For example, a transform of
+	// JSON-lines to fixed protobuf frames could use a generated protobuf
+	// message type, which is also a json.Unmarshaler.
+	New func() Frameable
+	// DeadLetter is an optional journal to which records which fail to
+	// transform are appended verbatim. If empty, a record which fails to
+	// transform instead fails AppendTransformed.
+	DeadLetter pb.Journal
+}
+
+// AppendTransformed reads records of Framing TransformArgs.From from the
+// io.Reader, re-encodes each with Framing TransformArgs.To, and appends them
+// to TransformArgs.Journal. This centralizes conversion of records into a
+// representation which is efficient for downstream readers, rather than
+// having each reader decode and re-encode them. For example, it may be used to
+// ingest newline-delimited JSON which is stored as fixed protobuf frames.
+//
+// Records which cannot be decoded or encoded (eg, because they're malformed)
+// are appended verbatim to the TransformArgs.DeadLetter journal, if set.
+// Other errors, such as a failure to read from the io.Reader, or a malformed
+// record if DeadLetter is not set, fail AppendTransformed. If an error is
+// returned, records preceding the failure may have already been appended.
+//
+// Like client.AppendReader, records are streamed into a sequence of
+// AsyncAppends of the AsyncJournalClient, and records are never split across
+// Append RPCs. AppendTransformed blocks until all records have committed, and
+// returns the offset of TransformArgs.Journal through which its final append
+// committed.
+func AppendTransformed(ctx context.Context, ajc client.AsyncJournalClient, args TransformArgs, r io.Reader) (pb.Offset, error) {
+	if args.DeadLetter != "" && args.DeadLetter == args.Journal {
+		return 0, fmt.Errorf("DeadLetter cannot be the same journal as Journal (%s)", args.Journal)
+	}
+
+	var rr = &recordingReader{r: r}
+	var br = bufio.NewReader(rr)
+	var unmarshal = args.From.NewUnmarshalFunc(br)
+
+	var scratch bytes.Buffer
+	var bw = bufio.NewWriter(&scratch)
+
+	var aa, last, dead, lastDead *client.AsyncAppend
+	var size int
+
+	// release |aa| and |dead|, retaining them as |last| and |lastDead|.
+	var release = func(err error) error {
+		if aa != nil {
+			if err = aa.Require(err).Release(); err != nil {
+				return err
+			}
+			aa, last, size = nil, aa, 0
+		}
+		if dead != nil {
+			if err = dead.Require(err).Release(); err != nil {
+				return err
+			}
+			dead, lastDead = nil, dead
+		}
+		return err
+	}
+
+	for {
+		var frame = args.New()
+		var err = unmarshal(frame)
+		var raw = rr.consume(br.Buffered())
+
+		if err == io.EOF && len(raw) == 0 {
+			break // Clean end of input.
+		} else if err == nil {
+			// Encode into |scratch|, so that a failed encoding doesn't leave a
+			// partial record in the append.
+			scratch.Reset()
+			bw.Reset(&scratch)
+
+			if err = args.To.Marshal(frame, bw); err == nil {
+				err = bw.Flush()
+			}
+		}
+
+		if err != nil {
+			if len(raw) == 0 || rr.err != nil || args.DeadLetter == "" {
+				// We can't make progress past this record. Roll back any
+				// records not yet released.
+				return 0, release(fmt.Errorf("transforming record: %w", err))
+			}
+			if dead == nil {
+				dead = ajc.StartAppend(pb.AppendRequest{Journal: args.DeadLetter}, nil)
+			}
+			_, _ = dead.Writer().Write(raw) // Release checks for errors.
+			continue
+		}
+
+		if aa == nil {
+			aa = ajc.StartAppend(pb.AppendRequest{Journal: args.Journal}, nil)
+		}
+		_, _ = aa.Writer().Write(scratch.Bytes()) // Release checks for errors.
+		size += scratch.Len()
+
+		if size >= transformChunkSize {
+			if err = release(nil); err != nil {
+				return 0, err
+			} else if err = ctx.Err(); err != nil {
+				return 0, err
+			}
+		}
+	}
+
+	if err := release(nil); err != nil {
+		return 0, err
+	}
+	// Appends of a journal are ordered and later appends fail if
+	// prior ones did, so it's sufficient to wait for just the last.
+	for _, op := range []*client.AsyncAppend{lastDead, last} {
+		if op == nil {
+			continue
+		}
+		select {
+		case <-op.Done():
+		case <-ctx.Done():
+			return 0, ctx.Err()
+		}
+		if err := op.Err(); err != nil {
+			return 0, err
+		}
+	}
+
+	if last == nil {
+		return 0, nil // No records were appended.
+	}
+	return last.Response().Commit.End, nil
+}
+
+// recordingReader records bytes read from its io.Reader, allowing the raw
+// bytes of each record read through a bufio.Reader to be recovered.
+type recordingReader struct {
+	r     io.Reader
+	err   error  // Non-EOF error returned by |r|, if any.
+	buf   []byte // Bytes read from |r| and not yet consumed.
+	begin int    // Offset of |buf| at which the next record begins.
+}
+
+func (rr *recordingReader) Read(p []byte) (int, error) {
+	// Discard bytes of consumed records.
+	if rr.begin != 0 {
+		rr.buf = rr.buf[:copy(rr.buf, rr.buf[rr.begin:])]
+		rr.begin = 0
+	}
+	var n, err = rr.r.Read(p)
+	rr.buf = append(rr.buf, p[:n]...)
+
+	if err != nil && err != io.EOF {
+		rr.err = err
+	}
+	return n, err
+}
+
+// consume returns the raw bytes of the record just read, given the number
+// of |buffered| bytes which the bufio.Reader has read but not yet returned.
+// The returned slice is invalidated by the next Read.
+func (rr *recordingReader) consume(buffered int) []byte {
+	var end = len(rr.buf) - buffered
+	var raw = rr.buf[rr.begin:end]
+	rr.begin = end
+	return raw
+}
+
+var transformChunkSize = 1 << 20 // 1MB.
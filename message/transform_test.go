@@ -0,0 +1,102 @@
+package message
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"io/ioutil"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/stretchr/testify/require"
+	"go.gazette.dev/core/broker/client"
+	pb "go.gazette.dev/core/broker/protocol"
+	"go.gazette.dev/core/brokertest"
+	"go.gazette.dev/core/etcdtest"
+	"go.gazette.dev/core/labels"
+)
+
+func TestAppendTransformed(t *testing.T) {
+	var etcd = etcdtest.TestClient()
+	defer etcdtest.Cleanup()
+
+	var (
+		bk   = brokertest.NewBroker(t, etcd, "local", "broker")
+		ajc  = client.NewAppendService(context.Background(), bk.Client())
+		from = newTestMsgSpec("a/journal")
+		dead = newTestMsgSpec("a/dead-letters")
+		args = TransformArgs{
+			Journal: "a/journal",
+			From:    mustFraming(t, labels.ContentType_JSONLines),
+			To:      mustFraming(t, labels.ContentType_ProtoFixed),
+			New:     func() Frameable { return new(pb.Fragment) },
+		}
+	)
+	brokertest.CreateJournals(t, bk, from, dead)
+
+	const fixture = `{"journal": "one", "begin": 1, "end": 2}
+not valid JSON
+{"journal": "two", "begin": 3, "end": 4}
+{"journal": "three", "begin": 5, "end": 6, "sum": "wrong type"}
+{"journal": "four", "begin": 7, "end": 8}
+`
+	// Case: without a DeadLetter journal, a malformed record fails the append.
+	var _, err = AppendTransformed(context.Background(), ajc, args, strings.NewReader(fixture))
+	require.Regexp(t, "transforming record: invalid character .*", err)
+
+	// Case: with a DeadLetter journal, malformed records are routed to it.
+	args.DeadLetter = "a/dead-letters"
+	offset, err := AppendTransformed(context.Background(), ajc, args, strings.NewReader(fixture))
+	require.NoError(t, err)
+
+	// Expect the first case appended no records.
+	var content = readAllContent(t, bk, "a/journal")
+	require.Equal(t, int64(len(content)), offset)
+
+	var br = bufio.NewReader(bytes.NewReader(content))
+	var unmarshal = args.To.NewUnmarshalFunc(br)
+
+	for _, expect := range []pb.Fragment{
+		{Journal: "one", Begin: 1, End: 2},
+		{Journal: "two", Begin: 3, End: 4},
+		{Journal: "four", Begin: 7, End: 8},
+	} {
+		var frag pb.Fragment
+		require.NoError(t, unmarshal(&frag))
+		require.Equal(t, expect, frag)
+	}
+	_, err = br.Peek(1)
+	require.Error(t, err) // Fully consumed.
+
+	require.Equal(t, "not valid JSON\n"+
+		`{"journal": "three", "begin": 5, "end": 6, "sum": "wrong type"}`+"\n",
+		string(readAllContent(t, bk, "a/dead-letters")))
+
+	// Case: a read error fails the append, even with a DeadLetter journal.
+	_, err = AppendTransformed(context.Background(), ajc, args,
+		iotest.ErrReader(errors.New("whoops")))
+	require.EqualError(t, err, "transforming record: whoops")
+
+	// Case: DeadLetter may not be Journal.
+	args.DeadLetter = args.Journal
+	_, err = AppendTransformed(context.Background(), ajc, args, strings.NewReader(fixture))
+	require.EqualError(t, err, "DeadLetter cannot be the same journal as Journal (a/journal)")
+
+	bk.Tasks.Cancel()
+	require.NoError(t, bk.Tasks.Wait())
+}
+
+func mustFraming(t *testing.T, contentType string) Framing {
+	var f, err = FramingByContentType(contentType)
+	require.NoError(t, err)
+	return f
+}
+
+func readAllContent(t *testing.T, bk *brokertest.Broker, journal pb.Journal) []byte {
+	var r = client.NewReader(context.Background(), bk.Client(), pb.ReadRequest{Journal: journal})
+	var b, err = ioutil.ReadAll(r)
+	require.Equal(t, client.ErrOffsetNotYetAvailable, err)
+	return b
+}
@@ -0,0 +1,210 @@
+package message
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.gazette.dev/core/broker/client"
+	pb "go.gazette.dev/core/broker/protocol"
+)
+
+// UnionIter is an Iterator of read-uncommitted messages of a dynamic set of
+// journals, such as those matching a label selector, which are read as a
+// single logical stream. Messages of each journal are returned in order,
+// but messages of different journals are returned as they're read, without
+// any ordering between them. Each Envelope's Journal is its source journal.
+//
+// The set of journals is that of a client.PolledList, and UnionIter begins
+// and cancels reads as journals are added to and removed from the list.
+// UnionIter tracks the offset through which messages of each journal have
+// been returned (see Checkpoint), and a UnionIter begun from a prior
+// Checkpoint resumes reading with the next message of each journal.
+type UnionIter struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	rjc    pb.RoutedJournalClient
+	list   *client.PolledList
+	newMsg NewMessageFunc
+
+	readers    map[pb.Journal]*unionReader // Current reader of each listed journal.
+	checkpoint pb.Offsets                  // Offsets through which messages were returned.
+	readCh     chan unionRead              // Reads of all readers.
+}
+
+// unionReader is a reader of a journal of a UnionIter.
+type unionReader struct {
+	cancel context.CancelFunc
+}
+
+// unionRead is an Envelope or error read by a unionReader.
+type unionRead struct {
+	reader  *unionReader
+	journal pb.Journal
+	env     Envelope
+	err     error
+}
+
+// NewUnionIter returns a UnionIter of the journals of the PolledList, which
+// begins reading each journal from its offset of the |checkpoint| (or from
+// its beginning, if it's not in the |checkpoint|).
+func NewUnionIter(ctx context.Context, rjc pb.RoutedJournalClient, list *client.PolledList,
+	checkpoint pb.Offsets, newMsg NewMessageFunc) *UnionIter {
+
+	var it = &UnionIter{
+		rjc:        rjc,
+		list:       list,
+		newMsg:     newMsg,
+		readers:    make(map[pb.Journal]*unionReader),
+		checkpoint: checkpoint.Copy(),
+		readCh:     make(chan unionRead),
+	}
+	if it.checkpoint == nil {
+		it.checkpoint = make(pb.Offsets)
+	}
+	it.ctx, it.cancel = context.WithCancel(ctx)
+	it.updateReaders()
+
+	return it
+}
+
+// Next returns the next message Envelope of any journal. An error of a
+// journal's read is returned with the journal's name, and the journal's read
+// is then restarted after a backoff, from the offset following its last
+// returned message. Next returns the Context error if the UnionIter's
+// Context is cancelled or the UnionIter is closed.
+func (it *UnionIter) Next() (Envelope, error) {
+	for {
+		if err := it.ctx.Err(); err != nil {
+			return Envelope{}, err
+		}
+		// Prefer to apply an update of the list, so that reads of
+		// removed journals are promptly cancelled.
+		select {
+		case <-it.list.UpdateCh():
+			it.updateReaders()
+		default:
+		}
+
+		select {
+		case <-it.list.UpdateCh():
+			it.updateReaders()
+
+		case r := <-it.readCh:
+			if it.readers[r.journal] != r.reader {
+				continue // Read of a journal which was removed.
+			} else if r.err != nil {
+				return Envelope{}, r.err
+			}
+			it.checkpoint[r.journal] = r.env.End
+			return r.env, nil
+
+		case <-it.ctx.Done():
+			return Envelope{}, it.ctx.Err()
+		}
+	}
+}
+
+// Checkpoint returns the offset of each journal through which its messages
+// have been returned by Next. It includes journals which have since been
+// removed from the set, so that a journal which is later re-added resumes
+// from its prior offset.
+func (it *UnionIter) Checkpoint() pb.Offsets { return it.checkpoint.Copy() }
+
+// Close the UnionIter, cancelling reads of all journals.
+func (it *UnionIter) Close() { it.cancel() }
+
+// updateReaders starts a read of each listed journal not already being read,
+// and cancels reads of journals which are no longer listed.
+func (it *UnionIter) updateReaders() {
+	var listed = make(map[pb.Journal]struct{})
+
+	for _, j := range it.list.List().Journals {
+		var name = j.Spec.Name
+		listed[name] = struct{}{}
+
+		if _, ok := it.readers[name]; ok {
+			continue
+		}
+		var ctx, cancel = context.WithCancel(it.ctx)
+		var r = &unionReader{cancel: cancel}
+		it.readers[name] = r
+
+		go it.read(ctx, r, pb.ReadRequest{
+			Journal: name,
+			Offset:  it.checkpoint[name],
+			Block:   true,
+		})
+	}
+	for name, r := range it.readers {
+		if _, ok := listed[name]; !ok {
+			r.cancel()
+			delete(it.readers, name)
+		}
+	}
+}
+
+// read messages of the ReadRequest until |ctx| is cancelled, sending each to
+// the UnionIter. An encountered error is also sent, and the read is restarted
+// after a backoff from the offset following the last sent message.
+func (it *UnionIter) read(ctx context.Context, r *unionReader, req pb.ReadRequest) {
+	for attempt := 0; ; attempt++ {
+		var offset, err = it.readUntilError(ctx, r, req)
+
+		if ctx.Err() != nil {
+			return // Cancelled.
+		} else if offset != req.Offset {
+			attempt, req.Offset = 0, offset // Messages were read before the error.
+		}
+
+		select {
+		case it.readCh <- unionRead{reader: r, journal: req.Journal, err: err}:
+		case <-ctx.Done():
+			return
+		}
+
+		select {
+		case <-time.After(unionBackoff(attempt)):
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// readUntilError sends messages of the ReadRequest to the UnionIter until an
+// error is encountered, returning it with the offset through which messages
+// were sent.
+func (it *UnionIter) readUntilError(ctx context.Context, r *unionReader, req pb.ReadRequest) (pb.Offset, error) {
+	var rr = client.NewRetryReader(ctx, it.rjc, req)
+	defer rr.Cancel()
+
+	var ri = NewReadUncommittedIter(rr, it.newMsg)
+	for {
+		var env, err = ri.Next()
+		if err != nil {
+			return req.Offset, fmt.Errorf("reading journal %s: %w", req.Journal, err)
+		}
+
+		select {
+		case it.readCh <- unionRead{reader: r, journal: req.Journal, env: env}:
+			req.Offset = env.End
+		case <-ctx.Done():
+			return req.Offset, ctx.Err()
+		}
+	}
+}
+
+// unionBackoff returns the backoff of a restarted read of a journal which
+// has failed |attempt| times since it last read a message.
+func unionBackoff(attempt int) time.Duration {
+	switch attempt {
+	case 0, 1:
+		return 50 * time.Millisecond
+	case 2, 3:
+		return 100 * time.Millisecond
+	case 4, 5:
+		return time.Second
+	default:
+		return 5 * time.Second
+	}
+}
@@ -0,0 +1,182 @@
+package message
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.gazette.dev/core/broker/client"
+	pb "go.gazette.dev/core/broker/protocol"
+	"go.gazette.dev/core/brokertest"
+	"go.gazette.dev/core/etcdtest"
+	"go.gazette.dev/core/labels"
+)
+
+func TestUnionIterOfDynamicJournals(t *testing.T) {
+	var etcd = etcdtest.TestClient()
+	defer etcdtest.Cleanup()
+
+	var (
+		framing, _ = FramingByContentType(labels.ContentType_JSONLines)
+		bk         = brokertest.NewBroker(t, etcd, "local", "broker")
+		ajc        = client.NewAppendService(context.Background(), bk.Client())
+		ctx        = pb.WithDispatchDefault(context.Background())
+	)
+	var newSpec = func(name pb.Journal, topic string) *pb.JournalSpec {
+		var spec = newTestMsgSpec(name)
+		spec.LabelSet.AddValue("topic", topic)
+		return spec
+	}
+	var write = func(journal pb.Journal, strs ...string) {
+		var aa = ajc.StartAppend(pb.AppendRequest{Journal: journal}, nil)
+		for _, str := range strs {
+			aa.Require(framing.Marshal(&testMsg{Str: str}, aa.Writer()))
+		}
+		require.NoError(t, aa.Release())
+		<-aa.Done()
+	}
+	var relabel = func(journal pb.Journal, topic string) {
+		var resp, err = client.ListAllJournals(ctx, bk.Client(), pb.ListRequest{
+			Selector: pb.LabelSelector{Include: pb.MustLabelSet("name", journal.String())},
+		})
+		require.NoError(t, err)
+		var spec = resp.Journals[0].Spec
+		spec.LabelSet.SetValue("topic", topic)
+
+		_, err = client.ApplyJournals(ctx, bk.Client(), &pb.ApplyRequest{
+			Changes: []pb.ApplyRequest_Change{{ExpectModRevision: resp.Journals[0].ModRevision, Upsert: &spec}},
+		})
+		require.NoError(t, err)
+	}
+	// Reads |n| messages of |it|, returned as "journal:str" in sorted order.
+	var read = func(it *UnionIter, n int) []string {
+		var out []string
+		var last = make(map[pb.Journal]pb.Offset)
+
+		for len(out) != n {
+			var env, err = it.Next()
+			require.NoError(t, err)
+
+			// Messages of each journal are in order.
+			require.True(t, env.Begin >= last[env.Journal.Name])
+			last[env.Journal.Name] = env.End
+
+			out = append(out, env.Journal.Name.String()+":"+env.Message.(*testMsg).Str)
+		}
+		sort.Strings(out)
+		return out
+	}
+
+	brokertest.CreateJournals(t, bk,
+		newSpec("a/one", "foo"),
+		newSpec("a/two", "foo"),
+		newSpec("b/other", "bar"),
+	)
+	write("a/one", "one-1", "one-2")
+	write("a/two", "two-1")
+	write("b/other", "other-1")
+
+	var selector, _ = pb.ParseLabelSelector("topic=foo")
+	var pl, err = client.NewPolledList(ctx, bk.Client(), 10*time.Millisecond, pb.ListRequest{Selector: selector})
+	require.NoError(t, err)
+
+	var it = NewUnionIter(ctx, bk.Client(), pl, nil, newTestMsg)
+	require.Equal(t, []string{"a/one:one-1", "a/one:one-2", "a/two:two-1"}, read(it, 3))
+
+	// A journal added to the set is read.
+	brokertest.CreateJournals(t, bk, newSpec("a/three", "foo"))
+	write("a/three", "three-1")
+	require.Equal(t, []string{"a/three:three-1"}, read(it, 1))
+
+	// Remove a journal from the set, append to it, and then restore it.
+	// Expect it resumes from its checkpoint, and its message is read once.
+	relabel("a/two", "bar")
+	require.Eventually(t, func() bool { return len(pl.List().Journals) == 2 }, time.Second, time.Millisecond)
+	write("a/two", "two-2")
+	write("a/one", "one-3")
+	relabel("a/two", "foo")
+
+	require.Equal(t, []string{"a/one:one-3", "a/two:two-2"}, read(it, 2))
+
+	var checkpoint = it.Checkpoint()
+	require.Len(t, checkpoint, 3)
+	it.Close()
+
+	_, err = it.Next()
+	require.Equal(t, context.Canceled, err)
+
+	// A UnionIter begun from the checkpoint resumes with the next messages.
+	write("a/one", "one-4")
+	write("a/three", "three-2")
+
+	it = NewUnionIter(ctx, bk.Client(), pl, checkpoint, newTestMsg)
+	require.Equal(t, []string{"a/one:one-4", "a/three:three-2"}, read(it, 2))
+	it.Close()
+
+	bk.Tasks.Cancel()
+	require.NoError(t, bk.Tasks.Wait())
+}
+
+func TestUnionIterRestartsFailedJournals(t *testing.T) {
+	var etcd = etcdtest.TestClient()
+	defer etcdtest.Cleanup()
+
+	var (
+		framing, _ = FramingByContentType(labels.ContentType_JSONLines)
+		bk         = brokertest.NewBroker(t, etcd, "local", "broker")
+		ajc        = client.NewAppendService(context.Background(), bk.Client())
+		ctx        = pb.WithDispatchDefault(context.Background())
+	)
+	var write = func(journal pb.Journal, content string) {
+		var aa = ajc.StartAppend(pb.AppendRequest{Journal: journal}, nil)
+		_, _ = aa.Writer().WriteString(content)
+		require.NoError(t, aa.Release())
+		<-aa.Done()
+	}
+	var marshal = func(str string) string {
+		var bw = bytes.Buffer{}
+		var w = bufio.NewWriter(&bw)
+		require.NoError(t, framing.Marshal(&testMsg{Str: str}, w))
+		require.NoError(t, w.Flush())
+		return bw.String()
+	}
+
+	brokertest.CreateJournals(t, bk, newTestMsgSpec("a/good"), newTestMsgSpec("a/bad"))
+	write("a/bad", marshal("bad-1")+"{malformed\n")
+
+	var pl, err = client.NewPolledList(ctx, bk.Client(), 10*time.Millisecond, pb.ListRequest{})
+	require.NoError(t, err)
+	var it = NewUnionIter(ctx, bk.Client(), pl, nil, newTestMsg)
+
+	// Expect the message preceding the malformed one is read.
+	env, err := it.Next()
+	require.NoError(t, err)
+	require.Equal(t, "bad-1", env.Message.(*testMsg).Str)
+
+	// The read fails on the malformed message, and is restarted from the
+	// offset following the last read message, where it fails again.
+	for i := 0; i != 2; i++ {
+		_, err = it.Next()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "reading journal a/bad")
+	}
+	// Other journals continue to be read.
+	write("a/good", marshal("good-1"))
+
+	for {
+		if env, err = it.Next(); err == nil {
+			break
+		}
+		require.Contains(t, err.Error(), "reading journal a/bad")
+	}
+	require.Equal(t, "good-1", env.Message.(*testMsg).Str)
+	require.Equal(t, pb.Offsets{"a/bad": int64(len(marshal("bad-1"))), "a/good": env.End}, it.Checkpoint())
+	it.Close()
+
+	bk.Tasks.Cancel()
+	require.NoError(t, bk.Tasks.Wait())
+}
@@ -16,6 +16,7 @@ import (
 	"go.gazette.dev/core/task"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/keepalive"
 )
 
 // Server bundles gRPC & HTTP servers, multiplexed over a single bound TCP
@@ -70,6 +71,13 @@ func NewFromListener(listener net.Listener) (*Server, error) {
 		GRPCServer: grpc.NewServer(
 			grpc.StreamInterceptor(grpc_prometheus.StreamServerInterceptor),
 			grpc.UnaryInterceptor(grpc_prometheus.UnaryServerInterceptor),
+			// Permit client keepalives as frequent as MinKeepaliveTime, including
+			// of connections without active streams. gRPC's default policy closes
+			// connections which ping more often than every five minutes.
+			grpc.KeepaliveEnforcementPolicy(keepalive.EnforcementPolicy{
+				MinTime:             MinKeepaliveTime,
+				PermitWithoutStream: true,
+			}),
 		),
 		RawListener: listener,
 	}
@@ -202,3 +210,8 @@ func (noopCloser) Close() error { return nil }
 // GracefulStopTimeout is the amount of time BoundedGracefulStop will wait
 // before performing a hard server Stop.
 var GracefulStopTimeout = 15 * time.Second
+
+// MinKeepaliveTime is the minimum interval of client keepalive pings which
+// a Server permits. A client which pings more frequently is closed by the
+// Server for excessive pings.
+var MinKeepaliveTime = 10 * time.Second